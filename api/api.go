@@ -12,6 +12,7 @@ import (
 	"github.com/fatih/structs"
 )
 
+//go:generate protoc proto/evcc.proto --go_out=. --go-grpc_out=.
 //go:generate mockgen -package mock -destination ../mock/mock_api.go github.com/evcc-io/evcc/api Charger,ChargeState,PhaseSwitcher,Identifier,Meter,MeterEnergy,Vehicle,ChargeRater,Battery
 
 // ChargeMode is the charge operation mode. Valid values are off, now, minpv and pv
@@ -50,6 +51,34 @@ func (c ChargeStatus) String() string {
 	return string(c)
 }
 
+// Charger fault codes reported by FaultReporter or raised by supply and write monitoring
+const (
+	FaultNone         = ""
+	FaultRCCB         = "rccb"         // residual current device tripped
+	FaultPhase        = "phase"        // phase failure
+	FaultGround       = "ground"       // missing ground
+	FaultTemperature  = "temperature"  // over temperature
+	FaultInternal     = "internal"     // internal charger error
+	FaultUndervoltage = "undervoltage" // supply voltage below cutoff
+	FaultWrite        = "write"        // repeated charger write failures
+)
+
+// SocSync is the charge limit synchronization policy between evcc and vehicle
+type SocSync string
+
+// Charge limit synchronization policies
+const (
+	SocSyncNone    SocSync = ""        // no synchronization
+	SocSyncEvcc    SocSync = "evcc"    // evcc target soc is written to the vehicle
+	SocSyncVehicle SocSync = "vehicle" // vehicle charge limit is applied as target soc
+	SocSyncMin     SocSync = "min"     // lower of both is applied as target soc
+)
+
+// String implements Stringer
+func (c SocSync) String() string {
+	return string(c)
+}
+
 // ActionConfig defines an action to take on event
 type ActionConfig struct {
 	Mode       *ChargeMode `mapstructure:"mode,omitempty"`       // Charge Mode
@@ -107,11 +136,31 @@ type MeterCurrent interface {
 	Currents() (float64, float64, float64, error)
 }
 
+// MeterVoltage is able to provide per-line voltage V
+type MeterVoltage interface {
+	Voltages() (float64, float64, float64, error)
+}
+
 // Battery is able to provide battery SoC in %
 type Battery interface {
 	SoC() (float64, error)
 }
 
+// BatteryMode is the home battery operating mode
+type BatteryMode string
+
+// Battery modes
+const (
+	BatteryNormal BatteryMode = "normal" // battery charges and discharges freely
+	BatteryHold   BatteryMode = "hold"   // battery is prevented from discharging
+	BatteryCharge BatteryMode = "charge" // battery is charged from grid
+)
+
+// BatteryController allows to hold the home battery, implemented by hybrid inverters
+type BatteryController interface {
+	SetBatteryMode(mode BatteryMode) error
+}
+
 // ChargeState provides current charging status
 type ChargeState interface {
 	Status() (ChargeStatus, error)
@@ -130,16 +179,77 @@ type ChargerEx interface {
 	MaxCurrentMillis(current float64) error
 }
 
+// PowerController controls the charger by power instead of current, e.g. DC chargers
+type PowerController interface {
+	MaxPower(power float64) error
+}
+
+// CableUnlocker unlocks the charging cable of the charger's socket
+type CableUnlocker interface {
+	Unlock() error
+}
+
+// CurrentGetter provides the current limit applied by the charger
+type CurrentGetter interface {
+	GetMaxCurrent() (float64, error)
+}
+
 // PhaseSwitcher provides 1p3p switching
 type PhaseSwitcher interface {
 	Phases1p3p(phases int) error
 }
 
+// FaultReporter provides the charger's active fault code, empty if none
+type FaultReporter interface {
+	Fault() (string, error)
+}
+
+// Command is a charger maintenance command
+type Command string
+
+// Commands
+const (
+	CommandReboot      Command = "reboot"      // restart the charger
+	CommandWakeUp      Command = "wakeup"      // wake up the vehicle
+	CommandReauthorize Command = "reauthorize" // authorize the charging session again
+)
+
+// Commander executes maintenance commands for fixing stuck chargers
+type Commander interface {
+	Commands() []Command
+	Command(cmd Command) error
+}
+
+// Indication is the loadpoint state shown by charger leds or displays
+type Indication string
+
+// Indications
+const (
+	IndicationIdle     Indication = "idle"     // vehicle disconnected
+	IndicationWaiting  Indication = "waiting"  // vehicle connected, not charging
+	IndicationPV       Indication = "pv"       // pv charging
+	IndicationCharging Indication = "charging" // grid or planned charging
+	IndicationError    Indication = "error"    // charger fault or control error
+)
+
+// Indicator reflects the loadpoint state on charger leds or displays
+type Indicator interface {
+	Indicate(indication Indication) error
+}
+
 // Diagnosis is a helper interface that allows to dump diagnostic data to console
 type Diagnosis interface {
 	Diagnose()
 }
 
+// Diagnostics is the driver-specific diagnostic data of a device like firmware version, raw status or last errors
+type Diagnostics map[string]interface{}
+
+// DiagnosticReporter returns structured diagnostic data for remote diagnosis
+type DiagnosticReporter interface {
+	Diagnostics() (Diagnostics, error)
+}
+
 // ChargeTimer provides current charge cycle duration
 type ChargeTimer interface {
 	ChargingTime() (time.Duration, error)
@@ -200,12 +310,51 @@ type SocLimiter interface {
 	TargetSoC() (float64, error)
 }
 
+// PollConfig defines the vehicle polling mode, interval and schedule
+type PollConfig struct {
+	Mode     string        `mapstructure:"mode"`     // polling mode charging (default), connected, always
+	Interval time.Duration `mapstructure:"interval"` // interval when not charging
+	Schedule []string      `mapstructure:"schedule"` // daily windows like 07:00-22:00 restricting interval polling
+}
+
+// StaleConfig defines how vehicle api errors are handled
+type StaleConfig struct {
+	MaxAge  time.Duration `mapstructure:"maxAge"`  // discard last known soc older than this after api errors, 0 to keep
+	Backoff time.Duration `mapstructure:"backoff"` // max delay between api calls after errors, 0 to disable backoff
+}
+
+// StaleHandler provides vehicle specific api error handling
+type StaleHandler interface {
+	Stale() StaleConfig
+}
+
+// Poller provides vehicle specific polling behaviour
+type Poller interface {
+	Poll() PollConfig
+}
+
+// SocLimitController allows to set the vehicles charge limit
+type SocLimitController interface {
+	SetTargetSoC(soc int) error
+}
+
+// SocSyncer provides the vehicles charge limit synchronization policy
+type SocSyncer interface {
+	SocSync() SocSync
+}
+
 // VehicleChargeController allows to start/stop the charging session on the vehicle side
 type VehicleChargeController interface {
 	StartCharge() error
 	StopCharge() error
 }
 
+// VehicleClimateController allows to start/stop climatisation (preconditioning) on the vehicle side
+type VehicleClimateController interface {
+	StartClimate() error
+	StopClimate() error
+}
+
 // Resurrector provides wakeup calls to the vehicle with an API call or a CP interrupt from the charger
 type Resurrector interface {
 	WakeUp() error
@@ -217,6 +366,36 @@ type Tariff interface {
 	CurrentPrice() (float64, error) // EUR/kWh, CHF/kWh, ...
 }
 
+// Rate is the value of a tariff or forecast for a time slot
+type Rate struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Price float64   `json:"price"` // price per kWh for tariffs, power in W for forecasts
+}
+
+// Rates is a slice of consecutive time slots
+type Rates []Rate
+
+// Rater provides upcoming tariff or forecast time slots
+type Rater interface {
+	Rates() (Rates, error)
+}
+
+// PowerSlot is the forecast power of a time slot
+type PowerSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Power float64   `json:"power"` // W
+}
+
+// PowerSlots is a slice of consecutive forecast time slots
+type PowerSlots []PowerSlot
+
+// PowerForecaster provides upcoming power forecast time slots like the pv surplus
+type PowerForecaster interface {
+	PowerForecast() (PowerSlots, error)
+}
+
 // AuthProvider is the ability to provide OAuth authentication through the ui
 type AuthProvider interface {
 	SetCallbackParams(baseURL, redirectURL string, authenticated chan<- bool)
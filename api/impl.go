@@ -36,3 +36,46 @@ func (c *ChargeMode) UnmarshalText(text []byte) error {
 
 	return nil
 }
+
+// BatteryModeString converts string to BatteryMode
+func BatteryModeString(mode string) (BatteryMode, error) {
+	switch strings.ToLower(mode) {
+	case string(BatteryNormal):
+		return BatteryNormal, nil
+	case string(BatteryHold):
+		return BatteryHold, nil
+	case string(BatteryCharge):
+		return BatteryCharge, nil
+	default:
+		return "", fmt.Errorf("invalid value: %s", mode)
+	}
+}
+
+// SocSyncString converts string to SocSync
+func SocSyncString(policy string) (SocSync, error) {
+	switch strings.ToLower(policy) {
+	case string(SocSyncNone):
+		return SocSyncNone, nil
+	case string(SocSyncEvcc):
+		return SocSyncEvcc, nil
+	case string(SocSyncVehicle):
+		return SocSyncVehicle, nil
+	case string(SocSyncMin):
+		return SocSyncMin, nil
+	default:
+		return "", fmt.Errorf("invalid value: %s", policy)
+	}
+}
+
+var _ encoding.TextUnmarshaler = (*SocSync)(nil)
+
+func (c *SocSync) UnmarshalText(text []byte) error {
+	casted, err := SocSyncString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = casted
+
+	return nil
+}
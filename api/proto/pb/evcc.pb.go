@@ -0,0 +1,1260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: proto/evcc.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChargeMode int32
+
+const (
+	ChargeMode_CHARGE_MODE_UNSPECIFIED ChargeMode = 0
+	ChargeMode_CHARGE_MODE_OFF         ChargeMode = 1
+	ChargeMode_CHARGE_MODE_NOW         ChargeMode = 2
+	ChargeMode_CHARGE_MODE_MINPV       ChargeMode = 3
+	ChargeMode_CHARGE_MODE_PV          ChargeMode = 4
+)
+
+// Enum value maps for ChargeMode.
+var (
+	ChargeMode_name = map[int32]string{
+		0: "CHARGE_MODE_UNSPECIFIED",
+		1: "CHARGE_MODE_OFF",
+		2: "CHARGE_MODE_NOW",
+		3: "CHARGE_MODE_MINPV",
+		4: "CHARGE_MODE_PV",
+	}
+	ChargeMode_value = map[string]int32{
+		"CHARGE_MODE_UNSPECIFIED": 0,
+		"CHARGE_MODE_OFF":         1,
+		"CHARGE_MODE_NOW":         2,
+		"CHARGE_MODE_MINPV":       3,
+		"CHARGE_MODE_PV":          4,
+	}
+)
+
+func (x ChargeMode) Enum() *ChargeMode {
+	p := new(ChargeMode)
+	*p = x
+	return p
+}
+
+func (x ChargeMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChargeMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_evcc_proto_enumTypes[0].Descriptor()
+}
+
+func (ChargeMode) Type() protoreflect.EnumType {
+	return &file_proto_evcc_proto_enumTypes[0]
+}
+
+func (x ChargeMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChargeMode.Descriptor instead.
+func (ChargeMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{0}
+}
+
+type StateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StateRequest) Reset() {
+	*x = StateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateRequest) ProtoMessage() {}
+
+func (x *StateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateRequest.ProtoReflect.Descriptor instead.
+func (*StateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{0}
+}
+
+type StateReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Site       *SiteState        `protobuf:"bytes,1,opt,name=site,proto3" json:"site,omitempty"`
+	Loadpoints []*LoadpointState `protobuf:"bytes,2,rep,name=loadpoints,proto3" json:"loadpoints,omitempty"`
+}
+
+func (x *StateReply) Reset() {
+	*x = StateReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateReply) ProtoMessage() {}
+
+func (x *StateReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateReply.ProtoReflect.Descriptor instead.
+func (*StateReply) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StateReply) GetSite() *SiteState {
+	if x != nil {
+		return x.Site
+	}
+	return nil
+}
+
+func (x *StateReply) GetLoadpoints() []*LoadpointState {
+	if x != nil {
+		return x.Loadpoints
+	}
+	return nil
+}
+
+type SiteState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Title          string  `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	GridPower      float64 `protobuf:"fixed64,2,opt,name=grid_power,json=gridPower,proto3" json:"grid_power,omitempty"`                  // W
+	PvPower        float64 `protobuf:"fixed64,3,opt,name=pv_power,json=pvPower,proto3" json:"pv_power,omitempty"`                        // W
+	HomePower      float64 `protobuf:"fixed64,4,opt,name=home_power,json=homePower,proto3" json:"home_power,omitempty"`                  // W
+	BatteryPower   float64 `protobuf:"fixed64,5,opt,name=battery_power,json=batteryPower,proto3" json:"battery_power,omitempty"`         // W
+	BatterySoc     float64 `protobuf:"fixed64,6,opt,name=battery_soc,json=batterySoc,proto3" json:"battery_soc,omitempty"`               // %
+	BufferSoc      float64 `protobuf:"fixed64,7,opt,name=buffer_soc,json=bufferSoc,proto3" json:"buffer_soc,omitempty"`                  // %
+	BufferStartSoc float64 `protobuf:"fixed64,8,opt,name=buffer_start_soc,json=bufferStartSoc,proto3" json:"buffer_start_soc,omitempty"` // %
+	PrioritySoc    float64 `protobuf:"fixed64,9,opt,name=priority_soc,json=prioritySoc,proto3" json:"priority_soc,omitempty"`            // %
+	ResidualPower  float64 `protobuf:"fixed64,10,opt,name=residual_power,json=residualPower,proto3" json:"residual_power,omitempty"`     // W
+}
+
+func (x *SiteState) Reset() {
+	*x = SiteState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SiteState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteState) ProtoMessage() {}
+
+func (x *SiteState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteState.ProtoReflect.Descriptor instead.
+func (*SiteState) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SiteState) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SiteState) GetGridPower() float64 {
+	if x != nil {
+		return x.GridPower
+	}
+	return 0
+}
+
+func (x *SiteState) GetPvPower() float64 {
+	if x != nil {
+		return x.PvPower
+	}
+	return 0
+}
+
+func (x *SiteState) GetHomePower() float64 {
+	if x != nil {
+		return x.HomePower
+	}
+	return 0
+}
+
+func (x *SiteState) GetBatteryPower() float64 {
+	if x != nil {
+		return x.BatteryPower
+	}
+	return 0
+}
+
+func (x *SiteState) GetBatterySoc() float64 {
+	if x != nil {
+		return x.BatterySoc
+	}
+	return 0
+}
+
+func (x *SiteState) GetBufferSoc() float64 {
+	if x != nil {
+		return x.BufferSoc
+	}
+	return 0
+}
+
+func (x *SiteState) GetBufferStartSoc() float64 {
+	if x != nil {
+		return x.BufferStartSoc
+	}
+	return 0
+}
+
+func (x *SiteState) GetPrioritySoc() float64 {
+	if x != nil {
+		return x.PrioritySoc
+	}
+	return 0
+}
+
+func (x *SiteState) GetResidualPower() float64 {
+	if x != nil {
+		return x.ResidualPower
+	}
+	return 0
+}
+
+type LoadpointState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Loadpoint     int32      `protobuf:"varint,1,opt,name=loadpoint,proto3" json:"loadpoint,omitempty"` // loadpoint starting at 1
+	Title         string     `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Mode          ChargeMode `protobuf:"varint,3,opt,name=mode,proto3,enum=evcc.v1.ChargeMode" json:"mode,omitempty"`
+	Enabled       bool       `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Connected     bool       `protobuf:"varint,5,opt,name=connected,proto3" json:"connected,omitempty"`
+	Charging      bool       `protobuf:"varint,6,opt,name=charging,proto3" json:"charging,omitempty"`
+	ChargePower   float64    `protobuf:"fixed64,7,opt,name=charge_power,json=chargePower,proto3" json:"charge_power,omitempty"`       // W
+	ChargedEnergy float64    `protobuf:"fixed64,8,opt,name=charged_energy,json=chargedEnergy,proto3" json:"charged_energy,omitempty"` // Wh
+	PhasesActive  int32      `protobuf:"varint,9,opt,name=phases_active,json=phasesActive,proto3" json:"phases_active,omitempty"`
+	MinCurrent    float64    `protobuf:"fixed64,10,opt,name=min_current,json=minCurrent,proto3" json:"min_current,omitempty"`       // A
+	MaxCurrent    float64    `protobuf:"fixed64,11,opt,name=max_current,json=maxCurrent,proto3" json:"max_current,omitempty"`       // A
+	MinSoc        int32      `protobuf:"varint,12,opt,name=min_soc,json=minSoc,proto3" json:"min_soc,omitempty"`                    // %
+	TargetSoc     int32      `protobuf:"varint,13,opt,name=target_soc,json=targetSoc,proto3" json:"target_soc,omitempty"`           // %
+	TargetEnergy  float64    `protobuf:"fixed64,14,opt,name=target_energy,json=targetEnergy,proto3" json:"target_energy,omitempty"` // kWh
+	VehicleTitle  string     `protobuf:"bytes,15,opt,name=vehicle_title,json=vehicleTitle,proto3" json:"vehicle_title,omitempty"`
+	VehicleSoc    float64    `protobuf:"fixed64,16,opt,name=vehicle_soc,json=vehicleSoc,proto3" json:"vehicle_soc,omitempty"` // %
+}
+
+func (x *LoadpointState) Reset() {
+	*x = LoadpointState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadpointState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadpointState) ProtoMessage() {}
+
+func (x *LoadpointState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadpointState.ProtoReflect.Descriptor instead.
+func (*LoadpointState) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LoadpointState) GetLoadpoint() int32 {
+	if x != nil {
+		return x.Loadpoint
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *LoadpointState) GetMode() ChargeMode {
+	if x != nil {
+		return x.Mode
+	}
+	return ChargeMode_CHARGE_MODE_UNSPECIFIED
+}
+
+func (x *LoadpointState) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *LoadpointState) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *LoadpointState) GetCharging() bool {
+	if x != nil {
+		return x.Charging
+	}
+	return false
+}
+
+func (x *LoadpointState) GetChargePower() float64 {
+	if x != nil {
+		return x.ChargePower
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetChargedEnergy() float64 {
+	if x != nil {
+		return x.ChargedEnergy
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetPhasesActive() int32 {
+	if x != nil {
+		return x.PhasesActive
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetMinCurrent() float64 {
+	if x != nil {
+		return x.MinCurrent
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetMaxCurrent() float64 {
+	if x != nil {
+		return x.MaxCurrent
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetMinSoc() int32 {
+	if x != nil {
+		return x.MinSoc
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetTargetSoc() int32 {
+	if x != nil {
+		return x.TargetSoc
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetTargetEnergy() float64 {
+	if x != nil {
+		return x.TargetEnergy
+	}
+	return 0
+}
+
+func (x *LoadpointState) GetVehicleTitle() string {
+	if x != nil {
+		return x.VehicleTitle
+	}
+	return ""
+}
+
+func (x *LoadpointState) GetVehicleSoc() float64 {
+	if x != nil {
+		return x.VehicleSoc
+	}
+	return 0
+}
+
+type SiteSetting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Setting:
+	//	*SiteSetting_BufferSoc
+	//	*SiteSetting_BufferStartSoc
+	//	*SiteSetting_PrioritySoc
+	//	*SiteSetting_ResidualPower
+	Setting isSiteSetting_Setting `protobuf_oneof:"setting"`
+}
+
+func (x *SiteSetting) Reset() {
+	*x = SiteSetting{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SiteSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteSetting) ProtoMessage() {}
+
+func (x *SiteSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteSetting.ProtoReflect.Descriptor instead.
+func (*SiteSetting) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *SiteSetting) GetSetting() isSiteSetting_Setting {
+	if m != nil {
+		return m.Setting
+	}
+	return nil
+}
+
+func (x *SiteSetting) GetBufferSoc() float64 {
+	if x, ok := x.GetSetting().(*SiteSetting_BufferSoc); ok {
+		return x.BufferSoc
+	}
+	return 0
+}
+
+func (x *SiteSetting) GetBufferStartSoc() float64 {
+	if x, ok := x.GetSetting().(*SiteSetting_BufferStartSoc); ok {
+		return x.BufferStartSoc
+	}
+	return 0
+}
+
+func (x *SiteSetting) GetPrioritySoc() float64 {
+	if x, ok := x.GetSetting().(*SiteSetting_PrioritySoc); ok {
+		return x.PrioritySoc
+	}
+	return 0
+}
+
+func (x *SiteSetting) GetResidualPower() float64 {
+	if x, ok := x.GetSetting().(*SiteSetting_ResidualPower); ok {
+		return x.ResidualPower
+	}
+	return 0
+}
+
+type isSiteSetting_Setting interface {
+	isSiteSetting_Setting()
+}
+
+type SiteSetting_BufferSoc struct {
+	BufferSoc float64 `protobuf:"fixed64,1,opt,name=buffer_soc,json=bufferSoc,proto3,oneof"`
+}
+
+type SiteSetting_BufferStartSoc struct {
+	BufferStartSoc float64 `protobuf:"fixed64,2,opt,name=buffer_start_soc,json=bufferStartSoc,proto3,oneof"`
+}
+
+type SiteSetting_PrioritySoc struct {
+	PrioritySoc float64 `protobuf:"fixed64,3,opt,name=priority_soc,json=prioritySoc,proto3,oneof"`
+}
+
+type SiteSetting_ResidualPower struct {
+	ResidualPower float64 `protobuf:"fixed64,4,opt,name=residual_power,json=residualPower,proto3,oneof"`
+}
+
+func (*SiteSetting_BufferSoc) isSiteSetting_Setting() {}
+
+func (*SiteSetting_BufferStartSoc) isSiteSetting_Setting() {}
+
+func (*SiteSetting_PrioritySoc) isSiteSetting_Setting() {}
+
+func (*SiteSetting_ResidualPower) isSiteSetting_Setting() {}
+
+type LoadpointSetting struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Setting:
+	//	*LoadpointSetting_Mode
+	//	*LoadpointSetting_MinSoc
+	//	*LoadpointSetting_TargetSoc
+	//	*LoadpointSetting_TargetEnergy
+	//	*LoadpointSetting_MinCurrent
+	//	*LoadpointSetting_MaxCurrent
+	//	*LoadpointSetting_Phases
+	Setting isLoadpointSetting_Setting `protobuf_oneof:"setting"`
+}
+
+func (x *LoadpointSetting) Reset() {
+	*x = LoadpointSetting{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadpointSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadpointSetting) ProtoMessage() {}
+
+func (x *LoadpointSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadpointSetting.ProtoReflect.Descriptor instead.
+func (*LoadpointSetting) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{5}
+}
+
+func (m *LoadpointSetting) GetSetting() isLoadpointSetting_Setting {
+	if m != nil {
+		return m.Setting
+	}
+	return nil
+}
+
+func (x *LoadpointSetting) GetMode() ChargeMode {
+	if x, ok := x.GetSetting().(*LoadpointSetting_Mode); ok {
+		return x.Mode
+	}
+	return ChargeMode_CHARGE_MODE_UNSPECIFIED
+}
+
+func (x *LoadpointSetting) GetMinSoc() int32 {
+	if x, ok := x.GetSetting().(*LoadpointSetting_MinSoc); ok {
+		return x.MinSoc
+	}
+	return 0
+}
+
+func (x *LoadpointSetting) GetTargetSoc() int32 {
+	if x, ok := x.GetSetting().(*LoadpointSetting_TargetSoc); ok {
+		return x.TargetSoc
+	}
+	return 0
+}
+
+func (x *LoadpointSetting) GetTargetEnergy() float64 {
+	if x, ok := x.GetSetting().(*LoadpointSetting_TargetEnergy); ok {
+		return x.TargetEnergy
+	}
+	return 0
+}
+
+func (x *LoadpointSetting) GetMinCurrent() float64 {
+	if x, ok := x.GetSetting().(*LoadpointSetting_MinCurrent); ok {
+		return x.MinCurrent
+	}
+	return 0
+}
+
+func (x *LoadpointSetting) GetMaxCurrent() float64 {
+	if x, ok := x.GetSetting().(*LoadpointSetting_MaxCurrent); ok {
+		return x.MaxCurrent
+	}
+	return 0
+}
+
+func (x *LoadpointSetting) GetPhases() int32 {
+	if x, ok := x.GetSetting().(*LoadpointSetting_Phases); ok {
+		return x.Phases
+	}
+	return 0
+}
+
+type isLoadpointSetting_Setting interface {
+	isLoadpointSetting_Setting()
+}
+
+type LoadpointSetting_Mode struct {
+	Mode ChargeMode `protobuf:"varint,1,opt,name=mode,proto3,enum=evcc.v1.ChargeMode,oneof"`
+}
+
+type LoadpointSetting_MinSoc struct {
+	MinSoc int32 `protobuf:"varint,2,opt,name=min_soc,json=minSoc,proto3,oneof"`
+}
+
+type LoadpointSetting_TargetSoc struct {
+	TargetSoc int32 `protobuf:"varint,3,opt,name=target_soc,json=targetSoc,proto3,oneof"`
+}
+
+type LoadpointSetting_TargetEnergy struct {
+	TargetEnergy float64 `protobuf:"fixed64,4,opt,name=target_energy,json=targetEnergy,proto3,oneof"`
+}
+
+type LoadpointSetting_MinCurrent struct {
+	MinCurrent float64 `protobuf:"fixed64,5,opt,name=min_current,json=minCurrent,proto3,oneof"`
+}
+
+type LoadpointSetting_MaxCurrent struct {
+	MaxCurrent float64 `protobuf:"fixed64,6,opt,name=max_current,json=maxCurrent,proto3,oneof"`
+}
+
+type LoadpointSetting_Phases struct {
+	Phases int32 `protobuf:"varint,7,opt,name=phases,proto3,oneof"`
+}
+
+func (*LoadpointSetting_Mode) isLoadpointSetting_Setting() {}
+
+func (*LoadpointSetting_MinSoc) isLoadpointSetting_Setting() {}
+
+func (*LoadpointSetting_TargetSoc) isLoadpointSetting_Setting() {}
+
+func (*LoadpointSetting_TargetEnergy) isLoadpointSetting_Setting() {}
+
+func (*LoadpointSetting_MinCurrent) isLoadpointSetting_Setting() {}
+
+func (*LoadpointSetting_MaxCurrent) isLoadpointSetting_Setting() {}
+
+func (*LoadpointSetting_Phases) isLoadpointSetting_Setting() {}
+
+type SetSiteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Setting *SiteSetting `protobuf:"bytes,1,opt,name=setting,proto3" json:"setting,omitempty"`
+}
+
+func (x *SetSiteRequest) Reset() {
+	*x = SetSiteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetSiteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSiteRequest) ProtoMessage() {}
+
+func (x *SetSiteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSiteRequest.ProtoReflect.Descriptor instead.
+func (*SetSiteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetSiteRequest) GetSetting() *SiteSetting {
+	if x != nil {
+		return x.Setting
+	}
+	return nil
+}
+
+type SetSiteReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Applied *SiteSetting `protobuf:"bytes,1,opt,name=applied,proto3" json:"applied,omitempty"` // applied value as read back from the site
+}
+
+func (x *SetSiteReply) Reset() {
+	*x = SetSiteReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetSiteReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSiteReply) ProtoMessage() {}
+
+func (x *SetSiteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSiteReply.ProtoReflect.Descriptor instead.
+func (*SetSiteReply) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetSiteReply) GetApplied() *SiteSetting {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+type SetLoadpointRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Loadpoint int32             `protobuf:"varint,1,opt,name=loadpoint,proto3" json:"loadpoint,omitempty"` // loadpoint starting at 1
+	Setting   *LoadpointSetting `protobuf:"bytes,2,opt,name=setting,proto3" json:"setting,omitempty"`
+}
+
+func (x *SetLoadpointRequest) Reset() {
+	*x = SetLoadpointRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetLoadpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLoadpointRequest) ProtoMessage() {}
+
+func (x *SetLoadpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLoadpointRequest.ProtoReflect.Descriptor instead.
+func (*SetLoadpointRequest) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetLoadpointRequest) GetLoadpoint() int32 {
+	if x != nil {
+		return x.Loadpoint
+	}
+	return 0
+}
+
+func (x *SetLoadpointRequest) GetSetting() *LoadpointSetting {
+	if x != nil {
+		return x.Setting
+	}
+	return nil
+}
+
+type SetLoadpointReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Applied *LoadpointSetting `protobuf:"bytes,1,opt,name=applied,proto3" json:"applied,omitempty"` // applied value as read back from the loadpoint
+}
+
+func (x *SetLoadpointReply) Reset() {
+	*x = SetLoadpointReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_evcc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetLoadpointReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLoadpointReply) ProtoMessage() {}
+
+func (x *SetLoadpointReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_evcc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLoadpointReply.ProtoReflect.Descriptor instead.
+func (*SetLoadpointReply) Descriptor() ([]byte, []int) {
+	return file_proto_evcc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetLoadpointReply) GetApplied() *LoadpointSetting {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+var File_proto_evcc_proto protoreflect.FileDescriptor
+
+var file_proto_evcc_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x07, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x22, 0x0e, 0x0a, 0x0c, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x6d, 0x0a, 0x0a, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x69, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x04, 0x73, 0x69, 0x74,
+	0x65, 0x12, 0x37, 0x0a, 0x0a, 0x6c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0a,
+	0x6c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0xd3, 0x02, 0x0a, 0x09, 0x53,
+	0x69, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x09, 0x67, 0x72, 0x69, 0x64, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x19, 0x0a,
+	0x08, 0x70, 0x76, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x07, 0x70, 0x76, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x6d, 0x65,
+	0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x68, 0x6f,
+	0x6d, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x74, 0x74, 0x65,
+	0x72, 0x79, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c,
+	0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b,
+	0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x53, 0x6f, 0x63, 0x12, 0x1d, 0x0a,
+	0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x6f, 0x63, 0x12, 0x28, 0x0a, 0x10,
+	0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x73, 0x6f, 0x63,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x53, 0x6f, 0x63, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72,
+	0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x53, 0x6f, 0x63, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73,
+	0x69, 0x64, 0x75, 0x61, 0x6c, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x69, 0x64, 0x75, 0x61, 0x6c, 0x50, 0x6f, 0x77, 0x65, 0x72,
+	0x22, 0x95, 0x04, 0x0a, 0x0e, 0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x72,
+	0x67, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x68, 0x61, 0x72,
+	0x67, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x68, 0x61, 0x72, 0x67,
+	0x65, 0x64, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0d, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x64, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x12, 0x23,
+	0x0a, 0x0d, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x41, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x43, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x43, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x6f, 0x63,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x53, 0x6f, 0x63, 0x12, 0x1d,
+	0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x6f, 0x63, 0x12, 0x23, 0x0a,
+	0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x45, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x5f, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x76, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x76, 0x65,
+	0x68, 0x69, 0x63, 0x6c, 0x65, 0x53, 0x6f, 0x63, 0x22, 0xb3, 0x01, 0x0a, 0x0b, 0x53, 0x69, 0x74,
+	0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x75, 0x66, 0x66,
+	0x65, 0x72, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x09,
+	0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x6f, 0x63, 0x12, 0x2a, 0x0a, 0x10, 0x62, 0x75, 0x66,
+	0x66, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0e, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x53, 0x6f, 0x63, 0x12, 0x23, 0x0a, 0x0c, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x53, 0x6f, 0x63, 0x12, 0x27, 0x0a, 0x0e, 0x72, 0x65,
+	0x73, 0x69, 0x64, 0x75, 0x61, 0x6c, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x00, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x69, 0x64, 0x75, 0x61, 0x6c, 0x50, 0x6f,
+	0x77, 0x65, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x8b,
+	0x02, 0x0a, 0x10, 0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x65, 0x74, 0x74,
+	0x69, 0x6e, 0x67, 0x12, 0x29, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x13, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x19,
+	0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x53, 0x6f, 0x63, 0x12, 0x1f, 0x0a, 0x0a, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x5f, 0x73, 0x6f, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
+	0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x6f, 0x63, 0x12, 0x25, 0x0a, 0x0d, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x00, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x45, 0x6e, 0x65, 0x72, 0x67,
+	0x79, 0x12, 0x21, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x43, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0a, 0x6d, 0x61, 0x78,
+	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x73, 0x42, 0x09, 0x0a, 0x07, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x40, 0x0a, 0x0e,
+	0x53, 0x65, 0x74, 0x53, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e,
+	0x0a, 0x07, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x3e,
+	0x0a, 0x0c, 0x53, 0x65, 0x74, 0x53, 0x69, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2e,
+	0x0a, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x22, 0x68,
+	0x0a, 0x13, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x6f, 0x61, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x52,
+	0x07, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x48, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x4c,
+	0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x33, 0x0a,
+	0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69,
+	0x65, 0x64, 0x2a, 0x7e, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x12, 0x1b, 0x0a, 0x17, 0x43, 0x48, 0x41, 0x52, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a,
+	0x0f, 0x43, 0x48, 0x41, 0x52, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4f, 0x46, 0x46,
+	0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x48, 0x41, 0x52, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44,
+	0x45, 0x5f, 0x4e, 0x4f, 0x57, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x48, 0x41, 0x52, 0x47,
+	0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4d, 0x49, 0x4e, 0x50, 0x56, 0x10, 0x03, 0x12, 0x12,
+	0x0a, 0x0e, 0x43, 0x48, 0x41, 0x52, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x50, 0x56,
+	0x10, 0x04, 0x32, 0xc2, 0x01, 0x0a, 0x04, 0x45, 0x76, 0x63, 0x63, 0x12, 0x35, 0x0a, 0x05, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x15, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x65, 0x76,
+	0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x30, 0x01, 0x12, 0x39, 0x0a, 0x07, 0x53, 0x65, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x17, 0x2e,
+	0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53, 0x69, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x65, 0x74, 0x53, 0x69, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x48, 0x0a,
+	0x0c, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1c, 0x2e,
+	0x65, 0x76, 0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x65, 0x76,
+	0x63, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x42, 0x0a, 0x5a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_evcc_proto_rawDescOnce sync.Once
+	file_proto_evcc_proto_rawDescData = file_proto_evcc_proto_rawDesc
+)
+
+func file_proto_evcc_proto_rawDescGZIP() []byte {
+	file_proto_evcc_proto_rawDescOnce.Do(func() {
+		file_proto_evcc_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_evcc_proto_rawDescData)
+	})
+	return file_proto_evcc_proto_rawDescData
+}
+
+var file_proto_evcc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_evcc_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_evcc_proto_goTypes = []interface{}{
+	(ChargeMode)(0),             // 0: evcc.v1.ChargeMode
+	(*StateRequest)(nil),        // 1: evcc.v1.StateRequest
+	(*StateReply)(nil),          // 2: evcc.v1.StateReply
+	(*SiteState)(nil),           // 3: evcc.v1.SiteState
+	(*LoadpointState)(nil),      // 4: evcc.v1.LoadpointState
+	(*SiteSetting)(nil),         // 5: evcc.v1.SiteSetting
+	(*LoadpointSetting)(nil),    // 6: evcc.v1.LoadpointSetting
+	(*SetSiteRequest)(nil),      // 7: evcc.v1.SetSiteRequest
+	(*SetSiteReply)(nil),        // 8: evcc.v1.SetSiteReply
+	(*SetLoadpointRequest)(nil), // 9: evcc.v1.SetLoadpointRequest
+	(*SetLoadpointReply)(nil),   // 10: evcc.v1.SetLoadpointReply
+}
+var file_proto_evcc_proto_depIdxs = []int32{
+	3,  // 0: evcc.v1.StateReply.site:type_name -> evcc.v1.SiteState
+	4,  // 1: evcc.v1.StateReply.loadpoints:type_name -> evcc.v1.LoadpointState
+	0,  // 2: evcc.v1.LoadpointState.mode:type_name -> evcc.v1.ChargeMode
+	0,  // 3: evcc.v1.LoadpointSetting.mode:type_name -> evcc.v1.ChargeMode
+	5,  // 4: evcc.v1.SetSiteRequest.setting:type_name -> evcc.v1.SiteSetting
+	5,  // 5: evcc.v1.SetSiteReply.applied:type_name -> evcc.v1.SiteSetting
+	6,  // 6: evcc.v1.SetLoadpointRequest.setting:type_name -> evcc.v1.LoadpointSetting
+	6,  // 7: evcc.v1.SetLoadpointReply.applied:type_name -> evcc.v1.LoadpointSetting
+	1,  // 8: evcc.v1.Evcc.State:input_type -> evcc.v1.StateRequest
+	7,  // 9: evcc.v1.Evcc.SetSite:input_type -> evcc.v1.SetSiteRequest
+	9,  // 10: evcc.v1.Evcc.SetLoadpoint:input_type -> evcc.v1.SetLoadpointRequest
+	2,  // 11: evcc.v1.Evcc.State:output_type -> evcc.v1.StateReply
+	8,  // 12: evcc.v1.Evcc.SetSite:output_type -> evcc.v1.SetSiteReply
+	10, // 13: evcc.v1.Evcc.SetLoadpoint:output_type -> evcc.v1.SetLoadpointReply
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_evcc_proto_init() }
+func file_proto_evcc_proto_init() {
+	if File_proto_evcc_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_evcc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SiteState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadpointState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SiteSetting); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadpointSetting); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetSiteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetSiteReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetLoadpointRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_evcc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetLoadpointReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_evcc_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*SiteSetting_BufferSoc)(nil),
+		(*SiteSetting_BufferStartSoc)(nil),
+		(*SiteSetting_PrioritySoc)(nil),
+		(*SiteSetting_ResidualPower)(nil),
+	}
+	file_proto_evcc_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*LoadpointSetting_Mode)(nil),
+		(*LoadpointSetting_MinSoc)(nil),
+		(*LoadpointSetting_TargetSoc)(nil),
+		(*LoadpointSetting_TargetEnergy)(nil),
+		(*LoadpointSetting_MinCurrent)(nil),
+		(*LoadpointSetting_MaxCurrent)(nil),
+		(*LoadpointSetting_Phases)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_evcc_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_evcc_proto_goTypes,
+		DependencyIndexes: file_proto_evcc_proto_depIdxs,
+		EnumInfos:         file_proto_evcc_proto_enumTypes,
+		MessageInfos:      file_proto_evcc_proto_msgTypes,
+	}.Build()
+	File_proto_evcc_proto = out.File
+	file_proto_evcc_proto_rawDesc = nil
+	file_proto_evcc_proto_goTypes = nil
+	file_proto_evcc_proto_depIdxs = nil
+}
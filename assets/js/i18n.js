@@ -18,8 +18,18 @@ function getBrowserLocale() {
   return navigatorLocale.trim().split(/-|_/)[0];
 }
 
+// fallback chain matching the backend, e.g. de-CH → de → en
+function fallbackLocales(locale) {
+  const base = (locale || "").split(/-|_/)[0];
+  return [base, "en"].filter((l, i, all) => l && l !== locale && all.indexOf(l) === i);
+}
+
+// user selected language persisted by the backend takes precedence over browser detection
+const locale =
+  window.evcc?.language || window.localStorage[PREFERRED_LOCALE_KEY] || getBrowserLocale();
+
 export default createI18n({
-  locale: window.localStorage[PREFERRED_LOCALE_KEY] || getBrowserLocale(),
-  fallbackLocale: "en",
+  locale,
+  fallbackLocale: fallbackLocales(locale),
   messages: { de, en, it, lt, nl, pl, pt },
 });
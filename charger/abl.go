@@ -250,6 +250,26 @@ func (wb *ABLeMH) Diagnose() {
 	}
 }
 
+var _ api.DiagnosticReporter = (*ABLeMH)(nil)
+
+// Diagnostics implements the api.DiagnosticReporter interface
+func (wb *ABLeMH) Diagnostics() (api.Diagnostics, error) {
+	firmware, err := wb.get(ablRegFirmware, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := wb.get(ablRegStatus, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.Diagnostics{
+		"firmware": fmt.Sprintf("% x", firmware),
+		"status":   fmt.Sprintf("% x", status),
+	}, nil
+}
+
 var _ api.Resurrector = (*ABLeMH)(nil)
 
 // WakeUp implements the api.Resurrector interface
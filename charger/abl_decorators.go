@@ -7,11 +7,19 @@ import (
 )
 
 func decorateABLeMH(base *ABLeMH, meter func() (float64, error), meterCurrent func() (float64, float64, float64, error)) api.Charger {
-	switch {
-	case meter == nil && meterCurrent == nil:
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterCurrent != nil {
+		caps |= 2
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case meter != nil && meterCurrent == nil:
+	case 1: // api.Meter
 		return &struct {
 			*ABLeMH
 			api.Meter
@@ -22,7 +30,7 @@ func decorateABLeMH(base *ABLeMH, meter func() (float64, error), meterCurrent fu
 			},
 		}
 
-	case meter == nil && meterCurrent != nil:
+	case 2: // api.MeterCurrent
 		return &struct {
 			*ABLeMH
 			api.MeterCurrent
@@ -33,7 +41,7 @@ func decorateABLeMH(base *ABLeMH, meter func() (float64, error), meterCurrent fu
 			},
 		}
 
-	case meter != nil && meterCurrent != nil:
+	case 3: // api.Meter, api.MeterCurrent
 		return &struct {
 			*ABLeMH
 			api.Meter
@@ -7,11 +7,28 @@ import (
 )
 
 func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurrent func() (float64, float64, float64, error), chargeRater func() (float64, error), meterEnergy func() (float64, error), identifier func() (string, error)) api.Charger {
-	switch {
-	case chargeRater == nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterCurrent != nil {
+		caps |= 2
+	}
+	if chargeRater != nil {
+		caps |= 4
+	}
+	if meterEnergy != nil {
+		caps |= 8
+	}
+	if identifier != nil {
+		caps |= 16
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case chargeRater == nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 1: // api.Meter
 		return &struct {
 			*BenderCC
 			api.Meter
@@ -22,7 +39,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 2: // api.MeterCurrent
 		return &struct {
 			*BenderCC
 			api.MeterCurrent
@@ -33,7 +50,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 3: // api.Meter, api.MeterCurrent
 		return &struct {
 			*BenderCC
 			api.Meter
@@ -48,7 +65,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 4: // api.ChargeRater
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -59,7 +76,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 5: // api.Meter, api.ChargeRater
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -74,7 +91,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 6: // api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -89,7 +106,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 7: // api.Meter, api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -108,7 +125,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 8: // api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.MeterEnergy
@@ -119,7 +136,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 9: // api.Meter, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.Meter
@@ -134,7 +151,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 10: // api.MeterCurrent, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.MeterCurrent
@@ -149,7 +166,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 11: // api.Meter, api.MeterCurrent, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.Meter
@@ -168,7 +185,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 12: // api.ChargeRater, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -183,7 +200,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 13: // api.Meter, api.ChargeRater, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -202,7 +219,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 14: // api.MeterCurrent, api.ChargeRater, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -221,7 +238,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 15: // api.Meter, api.MeterCurrent, api.ChargeRater, api.MeterEnergy
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -244,7 +261,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 16: // api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -255,7 +272,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 17: // api.Meter, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -270,7 +287,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 18: // api.MeterCurrent, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -285,7 +302,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 19: // api.Meter, api.MeterCurrent, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -304,7 +321,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 20: // api.ChargeRater, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -319,7 +336,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 21: // api.Meter, api.ChargeRater, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -338,7 +355,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 22: // api.MeterCurrent, api.ChargeRater, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -357,7 +374,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 23: // api.Meter, api.MeterCurrent, api.ChargeRater, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -380,7 +397,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 24: // api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -395,7 +412,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 25: // api.Meter, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -414,7 +431,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 26: // api.MeterCurrent, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -433,7 +450,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater == nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 27: // api.Meter, api.MeterCurrent, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.Identifier
@@ -456,7 +473,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 28: // api.ChargeRater, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -475,7 +492,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 29: // api.Meter, api.ChargeRater, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -498,7 +515,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 30: // api.MeterCurrent, api.ChargeRater, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
@@ -521,7 +538,7 @@ func decorateBenderCC(base *BenderCC, meter func() (float64, error), meterCurren
 			},
 		}
 
-	case chargeRater != nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 31: // api.Meter, api.MeterCurrent, api.ChargeRater, api.MeterEnergy, api.Identifier
 		return &struct {
 			*BenderCC
 			api.ChargeRater
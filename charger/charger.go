@@ -2,6 +2,7 @@ package charger
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/provider"
@@ -20,13 +21,15 @@ func init() {
 	registry.Add(api.Custom, NewConfigurableFromConfig)
 }
 
-// go:generate go run ../cmd/tools/decorate.go -f decorateCustom -b *Charger -r api.Charger -t "api.Identifier,Identify,func() (string, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) (error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateCustom -b *Charger -r api.Charger -t "api.Identifier,Identify,func() (string, error)" -t "api.PhaseSwitcher,Phases1p3p,func(phases int) error" -t "api.ChargerEx,MaxCurrentMillis,func(current float64) error" -t "api.PowerController,MaxPower,func(power float64) error"
 
 // NewConfigurableFromConfig creates a new configurable charger
 func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error) {
 	var cc struct {
 		Status, Enable, Enabled, MaxCurrent provider.Config
 		Identify, Phases1p3p                *provider.Config
+		MaxCurrentMillis                    *provider.Config
+		MaxPower                            *provider.Config
 	}
 	if err := util.DecodeOther(other, &cc); err != nil {
 		return nil, err
@@ -71,7 +74,29 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error
 		identify, err = provider.NewStringGetterFromConfig(*cc.Identify)
 	}
 
-	return decorateCustom(c, identify, phases1p3p), err
+	// decorator milliamp current, configured setter receives mA
+	var maxCurrentMillis func(float64) error
+	if err == nil && cc.MaxCurrentMillis != nil {
+		var maxCurrentMillisI64 func(int64) error
+		maxCurrentMillisI64, err = provider.NewIntSetterFromConfig("maxcurrentmillis", *cc.MaxCurrentMillis)
+
+		maxCurrentMillis = func(current float64) error {
+			return maxCurrentMillisI64(int64(math.Round(current * 1e3)))
+		}
+	}
+
+	// decorator power setpoint, configured setter receives W
+	var maxPower func(float64) error
+	if err == nil && cc.MaxPower != nil {
+		var maxPowerI64 func(int64) error
+		maxPowerI64, err = provider.NewIntSetterFromConfig("maxpower", *cc.MaxPower)
+
+		maxPower = func(power float64) error {
+			return maxPowerI64(int64(math.Round(power)))
+		}
+	}
+
+	return decorateCustom(c, identify, phases1p3p, maxCurrentMillis, maxPower), err
 }
 
 // NewConfigurable creates a new charger
@@ -6,12 +6,26 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateCustom(base *Charger, identifier func() (string, error), phaseSwitcher func(int) error) api.Charger {
-	switch {
-	case identifier == nil && phaseSwitcher == nil:
+func decorateCustom(base *Charger, identifier func() (string, error), phaseSwitcher func(phases int) error, chargerEx func(current float64) error, powerController func(power float64) error) api.Charger {
+	var caps int
+	if identifier != nil {
+		caps |= 1
+	}
+	if phaseSwitcher != nil {
+		caps |= 2
+	}
+	if chargerEx != nil {
+		caps |= 4
+	}
+	if powerController != nil {
+		caps |= 8
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case identifier != nil && phaseSwitcher == nil:
+	case 1: // api.Identifier
 		return &struct {
 			*Charger
 			api.Identifier
@@ -22,7 +36,7 @@ func decorateCustom(base *Charger, identifier func() (string, error), phaseSwitc
 			},
 		}
 
-	case identifier == nil && phaseSwitcher != nil:
+	case 2: // api.PhaseSwitcher
 		return &struct {
 			*Charger
 			api.PhaseSwitcher
@@ -33,7 +47,7 @@ func decorateCustom(base *Charger, identifier func() (string, error), phaseSwitc
 			},
 		}
 
-	case identifier != nil && phaseSwitcher != nil:
+	case 3: // api.Identifier, api.PhaseSwitcher
 		return &struct {
 			*Charger
 			api.Identifier
@@ -47,11 +61,215 @@ func decorateCustom(base *Charger, identifier func() (string, error), phaseSwitc
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
+
+	case 4: // api.ChargerEx
+		return &struct {
+			*Charger
+			api.ChargerEx
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+		}
+
+	case 5: // api.Identifier, api.ChargerEx
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+		}
+
+	case 6: // api.PhaseSwitcher, api.ChargerEx
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PhaseSwitcher
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 7: // api.Identifier, api.PhaseSwitcher, api.ChargerEx
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PhaseSwitcher
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 8: // api.PowerController
+		return &struct {
+			*Charger
+			api.PowerController
+		}{
+			Charger: base,
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 9: // api.Identifier, api.PowerController
+		return &struct {
+			*Charger
+			api.Identifier
+			api.PowerController
+		}{
+			Charger: base,
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 10: // api.PhaseSwitcher, api.PowerController
+		return &struct {
+			*Charger
+			api.PhaseSwitcher
+			api.PowerController
+		}{
+			Charger: base,
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 11: // api.Identifier, api.PhaseSwitcher, api.PowerController
+		return &struct {
+			*Charger
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerController
+		}{
+			Charger: base,
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 12: // api.ChargerEx, api.PowerController
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PowerController
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 13: // api.Identifier, api.ChargerEx, api.PowerController
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PowerController
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 14: // api.PhaseSwitcher, api.ChargerEx, api.PowerController
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PhaseSwitcher
+			api.PowerController
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
+
+	case 15: // api.Identifier, api.PhaseSwitcher, api.ChargerEx, api.PowerController
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerController
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerController: &decorateCustomPowerControllerImpl{
+				powerController: powerController,
+			},
+		}
 	}
 
 	return nil
 }
 
+type decorateCustomChargerExImpl struct {
+	chargerEx func(current float64) error
+}
+
+func (impl *decorateCustomChargerExImpl) MaxCurrentMillis(current float64) error {
+	return impl.chargerEx(current)
+}
+
 type decorateCustomIdentifierImpl struct {
 	identifier func() (string, error)
 }
@@ -61,9 +279,17 @@ func (impl *decorateCustomIdentifierImpl) Identify() (string, error) {
 }
 
 type decorateCustomPhaseSwitcherImpl struct {
-	phaseSwitcher func(int) error
+	phaseSwitcher func(phases int) error
 }
 
 func (impl *decorateCustomPhaseSwitcherImpl) Phases1p3p(phases int) error {
 	return impl.phaseSwitcher(phases)
 }
+
+type decorateCustomPowerControllerImpl struct {
+	powerController func(power float64) error
+}
+
+func (impl *decorateCustomPowerControllerImpl) MaxPower(power float64) error {
+	return impl.powerController(power)
+}
@@ -0,0 +1,50 @@
+package charger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurableMaxCurrentMillis(t *testing.T) {
+	var uris []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uris = append(uris, r.URL.RequestURI())
+	}))
+	defer ts.Close()
+
+	setter := func(param string) map[string]interface{} {
+		return map[string]interface{}{"source": "http", "uri": ts.URL + "/" + param + "?value=${" + param + "}"}
+	}
+
+	other := map[string]interface{}{
+		"status":     setter("status"),
+		"enabled":    setter("enabled"),
+		"enable":     setter("enable"),
+		"maxcurrent": setter("maxcurrent"),
+	}
+
+	c, err := NewConfigurableFromConfig(other)
+	require.NoError(t, err)
+
+	_, ok := c.(api.ChargerEx)
+	assert.False(t, ok, "unexpected api.ChargerEx")
+
+	other["maxcurrentmillis"] = setter("maxcurrentmillis")
+
+	c, err = NewConfigurableFromConfig(other)
+	require.NoError(t, err)
+
+	cex, ok := c.(api.ChargerEx)
+	require.True(t, ok, "missing api.ChargerEx")
+
+	require.NoError(t, cex.MaxCurrentMillis(6.4321))
+	require.NoError(t, c.MaxCurrent(7))
+
+	assert.Equal(t, []string{"/maxcurrentmillis?value=6432", "/maxcurrent?value=7"}, uris)
+}
@@ -64,7 +64,7 @@ func init() {
 	registry.Add("easee", NewEaseeFromConfig)
 }
 
-// NewEaseeFromConfig creates a go-e charger from generic config
+// NewEaseeFromConfig creates an Easee charger from generic config
 func NewEaseeFromConfig(other map[string]interface{}) (api.Charger, error) {
 	var cc struct {
 		User     string
@@ -510,9 +510,28 @@ func (c *Easee) TotalEnergy() (float64, error) {
 	return c.totalEnergy, nil
 }
 
+var _ api.Commander = (*Easee)(nil)
+
+// Commands implements the api.Commander interface
+func (c *Easee) Commands() []api.Command {
+	return []api.Command{api.CommandReboot}
+}
+
+// Command implements the api.Commander interface
+func (c *Easee) Command(cmd api.Command) error {
+	if cmd != api.CommandReboot {
+		return api.ErrNotAvailable
+	}
+
+	uri := fmt.Sprintf("%s/chargers/%s/commands/%s", easee.API, c.charger, easee.Reboot)
+	_, err := c.Post(uri, request.JSONContent, nil)
+
+	return err
+}
+
 var _ api.Identifier = (*Easee)(nil)
 
-// Currents implements the api.MeterCurrent interface
+// Identify implements the api.Identifier interface
 func (c *Easee) Identify() (string, error) {
 	c.mux.L.Lock()
 	defer c.mux.L.Unlock()
@@ -6,6 +6,7 @@ const API = "https://api.easee.cloud/api"
 const (
 	ChargePause  = "pause_charging"
 	ChargeResume = "resume_charging"
+	Reboot       = "reboot"
 )
 
 // charge mode definition
@@ -7,11 +7,22 @@ import (
 )
 
 func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func() (float64, float64, float64, error), chargeRater func() (float64, error)) api.Charger {
-	switch {
-	case chargeRater == nil && meter == nil && meterCurrent == nil:
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterCurrent != nil {
+		caps |= 2
+	}
+	if chargeRater != nil {
+		caps |= 4
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case chargeRater == nil && meter != nil && meterCurrent == nil:
+	case 1: // api.Meter
 		return &struct {
 			*EEBus
 			api.Meter
@@ -22,7 +33,7 @@ func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func
 			},
 		}
 
-	case chargeRater == nil && meter == nil && meterCurrent != nil:
+	case 2: // api.MeterCurrent
 		return &struct {
 			*EEBus
 			api.MeterCurrent
@@ -33,7 +44,7 @@ func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func
 			},
 		}
 
-	case chargeRater == nil && meter != nil && meterCurrent != nil:
+	case 3: // api.Meter, api.MeterCurrent
 		return &struct {
 			*EEBus
 			api.Meter
@@ -48,7 +59,7 @@ func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func
 			},
 		}
 
-	case chargeRater != nil && meter == nil && meterCurrent == nil:
+	case 4: // api.ChargeRater
 		return &struct {
 			*EEBus
 			api.ChargeRater
@@ -59,7 +70,7 @@ func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func
 			},
 		}
 
-	case chargeRater != nil && meter != nil && meterCurrent == nil:
+	case 5: // api.Meter, api.ChargeRater
 		return &struct {
 			*EEBus
 			api.ChargeRater
@@ -74,7 +85,7 @@ func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func
 			},
 		}
 
-	case chargeRater != nil && meter == nil && meterCurrent != nil:
+	case 6: // api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*EEBus
 			api.ChargeRater
@@ -89,7 +100,7 @@ func decorateEEBus(base *EEBus, meter func() (float64, error), meterCurrent func
 			},
 		}
 
-	case chargeRater != nil && meter != nil && meterCurrent != nil:
+	case 7: // api.Meter, api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*EEBus
 			api.ChargeRater
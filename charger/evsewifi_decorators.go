@@ -6,12 +6,29 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), chargerEx func(float64) error, identifier func() (string, error)) api.Charger {
-	switch {
-	case chargerEx == nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), chargerEx func(current float64) error, identifier func() (string, error)) api.Charger {
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+	if chargerEx != nil {
+		caps |= 8
+	}
+	if identifier != nil {
+		caps |= 16
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case chargerEx == nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 1: // api.Meter
 		return &struct {
 			*EVSEWifi
 			api.Meter
@@ -22,7 +39,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 2: // api.MeterEnergy
 		return &struct {
 			*EVSEWifi
 			api.MeterEnergy
@@ -33,7 +50,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 3: // api.Meter, api.MeterEnergy
 		return &struct {
 			*EVSEWifi
 			api.Meter
@@ -48,7 +65,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 4: // api.MeterCurrent
 		return &struct {
 			*EVSEWifi
 			api.MeterCurrent
@@ -59,7 +76,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 5: // api.Meter, api.MeterCurrent
 		return &struct {
 			*EVSEWifi
 			api.Meter
@@ -74,7 +91,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 6: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*EVSEWifi
 			api.MeterCurrent
@@ -89,7 +106,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*EVSEWifi
 			api.Meter
@@ -108,7 +125,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 8: // api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -119,7 +136,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 9: // api.Meter, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -134,7 +151,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 10: // api.MeterEnergy, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -149,7 +166,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 11: // api.Meter, api.MeterEnergy, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -168,7 +185,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 12: // api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -183,7 +200,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 13: // api.Meter, api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -202,7 +219,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 14: // api.MeterEnergy, api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -221,7 +238,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier == nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 15: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -244,7 +261,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 16: // api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -255,7 +272,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 17: // api.Meter, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -270,7 +287,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 18: // api.MeterEnergy, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -285,7 +302,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 19: // api.Meter, api.MeterEnergy, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -304,7 +321,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 20: // api.MeterCurrent, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -319,7 +336,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 21: // api.Meter, api.MeterCurrent, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -338,7 +355,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 22: // api.MeterEnergy, api.MeterCurrent, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -357,7 +374,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 23: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.Identifier
@@ -380,7 +397,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 24: // api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -395,7 +412,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 25: // api.Meter, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -414,7 +431,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 26: // api.MeterEnergy, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -433,7 +450,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 27: // api.Meter, api.MeterEnergy, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -456,7 +473,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 28: // api.MeterCurrent, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -475,7 +492,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 29: // api.Meter, api.MeterCurrent, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -498,7 +515,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 30: // api.MeterEnergy, api.MeterCurrent, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
@@ -521,7 +538,7 @@ func decorateEVSE(base *EVSEWifi, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && identifier != nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 31: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.ChargerEx, api.Identifier
 		return &struct {
 			*EVSEWifi
 			api.ChargerEx
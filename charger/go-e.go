@@ -20,6 +20,9 @@ package charger
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -34,27 +37,56 @@ import (
 
 // GoE charger implementation
 type GoE struct {
-	api goe.API
+	api      goe.API
+	colors   map[api.Indication]string
+	lock     int  // v2 cable lock mode restored after unlocking
+	unlocked bool // cable lock mode is set to auto unlock
+}
+
+// goeCableLocks are the v2 cable lock modes
+var goeCableLocks = map[string]int{
+	"normal": 0, // locked while the vehicle is connected
+	"auto":   1, // unlocked when charging is finished
+	"locked": 2, // always locked
+}
+
+// goeColors are the default led colors when the indicator is enabled
+var goeColors = map[string]string{
+	"waiting":  "#FFFF00",
+	"pv":       "#00FF00",
+	"charging": "#0000FF",
+	"error":    "#FF0000",
 }
 
 func init() {
 	registry.Add("go-e", NewGoEFromConfig)
 }
 
-// go:generate go run ../cmd/tools/decorate.go -f decorateGoE -b *GoE -r api.Charger -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) (error)"
+// go:generate go run ../cmd/tools/decorate.go -f decorateGoE -b *GoE -r api.Charger -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) (error)" -t "api.CableUnlocker,Unlock,func() error"
 
 // NewGoEFromConfig creates a go-e charger from generic config
 func NewGoEFromConfig(other map[string]interface{}) (api.Charger, error) {
 	var cc struct {
-		Token string
-		URI   string
-		Cache time.Duration
+		Token     string
+		URI       string
+		Cache     time.Duration
+		Indicator bool              // reflect loadpoint state on the charger's led
+		Colors    map[string]string // led colors per indication
+		CableLock string            // v2 cable lock mode: normal, auto or locked
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
 		return nil, err
 	}
 
+	var colors map[api.Indication]string
+	if cc.Indicator {
+		var err error
+		if colors, err = indicatorColors(goeColors, cc.Colors); err != nil {
+			return nil, err
+		}
+	}
+
 	if cc.URI != "" && cc.Token != "" {
 		return nil, errors.New("should only have one of uri/token")
 	}
@@ -62,12 +94,23 @@ func NewGoEFromConfig(other map[string]interface{}) (api.Charger, error) {
 		return nil, errors.New("must have one of uri/token")
 	}
 
-	return NewGoE(cc.URI, cc.Token, cc.Cache)
+	lock := -1
+	if cc.CableLock != "" {
+		var ok bool
+		if lock, ok = goeCableLocks[strings.ToLower(cc.CableLock)]; !ok {
+			return nil, fmt.Errorf("invalid cable lock: %s", cc.CableLock)
+		}
+	}
+
+	return NewGoE(cc.URI, cc.Token, cc.Cache, colors, lock)
 }
 
-// NewGoE creates GoE charger
-func NewGoE(uri, token string, cache time.Duration) (api.Charger, error) {
-	c := &GoE{}
+// NewGoE creates GoE charger. The v2 cable lock mode is applied unless negative.
+func NewGoE(uri, token string, cache time.Duration, colors map[api.Indication]string, lock int) (api.Charger, error) {
+	c := &GoE{
+		colors: colors,
+		lock:   lock,
+	}
 
 	log := util.NewLogger("go-e").Redact(token)
 
@@ -82,10 +125,20 @@ func NewGoE(uri, token string, cache time.Duration) (api.Charger, error) {
 	}
 
 	if c.api.IsV2() {
-		return decorateGoE(c, c.totalEnergy, c.phases1p3p), nil
+		if lock >= 0 {
+			if err := c.api.Update(fmt.Sprintf("ust=%d", lock)); err != nil {
+				return nil, err
+			}
+		}
+
+		return decorateGoE(c, c.totalEnergy, c.phases1p3p, c.unlock), nil
+	}
+
+	if lock >= 0 {
+		return nil, errors.New("cable lock requires api v2")
 	}
 
-	return c, nil
+	return decorateGoE(c, nil, nil, nil), nil
 }
 
 // Status implements the api.Charger interface
@@ -129,7 +182,22 @@ func (c *GoE) Enable(enable bool) error {
 		b += 1
 	}
 
-	return c.api.Update(fmt.Sprintf("%s=%d", param, b))
+	payload := fmt.Sprintf("%s=%d", param, b)
+
+	// restore cable lock mode for the next vehicle
+	if enable && c.unlocked {
+		payload += fmt.Sprintf("&ust=%d", c.lock)
+	}
+
+	if err := c.api.Update(payload); err != nil {
+		return err
+	}
+
+	if enable {
+		c.unlocked = false
+	}
+
+	return nil
 }
 
 // MaxCurrent implements the api.Charger interface
@@ -138,6 +206,18 @@ func (c *GoE) MaxCurrent(current int64) error {
 	return c.api.Update(fmt.Sprintf("%s=%d", param, current))
 }
 
+var _ api.CurrentGetter = (*GoE)(nil)
+
+// GetMaxCurrent implements the api.CurrentGetter interface
+func (c *GoE) GetMaxCurrent() (float64, error) {
+	resp, err := c.api.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(resp.MaxCurrent()), nil
+}
+
 var _ api.Meter = (*GoE)(nil)
 
 // CurrentPower implements the api.Meter interface
@@ -176,6 +256,45 @@ func (c *GoE) Currents() (float64, float64, float64, error) {
 	return i1, i2, i3, err
 }
 
+var _ api.MeterVoltage = (*GoE)(nil)
+
+// Voltages implements the api.MeterVoltage interface
+func (c *GoE) Voltages() (float64, float64, float64, error) {
+	resp, err := c.api.Status()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	u1, u2, u3 := resp.Voltages()
+
+	return u1, u2, u3, err
+}
+
+var _ api.FaultReporter = (*GoE)(nil)
+
+// Fault implements the api.FaultReporter interface
+func (c *GoE) Fault() (string, error) {
+	resp, err := c.api.Status()
+	if err != nil {
+		return "", err
+	}
+
+	switch resp.Fault() {
+	case 0:
+		return api.FaultNone, nil
+	case 1:
+		return api.FaultRCCB, nil
+	case 3:
+		return api.FaultPhase, nil
+	case 8:
+		return api.FaultGround, nil
+	case 13:
+		return api.FaultTemperature, nil
+	default:
+		return api.FaultInternal, nil
+	}
+}
+
 var _ api.Identifier = (*GoE)(nil)
 
 // Identify implements the api.Identifier interface
@@ -187,6 +306,31 @@ func (c *GoE) Identify() (string, error) {
 	return resp.Identify(), nil
 }
 
+var _ api.Indicator = (*GoE)(nil)
+
+// Indicate implements the api.Indicator interface - v2 only
+func (c *GoE) Indicate(indication api.Indication) error {
+	if c.colors == nil || !c.api.IsV2() {
+		return api.ErrNotAvailable
+	}
+
+	color, ok := c.colors[indication]
+	if !ok {
+		return nil
+	}
+
+	// led color of the charger state matching the indication
+	param := map[api.Indication]string{
+		api.IndicationIdle:     "cid",
+		api.IndicationWaiting:  "cwc",
+		api.IndicationPV:       "cch",
+		api.IndicationCharging: "cch",
+		api.IndicationError:    "cwc",
+	}[indication]
+
+	return c.api.Update(fmt.Sprintf("%s=%s", param, url.QueryEscape(strconv.Quote(color))))
+}
+
 // totalEnergy implements the api.MeterEnergy interface - v2 only
 func (c *GoE) totalEnergy() (float64, error) {
 	resp, err := c.api.Status()
@@ -210,3 +354,31 @@ func (c *GoE) phases1p3p(phases int) error {
 
 	return c.api.Update(fmt.Sprintf("psm=%d", phases))
 }
+
+// unlock implements the api.CableUnlocker interface - v2 only
+func (c *GoE) unlock() error {
+	if c.unlocked {
+		return nil
+	}
+
+	// keep the current cable lock mode unless configured
+	if c.lock < 0 {
+		resp, err := c.api.Status()
+		if err != nil {
+			return err
+		}
+
+		if res, ok := resp.(*goe.StatusResponse2); ok {
+			c.lock = res.Ust
+		}
+	}
+
+	// auto unlock releases the cable of the disabled charger
+	if err := c.api.Update(fmt.Sprintf("ust=%d", goeCableLocks["auto"])); err != nil {
+		return err
+	}
+
+	c.unlocked = true
+
+	return nil
+}
@@ -3,6 +3,7 @@ package goe
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,11 +20,17 @@ type Response interface {
 	CurrentPower() float64
 	ChargedEnergy() float64
 	Currents() (float64, float64, float64)
+	MaxCurrent() int
+	Voltages() (float64, float64, float64)
+	Fault() int
 	Identify() string
 }
 
 type UpdateResponse map[string]interface{}
 
+// StatusKeys are the v2 api keys read by the status request
+var StatusKeys = []string{"alw", "amp", "car", "err", "eto", "nrg", "psm", "ust", "wh", "trx", "cards"}
+
 type API interface {
 	IsV2() bool
 	Status() (Response, error)
@@ -86,7 +93,7 @@ func (c *LocalAPI) Status() (res Response, err error) {
 	if time.Since(c.updated) > c.cache {
 		if c.v2 {
 			c.status = new(StatusResponse2)
-			err = c.response("status?filter=alw,car,eto,nrg,wh,trx,cards", &c.status)
+			err = c.response("status?filter="+strings.Join(StatusKeys, ","), &c.status)
 		} else {
 			c.status = new(StatusResponse)
 			err = c.response("status", &c.status)
@@ -98,20 +105,42 @@ func (c *LocalAPI) Status() (res Response, err error) {
 	return c.status, err
 }
 
-// Update executes a v1/v2 api update and returns the response
+// Update executes a v1/v2 api update and returns the response.
+// The v2 api confirms each updated key, partial updates return the rejected keys as error.
 func (c *LocalAPI) Update(payload string) error {
 	c.updated = time.Time{}
 	res := new(UpdateResponse)
 
 	if c.v2 {
-		err := c.response(fmt.Sprintf("set?%s", payload), &res)
-		return err
+		if err := c.response(fmt.Sprintf("set?%s", payload), &res); err != nil {
+			return err
+		}
+
+		return res.Err()
 	}
 
 	err := c.response(fmt.Sprintf("mqtt?payload=%s", payload), &res)
 	return err
 }
 
+// Err returns the keys not confirmed by the v2 api
+func (r UpdateResponse) Err() error {
+	var rejected []string
+	for key, val := range r {
+		if ok, _ := val.(bool); !ok {
+			rejected = append(rejected, fmt.Sprintf("%s: %v", key, val))
+		}
+	}
+
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	sort.Strings(rejected)
+
+	return fmt.Errorf("update rejected: %s", strings.Join(rejected, ", "))
+}
+
 type cloud struct {
 	*request.Helper
 	token   string
@@ -57,7 +57,7 @@ func TestLocalV2(t *testing.T) {
 	h.expect("/api/status?filter=alw")
 	local := NewLocal(util.NewLogger("foo"), srv.URL, 0)
 
-	h.expect("/api/status?filter=alw,car,eto,nrg,wh,trx,cards")
+	h.expect("/api/status?filter=alw,amp,car,err,eto,nrg,psm,ust,wh,trx,cards")
 	if _, err := local.Status(); err != nil {
 		t.Error(err)
 	}
@@ -67,3 +67,14 @@ func TestLocalV2(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestUpdateResponse(t *testing.T) {
+	if err := (UpdateResponse{"amp": true, "psm": true}).Err(); err != nil {
+		t.Error(err)
+	}
+
+	err := (UpdateResponse{"amp": true, "psm": "value out of range"}).Err()
+	if err == nil || err.Error() != "update rejected: psm: value out of range" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -58,6 +58,22 @@ func (g *StatusResponse) Currents() (float64, float64, float64) {
 	return 0, 0, 0
 }
 
+func (g *StatusResponse) MaxCurrent() int {
+	return g.Amp
+}
+
+func (g *StatusResponse) Voltages() (float64, float64, float64) {
+	if len(g.Nrg) == 16 {
+		return g.Nrg[0], g.Nrg[1], g.Nrg[2]
+	}
+
+	return 0, 0, 0
+}
+
+func (g *StatusResponse) Fault() int {
+	return g.Err
+}
+
 func (g *StatusResponse) Identify() string {
 	switch g.Uby {
 	case 1:
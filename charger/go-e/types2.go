@@ -8,10 +8,11 @@ type StatusResponse2 struct {
 	Amp   int       // current [A]
 	Err   int       // error
 	Eto   uint64    // energy total Wh
-	Psm   int       // phase switching
+	Psm   int       // phase switching: 0 auto, 1 single phase, 2 three phases
 	Stp   int       // stop state
 	Tmp   int       // temperature [°C]
 	Trx   int       // transaction
+	Ust   int       // cable lock: 0 normal, 1 auto unlock, 2 always locked
 	Nrg   []float64 // voltage, current, power
 	Wh    float64   // energy [Wh]
 	Cards []Card    // RFID cards
@@ -56,8 +57,36 @@ func (g *StatusResponse2) Currents() (float64, float64, float64) {
 	return 0, 0, 0
 }
 
+func (g *StatusResponse2) MaxCurrent() int {
+	return g.Amp
+}
+
+func (g *StatusResponse2) Voltages() (float64, float64, float64) {
+	if len(g.Nrg) == 16 {
+		return g.Nrg[0], g.Nrg[1], g.Nrg[2]
+	}
+
+	return 0, 0, 0
+}
+
+// Phases returns the phase switching mode as number of phases or 0 if automatic
+func (g *StatusResponse2) Phases() int {
+	switch g.Psm {
+	case 1:
+		return 1
+	case 2:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func (g *StatusResponse2) Fault() int {
+	return g.Err
+}
+
 func (g *StatusResponse2) Identify() string {
-	if g.Trx > 0 {
+	if g.Trx > 0 && g.Trx <= len(g.Cards) {
 		return g.Cards[g.Trx-1].Name
 	}
 
@@ -1,17 +1,28 @@
 package charger
 
-// Code generated by github.com/andig/cmd/tools/decorate.go. DO NOT EDIT.
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
 
 import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateGoE(base *GoE, meterEnergy func() (float64, error), phaseSwitcher func(phases int) error) api.Charger {
-	switch {
-	case phaseSwitcher == nil && meterEnergy == nil:
+func decorateGoE(base *GoE, meterEnergy func() (float64, error), phaseSwitcher func(phases int) error, cableUnlocker func() error) api.Charger {
+	var caps int
+	if meterEnergy != nil {
+		caps |= 1
+	}
+	if phaseSwitcher != nil {
+		caps |= 2
+	}
+	if cableUnlocker != nil {
+		caps |= 4
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case phaseSwitcher == nil && meterEnergy != nil:
+	case 1: // api.MeterEnergy
 		return &struct {
 			*GoE
 			api.MeterEnergy
@@ -22,7 +33,7 @@ func decorateGoE(base *GoE, meterEnergy func() (float64, error), phaseSwitcher f
 			},
 		}
 
-	case phaseSwitcher != nil && meterEnergy == nil:
+	case 2: // api.PhaseSwitcher
 		return &struct {
 			*GoE
 			api.PhaseSwitcher
@@ -33,31 +44,91 @@ func decorateGoE(base *GoE, meterEnergy func() (float64, error), phaseSwitcher f
 			},
 		}
 
-	case phaseSwitcher != nil && meterEnergy != nil:
+	case 3: // api.MeterEnergy, api.PhaseSwitcher
 		return &struct {
 			*GoE
+			api.MeterEnergy
 			api.PhaseSwitcher
+		}{
+			GoE: base,
+			MeterEnergy: &decorateGoEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateGoEPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 4: // api.CableUnlocker
+		return &struct {
+			*GoE
+			api.CableUnlocker
+		}{
+			GoE: base,
+			CableUnlocker: &decorateGoECableUnlockerImpl{
+				cableUnlocker: cableUnlocker,
+			},
+		}
+
+	case 5: // api.MeterEnergy, api.CableUnlocker
+		return &struct {
+			*GoE
+			api.CableUnlocker
 			api.MeterEnergy
 		}{
 			GoE: base,
+			CableUnlocker: &decorateGoECableUnlockerImpl{
+				cableUnlocker: cableUnlocker,
+			},
+			MeterEnergy: &decorateGoEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 6: // api.PhaseSwitcher, api.CableUnlocker
+		return &struct {
+			*GoE
+			api.CableUnlocker
+			api.PhaseSwitcher
+		}{
+			GoE: base,
+			CableUnlocker: &decorateGoECableUnlockerImpl{
+				cableUnlocker: cableUnlocker,
+			},
 			PhaseSwitcher: &decorateGoEPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
+		}
+
+	case 7: // api.MeterEnergy, api.PhaseSwitcher, api.CableUnlocker
+		return &struct {
+			*GoE
+			api.CableUnlocker
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			GoE: base,
+			CableUnlocker: &decorateGoECableUnlockerImpl{
+				cableUnlocker: cableUnlocker,
+			},
 			MeterEnergy: &decorateGoEMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			PhaseSwitcher: &decorateGoEPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 	}
 
 	return nil
 }
 
-type decorateGoEPhaseSwitcherImpl struct {
-	phaseSwitcher func(int) error
+type decorateGoECableUnlockerImpl struct {
+	cableUnlocker func() error
 }
 
-func (impl *decorateGoEPhaseSwitcherImpl) Phases1p3p(phases int) error {
-	return impl.phaseSwitcher(phases)
+func (impl *decorateGoECableUnlockerImpl) Unlock() error {
+	return impl.cableUnlocker()
 }
 
 type decorateGoEMeterEnergyImpl struct {
@@ -67,3 +138,11 @@ type decorateGoEMeterEnergyImpl struct {
 func (impl *decorateGoEMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
+
+type decorateGoEPhaseSwitcherImpl struct {
+	phaseSwitcher func(phases int) error
+}
+
+func (impl *decorateGoEPhaseSwitcherImpl) Phases1p3p(phases int) error {
+	return impl.phaseSwitcher(phases)
+}
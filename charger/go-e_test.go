@@ -38,7 +38,7 @@ func TestGoEV1(t *testing.T) {
 
 	sponsor.Subject = "foo"
 
-	wb, err := NewGoE(srv.URL, "", 0)
+	wb, err := NewGoE(srv.URL, "", 0, nil, -1)
 	if err != nil {
 		t.Error(err)
 	}
@@ -63,7 +63,7 @@ func TestGoEV2(t *testing.T) {
 	sponsor.Subject = "foo"
 
 	h.expect("/api/status?filter=alw")
-	wb, err := NewGoE(srv.URL, "", 0)
+	wb, err := NewGoE(srv.URL, "", 0, nil, -1)
 	if err != nil {
 		t.Error(err)
 	}
@@ -87,4 +87,8 @@ func TestGoEV2(t *testing.T) {
 	if _, ok := wb.(api.PhaseSwitcher); !ok {
 		t.Error("missing PhaseSwitcher api")
 	}
+
+	if _, ok := wb.(api.CableUnlocker); !ok {
+		t.Error("missing CableUnlocker api")
+	}
 }
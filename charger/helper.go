@@ -3,8 +3,28 @@ package charger
 import (
 	"fmt"
 	"strings"
+
+	"github.com/evcc-io/evcc/api"
 )
 
+// indicatorColors returns the indication colors with configured colors overriding the defaults
+func indicatorColors(defaults, colors map[string]string) (map[api.Indication]string, error) {
+	res := make(map[api.Indication]string)
+
+	for _, m := range []map[string]string{defaults, colors} {
+		for k, v := range m {
+			switch ind := api.Indication(strings.ToLower(k)); ind {
+			case api.IndicationIdle, api.IndicationWaiting, api.IndicationPV, api.IndicationCharging, api.IndicationError:
+				res[ind] = v
+			default:
+				return nil, fmt.Errorf("invalid indication: %s", k)
+			}
+		}
+	}
+
+	return res, nil
+}
+
 // ensureCharger extracts VIN from list of VINs returned from `list` function
 func ensureCharger(vin string, list func() ([]string, error)) (string, error) {
 	vin, _, err := ensureChargerWithFeature(vin, list, func(v string) (string, string) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -37,15 +38,19 @@ func init() {
 	registry.Add("keba", NewKebaFromConfig)
 }
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateKeba -b *Keba -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateKeba -b *Keba -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.MeterVoltage,Voltages,func() (float64, float64, float64, error)" -t "api.ChargeRater,ChargedEnergy,func() (float64, error)"
 
 // NewKebaFromConfig creates a new configurable charger
 func NewKebaFromConfig(other map[string]interface{}) (api.Charger, error) {
 	cc := struct {
-		URI     string
-		Serial  string
-		Timeout time.Duration
-		RFID    RFID
+		URI      string
+		Serial   string
+		Timeout  time.Duration
+		RFID     RFID
+		Failsafe struct {
+			Timeout time.Duration // 0 keeps the charger's setting
+			Current float64       // 0 stops charging
+		}
 	}{
 		Timeout: udpTimeout,
 	}
@@ -59,13 +64,19 @@ func NewKebaFromConfig(other map[string]interface{}) (api.Charger, error) {
 		return nil, err
 	}
 
+	if cc.Failsafe.Timeout > 0 {
+		if err := k.failsafe(cc.Failsafe.Timeout, cc.Failsafe.Current); err != nil {
+			return nil, err
+		}
+	}
+
 	energy, err := k.totalEnergy()
 	if err != nil {
 		return nil, err
 	}
 
 	if energy > 0 {
-		return decorateKeba(k, k.currentPower, k.totalEnergy, k.currents), nil
+		return decorateKeba(k, k.currentPower, k.totalEnergy, k.currents, k.voltages, k.chargedEnergy), nil
 	}
 
 	return k, err
@@ -101,11 +112,34 @@ func NewKeba(uri, serial string, rfid RFID, timeout time.Duration) (*Keba, error
 		serial = conn
 	}
 
-	keba.Instance.Subscribe(serial, c.recv)
+	if err == nil {
+		err = keba.Instance.Subscribe(serial, c.recv)
+	}
 
 	return c, err
 }
 
+// failsafe configures the current the charger falls back to if not updated within timeout
+func (c *Keba) failsafe(timeout time.Duration, current float64) error {
+	if timeout < 10*time.Second || timeout > 600*time.Second {
+		return fmt.Errorf("invalid failsafe timeout: %v, must be 10s..10m", timeout)
+	}
+
+	if current != 0 && (current < 6 || current > 63) {
+		return fmt.Errorf("invalid failsafe current: %.3gA, must be 0 or 6..63A", current)
+	}
+
+	// don't persist to avoid wearing the charger's flash
+	msg := fmt.Sprintf("failsafe %d %d 0", int(timeout.Seconds()), int(1000*current))
+
+	var resp string
+	if err := c.roundtrip(msg, 0, &resp); err != nil {
+		return fmt.Errorf("could not set failsafe: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Keba) receive(report int, resC chan<- keba.UDPMsg, errC chan<- error, closeC <-chan struct{}) {
 	t := time.NewTimer(c.timeout)
 	defer close(resC)
@@ -251,6 +285,21 @@ func (c *Keba) Enable(enable bool) error {
 	return nil
 }
 
+var _ api.CableUnlocker = (*Keba)(nil)
+
+// Unlock implements the api.CableUnlocker interface
+func (c *Keba) Unlock() error {
+	var resp string
+	if err := c.roundtrip("unlock", 0, &resp); err != nil {
+		return err
+	}
+	if resp != keba.OK {
+		return fmt.Errorf("unlock unexpected response: %s", resp)
+	}
+
+	return nil
+}
+
 // MaxCurrent implements the api.Charger interface
 func (c *Keba) MaxCurrent(current int64) error {
 	d := 1000 * current
@@ -294,7 +343,7 @@ func (c *Keba) totalEnergy() (float64, error) {
 	var kr keba.Report3
 	err := c.roundtrip("report", 3, &kr)
 
-	// mW to W
+	// 0.1Wh to kWh
 	return float64(kr.ETotal) / 1e4, err
 }
 
@@ -307,12 +356,35 @@ func (c *Keba) currents() (float64, float64, float64, error) {
 	return float64(kr.I1) / 1e3, float64(kr.I2) / 1e3, float64(kr.I3) / 1e3, err
 }
 
+// voltages implements the api.MeterVoltage interface
+func (c *Keba) voltages() (float64, float64, float64, error) {
+	var kr keba.Report3
+	err := c.roundtrip("report", 3, &kr)
+
+	return float64(kr.U1), float64(kr.U2), float64(kr.U3), err
+}
+
+// chargedEnergy implements the api.ChargeRater interface
+func (c *Keba) chargedEnergy() (float64, error) {
+	var kr keba.Report3
+	err := c.roundtrip("report", 3, &kr)
+
+	// 0.1Wh to kWh
+	return float64(kr.EPres) / 1e4, err
+}
+
 var _ api.Identifier = (*Keba)(nil)
 
 // Identify implements the api.Identifier interface
 func (c *Keba) Identify() (string, error) {
 	var kr keba.Report100
 	err := c.roundtrip("report", 100, &kr)
+
+	// unused tag is reported as zeros
+	if strings.Trim(kr.RFIDTag, "0") == "" {
+		return "", err
+	}
+
 	return kr.RFIDTag, err
 }
 
@@ -325,3 +397,32 @@ func (c *Keba) Diagnose() {
 		fmt.Printf("%+v\n", kr)
 	}
 }
+
+var _ api.DiagnosticReporter = (*Keba)(nil)
+
+// Diagnostics implements the api.DiagnosticReporter interface
+func (c *Keba) Diagnostics() (api.Diagnostics, error) {
+	var r1 keba.Report1
+	if err := c.roundtrip("report", 1, &r1); err != nil {
+		return nil, err
+	}
+
+	var r2 keba.Report2
+	if err := c.roundtrip("report", 2, &r2); err != nil {
+		return nil, err
+	}
+
+	return api.Diagnostics{
+		"product":  r1.Product,
+		"serial":   r1.Serial,
+		"firmware": r1.Firmware,
+		"uptime":   (time.Duration(r1.Sec) * time.Second).String(),
+		"state":    r2.State,
+		"plug":     r2.Plug,
+		"error1":   r2.Error1,
+		"error2":   r2.Error2,
+		"currHW":   r2.CurrHW,
+		"currFS":   r2.CurrFS,
+		"tmoFS":    r2.TmoFS,
+	}, nil
+}
@@ -67,12 +67,19 @@ func New(log *util.Logger) (*Listener, error) {
 	return l, nil
 }
 
-// Subscribe adds a client address or serial and message channel to the list of subscribers
-func (l *Listener) Subscribe(addr string, c chan<- UDPMsg) {
+// Subscribe adds a client address or serial and message channel to the list of subscribers.
+// Multiple chargers sharing the sender address (e.g. behind docker NAT) must subscribe by serial.
+func (l *Listener) Subscribe(addr string, c chan<- UDPMsg) error {
 	l.mux.Lock()
 	defer l.mux.Unlock()
 
+	if _, ok := l.clients[addr]; ok {
+		return fmt.Errorf("duplicate subscription: %s, configure the serial of each charger", addr)
+	}
+
 	l.clients[addr] = c
+
+	return nil
 }
 
 func (l *Listener) listen() {
@@ -120,7 +127,8 @@ func (l *Listener) addrMatches(addr string, msg UDPMsg) bool {
 		return true
 
 	// simple response like TCH :OK where cached serial for sender address matches
-	case l.cache[addr] == msg.Addr:
+	// reports are only routed by serial to keep chargers sharing an address apart
+	case msg.Report == nil && l.cache[addr] == msg.Addr:
 		return true
 
 	// report response with matching serial
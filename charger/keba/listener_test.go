@@ -0,0 +1,36 @@
+package keba
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerSerialMultiplexing(t *testing.T) {
+	l := &Listener{
+		log:     util.NewLogger("foo"),
+		clients: make(map[string]chan<- UDPMsg),
+		cache:   make(map[string]string),
+	}
+
+	a := make(chan UDPMsg, 1)
+	b := make(chan UDPMsg, 1)
+	require.NoError(t, l.Subscribe("A", a))
+	require.NoError(t, l.Subscribe("B", b))
+	require.Error(t, l.Subscribe("B", b))
+
+	// both chargers share the sender address
+	const addr = "172.17.0.1:7090"
+
+	l.send(UDPMsg{Addr: addr, Report: &Report{ID: 2, Serial: "A"}})
+	l.send(UDPMsg{Addr: addr, Report: &Report{ID: 2, Serial: "B"}})
+	assert.Equal(t, "A", (<-a).Report.Serial)
+	assert.Equal(t, "B", (<-b).Report.Serial)
+
+	// report of A must not be routed to B by cached address
+	l.send(UDPMsg{Addr: addr, Report: &Report{ID: 3, Serial: "A"}})
+	assert.Len(t, b, 0)
+	assert.Equal(t, 3, (<-a).Report.ID)
+}
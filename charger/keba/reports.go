@@ -39,6 +39,8 @@ type Report2 struct {
 	SetEnergy      int    `json:"Setenergy"`
 	Output         int    `json:"Output"`
 	Input          int    `json:"Input"`
+	X2Source       int    `json:"X2 phaseSwitch source"`
+	X2             int    `json:"X2 phaseSwitch"`
 	Sec            int64  `json:"Sec"`
 }
 
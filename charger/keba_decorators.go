@@ -6,40 +6,313 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateKeba(base *Keba, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error)) api.Charger {
-	switch {
-	case meter == nil && meterCurrent == nil && meterEnergy == nil:
+func decorateKeba(base *Keba, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), meterVoltage func() (float64, float64, float64, error), chargeRater func() (float64, error)) api.Charger {
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+	if meterVoltage != nil {
+		caps |= 8
+	}
+	if chargeRater != nil {
+		caps |= 16
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 1: // api.Meter
+		return &struct {
+			*Keba
+			api.Meter
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case 2: // api.MeterEnergy
+		return &struct {
+			*Keba
+			api.MeterEnergy
+		}{
+			Keba: base,
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 3: // api.Meter, api.MeterEnergy
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterEnergy
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 4: // api.MeterCurrent
+		return &struct {
+			*Keba
+			api.MeterCurrent
+		}{
+			Keba: base,
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 5: // api.Meter, api.MeterCurrent
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterCurrent
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 6: // api.MeterEnergy, api.MeterCurrent
+		return &struct {
+			*Keba
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Keba: base,
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 8: // api.MeterVoltage
+		return &struct {
+			*Keba
+			api.MeterVoltage
+		}{
+			Keba: base,
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 9: // api.Meter, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterVoltage
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 10: // api.MeterEnergy, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 11: // api.Meter, api.MeterEnergy, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 12: // api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			Keba: base,
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 13: // api.Meter, api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 14: // api.MeterEnergy, api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 15: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*Keba
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 16: // api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+		}
+
+	case 17: // api.Meter, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.Meter
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			Meter: &decorateKebaMeterImpl{
 				meter: meter,
 			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 18: // api.MeterEnergy, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.MeterEnergy
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			MeterEnergy: &decorateKebaMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 19: // api.Meter, api.MeterEnergy, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.Meter
 			api.MeterEnergy
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			Meter: &decorateKebaMeterImpl{
 				meter: meter,
 			},
@@ -48,24 +321,32 @@ func decorateKeba(base *Keba, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 20: // api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.MeterCurrent
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			MeterCurrent: &decorateKebaMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 21: // api.Meter, api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.Meter
 			api.MeterCurrent
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			Meter: &decorateKebaMeterImpl{
 				meter: meter,
 			},
@@ -74,13 +355,17 @@ func decorateKeba(base *Keba, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 22: // api.MeterEnergy, api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.MeterCurrent
 			api.MeterEnergy
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			MeterCurrent: &decorateKebaMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
@@ -89,14 +374,18 @@ func decorateKeba(base *Keba, meter func() (float64, error), meterEnergy func()
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 23: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.ChargeRater
 		return &struct {
 			*Keba
+			api.ChargeRater
 			api.Meter
 			api.MeterCurrent
 			api.MeterEnergy
 		}{
 			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
 			Meter: &decorateKebaMeterImpl{
 				meter: meter,
 			},
@@ -107,11 +396,187 @@ func decorateKeba(base *Keba, meter func() (float64, error), meterEnergy func()
 				meterEnergy: meterEnergy,
 			},
 		}
+
+	case 24: // api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 25: // api.Meter, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.Meter
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 26: // api.MeterEnergy, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 27: // api.Meter, api.MeterEnergy, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.Meter
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 28: // api.MeterCurrent, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 29: // api.Meter, api.MeterCurrent, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.Meter
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 30: // api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 31: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.ChargeRater
+		return &struct {
+			*Keba
+			api.ChargeRater
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			Keba: base,
+			ChargeRater: &decorateKebaChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateKebaMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateKebaMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateKebaMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateKebaMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
 	}
 
 	return nil
 }
 
+type decorateKebaChargeRaterImpl struct {
+	chargeRater func() (float64, error)
+}
+
+func (impl *decorateKebaChargeRaterImpl) ChargedEnergy() (float64, error) {
+	return impl.chargeRater()
+}
+
 type decorateKebaMeterImpl struct {
 	meter func() (float64, error)
 }
@@ -135,3 +600,11 @@ type decorateKebaMeterEnergyImpl struct {
 func (impl *decorateKebaMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
+
+type decorateKebaMeterVoltageImpl struct {
+	meterVoltage func() (float64, float64, float64, error)
+}
+
+func (impl *decorateKebaMeterVoltageImpl) Voltages() (float64, float64, float64, error) {
+	return impl.meterVoltage()
+}
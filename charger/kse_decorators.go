@@ -7,11 +7,16 @@ import (
 )
 
 func decorateKSE(base *KSE, identifier func() (string, error)) api.Charger {
-	switch {
-	case identifier == nil:
+	var caps int
+	if identifier != nil {
+		caps |= 1
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case identifier != nil:
+	case 1: // api.Identifier
 		return &struct {
 			*KSE
 			api.Identifier
@@ -89,15 +89,25 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 		currentsG = c.currents
 	}
 
+	var voltagesG func() (float64, float64, float64, error)
+	if c.hasMeasurement(types.MeasurandVoltage) {
+		voltagesG = c.voltages
+	}
+
+	var socG func() (float64, error)
+	if c.hasMeasurement(types.MeasueandSoC) {
+		socG = c.soc
+	}
+
 	var phasesS func(int) error
 	if c.phaseSwitching {
 		phasesS = c.phases1p3p
 	}
 
-	return decorateOCPP(c, powerG, totalEnergyG, currentsG, phasesS), nil
+	return decorateOCPP(c, powerG, totalEnergyG, currentsG, voltagesG, socG, phasesS), nil
 }
 
-// go:generate go run ../cmd/tools/decorate.go -f decorateOCPP -b *OCPP -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) (error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateOCPP -b *OCPP -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.MeterVoltage,Voltages,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) (error)"
 
 // NewOCPP creates OCPP charger
 func NewOCPP(id string, connector int, idtag string, meterValues string, meterInterval time.Duration, quirks bool, timeout time.Duration) (*OCPP, error) {
@@ -416,11 +426,66 @@ func (c *OCPP) totalEnergy() (float64, error) {
 	return c.cp.TotalEnergy()
 }
 
+// soc implements the api.Battery interface
+func (c *OCPP) soc() (float64, error) {
+	return c.cp.SoC()
+}
+
 // Currents implements the api.MeterCurrent interface
 func (c *OCPP) currents() (float64, float64, float64, error) {
 	return c.cp.Currents()
 }
 
+// Voltages implements the api.MeterVoltage interface
+func (c *OCPP) voltages() (float64, float64, float64, error) {
+	return c.cp.Voltages()
+}
+
+var _ api.FaultReporter = (*OCPP)(nil)
+
+// Fault implements the api.FaultReporter interface
+func (c *OCPP) Fault() (string, error) {
+	return c.cp.Fault()
+}
+
+var _ api.Commander = (*OCPP)(nil)
+
+// Commands implements the api.Commander interface
+func (c *OCPP) Commands() []api.Command {
+	return []api.Command{api.CommandReboot, api.CommandReauthorize}
+}
+
+// Command implements the api.Commander interface
+func (c *OCPP) Command(cmd api.Command) error {
+	switch cmd {
+	case api.CommandReboot:
+		rc := make(chan error, 1)
+
+		err := ocpp.Instance().Reset(c.cp.ID(), func(resp *core.ResetConfirmation, err error) {
+			c.log.TRACE.Printf("%T: %+v", resp, resp)
+
+			if err == nil && resp != nil && resp.Status != core.ResetStatusAccepted {
+				err = errors.New(string(resp.Status))
+			}
+
+			rc <- err
+		}, core.ResetTypeSoft)
+
+		return c.wait(err, rc)
+
+	case api.CommandReauthorize:
+		if c.cp.TransactionID() > 0 {
+			return errors.New("transaction already running")
+		}
+
+		// start transaction for the configured id tag
+		return c.Enable(true)
+
+	default:
+		return api.ErrNotAvailable
+	}
+}
+
 // Phases1p3p implements the api.PhaseSwitcher interface
 func (c *OCPP) phases1p3p(phases int) error {
 	c.phases = phases
@@ -209,6 +209,25 @@ func (cp *CP) TotalEnergy() (float64, error) {
 	return 0, api.ErrNotAvailable
 }
 
+var _ api.Battery = (*CP)(nil)
+
+// SoC returns the vehicle soc reported by ISO 15118 capable chargepoints
+func (cp *CP) SoC() (float64, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.timeout > 0 && time.Since(cp.meterUpdated) > cp.timeout {
+		return 0, api.ErrNotAvailable
+	}
+
+	// measurand name is misspelled upstream
+	if m, ok := cp.measurements[string(types.MeasueandSoC)]; ok {
+		return strconv.ParseFloat(m.Value, 64)
+	}
+
+	return 0, api.ErrNotAvailable
+}
+
 func scale(f float64, scale types.UnitOfMeasure) float64 {
 	switch {
 	case strings.HasPrefix(string(scale), "k"):
@@ -252,3 +271,65 @@ func (cp *CP) Currents() (float64, float64, float64, error) {
 
 	return currents[0], currents[1], currents[2], nil
 }
+
+var _ api.MeterVoltage = (*CP)(nil)
+
+func (cp *CP) Voltages() (float64, float64, float64, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.timeout > 0 && time.Since(cp.meterUpdated) > cp.timeout {
+		return 0, 0, 0, api.ErrNotAvailable
+	}
+
+	voltages := make([]float64, 0, 3)
+
+	for phase := 1; phase <= 3; phase++ {
+		// prefer line to neutral voltage
+		m, ok := cp.measurements[string(types.MeasurandVoltage)+"@L"+strconv.Itoa(phase)+"-N"]
+		if !ok {
+			if m, ok = cp.measurements[string(types.MeasurandVoltage)+"@L"+strconv.Itoa(phase)]; !ok {
+				return 0, 0, 0, api.ErrNotAvailable
+			}
+		}
+
+		f, err := strconv.ParseFloat(m.Value, 64)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid voltage for phase %d: %w", phase, err)
+		}
+
+		voltages = append(voltages, scale(f, m.Unit))
+	}
+
+	return voltages[0], voltages[1], voltages[2], nil
+}
+
+var _ api.FaultReporter = (*CP)(nil)
+
+// Fault maps the status notification error code to the charger fault
+func (cp *CP) Fault() (string, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.status == nil {
+		return api.FaultNone, api.ErrNotAvailable
+	}
+
+	switch cp.status.ErrorCode {
+	case core.GroundFailure:
+		return api.FaultGround, nil
+	case core.HighTemperature:
+		return api.FaultTemperature, nil
+	case core.UnderVoltage:
+		return api.FaultUndervoltage, nil
+	case core.InternalError, core.OverCurrentFailure, core.OverVoltage, core.PowerSwitchFailure:
+		return api.FaultInternal, nil
+	}
+
+	// other error codes are informational unless the connector is faulted
+	if cp.status.Status == core.ChargePointStatusFaulted {
+		return api.FaultInternal, nil
+	}
+
+	return api.FaultNone, nil
+}
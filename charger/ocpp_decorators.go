@@ -6,97 +6,1026 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), phaseSwitcher func(int) error) api.Charger {
-	switch {
-	case meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), meterVoltage func() (float64, float64, float64, error), battery func() (float64, error), phaseSwitcher func(phases int) error) api.Charger {
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+	if meterVoltage != nil {
+		caps |= 8
+	}
+	if battery != nil {
+		caps |= 16
+	}
+	if phaseSwitcher != nil {
+		caps |= 32
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher == nil:
+	case 1: // api.Meter
+		return &struct {
+			*OCPP
+			api.Meter
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case 2: // api.MeterEnergy
+		return &struct {
+			*OCPP
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 3: // api.Meter, api.MeterEnergy
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 4: // api.MeterCurrent
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 5: // api.Meter, api.MeterCurrent
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 6: // api.MeterEnergy, api.MeterCurrent
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 8: // api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 9: // api.Meter, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 10: // api.MeterEnergy, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 11: // api.Meter, api.MeterEnergy, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 12: // api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 13: // api.Meter, api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 14: // api.MeterEnergy, api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 15: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.MeterVoltage
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 16: // api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+		}
+
+	case 17: // api.Meter, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case 18: // api.MeterEnergy, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 19: // api.Meter, api.MeterEnergy, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 20: // api.MeterCurrent, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterCurrent
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 21: // api.Meter, api.MeterCurrent, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterCurrent
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 22: // api.MeterEnergy, api.MeterCurrent, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 23: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 24: // api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 25: // api.Meter, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 26: // api.MeterEnergy, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 27: // api.Meter, api.MeterEnergy, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 28: // api.MeterCurrent, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 29: // api.Meter, api.MeterCurrent, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterCurrent
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 30: // api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 31: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.Battery
+		return &struct {
+			*OCPP
+			api.Battery
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+		}
+
+	case 32: // api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 33: // api.Meter, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 34: // api.MeterEnergy, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 35: // api.Meter, api.MeterEnergy, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 36: // api.MeterCurrent, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 37: // api.Meter, api.MeterCurrent, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 38: // api.MeterEnergy, api.MeterCurrent, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 39: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 40: // api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 41: // api.Meter, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 42: // api.MeterEnergy, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterEnergy
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 43: // api.Meter, api.MeterEnergy, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterEnergy
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 44: // api.MeterCurrent, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 45: // api.Meter, api.MeterCurrent, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 46: // api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 47: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+			api.MeterVoltage
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Meter: &decorateOCPPMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOCPPMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateOCPPMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 48: // api.Battery, api.PhaseSwitcher
+		return &struct {
+			*OCPP
+			api.Battery
+			api.PhaseSwitcher
+		}{
+			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 49: // api.Meter, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+	case 50: // api.MeterEnergy, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.MeterEnergy
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher == nil:
+	case 51: // api.Meter, api.MeterEnergy, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
 			api.MeterEnergy
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+	case 52: // api.MeterCurrent, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.MeterCurrent
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			MeterCurrent: &decorateOCPPMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher == nil:
+	case 53: // api.Meter, api.MeterCurrent, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
 			api.MeterCurrent
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
 			MeterCurrent: &decorateOCPPMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+	case 54: // api.MeterEnergy, api.MeterCurrent, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.MeterCurrent
 			api.MeterEnergy
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			MeterCurrent: &decorateOCPPMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher == nil:
+	case 55: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
 			api.MeterCurrent
 			api.MeterEnergy
+			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
@@ -106,130 +1035,194 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+	case 56: // api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy == nil && phaseSwitcher != nil:
+	case 57: // api.Meter, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+	case 58: // api.MeterEnergy, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.MeterEnergy
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy != nil && phaseSwitcher != nil:
+	case 59: // api.Meter, api.MeterEnergy, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
 			api.MeterEnergy
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+	case 60: // api.MeterCurrent, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.MeterCurrent
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			MeterCurrent: &decorateOCPPMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy == nil && phaseSwitcher != nil:
+	case 61: // api.Meter, api.MeterCurrent, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
 			api.MeterCurrent
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
 			MeterCurrent: &decorateOCPPMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+	case 62: // api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.MeterCurrent
 			api.MeterEnergy
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			MeterCurrent: &decorateOCPPMeterCurrentImpl{
 				meterCurrent: meterCurrent,
 			},
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy != nil && phaseSwitcher != nil:
+	case 63: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.MeterVoltage, api.Battery, api.PhaseSwitcher
 		return &struct {
 			*OCPP
+			api.Battery
 			api.Meter
 			api.MeterCurrent
 			api.MeterEnergy
+			api.MeterVoltage
 			api.PhaseSwitcher
 		}{
 			OCPP: base,
+			Battery: &decorateOCPPBatteryImpl{
+				battery: battery,
+			},
 			Meter: &decorateOCPPMeterImpl{
 				meter: meter,
 			},
@@ -239,6 +1232,9 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 			MeterEnergy: &decorateOCPPMeterEnergyImpl{
 				meterEnergy: meterEnergy,
 			},
+			MeterVoltage: &decorateOCPPMeterVoltageImpl{
+				meterVoltage: meterVoltage,
+			},
 			PhaseSwitcher: &decorateOCPPPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
@@ -248,6 +1244,14 @@ func decorateOCPP(base *OCPP, meter func() (float64, error), meterEnergy func()
 	return nil
 }
 
+type decorateOCPPBatteryImpl struct {
+	battery func() (float64, error)
+}
+
+func (impl *decorateOCPPBatteryImpl) SoC() (float64, error) {
+	return impl.battery()
+}
+
 type decorateOCPPMeterImpl struct {
 	meter func() (float64, error)
 }
@@ -272,8 +1276,16 @@ func (impl *decorateOCPPMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
 
+type decorateOCPPMeterVoltageImpl struct {
+	meterVoltage func() (float64, float64, float64, error)
+}
+
+func (impl *decorateOCPPMeterVoltageImpl) Voltages() (float64, float64, float64, error) {
+	return impl.meterVoltage()
+}
+
 type decorateOCPPPhaseSwitcherImpl struct {
-	phaseSwitcher func(int) error
+	phaseSwitcher func(phases int) error
 }
 
 func (impl *decorateOCPPPhaseSwitcherImpl) Phases1p3p(phases int) error {
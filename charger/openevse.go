@@ -21,23 +21,43 @@ type OpenEVSE struct {
 	api     *openevse.ClientWithResponses
 	helper  *request.Helper
 	timeout time.Duration
+	phases  int
+	colors  map[api.Indication]string
+}
+
+// openevseColors are the default lcd backlight colors when the indicator is enabled
+var openevseColors = map[string]string{
+	"waiting":  "yellow",
+	"pv":       "green",
+	"charging": "teal",
+	"error":    "red",
+}
+
+// openevseBacklight are the RAPI lcd backlight color codes
+var openevseBacklight = map[string]int{
+	"off": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "violet": 5, "teal": 6, "white": 7,
 }
 
 func init() {
 	registry.Add("openevse", NewOpenEVSEFromConfig)
 }
 
-// go:generate go run ../cmd/tools/decorate.go -f decorateOpenEVSE -b ""*OpenEVSE" -r api.Charger -t "api.PhaseSwitcher,Phases1p3p,func(int) error"
+//go:generate go run ../cmd/tools/decorate.go -f decorateOpenEVSE -b *OpenEVSE -r api.Charger -t "api.PhaseSwitcher,Phases1p3p,func(int) error" -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)"
 
 // NewOpenEVSEFromConfig creates a go-e charger from generic config
 func NewOpenEVSEFromConfig(other map[string]interface{}) (api.Charger, error) {
 	cc := struct {
-		URI      string
-		User     string
-		Password string
-		Timeout  time.Duration
+		URI       string
+		User      string
+		Password  string
+		Timeout   time.Duration
+		Phases    int               // connected phases unless switched by evcc
+		Divert    bool              // keep the charger's own solar divert enabled
+		Indicator bool              // reflect loadpoint state on the lcd backlight
+		Colors    map[string]string // backlight colors per indication
 	}{
 		Timeout: request.Timeout,
+		Phases:  1,
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -48,16 +68,32 @@ func NewOpenEVSEFromConfig(other map[string]interface{}) (api.Charger, error) {
 		return nil, errors.New("missing uri")
 	}
 
-	return NewOpenEVSE(cc.URI, cc.User, cc.Password, cc.Timeout)
+	var colors map[api.Indication]string
+	if cc.Indicator {
+		var err error
+		if colors, err = indicatorColors(openevseColors, cc.Colors); err != nil {
+			return nil, err
+		}
+
+		for _, color := range colors {
+			if _, ok := openevseBacklight[strings.ToLower(color)]; !ok {
+				return nil, fmt.Errorf("invalid color: %s", color)
+			}
+		}
+	}
+
+	return NewOpenEVSE(cc.URI, cc.User, cc.Password, cc.Timeout, cc.Phases, cc.Divert, colors)
 }
 
 // NewOpenEVSE creates OpenEVSE charger
-func NewOpenEVSE(uri, user, password string, timeout time.Duration) (api.Charger, error) {
+func NewOpenEVSE(uri, user, password string, timeout time.Duration, phases int, divert bool, colors map[api.Indication]string) (api.Charger, error) {
 	log := util.NewLogger("openevse").Redact(user, password)
 	c := &OpenEVSE{
 		helper:  request.NewHelper(log),
 		uri:     uri,
 		timeout: timeout,
+		phases:  phases,
+		colors:  colors,
 	}
 
 	options := []openevse.ClientOption{openevse.WithHTTPClient(c.helper.Client)}
@@ -87,7 +123,51 @@ func NewOpenEVSE(uri, user, password string, timeout time.Duration) (api.Charger
 		}
 	}
 
-	return decorateOpenEVSE(c, phases1p3p), err
+	// solar divert would compete with evcc's pv control
+	if !divert {
+		if err := c.disableDivert(); err != nil {
+			return c, err
+		}
+	}
+
+	// charge current and voltage are not reported by all firmware versions
+	var currentPower func() (float64, error)
+	var currents func() (float64, float64, float64, error)
+	if res, err := c.status(); err == nil && res.JSON200.Amp != nil && res.JSON200.Voltage != nil {
+		currentPower = c.currentPower
+		currents = c.currents
+	}
+
+	return decorateOpenEVSE(c, phases1p3p, currentPower, currents), err
+}
+
+// status returns the charger status
+func (c *OpenEVSE) status() (*openevse.GetStatusResponse, error) {
+	ctx, cancel := c.requestContextWithTimeout()
+	defer cancel()
+
+	res, err := c.api.GetStatusWithResponse(ctx)
+	if err == nil && res.JSON200 == nil {
+		err = fmt.Errorf("invalid status: %s", res.Status())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// disableDivert disables the charger's own solar divert
+func (c *OpenEVSE) disableDivert() error {
+	ctx, cancel := c.requestContextWithTimeout()
+	defer cancel()
+
+	disabled := false
+	_, err := c.api.UpdateConfigWithResponse(ctx, openevse.UpdateConfigJSONRequestBody{
+		DivertEnabled: &disabled,
+	})
+
+	return err
 }
 
 func (c *OpenEVSE) requestContextWithTimeout() (context.Context, context.CancelFunc) {
@@ -114,6 +194,22 @@ func (c *OpenEVSE) rapiCommand(command string) error {
 	return err
 }
 
+var _ api.Indicator = (*OpenEVSE)(nil)
+
+// Indicate implements the api.Indicator interface
+func (c *OpenEVSE) Indicate(indication api.Indication) error {
+	if c.colors == nil {
+		return api.ErrNotAvailable
+	}
+
+	color, ok := c.colors[indication]
+	if !ok {
+		return nil
+	}
+
+	return c.rapiCommand(fmt.Sprintf("$FB %d", openevseBacklight[strings.ToLower(color)]))
+}
+
 func (c *OpenEVSE) SetManualOverride(enable bool) error {
 	state := "disabled"
 	if enable {
@@ -220,6 +316,22 @@ func (c *OpenEVSE) MaxCurrent(current int64) error {
 	return err
 }
 
+var _ api.CurrentGetter = (*OpenEVSE)(nil)
+
+// GetMaxCurrent implements the api.CurrentGetter interface
+func (c *OpenEVSE) GetMaxCurrent() (float64, error) {
+	res, err := c.status()
+	if err != nil {
+		return 0, err
+	}
+
+	if res.JSON200.Pilot == nil {
+		return 0, api.ErrNotAvailable
+	}
+
+	return float64(*res.JSON200.Pilot), nil
+}
+
 var _ api.ChargeRater = (*OpenEVSE)(nil)
 
 // ChargedEnergy implements the api.ChargeRater interface
@@ -257,5 +369,118 @@ func (c *OpenEVSE) phases1p3p(phases int) error {
 		set3p = 1
 	}
 
-	return c.rapiCommand(fmt.Sprintf("$S7 %d", set3p))
+	err := c.rapiCommand(fmt.Sprintf("$S7 %d", set3p))
+	if err == nil {
+		c.phases = phases
+	}
+
+	return err
+}
+
+// currentPower implements the api.Meter interface
+func (c *OpenEVSE) currentPower() (float64, error) {
+	res, err := c.status()
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(*res.JSON200.Amp) / 1e3 * float64(*res.JSON200.Voltage) * float64(c.phases), nil
+}
+
+// currents implements the api.MeterCurrent interface
+func (c *OpenEVSE) currents() (float64, float64, float64, error) {
+	res, err := c.status()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	i := float64(*res.JSON200.Amp) / 1e3
+	if c.phases == 3 {
+		return i, i, i, nil
+	}
+
+	return i, 0, 0, nil
+}
+
+var _ api.Resurrector = (*OpenEVSE)(nil)
+
+// WakeUp implements the api.Resurrector interface by cycling the EVSE through sleep
+func (c *OpenEVSE) WakeUp() error {
+	if err := c.rapiCommand("$FS"); err != nil {
+		return err
+	}
+
+	return c.rapiCommand("$FE")
+}
+
+var _ api.Commander = (*OpenEVSE)(nil)
+
+// Commands implements the api.Commander interface
+func (c *OpenEVSE) Commands() []api.Command {
+	return []api.Command{api.CommandReboot}
+}
+
+// Command implements the api.Commander interface
+func (c *OpenEVSE) Command(cmd api.Command) error {
+	if cmd != api.CommandReboot {
+		return api.ErrNotAvailable
+	}
+
+	return c.rapiCommand("$FR")
+}
+
+var _ api.Diagnosis = (*OpenEVSE)(nil)
+
+// Diagnose implements the api.Diagnosis interface
+func (c *OpenEVSE) Diagnose() {
+	res, err := c.status()
+	if err != nil {
+		fmt.Printf("status: %v\n", err)
+		return
+	}
+
+	if res.JSON200.Temp != nil {
+		fmt.Printf("Temperature:\t%.1f°C\n", float64(*res.JSON200.Temp)/10)
+	}
+	if res.JSON200.Pilot != nil {
+		fmt.Printf("Pilot:\t%dA\n", *res.JSON200.Pilot)
+	}
+	if res.JSON200.ServiceLevel != nil {
+		fmt.Printf("Service level:\tL%d\n", *res.JSON200.ServiceLevel)
+	}
+	if res.JSON200.Divertmode != nil {
+		fmt.Printf("Divert mode:\t%d (active: %v)\n", *res.JSON200.Divertmode, res.JSON200.DivertActive != nil && *res.JSON200.DivertActive)
+	}
+	if res.JSON200.ManualOverride != nil {
+		fmt.Printf("Manual override:\t%d\n", *res.JSON200.ManualOverride)
+	}
+}
+
+var _ api.DiagnosticReporter = (*OpenEVSE)(nil)
+
+// Diagnostics implements the api.DiagnosticReporter interface
+func (c *OpenEVSE) Diagnostics() (api.Diagnostics, error) {
+	res, err := c.status()
+	if err != nil {
+		return nil, err
+	}
+
+	d := make(api.Diagnostics)
+	if res.JSON200.Temp != nil {
+		d["temperature"] = float64(*res.JSON200.Temp) / 10
+	}
+	if res.JSON200.Pilot != nil {
+		d["pilot"] = *res.JSON200.Pilot
+	}
+	if res.JSON200.ServiceLevel != nil {
+		d["serviceLevel"] = *res.JSON200.ServiceLevel
+	}
+	if res.JSON200.Divertmode != nil {
+		d["divertMode"] = *res.JSON200.Divertmode
+	}
+	if res.JSON200.ManualOverride != nil {
+		d["manualOverride"] = *res.JSON200.ManualOverride
+	}
+
+	return d, nil
 }
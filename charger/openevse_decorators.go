@@ -6,17 +6,114 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateOpenEVSE(base *OpenEVSE, phaseSwitcher func(phases int) error) api.Charger {
-	switch {
-	case phaseSwitcher == nil:
+func decorateOpenEVSE(base *OpenEVSE, phaseSwitcher func(phases int) error, meter func() (float64, error), meterCurrent func() (float64, float64, float64, error)) api.Charger {
+	var caps int
+	if phaseSwitcher != nil {
+		caps |= 1
+	}
+	if meter != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case phaseSwitcher != nil:
+	case 1: // api.PhaseSwitcher
+		return &struct {
+			*OpenEVSE
+			api.PhaseSwitcher
+		}{
+			OpenEVSE: base,
+			PhaseSwitcher: &decorateOpenEVSEPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 2: // api.Meter
+		return &struct {
+			*OpenEVSE
+			api.Meter
+		}{
+			OpenEVSE: base,
+			Meter: &decorateOpenEVSEMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case 3: // api.PhaseSwitcher, api.Meter
+		return &struct {
+			*OpenEVSE
+			api.Meter
+			api.PhaseSwitcher
+		}{
+			OpenEVSE: base,
+			Meter: &decorateOpenEVSEMeterImpl{
+				meter: meter,
+			},
+			PhaseSwitcher: &decorateOpenEVSEPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 4: // api.MeterCurrent
+		return &struct {
+			*OpenEVSE
+			api.MeterCurrent
+		}{
+			OpenEVSE: base,
+			MeterCurrent: &decorateOpenEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 5: // api.PhaseSwitcher, api.MeterCurrent
+		return &struct {
+			*OpenEVSE
+			api.MeterCurrent
+			api.PhaseSwitcher
+		}{
+			OpenEVSE: base,
+			MeterCurrent: &decorateOpenEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			PhaseSwitcher: &decorateOpenEVSEPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+		}
+
+	case 6: // api.Meter, api.MeterCurrent
+		return &struct {
+			*OpenEVSE
+			api.Meter
+			api.MeterCurrent
+		}{
+			OpenEVSE: base,
+			Meter: &decorateOpenEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOpenEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 7: // api.PhaseSwitcher, api.Meter, api.MeterCurrent
 		return &struct {
 			*OpenEVSE
+			api.Meter
+			api.MeterCurrent
 			api.PhaseSwitcher
 		}{
 			OpenEVSE: base,
+			Meter: &decorateOpenEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateOpenEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
 			PhaseSwitcher: &decorateOpenEVSEPhaseSwitcherImpl{
 				phaseSwitcher: phaseSwitcher,
 			},
@@ -26,8 +123,24 @@ func decorateOpenEVSE(base *OpenEVSE, phaseSwitcher func(phases int) error) api.
 	return nil
 }
 
+type decorateOpenEVSEMeterImpl struct {
+	meter func() (float64, error)
+}
+
+func (impl *decorateOpenEVSEMeterImpl) CurrentPower() (float64, error) {
+	return impl.meter()
+}
+
+type decorateOpenEVSEMeterCurrentImpl struct {
+	meterCurrent func() (float64, float64, float64, error)
+}
+
+func (impl *decorateOpenEVSEMeterCurrentImpl) Currents() (float64, float64, float64, error) {
+	return impl.meterCurrent()
+}
+
 type decorateOpenEVSEPhaseSwitcherImpl struct {
-	phaseSwitcher func(int) error
+	phaseSwitcher func(phases int) error
 }
 
 func (impl *decorateOpenEVSEPhaseSwitcherImpl) Phases1p3p(phases int) error {
@@ -6,12 +6,20 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateOpenWB(base *OpenWB, phaseSwitcher func(int) error, battery func() (float64, error)) api.Charger {
-	switch {
-	case battery == nil && phaseSwitcher == nil:
+func decorateOpenWB(base *OpenWB, phaseSwitcher func(phases int) error, battery func() (float64, error)) api.Charger {
+	var caps int
+	if phaseSwitcher != nil {
+		caps |= 1
+	}
+	if battery != nil {
+		caps |= 2
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case battery == nil && phaseSwitcher != nil:
+	case 1: // api.PhaseSwitcher
 		return &struct {
 			*OpenWB
 			api.PhaseSwitcher
@@ -22,7 +30,7 @@ func decorateOpenWB(base *OpenWB, phaseSwitcher func(int) error, battery func()
 			},
 		}
 
-	case battery != nil && phaseSwitcher == nil:
+	case 2: // api.Battery
 		return &struct {
 			*OpenWB
 			api.Battery
@@ -33,7 +41,7 @@ func decorateOpenWB(base *OpenWB, phaseSwitcher func(int) error, battery func()
 			},
 		}
 
-	case battery != nil && phaseSwitcher != nil:
+	case 3: // api.PhaseSwitcher, api.Battery
 		return &struct {
 			*OpenWB
 			api.Battery
@@ -61,7 +69,7 @@ func (impl *decorateOpenWBBatteryImpl) SoC() (float64, error) {
 }
 
 type decorateOpenWBPhaseSwitcherImpl struct {
-	phaseSwitcher func(int) error
+	phaseSwitcher func(phases int) error
 }
 
 func (impl *decorateOpenWBPhaseSwitcherImpl) Phases1p3p(phases int) error {
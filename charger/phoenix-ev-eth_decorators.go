@@ -7,11 +7,22 @@ import (
 )
 
 func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error)) api.Charger {
-	switch {
-	case meter == nil && meterCurrent == nil && meterEnergy == nil:
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 1: // api.Meter
 		return &struct {
 			*PhoenixEVEth
 			api.Meter
@@ -22,7 +33,7 @@ func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), met
 			},
 		}
 
-	case meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 2: // api.MeterEnergy
 		return &struct {
 			*PhoenixEVEth
 			api.MeterEnergy
@@ -33,7 +44,7 @@ func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), met
 			},
 		}
 
-	case meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 3: // api.Meter, api.MeterEnergy
 		return &struct {
 			*PhoenixEVEth
 			api.Meter
@@ -48,7 +59,7 @@ func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), met
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 4: // api.MeterCurrent
 		return &struct {
 			*PhoenixEVEth
 			api.MeterCurrent
@@ -59,7 +70,7 @@ func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), met
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 5: // api.Meter, api.MeterCurrent
 		return &struct {
 			*PhoenixEVEth
 			api.Meter
@@ -74,7 +85,7 @@ func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), met
 			},
 		}
 
-	case meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 6: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*PhoenixEVEth
 			api.MeterCurrent
@@ -89,7 +100,7 @@ func decoratePhoenixEVEth(base *PhoenixEVEth, meter func() (float64, error), met
 			},
 		}
 
-	case meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*PhoenixEVEth
 			api.Meter
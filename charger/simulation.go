@@ -0,0 +1,42 @@
+package charger
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/simulation"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.Add("simulation", NewSimulationFromConfig)
+}
+
+// NewSimulationFromConfig creates a charger of an in-memory simulation with integrated meter
+func NewSimulationFromConfig(other map[string]interface{}) (api.Charger, error) {
+	cc := struct {
+		Simulation string // simulation name, devices of the same simulation share their energy flows
+		ID         string // charger id referenced by the simulated vehicle
+		Phases     int
+		Phases1p3p bool
+	}{
+		Phases: 3,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.ID == "" {
+		return nil, errors.New("missing id")
+	}
+
+	c := simulation.Instance(cc.Simulation).Charger(cc.ID)
+	c.SetPhases(cc.Phases)
+
+	if cc.Phases1p3p {
+		return &simulation.PhaseCharger{Charger: c}, nil
+	}
+
+	return c, nil
+}
@@ -0,0 +1,230 @@
+package charger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/smartevse"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// https://github.com/SmartEVSE/SmartEVSE-3
+
+// SmartEVSE charger implementation
+type SmartEVSE struct {
+	*request.Helper
+	log       *util.Logger
+	uri       string
+	settingsG provider.Cacheable[smartevse.Settings]
+}
+
+func init() {
+	registry.Add("smartevse", NewSmartEVSEFromConfig)
+}
+
+//go:generate go run ../cmd/tools/decorate.go -f decorateSmartEVSE -b *SmartEVSE -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.ChargeRater,ChargedEnergy,func() (float64, error)"
+
+// NewSmartEVSEFromConfig creates a SmartEVSE charger from generic config
+func NewSmartEVSEFromConfig(other map[string]interface{}) (api.Charger, error) {
+	cc := struct {
+		URI   string
+		Cache time.Duration
+	}{
+		Cache: time.Second,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	return NewSmartEVSE(cc.URI, cc.Cache)
+}
+
+// NewSmartEVSE creates SmartEVSE charger
+func NewSmartEVSE(uri string, cache time.Duration) (api.Charger, error) {
+	log := util.NewLogger("smartevse")
+
+	wb := &SmartEVSE{
+		Helper: request.NewHelper(log),
+		log:    log,
+		uri:    strings.TrimRight(util.DefaultScheme(uri, "http"), "/"),
+	}
+
+	wb.settingsG = provider.ResettableCached(func() (smartevse.Settings, error) {
+		var res smartevse.Settings
+		err := wb.GetJSON(wb.uri+"/settings", &res)
+		return res, err
+	}, cache)
+
+	res, err := wb.settingsG.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	// slaves are controlled by the load balancing master
+	if res.EVSE.LoadBl > smartevse.LoadBalancingMaster {
+		return nil, fmt.Errorf("load balancing slave %d: configure the master instead", res.EVSE.LoadBl-1)
+	}
+
+	if res.EVSE.LoadBl == smartevse.LoadBalancingMaster {
+		log.WARN.Println("load balancing master: current is shared with slaves")
+	}
+
+	// evcc controls pv charging, the charger's own solar and smart modes are left when enabled
+	if res.ModeID == smartevse.ModeSolar || res.ModeID == smartevse.ModeSmart {
+		log.WARN.Printf("%s mode: switching to normal mode when enabled", strings.ToLower(res.Mode))
+	}
+
+	// ev meter is optional
+	if strings.EqualFold(res.EVMeter.Description, "disabled") || res.EVMeter.Description == "" {
+		return wb, nil
+	}
+
+	return decorateSmartEVSE(wb, wb.currentPower, wb.totalEnergy, wb.currents, wb.chargedEnergy), nil
+}
+
+// set updates settings
+func (wb *SmartEVSE) set(key string, val int) error {
+	uri := fmt.Sprintf("%s/settings?%s", wb.uri, url.Values{key: {fmt.Sprint(val)}}.Encode())
+
+	req, err := request.New(http.MethodPost, uri, nil, request.AcceptJSON)
+	if err == nil {
+		_, err = wb.DoBody(req)
+		wb.settingsG.Reset()
+	}
+
+	return err
+}
+
+// Status implements the api.Charger interface
+func (wb *SmartEVSE) Status() (api.ChargeStatus, error) {
+	res, err := wb.settingsG.Get()
+	if err != nil {
+		return api.StatusNone, err
+	}
+
+	switch res.EVSE.StateID {
+	case 0: // A
+		return api.StatusA, nil
+	case 1, 4, 5, 9: // B, communication B, B1
+		return api.StatusB, nil
+	case 2, 6, 7, 8, 10: // C, communication C, activation, C1
+		return api.StatusC, nil
+	case 3:
+		return api.StatusD, nil
+	default:
+		return api.StatusNone, fmt.Errorf("invalid state: %s", res.EVSE.State)
+	}
+}
+
+// Enabled implements the api.Charger interface
+func (wb *SmartEVSE) Enabled() (bool, error) {
+	res, err := wb.settingsG.Get()
+	return res.ModeID != smartevse.ModeOff, err
+}
+
+// Enable implements the api.Charger interface
+func (wb *SmartEVSE) Enable(enable bool) error {
+	mode := smartevse.ModeOff
+	if enable {
+		mode = smartevse.ModeNormal
+	}
+
+	return wb.set("mode", mode)
+}
+
+// MaxCurrent implements the api.Charger interface
+func (wb *SmartEVSE) MaxCurrent(current int64) error {
+	return wb.MaxCurrentMillis(float64(current))
+}
+
+var _ api.ChargerEx = (*SmartEVSE)(nil)
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (wb *SmartEVSE) MaxCurrentMillis(current float64) error {
+	return wb.set("override_current", int(current*10))
+}
+
+// currentPower implements the api.Meter interface
+func (wb *SmartEVSE) currentPower() (float64, error) {
+	res, err := wb.settingsG.Get()
+	return res.EVMeter.ImportActivePower * 1e3, err
+}
+
+// totalEnergy implements the api.MeterEnergy interface
+func (wb *SmartEVSE) totalEnergy() (float64, error) {
+	res, err := wb.settingsG.Get()
+	return res.EVMeter.TotalKwh, err
+}
+
+// currents implements the api.MeterCurrent interface
+func (wb *SmartEVSE) currents() (float64, float64, float64, error) {
+	res, err := wb.settingsG.Get()
+	c := res.EVMeter.Currents
+	return c.L1 / 10, c.L2 / 10, c.L3 / 10, err
+}
+
+// chargedEnergy implements the api.ChargeRater interface
+func (wb *SmartEVSE) chargedEnergy() (float64, error) {
+	res, err := wb.settingsG.Get()
+	return res.EVMeter.ChargedKwh, err
+}
+
+var _ api.FaultReporter = (*SmartEVSE)(nil)
+
+// Fault implements the api.FaultReporter interface
+func (wb *SmartEVSE) Fault() (string, error) {
+	res, err := wb.settingsG.Get()
+	if err != nil {
+		return "", err
+	}
+
+	switch id := res.EVSE.ErrorID &^ (smartevse.ErrorLess6A | smartevse.ErrorNoSun); {
+	case id == 0:
+		return api.FaultNone, nil
+	case id&smartevse.ErrorTempHigh != 0:
+		return api.FaultTemperature, nil
+	case id&smartevse.ErrorRCMTripped != 0:
+		return api.FaultRCCB, nil
+	default:
+		return api.FaultInternal, nil
+	}
+}
+
+var _ api.Identifier = (*SmartEVSE)(nil)
+
+// Identify implements the api.Identifier interface
+func (wb *SmartEVSE) Identify() (string, error) {
+	res, err := wb.settingsG.Get()
+	return res.EVSE.RFID, err
+}
+
+var _ api.Diagnosis = (*SmartEVSE)(nil)
+
+// Diagnose implements the api.Diagnosis interface
+func (wb *SmartEVSE) Diagnose() {
+	res, err := wb.settingsG.Get()
+	if err != nil {
+		fmt.Printf("settings: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Version:\t%s\n", res.Version)
+	fmt.Printf("Mode:\t%s\n", res.Mode)
+	fmt.Printf("State:\t%s\n", res.EVSE.State)
+	fmt.Printf("Temperature:\t%.0f°C\n", res.EVSE.Temp)
+	fmt.Printf("Load balancing:\t%d\n", res.EVSE.LoadBl)
+	fmt.Printf("Current min/max:\t%d/%dA\n", res.Settings.CurrentMin, res.Settings.CurrentMax)
+	fmt.Printf("Solar stop timer:\t%ds\n", res.EVSE.SolarStopTimer)
+}
@@ -0,0 +1,61 @@
+package smartevse
+
+// Modes
+const (
+	ModeOff    = 0
+	ModeNormal = 1
+	ModeSolar  = 2
+	ModeSmart  = 3
+)
+
+// Load balancing roles, nodes 2-8 are slaves
+const (
+	LoadBalancingDisabled = 0
+	LoadBalancingMaster   = 1
+)
+
+// Error flags, less than 6A and no sun are regular solar waiting states
+const (
+	ErrorLess6A     = 1 << 0
+	ErrorNoSun      = 1 << 1
+	ErrorTempHigh   = 1 << 3
+	ErrorRCMTripped = 1 << 5
+)
+
+// Settings is the /settings api response. Currents are reported in 0.1A.
+type Settings struct {
+	Version      string `json:"version"`
+	Mode         string `json:"mode"`
+	ModeID       int    `json:"mode_id"`
+	CarConnected bool   `json:"car_connected"`
+	EVSE         struct {
+		Temp           float64 `json:"temp"`
+		Connected      bool    `json:"connected"`
+		Access         bool    `json:"access"`
+		State          string  `json:"state"`
+		StateID        int     `json:"state_id"`
+		Error          string  `json:"error"`
+		ErrorID        int     `json:"error_id"`
+		LoadBl         int     `json:"loadbl"`
+		SolarStopTimer int     `json:"solar_stop_timer"`
+		RFID           string  `json:"rfid"`
+	} `json:"evse"`
+	Settings struct {
+		ChargeCurrent     int `json:"charge_current"`
+		OverrideCurrent   int `json:"override_current"`
+		CurrentMin        int `json:"current_min"`
+		CurrentMax        int `json:"current_max"`
+		CurrentMain       int `json:"current_main"`
+		SolarMaxImport    int `json:"solar_max_import"`
+		SolarStartCurrent int `json:"solar_start_current"`
+	} `json:"settings"`
+	EVMeter struct {
+		Description       string  `json:"description"`
+		ImportActivePower float64 `json:"import_active_power"` // kW
+		TotalKwh          float64 `json:"total_kwh"`
+		ChargedKwh        float64 `json:"charged_kwh"`
+		Currents          struct {
+			L1, L2, L3 float64
+		} `json:"currents"`
+	} `json:"ev_meter"`
+}
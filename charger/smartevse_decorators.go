@@ -0,0 +1,295 @@
+package charger
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateSmartEVSE(base *SmartEVSE, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), chargeRater func() (float64, error)) api.Charger {
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+	if chargeRater != nil {
+		caps |= 8
+	}
+
+	switch caps {
+	case 0:
+		return base
+
+	case 1: // api.Meter
+		return &struct {
+			*SmartEVSE
+			api.Meter
+		}{
+			SmartEVSE: base,
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case 2: // api.MeterEnergy
+		return &struct {
+			*SmartEVSE
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 3: // api.Meter, api.MeterEnergy
+		return &struct {
+			*SmartEVSE
+			api.Meter
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 4: // api.MeterCurrent
+		return &struct {
+			*SmartEVSE
+			api.MeterCurrent
+		}{
+			SmartEVSE: base,
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 5: // api.Meter, api.MeterCurrent
+		return &struct {
+			*SmartEVSE
+			api.Meter
+			api.MeterCurrent
+		}{
+			SmartEVSE: base,
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 6: // api.MeterEnergy, api.MeterCurrent
+		return &struct {
+			*SmartEVSE
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
+		return &struct {
+			*SmartEVSE
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 8: // api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+		}
+
+	case 9: // api.Meter, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.Meter
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+		}
+
+	case 10: // api.MeterEnergy, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 11: // api.Meter, api.MeterEnergy, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.Meter
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 12: // api.MeterCurrent, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.MeterCurrent
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 13: // api.Meter, api.MeterCurrent, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.Meter
+			api.MeterCurrent
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 14: // api.MeterEnergy, api.MeterCurrent, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 15: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.ChargeRater
+		return &struct {
+			*SmartEVSE
+			api.ChargeRater
+			api.Meter
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			SmartEVSE: base,
+			ChargeRater: &decorateSmartEVSEChargeRaterImpl{
+				chargeRater: chargeRater,
+			},
+			Meter: &decorateSmartEVSEMeterImpl{
+				meter: meter,
+			},
+			MeterCurrent: &decorateSmartEVSEMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateSmartEVSEMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateSmartEVSEChargeRaterImpl struct {
+	chargeRater func() (float64, error)
+}
+
+func (impl *decorateSmartEVSEChargeRaterImpl) ChargedEnergy() (float64, error) {
+	return impl.chargeRater()
+}
+
+type decorateSmartEVSEMeterImpl struct {
+	meter func() (float64, error)
+}
+
+func (impl *decorateSmartEVSEMeterImpl) CurrentPower() (float64, error) {
+	return impl.meter()
+}
+
+type decorateSmartEVSEMeterCurrentImpl struct {
+	meterCurrent func() (float64, float64, float64, error)
+}
+
+func (impl *decorateSmartEVSEMeterCurrentImpl) Currents() (float64, float64, float64, error) {
+	return impl.meterCurrent()
+}
+
+type decorateSmartEVSEMeterEnergyImpl struct {
+	meterEnergy func() (float64, error)
+}
+
+func (impl *decorateSmartEVSEMeterEnergyImpl) TotalEnergy() (float64, error) {
+	return impl.meterEnergy()
+}
@@ -1,6 +1,12 @@
 package charger
 
-import "github.com/evcc-io/evcc/api"
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
 
 // switchSocket implements the api.Charger Status and CurrentPower methods
 // using basic generic switch socket functions
@@ -68,3 +74,79 @@ func (c *switchSocket) CurrentPower() (float64, error) {
 
 	return power, err
 }
+
+// SwitchSocket is a generic switch socket charger with configurable getters and setters.
+// It allows plugs not covered by a dedicated driver, e.g. Shelly or Tasmota via MQTT, to be used as charger.
+type SwitchSocket struct {
+	enabledG func() (bool, error)
+	enableS  func(bool) error
+	*switchSocket
+}
+
+func init() {
+	registry.Add("switchsocket", NewSwitchSocketFromConfig)
+}
+
+// NewSwitchSocketFromConfig creates a generic switch socket charger from generic config
+func NewSwitchSocketFromConfig(other map[string]interface{}) (api.Charger, error) {
+	var cc struct {
+		Enabled, Enable, Power provider.Config
+		StandbyPower           float64
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	enabled, err := provider.NewBoolGetterFromConfig(cc.Enabled)
+	if err != nil {
+		return nil, fmt.Errorf("enabled: %w", err)
+	}
+
+	enable, err := provider.NewBoolSetterFromConfig("enable", cc.Enable)
+	if err != nil {
+		return nil, fmt.Errorf("enable: %w", err)
+	}
+
+	// power is not required in static mode
+	power := func() (float64, error) { return 0, nil }
+	if cc.StandbyPower >= 0 {
+		if power, err = provider.NewFloatGetterFromConfig(cc.Power); err != nil {
+			return nil, fmt.Errorf("power: %w", err)
+		}
+	}
+
+	return NewGenericSwitchSocket(enabled, enable, power, cc.StandbyPower), nil
+}
+
+// NewGenericSwitchSocket creates a generic switch socket charger
+func NewGenericSwitchSocket(
+	enabled func() (bool, error),
+	enable func(bool) error,
+	currentPower func() (float64, error),
+	standbypower float64,
+) *SwitchSocket {
+	c := &SwitchSocket{
+		enabledG: enabled,
+		enableS:  enable,
+	}
+
+	c.switchSocket = NewSwitchSocket(enabled, currentPower, standbypower)
+
+	return c
+}
+
+// Enabled implements the api.Charger interface
+func (c *SwitchSocket) Enabled() (bool, error) {
+	return c.enabledG()
+}
+
+// Enable implements the api.Charger interface
+func (c *SwitchSocket) Enable(enable bool) error {
+	return c.enableS(enable)
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *SwitchSocket) MaxCurrent(current int64) error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package charger
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+func TestSwitchSocketStandbyPower(t *testing.T) {
+	var (
+		on    bool
+		power float64
+	)
+
+	c := NewGenericSwitchSocket(
+		func() (bool, error) { return on, nil },
+		func(enable bool) error { on = enable; return nil },
+		func() (float64, error) { return power, nil },
+		15,
+	)
+
+	if err := c.Enable(true); err != nil || !on {
+		t.Fatal("enable failed", err)
+	}
+
+	for _, tc := range []struct {
+		power    float64
+		status   api.ChargeStatus
+		expected float64
+	}{
+		{100, api.StatusC, 100},
+		{15, api.StatusB, 0}, // charging complete
+		{5, api.StatusB, 0},
+	} {
+		power = tc.power
+
+		if status, err := c.Status(); err != nil || status != tc.status {
+			t.Errorf("power %.0fW: expected status %s, got %s", tc.power, tc.status, status)
+		}
+
+		if res, err := c.CurrentPower(); err != nil || res != tc.expected {
+			t.Errorf("power %.0fW: expected %.0fW, got %.0fW", tc.power, tc.expected, res)
+		}
+	}
+}
+
+func TestSwitchSocketStaticPower(t *testing.T) {
+	var on bool
+
+	c := NewGenericSwitchSocket(
+		func() (bool, error) { return on, nil },
+		func(enable bool) error { on = enable; return nil },
+		nil,
+		-100,
+	)
+
+	if status, _ := c.Status(); status != api.StatusB {
+		t.Errorf("expected status B, got %s", status)
+	}
+
+	_ = c.Enable(true)
+
+	if status, _ := c.Status(); status != api.StatusC {
+		t.Errorf("expected status C, got %s", status)
+	}
+
+	if power, _ := c.CurrentPower(); power != 100 {
+		t.Errorf("expected 100W, got %.0fW", power)
+	}
+}
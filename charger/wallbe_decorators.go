@@ -7,11 +7,25 @@ import (
 )
 
 func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), chargerEx func(current float64) error) api.Charger {
-	switch {
-	case chargerEx == nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+	if chargerEx != nil {
+		caps |= 8
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case chargerEx == nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 1: // api.Meter
 		return &struct {
 			*Wallbe
 			api.Meter
@@ -22,7 +36,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 2: // api.MeterEnergy
 		return &struct {
 			*Wallbe
 			api.MeterEnergy
@@ -33,7 +47,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 3: // api.Meter, api.MeterEnergy
 		return &struct {
 			*Wallbe
 			api.Meter
@@ -48,7 +62,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 4: // api.MeterCurrent
 		return &struct {
 			*Wallbe
 			api.MeterCurrent
@@ -59,7 +73,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 5: // api.Meter, api.MeterCurrent
 		return &struct {
 			*Wallbe
 			api.Meter
@@ -74,7 +88,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 6: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*Wallbe
 			api.MeterCurrent
@@ -89,7 +103,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx == nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*Wallbe
 			api.Meter
@@ -108,7 +122,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 8: // api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -119,7 +133,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 9: // api.Meter, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -134,7 +148,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 10: // api.MeterEnergy, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -149,7 +163,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 11: // api.Meter, api.MeterEnergy, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -168,7 +182,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 12: // api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -183,7 +197,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 13: // api.Meter, api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -202,7 +216,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 14: // api.MeterEnergy, api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -221,7 +235,7 @@ func decorateWallbe(base *Wallbe, meter func() (float64, error), meterEnergy fun
 			},
 		}
 
-	case chargerEx != nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 15: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.ChargerEx
 		return &struct {
 			*Wallbe
 			api.ChargerEx
@@ -7,11 +7,25 @@ import (
 )
 
 func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), identifier func() (string, error)) api.Charger {
-	switch {
-	case identifier == nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	var caps int
+	if meter != nil {
+		caps |= 1
+	}
+	if meterEnergy != nil {
+		caps |= 2
+	}
+	if meterCurrent != nil {
+		caps |= 4
+	}
+	if identifier != nil {
+		caps |= 8
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case identifier == nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 1: // api.Meter
 		return &struct {
 			*Warp2
 			api.Meter
@@ -22,7 +36,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier == nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 2: // api.MeterEnergy
 		return &struct {
 			*Warp2
 			api.MeterEnergy
@@ -33,7 +47,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier == nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 3: // api.Meter, api.MeterEnergy
 		return &struct {
 			*Warp2
 			api.Meter
@@ -48,7 +62,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier == nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 4: // api.MeterCurrent
 		return &struct {
 			*Warp2
 			api.MeterCurrent
@@ -59,7 +73,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier == nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 5: // api.Meter, api.MeterCurrent
 		return &struct {
 			*Warp2
 			api.Meter
@@ -74,7 +88,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier == nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 6: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*Warp2
 			api.MeterCurrent
@@ -89,7 +103,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier == nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 7: // api.Meter, api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			*Warp2
 			api.Meter
@@ -108,7 +122,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter == nil && meterCurrent == nil && meterEnergy == nil:
+	case 8: // api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -119,7 +133,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter != nil && meterCurrent == nil && meterEnergy == nil:
+	case 9: // api.Meter, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -134,7 +148,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter == nil && meterCurrent == nil && meterEnergy != nil:
+	case 10: // api.MeterEnergy, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -149,7 +163,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter != nil && meterCurrent == nil && meterEnergy != nil:
+	case 11: // api.Meter, api.MeterEnergy, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -168,7 +182,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter == nil && meterCurrent != nil && meterEnergy == nil:
+	case 12: // api.MeterCurrent, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -183,7 +197,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter != nil && meterCurrent != nil && meterEnergy == nil:
+	case 13: // api.Meter, api.MeterCurrent, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -202,7 +216,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter == nil && meterCurrent != nil && meterEnergy != nil:
+	case 14: // api.MeterEnergy, api.MeterCurrent, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
@@ -221,7 +235,7 @@ func decorateWarp2(base *Warp2, meter func() (float64, error), meterEnergy func(
 			},
 		}
 
-	case identifier != nil && meter != nil && meterCurrent != nil && meterEnergy != nil:
+	case 15: // api.Meter, api.MeterEnergy, api.MeterCurrent, api.Identifier
 		return &struct {
 			*Warp2
 			api.Identifier
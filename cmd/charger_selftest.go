@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/selftest"
+	"github.com/spf13/cobra"
+)
+
+// chargerSelfTestCmd represents the charger self-test command
+var chargerSelfTestCmd = &cobra.Command{
+	Use:   "selftest [name]",
+	Short: "Cycle enable/disable, min/max current and phase switching and report the charger's response",
+	Run:   runChargerSelfTest,
+}
+
+func init() {
+	chargerCmd.AddCommand(chargerSelfTestCmd)
+
+	chargerSelfTestCmd.Flags().Float64(flagMin, 6, "min current (A)")
+	chargerSelfTestCmd.Flags().Float64(flagMax, 16, "max current (A)")
+	chargerSelfTestCmd.Flags().Duration(flagTimeout, 30*time.Second, "max charger response time")
+}
+
+func printSelfTest(name string, res selftest.Report) {
+	fmt.Printf("charger:\t%s\n", name)
+	fmt.Printf("status:\t\t%s\n\n", res.Status)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Step\tResult\tResponse\tDetails")
+
+	for _, s := range res.Steps {
+		response := "-"
+		if s.Response > 0 {
+			response = s.Response.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, s.Result, response, s.Details)
+	}
+
+	w.Flush()
+
+	fmt.Printf("\npassed:\t%t\n", res.Passed())
+}
+
+func runChargerSelfTest(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup environment
+	if err := configureEnvironment(cmd, conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// select single charger
+	if err := selectByName(cmd, &conf.Chargers); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := cp.configureChargers(conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	chargers := cp.chargers
+	if len(args) == 1 {
+		name := args[0]
+		charger, err := cp.Charger(name)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+		chargers = map[string]api.Charger{name: charger}
+	}
+
+	var cc selftest.Config
+	var err error
+
+	if cc.MinCurrent, err = cmd.Flags().GetFloat64(flagMin); err != nil {
+		log.ERROR.Fatalln(err)
+	}
+	if cc.MaxCurrent, err = cmd.Flags().GetFloat64(flagMax); err != nil {
+		log.ERROR.Fatalln(err)
+	}
+	if cc.Timeout, err = cmd.Flags().GetDuration(flagTimeout); err != nil {
+		log.ERROR.Fatalln(err)
+	}
+
+	for name, c := range chargers {
+		printSelfTest(name, selftest.Run(c, cc))
+	}
+
+	// wait for shutdown
+	<-shutdownDoneC()
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core/metrics"
 	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
@@ -44,24 +45,43 @@ var conf = config{
 		Type: "sqlite",
 		Dsn:  "~/.evcc/evcc.db",
 	},
+	Fleet: server.FleetConfig{
+		Interval: time.Minute,
+	},
+	Cluster: server.ClusterConfig{
+		Interval: 10 * time.Second,
+	},
 }
 
 type config struct {
 	URI          interface{} // TODO deprecated
 	Network      networkConfig
+	Auth         server.AuthConfig
 	Log          string
 	SponsorToken string
 	Plant        string // telemetry plant id
 	Telemetry    bool
+	Experimental map[string]bool
 	Metrics      bool
 	Profile      bool
 	Levels       map[string]string
 	Interval     time.Duration
 	Mqtt         mqttConfig
+	Grpc         grpcConfig
+	Fleet        server.FleetConfig
+	Cluster      server.ClusterConfig
+	Mobile       server.MobileConfig
+	Dashboard    server.DashboardConfig
+	SmartHome    server.SmartHomeConfig
+	Discovery    server.DiscoveryConfig
+	ModbusBus    []modbus.BusConfig
 	ModbusProxy  []proxyConfig
+	ModbusServer modbusServerConfig
 	Database     dbConfig
 	Javascript   map[string]interface{}
 	Influx       server.InfluxConfig
+	Telegraf     server.TelegrafConfig
+	History      server.HistoryConfig
 	EEBus        map[string]interface{}
 	HEMS         typedConfig
 	Messaging    messagingConfig
@@ -69,8 +89,10 @@ type config struct {
 	Chargers     []qualifiedConfig
 	Vehicles     []qualifiedConfig
 	Tariffs      tariffConfig
+	Forecast     typedConfig
 	Site         map[string]interface{}
 	LoadPoints   []map[string]interface{}
+	Sites        []map[string]interface{} // additional sites with their own meters and loadpoints
 }
 
 type mqttConfig struct {
@@ -78,12 +100,21 @@ type mqttConfig struct {
 	Topic       string
 }
 
+type grpcConfig struct {
+	Port int // listening port, 0 to disable
+}
+
 type proxyConfig struct {
 	Port            int
 	ReadOnly        bool
 	modbus.Settings `mapstructure:",squash"`
 }
 
+type modbusServerConfig struct {
+	Port     int  // listening port, 0 to disable
+	Writable bool // accept setpoints, not authenticated
+}
+
 type dbConfig struct {
 	Type string
 	Dsn  string
@@ -108,6 +139,7 @@ type tariffConfig struct {
 	Currency string
 	Grid     typedConfig
 	FeedIn   typedConfig
+	Co2      typedConfig
 }
 
 type networkConfig struct {
@@ -172,6 +204,20 @@ func (cp *ConfigProvider) Vehicle(name string) (api.Vehicle, error) {
 	return nil, fmt.Errorf("vehicle does not exist: %s", name)
 }
 
+// Device provides meters, chargers and vehicles by name
+func (cp *ConfigProvider) Device(name string) (interface{}, error) {
+	if meter, ok := cp.meters[name]; ok {
+		return meter, nil
+	}
+	if charger, ok := cp.chargers[name]; ok {
+		return charger, nil
+	}
+	if vehicle, ok := cp.vehicles[name]; ok {
+		return vehicle, nil
+	}
+	return nil, fmt.Errorf("device does not exist: %s", name)
+}
+
 func (cp *ConfigProvider) configure(conf config) error {
 	err := cp.configureMeters(conf)
 	if err == nil {
@@ -201,6 +247,7 @@ func (cp *ConfigProvider) configureMeters(conf config) error {
 		}
 
 		cp.meters[cc.Name] = m
+		metrics.Register(m, "meter", cc.Name)
 	}
 
 	return nil
@@ -232,6 +279,7 @@ func (cp *ConfigProvider) configureChargers(conf config) error {
 			}
 
 			cp.chargers[cc.Name] = c
+			metrics.Register(c, "charger", cc.Name)
 			return nil
 		})
 	}
@@ -252,26 +300,9 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 		cc := cc
 
 		g.Go(func() error {
-			// ensure vehicle config has title
-			var ccWithTitle struct {
-				Title string
-				Other map[string]interface{} `mapstructure:",remain"`
-			}
-
-			if err := util.DecodeOther(cc.Other, &ccWithTitle); err != nil {
-				return err
-			}
-
-			if ccWithTitle.Title == "" {
-				//lint:ignore SA1019 as Title is safe on ascii
-				cc.Other["title"] = strings.Title(cc.Name)
-			}
-
-			v, err := vehicle.NewFromConfig(cc.Type, cc.Other)
+			v, err := newVehicle(cc)
 			if err != nil {
-				log.ERROR.Printf("creating vehicle %s failed: %v", cc.Name, err)
-				// wrap any created errors to prevent fatals
-				v, _ = wrapper.New(v, err)
+				return err
 			}
 
 			mu.Lock()
@@ -282,6 +313,7 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 			}
 
 			cp.vehicles[cc.Name] = v
+			metrics.Register(v, "vehicle", cc.Name)
 			return nil
 		})
 	}
@@ -289,6 +321,36 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 	return g.Wait()
 }
 
+// newVehicle creates a vehicle with default title. Vehicle errors are wrapped to prevent fatals.
+func newVehicle(cc qualifiedConfig) (api.Vehicle, error) {
+	// ensure vehicle config has title
+	var ccWithTitle struct {
+		Title string
+		Other map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(cc.Other, &ccWithTitle); err != nil {
+		return nil, err
+	}
+
+	if ccWithTitle.Title == "" {
+		if cc.Other == nil {
+			cc.Other = make(map[string]interface{})
+		}
+		//lint:ignore SA1019 as Title is safe on ascii
+		cc.Other["title"] = strings.Title(cc.Name)
+	}
+
+	v, err := vehicle.NewFromConfig(cc.Type, cc.Other)
+	if err != nil {
+		log.ERROR.Printf("creating vehicle %s failed: %v", cc.Name, err)
+		// wrap any created errors to prevent fatals
+		v, _ = wrapper.New(v, err)
+	}
+
+	return v, nil
+}
+
 // webControl handles routing for devices. For now only api.AuthProvider related routes
 func (cp *ConfigProvider) webControl(conf networkConfig, router *mux.Router, paramC chan<- util.Param) {
 	auth := router.PathPrefix("/oauth").Subrouter()
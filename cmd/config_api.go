@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Export returns the applied configuration with secrets redacted
+func (r *reloader) Export() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return redactSettings(r.settings).(map[string]interface{})
+}
+
+// Import validates and applies the yaml or json configuration and persists it to the config file.
+// Redacted secrets keep their current values. Changes that cannot be reloaded are applied on restart.
+func (r *reloader) Import(b []byte) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, err := parseSettings(b)
+	if err != nil {
+		return false, err
+	}
+
+	// the document is written as received, only redacted secrets are replaced
+	doc, err := restoreRedactedDocument(b, r.settings)
+	if err != nil {
+		return false, err
+	}
+
+	settings = restoreRedacted(settings, r.settings).(map[string]interface{})
+
+	if err := validateSettings(settings); err != nil {
+		return false, err
+	}
+
+	err = r.apply(settings)
+
+	restart := errors.Is(err, errRestartRequired)
+	if err != nil && !restart {
+		return false, err
+	}
+
+	if err := writeSettings(r.file, doc); err != nil {
+		return restart, fmt.Errorf("failed writing config file: %w", err)
+	}
+
+	// the saved settings are the baseline after restart
+	if restart {
+		r.settings = settings
+	}
+
+	log.INFO.Println("config import: saved", r.file)
+
+	return restart, nil
+}
+
+// parseSettings reads yaml or json configuration, json being a subset of yaml
+func parseSettings(b []byte) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(bytes.NewReader(b)); err != nil {
+		return nil, fmt.Errorf("failed parsing config: %w", err)
+	}
+
+	return v.AllSettings(), nil
+}
+
+// validateSettings decodes the settings like the config file at startup
+func validateSettings(settings map[string]interface{}) error {
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+
+	var conf config
+	if err := v.UnmarshalExact(&conf); err != nil {
+		return fmt.Errorf("failed parsing config: %w", err)
+	}
+
+	return nil
+}
+
+// writeSettings replaces the config file, keeping the previous file as backup
+func writeSettings(file string, b []byte) error {
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(file); err == nil {
+		mode = fi.Mode()
+
+		prev, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(file+".bak", prev, mode); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+// redactSettings replaces the values of secret keys, nested settings like token lists are redacted per entry
+func redactSettings(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, el := range v {
+			switch el.(type) {
+			case map[string]interface{}, []interface{}:
+				res[k] = redactSettings(el)
+			default:
+				if secretKey(k) {
+					res[k] = redactReplacement
+				} else {
+					res[k] = el
+				}
+			}
+		}
+		return res
+
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, el := range v {
+			res = append(res, redactSettings(el))
+		}
+		return res
+
+	default:
+		return val
+	}
+}
+
+// restoreRedacted replaces redacted values with the current values.
+// List entries are matched by name, unnamed entries by position.
+func restoreRedacted(val, cur interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		if v == redactReplacement && cur != nil {
+			return cur
+		}
+
+	case map[string]interface{}:
+		prev, _ := cur.(map[string]interface{})
+		for k, el := range v {
+			v[k] = restoreRedacted(el, prev[k])
+		}
+
+	case []interface{}:
+		prev, _ := cur.([]interface{})
+		for i, el := range v {
+			v[i] = restoreRedacted(el, listEntry(prev, el, i))
+		}
+	}
+
+	return val
+}
+
+// restoreRedactedDocument replaces redacted values of the yaml document with the current values.
+// Documents without redacted values are returned unchanged, otherwise comments are kept.
+func restoreRedactedDocument(b []byte, cur map[string]interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("failed parsing config: %w", err)
+	}
+
+	restored, err := restoreRedactedNode(&doc, cur)
+	if err != nil || !restored {
+		return b, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), enc.Close()
+}
+
+// restoreRedactedNode replaces redacted values of the yaml node with the current values and returns true if any was restored.
+// Keys are matched case-insensitive, list entries by name or, if unnamed, by position.
+func restoreRedactedNode(node *yaml.Node, cur interface{}) (bool, error) {
+	var restored bool
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			res, err := restoreRedactedNode(n, cur)
+			if err != nil {
+				return false, err
+			}
+			restored = restored || res
+		}
+
+	case yaml.ScalarNode:
+		if node.Value == redactReplacement && cur != nil {
+			head, line, foot := node.HeadComment, node.LineComment, node.FootComment
+			if err := node.Encode(cur); err != nil {
+				return false, err
+			}
+			node.HeadComment, node.LineComment, node.FootComment = head, line, foot
+			restored = true
+		}
+
+	case yaml.MappingNode:
+		prev, _ := cur.(map[string]interface{})
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			res, err := restoreRedactedNode(node.Content[i+1], prev[strings.ToLower(node.Content[i].Value)])
+			if err != nil {
+				return false, err
+			}
+			restored = restored || res
+		}
+
+	case yaml.SequenceNode:
+		prev, _ := cur.([]interface{})
+		for i, n := range node.Content {
+			var el interface{}
+			if err := n.Decode(&el); err != nil {
+				return false, err
+			}
+
+			res, err := restoreRedactedNode(n, listEntry(prev, el, i))
+			if err != nil {
+				return false, err
+			}
+			restored = restored || res
+		}
+	}
+
+	return restored, nil
+}
+
+// listEntry returns the list entry with the same name as el or, if unnamed, at the same position
+func listEntry(list []interface{}, el interface{}, i int) interface{} {
+	if m, ok := el.(map[string]interface{}); ok && m["name"] != nil {
+		for _, cur := range list {
+			if cm, ok := cur.(map[string]interface{}); ok && cm["name"] == m["name"] {
+				return cur
+			}
+		}
+
+		// new entry
+		return nil
+	}
+
+	if i < len(list) {
+		return list[i]
+	}
+
+	return nil
+}
@@ -435,6 +435,11 @@ func (c *CmdConfigure) processParams(templateItem *templates.Template, deviceCat
 				continue
 			}
 
+			// skip params whose dependencies are not met by the values entered so far
+			if !templateItem.DependenciesMet(param, additionalConfig) {
+				continue
+			}
+
 			if param.Hidden && param.Default != "" {
 				additionalConfig[param.Name] = param.Default
 				continue
@@ -506,6 +511,7 @@ func (c *CmdConfigure) processInputConfig(param templates.Param) string {
 		validValues:  param.ValidValues,
 		mask:         param.Mask,
 		required:     param.Required,
+		validate:     param.ValidateValue,
 	})
 
 	if param.ValueType == templates.ParamValueTypeBool && value == "true" {
@@ -109,6 +109,7 @@ type question struct {
 	minNumberValue, maxNumberValue int64
 	mask, required                 bool
 	excludeNone                    bool
+	validate                       func(string) error
 }
 
 // askBoolValue asks for a boolean value selection for a given question
@@ -196,6 +197,10 @@ func (c *CmdConfigure) askValue(q question) string {
 			}
 		}
 
+		if q.validate != nil {
+			return q.validate(value)
+		}
+
 		return nil
 	}
 
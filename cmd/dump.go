@@ -52,7 +52,7 @@ func runDump(cmd *cobra.Command, args []string) {
 
 	var site *core.Site
 	if err == nil {
-		site, err = configureSiteAndLoadpoints(conf)
+		site, _, err = configureSiteAndLoadpoints(conf)
 	}
 
 	if *dumpConfig {
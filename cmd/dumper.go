@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/fatih/structs"
+	"golang.org/x/exp/maps"
 )
 
 type dumper struct {
@@ -206,8 +208,26 @@ func (d *dumper) Dump(name string, v interface{}) {
 func (d *dumper) DumpDiagnosis(v interface{}) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
 
+	if v, ok := v.(api.DiagnosticReporter); ok {
+		fmt.Fprintln(w, "Diagnostics:")
+
+		if res, err := v.Diagnostics(); err != nil {
+			fmt.Fprintf(w, "Error:\t%v\n", err)
+		} else {
+			keys := maps.Keys(res)
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				fmt.Fprintf(w, "%s:\t%v\n", k, res[k])
+			}
+		}
+
+		w.Flush()
+	}
+
 	if v, ok := v.(api.Diagnosis); ok {
 		fmt.Fprintln(w, "Diagnostic dump:")
+		w.Flush()
 		v.Diagnose()
 	}
 
@@ -14,6 +14,9 @@ const (
 	flagHeaders            = "log-headers"
 	flagHeadersDescription = "Log headers"
 
+	flagDemo            = "demo"
+	flagDemoDescription = "Run simulated demo installation instead of config file"
+
 	flagName            = "name"
 	flagNameDescription = "Select %s by name"
 
@@ -38,6 +41,10 @@ const (
 
 	flagDigits = "digits"
 	flagDelay  = "delay"
+
+	flagTimeout = "timeout"
+	flagMin     = "min"
+	flagMax     = "max"
 )
 
 func bind(cmd *cobra.Command, flag string) {
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/util"
@@ -47,18 +48,34 @@ func unwrap(err error) (res []string) {
 	return
 }
 
+// redactSecrets are the configuration keys holding secrets
+var redactSecrets = []string{
+	"mac",                   // infrastructure
+	"sponsortoken", "plant", // global settings
+	"user", "password", "pin", // users
+	"token", "access", "refresh", // tokens
+	"ain", "secret", "serial", "deviceid", "machineid", // devices
+	"vin"} // vehicles
+
+// redactPatterns match configuration keys containing secrets like clientsecret, apitoken or apikey
+var redactPatterns = []string{`\w*(?:secret|token|password)\w*`, `\w*key`}
+
+// redacted replaces secret configuration values
+const redactReplacement = "*****"
+
+// redactKey matches the secret configuration keys
+var redactKey = regexp.MustCompile(fmt.Sprintf(`(?i)^(?:%s|%s)$`, strings.Join(redactSecrets, "|"), strings.Join(redactPatterns, "|")))
+
+// secretKey returns true if the configuration key holds a secret
+func secretKey(key string) bool {
+	return redactKey.MatchString(key)
+}
+
 // redact redacts a configuration string
 func redact(src string) string {
-	secrets := []string{
-		"mac",                   // infrastructure
-		"sponsortoken", "plant", // global settings
-		"user", "password", "pin", // users
-		"token", "access", "refresh", // tokens
-		"ain", "secret", "serial", "deviceid", "machineid", // devices
-		"vin"} // vehicles
 	return regexp.
-		MustCompile(fmt.Sprintf(`\b(%s)\b.*?:.*`, strings.Join(secrets, "|"))).
-		ReplaceAllString(src, "$1: *****")
+		MustCompile(fmt.Sprintf(`(?i)\b(%s|%s)\b.*?:.*`, strings.Join(redactSecrets, "|"), strings.Join(redactPatterns, "|"))).
+		ReplaceAllString(src, "$1: "+redactReplacement)
 }
 
 func publishErrorInfo(valueChan chan<- util.Param, cfgFile string, err error) {
@@ -93,6 +110,16 @@ func fatal(err error) {
 	os.Exit(1)
 }
 
+// stoppedC returns a channel that closes when all sites have stopped
+func stoppedC(wg *sync.WaitGroup) <-chan struct{} {
+	doneC := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(doneC)
+	}()
+	return doneC
+}
+
 // shutdownDoneC returns a channel that closes when shutdown has completed
 func shutdownDoneC() <-chan struct{} {
 	doneC := make(chan struct{})
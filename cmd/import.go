@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <sessions.csv>",
+	Short: "Import charging sessions",
+	Long:  "Import historical charging sessions from evcc, TeslaMate or openWB csv exports into the session database",
+	Args:  cobra.ExactArgs(1),
+	Run:   runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringP("format", "f", db.FormatEvcc, "Csv format (evcc, teslamate, openwb)")
+	importCmd.Flags().StringP("loadpoint", "l", "", "Loadpoint title of imported sessions")
+	importCmd.Flags().String("vehicle", "", "Vehicle title of imported sessions")
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup environment
+	if err := configureEnvironment(cmd, conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	defer f.Close()
+
+	var o db.ImportOptions
+	o.Format, _ = cmd.Flags().GetString("format")
+	o.Loadpoint, _ = cmd.Flags().GetString("loadpoint")
+	o.Vehicle, _ = cmd.Flags().GetString("vehicle")
+
+	sessions, err := db.ReadCsv(f, o)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	imported, skipped, err := db.Import(sessions)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	log.INFO.Printf("imported %d sessions, skipped %d existing", imported, skipped)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// meterScanCmd represents the meter scan command
+var meterScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Scan Modbus for meters",
+	Long: `Scan probes a Modbus TCP or RTU address range for SunSpec, E3/DC and common RS485 meters
+(e.g. Eastron SDM, Janitza, ABB) and prints the matching evcc.yaml meter configuration.
+
+Examples:
+  evcc meter scan --uri 192.168.0.10:502 --ids 1-10
+  evcc meter scan --device /dev/ttyUSB0 --baudrate 9600 --comset 8N1`,
+	Args: cobra.NoArgs,
+	Run:  runMeterScan,
+}
+
+func init() {
+	meterCmd.AddCommand(meterScanCmd)
+	meterScanCmd.Flags().String("uri", "", "Modbus TCP address")
+	meterScanCmd.Flags().Bool("rtu", false, "Use RTU over TCP")
+	meterScanCmd.Flags().String("device", "", "Modbus RTU serial device")
+	meterScanCmd.Flags().Int("baudrate", 9600, "Modbus RTU baudrate")
+	meterScanCmd.Flags().String("comset", "8N1", "Modbus RTU communication settings")
+	meterScanCmd.Flags().String("ids", "1-247", "Modbus ids to scan, e.g. 1-10,126")
+	meterScanCmd.Flags().Duration("timeout", 0, "Modbus timeout")
+}
+
+func runMeterScan(cmd *cobra.Command, args []string) {
+	util.LogLevel(viper.GetString("log"), nil)
+
+	var s modbus.Settings
+	s.URI, _ = cmd.Flags().GetString("uri")
+	s.Device, _ = cmd.Flags().GetString("device")
+
+	if s.Device != "" {
+		s.Baudrate, _ = cmd.Flags().GetInt("baudrate")
+		s.Comset, _ = cmd.Flags().GetString("comset")
+	}
+
+	if rtu, _ := cmd.Flags().GetBool("rtu"); rtu {
+		s.RTU = &rtu
+	}
+
+	idsS, _ := cmd.Flags().GetString("ids")
+	ids, err := meter.ParseIDs(idsS)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	log.INFO.Printf("scanning %s for %d ids", s.String(), len(ids))
+
+	res, err := meter.ScanModbus(util.NewLogger("scan"), s, ids, timeout)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if len(res) == 0 {
+		log.INFO.Println("no meters found")
+		return
+	}
+
+	var conf []map[string]interface{}
+	for i, r := range res {
+		log.INFO.Printf("id %d: %s (%s)", r.ID, r.Description, r.Model)
+		conf = append(conf, r.Config(fmt.Sprintf("meter%d", i+1), s))
+	}
+
+	fmt.Println()
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]interface{}{"meters": conf}); err != nil {
+		log.FATAL.Fatal(err)
+	}
+}
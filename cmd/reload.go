@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/viper"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// reloadableSections are the config file sections that can be applied without restart
+var reloadableSections = []string{"meters", "chargers", "vehicles"}
+
+// errRestartRequired indicates configuration changes that are only applied on restart
+var errRestartRequired = errors.New("restart required")
+
+// reloader re-reads the config file and replaces changed devices of the running site
+type reloader struct {
+	mu       sync.Mutex
+	file     string
+	site     *core.Site
+	settings map[string]interface{}
+}
+
+// newReloader creates a reloader using the current config file contents as baseline
+func newReloader(file string, site *core.Site) (*reloader, error) {
+	settings, err := readSettings(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloader{
+		file:     file,
+		site:     site,
+		settings: settings,
+	}, nil
+}
+
+// configureReload enables config reload on SIGHUP and POST /api/config/reload and config import/export via /api/config
+func configureReload(file string, site *core.Site, httpd *server.HTTPd) {
+	r, err := newReloader(file, site)
+	if err != nil {
+		log.ERROR.Printf("config reload: %v", err)
+		return
+	}
+
+	httpd.RegisterReloadHandler(r.Reload)
+	httpd.RegisterConfigHandler(r.Export, r.Import)
+
+	go func() {
+		signalC := make(chan os.Signal, 1)
+		signal.Notify(signalC, syscall.SIGHUP)
+
+		for range signalC {
+			log.INFO.Println("config reload: received SIGHUP")
+			if err := r.Reload(); err != nil {
+				log.ERROR.Printf("config reload: %v", err)
+			}
+		}
+	}()
+}
+
+// readSettings reads the config file without environment and flag overrides
+func readSettings(file string) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	return v.AllSettings(), nil
+}
+
+// changedSections returns the top level config sections that differ
+func changedSections(old, new map[string]interface{}) []string {
+	var res []string
+
+	for k, v := range new {
+		if !reflect.DeepEqual(old[k], v) {
+			res = append(res, k)
+		}
+	}
+
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			res = append(res, k)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// deviceConfigs decodes a device section by name
+func deviceConfigs(settings map[string]interface{}, section string) (map[string]qualifiedConfig, error) {
+	var list []qualifiedConfig
+	if err := util.DecodeOther(settings[section], &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", section, err)
+	}
+
+	res := make(map[string]qualifiedConfig, len(list))
+	for _, cc := range list {
+		res[cc.Name] = cc
+	}
+
+	return res, nil
+}
+
+// changedDevices returns the names of the devices with changed configuration.
+// Adding or removing devices requires restart since loadpoint and site references are not reloaded.
+func changedDevices(old, new map[string]interface{}, section string) (map[string]qualifiedConfig, error) {
+	oldConf, err := deviceConfigs(old, section)
+	if err != nil {
+		return nil, err
+	}
+
+	newConf, err := deviceConfigs(new, section)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(oldConf) != len(newConf) {
+		return nil, fmt.Errorf("%s added or removed, %w", section, errRestartRequired)
+	}
+
+	res := make(map[string]qualifiedConfig)
+	for name, cc := range newConf {
+		prev, ok := oldConf[name]
+		if !ok {
+			return nil, fmt.Errorf("%s added or removed, %w", section, errRestartRequired)
+		}
+
+		if !reflect.DeepEqual(prev, cc) {
+			res[cc.Name] = cc
+		}
+	}
+
+	return res, nil
+}
+
+// Reload applies the changed device configuration to the running site.
+// Nothing is applied if any part of the new configuration cannot be reloaded.
+func (r *reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, err := readSettings(r.file)
+	if err != nil {
+		return fmt.Errorf("failed reading config file: %w", err)
+	}
+
+	return r.apply(settings)
+}
+
+// apply replaces the changed devices and makes the settings the new baseline, r.mu must be held
+func (r *reloader) apply(settings map[string]interface{}) error {
+	var restart []string
+	for _, section := range changedSections(r.settings, settings) {
+		if !slices.Contains(reloadableSections, section) {
+			restart = append(restart, section)
+		}
+	}
+
+	if len(restart) > 0 {
+		return fmt.Errorf("changed %s, %w", strings.Join(restart, ", "), errRestartRequired)
+	}
+
+	meters, err := changedDevices(r.settings, settings, "meters")
+	if err != nil {
+		return err
+	}
+
+	chargers, err := changedDevices(r.settings, settings, "chargers")
+	if err != nil {
+		return err
+	}
+
+	vehicles, err := changedDevices(r.settings, settings, "vehicles")
+	if err != nil {
+		return err
+	}
+
+	if len(meters)+len(chargers)+len(vehicles) == 0 {
+		log.INFO.Println("config reload: no changes")
+		return nil
+	}
+
+	rep := core.DeviceReplacement{
+		Meters:   make(map[api.Meter]api.Meter),
+		Chargers: make(map[api.Charger]api.Charger),
+		Vehicles: make(map[api.Vehicle]api.Vehicle),
+	}
+
+	// create all devices before replacing any
+	newMeters := make(map[string]api.Meter)
+	for name, cc := range meters {
+		m, err := meter.NewFromConfig(cc.Type, cc.Other)
+		if err != nil {
+			return fmt.Errorf("cannot create meter '%s': %w", name, err)
+		}
+
+		rep.Meters[cp.meters[name]] = m
+		newMeters[name] = m
+	}
+
+	newChargers := make(map[string]api.Charger)
+	for name, cc := range chargers {
+		c, err := charger.NewFromConfig(cc.Type, cc.Other)
+		if err != nil {
+			return fmt.Errorf("cannot create charger '%s': %w", name, err)
+		}
+
+		rep.Chargers[cp.chargers[name]] = c
+		newChargers[name] = c
+	}
+
+	newVehicles := make(map[string]api.Vehicle)
+	for name, cc := range vehicles {
+		v, err := newVehicle(cc)
+		if err != nil {
+			return fmt.Errorf("cannot create vehicle '%s': %w", name, err)
+		}
+
+		rep.Vehicles[cp.vehicles[name]] = v
+		newVehicles[name] = v
+	}
+
+	if err := r.site.ReplaceDevices(rep); err != nil {
+		return err
+	}
+
+	for name, m := range newMeters {
+		cp.meters[name] = m
+		metrics.Register(m, "meter", name)
+	}
+	for name, c := range newChargers {
+		cp.chargers[name] = c
+		metrics.Register(c, "charger", name)
+	}
+	for name, v := range newVehicles {
+		cp.vehicles[name] = v
+		metrics.Register(v, "vehicle", name)
+	}
+
+	r.settings = settings
+
+	replaced := append(append(maps.Keys(newMeters), maps.Keys(newChargers)...), maps.Keys(newVehicles)...)
+	sort.Strings(replaced)
+	log.INFO.Printf("config reload: replaced %s", strings.Join(replaced, ", "))
+
+	return nil
+}
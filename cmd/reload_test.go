@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedDevices(t *testing.T) {
+	old := map[string]interface{}{
+		"chargers": []interface{}{
+			map[string]interface{}{"name": "wallbox", "type": "demo", "power": 1000},
+			map[string]interface{}{"name": "garage", "type": "demo"},
+		},
+		"site": map[string]interface{}{"title": "home"},
+	}
+
+	new := map[string]interface{}{
+		"chargers": []interface{}{
+			map[string]interface{}{"name": "wallbox", "type": "demo", "power": 2000},
+			map[string]interface{}{"name": "garage", "type": "demo"},
+		},
+		"site": map[string]interface{}{"title": "home"},
+	}
+
+	assert.Equal(t, []string{"chargers"}, changedSections(old, new))
+
+	res, err := changedDevices(old, new, "chargers")
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Contains(t, res, "wallbox")
+
+	// removed device
+	new["chargers"] = new["chargers"].([]interface{})[:1]
+	_, err = changedDevices(old, new, "chargers")
+	assert.Error(t, err)
+}
+
+func TestRedactSettings(t *testing.T) {
+	cur := map[string]interface{}{
+		"vehicles": []interface{}{
+			map[string]interface{}{"name": "car", "type": "template", "user": "me", "password": "secret"},
+			map[string]interface{}{"name": "bike", "type": "template", "password": "other"},
+		},
+		"site": map[string]interface{}{"title": "home"},
+	}
+
+	res := redactSettings(cur).(map[string]interface{})
+	car := res["vehicles"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, redactReplacement, car["password"])
+	assert.Equal(t, "car", car["name"])
+	assert.Equal(t, "secret", cur["vehicles"].([]interface{})[0].(map[string]interface{})["password"], "source unchanged")
+
+	// import with reordered and changed vehicles
+	imported, err := parseSettings([]byte(`
+vehicles:
+- name: bike
+  type: template
+  password: "*****"
+- name: car
+  type: template
+  user: me
+  password: changed
+site:
+  title: home
+`))
+	require.NoError(t, err)
+
+	restored := restoreRedacted(imported, cur).(map[string]interface{})
+	vehicles := restored["vehicles"].([]interface{})
+	assert.Equal(t, "other", vehicles[0].(map[string]interface{})["password"])
+	assert.Equal(t, "changed", vehicles[1].(map[string]interface{})["password"])
+
+	// documents are written as received
+	doc := []byte("# my vehicles\nvehicles:\n  - name: bike\n    type: template\n    passWord: \"*****\" # keep\n")
+	b, err := restoreRedactedDocument(doc, cur)
+	require.NoError(t, err)
+	assert.Equal(t, "# my vehicles\nvehicles:\n  - name: bike\n    type: template\n    passWord: other # keep\n", string(b))
+
+	doc = []byte("site:\n  Title: home # comment\n")
+	b, err = restoreRedactedDocument(doc, cur)
+	require.NoError(t, err)
+	assert.Equal(t, doc, b)
+
+	assert.NoError(t, validateSettings(map[string]interface{}{"site": map[string]interface{}{"title": "home"}}))
+	assert.Error(t, validateSettings(map[string]interface{}{"foo": "bar"}))
+}
+
+func TestRedactSecrets(t *testing.T) {
+	cur, err := parseSettings([]byte(`
+auth:
+  tokens:
+  - token: reader
+  users:
+  - user: admin
+    password: admin
+fleet:
+  uri: https://fleet
+  token: fleet
+cluster:
+  role: follower
+  token: cluster
+mobile:
+  token: relay
+dashboard:
+  token: lobby
+smarthome:
+  clientId: alexa
+  clientSecret: oauth
+tariffs:
+  grid:
+    type: tibber
+    apiKey: tibber
+`))
+	require.NoError(t, err)
+
+	res := redactSettings(cur)
+
+	for _, path := range []string{
+		"auth.tokens.0.token", "auth.users.0.password", "fleet.token", "cluster.token",
+		"mobile.token", "dashboard.token", "smarthome.clientsecret", "tariffs.grid.apikey",
+	} {
+		val := res
+		for _, key := range strings.Split(path, ".") {
+			if idx, err := strconv.Atoi(key); err == nil {
+				val = val.([]interface{})[idx]
+			} else {
+				val = val.(map[string]interface{})[key]
+			}
+		}
+		assert.Equal(t, redactReplacement, val, path)
+	}
+
+	assert.Equal(t, "alexa", res.(map[string]interface{})["smarthome"].(map[string]interface{})["clientid"])
+
+	assert.Equal(t, "smarthome:\n  clientSecret: "+redactReplacement, redact("smarthome:\n  clientSecret: oauth"))
+	assert.Equal(t, "  apikey: "+redactReplacement, redact("  apikey: tibber"))
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/evcc-io/evcc/core/harness"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <snapshot.json>",
+	Short: "Replay a state snapshot against the control logic",
+	Long:  "Replay a snapshot downloaded from /api/snapshot against a simulated installation and compare recorded and simulated charging decisions",
+	Args:  cobra.ExactArgs(1),
+	Run:   runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) {
+	util.LogLevel(viper.GetString("log"), nil)
+
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// accept api response or plain snapshot
+	var res struct {
+		Result *util.Snapshot
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if res.Result == nil {
+		res.Result = new(util.Snapshot)
+		if err := json.Unmarshal(b, res.Result); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	decisions, err := harness.Replay(*res.Result)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := harness.PrintDecisions(os.Stdout, decisions); err != nil {
+		log.FATAL.Fatal(err)
+	}
+}
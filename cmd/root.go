@@ -15,9 +15,11 @@ import (
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/modbus"
 	"github.com/evcc-io/evcc/server/updater"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/experimental"
 	"github.com/evcc-io/evcc/util/pipe"
 	"github.com/evcc-io/evcc/util/sponsor"
 	"github.com/evcc-io/evcc/util/telemetry"
@@ -27,14 +29,17 @@ import (
 	"github.com/spf13/viper"
 )
 
-const rebootDelay = 5 * time.Minute // delayed reboot on error
+const (
+	rebootDelay      = 5 * time.Minute // delayed reboot on error
+	settingsInterval = time.Minute     // periodic settings persistence
+)
 
 var (
 	log     = util.NewLogger("main")
 	cfgFile string
 
-	ignoreErrors = []string{"warn", "error"}        // don't add to cache
-	ignoreMqtt   = []string{"auth", "releaseNotes"} // excessive size may crash certain brokers
+	ignoreErrors = []string{"warn", "error"}                      // don't add to cache
+	ignoreMqtt   = []string{"auth", "releaseNotes", "targetPlan"} // excessive size may crash certain brokers
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -61,6 +66,8 @@ func init() {
 	rootCmd.PersistentFlags().StringP("log", "l", "info", "Log level (fatal, error, warn, info, debug, trace)")
 	bindP(rootCmd, "log")
 
+	rootCmd.Flags().Bool(flagDemo, false, flagDemoDescription)
+
 	rootCmd.Flags().Bool("metrics", false, "Expose metrics")
 	bind(rootCmd, "metrics")
 
@@ -104,7 +111,11 @@ func Execute() {
 func runRoot(cmd *cobra.Command, args []string) {
 	// load config and re-configure logging after reading config file
 	var err error
-	if cfgErr := loadConfigFile(&conf); errors.As(cfgErr, &viper.ConfigFileNotFoundError{}) {
+	if demo, _ := cmd.Flags().GetBool(flagDemo); demo {
+		log.INFO.Println("switching into demo mode")
+		cfgFile = ""
+		demoConfig(&conf)
+	} else if cfgErr := loadConfigFile(&conf); errors.As(cfgErr, &viper.ConfigFileNotFoundError{}) {
 		log.INFO.Println("missing config file - switching into demo mode")
 		demoConfig(&conf)
 	} else {
@@ -121,14 +132,24 @@ func runRoot(cmd *cobra.Command, args []string) {
 	// start broadcasting values
 	tee := new(util.Tee)
 
-	// value cache
-	cache := util.NewCache()
-	go cache.Run(pipe.NewDropper(ignoreErrors...).Pipe(tee.Attach()))
+	// value cache, forwarding the values to the UI with the cache's sequence numbers
+	cache := util.NewCache(ignoreErrors...)
+	updates := cache.Attach()
+	go cache.Run(tee.Attach())
 
 	// create web server
 	socketHub := server.NewSocketHub()
 	httpd := server.NewHTTPd(fmt.Sprintf(":%d", conf.Network.Port), socketHub)
 
+	// api authentication
+	auth, authErr := server.NewAuth(conf.Auth)
+	if authErr != nil {
+		log.FATAL.Fatal(authErr)
+	}
+	if auth.Enabled() {
+		httpd.Router().Use(auth.Handler)
+	}
+
 	// metrics
 	if viper.GetBool("metrics") {
 		httpd.Router().Handle("/metrics", promhttp.Handler())
@@ -140,7 +161,7 @@ func runRoot(cmd *cobra.Command, args []string) {
 	}
 
 	// publish to UI
-	go socketHub.Run(tee.Attach(), cache)
+	go socketHub.Run(updates, cache)
 
 	// setup values channel
 	valueChan := make(chan util.Param)
@@ -154,6 +175,13 @@ func runRoot(cmd *cobra.Command, args []string) {
 		err = configureEnvironment(cmd, conf)
 	}
 
+	// setup experimental subsystems
+	if err == nil {
+		if err = experimental.Configure(conf.Experimental); err == nil {
+			experimental.Publish(valueChan)
+		}
+	}
+
 	// setup telemetry
 	if err == nil {
 		telemetry.Create(conf.Plant)
@@ -162,6 +190,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// setup modbus buses
+	if err == nil {
+		err = configureModbusBuses(conf.ModbusBus)
+	}
+
 	// setup modbus proxy
 	if err == nil {
 		for _, cfg := range conf.ModbusProxy {
@@ -173,9 +206,21 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	// setup site and loadpoints
 	var site *core.Site
+	var sites []*core.Site
 	if err == nil {
 		cp.TrackVisitors() // track duplicate usage
-		site, err = configureSiteAndLoadpoints(conf)
+		site, sites, err = configureSiteAndLoadpoints(conf)
+	}
+
+	// power history for the ui chart
+	if err == nil {
+		history := server.NewHistory(conf.History)
+		if conf.History.Persist && db.Instance != nil {
+			err = history.Persist(db.Instance)
+		}
+
+		go history.Run(tee.Attach(), valueChan)
+		httpd.RegisterHistoryHandler(history)
 	}
 
 	// setup database
@@ -183,15 +228,57 @@ func runRoot(cmd *cobra.Command, args []string) {
 		configureInflux(conf.Influx, site.LoadPoints(), tee.Attach())
 	}
 
+	// setup telegraf socket output
+	if err == nil && conf.Telegraf.URL != "" {
+		var telegraf *server.Telegraf
+		if telegraf, err = server.NewTelegraf(conf.Telegraf); err == nil {
+			go telegraf.Run(site.LoadPoints(), tee.Attach())
+		}
+	}
+
 	// setup mqtt publisher
 	if err == nil && conf.Mqtt.Broker != "" {
 		publisher := server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"))
 		go publisher.Run(site, pipe.NewDropper(ignoreMqtt...).Pipe(tee.Attach()))
 	}
 
+	// setup modbus server
+	if err == nil && conf.ModbusServer.Port != 0 {
+		err = modbus.StartServer(conf.ModbusServer.Port, conf.ModbusServer.Writable, site, cache)
+	}
+
+	// setup grpc api
+	if err == nil && conf.Grpc.Port != 0 {
+		grpcd := server.NewGRPC(site, cache, auth)
+		go grpcd.Run(tee.Attach())
+
+		go func() {
+			log.INFO.Printf("starting grpc api at :%d", conf.Grpc.Port)
+			log.ERROR.Println(grpcd.ListenAndServe(fmt.Sprintf(":%d", conf.Grpc.Port)))
+		}()
+	}
+
+	// setup remote management
+	if err == nil && conf.Fleet.URI != "" {
+		var fleet *server.Fleet
+		if fleet, err = server.NewFleet(conf.Fleet, site, cache); err == nil {
+			go fleet.Run(conf.Fleet.Interval)
+		}
+	}
+
+	// setup load sharing
+	if err == nil && conf.Cluster.Role != "" {
+		err = configureCluster(conf.Cluster, site, httpd)
+	}
+
 	// announce on mDNS
 	if err == nil && strings.HasSuffix(conf.Network.Host, ".local") {
-		err = configureMDNS(conf.Network)
+		err = configureMDNS(conf.Network, site.Title)
+	}
+
+	// discover devices for the setup assistant
+	if err == nil && !conf.Discovery.Disable {
+		err = configureDiscovery(conf.Discovery, conf.Network, site.Title, httpd)
 	}
 
 	// start HEMS server
@@ -199,16 +286,44 @@ func runRoot(cmd *cobra.Command, args []string) {
 		err = configureHEMS(conf.HEMS, site, httpd)
 	}
 
+	// setup read-only public dashboard
+	if err == nil && conf.Dashboard.Token != "" {
+		httpd.RegisterDashboardHandler(conf.Dashboard, cache)
+	}
+
+	// setup mobile app api with push notifications via relay
+	var senders []push.Sender
+	if err == nil && conf.Mobile.Relay != "" {
+		var mobile *server.Mobile
+		if mobile, err = server.NewMobile(conf.Mobile); err == nil {
+			httpd.RegisterMobileHandlers(mobile, site, cache)
+			senders = append(senders, mobile)
+		}
+	}
+
+	// setup smart home bridge for voice assistants, linked accounts are authorized by access token
+	if err == nil && conf.SmartHome.ClientID != "" {
+		var sh *server.SmartHome
+		if sh, err = server.NewSmartHome(conf.SmartHome); err == nil {
+			httpd.RegisterSmartHomeHandlers(sh, site)
+			auth.AddTokenSource(sh.TokenRole)
+		}
+	}
+
 	// setup messaging
 	var pushChan chan push.Event
+	var pushHub *push.Hub
 	if err == nil {
-		pushChan, err = configureMessengers(conf.Messaging, cache)
+		pushChan, pushHub, err = configureMessengers(conf.Messaging, site.LoadPoints(), cache, senders...)
 	}
 
 	// run shutdown functions on stop
 	var once sync.Once
 	stopC := make(chan struct{})
 
+	// running sites persist their state when stopped
+	var sitesWG sync.WaitGroup
+
 	// catch signals
 	go func() {
 		signalC := make(chan os.Signal, 1)
@@ -222,6 +337,13 @@ func runRoot(cmd *cobra.Command, args []string) {
 	go func() {
 		<-stopC
 
+		// sites must be stopped before settings are saved, each step is bounded by the interval
+		select {
+		case <-stoppedC(&sitesWG):
+		case <-time.After(conf.Interval):
+			log.WARN.Println("sites did not stop within", conf.Interval)
+		}
+
 		select {
 		case <-shutdownDoneC(): // wait for shutdown
 		case <-time.After(conf.Interval):
@@ -237,6 +359,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 	// show main ui
 	if err == nil {
 		httpd.RegisterSiteHandlers(site, cache)
+		httpd.RegisterDeviceHandlers(cp)
+
+		// main site is also available as site 0
+		httpd.RegisterSitesHandlers(0, site, cache)
+		httpd.RegisterSocketHub("/ws/sites/0", socketHub)
 
 		// set channels
 		site.DumpConfig()
@@ -254,9 +381,21 @@ func runRoot(cmd *cobra.Command, args []string) {
 		// allow web access for vehicles
 		cp.webControl(conf.Network, httpd.Router(), valueChan)
 
+		sitesWG.Add(1)
 		go func() {
 			site.Run(stopC, conf.Interval)
+			sitesWG.Done()
 		}()
+
+		// additional sites
+		for i, s := range sites {
+			runSite(i+1, s, httpd, pushHub, stopC, &sitesWG, conf.Interval)
+		}
+
+		// reload changed devices on SIGHUP or api request
+		if cfgFile != "" {
+			configureReload(cfgFile, site, httpd)
+		}
 	} else {
 		httpd.RegisterShutdownHandler(func() {
 			log.FATAL.Println("evcc was stopped. OS should restart the service. Or restart manually.")
@@ -279,3 +418,33 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	log.FATAL.Println(httpd.ListenAndServe())
 }
+
+// runSite starts an additional site with its own cache, ui socket, api and messaging
+func runSite(id int, site *core.Site, httpd *server.HTTPd, pushHub *push.Hub, stopC chan struct{}, wg *sync.WaitGroup, interval time.Duration) {
+	tee := new(util.Tee)
+
+	cache := util.NewCache(ignoreErrors...)
+	updates := cache.Attach()
+	go cache.Run(tee.Attach())
+
+	socketHub := server.NewSocketHub()
+	go socketHub.Run(updates, cache)
+	httpd.RegisterSocketHub(fmt.Sprintf("/ws/sites/%d", id), socketHub)
+
+	valueChan := make(chan util.Param)
+	go tee.Run(valueChan)
+
+	httpd.RegisterSitesHandlers(id, site, cache)
+
+	pushChan := make(chan push.Event, 1)
+	go pushHub.WithCache(cache).Run(pushChan)
+
+	site.DumpConfig()
+	site.Prepare(valueChan, pushChan)
+
+	wg.Add(1)
+	go func() {
+		site.Run(stopC, interval)
+		wg.Done()
+	}()
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/forecast"
 	"github.com/evcc-io/evcc/hems"
 	"github.com/evcc-io/evcc/provider/javascript"
 	"github.com/evcc-io/evcc/provider/mqtt"
@@ -23,6 +24,7 @@ import (
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/evcc-io/evcc/util/machine"
+	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/pipe"
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/util/sponsor"
@@ -110,11 +112,24 @@ func configureDatabase(conf dbConfig) error {
 	err := db.NewInstance(conf.Type, conf.Dsn)
 	if err == nil {
 		if err = settings.Init(); err == nil {
+			if err := server.RestoreLanguage(); err != nil {
+				log.WARN.Println("language:", err)
+			}
+
 			shutdown.Register(func() {
 				if err := settings.Persist(); err != nil {
 					log.ERROR.Println("cannot save settings:", err)
 				}
 			})
+
+			// limit loss of transient state on crash
+			go func() {
+				for range time.Tick(settingsInterval) {
+					if err := settings.Persist(); err != nil {
+						log.ERROR.Println("cannot save settings:", err)
+					}
+				}
+			}()
 		}
 	}
 	return err
@@ -122,14 +137,7 @@ func configureDatabase(conf dbConfig) error {
 
 // configureInflux configures influx database
 func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, in <-chan util.Param) {
-	influx := server.NewInfluxClient(
-		conf.URL,
-		conf.Token,
-		conf.Org,
-		conf.User,
-		conf.Password,
-		conf.Database,
-	)
+	influx := server.NewInfluxClient(conf)
 
 	// eliminate duplicate values
 	dedupe := pipe.NewDeduplicator(30*time.Minute, "vehicleCapacity", "vehicleSoC", "vehicleRange", "vehicleOdometer", "chargedEnergy", "chargeRemainingEnergy")
@@ -175,7 +183,7 @@ func configureHEMS(conf typedConfig, site *core.Site, httpd *server.HTTPd) error
 }
 
 // setup MDNS
-func configureMDNS(conf networkConfig) error {
+func configureMDNS(conf networkConfig, title string) error {
 	host := strings.TrimSuffix(conf.Host, ".local")
 
 	zc, err := zeroconf.RegisterProxy("EV Charge Controller", "_http._tcp", "local.", conf.Port, host, nil, []string{}, nil)
@@ -185,6 +193,95 @@ func configureMDNS(conf networkConfig) error {
 
 	shutdown.Register(zc.Shutdown)
 
+	// evcc service for app and integration discovery
+	if title == "" {
+		title = "evcc"
+	}
+
+	txt := []string{
+		"version=" + server.FormattedVersion(),
+		"title=" + title,
+		"schema=" + conf.Schema,
+		"path=/api",
+		"websocket=/ws",
+	}
+
+	zc, err = zeroconf.RegisterProxy(title, "_evcc._tcp", "local.", conf.Port, host, nil, txt, nil)
+	if err != nil {
+		return fmt.Errorf("mDNS announcement: %w", err)
+	}
+
+	shutdown.Register(zc.Shutdown)
+
+	return nil
+}
+
+// configureDiscovery starts the lan device discovery and announces the evcc api if not yet announced by host name
+func configureDiscovery(conf server.DiscoveryConfig, network networkConfig, title string, httpd *server.HTTPd) error {
+	if !strings.HasSuffix(network.Host, ".local") {
+		if title == "" {
+			title = "evcc"
+		}
+
+		txt := []string{
+			"version=" + server.FormattedVersion(),
+			"title=" + title,
+			"schema=" + network.Schema,
+			"path=/api",
+			"websocket=/ws",
+		}
+
+		zc, err := zeroconf.Register(title, "_evcc._tcp", "local.", network.Port, txt, nil)
+		if err != nil {
+			return fmt.Errorf("mDNS announcement: %w", err)
+		}
+
+		shutdown.Register(zc.Shutdown)
+	}
+
+	discovery := server.NewDiscovery()
+	httpd.RegisterDiscoveryHandler(discovery)
+
+	go discovery.Run(conf.Interval)
+
+	return nil
+}
+
+// configureCluster shares the charge power budget with other instances as leader or follower
+func configureCluster(conf server.ClusterConfig, site *core.Site, httpd *server.HTTPd) error {
+	switch strings.ToLower(conf.Role) {
+	case "leader":
+		leader, err := server.NewClusterLeader(conf, site)
+		if err != nil {
+			return fmt.Errorf("cluster: %w", err)
+		}
+
+		httpd.RegisterClusterHandler(leader)
+		go leader.Run(conf.Interval)
+
+	case "follower":
+		follower, err := server.NewClusterFollower(conf, site)
+		if err != nil {
+			return fmt.Errorf("cluster: %w", err)
+		}
+
+		go follower.Run(conf.Interval)
+
+	default:
+		return fmt.Errorf("cluster: invalid role: %s", conf.Role)
+	}
+
+	return nil
+}
+
+// setup shared modbus buses
+func configureModbusBuses(conf []modbus.BusConfig) error {
+	for _, cc := range conf {
+		if err := modbus.ConfigureBus(cc); err != nil {
+			return fmt.Errorf("modbus: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -202,42 +299,93 @@ func configureEEBus(conf map[string]interface{}) error {
 }
 
 // setup messaging
-func configureMessengers(conf messagingConfig, cache *util.Cache) (chan push.Event, error) {
+func configureMessengers(conf messagingConfig, loadpoints []loadpoint.API, cache *util.Cache, senders ...push.Sender) (chan push.Event, *push.Hub, error) {
 	messageChan := make(chan push.Event, 1)
 
 	messageHub, err := push.NewHub(conf.Events, cache)
 	if err != nil {
-		return messageChan, fmt.Errorf("failed configuring push services: %w", err)
+		return messageChan, nil, fmt.Errorf("failed configuring push services: %w", err)
 	}
 
 	for _, service := range conf.Services {
+		// recipient language and channel name apply to all service types
+		lang, _ := service.Other["language"].(string)
+		delete(service.Other, "language")
+
+		name, _ := service.Other["name"].(string)
+		delete(service.Other, "name")
+		if name == "" {
+			name = strings.ToLower(service.Type)
+		}
+
 		impl, err := push.NewMessengerFromConfig(service.Type, service.Other)
 		if err != nil {
-			return messageChan, fmt.Errorf("failed configuring push service %s: %w", service.Type, err)
+			return messageChan, nil, fmt.Errorf("failed configuring push service %s: %w", service.Type, err)
+		}
+
+		// allow controlling loadpoints from messenger
+		if ctrl, ok := impl.(push.Controller); ok {
+			ctrl.LoadpointControl(loadpoints)
 		}
+
+		if lang != "" {
+			impl = push.WithLanguage(impl, lang)
+		}
+
+		messageHub.AddChannel(name, impl)
+	}
+
+	for _, impl := range senders {
 		messageHub.Add(impl)
 	}
 
 	go messageHub.Run(messageChan)
 
-	return messageChan, nil
+	return messageChan, messageHub, nil
 }
 
-func configureTariffs(conf tariffConfig) (tariff.Tariffs, error) {
-	var grid, feedin api.Tariff
-	var currencyCode currency.Unit = currency.EUR
-	var err error
+// configureCurrency returns the site currency, falling back to the deprecated tariffs currency
+func configureCurrency(conf config) (currency.Unit, error) {
+	code, _ := conf.Site["currency"].(string)
+
+	if conf.Tariffs.Currency != "" {
+		if code == "" {
+			log.WARN.Println("`tariffs.currency` is deprecated, use `site.currency` instead")
+			code = conf.Tariffs.Currency
+		} else if !strings.EqualFold(code, conf.Tariffs.Currency) {
+			return currency.EUR, fmt.Errorf("tariffs currency %s does not match site currency %s", conf.Tariffs.Currency, code)
+		}
+	}
 
-	if conf.Currency != "" {
-		currencyCode = currency.MustParseISO(conf.Currency)
+	if code == "" {
+		return currency.EUR, nil
 	}
 
+	res, err := currency.ParseISO(code)
+	if err != nil {
+		err = fmt.Errorf("invalid currency: %w", err)
+	}
+
+	return res, err
+}
+
+func configureTariffs(conf tariffConfig, currencyCode currency.Unit) (tariff.Tariffs, error) {
+	var grid, feedin api.Tariff
+	var err error
+
+	// prices are converted if the provider reports in a different currency
 	if conf.Grid.Type != "" {
-		grid, err = tariff.NewFromConfig(conf.Grid.Type, conf.Grid.Other)
+		grid, err = tariff.NewConvertedFromConfig(conf.Grid.Type, conf.Grid.Other, currencyCode)
 	}
 
 	if err == nil && conf.FeedIn.Type != "" {
-		feedin, err = tariff.NewFromConfig(conf.FeedIn.Type, conf.FeedIn.Other)
+		feedin, err = tariff.NewConvertedFromConfig(conf.FeedIn.Type, conf.FeedIn.Other, currencyCode)
+	}
+
+	// carbon intensity is not a price
+	var co2 api.Tariff
+	if err == nil && conf.Co2.Type != "" {
+		co2, err = tariff.NewFromConfig(conf.Co2.Type, conf.Co2.Other)
 	}
 
 	if err != nil {
@@ -245,39 +393,105 @@ func configureTariffs(conf tariffConfig) (tariff.Tariffs, error) {
 	}
 
 	tariffs := tariff.NewTariffs(currencyCode, grid, feedin)
+	tariffs.Co2 = co2
 
 	return *tariffs, err
 }
 
-func configureSiteAndLoadpoints(conf config) (site *core.Site, err error) {
+func configureForecast(conf typedConfig) (api.Rater, error) {
+	if conf.Type == "" {
+		return nil, nil
+	}
+
+	res, err := forecast.NewFromConfig(conf.Type, conf.Other)
+	if err != nil {
+		err = fmt.Errorf("failed configuring forecast: %w", err)
+	}
+
+	return res, err
+}
+
+// configureSiteAndLoadpoints creates the main site and the additional sites sharing tariffs and forecast
+func configureSiteAndLoadpoints(conf config) (site *core.Site, sites []*core.Site, err error) {
 	if err = cp.configure(conf); err == nil {
 		var loadPoints []*core.LoadPoint
 		loadPoints, err = configureLoadPoints(conf, cp)
 
+		var currencyCode currency.Unit
+		if err == nil {
+			currencyCode, err = configureCurrency(conf)
+		}
+
 		var tariffs tariff.Tariffs
 		if err == nil {
-			tariffs, err = configureTariffs(conf.Tariffs)
+			tariffs, err = configureTariffs(conf.Tariffs, currencyCode)
 		}
 
+		var forecast api.Rater
 		if err == nil {
-			// list of vehicles
-			vehicles := lo.MapToSlice(cp.vehicles, func(_ string, v api.Vehicle) api.Vehicle {
-				return v
-			})
+			forecast, err = configureForecast(conf.Forecast)
+		}
+
+		// list of vehicles
+		vehicles := lo.MapToSlice(cp.vehicles, func(_ string, v api.Vehicle) api.Vehicle {
+			return v
+		})
+
+		if err == nil {
+			site, err = configureSite(conf.Site, cp, loadPoints, vehicles, tariffs, forecast)
+		}
+
+		if err == nil {
+			sites, err = configureSites(cp, vehicles, tariffs, forecast)
+		}
+	}
+
+	return site, sites, err
+}
+
+// configureSites creates the additional sites, each with its own loadpoints
+func configureSites(cp *ConfigProvider, vehicles []api.Vehicle, tariffs tariff.Tariffs, forecast api.Rater) ([]*core.Site, error) {
+	siteInterfaces, _ := viper.AllSettings()["sites"].([]interface{})
 
-			site, err = configureSite(conf.Site, cp, loadPoints, vehicles, tariffs)
+	var res []*core.Site
+	for id, scI := range siteInterfaces {
+		var sc map[string]interface{}
+		if err := util.DecodeOther(scI, &sc); err != nil {
+			return nil, fmt.Errorf("failed decoding site %d configuration: %w", id+1, err)
 		}
+
+		lpInterfaces, ok := sc["loadpoints"].([]interface{})
+		if !ok || len(lpInterfaces) == 0 {
+			return nil, fmt.Errorf("site %d: missing loadpoints", id+1)
+		}
+		delete(sc, "loadpoints")
+
+		loadPoints, err := newLoadPoints(cp, lpInterfaces, fmt.Sprintf("site%d-lp-", id+1))
+		if err != nil {
+			return nil, fmt.Errorf("site %d: %w", id+1, err)
+		}
+
+		site, err := configureSite(sc, cp, loadPoints, vehicles, tariffs, forecast)
+		if err != nil {
+			return nil, fmt.Errorf("site %d: %w", id+1, err)
+		}
+
+		site.SetSettingsPrefix(fmt.Sprintf("site%d.", id+1))
+
+		res = append(res, site)
 	}
 
-	return site, err
+	return res, nil
 }
 
-func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, vehicles []api.Vehicle, tariffs tariff.Tariffs) (*core.Site, error) {
-	site, err := core.NewSiteFromConfig(log, cp, conf, loadPoints, vehicles, tariffs)
+func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, vehicles []api.Vehicle, tariffs tariff.Tariffs, forecast api.Rater) (*core.Site, error) {
+	site, err := core.NewSiteFromConfig(log, cp, conf, loadPoints, vehicles, tariffs, forecast)
 	if err != nil {
 		return nil, fmt.Errorf("failed configuring site: %w", err)
 	}
 
+	site.PersistState()
+
 	return site, nil
 }
 
@@ -287,13 +501,18 @@ func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.Lo
 		return nil, errors.New("missing loadpoints")
 	}
 
+	return newLoadPoints(cp, lpInterfaces, "lp-")
+}
+
+// newLoadPoints creates the loadpoints, logging areas are numbered using the prefix
+func newLoadPoints(cp *ConfigProvider, lpInterfaces []interface{}, prefix string) (loadPoints []*core.LoadPoint, err error) {
 	for id, lpcI := range lpInterfaces {
 		var lpc map[string]interface{}
 		if err := util.DecodeOther(lpcI, &lpc); err != nil {
 			return nil, fmt.Errorf("failed decoding loadpoint configuration: %w", err)
 		}
 
-		log := util.NewLogger("lp-" + strconv.Itoa(id+1))
+		log := util.NewLogger(prefix + strconv.Itoa(id+1))
 		lp, err := core.NewLoadPointFromConfig(log, cp, lpc)
 		if err != nil {
 			return nil, fmt.Errorf("failed configuring loadpoint: %w", err)
@@ -5,14 +5,22 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
+	"unicode"
 
 	combinations "github.com/mxschmitt/golang-combinations"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed decorate.tpl
@@ -23,10 +31,189 @@ type dynamicType struct {
 }
 
 type typeStruct struct {
-	Type, ShortType, Signature, Function, VarName string
+	Type, ShortType string
+	Functions       []funcStruct
 }
 
-func generate(out io.Writer, packageName, functionName, baseType string, dynamicTypes ...dynamicType) error {
+type funcStruct struct {
+	Signature, Function, VarName, Params string
+}
+
+// capabilityStruct is the bit set when all functions of an optional interface are provided
+type capabilityStruct struct {
+	Mask int
+	Vars []string
+}
+
+// caseStruct is the decorated type of a capability set
+type caseStruct struct {
+	Mask  int
+	Combo []string
+}
+
+// manifest is the yaml representation of the command line flags
+type manifest struct {
+	Package  string
+	Function string
+	Base     string
+	Return   string
+	Out      string
+	Types    []string // comma-separated type definitions
+}
+
+// firstWord returns the leading camel case word of name
+func firstWord(name string) string {
+	for i := 1; i < len(name); i++ {
+		if unicode.IsUpper(rune(name[i])) {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+// paramNames returns the comma-separated parameter names of a named function signature
+func paramNames(signature string) string {
+	params := strings.TrimPrefix(signature, "func(")
+	if i := strings.Index(params, ")"); i >= 0 {
+		params = params[:i]
+	}
+
+	var res []string
+	for _, p := range strings.Split(params, ",") {
+		if fields := strings.Fields(p); len(fields) > 1 {
+			res = append(res, fields[0])
+		}
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// apiDir locates the api package by walking up to the module root
+func apiDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, "api"), nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("module root not found")
+		}
+		dir = parent
+	}
+}
+
+// apiInterfaces parses the api package and returns its interface definitions
+func apiInterfaces() (map[string]*ast.InterfaceType, *token.FileSet, error) {
+	dir, err := apiDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make(map[string]*ast.InterfaceType)
+	for _, pkg := range pkgs {
+		ast.Inspect(pkg, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					res[ts.Name.Name] = it
+				}
+			}
+			return true
+		})
+	}
+
+	return res, fset, nil
+}
+
+// qualify prefixes exported api types with the package name
+func qualify(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("api"), Sel: t}
+		}
+	case *ast.ArrayType:
+		t.Elt = qualify(t.Elt)
+	case *ast.StarExpr:
+		t.X = qualify(t.X)
+	case *ast.MapType:
+		t.Key, t.Value = qualify(t.Key), qualify(t.Value)
+	case *ast.FuncType:
+		for _, fl := range []*ast.FieldList{t.Params, t.Results} {
+			if fl != nil {
+				for _, f := range fl.List {
+					f.Type = qualify(f.Type)
+				}
+			}
+		}
+	}
+	return expr
+}
+
+// apiSignature returns function name and named signature of an api interface's method.
+// If function is empty, the interface must define a single method.
+func apiSignature(typ, function string) (string, string, error) {
+	name := strings.TrimPrefix(typ, "api.")
+	if name == typ {
+		return "", "", fmt.Errorf("not an api type: %s", typ)
+	}
+
+	interfaces, fset, err := apiInterfaces()
+	if err != nil {
+		return "", "", err
+	}
+
+	it, ok := interfaces[name]
+	if !ok {
+		return "", "", fmt.Errorf("interface not found: %s", typ)
+	}
+
+	var methods []*ast.Field
+	for _, m := range it.Methods.List {
+		if _, ok := m.Type.(*ast.FuncType); ok && len(m.Names) > 0 {
+			methods = append(methods, m)
+		}
+	}
+
+	if function == "" {
+		if len(methods) != 1 {
+			return "", "", fmt.Errorf("interface %s must have a single method", typ)
+		}
+		function = methods[0].Names[0].Name
+	}
+
+	for _, m := range methods {
+		if m.Names[0].Name != function {
+			continue
+		}
+
+		ft := qualify(m.Type).(*ast.FuncType)
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, ft); err != nil {
+			return "", "", err
+		}
+
+		return function, buf.String(), nil
+	}
+
+	return "", "", fmt.Errorf("method %s not found: %s", function, typ)
+}
+
+func generate(out io.Writer, packageName, functionName, baseType, returnType string, dynamicTypes ...dynamicType) error {
 	types := make(map[string]typeStruct, len(dynamicTypes))
 	combos := make([]string, 0)
 
@@ -46,6 +233,8 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 			}
 			return dict, nil
 		},
+		// join concatenates the strings
+		"join": strings.Join,
 		// contains checks if slice contains string
 		"contains": func(combo []string, typ string) bool {
 			for _, v := range combo {
@@ -56,10 +245,10 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 			return false
 		},
 		// ordered checks if slice ordered string
-		"ordered": func() []typeStruct {
-			ordered := make([]typeStruct, 0)
-			for _, k := range dynamicTypes {
-				ordered = append(ordered, types[k.typ])
+		"ordered": func() []funcStruct {
+			ordered := make([]funcStruct, 0)
+			for _, k := range combos {
+				ordered = append(ordered, types[k].Functions...)
 			}
 
 			return ordered
@@ -73,18 +262,58 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 	for _, dt := range dynamicTypes {
 		parts := strings.SplitN(dt.typ, ".", 2)
 
-		types[dt.typ] = typeStruct{
-			Type:      dt.typ,
-			ShortType: parts[1],
+		ts, ok := types[dt.typ]
+		if !ok {
+			ts = typeStruct{
+				Type:      dt.typ,
+				ShortType: parts[1],
+			}
+			combos = append(combos, dt.typ)
+		}
+
+		ts.Functions = append(ts.Functions, funcStruct{
 			VarName:   strings.ToLower(parts[1][:1]) + parts[1][1:],
 			Signature: dt.signature,
 			Function:  dt.function,
+			Params:    paramNames(dt.signature),
+		})
+
+		// interfaces with multiple functions use the type's first word and the function name as variable names
+		if len(ts.Functions) > 1 {
+			prefix := strings.ToLower(firstWord(ts.ShortType))
+			for i := range ts.Functions {
+				ts.Functions[i].VarName = prefix + ts.Functions[i].Function
+			}
 		}
 
-		combos = append(combos, dt.typ)
+		types[dt.typ] = ts
+	}
+
+	// one bit per optional interface in argument order
+	capabilities := make([]capabilityStruct, 0, len(combos))
+	for i, typ := range combos {
+		c := capabilityStruct{Mask: 1 << i}
+		for _, f := range types[typ].Functions {
+			c.Vars = append(c.Vars, f.VarName)
+		}
+		capabilities = append(capabilities, c)
+	}
+
+	cases := make([]caseStruct, 0, 1<<len(combos))
+	for _, combo := range combinations.All(combos) {
+		c := caseStruct{Combo: combo}
+		for i, typ := range combos {
+			if slices.Contains(combo, typ) {
+				c.Mask |= 1 << i
+			}
+		}
+		cases = append(cases, c)
 	}
 
-	returnType := *ret
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].Mask < cases[j].Mask
+	})
+
 	if returnType == "" {
 		returnType = baseType
 	}
@@ -100,7 +329,8 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 		BaseType, ShortBase string
 		ReturnType          string
 		Types               map[string]typeStruct
-		Combinations        [][]string
+		Capabilities        []capabilityStruct
+		Cases               []caseStruct
 	}{
 		API:          "github.com/evcc-io/evcc/api",
 		Package:      packageName,
@@ -109,7 +339,8 @@ func generate(out io.Writer, packageName, functionName, baseType string, dynamic
 		ShortBase:    shortBase,
 		ReturnType:   returnType,
 		Types:        types,
-		Combinations: combinations.All(combos),
+		Capabilities: capabilities,
+		Cases:        cases,
 	}
 
 	return tmpl.Execute(out, vars)
@@ -122,20 +353,57 @@ var (
 	base     = pflag.StringP("base", "b", "", "base type")
 	ret      = pflag.StringP("return", "r", "", "return type")
 	types    = pflag.StringArrayP("type", "t", nil, "comma-separated list of type definitions")
+	config   = pflag.StringP("config", "c", "", "yaml manifest replacing the flags")
 )
 
 // Usage prints flags usage
 func Usage() {
 	fmt.Fprintf(os.Stderr, "Usage of decorate:\n")
-	fmt.Fprintf(os.Stderr, "\ndecorate [flags] -type interface,interface function,function signature\n")
+	fmt.Fprintf(os.Stderr, "\ndecorate [flags] -type interface[,interface function[,function signature]]\n")
+	fmt.Fprintf(os.Stderr, "decorate -config manifest.yaml\n")
 	fmt.Fprintf(os.Stderr, "\nFlags:\n")
 	pflag.PrintDefaults()
 }
 
+// readManifest applies the manifest's values to the flags
+func readManifest(name string) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	var m manifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for _, v := range []struct {
+		flag *string
+		val  string
+	}{
+		{pkg, m.Package}, {function, m.Function}, {base, m.Base}, {ret, m.Return}, {target, m.Out},
+	} {
+		if v.val != "" {
+			*v.flag = v.val
+		}
+	}
+
+	*types = append(*types, m.Types...)
+
+	return nil
+}
+
 func main() {
 	pflag.Usage = Usage
 	pflag.Parse()
 
+	if *config != "" {
+		if err := readManifest(*config); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
 	// read target from go:generate
 	if gopkg, ok := os.LookupEnv("GOPACKAGE"); *pkg == "" && ok {
 		pkg = &gopkg
@@ -149,12 +417,25 @@ func main() {
 	var dynamicTypes []dynamicType
 	for _, v := range *types {
 		split := strings.SplitN(v, ",", 3)
+		for len(split) < 3 {
+			split = append(split, "")
+		}
+
 		dt := dynamicType{split[0], split[1], split[2]}
+
+		// use named signature from api interface definition if available
+		if function, signature, err := apiSignature(dt.typ, dt.function); err == nil {
+			dt.function, dt.signature = function, signature
+		} else if dt.function == "" || dt.signature == "" {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
 		dynamicTypes = append(dynamicTypes, dt)
 	}
 
 	var buf bytes.Buffer
-	if err := generate(&buf, *pkg, *function, *base, dynamicTypes...); err != nil {
+	if err := generate(&buf, *pkg, *function, *base, *ret, dynamicTypes...); err != nil {
 		fmt.Println(err)
 		os.Exit(2)
 	}
@@ -168,7 +449,7 @@ func main() {
 		target = &gofile
 	}
 
-	if target != nil {
+	if *target != "" {
 		name := *target
 		if !strings.HasSuffix(name, ".go") {
 			name += ".go"
@@ -9,11 +9,6 @@ import (
 {{define "case"}}
 	{{- $combo := .Combo}}
 	{{- $prefix := .Prefix}}
-	{{- $idx := 0}}
-
-	{{- range $typ, $def := .Types}}
-		{{- if gt $idx 0}} &&{{else}}{{$idx = 1}}{{end}} {{$def.VarName}} {{if contains $combo $typ}}!={{else}}=={{end}} nil
-	{{- end}}:
 		return &struct {
 			{{.BaseType}}
 {{- range $typ, $def := .Types}}
@@ -26,38 +21,48 @@ import (
 {{- range $typ, $def := .Types}}
 	{{- if contains $combo $typ}}
 			{{$def.ShortType}}: &{{$prefix}}{{$def.ShortType}}Impl{
-				{{$def.VarName}}: {{$def.VarName}},
+		{{- range $def.Functions}}
+				{{.VarName}}: {{.VarName}},
+		{{- end}}
 			},
 	{{- end}}
 {{- end}}
 		}
 {{- end -}}
 
-func {{.Function}}(base {{.BaseType}}{{range ordered}}, {{.VarName}} func() {{slice .Signature 7}}{{end}}) {{.ReturnType}} {
+func {{.Function}}(base {{.BaseType}}{{range ordered}}, {{.VarName}} {{.Signature}}{{end}}) {{.ReturnType}} {
 {{- $basetype := .BaseType}}
 {{- $shortbase := .ShortBase}}
 {{- $prefix := .Function}}
 {{- $types := .Types}}
-{{- $idx := 0}}
-	switch {
-	case {{- range $typ, $def := .Types}}
-		{{- if gt $idx 0}} &&{{else}}{{$idx = 1}}{{end}} {{$def.VarName}} == nil
-	{{- end}}:
+	var caps int
+{{- range .Capabilities}}
+	if {{range $i, $v := .Vars}}{{if $i}} && {{end}}{{$v}} != nil{{end}} {
+		caps |= {{.Mask}}
+	}
+{{- end}}
+
+	switch caps {
+	case 0:
 		return base
-{{range $combo := .Combinations}}
-	case {{- template "case" dict "BaseType" $basetype "Prefix" $prefix "ShortBase" $shortbase "Types" $types "Combo" $combo}}
+{{range .Cases}}
+	case {{.Mask}}: // {{join .Combo ", "}}
+	{{- template "case" dict "BaseType" $basetype "Prefix" $prefix "ShortBase" $shortbase "Types" $types "Combo" .Combo}}
 {{end}}	}
 
 	return nil
 }
 
 {{range .Types -}}
-type {{$prefix}}{{.ShortType}}Impl struct {
+{{- $short := .ShortType}}
+type {{$prefix}}{{$short}}Impl struct {
+{{- range .Functions}}
 	{{.VarName}} {{.Signature}}
+{{- end}}
 }
-
-func (impl *{{$prefix}}{{.ShortType}}Impl) {{.Function}}{{slice .Signature 4}} {
-	return impl.{{.VarName}}()
+{{range .Functions}}
+func (impl *{{$prefix}}{{$short}}Impl) {{.Function}}{{slice .Signature 4}} {
+	return impl.{{.VarName}}({{.Params}})
 }
-
+{{end}}
 {{end}}
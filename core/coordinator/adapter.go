@@ -6,16 +6,18 @@ import (
 )
 
 type adapter struct {
-	lp loadpoint.API
-	c  *Coordinator
+	lp        loadpoint.API
+	c         *Coordinator
+	detection *detection
 }
 
 // NewAdapter exposes the coordinator for a given loadpoint.
 // Using an adapter simplifies the method signatures seen from the loadpoint.
 func NewAdapter(lp loadpoint.API, c *Coordinator) API {
 	return &adapter{
-		lp: lp,
-		c:  c,
+		lp:        lp,
+		c:         c,
+		detection: newDetection(),
 	}
 }
 
@@ -31,7 +33,12 @@ func (a *adapter) Release(v api.Vehicle) {
 	a.c.release(v)
 }
 
-func (a *adapter) IdentifyVehicleByStatus(includeIdCapable bool) api.Vehicle {
+func (a *adapter) IdentifyVehicleByStatus(includeIdCapable, charging bool) api.Vehicle {
 	available := a.c.availableDetectibleVehicles(a.lp, includeIdCapable)
-	return a.c.identifyVehicleByStatus(available)
+	a.detection.charging = charging
+	return a.c.identifyVehicleByStatus(available, a.detection)
+}
+
+func (a *adapter) AtSite(v api.Vehicle) bool {
+	return a.c.geofence.contains(a.c.log, v)
 }
@@ -7,5 +7,6 @@ type API interface {
 	GetVehicles() []api.Vehicle
 	Acquire(api.Vehicle)
 	Release(api.Vehicle)
-	IdentifyVehicleByStatus(includeIdCapable bool) api.Vehicle
+	IdentifyVehicleByStatus(includeIdCapable, charging bool) api.Vehicle
+	AtSite(api.Vehicle) bool
 }
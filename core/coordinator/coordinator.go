@@ -11,6 +11,7 @@ type Coordinator struct {
 	log      *util.Logger
 	vehicles []api.Vehicle
 	tracked  map[api.Vehicle]loadpoint.API
+	geofence *Geofence
 }
 
 // New creates a coordinator for a set of vehicles
@@ -22,10 +23,29 @@ func New(log *util.Logger, vehicles []api.Vehicle) *Coordinator {
 	}
 }
 
+// SetGeofence sets the site location excluding vehicles parked elsewhere from detection
+func (c *Coordinator) SetGeofence(geofence *Geofence) {
+	c.geofence = geofence
+}
+
 func (c *Coordinator) GetVehicles() []api.Vehicle {
 	return c.vehicles
 }
 
+// Replace replaces a reconfigured vehicle keeping its loadpoint association
+func (c *Coordinator) Replace(old, new api.Vehicle) {
+	for i, v := range c.vehicles {
+		if v == old {
+			c.vehicles[i] = new
+		}
+	}
+
+	if owner, ok := c.tracked[old]; ok {
+		delete(c.tracked, old)
+		c.tracked[new] = owner
+	}
+}
+
 func (c *Coordinator) acquire(owner loadpoint.API, vehicle api.Vehicle) {
 	if o, ok := c.tracked[vehicle]; ok && o != owner {
 		o.SetVehicle(nil)
@@ -58,9 +78,35 @@ func (c *Coordinator) availableDetectibleVehicles(owner loadpoint.API, includeId
 	return res
 }
 
-// identifyVehicleByStatus finds active vehicle by charge state
-func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle) api.Vehicle {
-	var res api.Vehicle
+// filter keeps the candidates matching the heuristic unless none matches
+func (c *Coordinator) filter(candidates []api.Vehicle, heuristic string, match func(api.Vehicle) bool) []api.Vehicle {
+	if len(candidates) < 2 {
+		return candidates
+	}
+
+	var res []api.Vehicle
+	for _, v := range candidates {
+		if match(v) {
+			res = append(res, v)
+		}
+	}
+
+	if len(res) == 0 {
+		return candidates
+	}
+
+	c.log.DEBUG.Printf("vehicle detection: %d of %d candidates match %s", len(res), len(candidates), heuristic)
+
+	return res
+}
+
+// identifyVehicleByStatus finds active vehicle by charge state.
+// Vehicles parked outside the geofence are excluded, e.g. when charging at a public charger.
+// Multiple plugged vehicles are disambiguated by charging status and soc increase.
+func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle, d *detection) api.Vehicle {
+	var candidates []api.Vehicle
+	charging := make(map[api.Vehicle]bool)
+
 	for _, vehicle := range available {
 		if vs, ok := vehicle.(api.ChargeState); ok {
 			status, err := vs.Status()
@@ -71,17 +117,40 @@ func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle) api.Vehic
 
 			c.log.DEBUG.Printf("vehicle status: %s (%s)", status, vehicle.Title())
 
-			// vehicle is plugged or charging, so it should be the right one
+			// vehicle is plugged or charging, so it may be the right one
 			if status == api.StatusB || status == api.StatusC {
-				if res != nil {
-					c.log.WARN.Println("vehicle status: >1 matches, giving up")
-					return nil
+				if !c.geofence.contains(c.log, vehicle) {
+					c.log.DEBUG.Printf("vehicle status: outside geofence (%s)", vehicle.Title())
+					continue
 				}
 
-				res = vehicle
+				candidates = append(candidates, vehicle)
+				charging[vehicle] = status == api.StatusC
 			}
 		}
 	}
 
-	return res
+	if d != nil && len(candidates) > 1 {
+		if !d.charging {
+			d.record(c.log, candidates)
+		} else {
+			candidates = c.filter(candidates, "charging", func(v api.Vehicle) bool {
+				return charging[v]
+			})
+
+			candidates = c.filter(candidates, "soc", func(v api.Vehicle) bool {
+				return d.socIncreased(c.log, v)
+			})
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil
+	case 1:
+		return candidates[0]
+	default:
+		c.log.WARN.Println("vehicle status: >1 matches, giving up")
+		return nil
+	}
 }
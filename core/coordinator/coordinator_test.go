@@ -53,7 +53,7 @@ func TestVehicleDetectByStatus(t *testing.T) {
 		v2.MockChargeState.EXPECT().Status().Return(tc.v2, nil)
 
 		available := c.availableDetectibleVehicles(lp, true) // include id-able vehicles
-		res := c.identifyVehicleByStatus(available)
+		res := c.identifyVehicleByStatus(available, nil)
 		if tc.res != res {
 			t.Errorf("expected %v, got %v", tc.res, res)
 		}
@@ -65,3 +65,84 @@ func TestVehicleDetectByStatus(t *testing.T) {
 		}
 	}
 }
+
+type positionVehicle struct {
+	*mock.MockVehicle
+	*mock.MockChargeState
+	lat, lon float64
+}
+
+func (v *positionVehicle) Position() (float64, float64, error) {
+	return v.lat, v.lon, nil
+}
+
+func TestVehicleDetectHeuristics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	type vehicle struct {
+		*mock.MockVehicle
+		*mock.MockChargeState
+	}
+
+	v1 := &vehicle{mock.NewMockVehicle(ctrl), mock.NewMockChargeState(ctrl)}
+	v2 := &positionVehicle{MockVehicle: mock.NewMockVehicle(ctrl), MockChargeState: mock.NewMockChargeState(ctrl)}
+
+	v1.MockVehicle.EXPECT().Title().Return("v1").AnyTimes()
+	v2.MockVehicle.EXPECT().Title().Return("v2").AnyTimes()
+
+	log := util.NewLogger("foo")
+	vehicles := []api.Vehicle{v1, v2}
+	c := New(log, vehicles)
+
+	// both plugged, v2 parked elsewhere
+	c.SetGeofence(&Geofence{Lat: 52.52, Lon: 13.40, Radius: 0.2})
+
+	v1.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
+	v2.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
+	v2.lat, v2.lon = 48.14, 11.58
+
+	if res := c.identifyVehicleByStatus(vehicles, nil); res != v1 {
+		t.Errorf("geofence: expected v1, got %v", res)
+	}
+
+	// only v2 plugged, charging elsewhere
+	v2.MockChargeState.EXPECT().Status().Return(api.StatusC, nil)
+
+	if res := c.identifyVehicleByStatus([]api.Vehicle{v2}, nil); res != nil {
+		t.Errorf("geofence: expected nil, got %v", res)
+	}
+
+	// both at site
+	c.SetGeofence(nil)
+	d := newDetection()
+
+	// charging status
+	v1.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
+	v2.MockChargeState.EXPECT().Status().Return(api.StatusC, nil)
+	d.charging = true
+
+	if res := c.identifyVehicleByStatus(vehicles, d); res != v2 {
+		t.Errorf("charging: expected v2, got %v", res)
+	}
+
+	// soc increase, recorded before charging
+	v1.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
+	v2.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
+	v1.MockVehicle.EXPECT().SoC().Return(50.0, nil)
+	v2.MockVehicle.EXPECT().SoC().Return(60.0, nil)
+	d.charging = false
+
+	if res := c.identifyVehicleByStatus(vehicles, d); res != nil {
+		t.Errorf("soc baseline: expected nil, got %v", res)
+	}
+
+	v1.MockChargeState.EXPECT().Status().Return(api.StatusC, nil)
+	v2.MockChargeState.EXPECT().Status().Return(api.StatusC, nil)
+	v1.MockVehicle.EXPECT().SoC().Return(52.0, nil)
+	v2.MockVehicle.EXPECT().SoC().Return(60.0, nil)
+	d.charging = true
+
+	if res := c.identifyVehicleByStatus(vehicles, d); res != v1 {
+		t.Errorf("soc: expected v1, got %v", res)
+	}
+}
@@ -0,0 +1,90 @@
+package coordinator
+
+import (
+	"math"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// earthRadius is the mean earth radius in km
+const earthRadius = 6371
+
+// Geofence is the site location used for excluding vehicles parked elsewhere from detection
+type Geofence struct {
+	Lat    float64 `mapstructure:"lat"`
+	Lon    float64 `mapstructure:"lon"`
+	Radius float64 `mapstructure:"radius"` // km
+}
+
+// distance returns the great-circle distance in km
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat2 - lat1)
+	dLon := rad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// contains returns true if the vehicle is inside the geofence or its position is unknown
+func (g *Geofence) contains(log *util.Logger, vehicle api.Vehicle) bool {
+	vp, ok := vehicle.(api.VehiclePosition)
+	if g == nil || !ok {
+		return true
+	}
+
+	lat, lon, err := vp.Position()
+	if err != nil {
+		log.ERROR.Println("vehicle position:", err)
+		return true
+	}
+
+	d := distance(g.Lat, g.Lon, lat, lon)
+	log.DEBUG.Printf("vehicle distance: %.1fkm (%s)", d, vehicle.Title())
+
+	return d <= g.Radius
+}
+
+// detection correlates the vehicle signals with a single loadpoint
+type detection struct {
+	charging bool                    // loadpoint is charging
+	socs     map[api.Vehicle]float64 // vehicle soc before charging started
+}
+
+func newDetection() *detection {
+	return &detection{
+		socs: make(map[api.Vehicle]float64),
+	}
+}
+
+// soc returns the vehicle soc
+func (d *detection) soc(log *util.Logger, vehicle api.Vehicle) (float64, bool) {
+	soc, err := vehicle.SoC()
+	if err != nil {
+		log.DEBUG.Printf("vehicle soc: %v (%s)", err, vehicle.Title())
+	}
+	return soc, err == nil
+}
+
+// record stores the soc of the vehicles before charging starts
+func (d *detection) record(log *util.Logger, vehicles []api.Vehicle) {
+	for _, v := range vehicles {
+		if soc, ok := d.soc(log, v); ok {
+			d.socs[v] = soc
+		}
+	}
+}
+
+// socIncreased returns true if the vehicle soc increased since charging started
+func (d *detection) socIncreased(log *util.Logger, vehicle api.Vehicle) bool {
+	start, ok := d.socs[vehicle]
+	if !ok {
+		return false
+	}
+
+	soc, ok := d.soc(log, vehicle)
+	return ok && soc > start
+}
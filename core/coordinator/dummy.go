@@ -19,6 +19,10 @@ func (a *dummy) Acquire(v api.Vehicle) {}
 
 func (a *dummy) Release(v api.Vehicle) {}
 
-func (a *dummy) IdentifyVehicleByStatus(includeIdCapable bool) api.Vehicle {
+func (a *dummy) IdentifyVehicleByStatus(includeIdCapable, charging bool) api.Vehicle {
 	return nil
 }
+
+func (a *dummy) AtSite(v api.Vehicle) bool {
+	return true
+}
@@ -15,9 +15,14 @@ type DB struct {
 	name string
 }
 
+var _ Database = (*DB)(nil)
+
 type Database interface {
 	Session(startEnergy float64) *Session
+	Restore(id uint) (*Session, error)
 	Persist(session interface{})
+	Decision(traceID, message string)
+	Usage(since time.Time, vehicle string, exclude uint) (float64, float64, error)
 }
 
 // New creates a database storage driver
@@ -42,6 +47,18 @@ func (s *DB) Session(meter float64) *Session {
 	return &t
 }
 
+// Restore loads the loadpoint's session for continuation after restart
+func (s *DB) Restore(id uint) (*Session, error) {
+	var res Session
+	if err := s.db.Where("id = ? AND loadpoint = ?", id, s.name).First(&res).Error; err != nil {
+		return nil, err
+	}
+
+	res.Finished = time.Time{}
+
+	return &res, nil
+}
+
 // Persist creates or updates a transaction in the database
 func (s *DB) Persist(session interface{}) {
 	// TODO remove
@@ -51,3 +68,37 @@ func (s *DB) Persist(session interface{}) {
 		s.log.ERROR.Printf("persist: %v", err)
 	}
 }
+
+// Decision appends the message to the decision log of the trace
+func (s *DB) Decision(traceID, message string) {
+	d := Decision{
+		TraceID:   traceID,
+		Created:   time.Now(),
+		Loadpoint: s.name,
+		Message:   message,
+	}
+
+	if err := s.db.Create(&d).Error; err != nil {
+		s.log.ERROR.Printf("decision: %v", err)
+	}
+}
+
+// Usage returns charged energy in kWh and cost of the sessions created since the given time.
+// Sessions are optionally filtered by vehicle title, the session with the excluded id is ignored.
+func (s *DB) Usage(since time.Time, vehicle string, exclude uint) (float64, float64, error) {
+	var res struct {
+		Energy, Cost float64
+	}
+
+	tx := s.db.Model(new(Session)).
+		Select("COALESCE(SUM(charged_kwh), 0) AS energy, COALESCE(SUM(cost), 0) AS cost").
+		Where("loadpoint = ? AND created >= ? AND id <> ?", s.name, since, exclude)
+
+	if vehicle != "" {
+		tx = tx.Where("vehicle = ?", vehicle)
+	}
+
+	err := tx.Scan(&res).Error
+
+	return res.Energy, res.Cost, err
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { serverdb.Instance = nil })
+
+	require.NoError(t, serverdb.Instance.AutoMigrate(new(Session)))
+
+	db, err := New("garage")
+	require.NoError(t, err)
+
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+	month := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range []Session{
+		{Loadpoint: "garage", Vehicle: "blue", Created: now, ChargedEnergy: 10, Cost: 3},
+		{Loadpoint: "garage", Vehicle: "red", Created: now, ChargedEnergy: 20, Cost: 5},
+		{Loadpoint: "garage", Vehicle: "red", Created: month.AddDate(0, 0, -1), ChargedEnergy: 40, Cost: 9},
+		{Loadpoint: "carport", Vehicle: "red", Created: now, ChargedEnergy: 80, Cost: 17},
+	} {
+		s := s
+		db.Persist(&s)
+	}
+
+	energy, cost, err := db.Usage(month, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, energy)
+	assert.Equal(t, 8.0, cost)
+
+	energy, _, err = db.Usage(month, "red", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, energy)
+
+	// exclude active session
+	energy, _, err = db.Usage(month, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, energy)
+}
+
+func TestSessionSlots(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { serverdb.Instance = nil })
+
+	require.NoError(t, serverdb.Instance.AutoMigrate(new(Session)))
+
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+	s := Session{Loadpoint: "garage", Created: now, Slots: CostSlots{
+		{Start: now, End: now.Add(time.Hour), Energy: 4, Price: 0.3, Cost: 1.2},
+	}}
+	require.NoError(t, serverdb.Instance.Save(&s).Error)
+
+	var res Session
+	require.NoError(t, serverdb.Instance.First(&res, s.ID).Error)
+	require.Len(t, res.Slots, 1)
+	assert.Equal(t, 1.2, res.Slots[0].Cost)
+	assert.True(t, now.Equal(res.Slots[0].Start))
+}
+
+func TestUsers(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { serverdb.Instance = nil })
+
+	require.NoError(t, serverdb.Instance.AutoMigrate(new(Session), new(User)))
+
+	anna := User{Name: "anna", Tokens: Strings{"04AB"}, Vehicles: Strings{"blue"}}
+	require.NoError(t, SaveUser(&anna))
+	bob := User{Name: "bob", Vehicles: Strings{"red"}}
+	require.NoError(t, SaveUser(&bob))
+
+	// tokens are unique
+	assert.Error(t, SaveUser(&User{Name: "eve", Tokens: Strings{"04ab"}}))
+	assert.Error(t, SaveUser(&User{Name: " "}))
+
+	users, err := Users()
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, anna, users[0])
+
+	// token takes precedence over vehicle
+	assert.Equal(t, "anna", MatchUser(users, "04ab", "red"))
+	assert.Equal(t, "bob", MatchUser(users, "", "red"))
+	assert.Equal(t, "", MatchUser(users, "ffff", "green"))
+
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+	for _, s := range []Session{
+		{Loadpoint: "garage", User: "anna", Created: now, ChargedEnergy: 10, SolarPercentage: 100, Cost: 3},
+		{Loadpoint: "garage", Identifier: "04AB", Created: now, ChargedEnergy: 30, Cost: 9},
+		{Loadpoint: "garage", User: "bob", Identifier: "04AB", Created: now, ChargedEnergy: 20, Cost: 5},
+	} {
+		s := s
+		require.NoError(t, serverdb.Instance.Create(&s).Error)
+	}
+
+	var sessions Sessions
+	require.NoError(t, UserSessions(anna).Find(&sessions).Error)
+
+	report := NewUserReport(anna, sessions)
+	assert.Len(t, report.Sessions, 2)
+	assert.Equal(t, 40.0, report.ChargedEnergy)
+	assert.Equal(t, 12.0, report.Cost)
+	assert.Equal(t, 25.0, report.SolarPercentage)
+
+	require.NoError(t, DeleteUser(bob.ID))
+	assert.ErrorIs(t, DeleteUser(bob.ID), ErrUserNotFound)
+
+	_, err = GetUser(bob.ID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
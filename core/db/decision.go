@@ -0,0 +1,15 @@
+package db
+
+import "time"
+
+// Decision is a charging decision of the loadpoint, grouped by the session's trace id
+type Decision struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	TraceID   string    `json:"traceId" gorm:"index"`
+	Created   time.Time `json:"created"`
+	Loadpoint string    `json:"loadpoint"`
+	Message   string    `json:"message"`
+}
+
+// Decisions is the decision log of a session
+type Decisions []Decision
@@ -0,0 +1,341 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/fatih/structs"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// Import formats
+const (
+	FormatEvcc      = "evcc"      // evcc csv export or any csv with matching headers
+	FormatTeslaMate = "teslamate" // TeslaMate charging processes export
+	FormatOpenWB    = "openwb"    // openWB ladelog without header
+)
+
+// ImportOptions defines how imported sessions are read
+type ImportOptions struct {
+	Format    string // csv format, default evcc
+	Loadpoint string // loadpoint title overriding the file's value
+	Vehicle   string // vehicle title overriding the file's value
+}
+
+// importAliases are column names of other tools mapped to session fields
+var importAliases = map[string][]string{
+	"Created":       {"start", "started", "start date", "start_date", "startdate", "date"},
+	"Finished":      {"end", "ended", "end date", "end_date", "enddate"},
+	"Loadpoint":     {"charger", "chargepoint"},
+	"Identifier":    {"rfid", "tag", "id tag"},
+	"Vehicle":       {"car", "car name", "car_name", "name"},
+	"Odometer":      {"mileage", "odometer (km)", "mileage (km)"},
+	"ChargedEnergy": {"energy", "energy (kwh)", "kwh", "charge_energy_added", "energy added (kwh)", "added (kwh)"},
+	"Price":         {"price (kwh)"},
+	"Cost":          {"costs", "total cost"},
+}
+
+// importLayouts are the accepted time formats
+var importLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"02.01.06-15:04",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+}
+
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// headerFields returns the accepted column names per session field including localized export captions
+func headerFields() map[string]string {
+	res := make(map[string]string)
+
+	var localizers []*i18n.Localizer
+	for _, lang := range locale.Languages() {
+		localizers = append(localizers, locale.NewLocalizer(lang))
+	}
+
+	for _, f := range structs.Fields(Session{}) {
+		csv := f.Tag("csv")
+		if csv == "-" {
+			continue
+		}
+
+		names := append([]string{f.Name(), f.Tag("json"), csv}, importAliases[f.Name()]...)
+
+		for _, l := range localizers {
+			if caption, err := l.Localize(&locale.Config{
+				MessageID: "sessions.csv." + strings.ToLower(f.Name()),
+			}); err == nil {
+				names = append(names, caption)
+			}
+		}
+
+		for _, name := range names {
+			if name = normalizeHeader(name); name != "" {
+				if _, ok := res[name]; !ok {
+					res[name] = f.Name()
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+func parseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	// decimal comma with optional thousands separator
+	if strings.Contains(s, ",") {
+		if strings.Contains(s, ".") {
+			s = strings.ReplaceAll(s, ".", "")
+		}
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range importLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time: %s", s)
+}
+
+// setField assigns the csv value to the named session field
+func setField(s *Session, name, val string) error {
+	f := structs.New(s).Field(name)
+
+	switch f.Value().(type) {
+	case time.Time:
+		t, err := parseTime(val)
+		if err != nil {
+			return err
+		}
+		return f.Set(t)
+
+	case float64:
+		v, err := parseFloat(val)
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.ToLower(name), err)
+		}
+		return f.Set(v)
+
+	default:
+		return f.Set(strings.TrimSpace(val))
+	}
+}
+
+// readRecords detects the separator and returns all csv records
+func readRecords(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+
+	// skip byte order mark
+	if b, err := br.Peek(3); err == nil && bytes.Equal(b, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(3)
+	}
+
+	b, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(bytes.NewReader(b))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	// set separator according to first line
+	if line, _, _ := bytes.Cut(b, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		cr.Comma = ';'
+	}
+
+	return cr.ReadAll()
+}
+
+// readHeaderCsv reads sessions from csv with header line
+func readHeaderCsv(records [][]string) (Sessions, error) {
+	if len(records) == 0 {
+		return nil, errors.New("missing header")
+	}
+
+	fields := headerFields()
+
+	columns := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		columns[i] = fields[normalizeHeader(h)]
+	}
+
+	var hasCreated bool
+	for _, c := range columns {
+		hasCreated = hasCreated || c == "Created"
+	}
+	if !hasCreated {
+		return nil, errors.New("missing start time column")
+	}
+
+	var res Sessions
+	for i, rec := range records[1:] {
+		var s Session
+
+		for j, val := range rec {
+			if j >= len(columns) || columns[j] == "" {
+				continue
+			}
+
+			if err := setField(&s, columns[j], val); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+2, err)
+			}
+		}
+
+		res = append(res, s)
+	}
+
+	return res, nil
+}
+
+// readOpenWB reads sessions from openWB ladelog csv with columns
+// start, end, km, kWh, kW, duration, loadpoint, mode, rfid and optional cost
+func readOpenWB(records [][]string) (Sessions, error) {
+	var res Sessions
+
+	for i, rec := range records {
+		if len(rec) < 7 {
+			return nil, fmt.Errorf("line %d: invalid number of columns", i+1)
+		}
+
+		var s Session
+		var err error
+
+		if s.Created, err = parseTime(rec[0]); err == nil {
+			s.Finished, err = parseTime(rec[1])
+		}
+
+		if err == nil {
+			s.ChargedEnergy, err = parseFloat(rec[3])
+		}
+
+		if err == nil && len(rec) > 9 {
+			s.Cost, err = parseFloat(rec[9])
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		s.Loadpoint = "LP" + strings.TrimSpace(rec[6])
+
+		if len(rec) > 8 {
+			if id := strings.TrimSpace(rec[8]); id != "0" {
+				s.Identifier = id
+			}
+		}
+
+		res = append(res, s)
+	}
+
+	return res, nil
+}
+
+// ReadCsv reads charging sessions from csv in the given format
+func ReadCsv(r io.Reader, o ImportOptions) (Sessions, error) {
+	records, err := readRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var res Sessions
+
+	switch strings.ToLower(o.Format) {
+	case "", FormatEvcc, FormatTeslaMate:
+		res, err = readHeaderCsv(records)
+	case FormatOpenWB:
+		res, err = readOpenWB(records)
+	default:
+		return nil, fmt.Errorf("invalid format: %s", o.Format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range res {
+		if res[i].Created.IsZero() {
+			return nil, fmt.Errorf("session %d: missing start time", i+1)
+		}
+
+		if o.Loadpoint != "" {
+			res[i].Loadpoint = o.Loadpoint
+		}
+		if o.Vehicle != "" {
+			res[i].Vehicle = o.Vehicle
+		}
+	}
+
+	return res, nil
+}
+
+// Import persists the sessions not yet contained in the database and returns the number of imported and skipped sessions
+func Import(sessions Sessions) (int, int, error) {
+	if serverdb.Instance == nil {
+		return 0, 0, errors.New("database offline")
+	}
+
+	// table may not exist yet when not running as service
+	if err := serverdb.Instance.AutoMigrate(new(Session)); err != nil {
+		return 0, 0, err
+	}
+
+	var imported, skipped int
+
+	for _, s := range sessions {
+		var count int64
+		if err := serverdb.Instance.Model(new(Session)).
+			Where("loadpoint = ? AND created = ?", s.Loadpoint, s.Created).
+			Count(&count).Error; err != nil {
+			return imported, skipped, err
+		}
+
+		if count > 0 {
+			skipped++
+			continue
+		}
+
+		s.ID = 0
+		if err := serverdb.Instance.Create(&s).Error; err != nil {
+			return imported, skipped, err
+		}
+
+		imported++
+	}
+
+	return imported, skipped, nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCsv(t *testing.T) {
+	tc := []struct {
+		name string
+		csv  string
+		o    ImportOptions
+		res  Sessions
+	}{
+		{
+			"evcc",
+			"\xEF\xBB\xBFCreated,Finished,Loadpoint,Identifier,Vehicle,Odometer,Meter Start (kWh),Meter Stop (kWh),Charged Energy (kWh),Price (per kWh),Cost,Currency\n" +
+				"2022-10-01 10:00:00,2022-10-01 12:30:00,Garage,,e-Golf,12345,100,112.5,12.5,0.3,3.75,EUR\n",
+			ImportOptions{},
+			Sessions{{
+				Created:       time.Date(2022, 10, 1, 10, 0, 0, 0, time.Local),
+				Finished:      time.Date(2022, 10, 1, 12, 30, 0, 0, time.Local),
+				Loadpoint:     "Garage",
+				Vehicle:       "e-Golf",
+				Odometer:      12345,
+				MeterStart:    100,
+				MeterStop:     112.5,
+				ChargedEnergy: 12.5,
+				Price:         0.3,
+				Cost:          3.75,
+				Currency:      "EUR",
+			}},
+		},
+		{
+			"decimal comma",
+			"created;loadpoint;chargedEnergy\n" +
+				"01.10.2022 10:00;Garage;1.012,5\n",
+			ImportOptions{Vehicle: "Model 3"},
+			Sessions{{
+				Created:       time.Date(2022, 10, 1, 10, 0, 0, 0, time.Local),
+				Loadpoint:     "Garage",
+				Vehicle:       "Model 3",
+				ChargedEnergy: 1012.5,
+			}},
+		},
+		{
+			"teslamate",
+			"start_date,end_date,car,charge_energy_added,cost\n" +
+				"2022-10-01T10:00:00Z,2022-10-01T11:00:00Z,Model 3,20.5,6.1\n",
+			ImportOptions{Format: FormatTeslaMate, Loadpoint: "Carport"},
+			Sessions{{
+				Created:       time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC),
+				Finished:      time.Date(2022, 10, 1, 11, 0, 0, 0, time.UTC),
+				Loadpoint:     "Carport",
+				Vehicle:       "Model 3",
+				ChargedEnergy: 20.5,
+				Cost:          6.1,
+			}},
+		},
+		{
+			"openwb",
+			"01.10.22-10:00,01.10.22-12:00,80,15.25,7.6,2 H 0 Min,1,2,0,4.57\n",
+			ImportOptions{Format: FormatOpenWB},
+			Sessions{{
+				Created:       time.Date(2022, 10, 1, 10, 0, 0, 0, time.Local),
+				Finished:      time.Date(2022, 10, 1, 12, 0, 0, 0, time.Local),
+				Loadpoint:     "LP1",
+				ChargedEnergy: 15.25,
+				Cost:          4.57,
+			}},
+		},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ReadCsv(strings.NewReader(tc.csv), tc.o)
+			require.NoError(t, err)
+			require.Len(t, res, len(tc.res))
+
+			for i := range res {
+				assert.True(t, tc.res[i].Created.Equal(res[i].Created), "created")
+				assert.True(t, tc.res[i].Finished.Equal(res[i].Finished), "finished")
+				res[i].Created, res[i].Finished = tc.res[i].Created, tc.res[i].Finished
+			}
+
+			assert.Equal(t, tc.res, res)
+		})
+	}
+}
+
+func TestReadCsvErrors(t *testing.T) {
+	for _, csv := range []string{
+		"loadpoint,chargedEnergy\nGarage,10\n",
+		"created,chargedEnergy\n2022-10-01 10:00:00,ten\n",
+		"created,chargedEnergy\nyesterday,10\n",
+	} {
+		_, err := ReadCsv(strings.NewReader(csv), ImportOptions{})
+		assert.Error(t, err, csv)
+	}
+
+	_, err := ReadCsv(strings.NewReader(""), ImportOptions{Format: "foo"})
+	assert.Error(t, err)
+}
+
+func TestImport(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { serverdb.Instance = nil })
+
+	now := time.Date(2022, 10, 15, 12, 0, 0, 0, time.UTC)
+	sessions := Sessions{
+		{Loadpoint: "garage", Created: now, ChargedEnergy: 10},
+		{Loadpoint: "garage", Created: now.Add(time.Hour), ChargedEnergy: 20},
+	}
+
+	imported, skipped, err := Import(sessions)
+	require.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	assert.Equal(t, 0, skipped)
+
+	// repeated import skips existing sessions
+	imported, skipped, err = Import(append(sessions, Session{Loadpoint: "carport", Created: now, ChargedEnergy: 5}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, imported)
+	assert.Equal(t, 2, skipped)
+}
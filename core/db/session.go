@@ -11,7 +11,6 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/fatih/structs"
-	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"golang.org/x/text/number"
@@ -19,16 +18,23 @@ import (
 
 // Session is a single charging session
 type Session struct {
-	ID            uint      `json:"-" csv:"-" gorm:"primarykey"`
-	Created       time.Time `json:"created"`
-	Finished      time.Time `json:"finished"`
-	Loadpoint     string    `json:"loadpoint"`
-	Identifier    string    `json:"identifier"`
-	Vehicle       string    `json:"vehicle"`
-	Odometer      float64   `json:"odometer" format:"int"`
-	MeterStart    float64   `json:"meterStart" csv:"Meter Start (kWh)" gorm:"column:meter_start_kwh"`
-	MeterStop     float64   `json:"meterStop" csv:"Meter Stop (kWh)" gorm:"column:meter_end_kwh"`
-	ChargedEnergy float64   `json:"chargedEnergy" csv:"Charged Energy (kWh)" gorm:"column:charged_kwh"`
+	ID              uint      `json:"-" csv:"-" gorm:"primarykey"`
+	Created         time.Time `json:"created"`
+	Finished        time.Time `json:"finished"`
+	Loadpoint       string    `json:"loadpoint"`
+	Identifier      string    `json:"identifier"`
+	User            string    `json:"user"`
+	Vehicle         string    `json:"vehicle"`
+	Odometer        float64   `json:"odometer" format:"int"`
+	MeterStart      float64   `json:"meterStart" csv:"Meter Start (kWh)" gorm:"column:meter_start_kwh"`
+	MeterStop       float64   `json:"meterStop" csv:"Meter Stop (kWh)" gorm:"column:meter_end_kwh"`
+	ChargedEnergy   float64   `json:"chargedEnergy" csv:"Charged Energy (kWh)" gorm:"column:charged_kwh"`
+	SolarPercentage float64   `json:"solarPercentage" csv:"Solar (%)" gorm:"column:solar_percentage"`
+	Price           float64   `json:"price" csv:"Price (per kWh)" gorm:"column:price"`
+	Cost            float64   `json:"cost" csv:"Cost" gorm:"column:cost"`
+	Currency        string    `json:"currency" csv:"Currency"`
+	Slots           CostSlots `json:"slots,omitempty" csv:"-" gorm:"type:text"`
+	TraceID         string    `json:"traceId,omitempty" csv:"-" gorm:"column:trace_id;index"`
 }
 
 // Stop stops charging session with end meter reading and due total amount
@@ -46,9 +52,9 @@ type Sessions []Session
 var _ api.CsvWriter = (*Sessions)(nil)
 
 func (t *Sessions) writeHeader(ctx context.Context, ww *csv.Writer) error {
-	localizer := locale.Localizer
-	if val := ctx.Value(locale.Locale).(string); val != "" {
-		localizer = i18n.NewLocalizer(locale.Bundle, val, locale.Language)
+	localizer := locale.Localizer()
+	if val, ok := ctx.Value(locale.Locale).(string); ok && val != "" {
+		localizer = locale.NewLocalizer(val)
 	}
 
 	var row []string
@@ -115,7 +121,7 @@ func (t *Sessions) WriteCsv(ctx context.Context, w io.Writer) error {
 	}
 
 	// get context language
-	lang := locale.Language
+	lang := locale.Language()
 	if language, ok := ctx.Value(locale.Locale).(string); ok && language != "" {
 		lang = language
 	}
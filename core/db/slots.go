@@ -0,0 +1,45 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CostSlot is the energy charged at a single tariff price
+type CostSlot struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Energy float64   `json:"energy"` // kWh
+	Price  float64   `json:"price"`  // per kWh
+	Cost   float64   `json:"cost"`
+}
+
+// CostSlots is the per tariff period cost breakdown of a session
+type CostSlots []CostSlot
+
+// Value implements the driver.Valuer interface
+func (s CostSlots) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+// Scan implements the sql.Scanner interface
+func (s *CostSlots) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	default:
+		return fmt.Errorf("invalid cost slots: %T", value)
+	}
+}
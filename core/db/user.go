@@ -0,0 +1,206 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// ErrUserNotFound is returned for unknown user ids
+var ErrUserNotFound = errors.New("user not found")
+
+// User is a driver sharing the loadpoints, sessions are mapped to the user by RFID token or vehicle
+type User struct {
+	ID       uint    `json:"id" gorm:"primarykey"`
+	Name     string  `json:"name" gorm:"uniqueIndex"`
+	Tokens   Strings `json:"tokens" gorm:"type:text"`   // RFID tokens reported by the charger
+	Vehicles Strings `json:"vehicles" gorm:"type:text"` // vehicle titles
+}
+
+// Strings is a list of strings stored as json
+type Strings []string
+
+// Value implements the driver.Valuer interface
+func (s Strings) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+// Scan implements the sql.Scanner interface
+func (s *Strings) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	default:
+		return fmt.Errorf("invalid strings: %T", value)
+	}
+}
+
+// Validate checks the user's name and tokens
+func (u *User) Validate() error {
+	if u.Name = strings.TrimSpace(u.Name); u.Name == "" {
+		return errors.New("missing name")
+	}
+
+	for _, token := range u.Tokens {
+		if strings.TrimSpace(token) == "" {
+			return errors.New("empty token")
+		}
+	}
+
+	return nil
+}
+
+// match returns true if the session's identifier or vehicle belongs to the user, tokens take precedence
+func (u *User) match(identifier, vehicle string) (bool, bool) {
+	if identifier != "" && slices.IndexFunc(u.Tokens, func(t string) bool { return strings.EqualFold(t, identifier) }) >= 0 {
+		return true, true
+	}
+
+	return false, vehicle != "" && slices.Contains(u.Vehicles, vehicle)
+}
+
+// MatchUser returns the name of the user owning the RFID token or, if no token matches, the vehicle
+func MatchUser(users []User, identifier, vehicle string) string {
+	var res string
+
+	for _, u := range users {
+		token, vehicleMatch := u.match(identifier, vehicle)
+		if token {
+			return u.Name
+		}
+
+		if vehicleMatch && res == "" {
+			res = u.Name
+		}
+	}
+
+	return res
+}
+
+// Users returns the users ordered by name
+func Users() ([]User, error) {
+	if serverdb.Instance == nil {
+		return nil, nil
+	}
+
+	var res []User
+	err := serverdb.Instance.Order("name").Find(&res).Error
+
+	return res, err
+}
+
+// GetUser returns the user with the id
+func GetUser(id uint) (User, error) {
+	var res User
+
+	tx := serverdb.Instance.Limit(1).Find(&res, id)
+	if tx.Error == nil && tx.RowsAffected == 0 {
+		return res, ErrUserNotFound
+	}
+
+	return res, tx.Error
+}
+
+// SaveUser creates or updates the user.
+// Tokens must be unique across users to map sessions unambiguously.
+func SaveUser(u *User) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+
+	users, err := Users()
+	if err != nil {
+		return err
+	}
+
+	for _, other := range users {
+		if other.ID == u.ID {
+			continue
+		}
+
+		for _, token := range u.Tokens {
+			if token, _ := other.match(token, ""); token {
+				return fmt.Errorf("token already assigned to %s", other.Name)
+			}
+		}
+	}
+
+	return serverdb.Instance.Save(u).Error
+}
+
+// DeleteUser deletes the user, sessions keep the user name
+func DeleteUser(id uint) error {
+	tx := serverdb.Instance.Delete(new(User), id)
+	if tx.Error == nil && tx.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return tx.Error
+}
+
+// UserSessions returns the query of the user's sessions. Sessions recorded before the user was
+// created are matched by the user's tokens.
+func UserSessions(u User) *gorm.DB {
+	tx := serverdb.Instance.Model(new(Session))
+
+	if len(u.Tokens) == 0 {
+		return tx.Where("user = ?", u.Name)
+	}
+
+	return tx.Where("user = ? OR (user = '' AND identifier IN ?)", u.Name, []string(u.Tokens))
+}
+
+// UserReport is the energy and cost summary of a user's sessions
+type UserReport struct {
+	User            User     `json:"user"`
+	ChargedEnergy   float64  `json:"chargedEnergy"`   // kWh
+	SolarPercentage float64  `json:"solarPercentage"` // energy weighted
+	Cost            float64  `json:"cost"`
+	Currency        string   `json:"currency,omitempty"`
+	Sessions        Sessions `json:"sessions"`
+}
+
+// NewUserReport summarizes the user's sessions
+func NewUserReport(u User, sessions Sessions) UserReport {
+	res := UserReport{
+		User:     u,
+		Sessions: sessions,
+	}
+
+	var solar float64
+	for _, s := range sessions {
+		res.ChargedEnergy += s.ChargedEnergy
+		res.Cost += s.Cost
+		solar += s.ChargedEnergy * s.SolarPercentage
+
+		if res.Currency == "" {
+			res.Currency = s.Currency
+		}
+	}
+
+	if res.ChargedEnergy > 0 {
+		res.SolarPercentage = solar / res.ChargedEnergy
+	}
+
+	if res.Sessions == nil {
+		res.Sessions = Sessions{}
+	}
+
+	return res
+}
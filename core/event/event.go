@@ -0,0 +1,151 @@
+// Package event provides the typed internal events of site and loadpoints.
+// Push notifications, MQTT and other integrations subscribe to the bus instead of watching published values.
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a typed internal event
+type Event interface {
+	// Name is the event's push notification and MQTT topic name
+	Name() string
+}
+
+// ChargeStarted is sent when the vehicle starts charging
+type ChargeStarted struct{}
+
+// ChargeStopped is sent when the vehicle stops charging
+type ChargeStopped struct {
+	Energy float64 `json:"energy"` // Wh charged in session
+}
+
+// VehicleConnected is sent when a vehicle is plugged in
+type VehicleConnected struct{}
+
+// VehicleDisconnected is sent when the vehicle is unplugged
+type VehicleDisconnected struct {
+	Duration time.Duration `json:"duration"`
+}
+
+// VehicleSoC is sent when the vehicle soc reaches the next progress step
+type VehicleSoC struct {
+	SoC float64 `json:"soc"`
+}
+
+// VehicleUnidentified is sent when no vehicle could be identified and a guest session starts
+type VehicleUnidentified struct{}
+
+// VehicleUnauthorized is sent when the charger identifier is not authorized
+type VehicleUnauthorized struct {
+	ID string `json:"id"`
+}
+
+// PlanActivated is sent when target charging becomes active
+type PlanActivated struct {
+	Target time.Time `json:"target"`
+}
+
+// TargetReached is sent when the vehicle reaches the target soc or energy
+type TargetReached struct {
+	SoC    float64 `json:"soc"`
+	Energy float64 `json:"energy"` // Wh charged in session
+}
+
+// BudgetExceeded is sent when the monthly charging budget is exceeded
+type BudgetExceeded struct{}
+
+// VehicleIdle is sent when the charger is freed after the vehicle finished charging
+type VehicleIdle struct {
+	Duration time.Duration `json:"duration"` // idle duration
+}
+
+// DeviceError is sent when a device reports a fault
+type DeviceError struct {
+	Device string `json:"device"`
+	Error  string `json:"error"`
+}
+
+// MeterValidation is sent when the startup meter validation found implausible measurements
+type MeterValidation struct {
+	Warnings []string `json:"warnings"`
+}
+
+// GridSignal is sent when the grid operator's load reduction starts, changes or is released
+type GridSignal struct {
+	Active bool    `json:"active"`
+	Limit  float64 `json:"limit"` // W
+}
+
+func (ChargeStarted) Name() string       { return "start" }
+func (ChargeStopped) Name() string       { return "stop" }
+func (VehicleConnected) Name() string    { return "connect" }
+func (VehicleDisconnected) Name() string { return "disconnect" }
+func (VehicleSoC) Name() string          { return "soc" }
+func (VehicleUnidentified) Name() string { return "guest" }
+func (VehicleUnauthorized) Name() string { return "unauthorized" }
+func (PlanActivated) Name() string       { return "plan" }
+func (TargetReached) Name() string       { return "target" }
+func (BudgetExceeded) Name() string      { return "budget" }
+func (VehicleIdle) Name() string         { return "idle" }
+func (DeviceError) Name() string         { return "fault" }
+func (MeterValidation) Name() string     { return "validation" }
+func (GridSignal) Name() string          { return "gridsignal" }
+
+// Envelope is a published event with its origin
+type Envelope struct {
+	Time      time.Time
+	LoadPoint *int // nil for site events
+	Event     Event
+}
+
+// Publisher publishes events of a fixed origin
+type Publisher func(Event)
+
+// Bus distributes events to subscribers. Handlers are called synchronously and must not block.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []func(Envelope)
+}
+
+// New creates an event bus
+func New() *Bus {
+	return new(Bus)
+}
+
+// Subscribe adds a handler for all events
+func (b *Bus) Subscribe(fn func(Envelope)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, fn)
+}
+
+// Publish sends the event to all subscribers
+func (b *Bus) Publish(lp *int, ev Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	e := Envelope{Time: time.Now(), LoadPoint: lp, Event: ev}
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// Publisher returns a publisher for the given loadpoint or the site if nil
+func (b *Bus) Publisher(lp *int) Publisher {
+	return func(ev Event) {
+		b.Publish(lp, ev)
+	}
+}
+
+// Handle subscribes the handler to events of type T
+func Handle[T Event](b *Bus, fn func(Envelope, T)) {
+	b.Subscribe(func(e Envelope) {
+		if ev, ok := e.Event.(T); ok {
+			fn(e, ev)
+		}
+	})
+}
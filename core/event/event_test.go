@@ -0,0 +1,33 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandle(t *testing.T) {
+	bus := New()
+
+	var all []Envelope
+	bus.Subscribe(func(e Envelope) {
+		all = append(all, e)
+	})
+
+	var stopped []ChargeStopped
+	Handle(bus, func(_ Envelope, ev ChargeStopped) {
+		stopped = append(stopped, ev)
+	})
+
+	lp := 1
+	bus.Publisher(&lp)(ChargeStarted{})
+	bus.Publisher(&lp)(ChargeStopped{Energy: 1000})
+	bus.Publish(nil, DeviceError{Device: "charger", Error: "overcurrent"})
+
+	assert.Len(t, all, 3)
+	assert.Equal(t, &lp, all[0].LoadPoint)
+	assert.Nil(t, all[2].LoadPoint)
+	assert.Equal(t, "fault", all[2].Event.Name())
+
+	assert.Equal(t, []ChargeStopped{{Energy: 1000}}, stopped)
+}
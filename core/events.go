@@ -0,0 +1,42 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/push"
+)
+
+// pushEvents forwards events as push messages
+func pushEvents(bus *event.Bus, pushChan chan<- push.Event) {
+	bus.Subscribe(func(e event.Envelope) {
+		pushChan <- push.Event{Event: e.Event.Name(), LoadPoint: e.LoadPoint, Payload: e.Event}
+	})
+}
+
+// recordSessions records the charging sessions of the loadpoint from the events of its origin
+func recordSessions(bus *event.Bus, origin *int, lp *LoadPoint) {
+	bus.Subscribe(func(e event.Envelope) {
+		if e.LoadPoint != origin {
+			return
+		}
+
+		switch e.Event.(type) {
+		case event.ChargeStarted:
+			lp.startSession()
+		case event.ChargeStopped:
+			lp.stopSession()
+		case event.VehicleDisconnected:
+			// ensure session is persisted and closed before vehicle is changed
+			lp.stopSession()
+			lp.finalizeSession()
+		}
+	})
+}
+
+// pushPublisher creates a publisher forwarding the loadpoint's events as push messages and
+// recording its sessions when not attached to a site
+func pushPublisher(lp *LoadPoint, pushChan chan<- push.Event) event.Publisher {
+	bus := event.New()
+	pushEvents(bus, pushChan)
+	recordSessions(bus, nil, lp)
+	return bus.Publisher(nil)
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type sessionDB struct {
+	db.Database
+	persisted int
+}
+
+func (d *sessionDB) Session(startEnergy float64) *db.Session {
+	return &db.Session{ID: 1, MeterStart: startEnergy}
+}
+
+func (d *sessionDB) Persist(interface{}) {
+	d.persisted++
+}
+
+func TestRecordSessions(t *testing.T) {
+	sessions := new(sessionDB)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.db = sessions
+
+	bus := event.New()
+	id := 0
+	recordSessions(bus, &id, lp)
+
+	// other loadpoints' events are ignored
+	other := 1
+	bus.Publisher(&other)(event.ChargeStarted{})
+	assert.Nil(t, lp.session)
+
+	bus.Publisher(&id)(event.ChargeStarted{})
+	assert.NotNil(t, lp.session)
+
+	bus.Publisher(&id)(event.ChargeStopped{})
+	assert.NotNil(t, lp.session)
+	assert.Equal(t, 2, sessions.persisted)
+
+	bus.Publisher(&id)(event.VehicleDisconnected{Duration: time.Hour})
+	assert.Nil(t, lp.session)
+}
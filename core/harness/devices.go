@@ -0,0 +1,212 @@
+package harness
+
+import (
+	"sync"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Meter is a simulated meter
+type Meter struct {
+	mu    sync.Mutex
+	power float64
+}
+
+var _ api.Meter = (*Meter)(nil)
+
+// SetPower sets the meter power
+func (m *Meter) SetPower(power float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.power = power
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *Meter) CurrentPower() (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.power, nil
+}
+
+// Charger is a simulated charger with integrated meter
+type Charger struct {
+	mu      sync.Mutex
+	status  api.ChargeStatus
+	enabled bool
+	current float64
+	phases  int
+	voltage float64
+}
+
+var (
+	_ api.Charger   = (*Charger)(nil)
+	_ api.ChargerEx = (*Charger)(nil)
+	_ api.Meter     = (*Charger)(nil)
+)
+
+// NewCharger creates a simulated charger
+func NewCharger(phases int) *Charger {
+	return &Charger{
+		status:  api.StatusA,
+		phases:  phases,
+		voltage: 230,
+	}
+}
+
+// SetStatus sets the charger status, e.g. to simulate a vehicle plugging in
+func (c *Charger) SetStatus(status api.ChargeStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+// Current returns the current limit
+func (c *Charger) Current() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current
+}
+
+// Status implements the api.Charger interface
+func (c *Charger) Status() (api.ChargeStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// vehicle charges when enabled
+	if c.status == api.StatusB && c.enabled && c.current > 0 {
+		return api.StatusC, nil
+	}
+	if c.status == api.StatusC && !c.enabled {
+		return api.StatusB, nil
+	}
+
+	return c.status, nil
+}
+
+// Enabled implements the api.Charger interface
+func (c *Charger) Enabled() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enabled, nil
+}
+
+// Enable implements the api.Charger interface
+func (c *Charger) Enable(enable bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = enable
+	return nil
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *Charger) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (c *Charger) MaxCurrentMillis(current float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = current
+	return nil
+}
+
+// CurrentPower implements the api.Meter interface
+func (c *Charger) CurrentPower() (float64, error) {
+	status, _ := c.Status()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if status != api.StatusC {
+		return 0, nil
+	}
+
+	return c.current * float64(c.phases) * c.voltage, nil
+}
+
+// PhaseCharger is a simulated charger with phase switching
+type PhaseCharger struct {
+	*Charger
+}
+
+var _ api.PhaseSwitcher = (*PhaseCharger)(nil)
+
+// Phases1p3p implements the api.PhaseSwitcher interface
+func (c *PhaseCharger) Phases1p3p(phases int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.phases = phases
+	return nil
+}
+
+// Phases returns the active phases
+func (c *PhaseCharger) Phases() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.phases
+}
+
+// Vehicle is a simulated vehicle
+type Vehicle struct {
+	mu       sync.Mutex
+	title    string
+	capacity float64
+	soc      float64
+}
+
+var _ api.Vehicle = (*Vehicle)(nil)
+
+// NewVehicle creates a simulated vehicle
+func NewVehicle(title string, capacity, soc float64) *Vehicle {
+	return &Vehicle{
+		title:    title,
+		capacity: capacity,
+		soc:      soc,
+	}
+}
+
+// Charge adds energy in kWh to the vehicle's battery
+func (v *Vehicle) Charge(energy float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.capacity > 0 {
+		v.soc += energy / v.capacity * 100
+		if v.soc > 100 {
+			v.soc = 100
+		}
+	}
+}
+
+// SoC implements the api.Vehicle interface
+func (v *Vehicle) SoC() (float64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.soc, nil
+}
+
+// Title implements the api.Vehicle interface
+func (v *Vehicle) Title() string {
+	return v.title
+}
+
+// Capacity implements the api.Vehicle interface
+func (v *Vehicle) Capacity() float64 {
+	return v.capacity
+}
+
+// Phases implements the api.Vehicle interface
+func (v *Vehicle) Phases() int {
+	return 0
+}
+
+// Identifiers implements the api.Vehicle interface
+func (v *Vehicle) Identifiers() []string {
+	return nil
+}
+
+// OnIdentified implements the api.Vehicle interface
+func (v *Vehicle) OnIdentified() api.ActionConfig {
+	return api.ActionConfig{}
+}
@@ -0,0 +1,219 @@
+// Package harness wires simulated devices to a real site and loadpoint and advances
+// virtual time for writing scenario based regression tests of the control logic.
+package harness
+
+import (
+	"errors"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/internal/sim"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Harness is a simulated installation consisting of grid and pv meter, charger and vehicle
+type Harness struct {
+	Clock     *clock.Mock
+	Site      *core.Site
+	LoadPoint *core.LoadPoint
+	Grid, PV  *Meter
+	Charger   api.Charger
+	Vehicle   *Vehicle
+
+	HomePower float64 // household consumption in W excluding charging
+
+	done chan struct{}
+}
+
+// Options configures the simulated installation
+type Options struct {
+	Phases        int                    // charger phases, default 3
+	PhaseSwitcher bool                   // charger supports 1p3p switching
+	Capacity, SoC float64                // vehicle capacity in kWh and initial soc
+	LoadPoint     map[string]interface{} // additional loadpoint configuration like mode or thresholds
+}
+
+// provider implements the core config provider for the simulated devices
+type provider struct {
+	h *Harness
+}
+
+func (p provider) Meter(name string) (api.Meter, error) {
+	switch name {
+	case "grid":
+		return p.h.Grid, nil
+	case "pv":
+		return p.h.PV, nil
+	}
+	return nil, errors.New("invalid meter: " + name)
+}
+
+func (p provider) Charger(name string) (api.Charger, error) {
+	return p.h.Charger, nil
+}
+
+func (p provider) Vehicle(name string) (api.Vehicle, error) {
+	return p.h.Vehicle, nil
+}
+
+// New creates a simulated installation. It must be closed after use.
+func New(o Options) (*Harness, error) {
+	if o.Phases == 0 {
+		o.Phases = 3
+	}
+	if o.Capacity == 0 {
+		o.Capacity = 50
+	}
+
+	charger := NewCharger(o.Phases)
+
+	h := &Harness{
+		Clock:   clock.NewMock(),
+		Grid:    new(Meter),
+		PV:      new(Meter),
+		Charger: charger,
+		Vehicle: NewVehicle("vehicle", o.Capacity, o.SoC),
+		done:    make(chan struct{}),
+	}
+
+	if o.PhaseSwitcher {
+		h.Charger = &PhaseCharger{charger}
+	}
+
+	// start at a defined time of day
+	h.Clock.Set(time.Date(2022, 6, 1, 12, 0, 0, 0, time.Local))
+
+	log := util.NewLogger("harness")
+	cp := provider{h}
+
+	other := map[string]interface{}{
+		"title":   "harness",
+		"charger": "charger",
+		"vehicle": "vehicle",
+		"phases":  o.Phases,
+	}
+	if o.PhaseSwitcher {
+		other["phases"] = 0
+	}
+	for k, v := range o.LoadPoint {
+		other[k] = v
+	}
+
+	lp, err := core.NewLoadPointFromConfig(log, cp, other)
+	if err != nil {
+		return nil, err
+	}
+
+	lp.SetClock(h.Clock)
+	h.LoadPoint = lp
+
+	site, err := core.NewSiteFromConfig(log, cp, map[string]interface{}{
+		"meters": map[string]interface{}{
+			"grid": "grid",
+			"pvs":  []string{"pv"},
+		},
+	}, []*core.LoadPoint{lp}, []api.Vehicle{h.Vehicle}, tariff.Tariffs{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Site = site
+
+	// discard published values and events
+	uiChan := make(chan util.Param)
+	pushChan := make(chan push.Event)
+
+	go func() {
+		for {
+			select {
+			case <-uiChan:
+			case <-pushChan:
+			case <-h.done:
+				return
+			}
+		}
+	}()
+
+	site.Prepare(uiChan, pushChan)
+
+	return h, nil
+}
+
+// Close stops discarding the published values and events
+func (h *Harness) Close() {
+	close(h.done)
+}
+
+// Connect simulates a vehicle plugging in or out
+func (h *Harness) Connect(connected bool) {
+	status := api.StatusA
+	if connected {
+		status = api.StatusB
+	}
+	h.charger().SetStatus(status)
+}
+
+// charger returns the underlying simulated charger
+func (h *Harness) charger() *Charger {
+	if c, ok := h.Charger.(*PhaseCharger); ok {
+		return c.Charger
+	}
+	return h.Charger.(*Charger)
+}
+
+// ChargePower returns the simulated charge power
+func (h *Harness) ChargePower() float64 {
+	power, _ := h.charger().CurrentPower()
+	return power
+}
+
+// Current returns the charger's current limit
+func (h *Harness) Current() float64 {
+	return h.charger().Current()
+}
+
+// Enabled returns the charger's enabled state
+func (h *Harness) Enabled() bool {
+	enabled, _ := h.charger().Enabled()
+	return enabled
+}
+
+// Step advances virtual time, updates the simulated energy flows and executes a control cycle
+func (h *Harness) Step(d time.Duration) {
+	chargePower := h.ChargePower()
+	h.Vehicle.Charge(chargePower * d.Hours() / 1e3)
+
+	h.Clock.Add(d)
+
+	pv, _ := h.PV.CurrentPower()
+	h.Grid.SetPower(h.HomePower + chargePower - pv)
+
+	sim.Step(h.Site)
+}
+
+// Scenario is a single step of a table-driven scenario
+type Scenario struct {
+	Name     string
+	Duration time.Duration // time to advance before the control cycle
+	PV       float64       // pv power in W
+	Home     float64       // household power in W
+	Connect  *bool         // change vehicle connection
+}
+
+// Run executes the scenario step and returns the charger's enabled state and current
+func (h *Harness) Run(s Scenario) (bool, float64) {
+	h.PV.SetPower(s.PV)
+	h.HomePower = s.Home
+
+	if s.Connect != nil {
+		h.Connect(*s.Connect)
+	}
+
+	h.Step(s.Duration)
+
+	return h.Enabled(), h.Current()
+}
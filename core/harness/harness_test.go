@@ -0,0 +1,86 @@
+package harness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPVHysteresis(t *testing.T) {
+	h, err := New(Options{
+		SoC: 20,
+		LoadPoint: map[string]interface{}{
+			"mode":          "pv",
+			"guardDuration": "1m",
+			"enable": map[string]interface{}{
+				"delay": "1m",
+			},
+			"disable": map[string]interface{}{
+				"delay": "3m",
+			},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(h.Close)
+
+	connect := true
+
+	tc := []struct {
+		Scenario
+		enabled bool
+	}{
+		{Scenario{Name: "connect without sun", Duration: 10 * time.Second, Connect: &connect}, false},
+		{Scenario{Name: "sun starts enable timer", Duration: 10 * time.Second, PV: 6000}, false},
+		{Scenario{Name: "enable timer elapsed", Duration: time.Minute, PV: 6000}, true},
+		{Scenario{Name: "cloud starts disable timer", Duration: 10 * time.Second}, true},
+		{Scenario{Name: "cloud passed", Duration: time.Minute, PV: 6000}, true},
+		{Scenario{Name: "cloud again", Duration: 10 * time.Second}, true},
+		{Scenario{Name: "disable timer elapsed", Duration: 3 * time.Minute}, false},
+	}
+
+	for _, tc := range tc {
+		enabled, _ := h.Run(tc.Scenario)
+		assert.Equal(t, tc.enabled, enabled, tc.Name)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	lp := 0
+	start := time.Now()
+
+	snapshot := util.Snapshot{
+		State: map[string]interface{}{
+			"loadpoints": []interface{}{
+				map[string]interface{}{"mode": "now", "phasesConfigured": 3.0, "minCurrent": 6.0, "maxCurrent": 16.0},
+			},
+		},
+	}
+
+	for i, enabled := range []bool{false, true, true} {
+		ts := start.Add(time.Duration(i) * 10 * time.Second)
+		snapshot.Timeline = append(snapshot.Timeline,
+			util.TimedParam{Time: ts, LoadPoint: &lp, Key: "connected", Val: true},
+			util.TimedParam{Time: ts, LoadPoint: &lp, Key: "enabled", Val: enabled},
+			util.TimedParam{Time: ts, LoadPoint: &lp, Key: "chargeCurrent", Val: 16.0},
+			util.TimedParam{Time: ts, Key: "homePower", Val: 500.0},
+		)
+	}
+
+	res, err := Replay(snapshot)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	for i, d := range res {
+		assert.True(t, d.SimEnabled, i)
+		assert.Equal(t, 16.0, d.SimCurrent, i)
+	}
+
+	assert.True(t, res[0].Differs())
+	assert.False(t, res[2].Differs())
+
+	_, err = Replay(util.Snapshot{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// Decision is the recorded and simulated loadpoint control result of a single site update
+type Decision struct {
+	Time                time.Time
+	PV, Home            float64
+	Connected           bool
+	Enabled, SimEnabled bool
+	Current, SimCurrent float64
+}
+
+// Differs returns true if the simulated decision differs from the recorded one
+func (d Decision) Differs() bool {
+	return d.Enabled != d.SimEnabled || d.Enabled && d.Current != d.SimCurrent
+}
+
+// number converts json decoded values to float
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// loadpointOptions extracts the first loadpoint's settings from the snapshot state
+func loadpointOptions(state map[string]interface{}) (Options, bool) {
+	var o Options
+
+	lps, ok := state["loadpoints"].([]interface{})
+	if !ok || len(lps) == 0 {
+		return o, false
+	}
+
+	lp, ok := lps[0].(map[string]interface{})
+	if !ok {
+		return o, false
+	}
+
+	o.LoadPoint = make(map[string]interface{})
+
+	if mode, ok := lp["mode"].(string); ok {
+		o.LoadPoint["mode"] = mode
+	}
+
+	for _, key := range []string{"minCurrent", "maxCurrent"} {
+		if f, ok := number(lp[key]); ok && f > 0 {
+			o.LoadPoint[key] = f
+		}
+	}
+
+	if f, ok := number(lp["phasesConfigured"]); ok {
+		o.Phases = int(f)
+		o.PhaseSwitcher = o.Phases == 0
+	}
+
+	if f, ok := number(lp["vehicleCapacity"]); ok {
+		o.Capacity = f
+	}
+
+	if f, ok := number(lp["vehicleSoC"]); ok {
+		o.SoC = f
+	}
+
+	return o, true
+}
+
+// Replay runs the snapshot's recorded pv and home power through the control logic of
+// a simulated installation and returns the recorded and simulated decision per site update
+func Replay(snapshot util.Snapshot) ([]Decision, error) {
+	o, ok := loadpointOptions(snapshot.State)
+	if !ok {
+		return nil, errors.New("snapshot contains no loadpoint")
+	}
+
+	h, err := New(o)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+
+	timeline := snapshot.Timeline
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+
+	var res []Decision
+	var cur Decision
+	var last time.Time
+
+	for _, p := range timeline {
+		if p.LoadPoint != nil && *p.LoadPoint != 0 {
+			continue
+		}
+
+		val, _ := number(p.Val)
+		flag, _ := p.Val.(bool)
+
+		switch {
+		case p.LoadPoint == nil && p.Key == "pvPower":
+			cur.PV = val
+		case p.LoadPoint != nil && p.Key == "connected":
+			cur.Connected = flag
+		case p.LoadPoint != nil && p.Key == "enabled":
+			cur.Enabled = flag
+		case p.LoadPoint != nil && p.Key == "chargeCurrent":
+			cur.Current = val
+
+		// home power is published once per site update
+		case p.LoadPoint == nil && p.Key == "homePower":
+			cur.Home = val
+			cur.Time = p.Time
+
+			var d time.Duration
+			if !last.IsZero() {
+				d = p.Time.Sub(last)
+			}
+			last = p.Time
+
+			connected := cur.Connected
+			cur.SimEnabled, cur.SimCurrent = h.Run(Scenario{
+				Duration: d,
+				PV:       cur.PV,
+				Home:     cur.Home,
+				Connect:  &connected,
+			})
+
+			res = append(res, cur)
+		}
+	}
+
+	return res, nil
+}
+
+// PrintDecisions writes the decisions as table, marking differences between recorded and simulated control
+func PrintDecisions(w io.Writer, decisions []Decision) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "time\tpv (W)\thome (W)\tconnected\tenabled\tcurrent (A)\tsimulated enabled\tsimulated current (A)\t")
+
+	for _, d := range decisions {
+		mark := ""
+		if d.Differs() {
+			mark = "*"
+		}
+
+		fmt.Fprintf(tw, "%s\t%.0f\t%.0f\t%v\t%v\t%.3g\t%v\t%.3g\t%s\n",
+			d.Time.Local().Format("15:04:05"), d.PV, d.Home, d.Connected, d.Enabled, d.Current, d.SimEnabled, d.SimCurrent, mark)
+	}
+
+	return tw.Flush()
+}
@@ -1,6 +1,10 @@
 package core
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/avast/retry-go/v3"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
@@ -26,6 +30,11 @@ func powerToCurrent(power float64, phases int) float64 {
 	return power / (float64(phases) * Voltage)
 }
 
+// currentToPower is a helper function to convert per-phase current to power
+func currentToPower(current float64, phases int) float64 {
+	return current * float64(phases) * Voltage
+}
+
 // sitePower returns the available delta power that the charger might additionally consume
 // negative value: available power (grid export), positive value: grid import
 func sitePower(log *util.Logger, maxGrid, grid, battery, residual float64) float64 {
@@ -46,3 +55,42 @@ func vehicleTitles(vehicles []api.Vehicle) []string {
 		return v.Title()
 	})
 }
+
+// parseTimeWindow returns start and end minute of day of a HH:MM-HH:MM window
+func parseTimeWindow(window string) (int, int, error) {
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time window: %s", window)
+	}
+
+	var res [2]int
+	for i, s := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid time window: %s", window)
+		}
+		res[i] = t.Hour()*60 + t.Minute()
+	}
+
+	return res[0], res[1], nil
+}
+
+// inTimeWindows returns true if the time is inside one of the HH:MM-HH:MM windows, invalid windows are ignored
+func inTimeWindows(windows []string, now time.Time) bool {
+	current := now.Hour()*60 + now.Minute()
+
+	for _, window := range windows {
+		start, end, err := parseTimeWindow(window)
+		if err != nil {
+			continue
+		}
+
+		// windows may span midnight
+		if start <= end && current >= start && current < end ||
+			start > end && (current >= start || current < end) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,5 @@
+// Package sim gives the simulation harness access to the site's control cycle without adding it to the site's api.
+package sim
+
+// Step executes a single control cycle for all loadpoints of the given *core.Site. It is provided by package core.
+var Step func(site interface{})
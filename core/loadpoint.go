@@ -13,12 +13,18 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/core/selftest"
 	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/core/wrapper"
 	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/holiday"
 
 	evbus "github.com/asaskevich/EventBus"
 	"github.com/avast/retry-go/v3"
@@ -26,17 +32,17 @@ import (
 	"github.com/cjrd/allocate"
 	"github.com/emirpasic/gods/queues"
 	aq "github.com/emirpasic/gods/queues/arrayqueue"
+	"golang.org/x/text/currency"
 )
 
 const (
-	evChargeStart         = "start"      // update chargeTimer
-	evChargeStop          = "stop"       // update chargeTimer
-	evChargeCurrent       = "current"    // update fakeChargeMeter
-	evChargePower         = "power"      // update chargeRater
-	evVehicleConnect      = "connect"    // vehicle connected
-	evVehicleDisconnect   = "disconnect" // vehicle disconnected
-	evVehicleSoC          = "soc"        // vehicle soc progress
-	evVehicleUnidentified = "guest"      // vehicle unidentified
+	evChargeStart       = "start"      // update chargeTimer
+	evChargeStop        = "stop"       // update chargeTimer
+	evChargeCurrent     = "current"    // update fakeChargeMeter
+	evChargePower       = "power"      // update chargeRater
+	evVehicleConnect    = "connect"    // vehicle connected
+	evVehicleDisconnect = "disconnect" // vehicle disconnected
+	evVehicleSoC        = "soc"        // vehicle soc progress
 
 	pvTimer   = "pv"
 	pvEnable  = "enable"
@@ -59,15 +65,13 @@ const (
 var elapsed = time.Unix(0, 1)
 
 // PollConfig defines the vehicle polling mode and interval
-type PollConfig struct {
-	Mode     string        `mapstructure:"mode"`     // polling mode charging (default), connected, always
-	Interval time.Duration `mapstructure:"interval"` // interval when not charging
-}
+type PollConfig = api.PollConfig
 
 // SoCConfig defines soc settings, estimation and update behaviour
 type SoCConfig struct {
 	Poll     PollConfig `mapstructure:"poll"`
 	Estimate *bool      `mapstructure:"estimate"`
+	Source   soc.Source `mapstructure:"source"` // auto, charger or vehicle
 	Min_     int        `mapstructure:"min"`    // TODO deprecated
 	Target_  int        `mapstructure:"target"` // TODO deprecated
 	min      int        // Default minimum SoC, guarded by mutex
@@ -83,6 +87,21 @@ const (
 	pollInterval = 60 * time.Minute
 )
 
+// PlannerConfig defines target charge planning behaviour
+type PlannerConfig struct {
+	Solar bool `mapstructure:"solar"` // defer target charging while the solar forecast covers the required energy
+}
+
+// TariffConfig overrides the site's grid tariff and currency for the loadpoint
+type TariffConfig struct {
+	Currency string                 `mapstructure:"currency"`
+	Fees     float64                `mapstructure:"fees"`         // grid fees and taxes per kWh added to the tariff price
+	Limit    float64                `mapstructure:"limit"`        // charge at max current in min+pv and pv mode up to this grid price including fees, replaces the tariff's cheap price
+	Windows  []string               `mapstructure:"cheapWindows"` // fixed HH:MM-HH:MM windows of static dual tariffs, min+pv mode charges at max current
+	Type     string                 `mapstructure:"type"`
+	Other    map[string]interface{} `mapstructure:",remain"`
+}
+
 // ThresholdConfig defines enable/disable hysteresis parameters
 type ThresholdConfig struct {
 	Delay     time.Duration
@@ -92,12 +111,12 @@ type ThresholdConfig struct {
 // LoadPoint is responsible for controlling charge depending on
 // SoC needs and power availability.
 type LoadPoint struct {
-	clock    clock.Clock       // mockable time
-	bus      evbus.Bus         // event bus
-	pushChan chan<- push.Event // notifications
-	uiChan   chan<- util.Param // client push messages
-	lpChan   chan<- *LoadPoint // update requests
-	log      *util.Logger
+	clock  clock.Clock       // mockable time
+	bus    evbus.Bus         // event bus
+	events event.Publisher   // notifications and integrations
+	uiChan chan<- util.Param // client push messages
+	lpChan chan<- *LoadPoint // update requests
+	log    *util.Logger
 
 	// exposed public configuration
 	sync.Mutex                // guard status
@@ -109,24 +128,51 @@ type LoadPoint struct {
 	VehicleRef        string   `mapstructure:"vehicle"`  // Vehicle reference
 	VehiclesRef_      []string `mapstructure:"vehicles"` // TODO deprecated
 	MeterRef          string   `mapstructure:"meter"`    // Charge meter reference
+	CircuitRef        string   `mapstructure:"circuit"`  // Circuit reference
+	Priority          int      `mapstructure:"priority"` // Circuit queue priority, higher priorities preempt lower ones
 	SoC               SoCConfig
+	Planner           PlannerConfig
+	Tariff            TariffConfig
+	Plans             []PlanConfig
+	Monitor           MonitorConfig
+	Climate           ClimateConfig
+	Watchdog          WatchdogConfig
+	Idle              IdleConfig
+	Ramp              RampConfig
+	Budgets           []BudgetConfig
+	Authorization     []AuthorizationConfig
 	Enable, Disable   ThresholdConfig
-	ResetOnDisconnect bool `mapstructure:"resetOnDisconnect"`
+	PhaseSwitch       PhaseSwitchConfig    `mapstructure:"phaseSwitch"`
+	ResetOnDisconnect bool                 `mapstructure:"resetOnDisconnect"`
+	ContinuousLoad    ContinuousLoadConfig `mapstructure:"continuousLoad"`
 	onDisconnect      api.ActionConfig
 	targetEnergy      float64 // Target charge energy for dumb vehicles
 
 	MinCurrent    float64       // PV mode: start current	Min+PV mode: min current
 	MaxCurrent    float64       // Max allowed current. Physically ensured by the charger
 	GuardDuration time.Duration // charger enable/disable minimum holding time
-
-	enabled             bool      // Charger enabled state
-	phases              int       // Charger enabled phases, guarded by mutex
-	measuredPhases      int       // Charger physically measured phases
-	chargeCurrent       float64   // Charger current limit
-	guardUpdated        time.Time // Charger enabled/disabled timestamp
-	socUpdated          time.Time // SoC updated timestamp (poll: connected)
-	vehicleDetect       time.Time // Vehicle connected timestamp
+	RemoteTimeout time.Duration // revert to local control if external controller heartbeat stops
+
+	enabled             bool                // Charger enabled state
+	phases              int                 // Charger enabled phases, guarded by mutex
+	measuredPhases      int                 // Charger physically measured phases
+	vehiclePhases       map[api.Vehicle]int // Phases detected while charging by vehicle
+	chargeCurrent       float64             // Charger current limit
+	guardUpdated        time.Time           // Charger enabled/disabled timestamp
+	rampUpdated         time.Time           // Charger current ramp step timestamp
+	switchCycles        []time.Time         // Charger enables within the last hour
+	currentWritten      time.Time           // Charger current limit written timestamp
+	socUpdated          time.Time           // SoC updated timestamp (poll: connected)
+	socSyncLimit        int                 // Vehicle charge limit last written by soc sync
+	vehicleDetect       time.Time           // Vehicle connected timestamp
 	vehicleDetectTicker *clock.Ticker
+	idleSince           time.Time        // Vehicle idle timestamp
+	idleFreed           bool             // Charger freed after idle timeout
+	idleUnlocked        bool             // Cable unlocked after idle timeout
+	traceID             string           // Trace id of the connected vehicle's session
+	selfTest            *selftest.Report // Last charger self-test report, guarded by mutex
+	selfTestRunning     bool             // Charger control suspended by self-test, guarded by mutex
+	decision            string           // Last recorded decision
 	vehicleIdentifier   string
 
 	charger     api.Charger
@@ -138,24 +184,78 @@ type LoadPoint struct {
 	defaultVehicle api.Vehicle // Default vehicle (disables detection)
 	coordinator    coordinator.API
 	socEstimator   *soc.Estimator
+	vehicleProfile *soc.Profile // Learned capacity and consumption of the active vehicle
 	socTimer       *soc.Timer
+	tariff         api.Tariff        // Grid tariff used for cost accounting
+	feedIn         api.Tariff        // Feed-in tariff applied to charged solar energy
+	holidays       *holiday.Calendar // Public holidays for plan scheduling
+	currency       currency.Unit     // Currency of the grid tariff
+	jitter         *jitter           // Randomized switching delay
 
 	// cached state
-	status         api.ChargeStatus       // Charger status
-	remoteDemand   loadpoint.RemoteDemand // External status demand
-	chargePower    float64                // Charging power
-	chargeCurrents []float64              // Phase currents
-	connectedTime  time.Time              // Time when vehicle was connected
-	pvTimer        time.Time              // PV enabled/disable timer
-	phaseTimer     time.Time              // 1p3p switch timer
-	wakeUpTimer    *Timer                 // Vehicle wake-up timeout
+	status         api.ChargeStatus        // Charger status
+	remoteDemand   loadpoint.RemoteDemand  // External status demand
+	guestSession   *loadpoint.GuestSession // Ad-hoc guest charging session
+	guestEnergy    float64                 // Wh charged before the guest session started
+	remoteBudgets  map[string]remoteBudget // External power budgets by source
+	remoteSource   string                  // External controller
+	remoteUpdated  time.Time               // External controller heartbeat
+	chargePower    float64                 // Charging power
+	chargeCurrents []float64               // Phase currents
+	connectedTime  time.Time               // Time when vehicle was connected
+	pvTimer        time.Time               // PV enabled/disable timer
+	phaseTimer     time.Time               // 1p3p switch timer
+	phaseSwitched  time.Time               // Last 1p3p switch
+	phasePause     time.Time               // Charge pause around 1p3p switch started
+	phasePending   int                     // Phases to switch to after the charge pause
+	wakeUpTimer    *Timer                  // Vehicle wake-up timeout
+	wakeUpPlanned  time.Time               // Planned charge start the vehicle was woken up for
+	planTime       time.Time               // Target time set from repeating plan
+	planConsumed   time.Time               // Plan occurrence whose target charge has been removed, e.g. target reached
+	planUserSoC    int                     // User target soc replaced by the plan's soc, 0 if not replaced
+	climateTarget  time.Time               // Target time climatisation was started for
+	schedulesKey   string                  // Settings key of persisted plans
+	fault          string                  // Active charger fault
+	voltageSag     bool                    // Supply voltage below min voltage
+	chargerUpdated time.Time               // Last successful charger status update
+	writeErrors    int                     // Consecutive failed charger writes
+	writeFaulted   time.Time               // Write fault raised
+	meterUpdated   time.Time               // Last successful charge meter update
+	apiError       string                  // Charger or charge meter communication lost
+	curtailed      bool                    // Site curtailment active
+	currentIgnored bool                    // Charger did not apply the current limit
+	indication     api.Indication          // State shown by charger leds or displays
+	islandBudget   *float64                // Off-grid charging power budget
+	gridBudget     *float64                // Grid operator load reduction power budget
+	targetReached  bool                    // Target reached event sent
+	stateKey       string                  // Settings key of persisted transient state
+	restored       *loadpointState         // Persisted session pending continuation at startup
+	peakBudget     *float64                // Peak shaving power budget
+	circuit        *circuit                // Circuit supplying the loadpoint
+	circuitBudget  *float64                // Remaining per-phase current of the circuits
+	validation     []string                // Configuration warnings
+	phasesMismatch bool                    // Measured phases exceed fixed phase configuration
+	budgetMonth    time.Time               // Month of budget usage
+	budgetSession  *db.Session             // Session excluded from budget usage
+	budgetUsage    []budgetUsage           // Budget usage of completed sessions
+	budgetLimited  bool                    // Budget exceeded, restricted to pv
+	authorizations []authorization         // Allowlist of identifiers
+	authorized     *authorization          // Authorization of the connected vehicle
+	unauthorizedID string                  // Rejected identifier
 
 	// charge progress
 	vehicleSoc              float64       // Vehicle SoC
+	odometer                float64       // Vehicle odometer read after connecting
+	arrivalPending          bool          // Consumption since last departure not learned yet
 	chargeDuration          time.Duration // Charge duration
 	chargedEnergy           float64       // Charged energy while connected in Wh
 	chargeRemainingDuration time.Duration // Remaining charge duration
 	chargeRemainingEnergy   float64       // Remaining charge energy in Wh
+	sessionCost             float64       // Cost of charged energy while connected
+	costSlots               db.CostSlots  // Session cost per tariff period
+	costEnergy              float64       // Charged energy already accounted for in session cost in Wh
+	solarEnergy             float64       // Charged solar energy while connected in Wh
+	greenShare              float64       // Solar and battery share of the current charge power, guarded by mutex
 	progress                *Progress     // Step-wise progress indicator
 
 	// session log
@@ -172,18 +272,21 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 		return nil, err
 	}
 
-	// set vehicle polling mode
-	switch lp.SoC.Poll.Mode = strings.ToLower(lp.SoC.Poll.Mode); lp.SoC.Poll.Mode {
-	case pollCharging:
-	case pollConnected, pollAlways:
-		lp.log.WARN.Printf("poll mode '%s' may deplete your battery or lead to API misuse. USE AT YOUR OWN RISK.", lp.SoC.Poll)
-	default:
-		if lp.SoC.Poll.Mode != "" {
-			lp.log.WARN.Printf("invalid poll mode: %s", lp.SoC.Poll.Mode)
-		}
+	// set vehicle polling mode and validate schedule
+	if err := validatePoll(lp.log, "soc", &lp.SoC.Poll); err != nil {
+		return nil, err
+	}
+	if lp.SoC.Poll.Mode == "" {
 		lp.SoC.Poll.Mode = pollCharging
 	}
 
+	// set vehicle soc source
+	source, err := soc.SourceString(string(lp.SoC.Source))
+	if err != nil {
+		return nil, err
+	}
+	lp.SoC.Source = source
+
 	// set vehicle polling interval
 	if lp.SoC.Poll.Interval < pollInterval {
 		if lp.SoC.Poll.Interval == 0 {
@@ -235,12 +338,46 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 	if lp.ChargerRef == "" {
 		return nil, errors.New("missing charger")
 	}
-	var err error
 	if lp.charger, err = cp.Charger(lp.ChargerRef); err != nil {
 		return nil, err
 	}
 	lp.configureChargerType(lp.charger)
 
+	if lp.continuousLoad() && !lp.HasChargeMeter() {
+		return nil, errors.New("continuous load requires a charge meter")
+	}
+
+	if err := lp.configureAuthorizations(cp); err != nil {
+		return nil, fmt.Errorf("authorization: %w", err)
+	}
+
+	if err := validatePlans(lp.Plans); err != nil {
+		return nil, err
+	}
+
+	if m := lp.Monitor; m.CutoffVoltage > 0 && m.MinVoltage > 0 && m.CutoffVoltage >= m.MinVoltage {
+		lp.log.WARN.Printf("monitor: cutoff voltage %.0fV should be below min voltage %.0fV", m.CutoffVoltage, m.MinVoltage)
+	}
+
+	// loadpoint specific tariff
+	if lp.Tariff.Type != "" {
+		if lp.tariff, err = tariff.NewFromConfig(lp.Tariff.Type, lp.Tariff.Other); err != nil {
+			return nil, fmt.Errorf("tariff: %w", err)
+		}
+	}
+
+	if lp.Tariff.Currency != "" {
+		if lp.currency, err = currency.ParseISO(lp.Tariff.Currency); err != nil {
+			return nil, fmt.Errorf("tariff: %w", err)
+		}
+	}
+
+	for _, window := range lp.Tariff.Windows {
+		if _, _, err := parseTimeWindow(window); err != nil {
+			return nil, fmt.Errorf("tariff: %w", err)
+		}
+	}
+
 	// setup fixed phases:
 	// - simple charger starts with phases config if specified or 3p
 	// - switchable charger starts at 0p since we don't know the current setting
@@ -261,6 +398,10 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 		lp.log.WARN.Printf("PV mode enable threshold %.0fW > 0 will start PV charging on grid power consumption. Did you mean -%.0f?", lp.Enable.Threshold, lp.Enable.Threshold)
 	}
 
+	if ps := lp.PhaseSwitch; ps.Scale1p.Threshold > 0 && ps.Scale3p.Threshold > 0 && ps.Scale1p.Threshold-ps.Hysteresis > ps.Scale3p.Threshold+ps.Hysteresis {
+		lp.log.WARN.Printf("phase switch 1p threshold (%.0fW) is larger than 3p threshold (%.0fW)", ps.Scale1p.Threshold, ps.Scale3p.Threshold)
+	}
+
 	return lp, nil
 }
 
@@ -281,6 +422,7 @@ func NewLoadPoint(log *util.Logger) *LoadPoint {
 		Enable:        ThresholdConfig{Delay: time.Minute, Threshold: 0},     // t, W
 		Disable:       ThresholdConfig{Delay: 3 * time.Minute, Threshold: 0}, // t, W
 		GuardDuration: 5 * time.Minute,
+		RemoteTimeout: loadpoint.RemoteTimeout,
 		progress:      NewProgress(0, 10),     // soc progress indicator
 		coordinator:   coordinator.NewDummy(), // dummy vehicle coordinator
 		tasks:         aq.New(),               // task queue
@@ -328,6 +470,9 @@ func (lp *LoadPoint) configureChargerType(charger api.Charger) {
 
 		if mt, ok := charger.(api.Meter); ok {
 			lp.chargeMeter = mt
+		} else if mt, ok := charger.(api.MeterEnergy); ok {
+			// derive power from the charger's energy counter
+			lp.chargeMeter = wrapper.NewEnergyMeter(mt.TotalEnergy, 0)
 		} else {
 			mt := new(wrapper.ChargeMeter)
 			_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentWrappedMeterHandler)
@@ -365,9 +510,19 @@ func (lp *LoadPoint) configureChargerType(charger api.Charger) {
 	lp.wakeUpTimer = NewTimer()
 }
 
-// pushEvent sends push messages to clients
-func (lp *LoadPoint) pushEvent(event string) {
-	lp.pushChan <- push.Event{Event: event}
+// pushEvent publishes the event to push messages and other subscribers
+func (lp *LoadPoint) pushEvent(ev event.Event) {
+	if lp.events != nil {
+		lp.events(ev)
+	}
+}
+
+// pushTargetReached sends the target reached event once until the target is no longer reached
+func (lp *LoadPoint) pushTargetReached(reached bool) {
+	if reached && !lp.targetReached {
+		lp.pushEvent(event.TargetReached{SoC: lp.vehicleSoc, Energy: lp.getChargedEnergy()})
+	}
+	lp.targetReached = reached
 }
 
 // publish sends values to UI and databases
@@ -380,20 +535,18 @@ func (lp *LoadPoint) publish(key string, val interface{}) {
 // evChargeStartHandler sends external start event
 func (lp *LoadPoint) evChargeStartHandler() {
 	lp.log.INFO.Println("start charging ->")
-	lp.pushEvent(evChargeStart)
+	lp.pushEvent(event.ChargeStarted{})
 
 	lp.wakeUpTimer.Stop()
 
 	// soc update reset
 	lp.socUpdated = time.Time{}
-
-	lp.startSession()
 }
 
 // evChargeStopHandler sends external stop event
 func (lp *LoadPoint) evChargeStopHandler() {
 	lp.log.INFO.Println("stop charging <-")
-	lp.pushEvent(evChargeStop)
+	lp.pushEvent(event.ChargeStopped{Energy: lp.getChargedEnergy()})
 
 	// soc update reset
 	lp.socUpdated = time.Time{}
@@ -403,8 +556,6 @@ func (lp *LoadPoint) evChargeStopHandler() {
 	if !lp.pvTimer.Equal(elapsed) {
 		lp.resetPVTimerIfRunning()
 	}
-
-	lp.stopSession()
 }
 
 // evVehicleConnectHandler sends external start event
@@ -413,11 +564,14 @@ func (lp *LoadPoint) evVehicleConnectHandler() {
 
 	// energy
 	lp.setChargedEnergy(0)
-	lp.publish("chargedEnergy", lp.getChargedEnergy())
+	lp.publish(state.ChargedEnergy, lp.getChargedEnergy())
+
+	// cost
+	lp.resetSessionCost()
 
 	// duration
 	lp.connectedTime = lp.clock.Now()
-	lp.publish("connectedDuration", time.Duration(0))
+	lp.publish(state.ConnectedDuration, time.Duration(0))
 
 	// soc update reset
 	lp.socUpdated = time.Time{}
@@ -427,31 +581,59 @@ func (lp *LoadPoint) evVehicleConnectHandler() {
 		lp.socEstimator.Reset()
 	}
 
+	// learn consumption once soc and odometer are known
+	lp.odometer = 0
+	lp.arrivalPending = true
+
+	// continue session interrupted by restart
+	lp.restoreSession()
+
 	// set default or start detection
 	lp.vehicleDefaultOrDetect()
 
 	// immediately allow pv mode activity
 	lp.elapsePVTimer()
+
+	// trace session decisions
+	lp.startTrace()
+	lp.decide("vehicle connected")
 }
 
 // evVehicleDisconnectHandler sends external start event
 func (lp *LoadPoint) evVehicleDisconnectHandler() {
 	lp.log.INFO.Println("car disconnected")
 
-	// ensure session is persisted and closed before vehicle is changed
-	lp.stopSession()
-	lp.finalizeSession()
+	// energy and duration
+	lp.publish(state.ChargedEnergy, lp.getChargedEnergy())
+	lp.publish(state.ConnectedDuration, lp.clock.Since(lp.connectedTime))
+
+	// closes the session before the vehicle is changed, no event without connect during startup
+	if !lp.connectedTime.IsZero() {
+		lp.pushEvent(event.VehicleDisconnected{Duration: lp.clock.Since(lp.connectedTime)})
+	}
+
+	// learn capacity before vehicle is changed
+	lp.learnDeparture()
+
+	// guest session ends with vehicle
+	lp.stopGuestSessionOnDisconnect()
 
 	// phases are unknown when vehicle disconnects
 	lp.resetMeasuredPhases()
+	lp.resetPhasePause()
 
-	// energy and duration
-	lp.publish("chargedEnergy", lp.getChargedEnergy())
-	lp.publish("connectedDuration", lp.clock.Since(lp.connectedTime))
+	// charger is available again
+	lp.resetIdle()
+
+	lp.stopTrace()
 
 	// remove charger vehicle id and stop potential detection
 	lp.setVehicleIdentifier("")
 	lp.stopVehicleDetection()
+	lp.resetAuthorization()
+
+	// plan target charge ends with the vehicle, restore the user target before vehicle and mode are reset
+	lp.resetPlan()
 
 	// remove active vehicle if not default
 	if lp.vehicle != lp.defaultVehicle {
@@ -477,7 +659,7 @@ func (lp *LoadPoint) evVehicleDisconnectHandler() {
 // evVehicleSoCProgressHandler sends external start event
 func (lp *LoadPoint) evVehicleSoCProgressHandler(soc float64) {
 	if lp.progress.NextStep(soc) {
-		lp.pushEvent(evVehicleSoC)
+		lp.pushEvent(event.VehicleSoC{SoC: soc})
 	}
 }
 
@@ -486,7 +668,7 @@ func (lp *LoadPoint) evChargeCurrentHandler(current float64) {
 	if !lp.enabled {
 		current = 0
 	}
-	lp.publish("chargeCurrent", current)
+	lp.publish(state.ChargeCurrent, current)
 }
 
 // evChargeCurrentWrappedMeterHandler updates the dummy charge meter's charge power.
@@ -533,7 +715,9 @@ func (lp *LoadPoint) Name() string {
 // Prepare loadpoint configuration by adding missing helper elements
 func (lp *LoadPoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event, lpChan chan<- *LoadPoint) {
 	lp.uiChan = uiChan
-	lp.pushChan = pushChan
+	if lp.events == nil {
+		lp.events = pushPublisher(lp, pushChan)
+	}
 	lp.lpChan = lpChan
 
 	// event handlers
@@ -545,29 +729,33 @@ func (lp *LoadPoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 	_ = lp.bus.Subscribe(evVehicleSoC, lp.evVehicleSoCProgressHandler)
 
 	// publish initial values
-	lp.publish("title", lp.Title)
-	lp.publish("minCurrent", lp.MinCurrent)
-	lp.publish("maxCurrent", lp.MaxCurrent)
+	lp.publish(state.Title, lp.Title)
+	lp.publish(state.Currency, lp.currency.String())
+	lp.publish(state.MinCurrent, lp.MinCurrent)
+	lp.publish(state.MaxCurrent, lp.MaxCurrent)
 
 	lp.setConfiguredPhases(lp.ConfiguredPhases)
-	lp.publish(phasesEnabled, lp.phases)
-	lp.publish(phasesActive, lp.activePhases())
+	lp.publish(state.PhasesEnabled, lp.phases)
+	lp.publish(state.PhasesActive, lp.activePhases())
 	lp.publishTimer(phaseTimer, 0, timerInactive)
 	lp.publishTimer(pvTimer, 0, timerInactive)
 
+	// warn about common misconfigurations
+	lp.publishLint()
+
 	// assign and publish default vehicle
 	if lp.defaultVehicle != nil {
 		lp.setActiveVehicle(lp.defaultVehicle)
 	}
 
 	lp.Lock()
-	lp.publish("mode", lp.Mode)
-	lp.publish("targetSoC", lp.SoC.target)
-	lp.publish("minSoC", lp.SoC.min)
+	lp.publish(state.Mode, lp.Mode)
+	lp.publish(state.TargetSoC, lp.SoC.target)
+	lp.publish(state.MinSoC, lp.SoC.min)
 	lp.Unlock()
 
 	// reset detection state
-	lp.publish(vehicleDetectionActive, false)
+	lp.publish(state.VehicleDetectionActive, false)
 
 	// read initial charger state to prevent immediately disabling charger
 	if enabled, err := lp.charger.Enabled(); err == nil {
@@ -607,24 +795,59 @@ func (lp *LoadPoint) syncCharger() {
 	}
 }
 
+// writeCurrent sends the current limit to the charger and returns the current as applied by the charger
+func (lp *LoadPoint) writeCurrent(current float64) (float64, error) {
+	var err error
+	if charger, ok := lp.charger.(api.PowerController); ok {
+		// power controlled chargers, e.g. DC, receive the power equivalent of the current
+		err = charger.MaxPower(currentToPower(current, lp.activePhases()))
+	} else if charger, ok := lp.charger.(api.ChargerEx); ok && !lp.vehicleHasFeature(api.CoarseCurrent) {
+		err = charger.MaxCurrentMillis(current)
+	} else {
+		current = math.Trunc(current)
+		err = lp.charger.MaxCurrent(int64(current))
+	}
+
+	if err == nil {
+		lp.currentWritten = lp.clock.Now()
+	}
+
+	return current, err
+}
+
 // setLimit applies charger current limits and enables/disables accordingly
 func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
+	// apply external power budget
+	chargeCurrent = lp.remoteBudgetCurrent(chargeCurrent)
+
+	// apply ramp rates, protection limits below still apply immediately
+	chargeCurrent = lp.rampCurrent(chargeCurrent)
+
+	// apply charger fault and undervoltage limits
+	chargeCurrent = lp.monitorCurrent(chargeCurrent)
+	force = force || lp.fault != api.FaultNone
+
+	// apply site curtailment, off-grid, grid operator and peak shaving budget
+	chargeCurrent = lp.curtailCurrent(chargeCurrent)
+	chargeCurrent = lp.islandCurrent(chargeCurrent)
+	chargeCurrent = lp.gridSignalCurrent(chargeCurrent)
+	chargeCurrent = lp.peakShavingCurrent(chargeCurrent)
+	force = force || lp.curtailed
+
+	// apply circuit limits, disable immediately to prevent fuse trips
+	chargeCurrent = lp.circuitCurrent(chargeCurrent)
+	force = force || lp.circuitExceeded()
+
 	// set current
 	if chargeCurrent != lp.chargeCurrent && chargeCurrent >= lp.GetMinCurrent() {
 		var err error
-		if charger, ok := lp.charger.(api.ChargerEx); ok && !lp.vehicleHasFeature(api.CoarseCurrent) {
-			err = charger.MaxCurrentMillis(chargeCurrent)
-		} else {
-			chargeCurrent = math.Trunc(chargeCurrent)
-			err = lp.charger.MaxCurrent(int64(chargeCurrent))
-		}
-
-		if err != nil {
+		if chargeCurrent, err = lp.writeCurrent(chargeCurrent); err != nil {
 			return fmt.Errorf("max charge current %.3gA: %w", chargeCurrent, err)
 		}
 
 		lp.log.DEBUG.Printf("max charge current: %.3gA", chargeCurrent)
 		lp.chargeCurrent = chargeCurrent
+		lp.rampUpdated = lp.clock.Now()
 		lp.bus.Publish(evChargeCurrent, chargeCurrent)
 	}
 
@@ -635,6 +858,18 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 			return nil
 		}
 
+		// minimum on/off times and switching debounce
+		if remaining := lp.switchDelay(enabled); remaining > 0 && !force {
+			lp.log.DEBUG.Printf("charger %s: switching delay %v", status[enabled], remaining)
+			return nil
+		}
+
+		// spread switching of many loadpoints
+		if remaining := lp.jitterDelay(enabled); remaining > 0 && !force {
+			lp.log.DEBUG.Printf("charger %s: random delay %v", status[enabled], remaining)
+			return nil
+		}
+
 		// remote stop
 		// TODO https://github.com/evcc-io/evcc/discussions/1929
 		// if car, ok := lp.vehicle.(api.VehicleChargeController); !enabled && ok {
@@ -651,6 +886,11 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 		lp.log.DEBUG.Printf("charger %s", status[enabled])
 		lp.enabled = enabled
 		lp.guardUpdated = lp.clock.Now()
+		lp.resetJitter()
+
+		if enabled {
+			lp.addSwitchCycle()
+		}
 
 		lp.bus.Publish(evChargeCurrent, chargeCurrent)
 
@@ -671,6 +911,8 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 		// 		lp.log.ERROR.Printf("vehicle remote charge start: %v", err)
 		// 	}
 		// }
+	} else {
+		lp.resetJitter()
 	}
 
 	return nil
@@ -737,7 +979,7 @@ func (lp *LoadPoint) climateActive() bool {
 				}
 			}
 
-			lp.publish("climater", status)
+			lp.publish(state.Climater, status)
 			return active
 		}
 
@@ -772,7 +1014,7 @@ func (lp *LoadPoint) remoteControlled(demand loadpoint.RemoteDemand) bool {
 func (lp *LoadPoint) setVehicleIdentifier(id string) {
 	if lp.vehicleIdentifier != id {
 		lp.vehicleIdentifier = id
-		lp.publish("vehicleIdentity", id)
+		lp.publish(state.VehicleIdentity, id)
 	}
 }
 
@@ -838,6 +1080,20 @@ func (lp *LoadPoint) selectVehicleByID(id string) api.Vehicle {
 	return nil
 }
 
+// estimateSoC resolves the optional soc estimation config
+func (lp *LoadPoint) estimateSoC() bool {
+	return lp.SoC.Estimate == nil || *lp.SoC.Estimate
+}
+
+// newSoCEstimator creates the soc estimator using the configured soc source
+func (lp *LoadPoint) newSoCEstimator(charger api.Charger, vehicle api.Vehicle) *soc.Estimator {
+	se := soc.NewEstimator(lp.log, charger, vehicle, lp.estimateSoC())
+	if lp.SoC.Source != "" {
+		se.SetSource(lp.SoC.Source)
+	}
+	return se
+}
+
 // setActiveVehicle assigns currently active vehicle, configures soc estimator
 // and adds an odometer task
 func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
@@ -866,17 +1122,14 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 
 	if lp.vehicle = vehicle; vehicle != nil {
 		lp.socUpdated = time.Time{}
+		lp.socSyncLimit = 0
 
-		// resolve optional config
-		var estimate bool
-		if lp.SoC.Estimate == nil || *lp.SoC.Estimate {
-			estimate = true
-		}
-		lp.socEstimator = soc.NewEstimator(lp.log, lp.charger, vehicle, estimate)
+		lp.socEstimator = lp.newSoCEstimator(lp.charger, vehicle)
+		lp.loadVehicleProfile()
 
-		lp.publish("vehiclePresent", true)
-		lp.publish("vehicleTitle", lp.vehicle.Title())
-		lp.publish("vehicleCapacity", lp.vehicle.Capacity())
+		lp.publish(state.VehiclePresent, true)
+		lp.publish(state.VehicleTitle, lp.vehicle.Title())
+		lp.publish(state.VehicleCapacity, lp.vehicle.Capacity())
 
 		// unblock api
 		lp.Unlock()
@@ -888,11 +1141,12 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 		lp.progress.Reset()
 	} else {
 		lp.socEstimator = nil
+		lp.vehicleProfile = nil
 
-		lp.publish("vehiclePresent", false)
-		lp.publish("vehicleTitle", "")
-		lp.publish("vehicleCapacity", int64(0))
-		lp.publish(vehicleOdometer, 0.0)
+		lp.publish(state.VehiclePresent, false)
+		lp.publish(state.VehicleTitle, "")
+		lp.publish(state.VehicleCapacity, int64(0))
+		lp.publish(state.VehicleOdometer, 0.0)
 	}
 
 	// reset target energy
@@ -900,7 +1154,7 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 
 	// re-publish vehicle settings
 	lp.Unlock()
-	lp.publish(phasesActive, lp.activePhases())
+	lp.publish(state.PhasesActive, lp.activePhases())
 	lp.Lock()
 
 	lp.unpublishVehicle()
@@ -912,6 +1166,10 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 		}
 
 		lp.session.Vehicle = title
+
+		if lp.authorized == nil {
+			lp.session.User = lp.sessionUser(lp.session)
+		}
 	})
 }
 
@@ -924,11 +1182,21 @@ func (lp *LoadPoint) wakeUpVehicle() {
 		return
 	}
 
-	// vehicle
-	if vs, ok := lp.vehicle.(api.Resurrector); ok {
-		if err := vs.WakeUp(); err != nil {
-			lp.log.ERROR.Printf("wake-up vehicle: %v", err)
+	// vehicle, rate limited
+	if lp.vehicle == nil {
+		return
+	}
+
+	vs, err := vehicleWakeUps.allow(lp.vehicle)
+	if err != nil {
+		if errors.Is(err, api.ErrMustRetry) {
+			lp.log.DEBUG.Printf("wake-up vehicle: %v", err)
 		}
+		return
+	}
+
+	if err := vs.WakeUp(); err != nil {
+		lp.log.ERROR.Printf("wake-up vehicle: %v", err)
 	}
 }
 
@@ -936,9 +1204,9 @@ func (lp *LoadPoint) wakeUpVehicle() {
 func (lp *LoadPoint) unpublishVehicle() {
 	lp.vehicleSoc = 0
 
-	lp.publish("vehicleSoC", 0.0)
-	lp.publish(vehicleRange, int64(0))
-	lp.publish(vehicleTargetSoC, 0.0)
+	lp.publish(state.VehicleSoC, 0.0)
+	lp.publish(state.VehicleRange, int64(0))
+	lp.publish(state.VehicleTargetSoC, 0.0)
 
 	lp.setRemainingDuration(-1)
 
@@ -970,7 +1238,7 @@ func (lp *LoadPoint) vehicleUnidentified() bool {
 	// stop detection
 	if lp.clock.Since(lp.vehicleDetect) > vehicleDetectDuration {
 		lp.stopVehicleDetection()
-		lp.pushEvent(evVehicleUnidentified)
+		lp.pushEvent(event.VehicleUnidentified{})
 		return false
 	}
 
@@ -985,8 +1253,20 @@ func (lp *LoadPoint) vehicleUnidentified() bool {
 	return true
 }
 
-// vehicleDefaultOrDetect will assign and update default vehicle or start detection
+// vehicleDefaultOrDetect will assign and update default vehicle or start detection.
+// A default vehicle parked outside the site geofence is not assumed to be connected.
 func (lp *LoadPoint) vehicleDefaultOrDetect() {
+	if lp.defaultVehicle != nil && !lp.vehicleAtSite(lp.defaultVehicle) {
+		lp.log.INFO.Printf("default vehicle outside geofence: %s", lp.defaultVehicle.Title())
+		lp.setActiveVehicle(nil)
+
+		if len(lp.coordinatedVehicles()) > 0 && lp.connected() {
+			lp.startVehicleDetection()
+		}
+
+		return
+	}
+
 	if lp.defaultVehicle != nil {
 		if lp.vehicle != lp.defaultVehicle {
 			lp.setActiveVehicle(lp.defaultVehicle)
@@ -1008,7 +1288,7 @@ func (lp *LoadPoint) startVehicleDetection() {
 
 	lp.vehicleDetect = lp.clock.Now()
 	lp.vehicleDetectTicker = lp.clock.Ticker(vehicleDetectInterval)
-	lp.publish(vehicleDetectionActive, true)
+	lp.publish(state.VehicleDetectionActive, true)
 }
 
 // stopVehicleDetection expires the connection timer and ticker
@@ -1017,7 +1297,7 @@ func (lp *LoadPoint) stopVehicleDetection() {
 	if lp.vehicleDetectTicker != nil {
 		lp.vehicleDetectTicker.Stop()
 	}
-	lp.publish(vehicleDetectionActive, false)
+	lp.publish(state.VehicleDetectionActive, false)
 }
 
 // identifyVehicleByStatus validates if the active vehicle is still connected to the loadpoint
@@ -1028,7 +1308,7 @@ func (lp *LoadPoint) identifyVehicleByStatus() {
 
 	_, ok := lp.charger.(api.Identifier)
 
-	if vehicle := lp.coordinator.IdentifyVehicleByStatus(!ok); vehicle != nil {
+	if vehicle := lp.coordinator.IdentifyVehicleByStatus(!ok, lp.charging()); vehicle != nil {
 		lp.stopVehicleDetection()
 		lp.setActiveVehicle(vehicle)
 		return
@@ -1045,7 +1325,10 @@ func (lp *LoadPoint) vehicleOdometer() {
 	if vs, ok := lp.vehicle.(api.VehicleOdometer); ok {
 		if odo, err := vs.Odometer(); err == nil {
 			lp.log.DEBUG.Printf("vehicle odometer: %.0fkm", odo)
-			lp.publish(vehicleOdometer, odo)
+			lp.publish(state.VehicleOdometer, odo)
+
+			lp.odometer = odo
+			lp.learnArrival()
 
 			// update session once odometer is read
 			lp.updateSession(func(session *db.Session) {
@@ -1086,11 +1369,22 @@ func statusEvents(prevStatus, status api.ChargeStatus) []string {
 
 // updateChargerStatus updates charger status and detects car connected/disconnected events
 func (lp *LoadPoint) updateChargerStatus() error {
-	status, err := lp.charger.Status()
-	if err != nil {
-		return err
+	var status api.ChargeStatus
+
+	if lp.continuousLoad() {
+		status = lp.continuousLoadStatus()
+	} else {
+		start := time.Now()
+		var err error
+		status, err = lp.charger.Status()
+		metrics.Observe(lp.charger, start, err)
+
+		if err != nil {
+			return err
+		}
 	}
 
+	lp.chargerUpdated = lp.clock.Now()
 	lp.log.DEBUG.Printf("charger status: %s", status)
 
 	if prevStatus := lp.GetStatus(); status != prevStatus {
@@ -1099,14 +1393,9 @@ func (lp *LoadPoint) updateChargerStatus() error {
 		for _, ev := range statusEvents(prevStatus, status) {
 			lp.bus.Publish(ev)
 
-			// send connect/disconnect events except during startup
-			if prevStatus != api.StatusNone {
-				switch ev {
-				case evVehicleConnect:
-					lp.pushEvent(evVehicleConnect)
-				case evVehicleDisconnect:
-					lp.pushEvent(evVehicleDisconnect)
-				}
+			// send connect events except during startup, disconnect events are sent by the handler
+			if prevStatus != api.StatusNone && ev == evVehicleConnect {
+				lp.pushEvent(event.VehicleConnected{})
 			}
 		}
 
@@ -1192,9 +1481,9 @@ func (lp *LoadPoint) setConfiguredPhases(phases int) {
 
 	// publish 1p3p capability and phase configuration
 	if _, ok := lp.charger.(api.PhaseSwitcher); ok {
-		lp.publish(phasesConfigured, lp.ConfiguredPhases)
+		lp.publish(state.PhasesConfigured, lp.ConfiguredPhases)
 	} else {
-		lp.publish(phasesConfigured, nil)
+		lp.publish(state.PhasesConfigured, nil)
 	}
 }
 
@@ -1234,6 +1523,7 @@ func (lp *LoadPoint) scalePhases(phases int) error {
 
 		// update setting and reset timer
 		lp.setPhases(phases)
+		lp.phaseSwitched = lp.clock.Now()
 
 		// allow pv mode to re-enable charger right away
 		lp.elapsePVTimer()
@@ -1255,23 +1545,48 @@ func (lp *LoadPoint) pvScalePhases(availablePower, minCurrent, maxCurrent float6
 		lp.log.WARN.Printf("ignoring inconsistent phases: %dp < %dp observed active", phases, measuredPhases)
 	}
 
+	// keep charger disabled during charge pause
+	if lp.phaseSwitchPausing() {
+		return true
+	}
+
+	// minimum duration between switches
+	if dwell := lp.PhaseSwitch.Dwell; dwell > 0 && !lp.phaseSwitched.IsZero() && lp.clock.Since(lp.phaseSwitched) < dwell {
+		if !lp.phaseTimer.IsZero() {
+			lp.resetPhaseTimer()
+		}
+		return false
+	}
+
 	var waiting bool
 	activePhases := lp.activePhases()
+	ps := lp.PhaseSwitch
 
 	// scale down phases
-	if targetCurrent := powerToCurrent(availablePower, activePhases); targetCurrent < minCurrent && activePhases > 1 && lp.ConfiguredPhases < 3 {
-		lp.log.DEBUG.Printf("available power %.0fW < %.0fW min %dp threshold", availablePower, float64(activePhases)*Voltage*minCurrent, activePhases)
+	threshold1p := ps.Scale1p.Threshold
+	if threshold1p == 0 {
+		threshold1p = float64(activePhases) * Voltage * minCurrent
+	}
+	threshold1p -= ps.Hysteresis
+
+	if availablePower < threshold1p && activePhases > 1 && lp.ConfiguredPhases < 3 {
+		lp.log.DEBUG.Printf("available power %.0fW < %.0fW %dp threshold", availablePower, threshold1p, activePhases)
+
+		delay := ps.Scale1p.Delay
+		if delay == 0 {
+			delay = lp.Disable.Delay
+		}
 
 		if lp.phaseTimer.IsZero() {
 			lp.log.DEBUG.Printf("start phase %s timer", phaseScale1p)
 			lp.phaseTimer = lp.clock.Now()
 		}
 
-		lp.publishTimer(phaseTimer, lp.Disable.Delay, phaseScale1p)
+		lp.publishTimer(phaseTimer, delay, phaseScale1p)
 
-		if elapsed := lp.clock.Since(lp.phaseTimer); elapsed >= lp.Disable.Delay {
+		if elapsed := lp.clock.Since(lp.phaseTimer); elapsed >= delay {
 			lp.log.DEBUG.Printf("phase %s timer elapsed", phaseScale1p)
-			if err := lp.scalePhases(1); err == nil {
+			if err := lp.switchPhases(1); err == nil {
 				lp.log.DEBUG.Printf("switched phases: 1p @ %.0fW", availablePower)
 			} else {
 				lp.log.ERROR.Println(err)
@@ -1287,19 +1602,30 @@ func (lp *LoadPoint) pvScalePhases(availablePower, minCurrent, maxCurrent float6
 	scalable := maxPhases > 1 && phases < maxPhases && target1pCurrent > maxCurrent
 
 	// scale up phases
-	if targetCurrent := powerToCurrent(availablePower, maxPhases); targetCurrent >= minCurrent && scalable {
-		lp.log.DEBUG.Printf("available power %.0fW > %.0fW min %dp threshold", availablePower, 3*Voltage*minCurrent, maxPhases)
+	threshold3p := ps.Scale3p.Threshold
+	if threshold3p == 0 {
+		threshold3p = float64(maxPhases) * Voltage * minCurrent
+	}
+	threshold3p += ps.Hysteresis
+
+	if availablePower >= threshold3p && scalable {
+		lp.log.DEBUG.Printf("available power %.0fW >= %.0fW %dp threshold", availablePower, threshold3p, maxPhases)
+
+		delay := ps.Scale3p.Delay
+		if delay == 0 {
+			delay = lp.Enable.Delay
+		}
 
 		if lp.phaseTimer.IsZero() {
 			lp.log.DEBUG.Printf("start phase %s timer", phaseScale3p)
 			lp.phaseTimer = lp.clock.Now()
 		}
 
-		lp.publishTimer(phaseTimer, lp.Enable.Delay, phaseScale3p)
+		lp.publishTimer(phaseTimer, delay, phaseScale3p)
 
-		if elapsed := lp.clock.Since(lp.phaseTimer); elapsed >= lp.Enable.Delay {
+		if elapsed := lp.clock.Since(lp.phaseTimer); elapsed >= delay {
 			lp.log.DEBUG.Printf("phase %s timer elapsed", phaseScale3p)
-			if err := lp.scalePhases(3); err == nil {
+			if err := lp.switchPhases(3); err == nil {
 				lp.log.DEBUG.Printf("switched phases: 3p @ %.0fW", availablePower)
 			} else {
 				lp.log.ERROR.Println(err)
@@ -1318,14 +1644,19 @@ func (lp *LoadPoint) pvScalePhases(availablePower, minCurrent, maxCurrent float6
 	return false
 }
 
-// coordinatedVehicles is the slice of vehicles from the coordinator
+// coordinatedVehicles is the slice of vehicles from the coordinator, continuous loads are never identified
 func (lp *LoadPoint) coordinatedVehicles() []api.Vehicle {
-	if lp.coordinator == nil {
+	if lp.coordinator == nil || lp.continuousLoad() {
 		return nil
 	}
 	return lp.coordinator.GetVehicles()
 }
 
+// vehicleAtSite returns false if the vehicle reports a position outside the site geofence
+func (lp *LoadPoint) vehicleAtSite(v api.Vehicle) bool {
+	return lp.coordinator == nil || lp.coordinator.AtSite(v)
+}
+
 // TODO move up to timer functions
 func (lp *LoadPoint) publishTimer(name string, delay time.Duration, action string) {
 	timer := lp.pvTimer
@@ -1452,17 +1783,21 @@ func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 // UpdateChargePower updates charge meter power
 func (lp *LoadPoint) UpdateChargePower() {
 	err := retry.Do(func() error {
+		start := time.Now()
 		value, err := lp.chargeMeter.CurrentPower()
+		metrics.Observe(lp.chargeMeter, start, err)
+
 		if err != nil {
 			return err
 		}
 
 		lp.Lock()
 		lp.chargePower = value // update value if no error
+		lp.meterUpdated = lp.clock.Now()
 		lp.Unlock()
 
 		lp.log.DEBUG.Printf("charge power: %.0fW", value)
-		lp.publish("chargePower", value)
+		lp.publish(state.ChargePower, value)
 
 		// use -1 for https://github.com/evcc-io/evcc/issues/2153
 		if lp.chargePower < -1 {
@@ -1493,7 +1828,7 @@ func (lp *LoadPoint) updateChargeCurrents() {
 
 	lp.chargeCurrents = []float64{i1, i2, i3}
 	lp.log.DEBUG.Printf("charge currents: %.3gA", lp.chargeCurrents)
-	lp.publish("chargeCurrents", lp.chargeCurrents)
+	lp.publish(state.ChargeCurrents, lp.chargeCurrents)
 
 	if lp.charging() {
 		// Quine-McCluskey for (¬L1∧L2∧¬L3) ∨ (¬L1∧¬L2∧L3) ∨ (L1∧¬L2∧L3) ∨ (¬L1∧L2∧L3) -> ¬L1 ∧ L2 ∨ ¬L2 ∧ L3
@@ -1514,7 +1849,10 @@ func (lp *LoadPoint) updateChargeCurrents() {
 			lp.Unlock()
 
 			lp.log.DEBUG.Printf("detected phases: %dp", phases)
-			lp.publish(phasesActive, phases)
+			lp.publish(state.PhasesActive, phases)
+
+			lp.learnVehiclePhases(phases)
+			lp.validatePhases(phases)
 		}
 	}
 }
@@ -1537,20 +1875,22 @@ func (lp *LoadPoint) publishChargeProgress() {
 		lp.log.ERROR.Printf("charge timer: %v", err)
 	}
 
-	lp.publish("chargedEnergy", lp.getChargedEnergy())
-	lp.publish("chargeDuration", lp.chargeDuration)
+	lp.publish(state.ChargedEnergy, lp.getChargedEnergy())
+	lp.publish(state.ChargeDuration, lp.chargeDuration)
 	if _, ok := lp.chargeMeter.(api.MeterEnergy); ok {
-		lp.publish("chargeTotalImport", lp.chargeMeterTotal())
+		lp.publish(state.ChargeTotalImport, lp.chargeMeterTotal())
 	}
 }
 
 // socPollAllowed validates charging state against polling mode
 func (lp *LoadPoint) socPollAllowed() bool {
-	remaining := lp.SoC.Poll.Interval - lp.clock.Since(lp.socUpdated)
+	poll := lp.pollConfig()
+	remaining := poll.Interval - lp.clock.Since(lp.socUpdated)
 
-	honourUpdateInterval := lp.SoC.Poll.Mode == pollAlways ||
-		lp.SoC.Poll.Mode == pollConnected && lp.connected() ||
-		lp.SoC.Poll.Mode == pollCharging && lp.connected() && (lp.vehicleSoc < float64(lp.SoC.target))
+	honourUpdateInterval := (poll.Mode == pollAlways ||
+		poll.Mode == pollConnected && lp.connected() ||
+		poll.Mode == pollCharging && lp.connected() && (lp.vehicleSoc < float64(lp.SoC.target))) &&
+		pollScheduled(poll.Schedule, lp.clock.Now())
 
 	if honourUpdateInterval && remaining > 0 {
 		lp.log.DEBUG.Printf("next soc poll remaining time: %v", remaining.Truncate(time.Second))
@@ -1561,6 +1901,9 @@ func (lp *LoadPoint) socPollAllowed() bool {
 
 // checks if the connected charger can provide SoC to the connected vehicle
 func (lp *LoadPoint) socProvidedByCharger() bool {
+	if lp.SoC.Source == soc.SourceVehicle {
+		return false
+	}
 	if charger, ok := lp.charger.(api.Battery); ok {
 		if _, err := charger.SoC(); err == nil {
 			return true
@@ -1569,7 +1912,7 @@ func (lp *LoadPoint) socProvidedByCharger() bool {
 	return false
 }
 
-// publish state of charge, remaining charge duration and range
+// publish state of charge and range
 func (lp *LoadPoint) publishSoCAndRange() {
 	if lp.socEstimator == nil {
 		return
@@ -1582,7 +1925,12 @@ func (lp *LoadPoint) publishSoCAndRange() {
 		// guard for socEstimator removed by api
 		if se := lp.socEstimator; se != nil {
 			lp.socUpdated = lp.clock.Now()
+
+			start := time.Now()
 			f, err = se.SoC(lp.getChargedEnergy())
+			if !errors.Is(err, api.ErrMustRetry) {
+				metrics.Observe(lp.vehicle, start, err)
+			}
 		} else {
 			return
 		}
@@ -1599,33 +1947,27 @@ func (lp *LoadPoint) publishSoCAndRange() {
 
 		lp.vehicleSoc = math.Trunc(f)
 		lp.log.DEBUG.Printf("vehicle soc: %.0f%%", lp.vehicleSoc)
-		lp.publish("vehicleSoC", lp.vehicleSoc)
-
-		if se := lp.socEstimator; se != nil {
-			if lp.charging() {
-				lp.setRemainingDuration(se.RemainingChargeDuration(lp.chargePower, lp.SoC.target))
-			} else {
-				lp.setRemainingDuration(-1)
-			}
-		}
+		lp.publish(state.VehicleSoC, lp.vehicleSoc)
 
-		if se := lp.socEstimator; se != nil {
-			lp.setRemainingEnergy(1e3 * se.RemainingChargeEnergy(lp.SoC.target))
-		}
+		lp.learnArrival()
 
 		// range
 		if vs, ok := lp.vehicle.(api.VehicleRange); ok {
 			if rng, err := vs.Range(); err == nil {
 				lp.log.DEBUG.Printf("vehicle range: %dkm", rng)
-				lp.publish(vehicleRange, rng)
+				lp.publish(state.VehicleRange, rng)
 			}
+		} else if rng, ok := lp.estimatedRange(); ok {
+			lp.log.DEBUG.Printf("vehicle range (estimated): %dkm", rng)
+			lp.publish(state.VehicleRange, rng)
 		}
 
 		// vehicle target soc
 		if vs, ok := lp.vehicle.(api.SocLimiter); ok {
 			if targetSoC, err := vs.TargetSoC(); err == nil {
 				lp.log.DEBUG.Printf("vehicle target soc: %.0f%%", targetSoC)
-				lp.publish(vehicleTargetSoC, targetSoC)
+				lp.publish(state.VehicleTargetSoC, targetSoC)
+				lp.syncVehicleSocLimit(int(targetSoC))
 			}
 		}
 
@@ -1634,6 +1976,44 @@ func (lp *LoadPoint) publishSoCAndRange() {
 	}
 }
 
+// publishSoCAge publishes the age of the last known vehicle soc
+func (lp *LoadPoint) publishSoCAge() {
+	if se := lp.socEstimator; se != nil {
+		lp.publish(state.VehicleSoCAge, se.Age().Truncate(time.Second))
+	}
+}
+
+// publishRemaining publishes the remaining charge duration and energy estimates.
+// Estimates are updated every cycle using the current charge power, not only when the vehicle is polled.
+func (lp *LoadPoint) publishRemaining() {
+	// energy target for vehicles without soc
+	if lp.targetEnergy > 0 && (lp.vehicle == nil || lp.vehicleHasFeature(api.Offline)) {
+		remaining := math.Max(1e3*lp.targetEnergy-lp.getChargedEnergy(), 0)
+		lp.setRemainingEnergy(remaining)
+
+		if lp.charging() && lp.chargePower > 0 {
+			lp.setRemainingDuration(time.Duration(float64(time.Hour) * remaining / lp.chargePower).Round(time.Second))
+		} else {
+			lp.setRemainingDuration(-1)
+		}
+
+		return
+	}
+
+	se := lp.socEstimator
+	if se == nil {
+		return
+	}
+
+	if lp.charging() {
+		lp.setRemainingDuration(se.RemainingChargeDuration(lp.chargePower, lp.SoC.target))
+	} else {
+		lp.setRemainingDuration(-1)
+	}
+
+	lp.setRemainingEnergy(1e3 * se.RemainingChargeEnergy(lp.SoC.target))
+}
+
 // addTask adds a single task to the queue
 func (lp *LoadPoint) addTask(task func()) {
 	// test guard
@@ -1659,14 +2039,25 @@ func (lp *LoadPoint) processTasks() {
 
 // Update is the main control function. It reevaluates meters and charger state
 func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
+	// charger is under test
+	if lp.selfTestActive() {
+		return
+	}
+
 	lp.processTasks()
 
+	// revert to local control if external controller is gone
+	lp.remoteWatchdog()
+
 	mode := lp.GetMode()
-	lp.publish("mode", mode)
+	lp.publish(state.Mode, mode)
 
 	// read and publish meters first- charge power has already been updated by the site
 	lp.updateChargeCurrents()
 
+	// check charger faults and supply voltage
+	lp.updateMonitor()
+
 	// update ChargeRater here to make sure initial meter update is caught
 	lp.bus.Publish(evChargeCurrent, lp.chargeCurrent)
 	lp.bus.Publish(evChargePower, lp.chargePower)
@@ -1674,18 +2065,44 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	// update progress and soc before status is updated
 	lp.publishChargeProgress()
 
+	// update guest session energy and cost, mode reverts when the budget is exhausted
+	if lp.syncGuestSession() {
+		mode = lp.GetMode()
+	}
+
+	// apply the price limit to the effective price
+	cheap = lp.updateEffectivePrice(mode, cheap)
+
+	// fixed cheap windows apply to min+pv mode only
+	if mode == api.ModeMinPV && inTimeWindows(lp.Tariff.Windows, lp.clock.Now()) {
+		cheap = true
+	}
+
+	// update session cost
+	lp.updateSessionCost()
+
 	// read and publish status
 	if err := lp.updateChargerStatus(); err != nil {
 		lp.log.ERROR.Printf("charger: %v", err)
+		lp.deviceWatchdog()
+		return
+	}
+
+	// persisted session is only continued if the vehicle is connected at startup
+	lp.restored = nil
+
+	// apply failsafe current while charger or meter communication is lost
+	if lp.deviceWatchdog() {
 		return
 	}
 
-	lp.publish("connected", lp.connected())
-	lp.publish("charging", lp.charging())
-	lp.publish("enabled", lp.enabled)
+	lp.publish(state.Connected, lp.connected())
+	lp.publish(state.Charging, lp.charging())
+	lp.publish(state.Enabled, lp.enabled)
 
-	// identify connected vehicle
-	if lp.connected() {
+	// identify connected vehicle unless charging a guest
+	guest := lp.guestSessionActive()
+	if lp.connected() && !guest && !lp.continuousLoad() {
 		// read identity and run associated action
 		lp.identifyVehicle()
 
@@ -1695,22 +2112,41 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		}
 	}
 
+	// guest sessions are authorized by the operator
+	authorized := guest || lp.authorize()
+
 	// publish soc after updating charger status to make sure
 	// initial update of connected state matches charger status
 	lp.publishSoCAndRange()
+	lp.publishSoCAge()
+	lp.publishRemaining()
+	lp.updateClimate(sitePower)
 
 	// sync settings with charger
 	lp.syncCharger()
+	lp.verifyCurrent()
 
 	// check if car connected and ready for charging
-	var err error
+	var (
+		err    error
+		reason string // decision reason
+	)
 
 	// track if remote disabled is actually active
 	remoteDisabled := loadpoint.RemoteEnable
 
+	// set target charge from repeating plans
+	lp.applyPlans()
+
 	// reset detection if soc timer needs be deactivated after evaluating the loading strategy
 	lp.socTimer.MustValidateDemand()
 
+	// restrict to pv while a monthly budget is exceeded
+	budgetExceeded := lp.budgetExceeded()
+	mode = budgetMode(mode, budgetExceeded)
+
+	lp.pushTargetReached(lp.connected() && (lp.targetEnergyReached() || lp.targetSocReached()))
+
 	// execute loading strategy
 	switch {
 	case !lp.connected():
@@ -1718,28 +2154,43 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		// https://github.com/evcc-io/evcc/issues/105
 		err = lp.setLimit(0, false)
 
+	case !authorized:
+		reason = "not authorized"
+		err = lp.setLimit(0, true)
+
+	case lp.idleTimeout():
+		reason = "vehicle idle"
+		err = lp.freeCharger()
+
 	case lp.scalePhasesRequired():
 		if err = lp.scalePhases(lp.ConfiguredPhases); err == nil {
-			lp.log.DEBUG.Printf("switched phases: %dp", lp.ConfiguredPhases)
+			lp.tracef("switched phases: %dp", lp.ConfiguredPhases)
 		}
 
 	case lp.targetEnergyReached():
-		lp.log.DEBUG.Printf("targetEnergy reached: %.0fkWh > %0.1fkWh", lp.getChargedEnergy()/1e3, lp.targetEnergy)
+		reason = fmt.Sprintf("target energy reached: %.1fkWh", lp.targetEnergy)
+		lp.tracef("targetEnergy reached: %.0fkWh > %0.1fkWh", lp.getChargedEnergy()/1e3, lp.targetEnergy)
 		err = lp.disableUnlessClimater()
 
 	case lp.targetSocReached():
-		lp.log.DEBUG.Printf("targetSoC reached: %.1f%% > %d%%", lp.vehicleSoc, lp.SoC.target)
+		reason = fmt.Sprintf("target soc reached: %d%%", lp.SoC.target)
+		lp.tracef("targetSoC reached: %.1f%% > %d%%", lp.vehicleSoc, lp.SoC.target)
 		err = lp.disableUnlessClimater()
 
 	// OCPP has priority over target charging
 	case lp.remoteControlled(loadpoint.RemoteHardDisable):
 		remoteDisabled = loadpoint.RemoteHardDisable
+		reason = "remote disabled"
 		fallthrough
 
 	case mode == api.ModeOff:
+		if reason == "" {
+			reason = "mode off"
+		}
 		err = lp.setLimit(0, true)
 
-	case lp.minSocNotReached():
+	case !budgetExceeded && lp.minSocNotReached():
+		reason = fmt.Sprintf("min soc not reached: %d%%", lp.SoC.min)
 		// 3p if available
 		if err = lp.scalePhasesIfAvailable(3); err == nil {
 			err = lp.setLimit(lp.GetMaxCurrent(), true)
@@ -1747,13 +2198,15 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		lp.elapsePVTimer() // let PV mode disable immediately afterwards
 
 	case mode == api.ModeNow:
+		reason = "mode now"
 		// 3p if available
 		if err = lp.scalePhasesIfAvailable(3); err == nil {
 			err = lp.setLimit(lp.GetMaxCurrent(), true)
 		}
 
 	// target charging
-	case lp.socTimer.DemandActive():
+	case !budgetExceeded && lp.socTimer.DemandActive():
+		reason = "target charging"
 		// 3p if available
 		if err = lp.scalePhasesIfAvailable(3); err == nil {
 			targetCurrent := lp.socTimer.Handle()
@@ -1761,25 +2214,33 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		}
 
 	case mode == api.ModeMinPV || mode == api.ModePV:
+		reason = "mode " + string(mode)
+		if budgetExceeded {
+			reason = "budget exceeded"
+		}
+
 		targetCurrent := lp.pvMaxCurrent(mode, sitePower, batteryBuffered)
 
 		var required bool // false
 		if targetCurrent == 0 && lp.climateActive() {
-			lp.log.DEBUG.Println("climater active")
+			lp.tracef("climater active")
+			reason = "climater active"
 			targetCurrent = lp.GetMinCurrent()
 			required = true
 		}
 
 		// tariff
-		if cheap {
+		if cheap && !budgetExceeded {
 			targetCurrent = lp.GetMaxCurrent()
-			lp.log.DEBUG.Printf("cheap tariff: %.3gA", targetCurrent)
+			lp.tracef("cheap tariff: %.3gA", targetCurrent)
+			reason = "cheap tariff"
 			required = true
 		}
 
 		// Sunny Home Manager
 		if lp.remoteControlled(loadpoint.RemoteSoftDisable) {
 			remoteDisabled = loadpoint.RemoteSoftDisable
+			reason = "remote soft disabled"
 			targetCurrent = 0
 			required = true
 		}
@@ -1787,12 +2248,24 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		err = lp.setLimit(targetCurrent, required)
 	}
 
+	// record why charging is enabled or disabled
+	if reason != "" {
+		status := "disabled"
+		if lp.enabled {
+			status = "enabled"
+		}
+		lp.decide("%s: charger %s", reason, status)
+	}
+
 	// Wake-up checks
 	if lp.enabled && lp.status == api.StatusB &&
 		int(lp.vehicleSoc) < lp.SoC.target && lp.wakeUpTimer.Expired() {
 		lp.wakeUpVehicle()
 	}
 
+	// refresh soc before planned charging
+	lp.planWakeUpVehicle()
+
 	// stop an active target charging session if not currently evaluated
 	if !lp.socTimer.DemandValidated() {
 		lp.socTimer.Stop()
@@ -1800,9 +2273,18 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 
 	// effective disabled status
 	if remoteDisabled != loadpoint.RemoteEnable {
-		lp.publish("remoteDisabled", remoteDisabled)
+		lp.publish(state.RemoteDisabled, remoteDisabled)
 	}
 
+	// escalate repeated charger write failures
+	lp.writeWatchdog(err)
+
+	// reflect state on charger leds or displays
+	lp.updateIndicator(mode, err)
+
+	// keep transient state for restoration after crash or restart
+	lp.persistState()
+
 	// log any error
 	if err != nil {
 		lp.log.ERROR.Println(err)
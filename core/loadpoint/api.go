@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/selftest"
 )
 
 // Controller gives access to loadpoint
@@ -22,6 +24,10 @@ type API interface {
 
 	// GetStatus returns the charging status
 	GetStatus() api.ChargeStatus
+	// GetVehicleSoC returns the vehicle soc in %
+	GetVehicleSoC() float64
+	// SetVehicleSoC sets the soc of offline vehicles in %
+	SetVehicleSoC(float64) error
 
 	//
 	// settings
@@ -50,8 +56,22 @@ type API interface {
 
 	// SetTargetCharge sets the charge targetSoC
 	SetTargetCharge(time.Time, int)
+	// GetTargetCharge returns the target charge time and soc, zero time if not set
+	GetTargetCharge() (time.Time, int)
+	// GetPlan returns the target charging plan
+	GetPlan() planner.Plan
+	// GetSchedules returns the recurring weekly target charges
+	GetSchedules() []Schedule
+	// SetSchedules replaces and persists the recurring weekly target charges
+	SetSchedules([]Schedule) error
 	// RemoteControl sets remote status demand
 	RemoteControl(string, RemoteDemand)
+	// SetRemoteBudget sets the external power budget of the source in W, negative values remove the source's budget
+	SetRemoteBudget(source string, power float64)
+	// GetRemoteBudget returns the lowest external power budget in W or -1 if not set
+	GetRemoteBudget() float64
+	// RemoteHeartbeat signals that the external controller is alive
+	RemoteHeartbeat(source string)
 
 	//
 	// power and energy
@@ -83,6 +103,21 @@ type API interface {
 	// GetRemainingEnergy is the remaining charge energy in Wh
 	GetRemainingEnergy() float64
 
+	//
+	// charger maintenance
+	//
+
+	// GetChargerCommands returns the maintenance commands supported by the charger
+	GetChargerCommands() []api.Command
+	// ChargerCommand executes a charger maintenance command
+	ChargerCommand(api.Command) error
+	// StartChargerSelfTest runs the charger interoperability self-test in the background
+	StartChargerSelfTest() error
+	// GetChargerSelfTest returns the last self-test report and if the self-test is running
+	GetChargerSelfTest() (*selftest.Report, bool)
+	// GetChargerDiagnostics returns the charger's diagnostic data
+	GetChargerDiagnostics() (api.Diagnostics, error)
+
 	//
 	// vehicles
 	//
@@ -91,4 +126,15 @@ type API interface {
 	SetVehicle(vehicle api.Vehicle)
 	// StartVehicleDetection allows triggering vehicle detection for debugging purposes
 	StartVehicleDetection()
+
+	//
+	// guest sessions
+	//
+
+	// StartGuestSession starts an ad-hoc guest charging session with energy and cost limits
+	StartGuestSession(energy, price, maxCost float64) error
+	// GetGuestSession returns the current or last guest session
+	GetGuestSession() *GuestSession
+	// StopGuestSession stops the guest session and returns its summary
+	StopGuestSession() (*GuestSession, error)
 }
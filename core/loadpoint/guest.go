@@ -0,0 +1,46 @@
+package loadpoint
+
+import (
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// GuestSession is an ad-hoc charging session independent of configured vehicles
+type GuestSession struct {
+	Created       time.Time      `json:"created"`
+	Finished      time.Time      `json:"finished"`
+	Energy        float64        `json:"energy"`        // energy limit in kWh, 0 for unlimited
+	MaxCost       float64        `json:"maxCost"`       // cost limit, 0 for unlimited
+	Price         float64        `json:"price"`         // price per kWh
+	ChargedEnergy float64        `json:"chargedEnergy"` // charged energy in kWh
+	Cost          float64        `json:"cost"`          // total cost
+	Mode          api.ChargeMode `json:"mode"`          // charge mode restored after the session
+}
+
+// Active returns true if the session has not finished yet
+func (s GuestSession) Active() bool {
+	return s.Finished.IsZero()
+}
+
+// EnergyLimit returns the effective energy limit in kWh taking the cost limit into account, 0 for unlimited
+func (s GuestSession) EnergyLimit() float64 {
+	limit := s.Energy
+
+	if s.MaxCost > 0 && s.Price > 0 {
+		if costLimit := s.MaxCost / s.Price; limit == 0 {
+			limit = costLimit
+		} else {
+			limit = math.Min(limit, costLimit)
+		}
+	}
+
+	return limit
+}
+
+// Exhausted returns true if the session has used its energy or cost limit
+func (s GuestSession) Exhausted() bool {
+	limit := s.EnergyLimit()
+	return limit > 0 && s.ChargedEnergy >= limit
+}
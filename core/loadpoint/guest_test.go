@@ -0,0 +1,41 @@
+package loadpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuestSessionEnergyLimit(t *testing.T) {
+	tc := []struct {
+		energy, price, maxCost, limit float64
+	}{
+		{0, 0, 0, 0},
+		{10, 0, 0, 10},
+		{0, 0.5, 0, 0},
+		{0, 0.5, 2, 4},
+		{10, 0.5, 2, 4},
+		{2, 0.5, 2, 2},
+	}
+
+	for _, tc := range tc {
+		s := GuestSession{Energy: tc.energy, Price: tc.price, MaxCost: tc.maxCost}
+		assert.Equal(t, tc.limit, s.EnergyLimit(), "%+v", tc)
+	}
+}
+
+func TestGuestSessionExhausted(t *testing.T) {
+	s := GuestSession{Energy: 10, ChargedEnergy: 9.9}
+	assert.False(t, s.Exhausted())
+
+	s.ChargedEnergy = 10
+	assert.True(t, s.Exhausted())
+
+	// cost limit
+	s = GuestSession{Price: 0.5, MaxCost: 2, ChargedEnergy: 4}
+	assert.True(t, s.Exhausted())
+
+	// unlimited
+	s = GuestSession{ChargedEnergy: 100}
+	assert.False(t, s.Exhausted())
+}
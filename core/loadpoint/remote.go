@@ -1,6 +1,12 @@
 package loadpoint
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
+
+// RemoteTimeout is the default duration after which external control reverts to local control without heartbeat
+const RemoteTimeout = 5 * time.Minute
 
 // RemoteDemand defines external status demand
 type RemoteDemand string
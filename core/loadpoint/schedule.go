@@ -0,0 +1,10 @@
+package loadpoint
+
+// Schedule is a recurring weekly target charge, e.g. weekdays to 80% by 07:00.
+// Target charging uses the planner, i.e. grid charging is placed into off-peak slots if a dynamic tariff is configured.
+type Schedule struct {
+	Days         []string `mapstructure:"days" json:"days"`                 // weekdays like mon, tue; empty for all days
+	Time         string   `mapstructure:"time" json:"time"`                 // target time of day like 07:00
+	SoC          int      `mapstructure:"soc" json:"soc"`                   // target soc
+	SkipHolidays bool     `mapstructure:"skipHolidays" json:"skipHolidays"` // skip public holidays
+}
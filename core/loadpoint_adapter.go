@@ -1,6 +1,7 @@
 package core
 
 import (
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/core/soc"
 )
 
@@ -14,6 +15,10 @@ func (a *adapter) Publish(key string, val interface{}) {
 	a.LoadPoint.publish(key, val)
 }
 
+func (a *adapter) PublishEvent(ev event.Event) {
+	a.LoadPoint.pushEvent(ev)
+}
+
 func (a *adapter) SocEstimator() *soc.Estimator {
 	return a.LoadPoint.socEstimator
 }
@@ -1,11 +1,14 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/core/wrapper"
 )
 
@@ -18,6 +21,40 @@ func (lp *LoadPoint) GetStatus() api.ChargeStatus {
 	return lp.status
 }
 
+// GetVehicleSoC returns the vehicle soc
+func (lp *LoadPoint) GetVehicleSoC() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.vehicleSoc
+}
+
+// SetVehicleSoC sets the soc of offline vehicles, it is updated from the charged energy afterwards
+func (lp *LoadPoint) SetVehicleSoC(soc float64) error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.vehicle == nil || lp.socEstimator == nil {
+		return errors.New("no vehicle")
+	}
+
+	if !lp.vehicleHasFeature(api.Offline) {
+		return errors.New("vehicle provides soc")
+	}
+
+	if soc < 0 || soc > 100 {
+		return fmt.Errorf("invalid soc: %.0f", soc)
+	}
+
+	lp.log.DEBUG.Printf("set vehicle soc: %.0f%%", soc)
+
+	lp.socEstimator.SetSoC(soc, lp.chargedEnergy)
+	lp.vehicleSoc = soc
+	lp.publish(state.VehicleSoC, soc)
+	lp.requestUpdate()
+
+	return nil
+}
+
 // GetMode returns loadpoint charge mode
 func (lp *LoadPoint) GetMode() api.ChargeMode {
 	lp.Lock()
@@ -40,7 +77,7 @@ func (lp *LoadPoint) SetMode(mode api.ChargeMode) {
 	// apply immediately
 	if lp.Mode != mode {
 		lp.Mode = mode
-		lp.publish("mode", mode)
+		lp.publish(state.Mode, mode)
 
 		// immediately allow pv mode activity
 		lp.elapsePVTimer()
@@ -77,7 +114,7 @@ func (lp *LoadPoint) setTargetEnergy(energy float64) {
 	// if lp.socTimer != nil {
 	// lp.socTimer.Energy = energy
 	// }
-	lp.publish("targetEnergy", energy)
+	lp.publish(state.TargetEnergy, energy)
 }
 
 // SetTargetEnergy sets loadpoint charge target energy
@@ -108,7 +145,7 @@ func (lp *LoadPoint) setTargetSoC(soc int) {
 	if lp.socTimer != nil {
 		lp.socTimer.SoC = soc
 	}
-	lp.publish("targetSoC", soc)
+	lp.publish(state.TargetSoC, soc)
 }
 
 // SetTargetSoC sets loadpoint charge target soc
@@ -135,7 +172,7 @@ func (lp *LoadPoint) GetMinSoC() int {
 // setMinSoC sets loadpoint charge min soc (no mutex)
 func (lp *LoadPoint) setMinSoC(soc int) {
 	lp.SoC.min = soc
-	lp.publish("minSoC", soc)
+	lp.publish(state.MinSoC, soc)
 }
 
 // SetMinSoC sets loadpoint charge minimum soc
@@ -203,6 +240,18 @@ func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) {
 	}
 }
 
+// GetTargetCharge returns the target charge time and soc, zero time if not set
+func (lp *LoadPoint) GetTargetCharge() (time.Time, int) {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.socTimer.Time, lp.SoC.target
+}
+
+// GetPlan returns the target charging plan
+func (lp *LoadPoint) GetPlan() planner.Plan {
+	return lp.socTimer.Plan()
+}
+
 // RemoteControl sets remote status demand
 func (lp *LoadPoint) RemoteControl(source string, demand loadpoint.RemoteDemand) {
 	lp.Lock()
@@ -214,8 +263,8 @@ func (lp *LoadPoint) RemoteControl(source string, demand loadpoint.RemoteDemand)
 	if lp.remoteDemand != demand {
 		lp.remoteDemand = demand
 
-		lp.publish("remoteDisabled", demand)
-		lp.publish("remoteDisabledSource", source)
+		lp.publish(state.RemoteDisabled, demand)
+		lp.publish(state.RemoteDisabledSource, source)
 
 		lp.requestUpdate()
 	}
@@ -250,7 +299,7 @@ func (lp *LoadPoint) SetMinCurrent(current float64) {
 
 	if current != lp.MinCurrent {
 		lp.MinCurrent = current
-		lp.publish("minCurrent", lp.MinCurrent)
+		lp.publish(state.MinCurrent, lp.MinCurrent)
 	}
 }
 
@@ -270,7 +319,7 @@ func (lp *LoadPoint) SetMaxCurrent(current float64) {
 
 	if current != lp.MaxCurrent {
 		lp.MaxCurrent = current
-		lp.publish("maxCurrent", lp.MaxCurrent)
+		lp.publish(state.MaxCurrent, lp.MaxCurrent)
 	}
 }
 
@@ -288,7 +337,7 @@ func (lp *LoadPoint) GetMaxPower() float64 {
 func (lp *LoadPoint) setRemainingDuration(chargeRemainingDuration time.Duration) {
 	if lp.chargeRemainingDuration != chargeRemainingDuration {
 		lp.chargeRemainingDuration = chargeRemainingDuration
-		lp.publish("chargeRemainingDuration", chargeRemainingDuration)
+		lp.publish(state.ChargeRemainingDuration, chargeRemainingDuration)
 	}
 }
 
@@ -306,7 +355,7 @@ func (lp *LoadPoint) setRemainingEnergy(chargeRemainingEnergy float64) {
 
 	if lp.chargeRemainingEnergy != chargeRemainingEnergy {
 		lp.chargeRemainingEnergy = chargeRemainingEnergy
-		lp.publish("chargeRemainingEnergy", chargeRemainingEnergy)
+		lp.publish(state.ChargeRemainingEnergy, chargeRemainingEnergy)
 	}
 }
 
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// AuthorizationConfig allows charging for an identifier reported by the charger
+type AuthorizationConfig struct {
+	ID      string `mapstructure:"id"`      // RFID tag, MAC or EVCCID
+	Vehicle string `mapstructure:"vehicle"` // vehicle reference, optional
+	User    string `mapstructure:"user"`    // user name recorded with the session, optional
+}
+
+// authorization is a resolved allowlist entry
+type authorization struct {
+	id, user string
+	vehicle  api.Vehicle
+}
+
+// configureAuthorizations resolves the allowlist's vehicle references
+func (lp *LoadPoint) configureAuthorizations(cp configProvider) error {
+	if len(lp.Authorization) == 0 {
+		return nil
+	}
+
+	if _, ok := lp.charger.(api.Identifier); !ok {
+		return errors.New("charger does not support identification")
+	}
+
+	for _, ac := range lp.Authorization {
+		if ac.ID == "" {
+			return errors.New("missing id")
+		}
+
+		a := authorization{id: ac.ID, user: ac.User}
+
+		if ac.Vehicle != "" {
+			var err error
+			if a.vehicle, err = cp.Vehicle(ac.Vehicle); err != nil {
+				return err
+			}
+		}
+
+		lp.authorizations = append(lp.authorizations, a)
+	}
+
+	return nil
+}
+
+// authorize matches the charger identifier against the allowlist and returns true if charging is allowed.
+// Without allowlist all identifiers are authorized.
+func (lp *LoadPoint) authorize() bool {
+	if len(lp.authorizations) == 0 {
+		return true
+	}
+
+	id := lp.vehicleIdentifier
+
+	if lp.authorized != nil && strings.EqualFold(lp.authorized.id, id) {
+		return true
+	}
+
+	lp.setAuthorized(nil)
+
+	// waiting for identification
+	if id == "" {
+		return false
+	}
+
+	for i, a := range lp.authorizations {
+		if !strings.EqualFold(a.id, id) {
+			continue
+		}
+
+		lp.log.INFO.Printf("authorized id: %s", id)
+		lp.setAuthorized(&lp.authorizations[i])
+
+		if a.vehicle != nil {
+			lp.stopVehicleDetection()
+			lp.setActiveVehicle(a.vehicle)
+		}
+
+		return true
+	}
+
+	// notify once per identifier
+	if lp.unauthorizedID != id {
+		lp.unauthorizedID = id
+		lp.log.WARN.Printf("unauthorized id: %s, charging disabled", id)
+		lp.pushEvent(event.VehicleUnauthorized{ID: id})
+	}
+
+	return false
+}
+
+// setAuthorized updates and publishes the authorization
+func (lp *LoadPoint) setAuthorized(a *authorization) {
+	if lp.authorized == a {
+		return
+	}
+
+	lp.authorized = a
+
+	var user string
+	if a != nil {
+		user = a.user
+	}
+
+	lp.publish(state.Authorized, a != nil)
+	lp.publish(state.AuthorizedUser, user)
+}
+
+// resetAuthorization revokes the authorization when the vehicle disconnects
+func (lp *LoadPoint) resetAuthorization() {
+	lp.unauthorizedID = ""
+	lp.setAuthorized(nil)
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	pushChan := make(chan push.Event, 10)
+
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Title().Return("target").AnyTimes()
+	vehicle.EXPECT().Capacity().AnyTimes()
+	vehicle.EXPECT().Phases().AnyTimes()
+	vehicle.EXPECT().OnIdentified().AnyTimes()
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.events = pushPublisher(lp, pushChan)
+
+	// no allowlist
+	assert.True(t, lp.authorize())
+
+	lp.authorizations = []authorization{
+		{id: "1234", user: "jane", vehicle: vehicle},
+		{id: "abcd"},
+	}
+
+	// waiting for identification
+	assert.False(t, lp.authorize())
+
+	// unknown identifier notified once
+	lp.vehicleIdentifier = "ffff"
+	assert.False(t, lp.authorize())
+	assert.False(t, lp.authorize())
+	assert.Len(t, pushChan, 1)
+
+	// allowlisted identifier selects vehicle
+	lp.vehicleIdentifier = "ABCD"
+	assert.True(t, lp.authorize())
+	assert.Nil(t, lp.vehicle)
+
+	lp.vehicleIdentifier = "1234"
+	assert.True(t, lp.authorize())
+	assert.Equal(t, "jane", lp.authorized.user)
+	assert.Equal(t, vehicle, lp.vehicle)
+
+	// revoked on disconnect
+	lp.resetAuthorization()
+	lp.vehicleIdentifier = "ffff"
+	assert.False(t, lp.authorize())
+	assert.Nil(t, lp.authorized)
+	assert.Len(t, pushChan, 2)
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// BudgetConfig defines a monthly charging budget for the loadpoint or a single vehicle
+type BudgetConfig struct {
+	Vehicle string  `mapstructure:"vehicle"` // vehicle title, empty for all vehicles
+	Energy  float64 `mapstructure:"energy"`  // charged energy in kWh per month
+	Cost    float64 `mapstructure:"cost"`    // charging cost in loadpoint currency per month
+}
+
+// budgetUsage is the energy and cost accounted to a budget
+type budgetUsage struct {
+	energy, cost float64 // kWh, currency
+}
+
+// monthStart returns the beginning of the month of t
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// vehicleTitle returns the active vehicle's title
+func (lp *LoadPoint) vehicleTitle() string {
+	if lp.vehicle == nil {
+		return ""
+	}
+	return lp.vehicle.Title()
+}
+
+// applies returns true if the budget applies to the active vehicle
+func (b BudgetConfig) applies(vehicle string) bool {
+	return b.Vehicle == "" || b.Vehicle == vehicle
+}
+
+// exceeded returns true if the usage exceeds the budget
+func (b BudgetConfig) exceeded(u budgetUsage) bool {
+	return b.Energy > 0 && u.energy >= b.Energy || b.Cost > 0 && u.cost >= b.Cost
+}
+
+// refreshBudgets loads the current month's usage of completed sessions from the database
+func (lp *LoadPoint) refreshBudgets(month time.Time) {
+	lp.budgetMonth = month
+	lp.budgetSession = lp.session
+	lp.budgetUsage = make([]budgetUsage, len(lp.Budgets))
+
+	// test guard
+	if lp.db == nil {
+		return
+	}
+
+	var exclude uint
+	if lp.session != nil {
+		exclude = lp.session.ID
+	}
+
+	for i, b := range lp.Budgets {
+		energy, cost, err := lp.db.Usage(month, b.Vehicle, exclude)
+		if err != nil {
+			lp.log.ERROR.Printf("budget: %v", err)
+			continue
+		}
+
+		lp.budgetUsage[i] = budgetUsage{energy: energy, cost: cost}
+	}
+}
+
+// budgetExceeded returns true if any budget applicable to the active vehicle is exhausted
+func (lp *LoadPoint) budgetExceeded() bool {
+	if len(lp.Budgets) == 0 {
+		return false
+	}
+
+	// refresh on month change and when the session changes
+	if month := monthStart(lp.clock.Now()); !month.Equal(lp.budgetMonth) || lp.session != lp.budgetSession || lp.budgetUsage == nil {
+		lp.refreshBudgets(month)
+	}
+
+	vehicle := lp.vehicleTitle()
+
+	var exceeded bool
+	var energy, cost float64
+
+	for i, b := range lp.Budgets {
+		if !b.applies(vehicle) {
+			continue
+		}
+
+		// add current session
+		u := lp.budgetUsage[i]
+		u.energy += lp.getChargedEnergy() / 1e3
+		u.cost += lp.sessionCost
+
+		energy, cost = u.energy, u.cost
+		exceeded = exceeded || b.exceeded(u)
+	}
+
+	lp.publish(state.BudgetEnergy, energy)
+	lp.publish(state.BudgetCost, cost)
+
+	if exceeded != lp.budgetLimited {
+		if exceeded {
+			lp.log.WARN.Printf("budget exceeded: %.1fkWh, %.2f %s, restricting to pv", energy, cost, lp.currency)
+			lp.pushEvent(event.BudgetExceeded{})
+		} else {
+			lp.log.INFO.Println("budget available")
+		}
+
+		lp.budgetLimited = exceeded
+		lp.publish(state.BudgetExceeded, exceeded)
+	}
+
+	return exceeded
+}
+
+// budgetMode restricts the charge mode to pv while the budget is exceeded
+func budgetMode(mode api.ChargeMode, exceeded bool) api.ChargeMode {
+	if exceeded && (mode == api.ModeNow || mode == api.ModeMinPV) {
+		return api.ModePV
+	}
+	return mode
+}
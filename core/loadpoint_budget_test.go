@@ -0,0 +1,51 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	clck := clock.NewMock()
+	pushChan := make(chan push.Event, 10)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.events = pushPublisher(lp, pushChan)
+	lp.Budgets = []BudgetConfig{
+		{Energy: 100},
+		{Vehicle: "guest car", Cost: 10},
+	}
+
+	// loadpoint budget
+	lp.setChargedEnergy(50e3)
+	assert.False(t, lp.budgetExceeded())
+
+	lp.setChargedEnergy(100e3)
+	assert.True(t, lp.budgetExceeded())
+	assert.Len(t, pushChan, 1)
+
+	// vehicle budget does not apply to other vehicles
+	lp.setChargedEnergy(10e3)
+	lp.sessionCost = 20
+	assert.False(t, lp.budgetExceeded())
+
+	vehicle := mock.NewMockVehicle(gomock.NewController(t))
+	vehicle.EXPECT().Title().Return("guest car").AnyTimes()
+
+	lp.vehicle = vehicle
+	assert.True(t, lp.budgetExceeded())
+	assert.Len(t, pushChan, 2)
+
+	assert.Equal(t, api.ModePV, budgetMode(api.ModeNow, true))
+	assert.Equal(t, api.ModePV, budgetMode(api.ModeMinPV, true))
+	assert.Equal(t, api.ModeOff, budgetMode(api.ModeOff, true))
+	assert.Equal(t, api.ModeNow, budgetMode(api.ModeNow, false))
+}
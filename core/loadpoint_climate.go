@@ -0,0 +1,44 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// ClimateConfig defines the automatic vehicle preconditioning before departure
+type ClimateConfig struct {
+	Precondition time.Duration `mapstructure:"precondition"` // start climatisation this long before the target time
+	Solar        bool          `mapstructure:"solar"`        // only start while pv surplus is available
+}
+
+// updateClimate starts vehicle climatisation once per target time while connected
+func (lp *LoadPoint) updateClimate(sitePower float64) {
+	cc, ok := lp.vehicle.(api.VehicleClimateController)
+	if !ok || lp.Climate.Precondition <= 0 || !lp.connected() {
+		return
+	}
+
+	target := lp.socTimer.Time
+	if target.IsZero() || target.Equal(lp.climateTarget) {
+		return
+	}
+
+	if now := lp.clock.Now(); now.Before(target.Add(-lp.Climate.Precondition)) || !now.Before(target) {
+		return
+	}
+
+	if lp.Climate.Solar && sitePower >= 0 {
+		return
+	}
+
+	// single attempt per target time
+	lp.climateTarget = target
+
+	if err := cc.StartClimate(); err != nil {
+		lp.log.ERROR.Printf("climate: %v", err)
+		return
+	}
+
+	lp.log.INFO.Printf("climate: preconditioning for %v", target.Round(time.Minute))
+}
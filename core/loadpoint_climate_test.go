@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type climateVehicle struct {
+	api.Vehicle
+	started int
+}
+
+func (v *climateVehicle) StartClimate() error {
+	v.started++
+	return nil
+}
+
+func (v *climateVehicle) StopClimate() error {
+	return nil
+}
+
+func TestUpdateClimate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := &climateVehicle{Vehicle: mock.NewMockVehicle(ctrl)}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.vehicle = vehicle
+	lp.status = api.StatusB
+	lp.Climate = ClimateConfig{Precondition: 30 * time.Minute, Solar: true}
+
+	lp.socTimer.Time = clck.Now().Add(time.Hour)
+
+	// too early
+	lp.updateClimate(-1000)
+	assert.Equal(t, 0, vehicle.started)
+
+	clck.Add(40 * time.Minute)
+
+	// no surplus
+	lp.updateClimate(500)
+	assert.Equal(t, 0, vehicle.started)
+
+	lp.updateClimate(-1000)
+	assert.Equal(t, 1, vehicle.started)
+
+	// once per target
+	lp.updateClimate(-1000)
+	assert.Equal(t, 1, vehicle.started)
+}
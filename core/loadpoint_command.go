@@ -0,0 +1,46 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// GetChargerCommands returns the maintenance commands supported by the charger
+func (lp *LoadPoint) GetChargerCommands() []api.Command {
+	var res []api.Command
+	if c, ok := lp.charger.(api.Commander); ok {
+		res = append(res, c.Commands()...)
+	}
+
+	// wake-up is provided by the resurrector interface
+	if _, ok := lp.charger.(api.Resurrector); ok && !slices.Contains(res, api.CommandWakeUp) {
+		res = append(res, api.CommandWakeUp)
+	}
+
+	return res
+}
+
+// ChargerCommand executes a charger maintenance command
+func (lp *LoadPoint) ChargerCommand(cmd api.Command) error {
+	if !slices.Contains(lp.GetChargerCommands(), cmd) {
+		return api.ErrNotAvailable
+	}
+
+	lp.log.INFO.Printf("charger command: %s", cmd)
+
+	if c, ok := lp.charger.(api.Commander); ok && slices.Contains(c.Commands(), cmd) {
+		return c.Command(cmd)
+	}
+
+	return lp.charger.(api.Resurrector).WakeUp()
+}
+
+// GetChargerDiagnostics returns the charger's diagnostic data
+func (lp *LoadPoint) GetChargerDiagnostics() (api.Diagnostics, error) {
+	c, ok := lp.charger.(api.DiagnosticReporter)
+	if !ok {
+		return nil, api.ErrNotAvailable
+	}
+
+	return c.Diagnostics()
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type commandCharger struct {
+	api.Charger
+	executed []api.Command
+}
+
+func (c *commandCharger) Commands() []api.Command {
+	return []api.Command{api.CommandReboot}
+}
+
+func (c *commandCharger) Command(cmd api.Command) error {
+	c.executed = append(c.executed, cmd)
+	return nil
+}
+
+func (c *commandCharger) WakeUp() error {
+	c.executed = append(c.executed, api.CommandWakeUp)
+	return nil
+}
+
+func TestChargerCommand(t *testing.T) {
+	charger := new(commandCharger)
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		charger: charger,
+	}
+
+	assert.Equal(t, []api.Command{api.CommandReboot, api.CommandWakeUp}, lp.GetChargerCommands())
+
+	assert.NoError(t, lp.ChargerCommand(api.CommandReboot))
+	assert.NoError(t, lp.ChargerCommand(api.CommandWakeUp))
+	assert.ErrorIs(t, lp.ChargerCommand(api.CommandReauthorize), api.ErrNotAvailable)
+	assert.Equal(t, []api.Command{api.CommandReboot, api.CommandWakeUp}, charger.executed)
+
+	lp.charger = &struct{ api.Charger }{}
+	assert.Empty(t, lp.GetChargerCommands())
+}
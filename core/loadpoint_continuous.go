@@ -0,0 +1,34 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+// continuousLoadThreshold is the default charge power above which a continuous load is charging
+const continuousLoadThreshold = 50 // W
+
+// ContinuousLoadConfig operates loads that never report a vehicle status, e.g. boat or rv chargers behind a relay.
+// The load is always considered connected and charging while drawing power, vehicle detection is skipped.
+type ContinuousLoadConfig struct {
+	Enable    bool    `mapstructure:"enable"`
+	Threshold float64 `mapstructure:"threshold"` // charge power above which the load is charging, default 50W
+}
+
+// continuousLoad returns true if the loadpoint does not use the charger's vehicle status
+func (lp *LoadPoint) continuousLoad() bool {
+	return lp.ContinuousLoad.Enable
+}
+
+// continuousLoadStatus derives the charge status from the measured charge power
+func (lp *LoadPoint) continuousLoadStatus() api.ChargeStatus {
+	threshold := lp.ContinuousLoad.Threshold
+	if threshold <= 0 {
+		threshold = continuousLoadThreshold
+	}
+
+	if lp.chargePower > threshold {
+		return api.StatusC
+	}
+
+	return api.StatusB
+}
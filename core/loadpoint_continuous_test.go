@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinuousLoadStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// charger status must not be read
+	charger := mock.NewMockCharger(ctrl)
+	vehicle := mock.NewMockVehicle(ctrl)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.charger = charger
+	lp.coordinator = coordinator.NewAdapter(lp, coordinator.New(util.NewLogger("foo"), []api.Vehicle{vehicle}))
+	lp.ContinuousLoad = ContinuousLoadConfig{Enable: true}
+
+	require.NoError(t, lp.updateChargerStatus())
+	assert.Equal(t, api.StatusB, lp.GetStatus(), "idle load is connected")
+	assert.Empty(t, lp.coordinatedVehicles(), "no vehicle detection")
+
+	lp.chargePower = 1000
+	require.NoError(t, lp.updateChargerStatus())
+	assert.Equal(t, api.StatusC, lp.GetStatus(), "power drawn")
+
+	lp.ContinuousLoad.Threshold = 2000
+	require.NoError(t, lp.updateChargerStatus())
+	assert.Equal(t, api.StatusB, lp.GetStatus(), "below threshold")
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/tariff"
+)
+
+// setDefaultTariff assigns the site's grid tariff and currency unless the loadpoint has its own
+func (lp *LoadPoint) setDefaultTariff(tariffs tariff.Tariffs) {
+	if lp.tariff == nil {
+		lp.tariff = tariffs.Grid
+	}
+
+	if lp.Tariff.Currency == "" {
+		lp.currency = tariffs.Currency
+	}
+
+	lp.feedIn = tariffs.FeedIn
+}
+
+// setGreenShare sets the solar and battery share of the current charge power
+func (lp *LoadPoint) setGreenShare(share float64) {
+	lp.Lock()
+	defer lp.Unlock()
+	lp.greenShare = share
+}
+
+// getGreenShare returns the solar and battery share of the current charge power
+func (lp *LoadPoint) getGreenShare() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.greenShare
+}
+
+// resetSessionCost resets the session cost when a vehicle connects
+func (lp *LoadPoint) resetSessionCost() {
+	lp.sessionCost = 0
+	lp.costEnergy = 0
+	lp.solarEnergy = 0
+	lp.costSlots = nil
+	lp.publishSessionCost()
+}
+
+// sessionSolarPercentage returns the solar share of the energy charged while connected
+func (lp *LoadPoint) sessionSolarPercentage() float64 {
+	if lp.costEnergy <= 0 {
+		return 0
+	}
+	return 100 * lp.solarEnergy / lp.costEnergy
+}
+
+// sessionPrice returns the average price per kWh of the energy charged while connected
+func (lp *LoadPoint) sessionPrice() float64 {
+	if lp.costEnergy <= 0 {
+		return 0
+	}
+	return lp.sessionCost / lp.costEnergy * 1e3
+}
+
+// updateSessionCost accounts the energy charged since the last update. The grid share is
+// charged at the current tariff price, the solar share at the feed-in tariff if configured.
+func (lp *LoadPoint) updateSessionCost() {
+	energy := lp.getChargedEnergy()
+
+	if delta := energy - lp.costEnergy; delta > 0 {
+		solar := delta * lp.getGreenShare()
+
+		if lp.tariff != nil {
+			price, err := lp.tariff.CurrentPrice()
+			if err != nil {
+				// account energy with next successful price update
+				lp.log.ERROR.Printf("tariff: %v", err)
+				return
+			}
+			price += lp.Tariff.Fees
+
+			cost := (delta - solar) / 1e3 * price
+			if lp.feedIn != nil && solar > 0 {
+				if feedIn, err := lp.feedIn.CurrentPrice(); err == nil {
+					cost += solar / 1e3 * feedIn
+				} else {
+					lp.log.ERROR.Printf("feed-in tariff: %v", err)
+				}
+			}
+
+			lp.sessionCost += cost
+			lp.addCostSlot(delta/1e3, price, cost)
+		}
+
+		lp.solarEnergy += solar
+	}
+
+	lp.costEnergy = energy
+	lp.publishSessionCost()
+}
+
+// effectivePrice returns the price per kWh of the current charge power and the grid price including fees.
+// The solar share is priced at the feed-in tariff as opportunity cost. While not charging, pv mode
+// would charge from solar and other modes from grid.
+func (lp *LoadPoint) effectivePrice(mode api.ChargeMode) (float64, float64, error) {
+	grid, err := lp.tariff.CurrentPrice()
+	if err != nil {
+		return 0, 0, err
+	}
+	grid += lp.Tariff.Fees
+
+	share := lp.getGreenShare()
+	if lp.GetChargePower() <= standbyPower {
+		share = 0
+		if mode == api.ModePV {
+			share = 1
+		}
+	}
+
+	var feedIn float64
+	if lp.feedIn != nil && share > 0 {
+		if feedIn, err = lp.feedIn.CurrentPrice(); err != nil {
+			return 0, 0, fmt.Errorf("feed-in tariff: %w", err)
+		}
+	}
+
+	return (1-share)*grid + share*feedIn, grid, nil
+}
+
+// updateEffectivePrice publishes the effective price and returns if charging from grid is cheap.
+// The price limit applies to the grid price including fees, as solar energy is charged regardless.
+func (lp *LoadPoint) updateEffectivePrice(mode api.ChargeMode, cheap bool) bool {
+	if lp.tariff == nil {
+		return cheap
+	}
+
+	price, grid, err := lp.effectivePrice(mode)
+	if err != nil {
+		lp.log.ERROR.Printf("tariff: %v", err)
+		return cheap && lp.Tariff.Limit == 0
+	}
+
+	lp.publish(state.EffectivePrice, price)
+
+	if lp.Tariff.Limit != 0 {
+		return grid <= lp.Tariff.Limit
+	}
+
+	return cheap
+}
+
+// sessionCostOption adds cost and currency to the session log
+func (lp *LoadPoint) sessionCostOption(session *db.Session) {
+	if lp.tariff == nil {
+		return
+	}
+
+	session.Cost = lp.sessionCost
+	session.Price = lp.sessionPrice()
+	session.Currency = lp.currency.String()
+	session.Slots = append(db.CostSlots(nil), lp.costSlots...)
+}
+
+// sessionSolarOption adds the solar share to the session log
+func (lp *LoadPoint) sessionSolarOption(session *db.Session) {
+	session.SolarPercentage = lp.sessionSolarPercentage()
+}
+
+// publishSessionCost publishes session cost and average price
+func (lp *LoadPoint) publishSessionCost() {
+	lp.publish(state.SessionCost, lp.sessionCost)
+	lp.publish(state.SessionPrice, lp.sessionPrice())
+	lp.publish(state.SessionSolarPercentage, lp.sessionSolarPercentage())
+}
+
+// ratePeriod returns the dynamic tariff period containing ts
+func (lp *LoadPoint) ratePeriod(ts time.Time) (time.Time, time.Time, bool) {
+	rater, ok := lp.tariff.(api.Rater)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	rates, err := rater.Rates()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	for _, r := range rates {
+		if !ts.Before(r.Start) && ts.Before(r.End) {
+			return r.Start, r.End, true
+		}
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// addCostSlot accounts energy and cost to the slot of the current tariff period.
+// Without dynamic tariff periods, a new slot starts whenever the price changes.
+func (lp *LoadPoint) addCostSlot(energy, price, cost float64) {
+	now := lp.clock.Now()
+	start, end, period := lp.ratePeriod(now)
+
+	if n := len(lp.costSlots); n > 0 {
+		last := &lp.costSlots[n-1]
+
+		if period && last.Start.Equal(start) || !period && last.Price == price {
+			last.Energy += energy
+			last.Cost += cost
+			if !period {
+				last.End = now
+			}
+			return
+		}
+
+		if !period {
+			last.End = now
+		}
+	}
+
+	if !period {
+		start, end = now, now
+	}
+
+	lp.costSlots = append(lp.costSlots, db.CostSlot{
+		Start:  start,
+		End:    end,
+		Energy: energy,
+		Price:  price,
+		Cost:   cost,
+	})
+}
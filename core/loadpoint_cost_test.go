@@ -0,0 +1,174 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/currency"
+)
+
+func TestSessionCost(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.Tariff.Currency = "CHF"
+	lp.currency = currency.CHF
+
+	fixed := &tariff.Fixed{Price: 0.2}
+	lp.tariff = fixed
+
+	// site tariff must not override loadpoint tariff
+	lp.setDefaultTariff(tariff.Tariffs{Currency: currency.EUR, Grid: &tariff.Fixed{Price: 1}})
+	assert.Equal(t, fixed, lp.tariff)
+	assert.Equal(t, currency.CHF, lp.currency)
+
+	lp.setChargedEnergy(5e3)
+	lp.updateSessionCost()
+	assert.InDelta(t, 1.0, lp.sessionCost, 1e-6)
+
+	// price change only applies to additional energy
+	fixed.Price = 0.4
+	lp.setChargedEnergy(10e3)
+	lp.updateSessionCost()
+	assert.InDelta(t, 3.0, lp.sessionCost, 1e-6)
+	assert.InDelta(t, 0.3, lp.sessionPrice(), 1e-6)
+
+	var session db.Session
+	lp.sessionCostOption(&session)
+	assert.InDelta(t, 3.0, session.Cost, 1e-6)
+	assert.Equal(t, "CHF", session.Currency)
+
+	require.Len(t, session.Slots, 2)
+	assert.InDelta(t, 1.0, session.Slots[0].Cost, 1e-6)
+	assert.InDelta(t, 5.0, session.Slots[1].Energy, 1e-6)
+	assert.Equal(t, 0.4, session.Slots[1].Price)
+
+	lp.resetSessionCost()
+	assert.Equal(t, 0.0, lp.sessionCost)
+	assert.Empty(t, lp.costSlots)
+}
+
+// ratesTariff is a dynamic tariff with hourly prices
+type ratesTariff struct {
+	clock clock.Clock
+	rates api.Rates
+}
+
+func (t *ratesTariff) IsCheap() (bool, error) {
+	return false, nil
+}
+
+func (t *ratesTariff) CurrentPrice() (float64, error) {
+	for _, r := range t.rates {
+		if !t.clock.Now().Before(r.Start) && t.clock.Now().Before(r.End) {
+			return r.Price, nil
+		}
+	}
+	return 0, errors.New("no price")
+}
+
+func (t *ratesTariff) Rates() (api.Rates, error) {
+	return t.rates, nil
+}
+
+func TestSessionCostSlots(t *testing.T) {
+	clck := clock.NewMock()
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+
+	now := clck.Now().Truncate(time.Hour)
+	clck.Set(now)
+
+	lp.tariff = &ratesTariff{clock: clck, rates: api.Rates{
+		{Start: now, End: now.Add(time.Hour), Price: 0.3},
+		{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Price: 0.1},
+	}}
+
+	// two updates within first period
+	for _, e := range []float64{2e3, 4e3} {
+		clck.Add(15 * time.Minute)
+		lp.setChargedEnergy(e)
+		lp.updateSessionCost()
+	}
+
+	clck.Set(now.Add(90 * time.Minute))
+	lp.setChargedEnergy(10e3)
+	lp.updateSessionCost()
+
+	assert.InDelta(t, 1.8, lp.sessionCost, 1e-6)
+	require.Len(t, lp.costSlots, 2)
+
+	assert.Equal(t, now, lp.costSlots[0].Start)
+	assert.Equal(t, now.Add(time.Hour), lp.costSlots[0].End)
+	assert.InDelta(t, 4.0, lp.costSlots[0].Energy, 1e-6)
+	assert.InDelta(t, 1.2, lp.costSlots[0].Cost, 1e-6)
+
+	assert.Equal(t, now.Add(time.Hour), lp.costSlots[1].Start)
+	assert.InDelta(t, 6.0, lp.costSlots[1].Energy, 1e-6)
+	assert.InDelta(t, 0.6, lp.costSlots[1].Cost, 1e-6)
+}
+
+func TestSessionSolarCost(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.setDefaultTariff(tariff.Tariffs{
+		Grid:   &tariff.Fixed{Price: 0.3},
+		FeedIn: &tariff.Fixed{Price: 0.1},
+	})
+
+	site := &Site{gridPower: 2500}
+	lp.setGreenShare(site.greenShare(10e3))
+
+	lp.setChargedEnergy(10e3)
+	lp.updateSessionCost()
+
+	// 2.5kWh grid at 0.3, 7.5kWh solar at 0.1
+	assert.InDelta(t, 1.5, lp.sessionCost, 1e-6)
+	assert.InDelta(t, 75.0, lp.sessionSolarPercentage(), 1e-6)
+
+	var session db.Session
+	lp.sessionSolarOption(&session)
+	assert.InDelta(t, 75.0, session.SolarPercentage, 1e-6)
+
+	// feed-in does not count
+	site.gridPower = -1000
+	assert.Equal(t, 1.0, site.greenShare(10e3))
+}
+
+func TestEffectivePrice(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.Tariff.Fees = 0.2
+	lp.Tariff.Limit = 0.25
+	lp.setDefaultTariff(tariff.Tariffs{
+		Grid:   &tariff.Fixed{Price: 0.1},
+		FeedIn: &tariff.Fixed{Price: 0.08},
+	})
+
+	price := func(mode api.ChargeMode) float64 {
+		res, _, err := lp.effectivePrice(mode)
+		require.NoError(t, err)
+		return res
+	}
+
+	// not charging
+	assert.InDelta(t, 0.3, price(api.ModeMinPV), 1e-6)
+	assert.InDelta(t, 0.08, price(api.ModePV), 1e-6, "pv mode charges from solar")
+	assert.False(t, lp.updateEffectivePrice(api.ModeMinPV, true), "grid price including fees above limit")
+
+	// half solar
+	lp.chargePower = 4e3
+	lp.setGreenShare(0.5)
+	assert.InDelta(t, 0.19, price(api.ModePV), 1e-6)
+
+	lp.Tariff.Fees = 0.1
+	assert.True(t, lp.updateEffectivePrice(api.ModePV, false), "grid price including fees at limit")
+
+	// site cheap indication without limit
+	lp.Tariff.Limit = 0
+	assert.True(t, lp.updateEffectivePrice(api.ModePV, true))
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+const guestIdentifier = "guest"
+
+// StartGuestSession starts an ad-hoc guest charging session with energy and cost limits
+func (lp *LoadPoint) StartGuestSession(energy, price, maxCost float64) error {
+	if energy < 0 || price < 0 || maxCost < 0 {
+		return errors.New("invalid guest session limits")
+	}
+
+	if maxCost > 0 && price == 0 {
+		return errors.New("cost limit requires price")
+	}
+
+	// guest sessions are independent of configured vehicles
+	lp.SetVehicle(nil)
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.guestSession != nil && lp.guestSession.Active() {
+		return errors.New("guest session already active")
+	}
+
+	lp.guestSession = &loadpoint.GuestSession{
+		Created: lp.clock.Now(),
+		Energy:  energy,
+		MaxCost: maxCost,
+		Price:   price,
+		Mode:    lp.Mode,
+	}
+
+	// energy charged before the session is not billed
+	lp.guestEnergy = lp.chargedEnergy
+
+	lp.log.DEBUG.Printf("start guest session: %.3gkWh @ %.3g/kWh", lp.guestSession.EnergyLimit(), price)
+
+	lp.updateSession(func(session *db.Session) {
+		session.Identifier = guestIdentifier
+	})
+
+	lp.setGuestTargetEnergy()
+	lp.publishGuestSession()
+
+	if lp.Mode != api.ModeNow {
+		lp.Mode = api.ModeNow
+		lp.publish(state.Mode, lp.Mode)
+		lp.elapsePVTimer()
+	}
+
+	lp.requestUpdate()
+
+	return nil
+}
+
+// GetGuestSession returns the current or last guest session
+func (lp *LoadPoint) GetGuestSession() *loadpoint.GuestSession {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.guestSession == nil {
+		return nil
+	}
+
+	res := *lp.guestSession
+	return &res
+}
+
+// StopGuestSession stops the guest session and returns its summary
+func (lp *LoadPoint) StopGuestSession() (*loadpoint.GuestSession, error) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.guestSession == nil || !lp.guestSession.Active() {
+		return nil, errors.New("no active guest session")
+	}
+
+	lp.finishGuestSession()
+	lp.requestUpdate()
+
+	res := *lp.guestSession
+	return &res, nil
+}
+
+// guestSessionActive returns true if a guest session is active
+func (lp *LoadPoint) guestSessionActive() bool {
+	lp.Lock()
+	defer lp.Unlock()
+
+	return lp.guestSession != nil && lp.guestSession.Active()
+}
+
+// finishGuestSession finalizes the active guest session and reverts to the previous mode (no mutex)
+func (lp *LoadPoint) finishGuestSession() {
+	lp.updateGuestSession()
+	lp.guestSession.Finished = lp.clock.Now()
+
+	lp.log.DEBUG.Printf("stop guest session: %.3gkWh, cost %.2f", lp.guestSession.ChargedEnergy, lp.guestSession.Cost)
+
+	lp.setTargetEnergy(0)
+	lp.publishGuestSession()
+
+	if mode := lp.guestSession.Mode; mode != "" && lp.Mode != mode {
+		lp.Mode = mode
+		lp.publish(state.Mode, lp.Mode)
+	}
+}
+
+// updateGuestSession updates charged energy and cost of the active guest session (no mutex)
+func (lp *LoadPoint) updateGuestSession() {
+	if lp.guestSession == nil || !lp.guestSession.Active() {
+		return
+	}
+
+	// charged energy restarts with the vehicle connecting after the guest session started
+	if lp.chargedEnergy < lp.guestEnergy {
+		lp.guestEnergy = 0
+		lp.setGuestTargetEnergy()
+	}
+
+	lp.guestSession.ChargedEnergy = (lp.chargedEnergy - lp.guestEnergy) / 1e3
+	lp.guestSession.Cost = lp.guestSession.ChargedEnergy * lp.guestSession.Price
+}
+
+// setGuestTargetEnergy limits the session's charged energy to the guest session's energy limit (no mutex)
+func (lp *LoadPoint) setGuestTargetEnergy() {
+	if limit := lp.guestSession.EnergyLimit(); limit > 0 {
+		lp.setTargetEnergy(lp.guestEnergy/1e3 + limit)
+	} else {
+		lp.setTargetEnergy(0)
+	}
+}
+
+// publishGuestSession publishes the guest session state (no mutex)
+func (lp *LoadPoint) publishGuestSession() {
+	active := lp.guestSession != nil && lp.guestSession.Active()
+	lp.publish(state.GuestSessionActive, active)
+
+	if lp.guestSession != nil {
+		lp.publish(state.GuestSessionCost, lp.guestSession.Cost)
+	}
+}
+
+// syncGuestSession updates the guest session during the loadpoint update cycle.
+// It returns true if the session finished as its budget is exhausted.
+func (lp *LoadPoint) syncGuestSession() bool {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.guestSession == nil || !lp.guestSession.Active() {
+		return false
+	}
+
+	lp.updateGuestSession()
+
+	if lp.guestSession.Exhausted() {
+		lp.log.INFO.Println("guest session budget exhausted")
+		lp.finishGuestSession()
+		return true
+	}
+
+	lp.publishGuestSession()
+
+	return false
+}
+
+// stopGuestSessionOnDisconnect finalizes an active guest session when the vehicle disconnects
+func (lp *LoadPoint) stopGuestSessionOnDisconnect() {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.guestSession != nil && lp.guestSession.Active() {
+		lp.finishGuestSession()
+	}
+}
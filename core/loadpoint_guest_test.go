@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestSessionBudget(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clock.NewMock()
+	lp.Mode = api.ModePV
+
+	require.NoError(t, lp.StartGuestSession(0, 0.5, 2))
+	assert.Equal(t, api.ModeNow, lp.GetMode())
+	assert.Equal(t, 4.0, lp.targetEnergy)
+
+	lp.chargedEnergy = 3e3
+	assert.False(t, lp.syncGuestSession())
+	assert.True(t, lp.guestSessionActive())
+
+	// budget exhausted, revert to previous mode
+	lp.chargedEnergy = 4e3
+	assert.True(t, lp.syncGuestSession())
+	assert.False(t, lp.guestSessionActive())
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Equal(t, 2.0, lp.GetGuestSession().Cost)
+	assert.Zero(t, lp.targetEnergy)
+}
+
+func TestGuestSessionMidCharge(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clock.NewMock()
+
+	// energy charged before the guest session
+	lp.chargedEnergy = 5e3
+
+	require.NoError(t, lp.StartGuestSession(2, 0.5, 0))
+	assert.Equal(t, 7.0, lp.targetEnergy)
+
+	lp.chargedEnergy = 6e3
+	assert.False(t, lp.syncGuestSession())
+	assert.Equal(t, 1.0, lp.GetGuestSession().ChargedEnergy)
+	assert.Equal(t, 0.5, lp.GetGuestSession().Cost)
+
+	lp.chargedEnergy = 7e3
+	assert.True(t, lp.syncGuestSession())
+	assert.Equal(t, 2.0, lp.GetGuestSession().ChargedEnergy)
+	assert.Equal(t, 1.0, lp.GetGuestSession().Cost)
+}
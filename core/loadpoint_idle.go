@@ -0,0 +1,70 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+)
+
+// IdleConfig frees shared chargers after the vehicle finished charging
+type IdleConfig struct {
+	Timeout time.Duration `mapstructure:"timeout"` // disable the charger after the vehicle reached its target soc or stopped drawing current for this long
+	Unlock  bool          `mapstructure:"unlock"`  // unlock the cable afterwards, requires charger support
+}
+
+// idleTimeout returns true once the connected vehicle has been idle for the configured duration.
+// The vehicle is idle when it reached its target soc or does not draw current from the enabled charger.
+func (lp *LoadPoint) idleTimeout() bool {
+	if lp.Idle.Timeout <= 0 {
+		return false
+	}
+
+	if lp.idleFreed {
+		return true
+	}
+
+	if !lp.targetSocReached() && (lp.GetStatus() != api.StatusB || !lp.enabled) {
+		lp.idleSince = time.Time{}
+		return false
+	}
+
+	if lp.idleSince.IsZero() {
+		lp.idleSince = lp.clock.Now()
+	}
+
+	elapsed := lp.clock.Since(lp.idleSince)
+	if elapsed < lp.Idle.Timeout {
+		return false
+	}
+
+	lp.log.INFO.Printf("vehicle idle for %v, freeing charger", elapsed.Round(time.Second))
+	lp.idleFreed = true
+	lp.pushEvent(event.VehicleIdle{Duration: elapsed})
+
+	return true
+}
+
+// freeCharger disables the charger and unlocks the cable once
+func (lp *LoadPoint) freeCharger() error {
+	if err := lp.setLimit(0, true); err != nil || !lp.Idle.Unlock || lp.idleUnlocked {
+		return err
+	}
+
+	lp.idleUnlocked = true
+
+	if c, ok := lp.charger.(api.CableUnlocker); ok {
+		return c.Unlock()
+	}
+
+	lp.log.WARN.Println("idle: charger does not support cable unlock")
+
+	return nil
+}
+
+// resetIdle makes the charger available for the next vehicle
+func (lp *LoadPoint) resetIdle() {
+	lp.idleSince = time.Time{}
+	lp.idleFreed = false
+	lp.idleUnlocked = false
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type unlockCharger struct {
+	*mock.MockCharger
+	unlocked int
+}
+
+func (c *unlockCharger) Unlock() error {
+	c.unlocked++
+	return nil
+}
+
+func TestIdleTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := &unlockCharger{MockCharger: mock.NewMockCharger(ctrl)}
+	clck := clock.NewMock()
+	pushChan := make(chan push.Event, 10)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.charger = charger
+	lp.events = pushPublisher(lp, pushChan)
+	lp.wakeUpTimer = NewTimer() // silence nil panics
+	lp.MinCurrent = minA
+	lp.MaxCurrent = maxA
+	lp.enabled = true
+	lp.status = api.StatusB
+	lp.Idle = IdleConfig{Timeout: time.Minute, Unlock: true}
+
+	assert.False(t, lp.idleTimeout(), "start watching")
+
+	clck.Add(30 * time.Second)
+	assert.False(t, lp.idleTimeout(), "within timeout")
+
+	// charging resets the timer
+	lp.status = api.StatusC
+	assert.False(t, lp.idleTimeout(), "charging")
+
+	lp.status = api.StatusB
+	assert.False(t, lp.idleTimeout(), "timer restarted")
+
+	clck.Add(time.Minute)
+	assert.True(t, lp.idleTimeout())
+	assert.Equal(t, "idle", (<-pushChan).Event)
+
+	// disable and unlock once
+	charger.EXPECT().Enable(false).Return(nil)
+	assert.NoError(t, lp.freeCharger())
+	assert.False(t, lp.enabled)
+	assert.Equal(t, 1, charger.unlocked)
+
+	assert.True(t, lp.idleTimeout(), "remains freed")
+	assert.NoError(t, lp.freeCharger())
+	assert.Equal(t, 1, charger.unlocked)
+	assert.Len(t, pushChan, 0)
+
+	// vehicle leaves
+	lp.resetIdle()
+	lp.status = api.StatusA
+	assert.False(t, lp.idleTimeout())
+}
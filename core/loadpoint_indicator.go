@@ -0,0 +1,43 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// indicate returns the loadpoint state shown by the charger
+func (lp *LoadPoint) indicate(mode api.ChargeMode, err error) api.Indication {
+	switch {
+	case err != nil || lp.fault != api.FaultNone:
+		return api.IndicationError
+	case !lp.connected():
+		return api.IndicationIdle
+	case !lp.charging():
+		return api.IndicationWaiting
+	case mode == api.ModePV || mode == api.ModeMinPV:
+		return api.IndicationPV
+	default:
+		return api.IndicationCharging
+	}
+}
+
+// updateIndicator reflects the loadpoint state on charger leds or displays
+func (lp *LoadPoint) updateIndicator(mode api.ChargeMode, err error) {
+	ci, ok := lp.charger.(api.Indicator)
+	if !ok {
+		return
+	}
+
+	indication := lp.indicate(mode, err)
+	if indication == lp.indication {
+		return
+	}
+
+	if err := ci.Indicate(indication); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+		lp.log.ERROR.Printf("charger indicator: %v", err)
+		return
+	}
+
+	lp.indication = indication
+}
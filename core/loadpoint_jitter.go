@@ -0,0 +1,63 @@
+package core
+
+import (
+	"math/rand"
+	"time"
+)
+
+// JitterConfig defines the randomized switching delay of all loadpoints.
+// Spreading charger starts avoids synchronized load steps when many loadpoints react to the same tariff boundary (VDE-AR-N 4100).
+type JitterConfig struct {
+	Max  time.Duration `mapstructure:"max"`  // upper bound of the random delay
+	Stop bool          `mapstructure:"stop"` // delay stops as well as starts
+}
+
+// jitter delays charger switching by a random duration
+type jitter struct {
+	max     time.Duration
+	stop    bool
+	random  func(time.Duration) time.Duration
+	pending bool      // switch pending
+	target  bool      // pending enabled state
+	until   time.Time // earliest switching time
+}
+
+// newJitter creates the switching jitter from configuration
+func newJitter(cc *JitterConfig) *jitter {
+	if cc == nil || cc.Max <= 0 {
+		return nil
+	}
+
+	return &jitter{
+		max:  cc.Max,
+		stop: cc.Stop,
+		random: func(max time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(max)))
+		},
+	}
+}
+
+// jitterDelay returns the remaining random delay before the charger may switch to enabled
+func (lp *LoadPoint) jitterDelay(enabled bool) time.Duration {
+	j := lp.jitter
+	if j == nil || !enabled && !j.stop {
+		return 0
+	}
+
+	now := lp.clock.Now()
+
+	if !j.pending || j.target != enabled {
+		j.pending = true
+		j.target = enabled
+		j.until = now.Add(j.random(j.max))
+	}
+
+	return j.until.Sub(now).Truncate(time.Second)
+}
+
+// resetJitter discards the pending switch once the charger switched or the switch is no longer required
+func (lp *LoadPoint) resetJitter() {
+	if lp.jitter != nil {
+		lp.jitter.pending = false
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitterDelaysStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	clck := clock.NewMock()
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.charger = charger
+	lp.wakeUpTimer = NewTimer()
+
+	lp.jitter = newJitter(&JitterConfig{Max: 5 * time.Minute})
+	lp.jitter.random = func(time.Duration) time.Duration { return 2 * time.Minute }
+
+	charger.EXPECT().MaxCurrent(int64(16)).Return(nil)
+
+	// start is delayed
+	require.NoError(t, lp.setLimit(16, false))
+	assert.False(t, lp.enabled)
+
+	clck.Add(time.Minute)
+	require.NoError(t, lp.setLimit(16, false))
+	assert.False(t, lp.enabled)
+
+	// start after delay
+	clck.Add(time.Minute)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.setLimit(16, false))
+	assert.True(t, lp.enabled)
+	assert.False(t, lp.jitter.pending)
+
+	// stop is immediate unless configured
+	clck.Add(lp.GuardDuration)
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.setLimit(0, false))
+	assert.False(t, lp.enabled)
+}
+
+func TestJitterReset(t *testing.T) {
+	clck := clock.NewMock()
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+
+	lp.jitter = newJitter(&JitterConfig{Max: 5 * time.Minute, Stop: true})
+	lp.jitter.random = func(time.Duration) time.Duration { return 2 * time.Minute }
+
+	assert.Equal(t, 2*time.Minute, lp.jitterDelay(true))
+
+	// pending switch is kept until reset
+	clck.Add(time.Minute)
+	assert.Equal(t, time.Minute, lp.jitterDelay(true))
+
+	// changed target draws a new delay
+	assert.Equal(t, 2*time.Minute, lp.jitterDelay(false))
+
+	lp.resetJitter()
+	clck.Add(time.Minute)
+	assert.Equal(t, 2*time.Minute, lp.jitterDelay(true))
+
+	// disabled without configuration
+	assert.Nil(t, newJitter(nil))
+	assert.Nil(t, newJitter(&JitterConfig{}))
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"math"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util/locale"
+)
+
+// voltageHysteresis is the voltage above threshold required for recovering from undervoltage
+const voltageHysteresis = 5 // V
+
+// MonitorConfig defines the supply voltage thresholds for charger protection
+type MonitorConfig struct {
+	MinVoltage    float64 `mapstructure:"minVoltage"`    // limit to min current below this phase voltage
+	CutoffVoltage float64 `mapstructure:"cutoffVoltage"` // pause charging below this phase voltage
+}
+
+// faultDescription returns the localized description of a fault code
+func faultDescription(fault string) string {
+	if fault == api.FaultNone || locale.Localizer() == nil {
+		return fault
+	}
+
+	id := "faults." + fault
+	if msg := locale.LocalizeID(id); msg != id {
+		return msg
+	}
+
+	return fault
+}
+
+// lowestVoltage returns the lowest active phase voltage of charge meter or charger
+func (lp *LoadPoint) lowestVoltage() (float64, bool) {
+	vm, ok := lp.chargeMeter.(api.MeterVoltage)
+	if !ok {
+		if vm, ok = lp.charger.(api.MeterVoltage); !ok {
+			return 0, false
+		}
+	}
+
+	u1, u2, u3, err := vm.Voltages()
+	if err != nil {
+		lp.log.ERROR.Printf("charge voltages: %v", err)
+		return 0, false
+	}
+
+	lp.log.DEBUG.Printf("charge voltages: %.0fV", []float64{u1, u2, u3})
+	lp.publish(state.ChargeVoltages, []float64{u1, u2, u3})
+
+	var res float64
+	for _, u := range []float64{u1, u2, u3} {
+		// ignore unused phases
+		if u > 0 && (res == 0 || u < res) {
+			res = u
+		}
+	}
+
+	return res, res > 0
+}
+
+// updateMonitor checks charger faults and supply voltage
+func (lp *LoadPoint) updateMonitor() {
+	fault := lp.fault
+
+	if fr, ok := lp.charger.(api.FaultReporter); ok {
+		// write faults are cleared by the write monitor
+		if code, err := fr.Fault(); err == nil && (code != api.FaultNone || fault != api.FaultWrite) {
+			fault = code
+		} else if err != nil {
+			lp.log.ERROR.Printf("charger fault: %v", err)
+		}
+	} else if fault != api.FaultUndervoltage && fault != api.FaultWrite {
+		fault = api.FaultNone
+	}
+
+	if u, ok := lp.lowestVoltage(); ok {
+		if cutoff := lp.Monitor.CutoffVoltage; cutoff > 0 && (fault == api.FaultNone || fault == api.FaultUndervoltage) {
+			fault = api.FaultNone
+			if u < cutoff || lp.fault == api.FaultUndervoltage && u < cutoff+voltageHysteresis {
+				fault = api.FaultUndervoltage
+			}
+		}
+
+		limit := lp.Monitor.MinVoltage
+		sag := limit > 0 && (u < limit || lp.voltageSag && u < limit+voltageHysteresis)
+
+		if sag != lp.voltageSag {
+			if sag {
+				lp.log.WARN.Printf("undervoltage: %.0fV, limiting to min current", u)
+			} else {
+				lp.log.INFO.Printf("undervoltage cleared: %.0fV", u)
+			}
+
+			lp.voltageSag = sag
+			lp.publish(state.VoltageSag, sag)
+		}
+	}
+
+	lp.setFault(fault)
+}
+
+// setFault updates the active fault and notifies when a fault is raised
+func (lp *LoadPoint) setFault(fault string) {
+	if fault == lp.fault {
+		return
+	}
+
+	if fault != api.FaultNone {
+		lp.log.WARN.Printf("charger fault: %s", faultDescription(fault))
+	} else {
+		lp.log.INFO.Println("charger fault cleared")
+	}
+
+	lp.fault = fault
+	lp.publish(state.Fault, fault)
+	lp.publish(state.FaultDescription, faultDescription(fault))
+
+	if fault != api.FaultNone {
+		lp.pushEvent(event.DeviceError{Device: "charger", Error: faultDescription(fault)})
+	}
+}
+
+// monitorCurrent limits the charge current while the charger is faulted or the supply voltage sags
+func (lp *LoadPoint) monitorCurrent(current float64) float64 {
+	switch {
+	case lp.fault == api.FaultWrite:
+		return math.Min(current, lp.Watchdog.Current)
+
+	case lp.fault != api.FaultNone:
+		return 0
+
+	case lp.voltageSag && current > lp.GetMinCurrent():
+		lp.log.DEBUG.Printf("undervoltage: limit current to %.3gA", lp.GetMinCurrent())
+		return lp.GetMinCurrent()
+
+	default:
+		return current
+	}
+}
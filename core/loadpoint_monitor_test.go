@@ -0,0 +1,70 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type monitorCharger struct {
+	api.Charger
+	voltage float64
+	fault   string
+}
+
+func (c *monitorCharger) Voltages() (float64, float64, float64, error) {
+	return c.voltage, c.voltage, 0, nil
+}
+
+func (c *monitorCharger) Fault() (string, error) {
+	return c.fault, nil
+}
+
+func TestMonitor(t *testing.T) {
+	charger := &monitorCharger{}
+	pushChan := make(chan push.Event, 10)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.charger = charger
+	lp.events = pushPublisher(lp, pushChan)
+	lp.MinCurrent = 6
+	lp.Monitor = MonitorConfig{MinVoltage: 210, CutoffVoltage: 195}
+
+	tc := []struct {
+		voltage float64
+		fault   string
+		sag     bool
+		res     string
+		current float64
+	}{
+		{230, "", false, api.FaultNone, 16},
+		{205, "", true, api.FaultNone, 6},
+		{212, "", true, api.FaultNone, 6}, // hysteresis
+		{190, "", true, api.FaultUndervoltage, 0},
+		{198, "", true, api.FaultUndervoltage, 0}, // hysteresis
+		{201, "", true, api.FaultNone, 6},
+		{230, "", false, api.FaultNone, 16},
+		{230, api.FaultRCCB, false, api.FaultRCCB, 0},
+		{190, api.FaultRCCB, true, api.FaultRCCB, 0}, // charger fault takes precedence
+		{230, "", false, api.FaultNone, 16},
+	}
+
+	for i, tc := range tc {
+		charger.voltage = tc.voltage
+		charger.fault = tc.fault
+
+		lp.updateMonitor()
+
+		assert.Equal(t, tc.sag, lp.voltageSag, i)
+		assert.Equal(t, tc.res, lp.fault, i)
+		assert.Equal(t, tc.current, lp.monitorCurrent(16), i)
+	}
+
+	// notify once per raised fault
+	assert.Len(t, pushChan, 2)
+
+	assert.Equal(t, "undervoltage", faultDescription(api.FaultUndervoltage))
+}
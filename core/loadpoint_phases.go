@@ -2,17 +2,30 @@ package core
 
 import (
 	"math"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
 )
 
+// PhaseSwitchConfig defines the automatic 1p3p switching of PhaseSwitcher chargers in pv mode
+type PhaseSwitchConfig struct {
+	Scale1p, Scale3p ThresholdConfig // Available power and delay, defaults to min current power and pv disable/enable delay
+	Hysteresis       float64         // W, raises the 3p and lowers the 1p threshold
+	Dwell            time.Duration   // Minimum duration between switches
+	Pause            time.Duration   // Charge pause before and after switching
+}
+
+// default charge pause for vehicles requiring a pause around phase switching
+const phaseSwitchPause = time.Minute
+
 // resetMeasuredPhases resets measured phases to unknown on vehicle disconnect, phase switch or phase api call
 func (lp *LoadPoint) resetMeasuredPhases() {
 	lp.Lock()
 	lp.measuredPhases = 0
 	lp.Unlock()
 
-	lp.publish(phasesActive, lp.activePhases())
+	lp.publish(state.PhasesActive, lp.activePhases())
 }
 
 // getMeasuredPhases provides synchronized access to measuredPhases
@@ -74,10 +87,98 @@ func (lp *LoadPoint) maxActivePhases() int {
 	return min(expect(vehicle), expect(physical), expect(measured))
 }
 
+// getVehiclePhases returns the configured vehicle phases or the phases previously detected while charging the vehicle
 func (lp *LoadPoint) getVehiclePhases() int {
-	if lp.vehicle != nil {
-		return lp.vehicle.Phases()
+	if lp.vehicle == nil {
+		return 0
+	}
+
+	if phases := lp.vehicle.Phases(); phases > 0 {
+		return phases
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.vehiclePhases[lp.vehicle]
+}
+
+// learnVehiclePhases remembers the phases measured while charging the vehicle.
+// Measured 1p only restricts the vehicle if the charger was not switched to 1p.
+func (lp *LoadPoint) learnVehiclePhases(measured int) {
+	if lp.vehicle == nil || lp.vehicle.Phases() > 0 || (measured == 1 && lp.GetPhases() != 3) {
+		return
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.vehiclePhases == nil {
+		lp.vehiclePhases = make(map[api.Vehicle]int)
+	}
+
+	if lp.vehiclePhases[lp.vehicle] != measured {
+		lp.vehiclePhases[lp.vehicle] = measured
+		lp.log.DEBUG.Printf("vehicle %s: detected %dp", lp.vehicle.Title(), measured)
+	}
+}
+
+// phaseSwitchPause returns the charge pause before and after switching phases
+func (lp *LoadPoint) phaseSwitchPause() time.Duration {
+	if lp.PhaseSwitch.Pause == 0 && lp.vehicleHasFeature(api.PhaseSwitchPause) {
+		return phaseSwitchPause
+	}
+	return lp.PhaseSwitch.Pause
+}
+
+// switchPhases switches phases right away or stops charging and switches after the charge pause
+func (lp *LoadPoint) switchPhases(phases int) error {
+	pause := lp.phaseSwitchPause()
+	if pause == 0 || lp.GetPhases() == phases {
+		return lp.scalePhases(phases)
+	}
+
+	if err := lp.setLimit(0, true); err != nil {
+		return err
+	}
+
+	lp.log.DEBUG.Printf("pause charging for %v before switching to %dp", pause, phases)
+	lp.phasePending = phases
+	lp.phasePause = lp.clock.Now()
+
+	return nil
+}
+
+// phaseSwitchPausing returns true while charging is paused around switching phases.
+// The pending switch is executed once the pause before switching has elapsed.
+func (lp *LoadPoint) phaseSwitchPausing() bool {
+	if lp.phasePause.IsZero() {
+		return false
 	}
 
-	return 0
+	if lp.clock.Since(lp.phasePause) < lp.phaseSwitchPause() {
+		return true
+	}
+
+	phases := lp.phasePending
+	lp.resetPhasePause()
+
+	if phases == 0 {
+		return false
+	}
+
+	if err := lp.scalePhases(phases); err != nil {
+		lp.log.ERROR.Println(err)
+		return true
+	}
+
+	// pause after switching
+	lp.phasePause = lp.clock.Now()
+
+	return true
+}
+
+// resetPhasePause cancels the charge pause and pending phase switch
+func (lp *LoadPoint) resetPhasePause() {
+	lp.phasePause = time.Time{}
+	lp.phasePending = 0
 }
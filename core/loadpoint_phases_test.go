@@ -379,6 +379,107 @@ func TestPvScalePhasesTimer(t *testing.T) {
 	}
 }
 
+func TestPvScalePhasesConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := &struct {
+		*mock.MockCharger
+		*mock.MockPhaseSwitcher
+	}{
+		mock.NewMockCharger(ctrl),
+		mock.NewMockPhaseSwitcher(ctrl),
+	}
+
+	dt := time.Minute
+	Voltage = 230 // V
+
+	clock := clock.NewMock()
+	clock.Add(time.Hour) // avoid time.IsZero
+
+	newLoadPoint := func(phases int, ps PhaseSwitchConfig) *LoadPoint {
+		return &LoadPoint{
+			log:            util.NewLogger("foo"),
+			clock:          clock,
+			charger:        charger,
+			MinCurrent:     minA,
+			MaxCurrent:     maxA,
+			phases:         phases,
+			measuredPhases: phases,
+			Enable:         ThresholdConfig{Delay: dt},
+			Disable:        ThresholdConfig{Delay: dt},
+			PhaseSwitch:    ps,
+		}
+	}
+
+	// hysteresis raises the 3p threshold
+	lp := newLoadPoint(1, PhaseSwitchConfig{Hysteresis: 500})
+	lp.phaseTimer = clock.Now().Add(-dt)
+
+	if lp.pvScalePhases(3*Voltage*minA, minA, maxA) || !lp.phaseTimer.IsZero() {
+		t.Error("unexpected switch below 3p threshold")
+	}
+
+	lp.phaseTimer = clock.Now().Add(-dt)
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(3).Return(nil)
+
+	if !lp.pvScalePhases(3*Voltage*minA+500, minA, maxA) || lp.phases != 3 {
+		t.Error("missing switch above 3p threshold")
+	}
+
+	// configured 1p threshold and delay
+	lp = newLoadPoint(3, PhaseSwitchConfig{Scale1p: ThresholdConfig{Threshold: 2000, Delay: 2 * dt}})
+	lp.phaseTimer = clock.Now().Add(-dt)
+
+	if lp.pvScalePhases(1999, minA, maxA) || lp.phases != 3 {
+		t.Error("unexpected switch before 1p delay")
+	}
+
+	lp.phaseTimer = clock.Now().Add(-2 * dt)
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(1).Return(nil)
+
+	if !lp.pvScalePhases(1999, minA, maxA) || lp.phases != 1 {
+		t.Error("missing switch below 1p threshold")
+	}
+
+	// dwell time blocks switching back
+	lp.PhaseSwitch.Dwell = 10 * dt
+	lp.measuredPhases = 1
+	lp.phaseTimer = clock.Now().Add(-dt)
+
+	if lp.pvScalePhases(3*Voltage*maxA, minA, maxA) || lp.phases != 1 || !lp.phaseTimer.IsZero() {
+		t.Error("unexpected switch during dwell time")
+	}
+
+	// charge pause before and after switching
+	lp = newLoadPoint(1, PhaseSwitchConfig{Pause: dt})
+	lp.phaseTimer = clock.Now().Add(-dt)
+
+	if !lp.pvScalePhases(3*Voltage*maxA, minA, maxA) || lp.phases != 1 || lp.phasePending != 3 {
+		t.Error("missing charge pause before switching")
+	}
+
+	clock.Add(dt / 2)
+	if !lp.pvScalePhases(3*Voltage*maxA, minA, maxA) || lp.phases != 1 {
+		t.Error("unexpected switch during charge pause")
+	}
+
+	clock.Add(dt / 2)
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(3).Return(nil)
+
+	if !lp.pvScalePhases(3*Voltage*maxA, minA, maxA) || lp.phases != 3 {
+		t.Error("missing switch after charge pause")
+	}
+
+	clock.Add(dt / 2)
+	if !lp.pvScalePhases(3*Voltage*maxA, minA, maxA) {
+		t.Error("missing charge pause after switching")
+	}
+
+	clock.Add(dt / 2)
+	if lp.pvScalePhases(3*Voltage*maxA, minA, maxA) || !lp.phasePause.IsZero() {
+		t.Error("charge pause not released")
+	}
+}
+
 func TestScalePhasesIfAvailable(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -427,3 +528,41 @@ func TestScalePhasesIfAvailable(t *testing.T) {
 		ctrl.Finish()
 	}
 }
+
+func TestVehiclePhasesDetected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Phases().Return(0).AnyTimes()
+	vehicle.EXPECT().Title().Return("car").AnyTimes()
+
+	lp := &LoadPoint{
+		log:     util.NewLogger("foo"),
+		charger: &struct{ api.Charger }{},
+		chargeMeter: &struct {
+			api.Meter
+			*circuitMeter
+		}{nil, &circuitMeter{currents: phaseCurrents{16, 0, 0}}},
+		vehicle: vehicle,
+		phases:  3,
+		status:  api.StatusC,
+	}
+
+	lp.updateChargeCurrents()
+	if phs := lp.activePhases(); phs != 1 {
+		t.Errorf("expected active %d, got %d", 1, phs)
+	}
+
+	// detected phases survive reconnect
+	lp.resetMeasuredPhases()
+	if phs := lp.activePhases(); phs != 1 {
+		t.Errorf("expected active %d, got %d", 1, phs)
+	}
+
+	// other vehicle
+	lp.vehicle = mock.NewMockVehicle(ctrl)
+	lp.vehicle.(*mock.MockVehicle).EXPECT().Phases().Return(0).AnyTimes()
+	if phs := lp.activePhases(); phs != unknownPhases {
+		t.Errorf("expected active %d, got %d", unknownPhases, phs)
+	}
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"golang.org/x/exp/slices"
+)
+
+// PlanConfig defines a repeating weekday target charge plan
+type PlanConfig = loadpoint.Schedule
+
+// planLookahead is the number of days searched for the next plan occurrence
+const planLookahead = 14
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// weekday parses abbreviated or full english weekday names
+func weekday(day string) (time.Weekday, bool) {
+	day = strings.ToLower(day)
+	res, ok := weekdays[day[:min(3, len(day))]]
+	return res, ok
+}
+
+// validatePlans checks the plans' weekdays and times
+func validatePlans(plans []PlanConfig) error {
+	for i, p := range plans {
+		if _, err := time.Parse("15:04", p.Time); err != nil {
+			return fmt.Errorf("plan %d: invalid time: %s", i+1, p.Time)
+		}
+
+		if p.SoC < 0 || p.SoC > 100 {
+			return fmt.Errorf("plan %d: invalid soc: %d", i+1, p.SoC)
+		}
+
+		for _, d := range p.Days {
+			if _, ok := weekday(d); !ok {
+				return fmt.Errorf("plan %d: invalid day: %s", i+1, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+// planMatches returns true if the plan applies to the weekday
+func planMatches(p PlanConfig, day time.Weekday) bool {
+	if len(p.Days) == 0 {
+		return true
+	}
+
+	for _, d := range p.Days {
+		if wd, ok := weekday(d); ok && wd == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextPlan returns the next plan target time after now and its soc or zero time if no plan applies
+func (lp *LoadPoint) nextPlan(now time.Time) (time.Time, int) {
+	var res time.Time
+	var soc int
+
+	for _, p := range lp.Plans {
+		tod, err := time.Parse("15:04", p.Time)
+		if err != nil {
+			continue
+		}
+
+		for i := 0; i < planLookahead; i++ {
+			day := now.AddDate(0, 0, i)
+			ts := time.Date(day.Year(), day.Month(), day.Day(), tod.Hour(), tod.Minute(), 0, 0, now.Location())
+
+			if !ts.After(now) || !planMatches(p, ts.Weekday()) || p.SkipHolidays && lp.holidays.IsHoliday(ts) {
+				continue
+			}
+
+			if res.IsZero() || ts.Before(res) {
+				res, soc = ts, p.SoC
+			}
+
+			break
+		}
+	}
+
+	return res, soc
+}
+
+// applyPlans sets the next plan occurrence as target charge unless a target has been set manually.
+// The user's target soc is restored once the plan's target charge is removed.
+func (lp *LoadPoint) applyPlans() {
+	if !lp.connected() {
+		return
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if len(lp.Plans) == 0 && lp.planTime.IsZero() {
+		return
+	}
+
+	// manual target takes precedence
+	if !lp.socTimer.Time.IsZero() && !lp.socTimer.Time.Equal(lp.planTime) {
+		return
+	}
+
+	// target charge removed, e.g. target reached
+	if !lp.planTime.IsZero() && lp.socTimer.Time.IsZero() {
+		lp.planConsumed = lp.planTime
+		lp.planTime = time.Time{}
+		lp.restorePlanUserSoC()
+	}
+
+	// the consumed occurrence is not applied again, the next one after its time
+	if len(lp.Plans) == 0 || lp.clock.Now().Before(lp.planConsumed) {
+		return
+	}
+
+	ts, soc := lp.nextPlan(lp.clock.Now())
+	if ts.Equal(lp.socTimer.Time) {
+		return
+	}
+
+	lp.log.DEBUG.Printf("plan: target charge %d%% @ %v", soc, ts.Round(time.Minute))
+
+	lp.planTime = ts
+	lp.socTimer.Set(ts)
+
+	if ts.IsZero() {
+		lp.restorePlanUserSoC()
+		return
+	}
+
+	if lp.planUserSoC == 0 {
+		lp.planUserSoC = lp.SoC.target
+	}
+	lp.setTargetSoC(soc)
+}
+
+// restorePlanUserSoC restores the user target soc replaced by the plan (no mutex)
+func (lp *LoadPoint) restorePlanUserSoC() {
+	if lp.planUserSoC > 0 {
+		lp.setTargetSoC(lp.planUserSoC)
+		lp.planUserSoC = 0
+	}
+}
+
+// resetPlan removes the plan's target charge and restores the user target soc
+func (lp *LoadPoint) resetPlan() {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if !lp.planTime.IsZero() && lp.socTimer.Time.Equal(lp.planTime) {
+		lp.socTimer.Set(time.Time{})
+	}
+
+	lp.planTime = time.Time{}
+	lp.restorePlanUserSoC()
+}
+
+// restoreSchedules replaces the configured plans by the schedules persisted under the settings key
+func (lp *LoadPoint) restoreSchedules(key string) {
+	lp.schedulesKey = key
+
+	var res []PlanConfig
+	if err := settings.Json(key, &res); err != nil {
+		if !errors.Is(err, settings.ErrNotFound) {
+			lp.log.ERROR.Printf("schedules: %v", err)
+		}
+		return
+	}
+
+	if err := validatePlans(res); err != nil {
+		lp.log.ERROR.Printf("schedules: %v", err)
+		return
+	}
+
+	lp.Plans = res
+}
+
+// GetSchedules returns the recurring weekly target charges
+func (lp *LoadPoint) GetSchedules() []loadpoint.Schedule {
+	lp.Lock()
+	defer lp.Unlock()
+
+	return slices.Clone(lp.Plans)
+}
+
+// SetSchedules replaces and persists the recurring weekly target charges
+func (lp *LoadPoint) SetSchedules(schedules []loadpoint.Schedule) error {
+	if err := validatePlans(schedules); err != nil {
+		return err
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Printf("set schedules: %d", len(schedules))
+	lp.Plans = slices.Clone(schedules)
+
+	if lp.schedulesKey != "" {
+		if err := settings.SetJson(lp.schedulesKey, lp.Plans); err != nil {
+			return err
+		}
+	}
+
+	// remove target charge of replaced plan, the next cycle applies the new schedules
+	if !lp.planTime.IsZero() && lp.socTimer.Time.Equal(lp.planTime) {
+		lp.planTime = time.Time{}
+		lp.socTimer.Set(time.Time{})
+		lp.restorePlanUserSoC()
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/holiday"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextPlan(t *testing.T) {
+	holidays, err := holiday.New("de")
+	require.NoError(t, err)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.holidays = holidays
+	lp.Plans = []PlanConfig{
+		{Days: []string{"mon", "tue", "wed", "thu", "friday"}, Time: "07:00", SoC: 80, SkipHolidays: true},
+		{Days: []string{"sat"}, Time: "10:00", SoC: 60},
+	}
+	require.NoError(t, validatePlans(lp.Plans))
+
+	date := func(s string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		return t
+	}
+
+	tc := []struct {
+		now, ts string
+		soc     int
+	}{
+		{"2022-10-03 06:00", "2022-10-04 07:00", 80}, // monday is german unity day
+		{"2022-10-04 06:00", "2022-10-04 07:00", 80},
+		{"2022-10-04 08:00", "2022-10-05 07:00", 80},
+		{"2022-10-07 08:00", "2022-10-08 10:00", 60},
+		{"2022-10-08 11:00", "2022-10-10 07:00", 80},
+	}
+
+	for _, tc := range tc {
+		ts, soc := lp.nextPlan(date(tc.now))
+		assert.Equal(t, date(tc.ts), ts, tc.now)
+		assert.Equal(t, tc.soc, soc, tc.now)
+	}
+
+	assert.Error(t, validatePlans([]PlanConfig{{Days: []string{"xyz"}, Time: "07:00"}}))
+	assert.Error(t, validatePlans([]PlanConfig{{Time: "7"}}))
+}
+
+func TestSetSchedules(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.Plans = []PlanConfig{{Time: "07:00", SoC: 80}}
+
+	// target charge from plan
+	ts := time.Now().Add(time.Hour)
+	lp.planTime = ts
+	lp.socTimer.Set(ts)
+
+	assert.Error(t, lp.SetSchedules([]loadpoint.Schedule{{Time: "7"}}))
+	assert.Equal(t, ts, lp.socTimer.Time)
+
+	schedules := []loadpoint.Schedule{{Days: []string{"sat", "sun"}, Time: "10:00", SoC: 60}}
+	require.NoError(t, lp.SetSchedules(schedules))
+	assert.Equal(t, schedules, lp.GetSchedules())
+	assert.True(t, lp.socTimer.Time.IsZero())
+
+	// manual target charge remains
+	manual := time.Now().Add(2 * time.Hour)
+	lp.socTimer.Set(manual)
+	require.NoError(t, lp.SetSchedules(nil))
+	assert.Equal(t, manual, lp.socTimer.Time)
+}
+
+func TestApplyPlans(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 10, 4, 5, 0, 0, 0, time.Local))
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.status = api.StatusB
+	lp.Plans = []PlanConfig{{Time: "07:00", SoC: 80}}
+	lp.setTargetSoC(60)
+
+	target := time.Date(2022, 10, 4, 7, 0, 0, 0, time.Local)
+
+	lp.applyPlans()
+	assert.Equal(t, target, lp.socTimer.Time)
+	assert.Equal(t, 80, lp.SoC.target)
+
+	// target reached, user target restored and occurrence not applied again
+	clck.Add(time.Hour)
+	lp.socTimer.Reset()
+	lp.applyPlans()
+	assert.True(t, lp.socTimer.Time.IsZero())
+	assert.Equal(t, 60, lp.SoC.target)
+
+	lp.applyPlans()
+	assert.True(t, lp.socTimer.Time.IsZero())
+
+	// next occurrence after the consumed one
+	clck.Add(time.Hour)
+	lp.applyPlans()
+	assert.Equal(t, target.AddDate(0, 0, 1), lp.socTimer.Time)
+	assert.Equal(t, 80, lp.SoC.target)
+
+	// disconnect restores user target
+	lp.resetPlan()
+	assert.True(t, lp.socTimer.Time.IsZero())
+	assert.Equal(t, 60, lp.SoC.target)
+}
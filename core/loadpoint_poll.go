@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// pollModes are the valid vehicle polling modes
+var pollModes = []string{pollCharging, pollConnected, pollAlways}
+
+// validatePoll normalizes the poll mode and validates the schedule. Invalid modes are reported and cleared to use the default mode.
+func validatePoll(log *util.Logger, name string, poll *api.PollConfig) error {
+	switch poll.Mode = strings.ToLower(poll.Mode); poll.Mode {
+	case "", pollCharging:
+	case pollConnected, pollAlways:
+		log.WARN.Printf("%s: poll mode '%s' may deplete your battery or lead to API misuse. USE AT YOUR OWN RISK.", name, poll.Mode)
+	default:
+		log.WARN.Printf("%s: invalid poll mode: %s", name, poll.Mode)
+		poll.Mode = ""
+	}
+
+	for _, window := range poll.Schedule {
+		if _, _, err := parseTimeWindow(window); err != nil {
+			return fmt.Errorf("%s: poll schedule: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// pollConfig returns the vehicle specific polling configuration falling back to the loadpoint's.
+// Invalid vehicle poll modes are ignored.
+func (lp *LoadPoint) pollConfig() api.PollConfig {
+	res := lp.SoC.Poll
+
+	if v, ok := lp.vehicle.(api.Poller); ok {
+		vc := v.Poll()
+
+		if mode := strings.ToLower(vc.Mode); slices.Contains(pollModes, mode) {
+			res.Mode = mode
+		}
+		if vc.Interval > 0 {
+			res.Interval = vc.Interval
+		}
+		if len(vc.Schedule) > 0 {
+			res.Schedule = vc.Schedule
+		}
+	}
+
+	return res
+}
+
+// pollScheduled returns true if no schedule is defined or the time is inside one of the HH:MM-HH:MM windows
+func pollScheduled(schedule []string, now time.Time) bool {
+	return len(schedule) == 0 || inTimeWindows(schedule, now)
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollScheduled(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2022, 10, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	tc := []struct {
+		schedule []string
+		now      time.Time
+		res      bool
+	}{
+		{nil, at(3, 0), true},
+		{[]string{"07:00-22:00"}, at(6, 59), false},
+		{[]string{"07:00-22:00"}, at(7, 0), true},
+		{[]string{"07:00-22:00"}, at(22, 0), false},
+		{[]string{"22:00-06:00"}, at(23, 30), true},
+		{[]string{"22:00-06:00"}, at(5, 0), true},
+		{[]string{"22:00-06:00"}, at(12, 0), false},
+		{[]string{"06:00-08:00", "18:00-20:00"}, at(19, 0), true},
+		{[]string{"invalid"}, at(12, 0), false},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.res, pollScheduled(tc.schedule, tc.now), "%v %v", tc.schedule, tc.now)
+	}
+}
+
+func TestCheapWindows(t *testing.T) {
+	_, _, err := parseTimeWindow("22:00-6")
+	assert.ErrorContains(t, err, "invalid time window")
+
+	start, end, err := parseTimeWindow("22:00 - 06:00")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{22 * 60, 6 * 60}, []int{start, end})
+
+	assert.False(t, inTimeWindows(nil, time.Now()), "no windows")
+}
+
+func TestPollConfigValidation(t *testing.T) {
+	// invalid mode is not rejected, config fails at the missing charger
+	_, err := NewLoadPointFromConfig(util.NewLogger("foo"), nil, map[string]interface{}{
+		"soc": map[string]interface{}{"poll": map[string]interface{}{"mode": "sometimes"}},
+	})
+	assert.ErrorContains(t, err, "missing charger")
+
+	_, err = NewLoadPointFromConfig(util.NewLogger("foo"), nil, map[string]interface{}{
+		"soc": map[string]interface{}{"poll": map[string]interface{}{"schedule": []string{"07:00-22"}}},
+	})
+	assert.ErrorContains(t, err, "invalid time window")
+}
+
+type pollVehicle struct {
+	api.Vehicle
+	poll api.PollConfig
+}
+
+func (v *pollVehicle) Poll() api.PollConfig {
+	return v.poll
+}
+
+func TestVehiclePollConfig(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.SoC.Poll = api.PollConfig{Mode: pollCharging, Interval: time.Hour}
+
+	v := &pollVehicle{poll: api.PollConfig{Mode: "sometimes"}}
+	lp.vehicle = v
+
+	// invalid vehicle mode is ignored
+	assert.Equal(t, lp.SoC.Poll, lp.pollConfig())
+	assert.NoError(t, validatePoll(lp.log, "vehicle", &v.poll))
+	assert.Equal(t, "", v.poll.Mode)
+
+	v.poll = api.PollConfig{Mode: "Always", Schedule: []string{"07:00-22:00"}}
+	assert.Equal(t, api.PollConfig{Mode: pollAlways, Interval: time.Hour, Schedule: v.poll.Schedule}, lp.pollConfig())
+
+	v.poll.Schedule = []string{"7-22"}
+	assert.ErrorContains(t, validatePoll(lp.log, "vehicle", &v.poll), "invalid time window")
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type powerCharger struct {
+	*mock.MockCharger
+	power float64
+}
+
+func (c *powerCharger) MaxPower(power float64) error {
+	c.power = power
+	return nil
+}
+
+func TestWritePower(t *testing.T) {
+	Voltage = 230 // V
+
+	ctrl := gomock.NewController(t)
+	charger := &powerCharger{MockCharger: mock.NewMockCharger(ctrl)}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.charger = charger
+	lp.phases = 3
+
+	// current is not truncated for power setpoints
+	current, err := lp.writeCurrent(10.5)
+	require.NoError(t, err)
+	assert.Equal(t, 10.5, current)
+	assert.Equal(t, 10.5*3*230, charger.power)
+
+	lp.phases = 1
+	_, err = lp.writeCurrent(6)
+	require.NoError(t, err)
+	assert.Equal(t, 6.0*230, charger.power)
+}
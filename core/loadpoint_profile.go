@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// vehicleProfileKey is the settings key of the vehicle's learned profile
+func vehicleProfileKey(vehicle api.Vehicle) string {
+	return "vehicle." + vehicle.Title() + ".profile"
+}
+
+// loadVehicleProfile restores the learned profile of the active vehicle. Must be called with lock held.
+func (lp *LoadPoint) loadVehicleProfile() {
+	lp.vehicleProfile = new(soc.Profile)
+
+	if err := settings.Json(vehicleProfileKey(lp.vehicle), lp.vehicleProfile); err != nil && !errors.Is(err, settings.ErrNotFound) {
+		lp.log.ERROR.Printf("vehicle profile: %v", err)
+	}
+
+	lp.applyVehicleProfile()
+	lp.publish(state.VehicleConsumption, lp.vehicleProfile.Consumption)
+}
+
+// applyVehicleProfile replaces the configured capacity by the learned capacity
+func (lp *LoadPoint) applyVehicleProfile() {
+	if p := lp.vehicleProfile; p != nil && p.Capacity > 0 && lp.socEstimator != nil {
+		lp.socEstimator.SetCapacity(p.Capacity)
+	}
+}
+
+// saveVehicleProfile persists the learned profile of the active vehicle
+func (lp *LoadPoint) saveVehicleProfile() {
+	if err := settings.SetJson(vehicleProfileKey(lp.vehicle), lp.vehicleProfile); err != nil {
+		lp.log.ERROR.Printf("vehicle profile: %v", err)
+	}
+}
+
+// vehicleCapacity returns the learned or configured usable capacity in kWh
+func (lp *LoadPoint) vehicleCapacity() float64 {
+	if p := lp.vehicleProfile; p != nil && p.Capacity > 0 {
+		return p.Capacity
+	}
+	return lp.vehicle.Capacity()
+}
+
+// learnArrival learns the consumption since last departure once soc and odometer are known after connecting
+func (lp *LoadPoint) learnArrival() {
+	if !lp.arrivalPending || lp.vehicleProfile == nil || lp.odometer == 0 || lp.vehicleSoc == 0 {
+		return
+	}
+	lp.arrivalPending = false
+
+	if lp.vehicleProfile.LearnConsumption(lp.vehicleSoc, lp.odometer, lp.vehicleCapacity()) {
+		lp.log.DEBUG.Printf("vehicle consumption learned: %.1fkWh/100km", lp.vehicleProfile.Consumption)
+		lp.publish(state.VehicleConsumption, lp.vehicleProfile.Consumption)
+		lp.saveVehicleProfile()
+	}
+}
+
+// learnDeparture learns the capacity from the charging session and records soc and odometer at departure
+func (lp *LoadPoint) learnDeparture() {
+	lp.arrivalPending = false
+
+	if lp.vehicle == nil || lp.vehicleProfile == nil {
+		return
+	}
+
+	if se := lp.socEstimator; se != nil {
+		if capacity, ok := se.LearnedCapacity(); ok {
+			lp.vehicleProfile.LearnCapacity(capacity)
+			lp.log.DEBUG.Printf("vehicle capacity learned: %.1fkWh", lp.vehicleProfile.Capacity)
+		}
+	}
+
+	if lp.odometer > 0 && lp.vehicleSoc > 0 {
+		lp.vehicleProfile.Depart(lp.vehicleSoc, lp.odometer)
+	}
+
+	lp.saveVehicleProfile()
+}
+
+// estimatedRange estimates the vehicle range from the learned consumption
+func (lp *LoadPoint) estimatedRange() (int64, bool) {
+	if lp.vehicleProfile == nil {
+		return 0, false
+	}
+	return lp.vehicleProfile.Range(lp.vehicleSoc, lp.vehicleCapacity())
+}
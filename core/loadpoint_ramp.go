@@ -0,0 +1,109 @@
+package core
+
+import (
+	"math"
+	"time"
+)
+
+// RampConfig defines the maximum rate of charge current changes and the charger's switching limits
+type RampConfig struct {
+	Up     float64       `mapstructure:"up"`     // max current increase in A/min, 0 for immediate changes
+	Down   float64       `mapstructure:"down"`   // max current decrease in A/min, 0 for immediate changes
+	MinOn  time.Duration `mapstructure:"minOn"`  // min duration the charger stays enabled
+	MinOff time.Duration `mapstructure:"minOff"` // min duration the charger stays disabled
+	Cycles int           `mapstructure:"cycles"` // max number of charger enables per hour, 0 for unlimited
+}
+
+// rampCurrent limits the change of the charge current to the configured ramp rates.
+// The elapsed time accumulates until the charge current changes which allows chargers
+// without milliamp support to follow the ramp in whole amps.
+func (lp *LoadPoint) rampCurrent(current float64) float64 {
+	if lp.Ramp.Up <= 0 && lp.Ramp.Down <= 0 {
+		return current
+	}
+
+	minCurrent := lp.GetMinCurrent()
+
+	// charging starts at min current
+	if !lp.enabled {
+		lp.rampUpdated = lp.clock.Now()
+		if lp.Ramp.Up > 0 && current > minCurrent {
+			return minCurrent
+		}
+		return current
+	}
+
+	// ramp down to min current before disabling
+	target := current
+	if target < minCurrent && lp.Ramp.Down > 0 {
+		target = minCurrent
+	}
+
+	diff := target - lp.chargeCurrent
+
+	var rate float64
+	switch {
+	case diff > 0:
+		rate = lp.Ramp.Up
+	case diff < 0:
+		rate = lp.Ramp.Down
+	}
+
+	if rate <= 0 {
+		lp.rampUpdated = lp.clock.Now()
+		return current
+	}
+
+	step := rate * lp.clock.Since(lp.rampUpdated).Minutes()
+	if step >= math.Abs(diff) {
+		return target
+	}
+
+	res := lp.chargeCurrent + math.Copysign(step, diff)
+	lp.log.DEBUG.Printf("ramp: %.3gA towards %.3gA", res, current)
+
+	return res
+}
+
+// switchDelay returns the remaining duration before the charger may be enabled or disabled, 0 if not delayed.
+// Enabling is also delayed while the hourly number of cycles is exhausted which debounces
+// frequent switching, e.g. caused by passing clouds.
+func (lp *LoadPoint) switchDelay(enable bool) time.Duration {
+	since := lp.clock.Since(lp.guardUpdated)
+
+	remaining := lp.Ramp.MinOff - since
+	if !enable {
+		remaining = lp.Ramp.MinOn - since
+	}
+
+	if enable && lp.Ramp.Cycles > 0 {
+		lp.pruneSwitchCycles()
+
+		if len(lp.switchCycles) >= lp.Ramp.Cycles {
+			if r := time.Hour - lp.clock.Since(lp.switchCycles[0]); r > remaining {
+				remaining = r
+			}
+		}
+	}
+
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining.Truncate(time.Second)
+}
+
+// pruneSwitchCycles removes charger enables older than an hour
+func (lp *LoadPoint) pruneSwitchCycles() {
+	for len(lp.switchCycles) > 0 && lp.clock.Since(lp.switchCycles[0]) >= time.Hour {
+		lp.switchCycles = lp.switchCycles[1:]
+	}
+}
+
+// addSwitchCycle records a charger enable for switching debounce
+func (lp *LoadPoint) addSwitchCycle() {
+	if lp.Ramp.Cycles > 0 {
+		lp.pruneSwitchCycles()
+		lp.switchCycles = append(lp.switchCycles, lp.clock.Now())
+	}
+}
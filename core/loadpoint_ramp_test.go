@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRampCurrent(t *testing.T) {
+	clck := clock.NewMock()
+
+	lp := &LoadPoint{
+		log:        util.NewLogger("foo"),
+		clock:      clck,
+		MinCurrent: minA,
+		MaxCurrent: maxA,
+		Ramp:       RampConfig{Up: 6, Down: 12},
+	}
+
+	// set applies the ramped current like setLimit
+	set := func(current float64) float64 {
+		res := lp.rampCurrent(current)
+		if res != lp.chargeCurrent && res >= minA {
+			lp.chargeCurrent = res
+			lp.rampUpdated = clck.Now()
+		}
+		lp.enabled = res >= minA
+		return res
+	}
+
+	assert.Equal(t, float64(minA), set(maxA), "start at min current")
+
+	clck.Add(10 * time.Second)
+	assert.Equal(t, float64(minA+1), set(maxA), "ramp up")
+
+	clck.Add(2 * time.Minute)
+	assert.Equal(t, float64(maxA), set(maxA), "ramp up completed")
+
+	clck.Add(10 * time.Second)
+	assert.Equal(t, float64(maxA-2), set(minA), "ramp down")
+
+	clck.Add(time.Minute)
+	assert.Equal(t, float64(minA), set(0), "ramp down to min current before disabling")
+
+	clck.Add(10 * time.Second)
+	assert.Equal(t, 0.0, set(0), "disable at min current")
+
+	// immediate changes without ramp
+	lp.Ramp = RampConfig{Up: 6}
+	assert.Equal(t, float64(minA), set(maxA))
+	clck.Add(10 * time.Second)
+	assert.Equal(t, float64(minA+1), set(maxA))
+	assert.Equal(t, 0.0, set(0), "immediate disable")
+}
+
+func TestSwitchDelay(t *testing.T) {
+	clck := clock.NewMock()
+
+	lp := &LoadPoint{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+		Ramp:  RampConfig{MinOn: 5 * time.Minute, MinOff: 2 * time.Minute, Cycles: 2},
+	}
+
+	// switch records the charger switching like setLimit
+	switchTo := func(enable bool) {
+		lp.guardUpdated = clck.Now()
+		if enable {
+			lp.addSwitchCycle()
+		}
+	}
+
+	switchTo(true)
+	assert.Equal(t, 5*time.Minute, lp.switchDelay(false), "min on")
+
+	clck.Add(5 * time.Minute)
+	assert.Equal(t, time.Duration(0), lp.switchDelay(false))
+	switchTo(false)
+	assert.Equal(t, 2*time.Minute, lp.switchDelay(true), "min off")
+
+	clck.Add(2 * time.Minute)
+	assert.Equal(t, time.Duration(0), lp.switchDelay(true))
+	switchTo(true)
+
+	// cycles exhausted until the first enable is an hour old
+	clck.Add(5 * time.Minute)
+	switchTo(false)
+	clck.Add(2 * time.Minute)
+	assert.Equal(t, 46*time.Minute, lp.switchDelay(true), "cycles")
+
+	clck.Add(46 * time.Minute)
+	assert.Equal(t, time.Duration(0), lp.switchDelay(true))
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// remoteBudget is the external power budget of a single source
+type remoteBudget struct {
+	power   float64
+	updated time.Time // source heartbeat
+}
+
+// SetRemoteBudget sets the external power budget of the source in W, negative values remove the source's budget.
+// The lowest budget of all sources applies.
+func (lp *LoadPoint) SetRemoteBudget(source string, power float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Printf("remote budget: %.0fW (%s)", power, source)
+
+	lp.remoteSource = source
+	lp.remoteUpdated = lp.clock.Now()
+
+	power0, source0, ok0 := lp.effectiveRemoteBudget()
+
+	if power < 0 {
+		delete(lp.remoteBudgets, source)
+	} else {
+		if lp.remoteBudgets == nil {
+			lp.remoteBudgets = make(map[string]remoteBudget)
+		}
+		lp.remoteBudgets[source] = remoteBudget{power: power, updated: lp.clock.Now()}
+	}
+
+	if power, source, ok := lp.effectiveRemoteBudget(); ok != ok0 || power != power0 || source != source0 {
+		lp.publishRemoteBudget()
+		lp.requestUpdate()
+	}
+}
+
+// GetRemoteBudget returns the lowest external power budget in W or -1 if not set
+func (lp *LoadPoint) GetRemoteBudget() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+
+	power, _, ok := lp.effectiveRemoteBudget()
+	if !ok {
+		return -1
+	}
+	return power
+}
+
+// RemoteHeartbeat signals that the external controller is alive
+func (lp *LoadPoint) RemoteHeartbeat(source string) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.remoteSource = source
+	lp.remoteUpdated = lp.clock.Now()
+
+	if b, ok := lp.remoteBudgets[source]; ok {
+		b.updated = lp.remoteUpdated
+		lp.remoteBudgets[source] = b
+	}
+}
+
+// effectiveRemoteBudget returns the lowest budget and its source, ordered by source name for equal budgets (no mutex)
+func (lp *LoadPoint) effectiveRemoteBudget() (float64, string, bool) {
+	sources := make([]string, 0, len(lp.remoteBudgets))
+	for source := range lp.remoteBudgets {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var (
+		res    float64
+		source string
+		ok     bool
+	)
+
+	for _, s := range sources {
+		if b := lp.remoteBudgets[s]; !ok || b.power < res {
+			res, source, ok = b.power, s, true
+		}
+	}
+
+	return res, source, ok
+}
+
+// publishRemoteBudget publishes the effective external power budget and its source (no mutex)
+func (lp *LoadPoint) publishRemoteBudget() {
+	if power, source, ok := lp.effectiveRemoteBudget(); ok {
+		lp.publish(state.RemoteBudget, power)
+		lp.publish(state.RemoteBudgetSource, source)
+	} else {
+		lp.publish(state.RemoteBudget, nil)
+		lp.publish(state.RemoteBudgetSource, "")
+	}
+}
+
+// remoteWatchdog removes the budgets of sources and reverts the demand to local control if the heartbeat has timed out
+func (lp *LoadPoint) remoteWatchdog() {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.RemoteTimeout <= 0 {
+		return
+	}
+
+	var expired bool
+	for source, b := range lp.remoteBudgets {
+		if lp.clock.Since(b.updated) >= lp.RemoteTimeout {
+			lp.log.WARN.Printf("remote control: no heartbeat from %s for %v, removing budget", source, lp.RemoteTimeout)
+			delete(lp.remoteBudgets, source)
+			expired = true
+		}
+	}
+
+	if expired {
+		lp.publishRemoteBudget()
+	}
+
+	if lp.remoteUpdated.IsZero() || lp.clock.Since(lp.remoteUpdated) < lp.RemoteTimeout {
+		return
+	}
+
+	lp.log.WARN.Printf("remote control: no heartbeat from %s for %v, reverting to local control", lp.remoteSource, lp.RemoteTimeout)
+
+	lp.remoteUpdated = time.Time{}
+
+	if lp.remoteDemand != loadpoint.RemoteEnable {
+		lp.remoteDemand = loadpoint.RemoteEnable
+		lp.publish(state.RemoteDisabled, lp.remoteDemand)
+		lp.publish(state.RemoteDisabledSource, lp.remoteSource)
+	}
+}
+
+// remoteBudgetCurrent limits the charge current to the external power budget
+func (lp *LoadPoint) remoteBudgetCurrent(current float64) float64 {
+	budget := lp.GetRemoteBudget()
+	if budget < 0 {
+		return current
+	}
+
+	maxCurrent := powerToCurrent(budget, lp.activePhases())
+	if current <= maxCurrent {
+		return current
+	}
+
+	if maxCurrent < lp.GetMinCurrent() {
+		maxCurrent = 0
+	}
+
+	lp.log.DEBUG.Printf("remote budget: %.0fW limits charge current to %.3gA", budget, maxCurrent)
+
+	return maxCurrent
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteBudget(t *testing.T) {
+	clck := clock.NewMock()
+
+	lp := &LoadPoint{
+		log:           util.NewLogger("foo"),
+		clock:         clck,
+		MinCurrent:    minA,
+		MaxCurrent:    maxA,
+		phases:        3,
+		RemoteTimeout: time.Minute,
+	}
+
+	assert.Equal(t, float64(maxA), lp.remoteBudgetCurrent(maxA), "no budget")
+
+	lp.SetRemoteBudget("ems", 3*230*10)
+	assert.Equal(t, 10.0, lp.remoteBudgetCurrent(maxA), "budget limits current")
+	assert.Equal(t, float64(minA), lp.remoteBudgetCurrent(minA), "budget above current")
+
+	lp.SetRemoteBudget("ems", 1000)
+	assert.Equal(t, 0.0, lp.remoteBudgetCurrent(maxA), "budget below min current")
+
+	lp.remoteDemand = loadpoint.RemoteHardDisable
+
+	clck.Add(30 * time.Second)
+	lp.RemoteHeartbeat("ems")
+	clck.Add(30 * time.Second)
+	lp.remoteWatchdog()
+	assert.Equal(t, 1000.0, lp.GetRemoteBudget(), "heartbeat keeps budget")
+
+	clck.Add(time.Minute)
+	lp.remoteWatchdog()
+	assert.Equal(t, -1.0, lp.GetRemoteBudget(), "budget removed after timeout")
+	assert.Equal(t, loadpoint.RemoteEnable, lp.remoteDemand, "demand reset after timeout")
+}
+
+func TestRemoteBudgetSources(t *testing.T) {
+	clck := clock.NewMock()
+
+	lp := &LoadPoint{
+		log:           util.NewLogger("foo"),
+		clock:         clck,
+		RemoteTimeout: time.Minute,
+	}
+
+	lp.SetRemoteBudget("cluster", 3000)
+	lp.SetRemoteBudget("ems", 5000)
+	assert.Equal(t, 3000.0, lp.GetRemoteBudget(), "lowest budget applies")
+
+	lp.SetRemoteBudget("foo", -1)
+	assert.Equal(t, 3000.0, lp.GetRemoteBudget(), "removing unknown source keeps budgets")
+
+	lp.SetRemoteBudget("ems", -1)
+	assert.Equal(t, 3000.0, lp.GetRemoteBudget(), "removing source keeps other budgets")
+
+	lp.SetRemoteBudget("ems", 2000)
+	assert.Equal(t, 2000.0, lp.GetRemoteBudget(), "lowest budget applies")
+
+	clck.Add(30 * time.Second)
+	lp.RemoteHeartbeat("cluster")
+	clck.Add(30 * time.Second)
+	lp.remoteWatchdog()
+	assert.Equal(t, 3000.0, lp.GetRemoteBudget(), "expired source removed")
+
+	lp.SetRemoteBudget("cluster", -1)
+	assert.Equal(t, -1.0, lp.GetRemoteBudget(), "no budget")
+}
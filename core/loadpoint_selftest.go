@@ -0,0 +1,66 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/selftest"
+)
+
+// StartChargerSelfTest runs the charger interoperability self-test in the background.
+// The loadpoint suspends charger control while the test is running.
+func (lp *LoadPoint) StartChargerSelfTest() error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.selfTestRunning {
+		return errors.New("self-test already running")
+	}
+
+	if lp.Mode != api.ModeOff {
+		return errors.New("self-test requires mode off")
+	}
+
+	lp.selfTestRunning = true
+
+	conf := selftest.Config{
+		MinCurrent: lp.MinCurrent,
+		MaxCurrent: lp.MaxCurrent,
+		Phases:     lp.phases,
+	}
+
+	go lp.runChargerSelfTest(conf)
+
+	return nil
+}
+
+func (lp *LoadPoint) runChargerSelfTest(conf selftest.Config) {
+	lp.log.INFO.Println("charger self-test started")
+
+	res := selftest.Run(lp.charger, conf)
+
+	lp.log.INFO.Printf("charger self-test finished: passed %t", res.Passed())
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.selfTest = &res
+	lp.selfTestRunning = false
+
+	// charger is disabled after the test
+	lp.enabled = false
+}
+
+// GetChargerSelfTest returns the last self-test report and if the self-test is running
+func (lp *LoadPoint) GetChargerSelfTest() (*selftest.Report, bool) {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.selfTest, lp.selfTestRunning
+}
+
+// selfTestActive returns if charger control is suspended by the self-test
+func (lp *LoadPoint) selfTestActive() bool {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.selfTestRunning
+}
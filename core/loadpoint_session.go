@@ -28,6 +28,7 @@ func (lp *LoadPoint) startSession() {
 
 	if lp.session == nil {
 		lp.session = lp.db.Session(lp.chargeMeterTotal())
+		lp.session.TraceID = lp.traceID
 
 		if lp.vehicle != nil {
 			lp.session.Vehicle = lp.vehicle.Title()
@@ -39,13 +40,35 @@ func (lp *LoadPoint) startSession() {
 			}
 		}
 
+		if lp.guestSessionActive() {
+			lp.session.Identifier = guestIdentifier
+		}
+
+		if lp.authorized != nil {
+			lp.session.User = lp.authorized.user
+		}
+
+		if lp.session.User == "" {
+			lp.session.User = lp.sessionUser(lp.session)
+		}
+
 		// TODO remove
-		lp.log.DEBUG.Println("session started")
+		lp.tracef("session started")
 
 		lp.db.Persist(lp.session)
 	}
 }
 
+// sessionUser returns the user owning the session's RFID token or vehicle
+func (lp *LoadPoint) sessionUser(session *db.Session) string {
+	users, err := db.Users()
+	if err != nil {
+		lp.log.ERROR.Printf("users: %v", err)
+	}
+
+	return db.MatchUser(users, session.Identifier, session.Vehicle)
+}
+
 func (lp *LoadPoint) stopSession() {
 	// test guard
 	if lp.db == nil || lp.session == nil {
@@ -53,9 +76,11 @@ func (lp *LoadPoint) stopSession() {
 	}
 
 	lp.session.Stop(lp.getChargedEnergy(), lp.chargeMeterTotal())
+	lp.sessionCostOption(lp.session)
+	lp.sessionSolarOption(lp.session)
 
 	// TODO remove
-	lp.log.DEBUG.Println("session stopped")
+	lp.tracef("session stopped")
 
 	lp.db.Persist(lp.session)
 }
@@ -73,7 +98,7 @@ func (lp *LoadPoint) updateSession(opts ...sessionOption) {
 	}
 
 	// TODO remove
-	lp.log.DEBUG.Println("session updated")
+	lp.tracef("session updated")
 
 	lp.db.Persist(lp.session)
 }
@@ -0,0 +1,60 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// socSyncTargets resolves the target soc for evcc and the vehicle charge limit according to the sync policy
+func socSyncTargets(policy api.SocSync, target, limit int) (int, int) {
+	switch policy {
+	case api.SocSyncEvcc:
+		return target, target
+	case api.SocSyncVehicle:
+		return limit, limit
+	case api.SocSyncMin:
+		if limit < target {
+			return limit, limit
+		}
+	}
+
+	return target, limit
+}
+
+// vehicleSocSync returns the active vehicle's charge limit synchronization policy
+func (lp *LoadPoint) vehicleSocSync() api.SocSync {
+	if v, ok := lp.vehicle.(api.SocSyncer); ok {
+		return v.SocSync()
+	}
+	return api.SocSyncNone
+}
+
+// syncVehicleSocLimit synchronizes evcc's target soc and the vehicle's own charge limit
+func (lp *LoadPoint) syncVehicleSocLimit(limit int) {
+	policy := lp.vehicleSocSync()
+	if policy == api.SocSyncNone || limit <= 0 {
+		return
+	}
+
+	target := lp.GetTargetSoC()
+	newTarget, newLimit := socSyncTargets(policy, target, limit)
+
+	if newTarget != target {
+		lp.log.DEBUG.Printf("vehicle soc sync (%s): target soc %d%% -> %d%%", policy, target, newTarget)
+		lp.SetTargetSoC(newTarget)
+	}
+
+	// the vehicle may report the previous limit until the new limit has been applied
+	if newLimit != limit && newLimit != lp.socSyncLimit {
+		vs, ok := lp.vehicle.(api.SocLimitController)
+		if !ok {
+			lp.log.WARN.Printf("vehicle soc sync (%s): vehicle does not support setting charge limit", policy)
+			return
+		}
+
+		lp.log.DEBUG.Printf("vehicle soc sync (%s): vehicle limit %d%% -> %d%%", policy, limit, newLimit)
+		if err := vs.SetTargetSoC(newLimit); err != nil {
+			lp.log.ERROR.Printf("vehicle soc sync: %v", err)
+			return
+		}
+
+		lp.socSyncLimit = newLimit
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type socSyncVehicle struct {
+	api.Vehicle
+	written []int
+}
+
+func (v *socSyncVehicle) SocSync() api.SocSync {
+	return api.SocSyncEvcc
+}
+
+func (v *socSyncVehicle) SetTargetSoC(soc int) error {
+	v.written = append(v.written, soc)
+	return nil
+}
+
+func TestSocSyncTargets(t *testing.T) {
+	tc := []struct {
+		policy              api.SocSync
+		target, limit       int
+		expTarget, expLimit int
+	}{
+		{api.SocSyncNone, 80, 90, 80, 90},
+		{api.SocSyncEvcc, 80, 90, 80, 80},
+		{api.SocSyncVehicle, 80, 90, 90, 90},
+		{api.SocSyncMin, 80, 90, 80, 90},
+		{api.SocSyncMin, 90, 80, 80, 80},
+	}
+
+	for _, tc := range tc {
+		target, limit := socSyncTargets(tc.policy, tc.target, tc.limit)
+		assert.Equal(t, tc.expTarget, target, tc.policy)
+		assert.Equal(t, tc.expLimit, limit, tc.policy)
+	}
+}
+
+func TestSyncVehicleSocLimitOnChange(t *testing.T) {
+	vehicle := &socSyncVehicle{Vehicle: mock.NewMockVehicle(gomock.NewController(t))}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.vehicle = vehicle
+	lp.SoC.target = 80
+
+	// vehicle keeps reporting its previous limit
+	lp.syncVehicleSocLimit(90)
+	lp.syncVehicleSocLimit(90)
+	assert.Equal(t, []int{80}, vehicle.written)
+
+	lp.SoC.target = 70
+	lp.syncVehicleSocLimit(80)
+	lp.syncVehicleSocLimit(70)
+	assert.Equal(t, []int{80, 70}, vehicle.written)
+}
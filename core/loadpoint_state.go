@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/wrapper"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// restoreMaxAge is the max downtime for continuing the interrupted session
+const restoreMaxAge = 30 * time.Minute
+
+// loadpointState is the transient loadpoint state persisted across restarts
+type loadpointState struct {
+	Mode          api.ChargeMode `json:"mode"`
+	TargetSoC     int            `json:"targetSoC,omitempty"`
+	TargetEnergy  float64        `json:"targetEnergy,omitempty"`
+	TargetTime    time.Time      `json:"targetTime,omitempty"`    // manually set target time
+	Session       uint           `json:"session,omitempty"`       // open session id
+	ChargedEnergy float64        `json:"chargedEnergy,omitempty"` // Wh charged in the open session
+	Updated       time.Time      `json:"updated"`
+}
+
+// restoreState applies the transient state persisted under the settings key
+func (lp *LoadPoint) restoreState(key string) {
+	lp.stateKey = key
+
+	var res loadpointState
+	if err := settings.Json(key, &res); err != nil {
+		if !errors.Is(err, settings.ErrNotFound) {
+			lp.log.ERROR.Printf("state: %v", err)
+		}
+		return
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if _, err := api.ChargeModeString(res.Mode.String()); err == nil {
+		lp.Mode = res.Mode
+	}
+
+	if res.TargetSoC > 0 {
+		lp.setTargetSoC(res.TargetSoC)
+	}
+
+	if res.TargetEnergy > 0 {
+		lp.setTargetEnergy(res.TargetEnergy)
+	}
+
+	if res.TargetTime.After(lp.clock.Now()) {
+		lp.socTimer.Set(res.TargetTime)
+	}
+
+	if res.Session != 0 && lp.clock.Since(res.Updated) < restoreMaxAge {
+		lp.restored = &res
+	}
+
+	lp.log.DEBUG.Printf("restored state: %s mode", lp.Mode)
+}
+
+// restoreSession continues the persisted session if the vehicle is still connected at startup
+func (lp *LoadPoint) restoreSession() {
+	res := lp.restored
+	lp.restored = nil
+
+	// test guard
+	if res == nil || lp.db == nil {
+		return
+	}
+
+	session, err := lp.db.Restore(res.Session)
+	if err != nil {
+		lp.log.ERROR.Printf("restore session: %v", err)
+		return
+	}
+
+	lp.session = session
+	lp.setChargedEnergy(res.ChargedEnergy)
+
+	// charger-provided charge raters keep their own session energy
+	if cr, ok := lp.chargeRater.(*wrapper.ChargeRater); ok {
+		cr.SetChargedEnergy(res.ChargedEnergy / 1e3)
+	}
+
+	lp.log.INFO.Printf("continued session with %.1fkWh charged", res.ChargedEnergy/1e3)
+}
+
+// persistState stores the transient state for restoration after restart
+func (lp *LoadPoint) persistState() {
+	if lp.stateKey == "" {
+		return
+	}
+
+	res := loadpointState{
+		Mode:         lp.GetMode(),
+		TargetEnergy: lp.GetTargetEnergy(),
+		Updated:      lp.clock.Now(),
+	}
+
+	lp.Lock()
+	res.TargetSoC = lp.SoC.target
+	if lp.planUserSoC > 0 {
+		res.TargetSoC = lp.planUserSoC
+	}
+	if ts := lp.socTimer.Time; !ts.IsZero() && !ts.Equal(lp.planTime) {
+		res.TargetTime = ts
+	}
+	lp.Unlock()
+
+	if lp.session != nil && lp.session.ID != 0 {
+		res.Session = lp.session.ID
+		res.ChargedEnergy = lp.getChargedEnergy()
+	}
+
+	if err := settings.SetJson(lp.stateKey, res); err != nil {
+		lp.log.ERROR.Printf("state: %v", err)
+	}
+}
+
+// shutdown closes the open session and persists the transient state
+func (lp *LoadPoint) shutdown() {
+	lp.stopSession()
+	lp.persistState()
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreState(t *testing.T) {
+	const key = "test.lp1.state"
+
+	clck := clock.NewMock()
+	clck.Set(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.stateKey = key
+	lp.Mode = api.ModePV
+	lp.SoC.target = 80
+	lp.socTimer.Set(clck.Now().Add(time.Hour))
+	lp.session = &db.Session{ID: 7}
+	lp.chargedEnergy = 5000
+
+	lp.persistState()
+
+	restore := func() *LoadPoint {
+		lp := NewLoadPoint(util.NewLogger("foo"))
+		lp.clock = clck
+		lp.restoreState(key)
+		return lp
+	}
+
+	res := restore()
+	assert.Equal(t, api.ModePV, res.Mode)
+	assert.Equal(t, 80, res.SoC.target)
+	assert.Equal(t, clck.Now().Add(time.Hour), res.socTimer.Time)
+	require.NotNil(t, res.restored)
+	assert.Equal(t, uint(7), res.restored.Session)
+	assert.Equal(t, 5000.0, res.restored.ChargedEnergy)
+
+	// session is not continued after long downtime, expired target times are dropped
+	clck.Add(2 * time.Hour)
+
+	res = restore()
+	assert.Equal(t, api.ModePV, res.Mode)
+	assert.Nil(t, res.restored)
+	assert.True(t, res.socTimer.Time.IsZero())
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -63,7 +64,7 @@ func createChannels(t *testing.T) (chan util.Param, chan push.Event, chan *LoadP
 
 func attachChannels(lp *LoadPoint, uiChan chan util.Param, pushChan chan push.Event, lpChan chan *LoadPoint) {
 	lp.uiChan = uiChan
-	lp.pushChan = pushChan
+	lp.events = pushPublisher(lp, pushChan)
 	lp.lpChan = lpChan
 }
 
@@ -771,3 +772,23 @@ func TestMinSoC(t *testing.T) {
 		}
 	}
 }
+
+func TestRemainingTargetEnergy(t *testing.T) {
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.targetEnergy = 10 // kWh
+	lp.setChargedEnergy(4000)
+
+	lp.status = api.StatusC
+	lp.chargePower = 3000
+	lp.publishRemaining()
+
+	assert.Equal(t, 6000.0, lp.GetRemainingEnergy())
+	assert.Equal(t, 2*time.Hour, lp.GetRemainingDuration())
+
+	lp.status = api.StatusB
+	lp.setChargedEnergy(12000)
+	lp.publishRemaining()
+
+	assert.Equal(t, 0.0, lp.GetRemainingEnergy())
+	assert.Equal(t, time.Duration(-1), lp.GetRemainingDuration())
+}
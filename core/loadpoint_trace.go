@@ -0,0 +1,57 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// newTraceID returns a random trace id
+func newTraceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// startTrace assigns a trace id to the session of the connected vehicle
+func (lp *LoadPoint) startTrace() {
+	lp.traceID = newTraceID()
+	lp.decision = ""
+	lp.publish(state.TraceID, lp.traceID)
+}
+
+// stopTrace ends the trace after the vehicle disconnected
+func (lp *LoadPoint) stopTrace() {
+	lp.decide("vehicle disconnected")
+	lp.traceID = ""
+	lp.decision = ""
+	lp.publish(state.TraceID, lp.traceID)
+}
+
+// tracef logs the message tagged with the trace id
+func (lp *LoadPoint) tracef(format string, v ...any) {
+	if lp.traceID != "" {
+		format = "[" + lp.traceID + "] " + format
+	}
+	lp.log.DEBUG.Printf(format, v...)
+}
+
+// decide records the loadpoint decision in the session's decision log whenever it changes
+func (lp *LoadPoint) decide(format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	if lp.traceID == "" || msg == lp.decision {
+		return
+	}
+
+	lp.decision = msg
+	lp.tracef("decision: %s", msg)
+
+	// test guard
+	if lp.db != nil {
+		lp.db.Decision(lp.traceID, msg)
+	}
+}
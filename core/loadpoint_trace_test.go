@@ -0,0 +1,50 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/core/db"
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceDecisions(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { serverdb.Instance = nil })
+
+	require.NoError(t, serverdb.Instance.AutoMigrate(new(db.Decision)))
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.db, err = db.New("garage")
+	require.NoError(t, err)
+
+	// not connected
+	lp.decide("mode pv: charger disabled")
+
+	lp.startTrace()
+	trace := lp.traceID
+	assert.Len(t, trace, 16)
+
+	lp.decide("mode pv: charger enabled")
+	lp.decide("mode pv: charger enabled")
+	lp.decide("target soc reached: %d%%", 80)
+	lp.stopTrace()
+
+	assert.Empty(t, lp.traceID)
+
+	var res db.Decisions
+	require.NoError(t, serverdb.Instance.Where("trace_id = ?", trace).Order("id").Find(&res).Error)
+
+	var msgs []string
+	for _, d := range res {
+		assert.Equal(t, "garage", d.Loadpoint)
+		msgs = append(msgs, d.Message)
+	}
+
+	assert.Equal(t, []string{"mode pv: charger enabled", "target soc reached: 80%", "vehicle disconnected"}, msgs)
+}
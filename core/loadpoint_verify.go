@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+const (
+	verifyDelay     = 30 * time.Second // time for charger and vehicle to follow a new current limit
+	verifyTolerance = 2.0              // A, measured current above limit tolerated for vehicle and meter inaccuracies
+)
+
+// currentMismatch returns a description if the charger did not apply the current limit.
+// The charger's reported limit is preferred, otherwise measured phase currents exceeding the limit are considered.
+func (lp *LoadPoint) currentMismatch() (string, error) {
+	if cg, ok := lp.charger.(api.CurrentGetter); ok {
+		current, err := cg.GetMaxCurrent()
+		if err == nil {
+			if math.Abs(current-lp.chargeCurrent) >= 1 {
+				return fmt.Sprintf("reported %.3gA", current), nil
+			}
+			return "", nil
+		}
+
+		if !errors.Is(err, api.ErrNotAvailable) {
+			return "", err
+		}
+	}
+
+	if !lp.charging() || lp.chargeCurrents == nil {
+		return "", nil
+	}
+
+	var current float64
+	for _, i := range lp.chargeCurrents {
+		current = math.Max(current, i)
+	}
+
+	if current > lp.chargeCurrent+verifyTolerance {
+		return fmt.Sprintf("measured %.3gA", current), nil
+	}
+
+	return "", nil
+}
+
+// verifyCurrent checks that the charger applied the current limit and writes it again otherwise
+func (lp *LoadPoint) verifyCurrent() {
+	if !lp.enabled || lp.currentWritten.IsZero() || lp.clock.Since(lp.currentWritten) < verifyDelay {
+		return
+	}
+
+	mismatch, err := lp.currentMismatch()
+	if err != nil {
+		lp.log.ERROR.Printf("charge current: %v", err)
+		return
+	}
+
+	if ignored := mismatch != ""; ignored != lp.currentIgnored {
+		lp.currentIgnored = ignored
+		lp.publish(state.CurrentIgnored, ignored)
+
+		if !ignored {
+			lp.log.INFO.Printf("charger applied current %.3gA", lp.chargeCurrent)
+		}
+	}
+
+	if mismatch == "" {
+		return
+	}
+
+	lp.log.WARN.Printf("charger ignored current %.3gA (%s), retrying", lp.chargeCurrent, mismatch)
+
+	if _, err := lp.writeCurrent(lp.chargeCurrent); err != nil {
+		lp.log.ERROR.Printf("max charge current %.3gA: %v", lp.chargeCurrent, err)
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type verifyCharger struct {
+	api.Charger
+	current, written float64
+	writes           int
+}
+
+func (c *verifyCharger) MaxCurrentMillis(current float64) error {
+	c.written = current
+	c.writes++
+	return nil
+}
+
+func (c *verifyCharger) GetMaxCurrent() (float64, error) {
+	return c.current, nil
+}
+
+func TestVerifyCurrent(t *testing.T) {
+	clck := clock.NewMock()
+	charger := &verifyCharger{current: 16}
+
+	lp := &LoadPoint{
+		log:           util.NewLogger("foo"),
+		clock:         clck,
+		charger:       charger,
+		enabled:       true,
+		chargeCurrent: 10,
+	}
+
+	// wait for charger to apply the limit
+	lp.currentWritten = clck.Now()
+	lp.verifyCurrent()
+	assert.Equal(t, 0, charger.writes)
+
+	// retry after ignored limit
+	clck.Add(verifyDelay)
+	lp.verifyCurrent()
+	assert.True(t, lp.currentIgnored)
+	assert.Equal(t, 1, charger.writes)
+	assert.Equal(t, 10.0, charger.written)
+
+	// next retry after delay
+	lp.verifyCurrent()
+	assert.Equal(t, 1, charger.writes)
+
+	charger.current = 10
+	clck.Add(verifyDelay)
+	lp.verifyCurrent()
+	assert.False(t, lp.currentIgnored)
+	assert.Equal(t, 1, charger.writes)
+}
+
+func TestCurrentMismatchMeasured(t *testing.T) {
+	lp := &LoadPoint{
+		log:            util.NewLogger("foo"),
+		charger:        &struct{ api.Charger }{},
+		status:         api.StatusC,
+		chargeCurrent:  10,
+		chargeCurrents: []float64{11, 11, 0},
+	}
+
+	res, err := lp.currentMismatch()
+	assert.NoError(t, err)
+	assert.Empty(t, res, "within tolerance")
+
+	lp.chargeCurrents = []float64{16, 16, 16}
+	res, err = lp.currentMismatch()
+	assert.NoError(t, err)
+	assert.Equal(t, "measured 16A", res)
+
+	// vehicle drawing less than limit
+	lp.chargeCurrents = []float64{6, 6, 6}
+	res, _ = lp.currentMismatch()
+	assert.Empty(t, res)
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// writeFaultRetry is the interval for resuming normal operation after repeated charger write failures
+const writeFaultRetry = 15 * time.Minute
+
+// WatchdogConfig defines the failsafe behaviour when charger or charge meter communication is lost
+type WatchdogConfig struct {
+	Timeout time.Duration `mapstructure:"timeout"` // apply failsafe after communication is lost for this long
+	Current float64       `mapstructure:"current"` // failsafe current, pause charging if zero
+	Retries int           `mapstructure:"retries"` // raise a write fault after this many consecutive failed charger writes
+}
+
+// deviceWatchdog applies the failsafe current while charger or charge meter communication is lost.
+// It returns true while the failsafe is active.
+func (lp *LoadPoint) deviceWatchdog() bool {
+	if lp.Watchdog.Timeout == 0 {
+		return false
+	}
+
+	now := lp.clock.Now()
+
+	// start watching on first update
+	for _, ts := range []*time.Time{&lp.chargerUpdated, &lp.meterUpdated} {
+		if ts.IsZero() {
+			*ts = now
+		}
+	}
+
+	var device string
+	switch {
+	case now.Sub(lp.chargerUpdated) > lp.Watchdog.Timeout:
+		device = "charger"
+	case now.Sub(lp.meterUpdated) > lp.Watchdog.Timeout:
+		device = "meter"
+	}
+
+	if device == "" {
+		if lp.apiError != "" {
+			lp.log.INFO.Println("watchdog: communication restored")
+			lp.setApiError("")
+		}
+		return false
+	}
+
+	if lp.apiError == "" {
+		msg := fmt.Sprintf("%s communication lost", device)
+		lp.log.WARN.Printf("watchdog: %s, failsafe current %.3gA", msg, lp.Watchdog.Current)
+		lp.setApiError(msg)
+		lp.pushEvent(event.DeviceError{Device: device, Error: msg})
+	}
+
+	if err := lp.setLimit(lp.Watchdog.Current, true); err != nil {
+		lp.log.ERROR.Printf("watchdog: %v", err)
+	}
+
+	return true
+}
+
+// writeWatchdog counts consecutive failed charger writes and raises a write fault once the retries are exhausted.
+// While faulted the failsafe current applies, normal operation is retried periodically.
+func (lp *LoadPoint) writeWatchdog(err error) {
+	if lp.Watchdog.Retries == 0 {
+		return
+	}
+
+	if err == nil {
+		switch {
+		case lp.fault != api.FaultWrite:
+			lp.writeErrors = 0
+		case lp.clock.Since(lp.writeFaulted) >= writeFaultRetry:
+			lp.log.INFO.Println("watchdog: retrying charger writes")
+			lp.setFault(api.FaultNone)
+		}
+		return
+	}
+
+	lp.writeErrors++
+	if lp.writeErrors < lp.Watchdog.Retries || lp.fault == api.FaultWrite {
+		return
+	}
+
+	lp.log.WARN.Printf("watchdog: %d consecutive charger write errors, failsafe current %.3gA", lp.writeErrors, lp.Watchdog.Current)
+	lp.writeFaulted = lp.clock.Now()
+	lp.setFault(api.FaultWrite)
+
+	if err := lp.setLimit(lp.Watchdog.Current, true); err != nil {
+		lp.log.ERROR.Printf("watchdog: %v", err)
+	}
+}
+
+// setApiError updates and publishes the communication error
+func (lp *LoadPoint) setApiError(msg string) {
+	lp.apiError = msg
+	lp.publish(state.ApiError, msg)
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+	clck := clock.NewMock()
+	pushChan := make(chan push.Event, 10)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.charger = charger
+	lp.events = pushPublisher(lp, pushChan)
+	lp.wakeUpTimer = NewTimer() // silence nil panics
+	lp.MinCurrent = minA
+	lp.MaxCurrent = maxA
+	lp.enabled = true
+	lp.chargeCurrent = maxA
+	lp.Watchdog = WatchdogConfig{Timeout: time.Minute, Current: minA}
+
+	assert.False(t, lp.deviceWatchdog(), "start watching")
+
+	clck.Add(45 * time.Second)
+	assert.False(t, lp.deviceWatchdog(), "within timeout")
+
+	lp.chargerUpdated = clck.Now()
+	clck.Add(30 * time.Second)
+
+	// meter lost, fall back to failsafe current
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	assert.True(t, lp.deviceWatchdog())
+	assert.Equal(t, "meter communication lost", lp.apiError)
+	assert.Equal(t, float64(minA), lp.chargeCurrent)
+	assert.Equal(t, "fault", (<-pushChan).Event)
+
+	// restored
+	lp.chargerUpdated = clck.Now()
+	lp.meterUpdated = clck.Now()
+	assert.False(t, lp.deviceWatchdog())
+	assert.Equal(t, "", lp.apiError)
+
+	// charger lost, pause charging
+	lp.Watchdog.Current = 0
+	clck.Add(2 * time.Minute)
+	charger.EXPECT().Enable(false).Return(nil)
+	assert.True(t, lp.deviceWatchdog())
+	assert.Equal(t, "charger communication lost", lp.apiError)
+	assert.False(t, lp.enabled)
+
+	// no repeated notification
+	assert.True(t, lp.deviceWatchdog())
+	assert.Len(t, pushChan, 1)
+
+	// disabled
+	lp.Watchdog.Timeout = 0
+	assert.False(t, lp.deviceWatchdog())
+}
+
+func TestWriteWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+	clck := clock.NewMock()
+	pushChan := make(chan push.Event, 10)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.charger = charger
+	lp.events = pushPublisher(lp, pushChan)
+	lp.wakeUpTimer = NewTimer() // silence nil panics
+	lp.MinCurrent = minA
+	lp.MaxCurrent = maxA
+	lp.enabled = true
+	lp.chargeCurrent = maxA
+	lp.Watchdog = WatchdogConfig{Retries: 3}
+
+	writeErr := errors.New("write failed")
+
+	// success resets the error budget
+	lp.writeWatchdog(writeErr)
+	lp.writeWatchdog(writeErr)
+	lp.writeWatchdog(nil)
+	assert.Equal(t, 0, lp.writeErrors)
+
+	lp.writeWatchdog(writeErr)
+	lp.writeWatchdog(writeErr)
+	assert.Equal(t, api.FaultNone, lp.fault)
+
+	// retries exhausted, stop charging
+	charger.EXPECT().Enable(false).Return(nil)
+	lp.writeWatchdog(writeErr)
+	assert.Equal(t, api.FaultWrite, lp.fault)
+	assert.False(t, lp.enabled)
+	assert.Equal(t, "fault", (<-pushChan).Event)
+
+	// limited to failsafe current while faulted
+	assert.Equal(t, 0.0, lp.monitorCurrent(maxA))
+
+	// fault is kept without repeated notification
+	lp.writeWatchdog(writeErr)
+	lp.writeWatchdog(nil)
+	lp.updateMonitor()
+	assert.Equal(t, api.FaultWrite, lp.fault)
+	assert.Len(t, pushChan, 0)
+
+	// resume normal operation after retry interval
+	clck.Add(writeFaultRetry)
+	lp.writeWatchdog(nil)
+	assert.Equal(t, api.FaultNone, lp.fault)
+
+	// single failure raises the fault again until a write succeeds
+	charger.EXPECT().Enable(false).Return(nil).AnyTimes()
+	lp.writeWatchdog(writeErr)
+	assert.Equal(t, api.FaultWrite, lp.fault)
+
+	clck.Add(writeFaultRetry)
+	lp.writeWatchdog(nil)
+	lp.writeWatchdog(nil)
+	assert.Equal(t, 0, lp.writeErrors)
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// device is the configured device class and name
+type device struct {
+	class, name string
+}
+
+// devices tracks the configured devices and their last successful request
+type devices struct {
+	mu      sync.Mutex
+	names   map[any]device
+	updated map[device]time.Time
+}
+
+var (
+	registry = &devices{
+		names:   make(map[any]device),
+		updated: make(map[device]time.Time),
+	}
+
+	durationMetric *prometheus.HistogramVec
+	errorMetric    *prometheus.CounterVec
+	stalenessDesc  = prometheus.NewDesc(
+		"evcc_device_staleness_seconds",
+		"Time since the last successful device request",
+		[]string{"class", "device"}, nil,
+	)
+)
+
+func init() {
+	labels := []string{"class", "device"}
+
+	durationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "evcc",
+		Subsystem: "device",
+		Name:      "request_duration_seconds",
+		Help:      "A histogram of device request durations",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, labels)
+
+	errorMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "evcc",
+		Subsystem: "device",
+		Name:      "errors_total",
+		Help:      "Total count of failed device requests",
+	}, labels)
+
+	prometheus.MustRegister(durationMetric, errorMetric, registry)
+}
+
+// Register associates a device with its configured class (meter, charger, vehicle) and name
+func Register(dev any, class, name string) {
+	if !comparable(dev) {
+		return
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.names[dev] = device{class, name}
+}
+
+// Observe records duration and result of a device request. Unregistered devices are ignored.
+func Observe(dev any, start time.Time, err error) {
+	if !comparable(dev) {
+		return
+	}
+
+	registry.mu.Lock()
+	d, ok := registry.names[dev]
+	if ok && err == nil {
+		registry.updated[d] = time.Now()
+	}
+	registry.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	durationMetric.WithLabelValues(d.class, d.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		errorMetric.WithLabelValues(d.class, d.name).Inc()
+	}
+}
+
+// comparable returns true if the device can be used as map key
+func comparable(dev any) bool {
+	return dev != nil && reflect.TypeOf(dev).Comparable()
+}
+
+// Describe implements the prometheus.Collector interface
+func (d *devices) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stalenessDesc
+}
+
+// Collect implements the prometheus.Collector interface
+func (d *devices) Collect(ch chan<- prometheus.Metric) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for dev, updated := range d.updated {
+		ch <- prometheus.MustNewConstMetric(stalenessDesc, prometheus.GaugeValue, time.Since(updated).Seconds(), dev.class, dev.name)
+	}
+}
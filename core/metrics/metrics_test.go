@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDevice struct {
+	name string
+}
+
+func TestObserve(t *testing.T) {
+	dev := &testDevice{"wallbox"}
+	Register(dev, "charger", "wallbox")
+
+	Observe(dev, time.Now(), nil)
+	Observe(dev, time.Now(), errors.New("timeout"))
+	Observe(&testDevice{"unknown"}, time.Now(), errors.New("ignored"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(errorMetric.WithLabelValues("charger", "wallbox")))
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "evcc_device_staleness_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(durationMetric, "evcc_device_request_duration_seconds"))
+}
@@ -0,0 +1,181 @@
+package planner
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Planner plans target charging using solar surplus forecast and grid tariff slots
+type Planner struct {
+	log   *util.Logger
+	clock clock.Clock
+	solar api.PowerForecaster // pv surplus after home consumption
+	grid  api.Rater           // grid price per kWh
+}
+
+// Plan is the result of planning a target charge
+type Plan struct {
+	Solar float64   `json:"solar"` // forecast solar energy in kWh until target time
+	Slots api.Rates `json:"slots"` // grid charging slots
+}
+
+// New creates a planner for the given solar surplus forecast and grid tariff, either may be nil.
+// The surplus forecast must already be reduced by the home consumption like forecast.Blended.
+func New(log *util.Logger, solar api.PowerForecaster, grid api.Rater) *Planner {
+	return &Planner{
+		log:   log,
+		clock: clock.New(),
+		solar: solar,
+		grid:  grid,
+	}
+}
+
+// Active returns true if grid charging is planned at the current time
+func (p Plan) Active(now time.Time) bool {
+	for _, slot := range p.Slots {
+		if !now.Before(slot.Start) && now.Before(slot.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start returns the start of the first planned grid slot or zero time if no grid charging is required
+func (p Plan) Start() time.Time {
+	var res time.Time
+	for _, slot := range p.Slots {
+		if res.IsZero() || slot.Start.Before(res) {
+			res = slot.Start
+		}
+	}
+	return res
+}
+
+// clip limits the slot to the interval between from and to
+func clip(slot api.Rate, from, to time.Time) (api.Rate, bool) {
+	if slot.Start.Before(from) {
+		slot.Start = from
+	}
+	if slot.End.After(to) {
+		slot.End = to
+	}
+	return slot, slot.End.After(slot.Start)
+}
+
+// solarEnergy returns the forecast surplus energy in kWh usable at charge power until target time
+func (t *Planner) solarEnergy(power float64, targetTime time.Time) float64 {
+	if t.solar == nil {
+		return 0
+	}
+
+	slots, err := t.solar.PowerForecast()
+	if err != nil {
+		t.log.ERROR.Printf("solar forecast: %v", err)
+		return 0
+	}
+
+	var res float64
+	now := t.clock.Now()
+	for _, slot := range slots {
+		start, end := slot.Start, slot.End
+		if start.Before(now) {
+			start = now
+		}
+		if end.After(targetTime) {
+			end = targetTime
+		}
+		if end.After(start) {
+			res += math.Max(0, math.Min(slot.Power, power)) * end.Sub(start).Hours() / 1e3
+		}
+	}
+
+	return res
+}
+
+// gridSlots returns the cheapest grid slots until target time for charging the given duration
+func (t *Planner) gridSlots(duration time.Duration, targetTime time.Time) api.Rates {
+	now := t.clock.Now()
+
+	var rates api.Rates
+	if t.grid != nil {
+		var err error
+		if rates, err = t.grid.Rates(); err != nil {
+			t.log.ERROR.Printf("grid tariff: %v", err)
+		}
+	}
+
+	var slots api.Rates
+	for _, slot := range rates {
+		if slot, ok := clip(slot, now, targetTime); ok {
+			slots = append(slots, slot)
+		}
+	}
+
+	// without tariff start as late as possible
+	if len(slots) == 0 {
+		start := targetTime.Add(-duration)
+		if start.Before(now) {
+			start = now
+		}
+		return api.Rates{{Start: start, End: targetTime}}
+	}
+
+	// cheapest slots first, later slots first for equal prices
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Price == slots[j].Price {
+			return slots[i].Start.After(slots[j].Start)
+		}
+		return slots[i].Price < slots[j].Price
+	})
+
+	var res api.Rates
+	for _, slot := range slots {
+		if duration <= 0 {
+			break
+		}
+
+		// use later part of partial slot
+		if d := slot.End.Sub(slot.Start); d > duration {
+			slot.Start = slot.End.Add(-duration)
+		}
+
+		duration -= slot.End.Sub(slot.Start)
+		res = append(res, slot)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Start.Before(res[j].Start)
+	})
+
+	return res
+}
+
+// Plan creates a plan for charging energy in kWh at power in W until target time.
+// Grid charging is deferred as long as the solar forecast covers the required energy.
+func (t *Planner) Plan(energy, power float64, targetTime time.Time) Plan {
+	var res Plan
+
+	if energy <= 0 || power <= 0 {
+		return res
+	}
+
+	res.Solar = t.solarEnergy(power, targetTime)
+
+	if res.Solar >= energy {
+		t.log.DEBUG.Printf("planner: solar forecast %.1fkWh covers %.1fkWh", res.Solar, energy)
+		return res
+	}
+
+	gridEnergy := energy - res.Solar
+	duration := time.Duration(gridEnergy / power * 1e3 * float64(time.Hour))
+	res.Slots = t.gridSlots(duration, targetTime)
+
+	t.log.DEBUG.Printf("planner: %.1fkWh solar, %.1fkWh grid in %d slots starting %v", res.Solar, gridEnergy, len(res.Slots), res.Start().Round(time.Minute))
+
+	return res
+}
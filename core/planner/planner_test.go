@@ -0,0 +1,71 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type rates api.Rates
+
+func (r rates) Rates() (api.Rates, error) {
+	return api.Rates(r), nil
+}
+
+// PowerForecast returns the rates as surplus power
+func (r rates) PowerForecast() (api.PowerSlots, error) {
+	var res api.PowerSlots
+	for _, rate := range r {
+		res = append(res, api.PowerSlot{Start: rate.Start, End: rate.End, Power: rate.Price})
+	}
+	return res, nil
+}
+
+func hourly(start time.Time, values ...float64) rates {
+	var res rates
+	for i, v := range values {
+		res = append(res, api.Rate{
+			Start: start.Add(time.Duration(i) * time.Hour),
+			End:   start.Add(time.Duration(i+1) * time.Hour),
+			Price: v,
+		})
+	}
+	return res
+}
+
+func TestPlan(t *testing.T) {
+	clck := clock.NewMock()
+	now := clck.Now()
+	target := now.Add(4 * time.Hour)
+
+	tc := []struct {
+		name         string
+		solar        api.PowerForecaster
+		grid         api.Rater
+		energy       float64
+		start        time.Time
+		active, done bool
+	}{
+		{"late start without tariff", nil, nil, 10, now.Add(2 * time.Hour), false, false},
+		{"cheapest slots", nil, hourly(now, 0.3, 0.1, 0.2, 0.4), 10, now.Add(time.Hour), false, false},
+		{"cheapest slot is now", nil, hourly(now, 0.1, 0.3, 0.2, 0.4), 10, now, true, false},
+		{"solar covers energy", hourly(now, 5000, 5000, 5000, 5000), hourly(now, 0.1, 0.3, 0.2, 0.4), 10, time.Time{}, false, true},
+		{"solar covers part", hourly(now, 0, 5000, 0, 0), hourly(now, 0.3, 0.3, 0.1, 0.4), 10, now.Add(2 * time.Hour), false, false},
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc.name)
+
+		p := New(util.NewLogger("foo"), tc.solar, tc.grid)
+		p.clock = clck
+
+		plan := p.Plan(tc.energy, 5000, target)
+		assert.Equal(t, tc.start, plan.Start(), tc.name)
+		assert.Equal(t, tc.active, plan.Active(now), tc.name)
+		assert.Equal(t, tc.done, len(plan.Slots) == 0, tc.name)
+	}
+}
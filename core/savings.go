@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/tariff"
 )
@@ -32,6 +33,7 @@ type Savings struct {
 	selfConsumptionCost            float64   // Running total of charged self-produced energy cost (e.g. EUR)
 	lastGridPrice, lastFeedInPrice float64   // Stores the last published grid price. Needed to detect price changes (Awattar, ..)
 	hasPublished                   bool      // Has initial publish happened?
+	prefix                         string    // Settings key prefix of additional sites
 }
 
 func NewSavings(tariffs tariff.Tariffs) *Savings {
@@ -49,21 +51,21 @@ func NewSavings(tariffs tariff.Tariffs) *Savings {
 }
 
 func (s *Savings) load() {
-	s.started, _ = settings.Time("savings.started")
-	s.gridCharged, _ = settings.Float("savings.gridCharged")
-	s.gridCost, _ = settings.Float("savings.gridCost")
-	s.gridSavedCost, _ = settings.Float("savings.gridSavedCost")
-	s.selfConsumptionCharged, _ = settings.Float("savings.selfConsumptionCharged")
-	s.selfConsumptionCost, _ = settings.Float("savings.selfConsumptionCost")
+	s.started, _ = settings.Time(s.prefix + "savings.started")
+	s.gridCharged, _ = settings.Float(s.prefix + "savings.gridCharged")
+	s.gridCost, _ = settings.Float(s.prefix + "savings.gridCost")
+	s.gridSavedCost, _ = settings.Float(s.prefix + "savings.gridSavedCost")
+	s.selfConsumptionCharged, _ = settings.Float(s.prefix + "savings.selfConsumptionCharged")
+	s.selfConsumptionCost, _ = settings.Float(s.prefix + "savings.selfConsumptionCost")
 }
 
 func (s *Savings) save() {
-	settings.SetTime("savings.started", s.started)
-	settings.SetFloat("savings.gridCharged", s.gridCharged)
-	settings.SetFloat("savings.gridCost", s.gridCost)
-	settings.SetFloat("savings.gridSavedCost", s.gridSavedCost)
-	settings.SetFloat("savings.selfConsumptionCharged", s.selfConsumptionCharged)
-	settings.SetFloat("savings.selfConsumptionCost", s.selfConsumptionCost)
+	settings.SetTime(s.prefix+"savings.started", s.started)
+	settings.SetFloat(s.prefix+"savings.gridCharged", s.gridCharged)
+	settings.SetFloat(s.prefix+"savings.gridCost", s.gridCost)
+	settings.SetFloat(s.prefix+"savings.gridSavedCost", s.gridSavedCost)
+	settings.SetFloat(s.prefix+"savings.selfConsumptionCharged", s.selfConsumptionCharged)
+	settings.SetFloat(s.prefix+"savings.selfConsumptionCost", s.selfConsumptionCost)
 }
 
 func (s *Savings) Since() time.Time {
@@ -135,13 +137,13 @@ func (s *Savings) updatePrices(p publisher) (float64, float64) {
 	gridPrice := s.currentGridPrice()
 	if gridPrice != s.lastGridPrice {
 		s.lastGridPrice = gridPrice
-		p.publish("tariffGrid", gridPrice)
+		p.publish(state.TariffGrid, gridPrice)
 	}
 
 	feedinPrice := s.currentFeedInPrice()
 	if feedinPrice != s.lastFeedInPrice {
 		s.lastFeedInPrice = feedinPrice
-		p.publish("tariffFeedIn", feedinPrice)
+		p.publish(state.TariffFeedIn, feedinPrice)
 	}
 
 	return gridPrice, feedinPrice
@@ -170,12 +172,12 @@ func (s *Savings) Update(p publisher, gridPower, pvPower, batteryPower, chargePo
 	s.selfConsumptionCharged += deltaSelf
 	s.selfConsumptionCost += deltaSelf * feedinPrice
 
-	p.publish("savingsTotalCharged", s.TotalCharged())
-	p.publish("savingsGridCharged", s.gridCharged)
-	p.publish("savingsSelfConsumptionCharged", s.selfConsumptionCharged)
-	p.publish("savingsSelfConsumptionPercent", s.SelfConsumptionPercent())
-	p.publish("savingsEffectivePrice", s.EffectivePrice())
-	p.publish("savingsAmount", s.SavingsAmount())
+	p.publish(state.SavingsTotalCharged, s.TotalCharged())
+	p.publish(state.SavingsGridCharged, s.gridCharged)
+	p.publish(state.SavingsSelfConsumptionCharged, s.selfConsumptionCharged)
+	p.publish(state.SavingsSelfConsumptionPercent, s.SelfConsumptionPercent())
+	p.publish(state.SavingsEffectivePrice, s.EffectivePrice())
+	p.publish(state.SavingsAmount, s.SavingsAmount())
 	s.hasPublished = true
 
 	s.save()
@@ -0,0 +1,250 @@
+package selftest
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Result is the outcome of a single test step
+type Result string
+
+const (
+	Passed      Result = "passed"      // charger reflected the change
+	Failed      Result = "failed"      // command failed or change not reflected within timeout
+	Unverified  Result = "unverified"  // command accepted, charger provides no means of verification
+	Unsupported Result = "unsupported" // charger does not implement the feature
+)
+
+// Config is the self-test configuration
+type Config struct {
+	MinCurrent float64       // A
+	MaxCurrent float64       // A
+	Phases     int           // active phases restored after phase switching, defaults to 3
+	Timeout    time.Duration // max time to wait for the charger to reflect a change, defaults to 30s
+	Interval   time.Duration // polling interval, defaults to 1s
+}
+
+// Step is a single test step
+type Step struct {
+	Name     string        `json:"name"`
+	Result   Result        `json:"result"`
+	Response time.Duration `json:"response,omitempty"` // time until the charger reflected the change
+	Details  string        `json:"details,omitempty"`
+}
+
+// Report is the charger compatibility report
+type Report struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Status   string    `json:"status"` // vehicle status at the start of the test
+	Steps    []Step    `json:"steps"`
+}
+
+// Passed returns true if no step failed
+func (r Report) Passed() bool {
+	for _, s := range r.Steps {
+		if s.Result == Failed {
+			return false
+		}
+	}
+	return true
+}
+
+// verifier returns true when the charger reflects the change
+type verifier func() (bool, string, error)
+
+type tester struct {
+	conf     Config
+	charger  api.Charger
+	charging bool // vehicle draws current, measured currents are meaningful
+	report   Report
+}
+
+// Run cycles enable/disable, min/max current and phase switching on the charger while measuring its response.
+// The charger is disabled afterwards.
+func Run(charger api.Charger, conf Config) Report {
+	if conf.Phases == 0 {
+		conf.Phases = 3
+	}
+	if conf.Timeout == 0 {
+		conf.Timeout = 30 * time.Second
+	}
+	if conf.Interval == 0 {
+		conf.Interval = time.Second
+	}
+
+	t := &tester{
+		conf:    conf,
+		charger: charger,
+		report:  Report{Started: time.Now()},
+	}
+
+	status, err := charger.Status()
+	if err != nil {
+		t.report.Status = err.Error()
+	} else {
+		t.report.Status = string(status)
+	}
+
+	t.step("enable", func() error { return charger.Enable(true) }, t.enabled(true))
+
+	// vehicle may need some time to start charging
+	if status != api.StatusA {
+		t.charging = t.wait(func() (bool, string, error) {
+			status, err := charger.Status()
+			return status == api.StatusC, "", err
+		}) == nil
+	}
+
+	t.step(fmt.Sprintf("min current %.3gA", conf.MinCurrent), t.setCurrent(conf.MinCurrent), t.current(conf.MinCurrent))
+	t.step(fmt.Sprintf("max current %.3gA", conf.MaxCurrent), t.setCurrent(conf.MaxCurrent), t.current(conf.MaxCurrent))
+
+	if _, ok := charger.(api.ChargerEx); ok {
+		current := conf.MinCurrent + 0.5
+		t.step(fmt.Sprintf("milliamp current %.3gA", current), t.setCurrent(current), t.current(current))
+	} else {
+		t.unsupported("milliamp current")
+	}
+
+	if ps, ok := charger.(api.PhaseSwitcher); ok {
+		t.step("switch 1p", func() error { return ps.Phases1p3p(1) }, t.phases(1))
+		t.step("switch 3p", func() error { return ps.Phases1p3p(3) }, t.phases(3))
+
+		if conf.Phases == 1 {
+			if err := ps.Phases1p3p(1); err != nil {
+				t.report.Steps = append(t.report.Steps, Step{Name: "restore 1p", Result: Failed, Details: err.Error()})
+			}
+		}
+	} else {
+		t.unsupported("phase switching")
+	}
+
+	t.step("disable", func() error { return charger.Enable(false) }, t.enabled(false))
+
+	t.report.Finished = time.Now()
+
+	return t.report
+}
+
+// step executes the command and waits for the charger to reflect the change
+func (t *tester) step(name string, cmd func() error, verify verifier) {
+	s := Step{Name: name}
+	start := time.Now()
+
+	if err := cmd(); err != nil {
+		s.Result = Failed
+		s.Details = err.Error()
+	} else if verify == nil {
+		s.Result = Unverified
+	} else if err := t.wait(func() (bool, string, error) {
+		ok, details, err := verify()
+		s.Details = details
+		return ok, details, err
+	}); err != nil {
+		s.Result = Failed
+		s.Details = err.Error()
+	} else {
+		s.Result = Passed
+		s.Response = time.Since(start).Round(time.Millisecond)
+	}
+
+	t.report.Steps = append(t.report.Steps, s)
+}
+
+func (t *tester) unsupported(name string) {
+	t.report.Steps = append(t.report.Steps, Step{Name: name, Result: Unsupported})
+}
+
+// wait polls the verifier until it succeeds or the timeout is exceeded
+func (t *tester) wait(verify verifier) error {
+	deadline := time.Now().Add(t.conf.Timeout)
+
+	for {
+		ok, details, err := verify()
+		if ok && err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return err
+			}
+			if details != "" {
+				return fmt.Errorf("timeout: %s", details)
+			}
+			return api.ErrTimeout
+		}
+
+		time.Sleep(t.conf.Interval)
+	}
+}
+
+func (t *tester) setCurrent(current float64) func() error {
+	return func() error {
+		if c, ok := t.charger.(api.ChargerEx); ok {
+			return c.MaxCurrentMillis(current)
+		}
+		return t.charger.MaxCurrent(int64(current))
+	}
+}
+
+func (t *tester) enabled(enable bool) verifier {
+	return func() (bool, string, error) {
+		res, err := t.charger.Enabled()
+		if err == nil && !enable && t.charging {
+			// charging must actually stop
+			if m, ok := t.charger.(api.Meter); ok {
+				var power float64
+				if power, err = m.CurrentPower(); err == nil && power > 50 {
+					return false, fmt.Sprintf("power %.0fW", power), nil
+				}
+			}
+		}
+		return res == enable, fmt.Sprintf("enabled %t", res), err
+	}
+}
+
+// current verifies the charger's reported limit or the measured currents while charging
+func (t *tester) current(current float64) verifier {
+	if c, ok := t.charger.(api.CurrentGetter); ok {
+		return func() (bool, string, error) {
+			res, err := c.GetMaxCurrent()
+			return math.Abs(res-current) < 0.1, fmt.Sprintf("limit %.3gA", res), err
+		}
+	}
+
+	if c, ok := t.charger.(api.MeterCurrent); ok && t.charging {
+		return func() (bool, string, error) {
+			l1, l2, l3, err := c.Currents()
+			res := math.Max(l1, math.Max(l2, l3))
+			// vehicles may draw less than offered
+			return res > 0 && res <= current+1, fmt.Sprintf("measured %.3gA", res), err
+		}
+	}
+
+	return nil
+}
+
+// phases verifies the active phases from the measured currents while charging
+func (t *tester) phases(phases int) verifier {
+	c, ok := t.charger.(api.MeterCurrent)
+	if !ok || !t.charging {
+		return nil
+	}
+
+	return func() (bool, string, error) {
+		l1, l2, l3, err := c.Currents()
+
+		var active int
+		for _, i := range []float64{l1, l2, l3} {
+			if i > 1 {
+				active++
+			}
+		}
+
+		return active == phases, fmt.Sprintf("%dp active", active), err
+	}
+}
@@ -0,0 +1,72 @@
+package selftest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type currentCharger struct {
+	*mock.MockCharger
+	current float64
+}
+
+func (c *currentCharger) MaxCurrent(current int64) error {
+	c.current = float64(current)
+	return nil
+}
+
+func (c *currentCharger) GetMaxCurrent() (float64, error) {
+	return c.current, nil
+}
+
+func TestSelfTest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := &currentCharger{MockCharger: mock.NewMockCharger(ctrl)}
+
+	var enabled bool
+	charger.MockCharger.EXPECT().Status().Return(api.StatusA, nil)
+	charger.MockCharger.EXPECT().Enable(gomock.Any()).DoAndReturn(func(enable bool) error {
+		enabled = enable
+		return nil
+	}).Times(2)
+	charger.MockCharger.EXPECT().Enabled().DoAndReturn(func() (bool, error) {
+		return enabled, nil
+	}).AnyTimes()
+
+	res := Run(charger, Config{MinCurrent: 6, MaxCurrent: 16, Timeout: 10 * time.Millisecond, Interval: time.Millisecond})
+
+	assert.Equal(t, string(api.StatusA), res.Status)
+	assert.Equal(t, []Result{Passed, Passed, Passed, Unsupported, Unsupported, Passed}, results(res))
+	assert.Equal(t, "limit 16A", res.Steps[2].Details)
+	assert.True(t, res.Passed())
+	assert.False(t, enabled)
+}
+
+func TestSelfTestFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	charger.EXPECT().Status().Return(api.StatusA, nil)
+	charger.EXPECT().Enable(gomock.Any()).Return(nil).Times(2)
+	charger.EXPECT().Enabled().Return(false, nil).AnyTimes()
+	charger.EXPECT().MaxCurrent(gomock.Any()).Return(nil).Times(2)
+
+	res := Run(charger, Config{MinCurrent: 6, MaxCurrent: 16, Timeout: 10 * time.Millisecond, Interval: time.Millisecond})
+
+	// charger does not reflect enable, currents cannot be verified
+	assert.Equal(t, []Result{Failed, Unverified, Unverified, Unsupported, Unsupported, Passed}, results(res))
+	assert.False(t, res.Passed())
+}
+
+func results(r Report) []Result {
+	var res []Result
+	for _, s := range r.Steps {
+		res = append(res, s.Result)
+	}
+	return res
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/internal/sim"
+)
+
+func init() {
+	sim.Step = func(site interface{}) {
+		site.(*Site).step()
+	}
+}
+
+// SetClock replaces the loadpoint's clock, e.g. for simulations advancing virtual time
+func (lp *LoadPoint) SetClock(clock clock.Clock) {
+	lp.clock = clock
+}
+
+// step executes a single control cycle for all loadpoints
+func (site *Site) step() {
+	for _, lp := range site.loadpoints {
+		site.update(lp)
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/avast/retry-go/v3"
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/forecast"
 	"github.com/evcc-io/evcc/push"
 	serverdb "github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/holiday"
 	"github.com/evcc-io/evcc/util/telemetry"
 )
 
@@ -30,7 +38,9 @@ type Updater interface {
 // Site is the main configuration container. A site can host multiple loadpoints.
 type Site struct {
 	uiChan       chan<- util.Param // client push messages
+	events       *event.Bus        // typed events
 	lpUpdateChan chan *LoadPoint
+	replaceChan  chan func() // device replacements applied between update cycles
 
 	*Health
 
@@ -38,38 +48,87 @@ type Site struct {
 	log *util.Logger
 
 	// configuration
-	Title                             string       `mapstructure:"title"`         // UI title
-	Voltage                           float64      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
-	ResidualPower                     float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
-	Meters                            MetersConfig // Meter references
-	PrioritySoC                       float64      `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
-	BufferSoC                         float64      `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
-	MaxGridSupplyWhileBatteryCharging float64      `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	Title                             string                  `mapstructure:"title"`           // UI title
+	Voltage                           float64                 `mapstructure:"voltage"`         // Operating voltage. 230V for Germany.
+	Currency                          string                  `mapstructure:"currency"`        // Currency of tariffs, sessions and statistics
+	ResidualPower                     float64                 `mapstructure:"residualPower"`   // PV meter only: household usage. Grid meter: household safety margin
+	TriggerInterval                   time.Duration           `mapstructure:"triggerInterval"` // Min interval of updates triggered by push-based meters, disabled if zero
+	Meters                            MetersConfig            // Meter references
+	Holidays                          HolidayConfig           `mapstructure:"holidays"`                          // Public holidays for plan scheduling
+	Virtual                           []VirtualMeterConfig    `mapstructure:"virtual"`                           // Meters derived from site measurements
+	Curtailment                       *CurtailmentConfig      `mapstructure:"curtailment"`                       // Grid frequency or signal based load shedding
+	Island                            *IslandConfig           `mapstructure:"island"`                            // Off-grid operation
+	GridSignal                        *GridSignalConfig       `mapstructure:"gridSignal"`                        // Grid operator load reduction, e.g. §14a EnWG
+	PeakShaving                       *PeakShavingConfig      `mapstructure:"peakShaving"`                       // Demand charge threshold of the average grid import
+	Jitter                            *JitterConfig           `mapstructure:"jitter"`                            // Randomized charger switching delay
+	Circuits                          []CircuitConfig         `mapstructure:"circuits"`                          // Per-phase current limits of nested circuits
+	Queue                             *QueueConfig            `mapstructure:"queue"`                             // Loadpoint queueing at circuit capacity
+	Consumers                         []ConsumerConfig        `mapstructure:"consumers"`                         // Smart consumers switched by pv surplus
+	Geofence                          *coordinator.Geofence   `mapstructure:"geofence"`                          // Site location for vehicle detection
+	BatteryDischarge                  *BatteryDischargeConfig `mapstructure:"batteryDischarge"`                  // Battery discharge usable for pv charging
+	PrioritySoC                       float64                 `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
+	BufferSoC                         float64                 `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
+	BufferStartSoC                    float64                 `mapstructure:"bufferStartSoC"`                    // start charging from battery above this SoC
+	MaxGridSupplyWhileBatteryCharging float64                 `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	Statistics                        StatisticsConfig        `mapstructure:"statistics"`                        // Reference price and co2 of the charge statistics
 
 	// meters
-	gridMeter     api.Meter   // Grid usage meter
-	pvMeters      []api.Meter // PV generation meters
-	batteryMeters []api.Meter // Battery charging meters
-
-	tariffs     tariff.Tariffs           // Tariff
-	loadpoints  []*LoadPoint             // Loadpoints
-	coordinator *coordinator.Coordinator // Savings
-	savings     *Savings                 // Savings
+	gridMeter     api.Meter         // Grid usage meter
+	gridFallback  api.Meter         // Fallback grid usage meter
+	gridMeterUsed string            // Active grid meter, primary or fallback
+	pvMeters      []api.Meter       // PV generation meters
+	pvTitles      []string          // PV meter names
+	batteryMeters []api.Meter       // Battery charging meters
+	virtualMeters []*virtualMeter   // Derived meters
+	curtailment   *curtailment      // Load shedding
+	island        *island           // Off-grid operation
+	gridSignal    *gridSignal       // Grid operator load reduction
+	demand        demandResponse    // Utility load-shed events
+	peakShaving   *peakShaving      // Demand charge threshold
+	validation    *meterValidation  // Startup meter validation
+	lintWarnings  []string          // Startup configuration warnings
+	circuits      []*circuit        // Circuit hierarchy
+	queue         *queue            // Loadpoints waiting for circuit capacity
+	consumers     []*consumer       // Smart consumers by descending priority
+	forecast      *forecast.Blended // Solar surplus estimate
+
+	tariffs        tariff.Tariffs           // Tariff
+	loadpoints     []*LoadPoint             // Loadpoints
+	coordinator    *coordinator.Coordinator // Savings
+	savings        *Savings                 // Savings
+	statistics     *statistics              // Charge statistics
+	settingsPrefix string                   // Settings key prefix of additional sites
+	persistState   bool                     // Loadpoint state is persisted across restarts
 
 	// cached state
-	gridPower       float64 // Grid power
-	pvPower         float64 // PV power
-	batteryPower    float64 // Battery charge power
-	batteryBuffered bool    // Battery buffer active
+	gridPower       float64          // Grid power
+	pvPower         float64          // PV power
+	pvMeasured      float64          // PV power including negative values
+	pvStrings       []state.PvString // PV power per meter
+	batteryPower    float64          // Battery charge power
+	batteryBuffered bool             // Battery buffer active
+	batterySoC      float64          // Battery soc
+	batteryMode     api.BatteryMode  // Battery operating mode
+	externalMode    api.BatteryMode  // Battery mode set by external automation
+	externalExpiry  time.Time        // Expiry of the external battery mode
+	gridRates       api.Rates        // Published grid tariff rates
+	feedInRates     api.Rates        // Published feed-in tariff rates
+}
+
+// HolidayConfig contains the public holiday region and additional dates
+type HolidayConfig struct {
+	Region string   `mapstructure:"region"` // country or region like de or de-by
+	Dates  []string `mapstructure:"dates"`  // additional holidays like 2022-12-24
 }
 
 // MetersConfig contains the loadpoint's meter configuration
 type MetersConfig struct {
-	GridMeterRef     string   `mapstructure:"grid"`      // Grid usage meter
-	PVMeterRef       string   `mapstructure:"pv"`        // PV meter
-	PVMetersRef      []string `mapstructure:"pvs"`       // Multiple PV meters
-	BatteryMeterRef  string   `mapstructure:"battery"`   // Battery charging meter
-	BatteryMetersRef []string `mapstructure:"batteries"` // Multiple Battery charging meters
+	GridMeterRef     string   `mapstructure:"grid"`         // Grid usage meter
+	GridFallbackRef  string   `mapstructure:"gridFallback"` // Grid usage meter used while grid meter is unavailable
+	PVMeterRef       string   `mapstructure:"pv"`           // PV meter
+	PVMetersRef      []string `mapstructure:"pvs"`          // Multiple PV meters
+	BatteryMeterRef  string   `mapstructure:"battery"`      // Battery charging meter
+	BatteryMetersRef []string `mapstructure:"batteries"`    // Multiple Battery charging meters
 }
 
 // NewSiteFromConfig creates a new site
@@ -80,17 +139,41 @@ func NewSiteFromConfig(
 	loadpoints []*LoadPoint,
 	vehicles []api.Vehicle,
 	tariffs tariff.Tariffs,
+	solar api.Rater,
 ) (*Site, error) {
 	site := NewSite()
 	if err := util.DecodeOther(other, site); err != nil {
 		return nil, err
 	}
 
+	if site.Currency != "" && !strings.EqualFold(site.Currency, tariffs.Currency.String()) {
+		return nil, fmt.Errorf("currency %s does not match tariff currency %s", site.Currency, tariffs.Currency)
+	}
+
 	Voltage = site.Voltage
 	site.loadpoints = loadpoints
 	site.tariffs = tariffs
 	site.coordinator = coordinator.New(log, vehicles)
+
+	// validate vehicle polling, invalid modes fall back to the loadpoint's
+	for _, v := range vehicles {
+		if p, ok := v.(api.Poller); ok {
+			poll := p.Poll()
+			if err := validatePoll(log, fmt.Sprintf("vehicle %s", v.Title()), &poll); err != nil {
+				return nil, err
+			}
+		}
+	}
+	site.coordinator.SetGeofence(site.Geofence)
 	site.savings = NewSavings(tariffs)
+	site.statistics = newStatistics(site.Statistics, tariffs)
+
+	// estimate the solar surplus of the site from forecast and actual consumption
+	var surplus api.PowerForecaster
+	if solar != nil {
+		site.forecast = forecast.NewBlended(solar)
+		surplus = site.forecast
+	}
 
 	// migrate session log
 	if serverdb.Instance != nil {
@@ -100,7 +183,7 @@ func NewSiteFromConfig(
 			err = serverdb.Instance.Migrator().RenameTable(table, new(db.Session))
 		}
 		if err == nil {
-			err = serverdb.Instance.AutoMigrate(new(db.Session))
+			err = serverdb.Instance.AutoMigrate(new(db.Session), new(db.Decision), new(db.User))
 		}
 		if err != nil {
 			return nil, err
@@ -114,10 +197,32 @@ func NewSiteFromConfig(
 		})
 	}
 
+	holidays, err := holiday.New(site.Holidays.Region, site.Holidays.Dates...)
+	if err != nil {
+		return nil, err
+	}
+
 	// give loadpoints access to vehicles and database
 	for _, lp := range loadpoints {
+		lp.holidays = holidays
+		lp.jitter = newJitter(site.Jitter)
+
 		lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
 
+		// use site tariff unless loadpoint has its own
+		lp.setDefaultTariff(tariffs)
+
+		// plan target charging using solar forecast and dynamic tariff slots
+		grid, dynamic := lp.tariff.(api.Rater)
+		if lp.Planner.Solar {
+			if surplus == nil {
+				lp.log.WARN.Println("planner: missing solar forecast")
+			}
+			lp.socTimer.Planner = planner.New(lp.log, surplus, grid)
+		} else if dynamic {
+			lp.socTimer.Planner = planner.New(lp.log, nil, grid)
+		}
+
 		if serverdb.Instance != nil {
 			var err error
 			if lp.db, err = db.New(lp.Title); err != nil {
@@ -136,6 +241,17 @@ func NewSiteFromConfig(
 		}
 	}
 
+	if site.Meters.GridFallbackRef != "" {
+		if site.gridMeter == nil {
+			return nil, errors.New("grid fallback requires grid meter")
+		}
+
+		var err error
+		if site.gridFallback, err = cp.Meter(site.Meters.GridFallbackRef); err != nil {
+			return nil, err
+		}
+	}
+
 	// multiple pv
 	for _, ref := range site.Meters.PVMetersRef {
 		pv, err := cp.Meter(ref)
@@ -143,6 +259,7 @@ func NewSiteFromConfig(
 			return nil, err
 		}
 		site.pvMeters = append(site.pvMeters, pv)
+		site.pvTitles = append(site.pvTitles, ref)
 	}
 
 	// single pv
@@ -155,6 +272,7 @@ func NewSiteFromConfig(
 			return nil, err
 		}
 		site.pvMeters = append(site.pvMeters, pv)
+		site.pvTitles = append(site.pvTitles, site.Meters.PVMeterRef)
 	}
 
 	// multiple batteries
@@ -183,6 +301,38 @@ func NewSiteFromConfig(
 		return nil, errors.New("missing either grid or pv meter")
 	}
 
+	if site.virtualMeters, err = newVirtualMeters(cp, site.Virtual); err != nil {
+		return nil, err
+	}
+
+	if site.curtailment, err = newCurtailment(site.log, site.Curtailment); err != nil {
+		return nil, err
+	}
+
+	if site.island, err = newIsland(site.log, site.Island); err != nil {
+		return nil, err
+	}
+
+	if site.gridSignal, err = newGridSignal(site.log, site.GridSignal); err != nil {
+		return nil, err
+	}
+
+	if site.peakShaving, err = newPeakShaving(site.log, site.PeakShaving); err != nil {
+		return nil, err
+	}
+
+	if site.circuits, err = newCircuits(cp, site.Circuits, loadpoints); err != nil {
+		return nil, err
+	}
+
+	if site.queue, err = newQueue(site.Queue); err != nil {
+		return nil, err
+	}
+
+	if site.consumers, err = newConsumers(site.log, cp, site.Consumers); err != nil {
+		return nil, err
+	}
+
 	return site, nil
 }
 
@@ -191,11 +341,27 @@ func NewSite() *Site {
 	lp := &Site{
 		log:     util.NewLogger("site"),
 		Voltage: 230, // V
+		events:  event.New(),
+		demand:  demandResponse{clock: clock.New()},
 	}
 
 	return lp
 }
 
+// PersistState enables restoring mode, targets and the open session of the loadpoints after restart
+func (site *Site) PersistState() {
+	site.persistState = true
+}
+
+// SetSettingsPrefix separates the persisted settings of additional sites from the main site
+func (site *Site) SetSettingsPrefix(prefix string) {
+	site.settingsPrefix = prefix
+	site.savings.prefix = prefix
+	site.savings.load()
+	site.statistics.prefix = prefix
+	site.statistics.load()
+}
+
 // LoadPoints returns the array of associated loadpoints
 func (site *Site) LoadPoints() []loadpoint.API {
 	res := make([]loadpoint.API, len(site.loadpoints))
@@ -241,6 +407,10 @@ func (site *Site) DumpConfig() {
 		site.log.INFO.Println(meterCapabilities("grid", site.gridMeter))
 	}
 
+	if site.gridFallback != nil {
+		site.log.INFO.Println(meterCapabilities("grid fallback", site.gridFallback))
+	}
+
 	if len(site.pvMeters) > 0 {
 		for i, pv := range site.pvMeters {
 			site.log.INFO.Println(meterCapabilities(fmt.Sprintf("pv %d", i+1), pv))
@@ -292,7 +462,7 @@ func (site *Site) DumpConfig() {
 
 		lp.log.INFO.Printf("  meters:      charge %s", presence[lp.HasChargeMeter()])
 
-		lp.publish("chargeConfigured", lp.HasChargeMeter())
+		lp.publish(state.ChargeConfigured, lp.HasChargeMeter())
 		if lp.HasChargeMeter() {
 			lp.log.INFO.Printf(meterCapabilities("charge", lp.chargeMeter))
 		}
@@ -315,7 +485,10 @@ func (site *Site) publish(key string, val interface{}) {
 // updateMeter updates and publishes single meter
 func (site *Site) updateMeter(meter api.Meter, power *float64) func() error {
 	return func() error {
+		start := time.Now()
 		value, err := meter.CurrentPower()
+		metrics.Observe(meter, start, err)
+
 		if err == nil {
 			*power = value // update value if no error
 		}
@@ -346,14 +519,22 @@ func (site *Site) updateMeters() error {
 
 	if len(site.pvMeters) > 0 {
 		site.pvPower = 0
+		site.pvMeasured = 0
+		site.pvStrings = make([]state.PvString, len(site.pvMeters))
 
 		for id, meter := range site.pvMeters {
 			var power float64
 			err := retry.Do(site.updateMeter(meter, &power), retryOptions...)
 
+			if id < len(site.pvTitles) {
+				site.pvStrings[id].Title = site.pvTitles[id]
+			}
+
 			if err == nil {
 				// ignore negative values which represent self-consumption
 				site.pvPower += math.Max(0, power)
+				site.pvMeasured += power
+				site.pvStrings[id].Power = math.Max(0, power)
 				if power < -500 {
 					site.log.WARN.Printf("pv %d power: %.0fW is negative - check configuration if sign is correct", id, power)
 				}
@@ -364,7 +545,8 @@ func (site *Site) updateMeters() error {
 		}
 
 		site.log.DEBUG.Printf("pv power: %.0fW", site.pvPower)
-		site.publish("pvPower", site.pvPower)
+		site.publish(state.PvPower, site.pvPower)
+		site.publish(state.Pv, site.pvStrings)
 	}
 
 	if len(site.batteryMeters) > 0 {
@@ -382,27 +564,47 @@ func (site *Site) updateMeters() error {
 		}
 
 		site.log.DEBUG.Printf("battery power: %.0fW", site.batteryPower)
-		site.publish("batteryPower", site.batteryPower)
+		site.publish(state.BatteryPower, site.batteryPower)
 	}
 
-	err := retryMeter("grid", site.gridMeter, &site.gridPower)
+	gridMeter := site.gridMeter
+	err := retryMeter("grid", gridMeter, &site.gridPower)
+
+	// use fallback grid meter while grid meter is unavailable
+	if site.gridFallback != nil {
+		used := "primary"
+		if err != nil {
+			gridMeter, used = site.gridFallback, "fallback"
+			err = retryMeter("grid", gridMeter, &site.gridPower)
+		}
+
+		if used != site.gridMeterUsed {
+			if used == "fallback" {
+				site.log.WARN.Println("grid meter: using fallback")
+			} else {
+				site.log.INFO.Println("grid meter: using primary")
+			}
+			site.gridMeterUsed = used
+			site.publish(state.GridMeter, used)
+		}
+	}
 
 	// currents
-	if phaseMeter, ok := site.gridMeter.(api.MeterCurrent); err == nil && ok {
+	if phaseMeter, ok := gridMeter.(api.MeterCurrent); err == nil && ok {
 		i1, i2, i3, err := phaseMeter.Currents()
 		if err == nil {
 			site.log.DEBUG.Printf("grid currents: %.3gA", []float64{i1, i2, i3})
-			site.publish("gridCurrents", []float64{i1, i2, i3})
+			site.publish(state.GridCurrents, []float64{i1, i2, i3})
 		} else {
 			site.log.ERROR.Printf("grid meter currents: %v", err)
 		}
 	}
 
 	// grid energy
-	if energyMeter, ok := site.gridMeter.(api.MeterEnergy); ok {
+	if energyMeter, ok := gridMeter.(api.MeterEnergy); ok {
 		val, err := energyMeter.TotalEnergy()
 		if err == nil {
-			site.publish("gridEnergy", val)
+			site.publish(state.GridEnergy, val)
 		} else {
 			site.log.ERROR.Println(fmt.Errorf("grid meter energy: %v", err))
 		}
@@ -430,7 +632,7 @@ func (site *Site) sitePower(totalChargePower float64) (float64, error) {
 			site.pvPower = 0
 		}
 		site.log.DEBUG.Printf("pv power: %.0fW", site.pvPower)
-		site.publish("pvPower", site.pvPower)
+		site.publish(state.PvPower, site.pvPower)
 	}
 
 	// honour battery priority
@@ -448,7 +650,8 @@ func (site *Site) sitePower(totalChargePower float64) (float64, error) {
 				socs += soc / float64(len(site.batteryMeters))
 			}
 		}
-		site.publish("batterySoC", math.Round(socs))
+		site.batterySoC = socs
+		site.publish(state.BatterySoC, math.Round(socs))
 
 		site.Lock()
 		defer site.Unlock()
@@ -461,6 +664,15 @@ func (site *Site) sitePower(totalChargePower float64) (float64, error) {
 
 		// if battery is discharging above bufferSoC ignore it
 		site.batteryBuffered = batteryPower > 0 && site.BufferSoC > 0 && socs > site.BufferSoC
+
+		// above bufferStartSoC battery headroom is available for charging
+		if site.BufferStartSoC > 0 && socs > site.BufferStartSoC {
+			site.batteryBuffered = true
+			batteryPower = site.bufferPower(batteryPower, socs)
+		}
+
+		// treat battery discharge as surplus up to the configured limit
+		batteryPower = site.batteryDischargePower(batteryPower, socs)
 	}
 
 	sitePower := sitePower(site.log, site.MaxGridSupplyWhileBatteryCharging, site.gridPower, batteryPower, site.ResidualPower)
@@ -489,68 +701,144 @@ func (site *Site) update(lp Updater) {
 		totalChargePower += lp.GetChargePower()
 	}
 
-	if sitePower, err := site.sitePower(totalChargePower); err == nil {
-		lp.Update(sitePower, cheap, site.batteryBuffered)
+	// shed charging load on low grid frequency
+	site.updateCurtailment()
+
+	// limit total charge power on grid operator signal
+	site.updateGridSignal(totalChargePower)
+
+	// keep the average grid import below the demand charge threshold
+	site.updatePeakShaving(totalChargePower)
 
+	// limit loadpoints to the remaining current of their circuits
+	site.updateCircuits()
+
+	if sitePower, err := site.sitePower(totalChargePower); err == nil {
 		// ignore negative pvPower values as that means it is not an energy source but consumption
 		homePower := site.gridPower + math.Max(0, site.pvPower) + site.batteryPower - totalChargePower
 		homePower = math.Max(homePower, 0)
-		site.publish("homePower", homePower)
+
+		// limit charging to inverter capacity while off-grid
+		site.updateIsland(homePower, totalChargePower)
+
+		// grid import is attributed to charging, home consumption is supplied by solar and battery first
+		greenShare := site.greenShare(totalChargePower)
+		for _, lp := range site.loadpoints {
+			lp.setGreenShare(greenShare)
+		}
+
+		lp.Update(sitePower, cheap, site.batteryBuffered)
+
+		// switch smart consumers by pv surplus
+		site.updateConsumers(sitePower)
+
+		// hold battery while boost charging
+		site.updateBatteryMode()
+
+		site.publish(state.HomePower, homePower)
+
+		site.updateForecast(homePower)
+
+		site.updateVirtualMeters(siteMeasurements{
+			grid:    site.gridPower,
+			pv:      site.pvPower,
+			battery: site.batteryPower,
+			charge:  totalChargePower,
+			home:    homePower,
+		})
+
+		site.validateMeters(validationSample{
+			grid:    site.gridPower,
+			pv:      site.pvMeasured,
+			battery: site.batteryPower,
+			charge:  totalChargePower,
+		})
 
 		site.Health.Update()
 	}
 
+	site.publishTariffs()
+
 	// update savings and aggregate telemetry
 	// TODO: use energy instead of current power for better results
 	deltaCharged, deltaSelf := site.savings.Update(site, site.gridPower, site.pvPower, site.batteryPower, totalChargePower)
+	site.statistics.update(site, deltaCharged-deltaSelf, deltaSelf, site.savings.lastGridPrice, site.savings.lastFeedInPrice)
 	if telemetry.Enabled() && totalChargePower > standbyPower {
 		go telemetry.UpdateChargeProgress(site.log, totalChargePower, deltaCharged, deltaSelf)
 	}
 }
 
+// greenShare returns the solar and battery share of the charge power
+func (site *Site) greenShare(totalChargePower float64) float64 {
+	if totalChargePower <= 0 {
+		return 0
+	}
+
+	share := 1 - math.Max(0, site.gridPower)/totalChargePower
+	return math.Min(1, math.Max(0, share))
+}
+
 // prepare publishes initial values
 func (site *Site) prepare() {
-	site.publish("siteTitle", site.Title)
+	site.publish(state.SiteTitle, site.Title)
+
+	site.publish(state.GridConfigured, site.gridMeter != nil)
+	site.publish(state.PvConfigured, len(site.pvMeters) > 0)
+	site.publish(state.BatteryConfigured, len(site.batteryMeters) > 0)
+	site.publish(state.BufferSoC, site.BufferSoC)
+	site.publish(state.BufferStartSoC, site.BufferStartSoC)
+	site.publish(state.PrioritySoC, site.PrioritySoC)
+	site.publish(state.ResidualPower, site.ResidualPower)
 
-	site.publish("gridConfigured", site.gridMeter != nil)
-	site.publish("pvConfigured", len(site.pvMeters) > 0)
-	site.publish("batteryConfigured", len(site.batteryMeters) > 0)
-	site.publish("bufferSoC", site.BufferSoC)
-	site.publish("prioritySoC", site.PrioritySoC)
-	site.publish("residualPower", site.ResidualPower)
+	site.publish(state.Currency, site.tariffs.Currency.String())
+	site.publish(state.SavingsSince, site.savings.Since().Unix())
+	site.publish(state.Statistics, site.statistics.get())
 
-	site.publish("currency", site.tariffs.Currency.String())
-	site.publish("savingsSince", site.savings.Since().Unix())
+	site.publish(state.Vehicles, vehicleTitles(site.GetVehicles()))
 
-	site.publish("vehicles", vehicleTitles(site.GetVehicles()))
+	// warn about common misconfigurations
+	site.publishLint()
 }
 
 // Prepare attaches communication channels to site and loadpoints
 func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event) {
 	site.uiChan = uiChan
 	site.lpUpdateChan = make(chan *LoadPoint, 1) // 1 capacity to avoid deadlock
+	site.replaceChan = make(chan func())
+
+	if site.events == nil {
+		site.events = event.New()
+	}
+	pushEvents(site.events, pushChan)
 
 	site.prepare()
 
 	for id, lp := range site.loadpoints {
+		id := id
 		lpUIChan := make(chan util.Param)
-		lpPushChan := make(chan push.Event)
 
 		// pipe messages through go func to add id
 		go func(id int) {
-			for {
-				select {
-				case param := <-lpUIChan:
-					param.LoadPoint = &id
-					uiChan <- param
-				case ev := <-lpPushChan:
-					ev.LoadPoint = &id
-					pushChan <- ev
-				}
+			for param := range lpUIChan {
+				param.LoadPoint = &id
+				uiChan <- param
 			}
 		}(id)
 
-		lp.Prepare(lpUIChan, lpPushChan, site.lpUpdateChan)
+		lp.events = site.events.Publisher(&id)
+		recordSessions(site.events, &id, lp)
+		lp.restoreSchedules(fmt.Sprintf("%slp%d.schedules", site.settingsPrefix, id+1))
+		if site.persistState {
+			lp.restoreState(fmt.Sprintf("%slp%d.state", site.settingsPrefix, id+1))
+		}
+		lp.Prepare(lpUIChan, pushChan, site.lpUpdateChan)
+	}
+}
+
+// shutdown closes the open sessions and persists the transient state of all loadpoints
+func (site *Site) shutdown() {
+	for _, lp := range site.loadpoints {
+		lp.shutdown()
 	}
 }
 
@@ -571,16 +859,34 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 	loadpointChan := make(chan Updater)
 	go site.loopLoadpoints(loadpointChan)
 
+	// push-based devices trigger immediate updates if enabled
+	var trigger <-chan struct{}
+	if site.TriggerInterval > 0 {
+		trigger = util.UpdateTrigger()
+	}
+
 	ticker := time.NewTicker(interval)
 	site.update(<-loadpointChan) // start immediately
+	updated := time.Now()
 
 	for {
 		select {
 		case <-ticker.C:
 			site.update(<-loadpointChan)
+			updated = time.Now()
+		case <-trigger:
+			// the ticker remains as fallback for pull-only devices
+			if time.Since(updated) >= site.TriggerInterval {
+				site.update(<-loadpointChan)
+				updated = time.Now()
+				ticker.Reset(interval)
+			}
 		case lp := <-site.lpUpdateChan:
 			site.update(lp)
+		case fn := <-site.replaceChan:
+			fn()
 		case <-stopC:
+			site.shutdown()
 			return
 		}
 	}
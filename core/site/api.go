@@ -1,8 +1,12 @@
 package site
 
 import (
+	"time"
+
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/state"
 )
 
 // API is the external site API
@@ -10,12 +14,25 @@ type API interface {
 	Healthy() bool
 	LoadPoints() []loadpoint.API
 
+	// Events returns the event bus of site and loadpoints
+	Events() *event.Bus
+
 	//
 	// battery
 	//
 
 	GetBufferSoC() float64
 	SetBufferSoC(float64) error
+	// GetBatteryMode returns the battery mode resolved by evcc
+	GetBatteryMode() api.BatteryMode
+	// GetExternalBatteryMode returns the external battery mode and its expiry
+	GetExternalBatteryMode() (api.BatteryMode, time.Time)
+	// SetExternalBatteryMode overrides the battery mode until expiry, empty mode removes the override
+	SetExternalBatteryMode(api.BatteryMode, time.Time) error
+	// GetBufferStartSoC returns the soc above which charging may start from the battery
+	GetBufferStartSoC() float64
+	// SetBufferStartSoC sets the soc above which charging may start from the battery
+	SetBufferStartSoC(float64) error
 	GetPrioritySoC() float64
 	SetPrioritySoC(float64) error
 
@@ -26,10 +43,37 @@ type API interface {
 	GetResidualPower() float64
 	SetResidualPower(float64) error
 
+	//
+	// demand response
+	//
+
+	// SetDemandResponse applies the utility's load-shed event, nil releases the event
+	SetDemandResponse(*state.DemandResponseEvent)
+	// GetDemandResponseOverride returns if the user overrides the load-shed event
+	GetDemandResponseOverride() bool
+	// SetDemandResponseOverride overrides the load-shed event for emergency charging
+	SetDemandResponseOverride(bool) error
+
 	//
 	// vehicles
 	//
 
 	// GetVehicles is the list of vehicles
 	GetVehicles() []api.Vehicle
+	// WakeUpVehicle wakes up the vehicle in the background, wake-up calls are rate limited
+	WakeUpVehicle(api.Vehicle) error
+
+	//
+	// statistics
+	//
+
+	// GetStatistics returns the charged energy, cost and co2 statistics
+	GetStatistics() state.ChargeStatistics
+
+	//
+	// tariffs
+	//
+
+	// GetTariff returns the grid, feedin or co2 tariff
+	GetTariff(string) api.Tariff
 }
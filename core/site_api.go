@@ -2,13 +2,28 @@ package core
 
 import (
 	"errors"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
 )
 
 var _ site.API = (*Site)(nil)
 
+// Events returns the site's event bus
+func (site *Site) Events() *event.Bus {
+	return site.events
+}
+
+// Tariff names
+const (
+	TariffGrid   = "grid"
+	TariffFeedIn = "feedin"
+	TariffCo2    = "co2"
+)
+
 // GetPrioritySoC returns the PrioritySoC
 func (site *Site) GetPrioritySoC() float64 {
 	site.Lock()
@@ -26,7 +41,7 @@ func (site *Site) SetPrioritySoC(soc float64) error {
 	}
 
 	site.PrioritySoC = soc
-	site.publish("prioritySoC", site.PrioritySoC)
+	site.publish(state.PrioritySoC, site.PrioritySoC)
 
 	return nil
 }
@@ -48,7 +63,76 @@ func (site *Site) SetBufferSoC(soc float64) error {
 	}
 
 	site.BufferSoC = soc
-	site.publish("bufferSoC", site.BufferSoC)
+	site.publish(state.BufferSoC, site.BufferSoC)
+
+	return nil
+}
+
+// GetBufferStartSoC returns the BufferStartSoC
+func (site *Site) GetBufferStartSoC() float64 {
+	site.Lock()
+	defer site.Unlock()
+	return site.BufferStartSoC
+}
+
+// SetBufferStartSoC sets the BufferStartSoC
+func (site *Site) SetBufferStartSoC(soc float64) error {
+	site.Lock()
+	defer site.Unlock()
+
+	if len(site.batteryMeters) == 0 {
+		return errors.New("battery not configured")
+	}
+
+	site.BufferStartSoC = soc
+	site.publish(state.BufferStartSoC, site.BufferStartSoC)
+
+	return nil
+}
+
+// GetBatteryMode returns the battery mode resolved by evcc
+func (site *Site) GetBatteryMode() api.BatteryMode {
+	site.Lock()
+	defer site.Unlock()
+	return site.batteryMode
+}
+
+// GetExternalBatteryMode returns the external battery mode and its expiry
+func (site *Site) GetExternalBatteryMode() (api.BatteryMode, time.Time) {
+	site.Lock()
+	defer site.Unlock()
+	return site.externalMode, site.externalExpiry
+}
+
+// SetExternalBatteryMode overrides the battery mode until expiry, empty mode removes the override
+func (site *Site) SetExternalBatteryMode(mode api.BatteryMode, expiry time.Time) error {
+	site.Lock()
+	defer site.Unlock()
+
+	if mode != "" {
+		var controllable bool
+		for _, meter := range site.batteryMeters {
+			if _, ok := meter.(api.BatteryController); ok {
+				controllable = true
+			}
+		}
+
+		if !controllable {
+			return errors.New("battery not controllable")
+		}
+
+		if !expiry.After(time.Now()) {
+			return errors.New("expiry in the past")
+		}
+	} else {
+		expiry = time.Time{}
+	}
+
+	site.log.DEBUG.Printf("set external battery mode: %s until %v", mode, expiry.Round(time.Second))
+
+	site.externalMode = mode
+	site.externalExpiry = expiry
+	site.publish(state.BatteryModeExpiry, expiry)
 
 	return nil
 }
@@ -66,7 +150,7 @@ func (site *Site) SetResidualPower(power float64) error {
 	defer site.Unlock()
 
 	site.ResidualPower = power
-	site.publish("residualPower", site.ResidualPower)
+	site.publish(state.ResidualPower, site.ResidualPower)
 
 	return nil
 }
@@ -77,3 +161,25 @@ func (site *Site) GetVehicles() []api.Vehicle {
 	defer site.Unlock()
 	return site.coordinator.GetVehicles()
 }
+
+// GetStatistics returns the charged energy, cost and co2 statistics
+func (site *Site) GetStatistics() Statistics {
+	return site.statistics.get()
+}
+
+// GetTariff returns the grid, feedin or co2 tariff
+func (site *Site) GetTariff(tariff string) api.Tariff {
+	site.Lock()
+	defer site.Unlock()
+
+	switch tariff {
+	case TariffGrid:
+		return site.tariffs.Grid
+	case TariffFeedIn:
+		return site.tariffs.FeedIn
+	case TariffCo2:
+		return site.tariffs.Co2
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// BatteryDischargeConfig defines home battery discharge usable for pv charging
+type BatteryDischargeConfig struct {
+	MaxPower float64 `mapstructure:"maxPower"` // battery discharge power treated as surplus in W
+	MinSoC   float64 `mapstructure:"minSoC"`   // protect battery from discharging into vehicles below this soc
+}
+
+// batteryDischargePower adjusts the battery power by the discharge power usable as surplus.
+// Below min soc or while the battery has charging priority discharge is never treated as surplus.
+func (site *Site) batteryDischargePower(batteryPower, soc float64) float64 {
+	bd := site.BatteryDischarge
+	if bd == nil || bd.MaxPower <= 0 || soc < bd.MinSoC || soc < site.PrioritySoC {
+		return batteryPower
+	}
+
+	site.log.DEBUG.Printf("battery discharge: using up to %.0fW at soc: %.0f%%", bd.MaxPower, soc)
+
+	return batteryPower - bd.MaxPower
+}
+
+// bufferPower adjusts the battery power above buffer start soc.
+// Battery discharge is increasingly treated as surplus, ramping from none at buffer start soc to all at 100%.
+func (site *Site) bufferPower(batteryPower, soc float64) float64 {
+	if batteryPower <= 0 || site.BufferStartSoC >= 100 {
+		return batteryPower
+	}
+
+	ramp := math.Min((soc-site.BufferStartSoC)/(100-site.BufferStartSoC), 1)
+	site.log.DEBUG.Printf("battery buffer: using %.0f%% of %.0fW discharge at soc: %.0f%%", 100*ramp, batteryPower, soc)
+
+	return batteryPower * (1 - ramp)
+}
+
+// requiredBatteryMode returns the battery mode required by the charging loadpoints.
+// The battery is held while boost charging or while charging below min soc.
+// An external battery mode takes precedence until it expires.
+func (site *Site) requiredBatteryMode() api.BatteryMode {
+	if mode, ok := site.externalBatteryMode(); ok {
+		return mode
+	}
+
+	for _, lp := range site.loadpoints {
+		if lp.GetStatus() != api.StatusC {
+			continue
+		}
+
+		if lp.GetMode() == api.ModeNow {
+			return api.BatteryHold
+		}
+
+		if bd := site.BatteryDischarge; bd != nil && site.batterySoC < bd.MinSoC {
+			return api.BatteryHold
+		}
+	}
+
+	return api.BatteryNormal
+}
+
+// externalBatteryMode returns the external battery mode and removes it once expired
+func (site *Site) externalBatteryMode() (api.BatteryMode, bool) {
+	site.Lock()
+	defer site.Unlock()
+
+	if site.externalMode == "" {
+		return "", false
+	}
+
+	if !time.Now().Before(site.externalExpiry) {
+		site.log.DEBUG.Printf("battery mode: %s expired", site.externalMode)
+		site.externalMode = ""
+		site.externalExpiry = time.Time{}
+		site.publish(state.BatteryModeExpiry, site.externalExpiry)
+		return "", false
+	}
+
+	return site.externalMode, true
+}
+
+// updateBatteryMode applies the battery mode to all controllable batteries
+func (site *Site) updateBatteryMode() {
+	mode := site.requiredBatteryMode()
+	if mode == site.batteryMode {
+		return
+	}
+
+	for id, meter := range site.batteryMeters {
+		bc, ok := meter.(api.BatteryController)
+		if !ok {
+			continue
+		}
+
+		if err := bc.SetBatteryMode(mode); err != nil {
+			site.log.ERROR.Printf("battery %d mode: %v", id+1, err)
+			return
+		}
+	}
+
+	site.log.DEBUG.Printf("battery mode: %s", mode)
+	site.batteryMode = mode
+	site.publish(state.BatteryMode, mode)
+}
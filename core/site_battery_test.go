@@ -0,0 +1,122 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatteryDischargePower(t *testing.T) {
+	site := &Site{
+		log:         util.NewLogger("foo"),
+		PrioritySoC: 30,
+		BatteryDischarge: &BatteryDischargeConfig{
+			MaxPower: 2000,
+			MinSoC:   50,
+		},
+	}
+
+	tc := []struct {
+		battery, soc, res float64
+	}{
+		{0, 80, -2000},     // idle battery supplies up to limit
+		{1500, 80, -500},   // discharging below limit
+		{-1000, 80, -3000}, // charging
+		{1500, 40, 1500},   // protected below min soc
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.res, site.batteryDischargePower(tc.battery, tc.soc), "%+v", tc)
+	}
+
+	// battery charging has priority
+	site.BatteryDischarge.MinSoC = 0
+	assert.Equal(t, 1500.0, site.batteryDischargePower(1500, 20))
+}
+
+func TestBufferPower(t *testing.T) {
+	site := &Site{
+		log:            util.NewLogger("foo"),
+		BufferStartSoC: 80,
+	}
+
+	tc := []struct {
+		battery, soc, res float64
+	}{
+		{2000, 80, 2000}, // no surplus at start soc
+		{2000, 90, 1000}, // half way
+		{2000, 100, 0},   // all discharge is surplus
+		{-1000, 90, -1000},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.res, site.bufferPower(tc.battery, tc.soc), "%+v", tc)
+	}
+}
+
+func TestRequiredBatteryMode(t *testing.T) {
+	lp := &LoadPoint{status: api.StatusC, Mode: api.ModePV}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		loadpoints: []*LoadPoint{lp},
+		batterySoC: 40,
+	}
+
+	assert.Equal(t, api.BatteryNormal, site.requiredBatteryMode())
+
+	// boost charging
+	lp.Mode = api.ModeNow
+	assert.Equal(t, api.BatteryHold, site.requiredBatteryMode())
+
+	// charging below min soc
+	lp.Mode = api.ModePV
+	site.BatteryDischarge = &BatteryDischargeConfig{MinSoC: 50}
+	assert.Equal(t, api.BatteryHold, site.requiredBatteryMode())
+
+	// not charging
+	lp.status = api.StatusB
+	assert.Equal(t, api.BatteryNormal, site.requiredBatteryMode())
+}
+
+func TestExternalBatteryMode(t *testing.T) {
+	lp := &LoadPoint{status: api.StatusC, Mode: api.ModeNow}
+
+	site := &Site{
+		log:           util.NewLogger("foo"),
+		loadpoints:    []*LoadPoint{lp},
+		batteryMeters: []api.Meter{&batteryController{}},
+	}
+
+	assert.Error(t, site.SetExternalBatteryMode(api.BatteryCharge, time.Now().Add(-time.Minute)), "expired")
+
+	// external mode takes precedence
+	assert.NoError(t, site.SetExternalBatteryMode(api.BatteryCharge, time.Now().Add(time.Hour)))
+	assert.Equal(t, api.BatteryCharge, site.requiredBatteryMode())
+
+	// expired
+	site.externalExpiry = time.Now().Add(-time.Second)
+	assert.Equal(t, api.BatteryHold, site.requiredBatteryMode())
+	mode, _ := site.GetExternalBatteryMode()
+	assert.Equal(t, api.BatteryMode(""), mode)
+
+	// removed
+	assert.NoError(t, site.SetExternalBatteryMode(api.BatteryNormal, time.Now().Add(time.Hour)))
+	assert.NoError(t, site.SetExternalBatteryMode("", time.Time{}))
+	assert.Equal(t, api.BatteryHold, site.requiredBatteryMode())
+
+	// battery not controllable
+	site.batteryMeters = nil
+	assert.Error(t, site.SetExternalBatteryMode(api.BatteryHold, time.Now().Add(time.Hour)))
+}
+
+type batteryController struct {
+	api.Meter
+}
+
+func (*batteryController) SetBatteryMode(api.BatteryMode) error {
+	return nil
+}
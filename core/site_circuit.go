@@ -0,0 +1,355 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+	"golang.org/x/exp/slices"
+)
+
+// CircuitConfig defines a circuit with per-phase current limit. Circuits can be nested, e.g. main fuse and garage subpanel.
+type CircuitConfig struct {
+	Name       string  `mapstructure:"name"`
+	Parent     string  `mapstructure:"parent"`     // parent circuit, empty for top level circuits
+	MaxCurrent float64 `mapstructure:"maxCurrent"` // per-phase current limit in A
+	MeterRef   string  `mapstructure:"meter"`      // meter measuring the phase currents of all consumers in the circuit
+
+	// MaxUnbalance is the maximum current difference between the phases in A, e.g. 20A in Germany.
+	// Single and two phase charging is limited to keep the circuit within the limit.
+	MaxUnbalance float64 `mapstructure:"maxUnbalance"`
+}
+
+// circuit is a node of the circuit hierarchy
+type circuit struct {
+	name         string
+	parent       *circuit
+	maxCurrent   float64
+	maxUnbalance float64
+	meter        api.MeterCurrent
+}
+
+// phaseCurrents are the L1..L3 currents
+type phaseCurrents [3]float64
+
+// newCircuits creates the circuit hierarchy and assigns the loadpoints
+func newCircuits(cp configProvider, configs []CircuitConfig, loadpoints []*LoadPoint) ([]*circuit, error) {
+	res := make([]*circuit, 0, len(configs))
+	circuits := make(map[string]*circuit)
+
+	for i, cc := range configs {
+		if cc.Name == "" {
+			return nil, fmt.Errorf("circuit %d: missing name", i+1)
+		}
+		if _, ok := circuits[cc.Name]; ok {
+			return nil, fmt.Errorf("circuit %s: duplicate name", cc.Name)
+		}
+		if cc.MaxCurrent <= 0 {
+			return nil, fmt.Errorf("circuit %s: missing maxCurrent", cc.Name)
+		}
+		if cc.MaxUnbalance < 0 {
+			return nil, fmt.Errorf("circuit %s: invalid maxUnbalance", cc.Name)
+		}
+
+		c := &circuit{name: cc.Name, maxCurrent: cc.MaxCurrent, maxUnbalance: cc.MaxUnbalance}
+
+		if cc.MeterRef != "" {
+			meter, err := cp.Meter(cc.MeterRef)
+			if err != nil {
+				return nil, fmt.Errorf("circuit %s: %w", cc.Name, err)
+			}
+
+			var ok bool
+			if c.meter, ok = meter.(api.MeterCurrent); !ok {
+				return nil, fmt.Errorf("circuit %s: meter does not provide phase currents", cc.Name)
+			}
+		}
+
+		circuits[cc.Name] = c
+		res = append(res, c)
+	}
+
+	for _, cc := range configs {
+		if cc.Parent == "" {
+			continue
+		}
+
+		parent, ok := circuits[cc.Parent]
+		if !ok {
+			return nil, fmt.Errorf("circuit %s: invalid parent: %s", cc.Name, cc.Parent)
+		}
+		circuits[cc.Name].parent = parent
+	}
+
+	// detect loops
+	for _, c := range res {
+		for p, depth := c.parent, 0; p != nil; p, depth = p.parent, depth+1 {
+			if depth >= len(res) {
+				return nil, fmt.Errorf("circuit %s: circular parent", c.name)
+			}
+		}
+	}
+
+	for _, lp := range loadpoints {
+		if lp.CircuitRef == "" {
+			continue
+		}
+
+		c, ok := circuits[lp.CircuitRef]
+		if !ok {
+			return nil, fmt.Errorf("loadpoint %s: invalid circuit: %s", lp.Title, lp.CircuitRef)
+		}
+		lp.circuit = c
+	}
+
+	return res, nil
+}
+
+// circuitLoad returns the phase currents of all circuits. Circuits without meter sum up the loadpoints of the circuit and its children.
+func (site *Site) circuitLoad(usage map[*LoadPoint]phaseCurrents) map[*circuit]phaseCurrents {
+	res := make(map[*circuit]phaseCurrents, len(site.circuits))
+	measured := make(map[*circuit]bool)
+
+	for _, c := range site.circuits {
+		if c.meter == nil {
+			continue
+		}
+
+		l1, l2, l3, err := c.meter.Currents()
+		if err != nil {
+			site.log.ERROR.Printf("circuit %s: %v", c.name, err)
+			continue
+		}
+
+		res[c] = phaseCurrents{l1, l2, l3}
+		measured[c] = true
+	}
+
+	for lp, u := range usage {
+		for c := lp.circuit; c != nil; c = c.parent {
+			if measured[c] {
+				continue
+			}
+
+			load := res[c]
+			for p := range load {
+				load[p] += u[p]
+			}
+			res[c] = load
+		}
+	}
+
+	return res
+}
+
+// updateCircuits applies the remaining per-phase current of all parent circuits to the loadpoints
+func (site *Site) updateCircuits() {
+	if len(site.circuits) == 0 {
+		return
+	}
+
+	usage := make(map[*LoadPoint]phaseCurrents)
+	for _, lp := range site.loadpoints {
+		if lp.circuit != nil {
+			usage[lp] = lp.circuitUsage()
+		}
+	}
+
+	load := site.circuitLoad(usage)
+	site.publishCircuits(load)
+
+	// loadpoints waiting for capacity
+	var queued map[*LoadPoint]bool
+	if site.queue != nil {
+		queued = site.queue.update(site.loadpoints, load, usage)
+		site.queue.publish(site.loadpoints, queued)
+	}
+
+	// allocate in priority order, earlier loadpoints' grants reduce the remaining current
+	loadpoints := slices.Clone(site.loadpoints)
+	sort.SliceStable(loadpoints, func(i, j int) bool {
+		return loadpoints[i].Priority > loadpoints[j].Priority
+	})
+
+	for _, lp := range loadpoints {
+		if lp.circuit == nil {
+			continue
+		}
+
+		if queued[lp] {
+			lp.setCircuitBudget(new(float64))
+			continue
+		}
+
+		phases := lp.circuitPhases(usage[lp])
+
+		// other consumers' load reduces the remaining current
+		budget := math.MaxFloat64
+		for c := lp.circuit; c != nil; c = c.parent {
+			var other phaseCurrents
+			for p := range other {
+				other[p] = load[c][p] - usage[lp][p]
+			}
+
+			for _, p := range phases {
+				budget = math.Min(budget, c.maxCurrent-other[p])
+			}
+
+			budget = math.Min(budget, c.unbalanceBudget(other, phases))
+		}
+
+		waiting := lp.waiting()
+		if site.queue != nil {
+			waiting = site.queue.waiting(lp)
+		}
+
+		// leave min current to the other admitted loadpoints
+		if site.queue != nil && waiting {
+			budget = math.Min(budget, site.queue.budget(lp, phases))
+		}
+
+		budget = math.Max(0, budget)
+		lp.setCircuitBudget(&budget)
+
+		// reserve the granted current on top of the current usage
+		if waiting && budget >= lp.GetMinCurrent() {
+			granted := math.Min(budget, lp.GetMaxCurrent())
+			for c := lp.circuit; c != nil; c = c.parent {
+				l := load[c]
+				for _, p := range phases {
+					l[p] += math.Max(0, granted-usage[lp][p])
+				}
+				load[c] = l
+			}
+		}
+	}
+}
+
+// publishCircuits logs and publishes the per-phase load of the circuits
+func (site *Site) publishCircuits(load map[*circuit]phaseCurrents) {
+	res := make([]state.Circuit, 0, len(site.circuits))
+
+	for _, c := range site.circuits {
+		l := load[c]
+		site.log.DEBUG.Printf("circuit %s: %.3gA of %.3gA", c.name, l, c.maxCurrent)
+
+		if c.maxUnbalance > 0 && l.unbalance() > c.maxUnbalance {
+			site.log.WARN.Printf("circuit %s: phase unbalance %.3gA exceeds %.3gA", c.name, l.unbalance(), c.maxUnbalance)
+		}
+
+		res = append(res, state.Circuit{
+			Name:       c.name,
+			Currents:   l,
+			MaxCurrent: c.maxCurrent,
+			Unbalance:  l.unbalance(),
+		})
+	}
+
+	site.publish(state.Circuits, res)
+}
+
+// unbalance returns the current difference between the highest and lowest phase
+func (pc phaseCurrents) unbalance() float64 {
+	return math.Max(pc[0], math.Max(pc[1], pc[2])) - math.Min(pc[0], math.Min(pc[1], pc[2]))
+}
+
+// unbalanceBudget returns the current the phases may draw on top of the other consumers' load
+// without exceeding the circuit's phase unbalance limit. Three phase charging is balanced.
+func (c *circuit) unbalanceBudget(other phaseCurrents, phases []int) float64 {
+	res := math.MaxFloat64
+	if c.maxUnbalance <= 0 || len(phases) >= 3 {
+		return res
+	}
+
+	for q := range other {
+		if slices.Contains(phases, q) {
+			continue
+		}
+
+		for _, p := range phases {
+			res = math.Min(res, c.maxUnbalance+other[q]-other[p])
+		}
+	}
+
+	return res
+}
+
+// circuitUsage returns the phase currents of the loadpoint. Without measured currents the
+// current limit is assumed on the active phases while charging.
+func (lp *LoadPoint) circuitUsage() phaseCurrents {
+	lp.Lock()
+	defer lp.Unlock()
+
+	var res phaseCurrents
+	if lp.chargeCurrents != nil {
+		copy(res[:], lp.chargeCurrents)
+		return res
+	}
+
+	if lp.enabled && lp.status == api.StatusC {
+		phases := lp.phases
+		if phases == 0 {
+			phases = 3
+		}
+		for p := 0; p < phases && p < 3; p++ {
+			res[p] = lp.chargeCurrent
+		}
+	}
+
+	return res
+}
+
+// circuitPhases returns the phases used by the loadpoint. Measured currents identify the phases
+// of single phase charging. Otherwise the phases are unknown and all phases are assumed, limiting
+// the loadpoint to the phase with the least remaining current.
+func (lp *LoadPoint) circuitPhases(usage phaseCurrents) []int {
+	lp.Lock()
+	measured := lp.chargeCurrents != nil
+	lp.Unlock()
+
+	var res []int
+	if measured {
+		for p, i := range usage {
+			if i > 1 {
+				res = append(res, p)
+			}
+		}
+	}
+
+	if len(res) == 0 {
+		res = []int{0, 1, 2}
+	}
+
+	return res
+}
+
+// setCircuitBudget sets the remaining per-phase current of the loadpoint's circuits
+func (lp *LoadPoint) setCircuitBudget(budget *float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.circuitBudget = budget
+}
+
+// circuitExceeded returns true if the circuits do not allow charging at min current
+func (lp *LoadPoint) circuitExceeded() bool {
+	return lp.circuitBudget != nil && *lp.circuitBudget < lp.GetMinCurrent()
+}
+
+// circuitCurrent limits the charge current to the remaining current of the loadpoint's circuits
+func (lp *LoadPoint) circuitCurrent(current float64) float64 {
+	if lp.circuitBudget == nil || current <= *lp.circuitBudget {
+		return current
+	}
+
+	maxCurrent := *lp.circuitBudget
+	if lp.circuitExceeded() {
+		maxCurrent = 0
+	}
+
+	lp.log.DEBUG.Printf("circuit %s: limits charge current to %.3gA", lp.circuit.name, maxCurrent)
+
+	return maxCurrent
+}
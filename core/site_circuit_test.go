@@ -0,0 +1,203 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type circuitMeter struct {
+	currents phaseCurrents
+}
+
+func (m *circuitMeter) Currents() (float64, float64, float64, error) {
+	return m.currents[0], m.currents[1], m.currents[2], nil
+}
+
+func TestNewCircuits(t *testing.T) {
+	lp := &LoadPoint{CircuitRef: "garage"}
+
+	res, err := newCircuits(nil, []CircuitConfig{
+		{Name: "main", MaxCurrent: 35},
+		{Name: "garage", Parent: "main", MaxCurrent: 20},
+	}, []*LoadPoint{lp})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, res[1], lp.circuit)
+	assert.Equal(t, res[0], lp.circuit.parent)
+
+	for _, cc := range [][]CircuitConfig{
+		{{Name: "main"}},
+		{{Name: "main", MaxCurrent: 35}, {Name: "main", MaxCurrent: 35}},
+		{{Name: "garage", Parent: "main", MaxCurrent: 20}},
+		{{Name: "a", Parent: "b", MaxCurrent: 20}, {Name: "b", Parent: "a", MaxCurrent: 20}},
+		{{Name: "main", MaxCurrent: 35}}, // loadpoint circuit missing
+	} {
+		_, err := newCircuits(nil, cc, []*LoadPoint{lp})
+		assert.Error(t, err, "%+v", cc)
+	}
+}
+
+func TestCircuitBudget(t *testing.T) {
+	meter := &circuitMeter{currents: phaseCurrents{30, 20, 20}}
+	main := &circuit{name: "main", maxCurrent: 35, meter: meter}
+	garage := &circuit{name: "garage", parent: main, maxCurrent: 20}
+
+	// charging on 3 phases
+	lp1 := &LoadPoint{
+		log:            util.NewLogger("lp1"),
+		MinCurrent:     6,
+		MaxCurrent:     16,
+		phases:         3,
+		enabled:        true,
+		chargeCurrents: []float64{10, 10, 10},
+		circuit:        garage,
+	}
+
+	// not charging
+	lp2 := &LoadPoint{
+		log:        util.NewLogger("lp2"),
+		MinCurrent: 6,
+		MaxCurrent: 16,
+		phases:     3,
+		circuit:    garage,
+	}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{main, garage},
+		loadpoints: []*LoadPoint{lp1, lp2},
+	}
+
+	site.updateCircuits()
+
+	// main fuse including house consumption on L1 limits lp1
+	assert.Equal(t, 15.0, *lp1.circuitBudget)
+	assert.Equal(t, 15.0, lp1.circuitCurrent(16))
+	assert.False(t, lp1.circuitExceeded())
+
+	// current granted to lp1 is not available to lp2
+	assert.Equal(t, 0.0, *lp2.circuitBudget)
+	assert.Equal(t, 0.0, lp2.circuitCurrent(16))
+	assert.True(t, lp2.circuitExceeded())
+
+	// garage subpanel limits both loadpoints
+	meter.currents = phaseCurrents{10, 10, 10}
+	site.updateCircuits()
+
+	assert.Equal(t, 20.0, *lp1.circuitBudget)
+	assert.Equal(t, 4.0, *lp2.circuitBudget)
+	assert.True(t, lp2.circuitExceeded())
+
+	// lp1 limited to min current leaves the remainder to lp2
+	lp1.MaxCurrent = 6
+	site.updateCircuits()
+
+	assert.Equal(t, 20.0, *lp1.circuitBudget)
+	assert.Equal(t, 10.0, *lp2.circuitBudget)
+	assert.Equal(t, 10.0, lp2.circuitCurrent(16))
+}
+
+func TestCircuitBudgetIdleLoadpoints(t *testing.T) {
+	main := &circuit{name: "main", maxCurrent: 32}
+
+	var lps []*LoadPoint
+	for _, name := range []string{"lp1", "lp2"} {
+		lps = append(lps, &LoadPoint{
+			log:        util.NewLogger(name),
+			MinCurrent: 6,
+			MaxCurrent: 32,
+			phases:     3,
+			status:     api.StatusB,
+			circuit:    main,
+		})
+	}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{main},
+		loadpoints: lps,
+	}
+
+	site.updateCircuits()
+
+	// both loadpoints starting together must not exceed the circuit
+	assert.Equal(t, 32.0, *lps[0].circuitBudget)
+	assert.Equal(t, 0.0, *lps[1].circuitBudget)
+}
+
+func TestCircuitUnbalance(t *testing.T) {
+	// household load of 4A on L2 and L3
+	meter := &circuitMeter{currents: phaseCurrents{16, 4, 4}}
+	main := &circuit{name: "main", maxCurrent: 35, maxUnbalance: 20, meter: meter}
+
+	// charging single phase on L1
+	lp := &LoadPoint{
+		log:            util.NewLogger("lp"),
+		MinCurrent:     6,
+		MaxCurrent:     32,
+		phases:         1,
+		enabled:        true,
+		chargeCurrents: []float64{16, 0, 0},
+		circuit:        main,
+	}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{main},
+		loadpoints: []*LoadPoint{lp},
+	}
+
+	site.updateCircuits()
+
+	// 20A unbalance on top of the other phases' load
+	assert.Equal(t, 24.0, *lp.circuitBudget)
+	assert.Equal(t, 24.0, lp.circuitCurrent(32))
+
+	// three phase charging is balanced
+	lp.phases = 3
+	lp.chargeCurrents = []float64{16, 16, 16}
+	meter.currents = phaseCurrents{16, 20, 20}
+	site.updateCircuits()
+
+	assert.Equal(t, 31.0, *lp.circuitBudget)
+	assert.Equal(t, 20.0, phaseCurrents{24, 4, 4}.unbalance())
+}
+
+func TestCircuitUnknownPhase(t *testing.T) {
+	// household load of 20A on L3
+	meter := &circuitMeter{currents: phaseCurrents{0, 0, 20}}
+	main := &circuit{name: "main", maxCurrent: 32, meter: meter}
+
+	// single phase loadpoint without measured currents may be connected to any phase
+	lp := &LoadPoint{
+		log:        util.NewLogger("lp"),
+		MinCurrent: 6,
+		MaxCurrent: 32,
+		phases:     1,
+		status:     api.StatusB,
+		circuit:    main,
+	}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{main},
+		loadpoints: []*LoadPoint{lp},
+	}
+
+	site.updateCircuits()
+
+	assert.Equal(t, 12.0, *lp.circuitBudget)
+
+	// measured currents identify the phase
+	lp.status = api.StatusC
+	lp.enabled = true
+	lp.chargeCurrents = []float64{10, 0, 0}
+	meter.currents = phaseCurrents{10, 0, 20}
+	site.updateCircuits()
+
+	assert.Equal(t, 32.0, *lp.circuitBudget)
+}
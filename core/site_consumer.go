@@ -0,0 +1,180 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// consumerMinRuntime is the default minimum on and off duration protecting heat pump compressors
+const consumerMinRuntime = 10 * time.Minute
+
+// ConsumerConfig defines a switchable consumer like an SG-Ready heat pump or heating rod
+type ConsumerConfig struct {
+	Title      string          `mapstructure:"title"`
+	Switch     provider.Config `mapstructure:"switch"`     // turns the consumer on (true) or back to normal operation (false), e.g. SG-Ready boost contact
+	MeterRef   string          `mapstructure:"meter"`      // optional meter measuring the consumer's power
+	Power      float64         `mapstructure:"power"`      // nominal power while switched on in W
+	Surplus    float64         `mapstructure:"surplus"`    // switch on above this pv surplus in W, defaults to power
+	Priority   int             `mapstructure:"priority"`   // consumers above 0 are supplied before pv mode loadpoints, others only from surplus left by the loadpoints
+	MinRuntime time.Duration   `mapstructure:"minRuntime"` // minimum on and off duration
+}
+
+// consumer is a smart consumer switched by pv surplus
+type consumer struct {
+	log        *util.Logger
+	clock      clock.Clock
+	title      string
+	switchS    func(bool) error
+	meter      api.Meter
+	power      float64
+	surplus    float64
+	priority   int
+	minRuntime time.Duration
+	on         bool
+	switched   time.Time
+}
+
+// newConsumers creates the smart consumers ordered by descending priority
+func newConsumers(log *util.Logger, cp configProvider, configs []ConsumerConfig) ([]*consumer, error) {
+	res := make([]*consumer, 0, len(configs))
+
+	for i, cc := range configs {
+		if cc.Title == "" {
+			cc.Title = fmt.Sprintf("consumer %d", i+1)
+		}
+
+		if cc.Power <= 0 {
+			return nil, fmt.Errorf("%s: missing power", cc.Title)
+		}
+
+		switchS, err := provider.NewBoolSetterFromConfig("switch", cc.Switch)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cc.Title, err)
+		}
+
+		c := &consumer{
+			log:        log,
+			clock:      clock.New(),
+			title:      cc.Title,
+			switchS:    switchS,
+			power:      cc.Power,
+			surplus:    cc.Surplus,
+			priority:   cc.Priority,
+			minRuntime: cc.MinRuntime,
+		}
+
+		if c.surplus == 0 {
+			c.surplus = c.power
+		}
+
+		if c.minRuntime == 0 {
+			c.minRuntime = consumerMinRuntime
+		}
+
+		if cc.MeterRef != "" {
+			if c.meter, err = cp.Meter(cc.MeterRef); err != nil {
+				return nil, fmt.Errorf("%s: %w", cc.Title, err)
+			}
+		}
+
+		res = append(res, c)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].priority > res[j].priority
+	})
+
+	return res, nil
+}
+
+// consumption returns the measured or nominal power of the consumer
+func (c *consumer) consumption() float64 {
+	if c.meter != nil {
+		power, err := c.meter.CurrentPower()
+		if err == nil {
+			return power
+		}
+
+		c.log.ERROR.Printf("%s: %v", c.title, err)
+	}
+
+	if c.on {
+		return c.power
+	}
+
+	return 0
+}
+
+// update switches the consumer depending on the available surplus and returns its expected consumption.
+// Switched on consumers remain on while the site is not importing.
+func (c *consumer) update(available, consumption float64) float64 {
+	on := available >= c.surplus
+	if c.on {
+		on = available >= 0
+	}
+
+	if on != c.on {
+		if remaining := c.minRuntime - c.clock.Since(c.switched); remaining > 0 && !c.switched.IsZero() {
+			c.log.DEBUG.Printf("%s: switch delay %v", c.title, remaining.Truncate(time.Second))
+			return consumption
+		}
+
+		if err := c.switchS(on); err != nil {
+			c.log.ERROR.Printf("%s: %v", c.title, err)
+			return consumption
+		}
+
+		c.log.DEBUG.Printf("%s: %s", c.title, status[on])
+		c.on = on
+		c.switched = c.clock.Now()
+
+		if on {
+			return c.power
+		}
+		return 0
+	}
+
+	return consumption
+}
+
+// updateConsumers switches the smart consumers by pv surplus.
+// Consumers of higher priority than the loadpoints may use the power currently charged in pv mode.
+func (site *Site) updateConsumers(sitePower float64) {
+	if len(site.consumers) == 0 {
+		return
+	}
+
+	var pvChargePower float64
+	for _, lp := range site.loadpoints {
+		if lp.GetMode() == api.ModePV {
+			pvChargePower += lp.GetChargePower()
+		}
+	}
+
+	surplus := -sitePower
+	res := make([]state.Consumer, 0, len(site.consumers))
+
+	for _, c := range site.consumers {
+		consumption := c.consumption()
+
+		available := surplus
+		if c.priority > 0 {
+			available += pvChargePower
+		}
+
+		// account for switching until the next measurement
+		expected := c.update(available, consumption)
+		surplus -= expected - consumption
+
+		res = append(res, state.Consumer{Title: c.title, On: c.on, Power: expected})
+	}
+
+	site.publish(state.Consumers, res)
+}
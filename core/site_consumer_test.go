@@ -0,0 +1,78 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateConsumers(t *testing.T) {
+	clck := clock.NewMock()
+
+	var heatpump, rod bool
+
+	newConsumer := func(title string, power float64, priority int, res *bool) *consumer {
+		return &consumer{
+			log:        util.NewLogger("foo"),
+			clock:      clck,
+			title:      title,
+			power:      power,
+			surplus:    power,
+			priority:   priority,
+			minRuntime: consumerMinRuntime,
+			switchS: func(on bool) error {
+				*res = on
+				return nil
+			},
+		}
+	}
+
+	lp := &LoadPoint{Mode: api.ModePV, chargePower: 4000}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		loadpoints: []*LoadPoint{lp},
+		consumers: []*consumer{
+			newConsumer("heatpump", 2000, 1, &heatpump),
+			newConsumer("rod", 3000, 0, &rod),
+		},
+	}
+
+	// heat pump has priority over pv charging
+	site.updateConsumers(500)
+	assert.True(t, heatpump)
+	assert.False(t, rod)
+
+	// rod only uses surplus left by the loadpoint
+	clck.Add(consumerMinRuntime)
+	site.updateConsumers(-4000)
+	assert.True(t, rod)
+
+	// importing, switch delay applies
+	clck.Add(time.Minute)
+	site.updateConsumers(500)
+	assert.True(t, rod)
+
+	clck.Add(consumerMinRuntime)
+	site.updateConsumers(500)
+	assert.False(t, rod)
+	assert.True(t, heatpump, "covered by pv charge power")
+
+	site.updateConsumers(5000)
+	assert.False(t, heatpump)
+
+	// surplus consumed by consumers switched on within the same cycle
+	lp.Mode = api.ModeNow
+	site.consumers = []*consumer{
+		newConsumer("heatpump", 2000, 1, &heatpump),
+		newConsumer("rod", 3000, 0, &rod),
+	}
+
+	site.updateConsumers(-4000)
+	assert.True(t, heatpump)
+	assert.False(t, rod)
+}
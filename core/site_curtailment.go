@@ -0,0 +1,142 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// frequencyHysteresis is the default frequency above threshold required for releasing curtailment
+const frequencyHysteresis = 0.1 // Hz
+
+// CurtailmentConfig defines the grid frequency or external signal for shedding charging load
+type CurtailmentConfig struct {
+	Frequency    *provider.Config `mapstructure:"frequency"`    // grid frequency in Hz
+	MinFrequency float64          `mapstructure:"minFrequency"` // shed load below this frequency
+	Hysteresis   float64          `mapstructure:"hysteresis"`   // frequency above threshold required for release
+	Signal       *provider.Config `mapstructure:"signal"`       // external signal, true to shed load
+}
+
+// curtailment sheds charging load on low grid frequency or external signal
+type curtailment struct {
+	log          *util.Logger
+	frequencyG   func() (float64, error)
+	signalG      func() (bool, error)
+	minFrequency float64
+	hysteresis   float64
+	active       bool
+}
+
+// newCurtailment creates curtailment from configuration
+func newCurtailment(log *util.Logger, cc *CurtailmentConfig) (*curtailment, error) {
+	if cc == nil {
+		return nil, nil
+	}
+
+	c := &curtailment{
+		log:          log,
+		minFrequency: cc.MinFrequency,
+		hysteresis:   cc.Hysteresis,
+	}
+
+	if c.hysteresis == 0 {
+		c.hysteresis = frequencyHysteresis
+	}
+
+	if cc.Frequency != nil {
+		if cc.MinFrequency <= 0 {
+			return nil, errors.New("curtailment: missing minFrequency")
+		}
+
+		var err error
+		if c.frequencyG, err = provider.NewFloatGetterFromConfig(*cc.Frequency); err != nil {
+			return nil, err
+		}
+	}
+
+	if cc.Signal != nil {
+		var err error
+		if c.signalG, err = provider.NewBoolGetterFromConfig(*cc.Signal); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.frequencyG == nil && c.signalG == nil {
+		return nil, errors.New("curtailment: missing frequency or signal")
+	}
+
+	return c, nil
+}
+
+// update evaluates frequency and signal and returns true while charging load must be shed.
+// The previous state is retained if a value cannot be read.
+func (c *curtailment) update() bool {
+	active := c.active
+	var frequencyLow, signal bool
+
+	if c.frequencyG != nil {
+		f, err := c.frequencyG()
+		if err != nil {
+			c.log.ERROR.Printf("curtailment frequency: %v", err)
+			return c.active
+		}
+
+		c.log.DEBUG.Printf("grid frequency: %.2fHz", f)
+		frequencyLow = f < c.minFrequency || c.active && f < c.minFrequency+c.hysteresis
+	}
+
+	if c.signalG != nil {
+		var err error
+		if signal, err = c.signalG(); err != nil {
+			c.log.ERROR.Printf("curtailment signal: %v", err)
+			return c.active
+		}
+	}
+
+	if c.active = frequencyLow || signal; c.active != active {
+		if c.active {
+			c.log.WARN.Println("curtailment active: shedding charging load")
+		} else {
+			c.log.INFO.Println("curtailment released")
+		}
+	}
+
+	return c.active
+}
+
+// updateCurtailment applies the curtailment state to all loadpoints
+func (site *Site) updateCurtailment() {
+	if site.curtailment == nil {
+		return
+	}
+
+	curtailed := site.curtailment.update()
+	site.publish(state.Curtailed, curtailed)
+
+	for _, lp := range site.loadpoints {
+		lp.setCurtailed(curtailed)
+	}
+}
+
+// setCurtailed sets the site curtailment state
+func (lp *LoadPoint) setCurtailed(curtailed bool) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.curtailed != curtailed {
+		lp.curtailed = curtailed
+		lp.publish(state.Curtailed, curtailed)
+	}
+}
+
+// curtailCurrent stops charging while the site is curtailed
+func (lp *LoadPoint) curtailCurrent(current float64) float64 {
+	if lp.curtailed {
+		lp.log.DEBUG.Println("curtailment: charging disabled")
+		return 0
+	}
+
+	return current
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurtailment(t *testing.T) {
+	var f float64
+	var signal bool
+	var err error
+
+	c := &curtailment{
+		log:          util.NewLogger("foo"),
+		frequencyG:   func() (float64, error) { return f, err },
+		signalG:      func() (bool, error) { return signal, nil },
+		minFrequency: 49.5,
+		hysteresis:   0.2,
+	}
+
+	tc := []struct {
+		f      float64
+		signal bool
+		err    error
+		active bool
+	}{
+		{50, false, nil, false},
+		{49.4, false, nil, true},
+		{49.6, false, nil, true}, // hysteresis
+		{50, false, errors.New("foo"), true},
+		{49.8, false, nil, false},
+		{50, true, nil, true},
+		{50, false, nil, false},
+	}
+
+	for _, tc := range tc {
+		f, signal, err = tc.f, tc.signal, tc.err
+		assert.Equal(t, tc.active, c.update(), "%+v", tc)
+	}
+
+	// loadpoint current
+	lp := &LoadPoint{log: util.NewLogger("foo")}
+	lp.setCurtailed(true)
+	assert.Equal(t, 0.0, lp.curtailCurrent(16))
+	lp.setCurtailed(false)
+	assert.Equal(t, 16.0, lp.curtailCurrent(16))
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// DemandResponseEvent is a load-shed event sent by the utility
+type DemandResponseEvent = state.DemandResponseEvent
+
+// demandResponse is the utility's current load-shed event
+type demandResponse struct {
+	clock    clock.Clock
+	event    *DemandResponseEvent
+	override bool // user overrides the event for emergency charging
+}
+
+// SetDemandResponse applies the utility's load-shed event, nil releases the event
+func (site *Site) SetDemandResponse(ev *DemandResponseEvent) {
+	site.Lock()
+	defer site.Unlock()
+
+	d := &site.demand
+
+	switch {
+	case ev == nil && d.event != nil:
+		site.log.INFO.Printf("demand response: event %s released", d.event.ID)
+
+	case ev != nil && (d.event == nil || *ev != *d.event):
+		site.log.WARN.Printf("demand response: event %s limits charge power to %.0fW from %s to %s (%s)",
+			ev.ID, ev.Limit, ev.Start.Local().Format("15:04"), ev.End.Local().Format("15:04"), ev.Reason)
+	}
+
+	// override applies to the current event only
+	if ev == nil || d.event == nil || ev.ID != d.event.ID {
+		d.override = false
+	}
+
+	d.event = ev
+}
+
+// GetDemandResponseOverride returns if the user overrides the load-shed event
+func (site *Site) GetDemandResponseOverride() bool {
+	site.Lock()
+	defer site.Unlock()
+	return site.demand.override
+}
+
+// SetDemandResponseOverride overrides the load-shed event for emergency charging
+func (site *Site) SetDemandResponseOverride(override bool) error {
+	site.Lock()
+	defer site.Unlock()
+
+	if override != site.demand.override {
+		site.log.WARN.Printf("demand response: override %t", override)
+	}
+
+	site.demand.override = override
+	site.publish(state.DemandResponseOverride, override)
+
+	return nil
+}
+
+// demandResponseLimit returns the total charge power limit while a load-shed event is active
+func (site *Site) demandResponseLimit() (float64, bool) {
+	site.Lock()
+	defer site.Unlock()
+
+	d := &site.demand
+
+	var active bool
+	if ev := d.event; ev != nil && !d.override {
+		now := d.clock.Now()
+		active = !now.Before(ev.Start) && now.Before(ev.End)
+	}
+
+	site.publish(state.DemandResponse, d.event)
+	site.publish(state.DemandResponseActive, active)
+	site.publish(state.DemandResponseOverride, d.override)
+
+	if !active {
+		return 0, false
+	}
+
+	return d.event.Limit, true
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemandResponse(t *testing.T) {
+	clck := clock.NewMock()
+
+	site := &Site{
+		log:    util.NewLogger("foo"),
+		demand: demandResponse{clock: clck},
+	}
+
+	_, active := site.demandResponseLimit()
+	assert.False(t, active)
+
+	ev := &DemandResponseEvent{ID: "ev1", Start: clck.Now().Add(time.Hour), End: clck.Now().Add(2 * time.Hour), Limit: 2000}
+	site.SetDemandResponse(ev)
+
+	// pending
+	_, active = site.demandResponseLimit()
+	assert.False(t, active)
+
+	clck.Add(time.Hour)
+	limit, active := site.demandResponseLimit()
+	assert.True(t, active)
+	assert.Equal(t, 2000.0, limit)
+
+	// emergency charging
+	assert.NoError(t, site.SetDemandResponseOverride(true))
+	_, active = site.demandResponseLimit()
+	assert.False(t, active)
+
+	// updated event keeps the override
+	updated := *ev
+	updated.Limit = 1000
+	site.SetDemandResponse(&updated)
+	assert.True(t, site.GetDemandResponseOverride())
+
+	// new event resets the override
+	next := updated
+	next.ID = "ev2"
+	site.SetDemandResponse(&next)
+	assert.False(t, site.GetDemandResponseOverride())
+
+	limit, active = site.demandResponseLimit()
+	assert.True(t, active)
+	assert.Equal(t, 1000.0, limit)
+
+	// event ends
+	clck.Add(time.Hour)
+	_, active = site.demandResponseLimit()
+	assert.False(t, active)
+}
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// updateForecast corrects the solar forecast by the actual pv power and publishes the surplus estimate
+func (site *Site) updateForecast(homePower float64) {
+	if site.forecast == nil {
+		return
+	}
+
+	if err := site.forecast.Update(site.pvPower, homePower); err != nil {
+		site.log.DEBUG.Printf("forecast: %v", err)
+		return
+	}
+
+	est, err := site.forecast.Estimate()
+	if err != nil {
+		return
+	}
+
+	site.publish(state.Forecast, state.SolarForecast{
+		Power:    est.Power,
+		Surplus:  est.Surplus,
+		Today:    est.Today,
+		Tomorrow: est.Tomorrow,
+	})
+}
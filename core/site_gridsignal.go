@@ -0,0 +1,179 @@
+package core
+
+import (
+	"errors"
+	"math"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	// gridSignalMaxPower is the default total charge power while load reduction is signalled, see §14a EnWG
+	gridSignalMaxPower = 4200 // W
+
+	// gridSignalLogSize is the number of retained load reduction events
+	gridSignalLogSize = 20
+)
+
+// GridSignalConfig defines the grid operator's load reduction signal, e.g. ripple control receiver
+type GridSignalConfig struct {
+	Signal   provider.Config  `mapstructure:"signal"`   // load reduction signal, true while active
+	MaxPower float64          `mapstructure:"maxPower"` // total charge power while active in W
+	Limit    *provider.Config `mapstructure:"limit"`    // optional power limit signalled by the grid operator in W
+}
+
+// GridSignalEvent is a load reduction period
+type GridSignalEvent = state.GridSignalEvent
+
+// gridSignal tracks the grid operator's load reduction signal
+type gridSignal struct {
+	log      *util.Logger
+	clock    clock.Clock
+	signalG  func() (bool, error)
+	limitG   func() (float64, error)
+	maxPower float64
+	active   bool
+	limit    float64
+	events   []GridSignalEvent
+}
+
+// newGridSignal creates the grid signal from configuration
+func newGridSignal(log *util.Logger, cc *GridSignalConfig) (*gridSignal, error) {
+	if cc == nil {
+		return nil, nil
+	}
+
+	if cc.Signal.Source == "" {
+		return nil, errors.New("grid signal: missing signal")
+	}
+
+	signalG, err := provider.NewBoolGetterFromConfig(cc.Signal)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &gridSignal{
+		log:      log,
+		clock:    clock.New(),
+		signalG:  signalG,
+		maxPower: cc.MaxPower,
+	}
+
+	if g.maxPower == 0 {
+		g.maxPower = gridSignalMaxPower
+	}
+
+	if cc.Limit != nil {
+		if g.limitG, err = provider.NewFloatGetterFromConfig(*cc.Limit); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// update reads the signal and returns true while load reduction is active.
+// The previous state is retained if the signal cannot be read.
+func (g *gridSignal) update() bool {
+	active, err := g.signalG()
+	if err != nil {
+		g.log.ERROR.Printf("grid signal: %v", err)
+		return g.active
+	}
+
+	limit, reason := g.maxPower, "grid operator signal"
+	if active && g.limitG != nil {
+		if l, err := g.limitG(); err == nil && l > 0 {
+			limit, reason = l, "grid operator limit"
+		} else if err != nil {
+			g.log.ERROR.Printf("grid signal limit: %v", err)
+		}
+	}
+
+	switch {
+	case active && !g.active:
+		g.log.WARN.Printf("grid signal: load reduction to %.0fW (%s)", limit, reason)
+		g.events = append(g.events, GridSignalEvent{Start: g.clock.Now(), Limit: limit, Reason: reason})
+		if len(g.events) > gridSignalLogSize {
+			g.events = g.events[len(g.events)-gridSignalLogSize:]
+		}
+
+	case !active && g.active:
+		g.log.INFO.Println("grid signal: load reduction released")
+		now := g.clock.Now()
+		g.events[len(g.events)-1].End = &now
+
+	case active && limit != g.limit:
+		g.log.WARN.Printf("grid signal: load reduction to %.0fW (%s)", limit, reason)
+		g.events[len(g.events)-1].Limit = limit
+		g.events[len(g.events)-1].Reason = reason
+	}
+
+	g.active, g.limit = active, limit
+
+	return g.active
+}
+
+// updateGridSignal applies the load reduction power budget of grid signal and demand response to all loadpoints
+func (site *Site) updateGridSignal(totalChargePower float64) {
+	// utility load-shed event
+	limit, active := site.demandResponseLimit()
+
+	if site.gridSignal != nil {
+		prev, prevLimit := site.gridSignal.active, site.gridSignal.limit
+		signal := site.gridSignal.update()
+		if site.events != nil && (signal != prev || signal && site.gridSignal.limit != prevLimit) {
+			site.events.Publish(nil, event.GridSignal{Active: signal, Limit: site.gridSignal.limit})
+		}
+		site.publish(state.GridSignal, signal)
+		site.publish(state.GridSignalLimit, site.gridSignal.limit)
+		site.publish(state.GridSignalLog, site.gridSignal.events)
+
+		if signal && (!active || site.gridSignal.limit < limit) {
+			limit, active = site.gridSignal.limit, true
+		}
+	}
+
+	for _, lp := range site.loadpoints {
+		if !active {
+			lp.setGridBudget(nil)
+			continue
+		}
+
+		// other loadpoints' consumption reduces the budget
+		budget := math.Max(0, limit-(totalChargePower-lp.GetChargePower()))
+		lp.setGridBudget(&budget)
+	}
+}
+
+// setGridBudget sets the load reduction power budget, nil while inactive
+func (lp *LoadPoint) setGridBudget(budget *float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.gridBudget = budget
+}
+
+// gridSignalCurrent limits the charge current to the load reduction power budget
+func (lp *LoadPoint) gridSignalCurrent(current float64) float64 {
+	if lp.gridBudget == nil {
+		return current
+	}
+
+	maxCurrent := powerToCurrent(*lp.gridBudget, lp.activePhases())
+	if current <= maxCurrent {
+		return current
+	}
+
+	if maxCurrent < lp.GetMinCurrent() {
+		maxCurrent = 0
+	}
+
+	lp.log.DEBUG.Printf("load reduction: %.0fW limits charge current to %.3gA", *lp.gridBudget, maxCurrent)
+
+	return maxCurrent
+}
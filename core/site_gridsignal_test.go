@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGridSignal(t *testing.T) {
+	clck := clock.NewMock()
+
+	var active bool
+	limit := -1.0
+
+	g := &gridSignal{
+		log:      util.NewLogger("foo"),
+		clock:    clck,
+		signalG:  func() (bool, error) { return active, nil },
+		limitG:   func() (float64, error) { return limit, nil },
+		maxPower: gridSignalMaxPower,
+	}
+
+	assert.False(t, g.update())
+	assert.Empty(t, g.events)
+
+	// signal without limit uses max power
+	active = true
+	start := clck.Now()
+	assert.True(t, g.update())
+	assert.Equal(t, float64(gridSignalMaxPower), g.limit)
+	require.Len(t, g.events, 1)
+	assert.Equal(t, start, g.events[0].Start)
+
+	// signalled limit updates the running event
+	limit = 6000
+	assert.True(t, g.update())
+	assert.Equal(t, 6000.0, g.limit)
+	assert.Equal(t, 6000.0, g.events[0].Limit)
+	assert.Equal(t, "grid operator limit", g.events[0].Reason)
+
+	// release
+	clck.Add(time.Hour)
+	active = false
+	assert.False(t, g.update())
+	require.Len(t, g.events, 1)
+	require.NotNil(t, g.events[0].End)
+	assert.Equal(t, time.Hour, g.events[0].End.Sub(g.events[0].Start))
+}
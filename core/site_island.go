@@ -0,0 +1,144 @@
+package core
+
+import (
+	"errors"
+	"math"
+
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// IslandConfig defines off-grid operation where charging is limited by inverter capacity and battery soc
+type IslandConfig struct {
+	Status   *provider.Config `mapstructure:"status"`   // inverter status, true while off-grid
+	MaxPower float64          `mapstructure:"maxPower"` // inverter capacity in W
+	MinSoC   float64          `mapstructure:"minSoC"`   // stop charging below this battery soc
+}
+
+// island tracks off-grid operation
+type island struct {
+	log      *util.Logger
+	statusG  func() (bool, error)
+	maxPower float64
+	minSoC   float64
+	active   bool
+}
+
+// newIsland creates island operation from configuration
+func newIsland(log *util.Logger, cc *IslandConfig) (*island, error) {
+	if cc == nil {
+		return nil, nil
+	}
+
+	if cc.Status == nil {
+		return nil, errors.New("island: missing status")
+	}
+
+	if cc.MaxPower <= 0 {
+		return nil, errors.New("island: missing maxPower")
+	}
+
+	statusG, err := provider.NewBoolGetterFromConfig(*cc.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &island{
+		log:      log,
+		statusG:  statusG,
+		maxPower: cc.MaxPower,
+		minSoC:   cc.MinSoC,
+	}
+
+	return i, nil
+}
+
+// update reads the inverter status and returns true while off-grid.
+// The previous state is retained if the status cannot be read.
+func (i *island) update() bool {
+	active, err := i.statusG()
+	if err != nil {
+		i.log.ERROR.Printf("island status: %v", err)
+		return i.active
+	}
+
+	if active != i.active {
+		if active {
+			i.log.WARN.Println("island operation: off-grid")
+		} else {
+			i.log.INFO.Println("island operation: grid restored")
+		}
+
+		i.active = active
+	}
+
+	return i.active
+}
+
+// budget returns the charging power available from the inverter after home consumption, zero below min soc
+func (i *island) budget(homePower, batterySoC float64) float64 {
+	if batterySoC < i.minSoC {
+		return 0
+	}
+
+	return math.Max(0, i.maxPower-homePower)
+}
+
+// updateIsland applies the off-grid charging power budget to all loadpoints
+func (site *Site) updateIsland(homePower, totalChargePower float64) {
+	if site.island == nil {
+		return
+	}
+
+	active := site.island.update()
+	site.publish(state.Island, active)
+
+	var budget float64
+	if active {
+		budget = site.island.budget(homePower, site.batterySoC)
+		site.log.DEBUG.Printf("island budget: %.0fW", budget)
+
+		// battery supplies the loadpoints above min soc
+		site.batteryBuffered = site.batterySoC >= site.island.minSoC
+	}
+
+	for _, lp := range site.loadpoints {
+		if !active {
+			lp.setIslandBudget(nil)
+			continue
+		}
+
+		// other loadpoints' consumption reduces the budget
+		lpBudget := math.Max(0, budget-(totalChargePower-lp.GetChargePower()))
+		lp.setIslandBudget(&lpBudget)
+	}
+}
+
+// setIslandBudget sets the off-grid charging power budget, nil while on-grid
+func (lp *LoadPoint) setIslandBudget(budget *float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.islandBudget = budget
+}
+
+// islandCurrent limits the charge current to the off-grid power budget
+func (lp *LoadPoint) islandCurrent(current float64) float64 {
+	if lp.islandBudget == nil {
+		return current
+	}
+
+	maxCurrent := powerToCurrent(*lp.islandBudget, lp.activePhases())
+	if current <= maxCurrent {
+		return current
+	}
+
+	if maxCurrent < lp.GetMinCurrent() {
+		maxCurrent = 0
+	}
+
+	lp.log.DEBUG.Printf("island budget: %.0fW limits charge current to %.3gA", *lp.islandBudget, maxCurrent)
+
+	return maxCurrent
+}
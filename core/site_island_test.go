@@ -0,0 +1,63 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsland(t *testing.T) {
+	var status bool
+	var err error
+
+	i := &island{
+		log:      util.NewLogger("foo"),
+		statusG:  func() (bool, error) { return status, err },
+		maxPower: 5000,
+		minSoC:   50,
+	}
+
+	assert.False(t, i.update())
+
+	status = true
+	assert.True(t, i.update())
+
+	// keep state on error
+	status, err = false, errors.New("foo")
+	assert.True(t, i.update())
+
+	tc := []struct {
+		home, soc, budget float64
+	}{
+		{1000, 80, 4000},
+		{6000, 80, 0},
+		{1000, 49, 0},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.budget, i.budget(tc.home, tc.soc), "%+v", tc)
+	}
+}
+
+func TestIslandCurrent(t *testing.T) {
+	Voltage = 230 // V
+
+	lp := &LoadPoint{
+		log:        util.NewLogger("foo"),
+		MinCurrent: 6,
+		MaxCurrent: 16,
+		phases:     1,
+	}
+
+	assert.Equal(t, 16.0, lp.islandCurrent(16))
+
+	budget := 2300.0
+	lp.setIslandBudget(&budget)
+	assert.Equal(t, 10.0, lp.islandCurrent(16))
+	assert.Equal(t, 8.0, lp.islandCurrent(8))
+
+	budget = 1000
+	assert.Equal(t, 0.0, lp.islandCurrent(16))
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// lint returns warnings for common misconfigurations of the site and its vehicles
+func (site *Site) lint() []string {
+	var res []string
+
+	for _, v := range site.GetVehicles() {
+		if v.Capacity() == 0 {
+			res = append(res, fmt.Sprintf("vehicle %s: capacity not configured, soc estimation and target charging are inaccurate", v.Title()))
+		}
+	}
+
+	return res
+}
+
+// publishLint logs the configuration warnings found at startup
+func (site *Site) publishLint() {
+	site.lintWarnings = site.lint()
+
+	for _, msg := range site.lintWarnings {
+		site.log.WARN.Printf("config: %s", msg)
+	}
+
+	site.publish(state.Validation, site.lintWarnings)
+}
+
+// lint returns warnings for common misconfigurations of the loadpoint
+func (lp *LoadPoint) lint() []string {
+	var res []string
+
+	if lp.MinCurrent > lp.MaxCurrent {
+		res = append(res, fmt.Sprintf("min current %.3gA exceeds max current %.3gA", lp.MinCurrent, lp.MaxCurrent))
+	}
+
+	if lp.MinCurrent <= 0 {
+		res = append(res, "min current not configured, charging cannot be controlled")
+	}
+
+	return res
+}
+
+// publishLint logs the loadpoint's configuration warnings found at startup
+func (lp *LoadPoint) publishLint() {
+	for _, msg := range lp.lint() {
+		lp.addValidation(msg)
+	}
+
+	lp.publish(state.Validation, lp.validation)
+}
+
+// addValidation logs and publishes a configuration warning
+func (lp *LoadPoint) addValidation(msg string) {
+	lp.log.WARN.Printf("config: %s", msg)
+	lp.validation = append(lp.validation, msg)
+	lp.publish(state.Validation, lp.validation)
+}
+
+// validatePhases warns once if more phases are measured than the fixed phase configuration allows
+func (lp *LoadPoint) validatePhases(measured int) {
+	if lp.phasesMismatch {
+		return
+	}
+
+	if _, ok := lp.charger.(api.PhaseSwitcher); ok {
+		return
+	}
+
+	if phases := lp.GetPhases(); phases > 0 && measured > phases {
+		lp.phasesMismatch = true
+		lp.addValidation(fmt.Sprintf("phases configured as %dp but %dp measured, check the phases setting", phases, measured))
+	}
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadpointLint(t *testing.T) {
+	lp := &LoadPoint{log: util.NewLogger("foo"), MinCurrent: 6, MaxCurrent: 16, phases: 1}
+	assert.Empty(t, lp.lint())
+
+	lp.MinCurrent = 20
+	assert.Len(t, lp.lint(), 1)
+
+	// measured phases exceeding fixed phases warn once
+	lp.validatePhases(1)
+	assert.Empty(t, lp.validation)
+
+	lp.validatePhases(3)
+	lp.validatePhases(3)
+	assert.Len(t, lp.validation, 1)
+}
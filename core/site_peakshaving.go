@@ -0,0 +1,146 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+)
+
+// peakShavingPeriod is the default demand charge averaging period
+const peakShavingPeriod = 15 * time.Minute
+
+// PeakShavingConfig defines the demand charge threshold of the average grid import
+type PeakShavingConfig struct {
+	Limit  float64       `mapstructure:"limit"`  // max average grid import in W
+	Period time.Duration `mapstructure:"period"` // rolling averaging period, defaults to 15m
+}
+
+// powerSample is a grid import measurement valid until the next sample
+type powerSample struct {
+	ts    time.Time
+	power float64
+}
+
+// peakShaving tracks the rolling average grid import
+type peakShaving struct {
+	log     *util.Logger
+	clock   clock.Clock
+	limit   float64
+	period  time.Duration
+	samples []powerSample
+	average float64
+}
+
+// newPeakShaving creates the peak shaving from configuration
+func newPeakShaving(log *util.Logger, cc *PeakShavingConfig) (*peakShaving, error) {
+	if cc == nil {
+		return nil, nil
+	}
+
+	if cc.Limit <= 0 {
+		return nil, errors.New("peak shaving: missing limit")
+	}
+
+	p := &peakShaving{
+		log:    log,
+		clock:  clock.New(),
+		limit:  cc.Limit,
+		period: cc.Period,
+	}
+
+	if p.period <= 0 {
+		p.period = peakShavingPeriod
+	}
+
+	return p, nil
+}
+
+// update records the grid import and returns the allowed grid import power.
+// Headroom below the limit in the current period allows importing more, exceeding it requires importing less.
+func (p *peakShaving) update(gridPower float64) float64 {
+	now := p.clock.Now()
+	p.samples = append(p.samples, powerSample{ts: now, power: math.Max(0, gridPower)})
+
+	// retain the sample valid at the start of the period
+	start := now.Add(-p.period)
+	for len(p.samples) > 1 && !p.samples[1].ts.After(start) {
+		p.samples = p.samples[1:]
+	}
+
+	var energy float64 // Ws
+	for i, s := range p.samples {
+		from, to := s.ts, now
+		if i+1 < len(p.samples) {
+			to = p.samples[i+1].ts
+		}
+		if from.Before(start) {
+			from = start
+		}
+		energy += s.power * to.Sub(from).Seconds()
+	}
+
+	// average over the measured part of the period
+	if elapsed := now.Sub(p.samples[0].ts); elapsed > 0 {
+		p.average = energy / math.Min(elapsed.Seconds(), p.period.Seconds())
+	} else {
+		p.average = math.Max(0, gridPower)
+	}
+
+	return math.Max(0, 2*p.limit-p.average)
+}
+
+// updatePeakShaving applies the demand charge threshold to all loadpoints
+func (site *Site) updatePeakShaving(totalChargePower float64) {
+	if site.peakShaving == nil {
+		return
+	}
+
+	allowed := site.peakShaving.update(site.gridPower)
+	site.publish(state.PeakShavingAverage, site.peakShaving.average)
+	site.publish(state.PeakShavingLimit, site.peakShaving.limit)
+
+	// grid import not caused by charging
+	base := site.gridPower - totalChargePower
+
+	if site.peakShaving.average > site.peakShaving.limit {
+		site.log.DEBUG.Printf("peak shaving: average grid import %.0fW exceeds %.0fW", site.peakShaving.average, site.peakShaving.limit)
+	}
+
+	for _, lp := range site.loadpoints {
+		// other loadpoints' consumption reduces the budget
+		budget := math.Max(0, allowed-base-(totalChargePower-lp.GetChargePower()))
+		lp.setPeakBudget(&budget)
+	}
+}
+
+// setPeakBudget sets the peak shaving power budget, nil if not configured
+func (lp *LoadPoint) setPeakBudget(budget *float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.peakBudget = budget
+}
+
+// peakShavingCurrent limits the charge current to the peak shaving power budget
+func (lp *LoadPoint) peakShavingCurrent(current float64) float64 {
+	if lp.peakBudget == nil {
+		return current
+	}
+
+	maxCurrent := powerToCurrent(*lp.peakBudget, lp.activePhases())
+	if current <= maxCurrent {
+		return current
+	}
+
+	if maxCurrent < lp.GetMinCurrent() {
+		maxCurrent = 0
+	}
+
+	lp.log.DEBUG.Printf("peak shaving: %.0fW limits charge current to %.3gA", *lp.peakBudget, maxCurrent)
+
+	return maxCurrent
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeakShaving(t *testing.T) {
+	clck := clock.NewMock()
+
+	p := &peakShaving{
+		log:    util.NewLogger("foo"),
+		clock:  clck,
+		limit:  10e3,
+		period: 15 * time.Minute,
+	}
+
+	// headroom allows importing above the limit
+	assert.Equal(t, 15e3, p.update(5e3))
+	assert.Equal(t, 5e3, p.average)
+
+	clck.Add(5 * time.Minute)
+	assert.Equal(t, 15e3, p.update(20e3))
+
+	// 5m at 5kW, 5m at 20kW
+	clck.Add(5 * time.Minute)
+	assert.Equal(t, 7.5e3, p.update(20e3))
+	assert.Equal(t, 12.5e3, p.average)
+
+	// first sample leaves the period: 5m at 5kW are replaced by 20kW
+	clck.Add(10 * time.Minute)
+	assert.Equal(t, 0.0, p.update(0))
+	assert.Equal(t, 20e3, p.average)
+	assert.Len(t, p.samples, 3)
+
+	// export does not count
+	clck.Add(15 * time.Minute)
+	assert.Equal(t, 20e3, p.update(-3e3))
+	assert.Equal(t, 0.0, p.average)
+}
+
+func TestPeakShavingCurrent(t *testing.T) {
+	Voltage = 230 // V
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.MinCurrent = minA
+	lp.phases = 3
+
+	assert.Equal(t, 16.0, lp.peakShavingCurrent(16))
+
+	budget := 230.0 * 3 * 10
+	lp.setPeakBudget(&budget)
+	assert.Equal(t, 10.0, lp.peakShavingCurrent(16))
+	assert.Equal(t, 8.0, lp.peakShavingCurrent(8))
+
+	// below min current
+	budget = 1000
+	assert.Equal(t, 0.0, lp.peakShavingCurrent(16))
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+)
+
+// queue strategies
+const (
+	queuePriority   = "priority"   // higher loadpoint priority preempts lower, first come first served within the same priority
+	queueRoundRobin = "roundrobin" // charging loadpoints yield after their time slice while others are waiting
+)
+
+// queueSlice is the default round-robin time slice
+const queueSlice = 30 * time.Minute
+
+// QueueConfig defines how loadpoints are queued when their circuits cannot serve all connected vehicles at min current
+type QueueConfig struct {
+	Strategy string        `mapstructure:"strategy"` // priority or roundrobin
+	Slice    time.Duration `mapstructure:"slice"`    // round-robin time slice, max idle time of admitted loadpoints
+}
+
+// queue orders the loadpoints waiting for circuit capacity
+type queue struct {
+	clock    clock.Clock
+	strategy string
+	slice    time.Duration
+	order    []*LoadPoint               // waiting loadpoints by arrival or rotation
+	admitted map[*LoadPoint]time.Time   // loadpoints allowed to charge since
+	drawn    map[*LoadPoint]time.Time   // loadpoints last drawing current
+	idle     map[*LoadPoint]bool        // admitted loadpoints not drawing current within the slice
+	reserved map[*circuit]phaseCurrents // other consumers and min current of admitted loadpoints
+	position map[*LoadPoint]int         // published queue position
+}
+
+// newQueue creates the loadpoint queue from configuration
+func newQueue(cc *QueueConfig) (*queue, error) {
+	if cc == nil {
+		return nil, nil
+	}
+
+	q := &queue{
+		clock:    clock.New(),
+		strategy: strings.ToLower(cc.Strategy),
+		slice:    cc.Slice,
+		admitted: make(map[*LoadPoint]time.Time),
+		drawn:    make(map[*LoadPoint]time.Time),
+		idle:     make(map[*LoadPoint]bool),
+		position: make(map[*LoadPoint]int),
+	}
+
+	switch q.strategy {
+	case "":
+		q.strategy = queuePriority
+	case queuePriority, queueRoundRobin:
+	default:
+		return nil, fmt.Errorf("queue: invalid strategy: %s", cc.Strategy)
+	}
+
+	if q.slice == 0 {
+		q.slice = queueSlice
+	}
+
+	return q, nil
+}
+
+// waiting returns true if the loadpoint's vehicle requests charging and has not reached its target
+func (lp *LoadPoint) waiting() bool {
+	return lp.circuit != nil && lp.GetStatus() != api.StatusA && lp.GetMode() != api.ModeOff &&
+		!lp.targetEnergyReached() && !lp.targetSocReached()
+}
+
+// waiting returns true if the loadpoint requests charging and has drawn current within the slice after admission
+func (q *queue) waiting(lp *LoadPoint) bool {
+	return lp.waiting() && !q.idle[lp]
+}
+
+// updateIdle marks admitted loadpoints as idle if they have not drawn current within the slice.
+// Idle loadpoints release their reservation until they draw current again.
+func (q *queue) updateIdle(loadpoints []*LoadPoint, usage map[*LoadPoint]phaseCurrents) {
+	now := q.clock.Now()
+
+	for _, lp := range loadpoints {
+		if !lp.waiting() {
+			delete(q.drawn, lp)
+			delete(q.idle, lp)
+			continue
+		}
+
+		if u := usage[lp]; math.Max(u[0], math.Max(u[1], u[2])) > 1 {
+			q.drawn[lp] = now
+			delete(q.idle, lp)
+			continue
+		}
+
+		since, ok := q.admitted[lp]
+		if !ok {
+			continue
+		}
+
+		if drawn, ok := q.drawn[lp]; ok && drawn.After(since) {
+			since = drawn
+		}
+
+		if !q.idle[lp] && now.Sub(since) >= q.slice {
+			lp.log.DEBUG.Printf("queue: no current drawn for %v, releasing reservation", q.slice)
+			q.idle[lp] = true
+		}
+	}
+}
+
+// update tracks the waiting loadpoints and returns the loadpoints that have to wait for circuit capacity.
+// Loadpoints are admitted in queue order as long as all their circuits can supply min current.
+func (q *queue) update(loadpoints []*LoadPoint, load map[*circuit]phaseCurrents, usage map[*LoadPoint]phaseCurrents) map[*LoadPoint]bool {
+	q.updateIdle(loadpoints, usage)
+
+	waiting := make(map[*LoadPoint]bool)
+	for _, lp := range loadpoints {
+		if q.waiting(lp) {
+			waiting[lp] = true
+		}
+	}
+
+	// remove disconnected, append arrived loadpoints
+	order := q.order[:0]
+	for _, lp := range q.order {
+		if waiting[lp] {
+			order = append(order, lp)
+			delete(waiting, lp)
+		} else {
+			delete(q.admitted, lp)
+		}
+	}
+	for _, lp := range loadpoints {
+		if waiting[lp] {
+			order = append(order, lp)
+		}
+	}
+	q.order = order
+
+	if q.strategy == queuePriority {
+		sort.SliceStable(q.order, func(i, j int) bool {
+			return q.order[i].Priority > q.order[j].Priority
+		})
+	}
+
+	queued := q.admit(load, usage)
+
+	// charging loadpoints yield after their time slice
+	if q.strategy == queueRoundRobin && len(queued) > 0 {
+		now := q.clock.Now()
+
+		var rotated bool
+		for i := 0; i < len(q.order); i++ {
+			lp := q.order[i]
+			if since, ok := q.admitted[lp]; ok && now.Sub(since) >= q.slice && i < len(q.order)-1 {
+				q.order = append(append(q.order[:i:i], q.order[i+1:]...), lp)
+				delete(q.admitted, lp)
+				rotated = true
+				i--
+			}
+		}
+
+		if rotated {
+			queued = q.admit(load, usage)
+		}
+	}
+
+	return queued
+}
+
+// admit reserves min current for the loadpoints in queue order and returns the loadpoints exceeding the circuits
+func (q *queue) admit(load map[*circuit]phaseCurrents, usage map[*LoadPoint]phaseCurrents) map[*LoadPoint]bool {
+	// consumption of other consumers in measured circuits
+	reserved := make(map[*circuit]phaseCurrents, len(load))
+	for c, l := range load {
+		reserved[c] = l
+	}
+	for lp, u := range usage {
+		for c := lp.circuit; c != nil; c = c.parent {
+			r := reserved[c]
+			for p := range r {
+				r[p] -= u[p]
+			}
+			reserved[c] = r
+		}
+	}
+
+	now := q.clock.Now()
+	queued := make(map[*LoadPoint]bool)
+
+	for _, lp := range q.order {
+		minCurrent := lp.GetMinCurrent()
+		phases := lp.circuitPhases(usage[lp])
+
+		fits := true
+		for c := lp.circuit; c != nil && fits; c = c.parent {
+			for _, p := range phases {
+				if reserved[c][p]+minCurrent > c.maxCurrent {
+					fits = false
+				}
+			}
+		}
+
+		if !fits {
+			queued[lp] = true
+			delete(q.admitted, lp)
+			continue
+		}
+
+		if _, ok := q.admitted[lp]; !ok {
+			q.admitted[lp] = now
+		}
+
+		for c := lp.circuit; c != nil; c = c.parent {
+			r := reserved[c]
+			for _, p := range phases {
+				r[p] += minCurrent
+			}
+			reserved[c] = r
+		}
+	}
+
+	q.reserved = reserved
+
+	return queued
+}
+
+// budget returns the per-phase current available to the admitted loadpoint while leaving min current to all other admitted loadpoints
+func (q *queue) budget(lp *LoadPoint, phases []int) float64 {
+	minCurrent := lp.GetMinCurrent()
+
+	res := math.MaxFloat64
+	for c := lp.circuit; c != nil; c = c.parent {
+		for _, p := range phases {
+			res = math.Min(res, c.maxCurrent-(q.reserved[c][p]-minCurrent))
+		}
+	}
+
+	return res
+}
+
+// publish publishes the queue position of all loadpoints, 0 if not queued
+func (q *queue) publish(loadpoints []*LoadPoint, queued map[*LoadPoint]bool) {
+	var pos int
+	positions := make(map[*LoadPoint]int)
+	for _, lp := range q.order {
+		if queued[lp] {
+			pos++
+			positions[lp] = pos
+		}
+	}
+
+	for _, lp := range loadpoints {
+		if lp.circuit == nil {
+			continue
+		}
+
+		if positions[lp] != q.position[lp] {
+			if positions[lp] > 0 {
+				lp.log.INFO.Printf("queue: waiting for circuit capacity at position %d", positions[lp])
+			} else if q.position[lp] > 0 {
+				lp.log.INFO.Println("queue: circuit capacity available")
+			}
+		}
+
+		q.position[lp] = positions[lp]
+		lp.publish(state.QueuePosition, positions[lp])
+	}
+}
@@ -0,0 +1,190 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queueLoadpoint(name string, priority int, c *circuit) *LoadPoint {
+	return &LoadPoint{
+		log:        util.NewLogger(name),
+		MinCurrent: 6,
+		MaxCurrent: 16,
+		Priority:   priority,
+		Mode:       api.ModePV,
+		status:     api.StatusB,
+		phases:     3,
+		circuit:    c,
+	}
+}
+
+func TestNewQueue(t *testing.T) {
+	q, err := newQueue(nil)
+	require.NoError(t, err)
+	assert.Nil(t, q)
+
+	q, err = newQueue(&QueueConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, queuePriority, q.strategy)
+	assert.Equal(t, queueSlice, q.slice)
+
+	_, err = newQueue(&QueueConfig{Strategy: "foo"})
+	assert.Error(t, err)
+}
+
+func TestQueuePriority(t *testing.T) {
+	garage := &circuit{name: "garage", maxCurrent: 10}
+
+	lp1 := queueLoadpoint("lp1", 0, garage)
+	lp2 := queueLoadpoint("lp2", 0, garage)
+
+	q, err := newQueue(&QueueConfig{Strategy: queuePriority})
+	require.NoError(t, err)
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{garage},
+		loadpoints: []*LoadPoint{lp1, lp2},
+		queue:      q,
+	}
+
+	// first come first served
+	site.updateCircuits()
+
+	assert.Equal(t, 10.0, *lp1.circuitBudget)
+	assert.Equal(t, 0.0, *lp2.circuitBudget)
+	assert.Equal(t, 0, q.position[lp1])
+	assert.Equal(t, 1, q.position[lp2])
+
+	// higher priority preempts
+	lp2.Priority = 1
+	site.updateCircuits()
+
+	assert.Equal(t, 0.0, *lp1.circuitBudget)
+	assert.Equal(t, 10.0, *lp2.circuitBudget)
+	assert.Equal(t, 1, q.position[lp1])
+	assert.Equal(t, 0, q.position[lp2])
+
+	// disconnect releases capacity
+	lp2.status = api.StatusA
+	site.updateCircuits()
+
+	assert.Equal(t, 10.0, *lp1.circuitBudget)
+	assert.Equal(t, 0, q.position[lp1])
+}
+
+func TestQueueMinCurrent(t *testing.T) {
+	garage := &circuit{name: "garage", maxCurrent: 16}
+
+	lp1 := queueLoadpoint("lp1", 0, garage)
+	lp2 := queueLoadpoint("lp2", 0, garage)
+	lp3 := queueLoadpoint("lp3", 0, garage)
+
+	q, err := newQueue(&QueueConfig{})
+	require.NoError(t, err)
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{garage},
+		loadpoints: []*LoadPoint{lp1, lp2, lp3},
+		queue:      q,
+	}
+
+	site.updateCircuits()
+
+	// min current is reserved for the other admitted loadpoint, which receives the remainder
+	assert.Equal(t, 10.0, *lp1.circuitBudget)
+	assert.Equal(t, 6.0, *lp2.circuitBudget)
+	assert.Equal(t, 0.0, *lp3.circuitBudget)
+	assert.Equal(t, 1, q.position[lp3])
+}
+
+func TestQueueRoundRobin(t *testing.T) {
+	garage := &circuit{name: "garage", maxCurrent: 10}
+
+	lp1 := queueLoadpoint("lp1", 0, garage)
+	lp2 := queueLoadpoint("lp2", 0, garage)
+
+	clock := clock.NewMock()
+	q, err := newQueue(&QueueConfig{Strategy: queueRoundRobin, Slice: time.Hour})
+	require.NoError(t, err)
+	q.clock = clock
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{garage},
+		loadpoints: []*LoadPoint{lp1, lp2},
+		queue:      q,
+	}
+
+	site.updateCircuits()
+
+	assert.Equal(t, 10.0, *lp1.circuitBudget)
+	assert.Equal(t, 0.0, *lp2.circuitBudget)
+
+	// within slice
+	clock.Add(30 * time.Minute)
+	lp1.chargeCurrents = []float64{10, 10, 10}
+	site.updateCircuits()
+
+	assert.Equal(t, 10.0, *lp1.circuitBudget)
+	assert.Equal(t, 0.0, *lp2.circuitBudget)
+
+	// slice elapsed
+	clock.Add(30 * time.Minute)
+	lp1.chargeCurrents = nil
+	site.updateCircuits()
+
+	assert.Equal(t, 0.0, *lp1.circuitBudget)
+	assert.Equal(t, 10.0, *lp2.circuitBudget)
+	assert.Equal(t, 1, q.position[lp1])
+	assert.Equal(t, 0, q.position[lp2])
+
+	// single loadpoint keeps charging
+	lp1.status = api.StatusA
+	clock.Add(2 * time.Hour)
+	site.updateCircuits()
+
+	assert.Equal(t, 10.0, *lp2.circuitBudget)
+}
+
+func TestQueueIdle(t *testing.T) {
+	garage := &circuit{name: "garage", maxCurrent: 10}
+
+	lp1 := queueLoadpoint("lp1", 0, garage)
+	lp2 := queueLoadpoint("lp2", 0, garage)
+
+	clock := clock.NewMock()
+	q, err := newQueue(&QueueConfig{})
+	require.NoError(t, err)
+	q.clock = clock
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		circuits:   []*circuit{garage},
+		loadpoints: []*LoadPoint{lp1, lp2},
+		queue:      q,
+	}
+
+	site.updateCircuits()
+	assert.Equal(t, 1, q.position[lp2])
+
+	// admitted loadpoint not drawing current within the slice releases its reservation
+	clock.Add(queueSlice)
+	site.updateCircuits()
+
+	assert.True(t, q.idle[lp1])
+	assert.Equal(t, 10.0, *lp2.circuitBudget)
+	assert.Equal(t, 0, q.position[lp2])
+
+	// loadpoint at its target does not wait
+	lp2.targetEnergy = 5
+	lp2.chargedEnergy = 5e3
+	assert.False(t, lp2.waiting())
+}
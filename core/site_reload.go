@@ -0,0 +1,146 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// DeviceReplacement maps running devices to their reconfigured instances
+type DeviceReplacement struct {
+	Meters   map[api.Meter]api.Meter
+	Chargers map[api.Charger]api.Charger
+	Vehicles map[api.Vehicle]api.Vehicle
+}
+
+// chargerCapabilities are the optional charger interfaces evaluated when creating the loadpoint
+var chargerCapabilities = []func(api.Charger) bool{
+	func(c api.Charger) bool { _, ok := c.(api.Meter); return ok },
+	func(c api.Charger) bool { _, ok := c.(api.ChargeRater); return ok },
+	func(c api.Charger) bool { _, ok := c.(api.ChargeTimer); return ok },
+	func(c api.Charger) bool { _, ok := c.(api.PhaseSwitcher); return ok },
+}
+
+// ReplaceDevices replaces reconfigured devices of the site and its loadpoints between update cycles.
+// It must only be called while the site is running.
+func (site *Site) ReplaceDevices(r DeviceReplacement) error {
+	errC := make(chan error, 1)
+	site.replaceChan <- func() {
+		errC <- site.replaceDevices(r)
+	}
+	return <-errC
+}
+
+// validateReplacement ensures that reconfigured devices can be used by the existing site configuration
+func (site *Site) validateReplacement(r DeviceReplacement) error {
+	for old, new := range r.Chargers {
+		for _, capable := range chargerCapabilities {
+			if capable(old) != capable(new) {
+				return fmt.Errorf("charger capabilities changed (%T -> %T), restart required", old, new)
+			}
+		}
+	}
+
+	for _, c := range site.circuits {
+		if c.meter == nil {
+			continue
+		}
+
+		if new, ok := r.Meters[c.meter.(api.Meter)]; ok {
+			if _, ok := new.(api.MeterCurrent); !ok {
+				return fmt.Errorf("circuit %s: meter does not provide phase currents", c.name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (site *Site) replaceDevices(r DeviceReplacement) error {
+	if err := site.validateReplacement(r); err != nil {
+		return err
+	}
+
+	meter := func(m api.Meter) api.Meter {
+		if new, ok := r.Meters[m]; ok {
+			return new
+		}
+		return m
+	}
+
+	site.gridMeter = meter(site.gridMeter)
+	site.gridFallback = meter(site.gridFallback)
+	for i, m := range site.pvMeters {
+		site.pvMeters[i] = meter(m)
+	}
+	for i, m := range site.batteryMeters {
+		site.batteryMeters[i] = meter(m)
+	}
+	for _, vm := range site.virtualMeters {
+		for i, t := range vm.terms {
+			vm.terms[i].meter = meter(t.meter)
+		}
+	}
+	for _, c := range site.circuits {
+		if c.meter != nil {
+			c.meter = meter(c.meter.(api.Meter)).(api.MeterCurrent)
+		}
+	}
+
+	for old, new := range r.Vehicles {
+		site.coordinator.Replace(old, new)
+	}
+
+	for _, lp := range site.loadpoints {
+		lp.replaceDevices(r, meter)
+	}
+
+	return nil
+}
+
+// replaceDevices replaces reconfigured devices of the loadpoint including the charger's derived meter, rater and timer
+func (lp *LoadPoint) replaceDevices(r DeviceReplacement, meter func(api.Meter) api.Meter) {
+	lp.Lock()
+
+	if lp.chargeMeter != nil {
+		lp.chargeMeter = meter(lp.chargeMeter)
+	}
+
+	if new, ok := r.Chargers[lp.charger]; ok {
+		old := lp.charger
+		lp.charger = new
+
+		if m, ok := old.(api.Meter); ok && lp.chargeMeter == m {
+			lp.chargeMeter = new.(api.Meter)
+		}
+		if rt, ok := old.(api.ChargeRater); ok && lp.chargeRater == rt {
+			lp.chargeRater = new.(api.ChargeRater)
+		}
+		if ct, ok := old.(api.ChargeTimer); ok && lp.chargeTimer == ct {
+			lp.chargeTimer = new.(api.ChargeTimer)
+		}
+
+		if lp.vehicle != nil {
+			lp.socEstimator = lp.newSoCEstimator(new, lp.vehicle)
+			lp.applyVehicleProfile()
+		}
+	}
+
+	if new, ok := r.Vehicles[lp.defaultVehicle]; ok {
+		lp.defaultVehicle = new
+	}
+
+	for i, a := range lp.authorizations {
+		if new, ok := r.Vehicles[a.vehicle]; ok {
+			lp.authorizations[i].vehicle = new
+		}
+	}
+
+	vehicle, replaced := r.Vehicles[lp.vehicle]
+	lp.Unlock()
+
+	// re-apply active vehicle settings
+	if replaced {
+		lp.setActiveVehicle(vehicle)
+	}
+}
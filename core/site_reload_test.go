@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadCharger struct {
+	api.Charger
+	api.Meter
+}
+
+func TestReplaceDevices(t *testing.T) {
+	oldCharger := &reloadCharger{}
+
+	lp := &LoadPoint{
+		log:         util.NewLogger("foo"),
+		charger:     oldCharger,
+		chargeMeter: oldCharger,
+	}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		gridMeter:  &reloadCharger{},
+		loadpoints: []*LoadPoint{lp},
+	}
+
+	newGrid := &reloadCharger{}
+	newCharger := &reloadCharger{}
+
+	err := site.replaceDevices(DeviceReplacement{
+		Meters:   map[api.Meter]api.Meter{site.gridMeter: newGrid},
+		Chargers: map[api.Charger]api.Charger{oldCharger: newCharger},
+	})
+	assert.NoError(t, err)
+	assert.True(t, site.gridMeter == api.Meter(newGrid))
+	assert.True(t, lp.charger == api.Charger(newCharger))
+	assert.True(t, lp.chargeMeter == api.Meter(newCharger), "integrated charge meter")
+
+	// capabilities must not change
+	err = site.replaceDevices(DeviceReplacement{
+		Chargers: map[api.Charger]api.Charger{newCharger: &struct{ api.Charger }{}},
+	})
+	assert.Error(t, err)
+	assert.True(t, lp.charger == api.Charger(newCharger))
+}
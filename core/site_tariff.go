@@ -0,0 +1,43 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// publishTariff publishes the tariff's current price and its upcoming rates when changed
+func (site *Site) publishTariff(key string, tariff api.Tariff, published *api.Rates) {
+	if tariff == nil {
+		return
+	}
+
+	if price, err := tariff.CurrentPrice(); err == nil {
+		site.publish(key, price)
+	} else {
+		site.log.ERROR.Printf("%s tariff: %v", key, err)
+	}
+
+	rater, ok := tariff.(api.Rater)
+	if !ok {
+		return
+	}
+
+	rates, err := rater.Rates()
+	if err != nil {
+		site.log.ERROR.Printf("%s rates: %v", key, err)
+		return
+	}
+
+	if !slices.EqualFunc(rates, *published, func(a, b api.Rate) bool {
+		return a.Start.Equal(b.Start) && a.End.Equal(b.End) && a.Price == b.Price
+	}) {
+		*published = rates
+		site.publish(key+"Rates", rates)
+	}
+}
+
+// publishTariffs publishes grid and feed-in tariffs
+func (site *Site) publishTariffs() {
+	site.publishTariff("tariffGrid", site.tariffs.Grid, &site.gridRates)
+	site.publishTariff("tariffFeedIn", site.tariffs.FeedIn, &site.feedInRates)
+}
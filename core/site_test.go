@@ -1,9 +1,17 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/mock"
 	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSitePower(t *testing.T) {
@@ -30,3 +38,110 @@ func TestSitePower(t *testing.T) {
 		}
 	}
 }
+
+type virtualProvider map[string]api.Meter
+
+func (p virtualProvider) Meter(name string) (api.Meter, error) {
+	if m, ok := p[name]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("invalid meter: %s", name)
+}
+
+func (p virtualProvider) Charger(name string) (api.Charger, error) {
+	return nil, fmt.Errorf("invalid charger: %s", name)
+}
+
+func (p virtualProvider) Vehicle(name string) (api.Vehicle, error) {
+	return nil, fmt.Errorf("invalid vehicle: %s", name)
+}
+
+func TestVirtualMeters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	heatpump := mock.NewMockMeter(ctrl)
+	heatpump.EXPECT().CurrentPower().Return(1500.0, nil)
+
+	minus := -1.0
+	cp := virtualProvider{"heatpump": heatpump}
+
+	vms, err := newVirtualMeters(cp, []VirtualMeterConfig{
+		{Name: "consumption", Add: []VirtualTermConfig{{Source: "grid"}, {Source: "pv"}, {Source: "charge", Scale: &minus}}},
+		{Name: "other", Add: []VirtualTermConfig{{Source: "home"}, {Meter: "heatpump", Scale: &minus}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, vms, 2)
+
+	m := siteMeasurements{grid: 1000, pv: 4000, charge: 3000, home: 2000}
+
+	res, err := vms[0].power(m)
+	require.NoError(t, err)
+	assert.Equal(t, 2000.0, res)
+
+	res, err = vms[1].power(m)
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, res)
+
+	for _, cc := range [][]VirtualMeterConfig{
+		{{Add: []VirtualTermConfig{{Source: "grid"}}}},
+		{{Name: "foo"}},
+		{{Name: "foo", Add: []VirtualTermConfig{{Source: "foo"}}}},
+		{{Name: "foo", Add: []VirtualTermConfig{{Source: "grid", Meter: "heatpump"}}}},
+		{{Name: "foo", Add: []VirtualTermConfig{{Meter: "foo"}}}},
+		{{Name: "foo", Add: []VirtualTermConfig{{Source: "grid"}}}, {Name: "foo", Add: []VirtualTermConfig{{Source: "pv"}}}},
+	} {
+		_, err := newVirtualMeters(cp, cc)
+		assert.Error(t, err, cc)
+	}
+}
+
+func TestGridFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	primary := mock.NewMockMeter(ctrl)
+	fallback := mock.NewMockMeter(ctrl)
+
+	site := &Site{
+		log:          util.NewLogger("foo"),
+		gridMeter:    primary,
+		gridFallback: fallback,
+	}
+
+	primary.EXPECT().CurrentPower().Return(100.0, nil)
+	require.NoError(t, site.updateMeters())
+	assert.Equal(t, 100.0, site.gridPower)
+	assert.Equal(t, "primary", site.gridMeterUsed)
+
+	// primary stale
+	primary.EXPECT().CurrentPower().Return(0.0, errors.New("outdated")).Times(3)
+	fallback.EXPECT().CurrentPower().Return(200.0, nil)
+	require.NoError(t, site.updateMeters())
+	assert.Equal(t, 200.0, site.gridPower)
+	assert.Equal(t, "fallback", site.gridMeterUsed)
+
+	// primary restored
+	primary.EXPECT().CurrentPower().Return(300.0, nil)
+	require.NoError(t, site.updateMeters())
+	assert.Equal(t, 300.0, site.gridPower)
+	assert.Equal(t, "primary", site.gridMeterUsed)
+}
+
+func TestPvStrings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	east := mock.NewMockMeter(ctrl)
+	west := mock.NewMockMeter(ctrl)
+
+	site := &Site{
+		log:      util.NewLogger("foo"),
+		pvMeters: []api.Meter{east, west},
+		pvTitles: []string{"east", "west"},
+	}
+
+	east.EXPECT().CurrentPower().Return(1200.0, nil)
+	west.EXPECT().CurrentPower().Return(-10.0, nil)
+
+	require.NoError(t, site.updateMeters())
+	assert.Equal(t, 1200.0, site.pvPower)
+	assert.Equal(t, []state.PvString{{Title: "east", Power: 1200}, {Title: "west", Power: 0}}, site.pvStrings)
+}
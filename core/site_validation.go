@@ -0,0 +1,138 @@
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/state"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	validationSamples   = 10 // site updates evaluated by the startup meter validation
+	validationThreshold = 50 // W, tolerance for measurement noise and self-consumption
+)
+
+// validationSample is the raw site measurement of a single update
+type validationSample struct {
+	grid, pv, battery, charge float64
+}
+
+// meterValidation cross-checks the configured meters during the first site updates
+type meterValidation struct {
+	grid, pv, battery bool // configured meters
+	samples           []validationSample
+	done              bool
+}
+
+// add records the sample and returns true once enough samples are available for validation
+func (v *meterValidation) add(s validationSample) bool {
+	if v.done {
+		return false
+	}
+
+	v.samples = append(v.samples, s)
+	if len(v.samples) < validationSamples {
+		return false
+	}
+
+	v.done = true
+	return true
+}
+
+// validate returns actionable warnings for implausible measurements
+func (v *meterValidation) validate() []string {
+	var res []string
+
+	constant := func(f func(validationSample) float64) (float64, bool) {
+		first := f(v.samples[0])
+		for _, s := range v.samples[1:] {
+			if f(s) != first {
+				return 0, false
+			}
+		}
+		return first, first != 0
+	}
+
+	// all samples below the threshold
+	below := func(f func(validationSample) float64, threshold float64) (float64, bool) {
+		var sum float64
+		for _, s := range v.samples {
+			if f(s) >= threshold {
+				return 0, false
+			}
+			sum += f(s)
+		}
+		return sum / float64(len(v.samples)), true
+	}
+
+	grid := func(s validationSample) float64 { return s.grid }
+	pv := func(s validationSample) float64 { return s.pv }
+
+	// home power as calculated by the site, before clamping negative values
+	home := func(s validationSample) float64 { return s.grid + math.Max(0, s.pv) + s.battery - s.charge }
+
+	if v.grid {
+		if power, ok := constant(grid); ok {
+			res = append(res, fmt.Sprintf("grid meter: constant power %.0fW, check that the meter is updated", power))
+		}
+	}
+
+	var pvInverted bool
+
+	if v.pv {
+		if power, ok := constant(pv); ok {
+			res = append(res, fmt.Sprintf("pv meter: constant power %.0fW, check that the meter is updated", power))
+		}
+
+		if power, ok := below(pv, -validationThreshold); ok {
+			pvInverted = true
+			res = append(res, fmt.Sprintf("pv meter: negative power %.0fW, pv production must be positive - check the meter's sign", power))
+		}
+	}
+
+	// energy balance requires a measured grid meter, an inverted pv meter already explains a negative balance
+	if v.grid && !pvInverted {
+		if power, ok := below(home, -validationThreshold); ok {
+			meters := "grid meter"
+			if v.battery {
+				meters = "grid or battery meter"
+			}
+			res = append(res, fmt.Sprintf("%s: negative home power %.0fW, import and export may be swapped - grid import and battery discharge must be positive", meters, power))
+		}
+	}
+
+	return res
+}
+
+// validateMeters collects the startup measurements and reports implausible meter configurations once
+func (site *Site) validateMeters(s validationSample) {
+	if site.validation == nil {
+		site.validation = &meterValidation{
+			grid:    site.gridMeter != nil,
+			pv:      len(site.pvMeters) > 0,
+			battery: len(site.batteryMeters) > 0,
+		}
+	}
+
+	if !site.validation.add(s) {
+		return
+	}
+
+	res := site.validation.validate()
+	site.validation.samples = nil
+
+	site.publish(state.Validation, append(slices.Clone(site.lintWarnings), res...))
+
+	if len(res) == 0 {
+		site.log.DEBUG.Println("meter validation: ok")
+		return
+	}
+
+	for _, msg := range res {
+		site.log.WARN.Printf("meter validation: %s", msg)
+	}
+
+	site.events.Publish(nil, event.MeterValidation{Warnings: res})
+}
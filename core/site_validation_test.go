@@ -0,0 +1,61 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeterValidation(t *testing.T) {
+	tc := []struct {
+		name     string
+		sample   func(i int) validationSample
+		warnings []string
+	}{
+		{"plausible", func(i int) validationSample {
+			return validationSample{grid: float64(500 - i*10), pv: float64(2000 + i), charge: 1500}
+		}, nil},
+		{"constant grid", func(i int) validationSample {
+			return validationSample{grid: 1234, pv: float64(2000 + i)}
+		}, []string{"grid meter: constant"}},
+		{"negative pv", func(i int) validationSample {
+			return validationSample{grid: float64(-1000 - i), pv: float64(-3000 + i)}
+		}, []string{"pv meter: negative"}},
+		{"swapped grid", func(i int) validationSample {
+			return validationSample{grid: float64(-800 - i), pv: 0.5 * float64(i)}
+		}, []string{"grid meter: negative home power"}},
+		{"swapped battery", func(i int) validationSample {
+			return validationSample{grid: float64(100 + i), battery: float64(-900 - i)}
+		}, []string{"grid or battery meter: negative home power"}},
+	}
+
+	for _, tc := range tc {
+		v := &meterValidation{grid: true, pv: true, battery: strings.Contains(tc.name, "battery")}
+
+		var done bool
+		for i := 0; i < validationSamples; i++ {
+			if done {
+				t.Fatalf("%s: validation completed early", tc.name)
+			}
+			done = v.add(tc.sample(i))
+		}
+
+		if !done {
+			t.Fatalf("%s: validation not completed", tc.name)
+		}
+
+		res := v.validate()
+		if len(res) != len(tc.warnings) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.warnings, res)
+		}
+
+		for i, w := range tc.warnings {
+			if !strings.HasPrefix(res[i], w) {
+				t.Errorf("%s: expected %s, got %s", tc.name, w, res[i])
+			}
+		}
+
+		if v.add(tc.sample(0)) {
+			t.Errorf("%s: validation must run only once", tc.name)
+		}
+	}
+}
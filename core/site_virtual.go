@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// VirtualMeterConfig defines a meter derived from site measurements and configured meters
+type VirtualMeterConfig struct {
+	Name string              `mapstructure:"name"` // published as <name>Power
+	Add  []VirtualTermConfig `mapstructure:"add"`  // terms to be summed up
+}
+
+// VirtualTermConfig is a single term of a virtual meter
+type VirtualTermConfig struct {
+	Source string   `mapstructure:"source"` // site measurement: grid, pv, battery, charge, home
+	Meter  string   `mapstructure:"meter"`  // configured meter reference
+	Scale  *float64 `mapstructure:"scale"`  // factor, default 1, use -1 to subtract
+}
+
+// virtualSources are the site measurements available as virtual meter terms
+var virtualSources = []string{"grid", "pv", "battery", "charge", "home"}
+
+// virtualTerm is a configured virtual meter term
+type virtualTerm struct {
+	source string
+	meter  api.Meter
+	scale  float64
+}
+
+// virtualMeter is a meter derived from site measurements and configured meters
+type virtualMeter struct {
+	name  string
+	terms []virtualTerm
+}
+
+// siteMeasurements contains the site's power values of the current update cycle
+type siteMeasurements struct {
+	grid, pv, battery, charge, home float64
+}
+
+func (m siteMeasurements) value(source string) float64 {
+	switch source {
+	case "grid":
+		return m.grid
+	case "pv":
+		return m.pv
+	case "battery":
+		return m.battery
+	case "charge":
+		return m.charge
+	default:
+		return m.home
+	}
+}
+
+// newVirtualMeters creates the virtual meters from configuration
+func newVirtualMeters(cp configProvider, configs []VirtualMeterConfig) ([]*virtualMeter, error) {
+	var res []*virtualMeter
+	names := make(map[string]bool)
+
+	for i, cc := range configs {
+		if cc.Name == "" {
+			return nil, fmt.Errorf("virtual meter %d: missing name", i+1)
+		}
+		if names[cc.Name] {
+			return nil, fmt.Errorf("virtual meter %s: duplicate name", cc.Name)
+		}
+		names[cc.Name] = true
+
+		if len(cc.Add) == 0 {
+			return nil, fmt.Errorf("virtual meter %s: missing terms", cc.Name)
+		}
+
+		vm := &virtualMeter{name: cc.Name}
+
+		for j, tc := range cc.Add {
+			term := virtualTerm{scale: 1}
+			if tc.Scale != nil {
+				term.scale = *tc.Scale
+			}
+
+			switch {
+			case tc.Source != "" && tc.Meter != "":
+				return nil, fmt.Errorf("virtual meter %s: add[%d]: can only have either source or meter", cc.Name, j)
+
+			case tc.Meter != "":
+				meter, err := cp.Meter(tc.Meter)
+				if err != nil {
+					return nil, fmt.Errorf("virtual meter %s: add[%d]: %w", cc.Name, j, err)
+				}
+				term.meter = meter
+
+			case tc.Source != "":
+				term.source = strings.ToLower(tc.Source)
+				if !slices.Contains(virtualSources, term.source) {
+					return nil, fmt.Errorf("virtual meter %s: add[%d]: invalid source %s", cc.Name, j, tc.Source)
+				}
+
+			default:
+				return nil, fmt.Errorf("virtual meter %s: add[%d]: missing source or meter", cc.Name, j)
+			}
+
+			vm.terms = append(vm.terms, term)
+		}
+
+		res = append(res, vm)
+	}
+
+	return res, nil
+}
+
+// power calculates the virtual meter's power from the site measurements
+func (vm *virtualMeter) power(m siteMeasurements) (float64, error) {
+	var res float64
+
+	for i, t := range vm.terms {
+		val := m.value(t.source)
+
+		if t.meter != nil {
+			var err error
+			if val, err = t.meter.CurrentPower(); err != nil {
+				return 0, fmt.Errorf("add[%d]: %w", i, err)
+			}
+		}
+
+		res += t.scale * val
+	}
+
+	return res, nil
+}
+
+// updateVirtualMeters publishes the virtual meters' power
+func (site *Site) updateVirtualMeters(m siteMeasurements) {
+	for _, vm := range site.virtualMeters {
+		power, err := vm.power(m)
+		if err != nil {
+			site.log.ERROR.Printf("virtual meter %s: %v", vm.name, err)
+			continue
+		}
+
+		site.log.DEBUG.Printf("virtual meter %s power: %.0fW", vm.name, power)
+		site.publish(vm.name+"Power", power)
+	}
+}
@@ -1,10 +1,14 @@
 package soc
 
-import "github.com/evcc-io/evcc/core/loadpoint"
+import (
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
 
 // Adapter provides the required methods for interacting with the loadpoint
 type Adapter interface {
 	loadpoint.API
 	Publish(key string, val interface{})
+	PublishEvent(ev event.Event)
 	SocEstimator() *Estimator
 }
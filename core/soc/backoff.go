@@ -0,0 +1,77 @@
+package soc
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+const (
+	backoffInitial = 30 * time.Second // delay after first error
+	backoffJitter  = 0.2              // random delay variation
+)
+
+// backoff delays vehicle api calls after errors using exponential backoff with jitter
+type backoff struct {
+	mu       sync.Mutex
+	failures int
+	until    time.Time
+}
+
+var (
+	backoffMu sync.Mutex
+	backoffs  = make(map[api.Vehicle]*backoff)
+)
+
+// backoffFor returns the backoff of the vehicle, shared across loadpoints
+func backoffFor(v api.Vehicle) *backoff {
+	backoffMu.Lock()
+	defer backoffMu.Unlock()
+
+	b, ok := backoffs[v]
+	if !ok {
+		b = new(backoff)
+		backoffs[v] = b
+	}
+
+	return b
+}
+
+// Active returns true if api calls are delayed
+func (b *backoff) Active(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return now.Before(b.until)
+}
+
+// Fail records an api error and returns the delay until the next call
+func (b *backoff) Fail(now time.Time, max time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay := max
+	if b.failures < 16 {
+		delay = backoffInitial << b.failures
+	}
+	if delay > max {
+		delay = max
+	}
+
+	delay = time.Duration(float64(delay) * (1 - backoffJitter + 2*backoffJitter*rand.Float64()))
+
+	b.failures++
+	b.until = now.Add(delay)
+
+	return delay
+}
+
+// Reset clears the backoff after a successful api call
+func (b *backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.until = time.Time{}
+}
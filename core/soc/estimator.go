@@ -2,22 +2,62 @@ package soc
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 )
 
 const chargeEfficiency = 0.9 // assume charge 90% efficiency
 
+// taper model: charge power declines linearly above taperSoC down to taperPower of full power at 100%
+const (
+	taperSoC   = 80.0
+	taperPower = 0.3
+)
+
+// Source selects where the vehicle soc is read from
+type Source string
+
+// Soc sources
+const (
+	SourceAuto    Source = "auto"    // charger if it provides soc, vehicle api otherwise
+	SourceCharger Source = "charger" // charger only, e.g. ISO 15118 or EEBus
+	SourceVehicle Source = "vehicle" // vehicle api only
+)
+
+// SourceString parses the soc source, defaulting to SourceAuto
+func SourceString(s string) (Source, error) {
+	switch src := Source(strings.ToLower(s)); src {
+	case "":
+		return SourceAuto, nil
+	case SourceAuto, SourceCharger, SourceVehicle:
+		return src, nil
+	default:
+		return "", fmt.Errorf("invalid soc source: %s", s)
+	}
+}
+
 // Estimator provides vehicle soc and charge duration
 // Vehicle SoC can be estimated to provide more granularity
 type Estimator struct {
 	log      *util.Logger
+	clock    clock.Clock
 	charger  api.Charger
 	vehicle  api.Vehicle
 	estimate bool
+	source   Source
+	stale    api.StaleConfig
+	backoff  *backoff
+	updated  time.Time // last successful soc update
+	knownSoc float64   // last successfully fetched vehicle soc
+
+	manualSoc    *float64 // soc entered by the user for vehicles without api
+	manualEnergy float64  // charged energy at manual soc input in Wh
 
 	capacity          float64 // vehicle capacity in Wh cached to simplify testing
 	virtualCapacity   float64 // estimated virtual vehicle capacity in Wh
@@ -27,15 +67,24 @@ type Estimator struct {
 	prevSoc           float64 // previous vehicle SoC in %
 	prevChargedEnergy float64 // previous charged energy in Wh
 	energyPerSocStep  float64 // Energy per SoC percent in Wh
+	learnedCapacity   float64 // learned usable capacity in Wh replacing the configured capacity
+	gradientUpdated   bool    // soc gradient measured during session
 }
 
 // NewEstimator creates new estimator
 func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, estimate bool) *Estimator {
 	s := &Estimator{
 		log:      log,
+		clock:    clock.New(),
 		charger:  charger,
 		vehicle:  vehicle,
 		estimate: estimate,
+		source:   SourceAuto,
+		backoff:  backoffFor(vehicle),
+	}
+
+	if v, ok := vehicle.(api.StaleHandler); ok {
+		s.stale = v.Stale()
 	}
 
 	s.Reset()
@@ -46,23 +95,143 @@ func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, es
 // Reset resets the estimation process to default values
 func (s *Estimator) Reset() {
 	s.prevSoc = 0
+	s.knownSoc = 0
 	s.prevChargedEnergy = 0
 	s.initialSoc = 0
-	s.capacity = float64(s.vehicle.Capacity()) * 1e3  // cache to simplify debugging
+	s.gradientUpdated = false
+	s.manualSoc = nil
+	s.capacity = float64(s.vehicle.Capacity()) * 1e3 // cache to simplify debugging
+	if s.learnedCapacity > 0 {
+		s.capacity = s.learnedCapacity
+	}
 	s.virtualCapacity = s.capacity / chargeEfficiency // initial capacity taking efficiency into account
 	s.energyPerSocStep = s.virtualCapacity / 100
 }
 
-// AssumedChargeDuration estimates charge duration up to targetSoC based on virtual capacity
+// SetSource selects the soc source
+func (s *Estimator) SetSource(source Source) {
+	s.source = source
+}
+
+// SetSoC sets the known soc of vehicles without api. Afterwards the soc is derived from the charged energy.
+func (s *Estimator) SetSoC(soc, chargedEnergy float64) {
+	s.manualSoc = &soc
+	s.manualEnergy = chargedEnergy
+	s.vehicleSoc = soc
+	s.updated = s.clock.Now()
+}
+
+// integratedSoC adds the charged energy since the manual soc input taking charge efficiency into account
+func (s *Estimator) integratedSoC(chargedEnergy float64) float64 {
+	soc := *s.manualSoc
+	if s.energyPerSocStep > 0 {
+		soc += math.Max(chargedEnergy-s.manualEnergy, 0) / s.energyPerSocStep
+	}
+	return math.Min(soc, 100)
+}
+
+// SetCapacity replaces the configured usable capacity in kWh by a learned value and resets the estimation
+func (s *Estimator) SetCapacity(capacity float64) {
+	s.learnedCapacity = capacity * 1e3
+	s.Reset()
+}
+
+// LearnedCapacity returns the usable capacity in kWh derived from the soc gradient measured during the session
+func (s *Estimator) LearnedCapacity() (float64, bool) {
+	return s.virtualCapacity * chargeEfficiency / 1e3, s.gradientUpdated
+}
+
+// Age returns the age of the last successfully updated soc or zero if never updated
+func (s *Estimator) Age() time.Duration {
+	if s.updated.IsZero() {
+		return 0
+	}
+	return s.clock.Since(s.updated)
+}
+
+// vehicleSoC fetches the vehicle soc unless api calls are delayed after errors.
+// On errors the last known soc is used until it exceeds the configured max age.
+func (s *Estimator) vehicleSoC() (float64, error) {
+	now := s.clock.Now()
+
+	if s.knownSoc != 0 && s.stale.Backoff > 0 && s.backoff.Active(now) {
+		if err := s.staleErr(); err != nil {
+			return 0, err
+		}
+
+		s.log.DEBUG.Printf("vehicle soc: api backoff, using last known soc (age: %v)", s.Age().Truncate(time.Second))
+		return s.knownSoc, nil
+	}
+
+	f, err := s.vehicle.SoC()
+	if err == nil {
+		s.updated = now
+		s.knownSoc = f
+		s.backoff.Reset()
+		return f, nil
+	}
+
+	// required for online APIs with refreshkey
+	if errors.Is(err, api.ErrMustRetry) {
+		return 0, err
+	}
+
+	if s.stale.Backoff > 0 {
+		delay := s.backoff.Fail(now, s.stale.Backoff)
+		s.log.DEBUG.Printf("vehicle soc: api backoff for %v", delay.Truncate(time.Second))
+	}
+
+	// never received a soc value
+	if s.knownSoc == 0 {
+		return 0, err
+	}
+
+	if err := s.staleErr(); err != nil {
+		return 0, err
+	}
+
+	// recover from temporary api errors
+	s.log.WARN.Printf("vehicle soc: %v (ignored by estimator)", err)
+
+	return s.knownSoc, nil
+}
+
+// staleErr returns an error if the last known soc exceeds the configured max age
+func (s *Estimator) staleErr() error {
+	if s.stale.MaxAge > 0 && s.Age() > s.stale.MaxAge {
+		return fmt.Errorf("vehicle soc outdated: %v", s.Age().Truncate(time.Second))
+	}
+	return nil
+}
+
+// AssumedChargeDuration estimates charge duration up to targetSoC based on virtual capacity and taper model
 func (s *Estimator) AssumedChargeDuration(targetSoC int, chargePower float64) time.Duration {
 	percentRemaining := float64(targetSoC) - s.vehicleSoc
 
-	if percentRemaining <= 0 || s.virtualCapacity <= 0 {
+	if percentRemaining <= 0 || s.virtualCapacity <= 0 || chargePower <= 0 {
 		return 0
 	}
 
-	whRemaining := percentRemaining / 100 * s.virtualCapacity
-	return time.Duration(float64(time.Hour) * whRemaining / chargePower).Round(time.Second)
+	// hours per soc percent at full power
+	step := s.virtualCapacity / 100 / chargePower * taperFactor(s.vehicleSoc)
+
+	hours := step * (math.Min(float64(targetSoC), taperSoC) - math.Min(s.vehicleSoc, taperSoC))
+
+	// integrate 1/power over the taper range
+	if from, to := math.Max(s.vehicleSoc, taperSoC), math.Min(float64(targetSoC), 100); to > from {
+		k := (1 - taperPower) / (100 - taperSoC)
+		hours += step / k * math.Log(taperFactor(from)/taperFactor(to))
+	}
+
+	return time.Duration(float64(time.Hour) * hours).Round(time.Second)
+}
+
+// taperFactor is the ratio of charge power at given soc to full charge power
+func taperFactor(soc float64) float64 {
+	if soc <= taperSoC {
+		return 1
+	}
+	return 1 - (1-taperPower)*(math.Min(soc, 100)-taperSoC)/(100-taperSoC)
 }
 
 // RemainingChargeDuration returns the remaining duration estimate based on SoC, target and charge power
@@ -108,7 +277,7 @@ func (s *Estimator) RemainingChargeEnergy(targetSoC int) float64 {
 func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 	var fetchedSoC *float64
 
-	if charger, ok := s.charger.(api.Battery); ok {
+	if charger, ok := s.charger.(api.Battery); ok && s.source != SourceVehicle {
 		f, err := charger.SoC()
 
 		// if the charger does or could provide SoC, we always use it instead of using the vehicle API
@@ -122,6 +291,8 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 				// recover from temporary api errors
 				f = s.prevSoc
 				s.log.WARN.Printf("vehicle soc (charger): %v (ignored by estimator)", err)
+			} else {
+				s.updated = s.clock.Now()
 			}
 
 			fetchedSoC = &f
@@ -129,22 +300,20 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 		}
 	}
 
-	if fetchedSoC == nil {
-		f, err := s.vehicle.SoC()
-		if err != nil {
-			// required for online APIs with refreshkey
-			if errors.Is(err, api.ErrMustRetry) {
-				return 0, err
-			}
+	// vehicles without api
+	if fetchedSoC == nil && s.manualSoc != nil {
+		s.vehicleSoc = s.integratedSoC(chargedEnergy)
+		return s.vehicleSoc, nil
+	}
 
-			// never received a soc value
-			if s.prevSoc == 0 {
-				return 0, err
-			}
+	if fetchedSoC == nil {
+		if s.source == SourceCharger {
+			return 0, api.ErrNotAvailable
+		}
 
-			// recover from temporary api errors
-			f = s.prevSoc
-			s.log.WARN.Printf("vehicle soc: %v (ignored by estimator)", err)
+		f, err := s.vehicleSoC()
+		if err != nil {
+			return 0, err
 		}
 
 		fetchedSoC = &f
@@ -186,6 +355,7 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 				if socDiff > 10 && energyDiff > 0 {
 					s.energyPerSocStep = energyDiff / socDiff
 					s.virtualCapacity = s.energyPerSocStep * 100
+					s.gradientUpdated = true
 					s.log.DEBUG.Printf("soc gradient updated: soc: %.1f%%, socDiff: %.1f%%, energyDiff: %.0fWh, energyPerSocStep: %.1fWh, virtualCapacity: %.0fWh", s.vehicleSoc, socDiff, energyDiff, s.energyPerSocStep, s.virtualCapacity)
 				}
 			}
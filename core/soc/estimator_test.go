@@ -2,13 +2,16 @@ package soc
 
 import (
 	"errors"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/mock"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRemainingChargeDuration(t *testing.T) {
@@ -29,6 +32,33 @@ func TestRemainingChargeDuration(t *testing.T) {
 	}
 }
 
+func TestRemainingChargeDurationTaper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(float64(9))
+
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+
+	tc := []struct {
+		soc, chargePower float64
+		targetSoC        int
+		remaining        time.Duration
+	}{
+		{70, 1000, 80, time.Hour},
+		{70, 1000, 100, 4*time.Hour + 26*time.Minute + 24*time.Second},
+		// measured power is already reduced by taper
+		{90, 650, 100, 2*time.Hour + 12*time.Minute + 33*time.Second},
+	}
+
+	for _, tc := range tc {
+		ce.vehicleSoc = tc.soc
+		if remaining := ce.RemainingChargeDuration(tc.chargePower, tc.targetSoC); remaining != tc.remaining {
+			t.Errorf("%+v: expected remaining charge duration %v, got %v", tc, tc.remaining, remaining)
+		}
+	}
+}
+
 func TestSoCEstimation(t *testing.T) {
 	type chargerStruct struct {
 		*mock.MockCharger
@@ -102,8 +132,8 @@ func TestSoCEstimation(t *testing.T) {
 
 			// validate duration estimate
 			chargePower := 1e3
-			targetSoC := 100
-			remainingHours := (float64(targetSoC) - soc) / 100 * tc.virtualCapacity / chargePower
+			targetSoC := 80 // below taper
+			remainingHours := math.Max(float64(targetSoC)-soc, 0) / 100 * tc.virtualCapacity / chargePower
 			remainingDuration := time.Duration(float64(time.Hour) * remainingHours).Round(time.Second)
 
 			if rm := ce.RemainingChargeDuration(chargePower, targetSoC); rm != remainingDuration {
@@ -198,8 +228,8 @@ func TestSoCFromChargerAndVehicleWithErrors(t *testing.T) {
 
 		// validate duration estimate
 		chargePower := 1e3
-		targetSoC := 100
-		remainingHours := (float64(targetSoC) - soc) / 100 * tc.virtualCapacity / chargePower
+		targetSoC := 80 // below taper
+		remainingHours := math.Max(float64(targetSoC)-soc, 0) / 100 * tc.virtualCapacity / chargePower
 		remainingDuration := time.Duration(float64(time.Hour) * remainingHours).Round(time.Second)
 
 		if rm := ce.RemainingChargeDuration(chargePower, targetSoC); rm != remainingDuration {
@@ -207,3 +237,135 @@ func TestSoCFromChargerAndVehicleWithErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestSoCSource(t *testing.T) {
+	type chargerStruct struct {
+		*mock.MockCharger
+		*mock.MockBattery
+	}
+
+	ctrl := gomock.NewController(t)
+	vehicle := mock.NewMockVehicle(ctrl)
+	charger := &chargerStruct{mock.NewMockCharger(ctrl), mock.NewMockBattery(ctrl)}
+
+	vehicle.EXPECT().Capacity().Return(float64(10)).AnyTimes()
+
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+
+	// vehicle api only, charger soc is ignored
+	ce.SetSource(SourceVehicle)
+	vehicle.EXPECT().SoC().Return(40.0, nil)
+
+	if soc, err := ce.SoC(0); err != nil || soc != 40 {
+		t.Errorf("expected vehicle soc 40, got %g (%v)", soc, err)
+	}
+
+	// charger only, vehicle api is never used
+	ce.SetSource(SourceCharger)
+	charger.MockBattery.EXPECT().SoC().Return(0.0, api.ErrNotAvailable)
+
+	if _, err := ce.SoC(0); !errors.Is(err, api.ErrNotAvailable) {
+		t.Errorf("expected %v, got %v", api.ErrNotAvailable, err)
+	}
+
+	charger.MockBattery.EXPECT().SoC().Return(50.0, nil)
+
+	if soc, err := ce.SoC(0); err != nil || soc != 50 {
+		t.Errorf("expected charger soc 50, got %g (%v)", soc, err)
+	}
+}
+
+func TestManualSoC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	vehicle := mock.NewMockVehicle(ctrl)
+
+	// 9 kWh usable capacity, 10 kWh virtual capacity
+	vehicle.EXPECT().Capacity().Return(float64(9)).AnyTimes()
+
+	ce := NewEstimator(util.NewLogger("foo"), mock.NewMockCharger(ctrl), vehicle, false)
+	ce.SetSoC(20, 500)
+
+	for _, tc := range []struct {
+		chargedEnergy, soc float64
+	}{
+		{500, 20},
+		{1500, 30},
+		{5500, 70},
+		{20000, 100},
+	} {
+		soc, err := ce.SoC(tc.chargedEnergy)
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.soc, soc, 1e-6, "charged energy %.0fWh", tc.chargedEnergy)
+	}
+
+	// manual soc is discarded on reset
+	ce.Reset()
+	vehicle.EXPECT().SoC().Return(0.0, nil)
+
+	soc, err := ce.SoC(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, soc)
+}
+
+func TestSourceString(t *testing.T) {
+	for in, out := range map[string]Source{"": SourceAuto, "Charger": SourceCharger, "vehicle": SourceVehicle} {
+		if res, err := SourceString(in); err != nil || res != out {
+			t.Errorf("%s: expected %s, got %s (%v)", in, out, res, err)
+		}
+	}
+
+	if _, err := SourceString("cloud"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestStaleSoC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(float64(9))
+
+	clck := clock.NewMock()
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+	ce.clock = clck
+	ce.stale = api.StaleConfig{MaxAge: time.Hour, Backoff: 10 * time.Minute}
+
+	vehicle.EXPECT().SoC().Return(50.0, nil)
+	soc, err := ce.SoC(0)
+	if err != nil || soc != 50 {
+		t.Fatalf("unexpected soc: %v %v", soc, err)
+	}
+
+	// api error uses last known soc and starts backoff
+	clck.Add(time.Minute)
+	vehicle.EXPECT().SoC().Return(0.0, errors.New("rate limited"))
+	if soc, err := ce.SoC(0); err != nil || soc != 50 {
+		t.Errorf("expected last known soc, got: %v %v", soc, err)
+	}
+
+	// api not called during backoff
+	if soc, err := ce.SoC(0); err != nil || soc != 50 {
+		t.Errorf("expected last known soc, got: %v %v", soc, err)
+	}
+
+	if age := ce.Age(); age != time.Minute {
+		t.Errorf("expected age 1m, got: %v", age)
+	}
+
+	// outdated soc is discarded
+	clck.Add(2 * time.Hour)
+	vehicle.EXPECT().SoC().Return(0.0, errors.New("rate limited"))
+	if _, err := ce.SoC(0); err == nil {
+		t.Error("expected outdated soc error")
+	}
+
+	// recovery resets backoff
+	clck.Add(time.Hour)
+	vehicle.EXPECT().SoC().Return(60.0, nil)
+	if soc, err := ce.SoC(0); err != nil || soc != 60 {
+		t.Errorf("unexpected soc: %v %v", soc, err)
+	}
+	if ce.backoff.Active(clck.Now()) {
+		t.Error("expected backoff reset")
+	}
+}
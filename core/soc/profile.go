@@ -0,0 +1,59 @@
+package soc
+
+const (
+	learningWeight = 0.3 // weight of a new sample in the learned average
+	minDistance    = 20  // min distance in km for a consumption sample
+	minSocDelta    = 5   // min soc drop in % for a consumption sample
+)
+
+// Profile is the learned battery capacity and consumption of a vehicle
+type Profile struct {
+	Capacity    float64 `json:"capacity,omitempty"`    // usable capacity in kWh
+	Consumption float64 `json:"consumption,omitempty"` // average consumption in kWh/100km
+	SoC         float64 `json:"soc,omitempty"`         // soc at last departure in %
+	Odometer    float64 `json:"odometer,omitempty"`    // odometer at last departure in km
+}
+
+// average adds the sample to the moving average
+func average(avg, sample float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	return avg + learningWeight*(sample-avg)
+}
+
+// LearnCapacity adds a usable capacity sample in kWh
+func (p *Profile) LearnCapacity(capacity float64) {
+	if capacity > 0 {
+		p.Capacity = average(p.Capacity, capacity)
+	}
+}
+
+// LearnConsumption adds the consumption driven since last departure on arrival.
+// Capacity in kWh is used to convert the soc drop into energy.
+func (p *Profile) LearnConsumption(soc, odometer, capacity float64) bool {
+	distance := odometer - p.Odometer
+	delta := p.SoC - soc
+
+	if p.Odometer == 0 || distance < minDistance || delta < minSocDelta || capacity <= 0 {
+		return false
+	}
+
+	p.Consumption = average(p.Consumption, delta/100*capacity/distance*100)
+
+	return true
+}
+
+// Depart records soc and odometer at departure
+func (p *Profile) Depart(soc, odometer float64) {
+	p.SoC = soc
+	p.Odometer = odometer
+}
+
+// Range estimates the range in km for given soc and capacity in kWh
+func (p *Profile) Range(soc, capacity float64) (int64, bool) {
+	if p.Consumption <= 0 || capacity <= 0 {
+		return 0, false
+	}
+	return int64(soc / 100 * capacity / p.Consumption * 100), true
+}
@@ -0,0 +1,36 @@
+package soc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	var p Profile
+
+	// no departure recorded
+	assert.False(t, p.LearnConsumption(60, 10000, 50))
+
+	p.Depart(80, 10000)
+
+	// too short
+	assert.False(t, p.LearnConsumption(78, 10010, 50))
+
+	// 20% of 50kWh over 50km
+	assert.True(t, p.LearnConsumption(60, 10050, 50))
+	assert.Equal(t, 20.0, p.Consumption)
+
+	rng, ok := p.Range(50, 50)
+	assert.True(t, ok)
+	assert.Equal(t, int64(125), rng)
+
+	// moving average
+	p.Depart(80, 10050)
+	assert.True(t, p.LearnConsumption(70, 10100, 50))
+	assert.InDelta(t, 17.0, p.Consumption, 1e-9)
+
+	p.LearnCapacity(50)
+	p.LearnCapacity(40)
+	assert.Equal(t, 47.0, p.Capacity)
+}
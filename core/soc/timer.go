@@ -2,9 +2,13 @@ package soc
 
 import (
 	"math"
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/util"
 )
 
@@ -15,6 +19,7 @@ const (
 // Timer is the target charging handler
 type Timer struct {
 	Adapter
+	Planner   *planner.Planner // optional solar and tariff aware planning
 	log       *util.Logger
 	current   float64
 	SoC       int
@@ -22,6 +27,8 @@ type Timer struct {
 	finishAt  time.Time
 	active    bool
 	validated bool
+	mu        sync.Mutex   // guards plan
+	plan      planner.Plan // current charging plan
 }
 
 // NewTimer creates a Timer
@@ -60,7 +67,7 @@ func (lp *Timer) Stop() {
 
 	if lp.active {
 		lp.active = false
-		lp.Publish("targetTimeActive", lp.active)
+		lp.Publish(state.TargetTimeActive, lp.active)
 		lp.log.DEBUG.Println("target charging: disable")
 	}
 }
@@ -74,10 +81,36 @@ func (lp *Timer) Set(t time.Time) {
 	lp.Time = t
 
 	if lp.Time.IsZero() {
-		lp.Publish("targetTime", nil)
-		lp.Publish("targetTimeProjectedStart", nil)
+		lp.Publish(state.TargetTime, nil)
+		lp.Publish(state.TargetTimeProjectedStart, nil)
+		lp.setPlan(planner.Plan{})
 	} else {
-		lp.Publish("targetTime", lp.Time)
+		lp.Publish(state.TargetTime, lp.Time)
+	}
+}
+
+// Plan returns the current charging plan
+func (lp *Timer) Plan() planner.Plan {
+	if lp == nil {
+		return planner.Plan{}
+	}
+
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+
+	return lp.plan
+}
+
+// setPlan updates and publishes the charging plan
+func (lp *Timer) setPlan(plan planner.Plan) {
+	lp.mu.Lock()
+	lp.plan = plan
+	lp.mu.Unlock()
+
+	if plan.Slots == nil && plan.Solar == 0 {
+		lp.Publish(state.TargetPlan, nil)
+	} else {
+		lp.Publish(state.TargetPlan, plan)
 	}
 }
 
@@ -112,6 +145,10 @@ func (lp *Timer) DemandActive() bool {
 		return false
 	}
 
+	if lp.Planner != nil {
+		return lp.planDemand(se.RemainingChargeEnergy(lp.SoC))
+	}
+
 	// time
 	remainingDuration := time.Duration(float64(se.AssumedChargeDuration(lp.SoC, power)) / chargeEfficiency)
 	lp.finishAt = time.Now().Add(remainingDuration).Round(time.Minute)
@@ -120,11 +157,13 @@ func (lp *Timer) DemandActive() bool {
 	if lp.active {
 		lp.log.DEBUG.Printf("projected end: %v", lp.finishAt)
 		lp.log.DEBUG.Printf("desired finish time: %v", lp.Time)
-		lp.Publish("targetTimeProjectedStart", nil)
+		lp.Publish(state.TargetTimeProjectedStart, nil)
+		lp.setPlan(planner.Plan{Slots: api.Rates{{Start: time.Now(), End: lp.finishAt}}})
 	} else {
 		projectedStart := lp.Time.Add(-remainingDuration)
 		lp.log.DEBUG.Printf("projected start: %v", projectedStart)
-		lp.Publish("targetTimeProjectedStart", projectedStart)
+		lp.Publish(state.TargetTimeProjectedStart, projectedStart)
+		lp.setPlan(planner.Plan{Slots: api.Rates{{Start: projectedStart, End: lp.Time}}})
 	}
 
 	// timer charging is already active- only deactivate once charging has stopped
@@ -139,7 +178,8 @@ func (lp *Timer) DemandActive() bool {
 	// check if charging need be activated
 	if active := lp.finishAt.After(lp.Time); active {
 		lp.active = active
-		lp.Publish("targetTimeActive", lp.active)
+		lp.Publish(state.TargetTimeActive, lp.active)
+		lp.PublishEvent(event.PlanActivated{Target: lp.Time})
 
 		lp.current = lp.GetMaxCurrent()
 		lp.log.INFO.Printf("target charging active for %v: projected %v (%v remaining)", lp.Time.Local(), lp.finishAt.Local(), remainingDuration.Round(time.Minute))
@@ -148,8 +188,41 @@ func (lp *Timer) DemandActive() bool {
 	return lp.active
 }
 
+// planDemand returns true if the planner schedules grid charging at the current time
+func (lp *Timer) planDemand(energy float64) bool {
+	plan := lp.Planner.Plan(energy, lp.GetMaxPower(), lp.Time)
+	lp.setPlan(plan)
+
+	if start := plan.Start(); start.IsZero() {
+		lp.Publish(state.TargetTimeProjectedStart, nil)
+	} else {
+		lp.Publish(state.TargetTimeProjectedStart, start)
+	}
+
+	if active := plan.Active(time.Now()); active != lp.active {
+		lp.active = active
+		lp.Publish(state.TargetTimeActive, lp.active)
+
+		if active {
+			lp.PublishEvent(event.PlanActivated{Target: lp.Time})
+			lp.log.INFO.Printf("target charging active for %v: planned slot", lp.Time.Local())
+		} else {
+			lp.log.DEBUG.Println("target charging: waiting for planned slot")
+		}
+	}
+
+	lp.current = lp.GetMaxCurrent()
+
+	return lp.active
+}
+
 // Handle adjusts current up/down to achieve desired target time taking.
 func (lp *Timer) Handle() float64 {
+	// planned slots are charged at full power
+	if lp.Planner != nil {
+		return lp.current
+	}
+
 	action := "steady"
 
 	switch {
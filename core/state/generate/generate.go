@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	source = "state.go"
+	target = "keys.go"
+)
+
+// roots are the published models, slices of roots are aggregated by the cache and have no key
+var roots = []string{"Site", "Loadpoint"}
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, nil, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	types := make(map[string]*ast.StructType)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				types[ts.Name.Name] = st
+			}
+		}
+		return true
+	})
+
+	keys := make(map[string]string)
+	for _, root := range roots {
+		if err := collect(types, root, keys); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := os.WriteFile(target, render(keys), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// collect adds the json keys of the named struct including embedded structs
+func collect(types map[string]*ast.StructType, name string, keys map[string]string) error {
+	st, ok := types[name]
+	if !ok {
+		return fmt.Errorf("unknown type: %s", name)
+	}
+
+	for _, f := range st.Fields.List {
+		// embedded struct
+		if len(f.Names) == 0 {
+			id, ok := f.Type.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("%s: unsupported embedded type", name)
+			}
+			if err := collect(types, id.Name, keys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if at, ok := f.Type.(*ast.ArrayType); ok {
+			if id, ok := at.Elt.(*ast.Ident); ok && isRoot(id.Name) {
+				continue
+			}
+		}
+
+		if f.Tag == nil {
+			return fmt.Errorf("%s.%s: missing json tag", name, f.Names[0].Name)
+		}
+
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return err
+		}
+
+		key, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+		if key == "" || key == "-" {
+			return fmt.Errorf("%s.%s: missing json key", name, f.Names[0].Name)
+		}
+
+		for _, n := range f.Names {
+			if k, ok := keys[n.Name]; ok && k != key {
+				return fmt.Errorf("%s.%s: key %s conflicts with %s", name, n.Name, key, k)
+			}
+			keys[n.Name] = key
+		}
+	}
+
+	return nil
+}
+
+func isRoot(name string) bool {
+	for _, r := range roots {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+func render(keys map[string]string) []byte {
+	names := make([]string, 0, len(keys))
+	for n := range keys {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by github.com/evcc-io/evcc/core/state/generate. DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package state")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// Published keys")
+	fmt.Fprintln(&b, "const (")
+	for _, n := range names {
+		fmt.Fprintf(&b, "%s = %q\n", n, keys[n])
+	}
+	fmt.Fprintln(&b, ")")
+
+	res, err := format.Source(b.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	return res
+}
@@ -0,0 +1,128 @@
+// Code generated by github.com/evcc-io/evcc/core/state/generate. DO NOT EDIT.
+
+package state
+
+// Published keys
+const (
+	ApiError                      = "apiError"
+	Authorized                    = "authorized"
+	AuthorizedUser                = "authorizedUser"
+	AvailableVersion              = "availableVersion"
+	BatteryConfigured             = "batteryConfigured"
+	BatteryMode                   = "batteryMode"
+	BatteryModeExpiry             = "batteryModeExpiry"
+	BatteryPower                  = "batteryPower"
+	BatterySoC                    = "batterySoC"
+	BudgetCost                    = "budgetCost"
+	BudgetEnergy                  = "budgetEnergy"
+	BudgetExceeded                = "budgetExceeded"
+	BufferSoC                     = "bufferSoC"
+	BufferStartSoC                = "bufferStartSoC"
+	ChargeConfigured              = "chargeConfigured"
+	ChargeCurrent                 = "chargeCurrent"
+	ChargeCurrents                = "chargeCurrents"
+	ChargeDuration                = "chargeDuration"
+	ChargePower                   = "chargePower"
+	ChargeRemainingDuration       = "chargeRemainingDuration"
+	ChargeRemainingEnergy         = "chargeRemainingEnergy"
+	ChargeTotalImport             = "chargeTotalImport"
+	ChargeVoltages                = "chargeVoltages"
+	ChargedEnergy                 = "chargedEnergy"
+	Charging                      = "charging"
+	Circuits                      = "circuits"
+	Climater                      = "climater"
+	Connected                     = "connected"
+	ConnectedDuration             = "connectedDuration"
+	Consumers                     = "consumers"
+	Currency                      = "currency"
+	CurrentIgnored                = "currentIgnored"
+	Curtailed                     = "curtailed"
+	DemandResponse                = "demandResponse"
+	DemandResponseActive          = "demandResponseActive"
+	DemandResponseOverride        = "demandResponseOverride"
+	EffectivePrice                = "effectivePrice"
+	Enabled                       = "enabled"
+	Experimental                  = "experimental"
+	Fault                         = "fault"
+	FaultDescription              = "faultDescription"
+	Forecast                      = "forecast"
+	GridConfigured                = "gridConfigured"
+	GridCurrents                  = "gridCurrents"
+	GridEnergy                    = "gridEnergy"
+	GridMeter                     = "gridMeter"
+	GridPower                     = "gridPower"
+	GridSignal                    = "gridSignal"
+	GridSignalLimit               = "gridSignalLimit"
+	GridSignalLog                 = "gridSignalLog"
+	GuestSessionActive            = "guestSessionActive"
+	GuestSessionCost              = "guestSessionCost"
+	HasUpdater                    = "hasUpdater"
+	HomePower                     = "homePower"
+	Island                        = "island"
+	MaxCurrent                    = "maxCurrent"
+	MinCurrent                    = "minCurrent"
+	MinSoC                        = "minSoC"
+	Mode                          = "mode"
+	PeakShavingAverage            = "peakShavingAverage"
+	PeakShavingLimit              = "peakShavingLimit"
+	PhaseAction                   = "phaseAction"
+	PhaseRemaining                = "phaseRemaining"
+	PhasesActive                  = "phasesActive"
+	PhasesConfigured              = "phasesConfigured"
+	PhasesEnabled                 = "phasesEnabled"
+	PrioritySoC                   = "prioritySoC"
+	Pv                            = "pv"
+	PvAction                      = "pvAction"
+	PvConfigured                  = "pvConfigured"
+	PvPower                       = "pvPower"
+	PvRemaining                   = "pvRemaining"
+	QueuePosition                 = "queuePosition"
+	ReleaseNotes                  = "releaseNotes"
+	RemoteBudget                  = "remoteBudget"
+	RemoteBudgetSource            = "remoteBudgetSource"
+	RemoteDisabled                = "remoteDisabled"
+	RemoteDisabledSource          = "remoteDisabledSource"
+	ResidualPower                 = "residualPower"
+	SavingsAmount                 = "savingsAmount"
+	SavingsEffectivePrice         = "savingsEffectivePrice"
+	SavingsGridCharged            = "savingsGridCharged"
+	SavingsSelfConsumptionCharged = "savingsSelfConsumptionCharged"
+	SavingsSelfConsumptionPercent = "savingsSelfConsumptionPercent"
+	SavingsSince                  = "savingsSince"
+	SavingsTotalCharged           = "savingsTotalCharged"
+	SessionCost                   = "sessionCost"
+	SessionPrice                  = "sessionPrice"
+	SessionSolarPercentage        = "sessionSolarPercentage"
+	SiteTitle                     = "siteTitle"
+	Sponsor                       = "sponsor"
+	Statistics                    = "statistics"
+	TargetEnergy                  = "targetEnergy"
+	TargetPlan                    = "targetPlan"
+	TargetSoC                     = "targetSoC"
+	TargetTime                    = "targetTime"
+	TargetTimeActive              = "targetTimeActive"
+	TargetTimeProjectedStart      = "targetTimeProjectedStart"
+	TariffFeedIn                  = "tariffFeedIn"
+	TariffFeedInRates             = "tariffFeedInRates"
+	TariffGrid                    = "tariffGrid"
+	TariffGridRates               = "tariffGridRates"
+	Title                         = "title"
+	TraceID                       = "traceId"
+	UploadMessage                 = "uploadMessage"
+	UploadProgress                = "uploadProgress"
+	Validation                    = "validation"
+	VehicleCapacity               = "vehicleCapacity"
+	VehicleConsumption            = "vehicleConsumption"
+	VehicleDetectionActive        = "vehicleDetectionActive"
+	VehicleIdentity               = "vehicleIdentity"
+	VehicleOdometer               = "vehicleOdometer"
+	VehiclePresent                = "vehiclePresent"
+	VehicleRange                  = "vehicleRange"
+	VehicleSoC                    = "vehicleSoC"
+	VehicleSoCAge                 = "vehicleSoCAge"
+	VehicleTargetSoC              = "vehicleTargetSoC"
+	VehicleTitle                  = "vehicleTitle"
+	Vehicles                      = "vehicles"
+	Version                       = "version"
+	VoltageSag                    = "voltageSag"
+)
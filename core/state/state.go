@@ -0,0 +1,293 @@
+// Package state defines the typed model of the values published by site and loadpoints.
+// The json tags are the published keys, the key constants are generated from the model.
+package state
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
+)
+
+//go:generate go run ./generate
+
+// Site is the published state of the site
+type Site struct {
+	SiteTitle string   `json:"siteTitle"`
+	Version   string   `json:"version"`
+	Sponsor   string   `json:"sponsor,omitempty"`
+	Currency  string   `json:"currency"`
+	Vehicles  []string `json:"vehicles"`
+
+	Experimental map[string]bool `json:"experimental,omitempty"` // experimental subsystems by flag
+
+	Updater
+	Grid
+	Battery
+	Tariff
+	Savings
+
+	PvConfigured  bool             `json:"pvConfigured"`
+	PvPower       float64          `json:"pvPower"`
+	Pv            []PvString       `json:"pv"`
+	HomePower     float64          `json:"homePower"`
+	ResidualPower float64          `json:"residualPower"`
+	Island        bool             `json:"island"`
+	Curtailed     bool             `json:"curtailed"`
+	Consumers     []Consumer       `json:"consumers"`
+	Circuits      []Circuit        `json:"circuits"`   // per-phase load of the circuits
+	Forecast      *SolarForecast   `json:"forecast"`   // solar forecast and surplus estimate
+	Validation    []string         `json:"validation"` // configuration and meter warnings found at startup
+	Statistics    ChargeStatistics `json:"statistics"` // charged energy, cost and co2 since start
+
+	Loadpoints []Loadpoint `json:"loadpoints"`
+}
+
+// PvString is the power of a named pv meter, e.g. per roof orientation or inverter
+type PvString struct {
+	Title string  `json:"title"`
+	Power float64 `json:"power"`
+}
+
+// SolarForecast is the solar forecast and the surplus estimate corrected by actual pv power and home consumption
+type SolarForecast struct {
+	Power    float64 `json:"power"`    // forecast pv power in W
+	Surplus  float64 `json:"surplus"`  // estimated pv surplus in W
+	Today    float64 `json:"today"`    // remaining surplus energy of today in kWh
+	Tomorrow float64 `json:"tomorrow"` // forecast pv energy of tomorrow in kWh
+}
+
+// Circuit is the per-phase load of a circuit including household consumption if metered
+type Circuit struct {
+	Name       string     `json:"name"`
+	Currents   [3]float64 `json:"currents"`
+	MaxCurrent float64    `json:"maxCurrent"`
+	Unbalance  float64    `json:"unbalance"` // current difference between highest and lowest phase
+}
+
+// Consumer is the state of a smart consumer switched by pv surplus
+type Consumer struct {
+	Title string  `json:"title"`
+	On    bool    `json:"on"`
+	Power float64 `json:"power"`
+}
+
+// Updater is the release and update progress state
+type Updater struct {
+	AvailableVersion string `json:"availableVersion,omitempty"`
+	ReleaseNotes     string `json:"releaseNotes,omitempty"` // html
+	HasUpdater       bool   `json:"hasUpdater,omitempty"`
+	UploadProgress   int64  `json:"uploadProgress,omitempty"` // %
+	UploadMessage    string `json:"uploadMessage,omitempty"`
+}
+
+// GridSignalEvent is a load reduction period signalled by the grid operator
+type GridSignalEvent struct {
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end,omitempty"`
+	Limit  float64    `json:"limit"`
+	Reason string     `json:"reason"`
+}
+
+// DemandResponseEvent is a load-shed event sent by the utility, e.g. via OpenADR
+type DemandResponseEvent struct {
+	ID     string    `json:"id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Limit  float64   `json:"limit"` // total charge power in W
+	Reason string    `json:"reason"`
+}
+
+// Grid is the grid connection state
+type Grid struct {
+	GridConfigured  bool              `json:"gridConfigured"`
+	GridMeter       string            `json:"gridMeter,omitempty"` // primary or fallback
+	GridPower       float64           `json:"gridPower"`
+	GridCurrents    []float64         `json:"gridCurrents,omitempty"`
+	GridEnergy      float64           `json:"gridEnergy,omitempty"`
+	GridSignal      bool              `json:"gridSignal"`
+	GridSignalLimit float64           `json:"gridSignalLimit,omitempty"`
+	GridSignalLog   []GridSignalEvent `json:"gridSignalLog,omitempty"`
+
+	DemandResponse         *DemandResponseEvent `json:"demandResponse,omitempty"`
+	DemandResponseActive   bool                 `json:"demandResponseActive"`
+	DemandResponseOverride bool                 `json:"demandResponseOverride"` // user overrides the event for emergency charging
+
+	PeakShavingAverage float64 `json:"peakShavingAverage,omitempty"` // rolling average grid import
+	PeakShavingLimit   float64 `json:"peakShavingLimit,omitempty"`
+}
+
+// Battery is the home battery state
+type Battery struct {
+	BatteryConfigured bool            `json:"batteryConfigured"`
+	BatteryPower      float64         `json:"batteryPower"`
+	BatterySoC        float64         `json:"batterySoC"`
+	BatteryMode       api.BatteryMode `json:"batteryMode,omitempty"`
+	BatteryModeExpiry time.Time       `json:"batteryModeExpiry"` // external battery mode override
+	BufferSoC         float64         `json:"bufferSoC"`
+	BufferStartSoC    float64         `json:"bufferStartSoC"`
+	PrioritySoC       float64         `json:"prioritySoC"`
+}
+
+// Tariff is the current price and upcoming rates
+type Tariff struct {
+	TariffGrid        float64   `json:"tariffGrid,omitempty"`
+	TariffGridRates   api.Rates `json:"tariffGridRates,omitempty"`
+	TariffFeedIn      float64   `json:"tariffFeedIn,omitempty"`
+	TariffFeedInRates api.Rates `json:"tariffFeedInRates,omitempty"`
+}
+
+// Savings is the self consumption statistics
+type Savings struct {
+	SavingsSince                  int64   `json:"savingsSince"` // unix timestamp
+	SavingsAmount                 float64 `json:"savingsAmount"`
+	SavingsEffectivePrice         float64 `json:"savingsEffectivePrice"`
+	SavingsGridCharged            float64 `json:"savingsGridCharged"`
+	SavingsSelfConsumptionCharged float64 `json:"savingsSelfConsumptionCharged"`
+	SavingsSelfConsumptionPercent float64 `json:"savingsSelfConsumptionPercent"`
+	SavingsTotalCharged           float64 `json:"savingsTotalCharged"`
+}
+
+// ChargeStatistics is the charged energy, cost and co2 aggregated across restarts
+type ChargeStatistics struct {
+	Since         time.Time `json:"since"`
+	SolarCharged  float64   `json:"solarCharged"`  // kWh, including battery
+	GridCharged   float64   `json:"gridCharged"`   // kWh
+	Cost          float64   `json:"cost"`          // grid price for grid and feed-in price for solar energy
+	ReferenceCost float64   `json:"referenceCost"` // all energy at the reference price
+	Saved         float64   `json:"saved"`         // reference cost less cost
+	Co2Emitted    float64   `json:"co2Emitted"`    // kg of grid energy
+	Co2Avoided    float64   `json:"co2Avoided"`    // kg of grid energy replaced by solar energy
+}
+
+// Loadpoint is the published state of a loadpoint
+type Loadpoint struct {
+	Title     string         `json:"title"`
+	Mode      api.ChargeMode `json:"mode"`
+	Enabled   bool           `json:"enabled"`
+	Connected bool           `json:"connected"`
+	Charging  bool           `json:"charging"`
+
+	Charge
+	Phases
+	Vehicle
+	Timer
+	Plan
+	Remote
+	Session
+	Monitor
+
+	MinCurrent    float64 `json:"minCurrent"`
+	MaxCurrent    float64 `json:"maxCurrent"`
+	MinSoC        int     `json:"minSoC"`
+	TargetSoC     int     `json:"targetSoC"`
+	TargetEnergy  float64 `json:"targetEnergy"`
+	Climater      string  `json:"climater,omitempty"` // off, on, heating or cooling
+	Curtailed     bool    `json:"curtailed"`
+	QueuePosition int     `json:"queuePosition"` // waiting for circuit capacity, 0 if not queued
+
+	Validation []string `json:"validation,omitempty"` // configuration warnings found at startup or while charging
+
+	Authorized     bool   `json:"authorized,omitempty"`
+	AuthorizedUser string `json:"authorizedUser,omitempty"`
+}
+
+// Charge is the charging measurement state
+type Charge struct {
+	ChargeConfigured        bool          `json:"chargeConfigured"`
+	ChargePower             float64       `json:"chargePower"`
+	ChargeCurrent           float64       `json:"chargeCurrent"`
+	ChargeCurrents          []float64     `json:"chargeCurrents,omitempty"`
+	ChargeVoltages          []float64     `json:"chargeVoltages,omitempty"`
+	ChargedEnergy           float64       `json:"chargedEnergy"`
+	ChargeDuration          time.Duration `json:"chargeDuration"`
+	ChargeRemainingDuration time.Duration `json:"chargeRemainingDuration"`
+	ChargeRemainingEnergy   float64       `json:"chargeRemainingEnergy"`
+	ChargeTotalImport       float64       `json:"chargeTotalImport,omitempty"`
+	ConnectedDuration       time.Duration `json:"connectedDuration"`
+	CurrentIgnored          bool          `json:"currentIgnored"`
+}
+
+// Phases is the phase switching state
+type Phases struct {
+	PhasesConfigured *int `json:"phasesConfigured"` // configured phases (1/3, 0 for auto on 1p3p chargers, nil for plain chargers)
+	PhasesEnabled    int  `json:"phasesEnabled"`    // enabled phases (1/3)
+	PhasesActive     int  `json:"phasesActive"`     // active phases as used by vehicle (1/2/3)
+}
+
+// Vehicle is the state of the loadpoint's active vehicle
+type Vehicle struct {
+	VehicleTitle           string        `json:"vehicleTitle"`
+	VehiclePresent         bool          `json:"vehiclePresent"`
+	VehicleCapacity        float64       `json:"vehicleCapacity"`
+	VehicleConsumption     float64       `json:"vehicleConsumption"` // learned kWh/100km
+	VehicleSoC             float64       `json:"vehicleSoC"`
+	VehicleSoCAge          time.Duration `json:"vehicleSoCAge"` // age of last known soc
+	VehicleRange           int64         `json:"vehicleRange"`
+	VehicleOdometer        float64       `json:"vehicleOdometer"`
+	VehicleTargetSoC       float64       `json:"vehicleTargetSoC"`
+	VehicleIdentity        string        `json:"vehicleIdentity"`
+	VehicleDetectionActive bool          `json:"vehicleDetectionActive"`
+}
+
+// Timer is the pv and phase switching timer state
+type Timer struct {
+	PvAction       string        `json:"pvAction"` // enable, disable or inactive
+	PvRemaining    time.Duration `json:"pvRemaining"`
+	PhaseAction    string        `json:"phaseAction"` // scale1p, scale3p or inactive
+	PhaseRemaining time.Duration `json:"phaseRemaining"`
+}
+
+// Plan is the target charging state
+type Plan struct {
+	TargetTime               *time.Time    `json:"targetTime"`
+	TargetTimeActive         bool          `json:"targetTimeActive"`
+	TargetTimeProjectedStart *time.Time    `json:"targetTimeProjectedStart"`
+	TargetPlan               *planner.Plan `json:"targetPlan"`
+}
+
+// Remote is the external control state
+type Remote struct {
+	RemoteDisabled       loadpoint.RemoteDemand `json:"remoteDisabled"`
+	RemoteDisabledSource string                 `json:"remoteDisabledSource"`
+	RemoteBudget         *float64               `json:"remoteBudget"` // W
+	RemoteBudgetSource   string                 `json:"remoteBudgetSource,omitempty"`
+}
+
+// Session is the charging session cost and budget state
+type Session struct {
+	Currency               string  `json:"currency"`
+	SessionCost            float64 `json:"sessionCost"`
+	SessionPrice           float64 `json:"sessionPrice"`
+	SessionSolarPercentage float64 `json:"sessionSolarPercentage"`
+	EffectivePrice         float64 `json:"effectivePrice"` // price per kWh of the current charge power
+	BudgetEnergy           float64 `json:"budgetEnergy,omitempty"`
+	BudgetCost             float64 `json:"budgetCost,omitempty"`
+	BudgetExceeded         bool    `json:"budgetExceeded"`
+	GuestSessionActive     bool    `json:"guestSessionActive"`
+	GuestSessionCost       float64 `json:"guestSessionCost,omitempty"`
+	TraceID                string  `json:"traceId,omitempty"` // identifies the decision log of the connected vehicle's session
+}
+
+// Monitor is the charger fault and supply voltage state
+type Monitor struct {
+	Fault            string `json:"fault"`
+	FaultDescription string `json:"faultDescription"`
+	VoltageSag       bool   `json:"voltageSag"`
+	ApiError         string `json:"apiError"` // charger or charge meter communication lost
+}
+
+// Decode converts the structured cache state into the typed model.
+// Values without model field like virtual meters are ignored.
+func Decode(values map[string]interface{}) (Site, error) {
+	var res Site
+
+	b, err := json.Marshal(values)
+	if err == nil {
+		err = json.Unmarshal(b, &res)
+	}
+
+	return res, err
+}
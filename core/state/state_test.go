@@ -0,0 +1,50 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	cache := util.NewCache()
+
+	lp := 0
+	phases := 3
+
+	for _, p := range []util.Param{
+		{Key: SiteTitle, Val: "home"},
+		{Key: GridPower, Val: -1200.0},
+		{Key: BatteryMode, Val: api.BatteryHold},
+		{Key: SavingsSince, Val: int64(1672531200)},
+		{Key: "virtualPower", Val: 100.0},
+		{LoadPoint: &lp, Key: Mode, Val: api.ModePV},
+		{LoadPoint: &lp, Key: ChargePower, Val: 4200.0},
+		{LoadPoint: &lp, Key: ChargeDuration, Val: 90 * time.Minute},
+		{LoadPoint: &lp, Key: PhasesConfigured, Val: phases},
+		{LoadPoint: &lp, Key: VehicleRange, Val: int64(250)},
+		{LoadPoint: &lp, Key: RemoteBudget, Val: nil},
+	} {
+		cache.Add(p.UniqueID(), p)
+	}
+
+	res, err := Decode(cache.State())
+	require.NoError(t, err)
+
+	assert.Equal(t, "home", res.SiteTitle)
+	assert.Equal(t, -1200.0, res.GridPower)
+	assert.Equal(t, api.BatteryHold, res.BatteryMode)
+	assert.Equal(t, int64(1672531200), res.SavingsSince)
+
+	require.Len(t, res.Loadpoints, 1)
+	assert.Equal(t, api.ModePV, res.Loadpoints[0].Mode)
+	assert.Equal(t, 4200.0, res.Loadpoints[0].ChargePower)
+	assert.Equal(t, 90*time.Minute, res.Loadpoints[0].ChargeDuration)
+	assert.Equal(t, &phases, res.Loadpoints[0].PhasesConfigured)
+	assert.Equal(t, int64(250), res.Loadpoints[0].VehicleRange)
+	assert.Nil(t, res.Loadpoints[0].RemoteBudget)
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/tariff"
+)
+
+// StatisticsConfig defines the reference values of the charge statistics
+type StatisticsConfig struct {
+	ReferencePrice float64 `mapstructure:"referencePrice"` // Price of the reference tariff per kWh, default grid price
+	Co2            float64 `mapstructure:"co2"`            // Grid carbon intensity in gCO2eq/kWh if no co2 tariff is configured
+}
+
+// Statistics is the charged energy, cost and co2 aggregated across restarts
+type Statistics = state.ChargeStatistics
+
+// statistics aggregates the solar and grid charged energy with their cost and co2
+type statistics struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	conf    StatisticsConfig
+	tariffs tariff.Tariffs
+	prefix  string // Settings key prefix of additional sites
+	data    Statistics
+}
+
+func newStatistics(conf StatisticsConfig, tariffs tariff.Tariffs) *statistics {
+	s := &statistics{
+		clock:   clock.New(),
+		conf:    conf,
+		tariffs: tariffs,
+	}
+
+	s.load()
+
+	return s
+}
+
+func (s *statistics) load() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = Statistics{}
+	if err := settings.Json(s.prefix+"statistics", &s.data); err != nil || s.data.Since.IsZero() {
+		s.data = Statistics{Since: s.clock.Now()}
+	}
+}
+
+func (s *statistics) save() {
+	_ = settings.SetJson(s.prefix+"statistics", s.data)
+}
+
+// co2 returns the current grid carbon intensity in gCO2eq/kWh
+func (s *statistics) co2() float64 {
+	if s.tariffs.Co2 != nil {
+		if co2, err := s.tariffs.Co2.CurrentPrice(); err == nil {
+			return co2
+		}
+	}
+	return s.conf.Co2
+}
+
+// update adds the grid and solar charged energy in kWh at the current prices
+func (s *statistics) update(p publisher, deltaGrid, deltaSolar, gridPrice, feedinPrice float64) {
+	if deltaGrid == 0 && deltaSolar == 0 {
+		return
+	}
+
+	referencePrice := s.conf.ReferencePrice
+	if referencePrice == 0 {
+		referencePrice = gridPrice
+	}
+
+	co2 := s.co2() / 1e3 // kg/kWh
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := &s.data
+	d.GridCharged += deltaGrid
+	d.SolarCharged += deltaSolar
+	d.Cost += deltaGrid*gridPrice + deltaSolar*feedinPrice
+	d.ReferenceCost += (deltaGrid + deltaSolar) * referencePrice
+	d.Saved = d.ReferenceCost - d.Cost
+	d.Co2Emitted += deltaGrid * co2
+	d.Co2Avoided += deltaSolar * co2
+
+	s.save()
+	p.publish(state.Statistics, s.data)
+}
+
+// get returns the current statistics
+func (s *statistics) get() Statistics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data
+}
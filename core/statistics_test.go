@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatistics(t *testing.T) {
+	s := &statistics{
+		clock: clock.NewMock(),
+		conf:  StatisticsConfig{ReferencePrice: 0.4, Co2: 400},
+	}
+
+	s.update(StubPublisher{}, 10, 30, 0.3, 0.1)
+
+	res := s.get()
+	assert.Equal(t, 10.0, res.GridCharged)
+	assert.Equal(t, 30.0, res.SolarCharged)
+	assert.InDelta(t, 6.0, res.Cost, 1e-9)
+	assert.InDelta(t, 16.0, res.ReferenceCost, 1e-9)
+	assert.InDelta(t, 10.0, res.Saved, 1e-9)
+	assert.InDelta(t, 4.0, res.Co2Emitted, 1e-9)
+	assert.InDelta(t, 12.0, res.Co2Avoided, 1e-9)
+
+	// live carbon intensity, grid price as reference
+	s = &statistics{
+		clock:   clock.NewMock(),
+		tariffs: tariff.Tariffs{Co2: &tariff.Fixed{Price: 200}},
+	}
+
+	s.update(StubPublisher{}, 10, 10, 0.3, 0.1)
+
+	res = s.get()
+	assert.InDelta(t, 2.0, res.Saved, 1e-9)
+	assert.InDelta(t, 2.0, res.Co2Avoided, 1e-9)
+}
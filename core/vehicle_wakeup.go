@@ -0,0 +1,119 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+)
+
+const (
+	wakeUpInterval = 15 * time.Minute // minimum interval between wake-up calls per vehicle to protect the 12V battery
+	wakeUpLead     = 10 * time.Minute // wake-up ahead of the planned charge start
+)
+
+// wakeUpLimiter rate limits the wake-up calls of vehicles across site and loadpoints
+type wakeUpLimiter struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	last  map[api.Vehicle]time.Time
+}
+
+var vehicleWakeUps = newWakeUpLimiter(clock.New())
+
+func newWakeUpLimiter(clock clock.Clock) *wakeUpLimiter {
+	return &wakeUpLimiter{
+		clock: clock,
+		last:  make(map[api.Vehicle]time.Time),
+	}
+}
+
+// allow returns the vehicle's wake-up interface unless it has been woken up recently
+func (l *wakeUpLimiter) allow(v api.Vehicle) (api.Resurrector, error) {
+	vs, ok := v.(api.Resurrector)
+	if !ok {
+		return nil, api.ErrNotAvailable
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.last[v]; ok && l.clock.Since(last) < wakeUpInterval {
+		return nil, api.ErrMustRetry
+	}
+
+	l.last[v] = l.clock.Now()
+
+	return vs, nil
+}
+
+// WakeUpVehicle wakes up the vehicle in the background and refreshes its soc afterwards.
+// It returns api.ErrMustRetry if the vehicle has been woken up within the last 15 minutes.
+func (site *Site) WakeUpVehicle(v api.Vehicle) error {
+	vs, err := vehicleWakeUps.allow(v)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		site.log.DEBUG.Printf("vehicle wake-up: %s", v.Title())
+
+		if err := vs.WakeUp(); err != nil {
+			site.log.ERROR.Printf("vehicle wake-up: %s: %v", v.Title(), err)
+			return
+		}
+
+		// flush stale vehicle data and read soc of the connected loadpoint
+		provider.ResetCached()
+
+		for _, lp := range site.loadpoints {
+			lp := lp
+			lp.addTask(func() {
+				if lp.vehicle == v {
+					lp.refreshVehicleSoC()
+				}
+			})
+		}
+	}()
+
+	return nil
+}
+
+// refreshVehicleSoC requests a soc update of the connected vehicle
+func (lp *LoadPoint) refreshVehicleSoC() {
+	lp.socUpdated = time.Time{}
+}
+
+// planWakeUpVehicle wakes up the vehicle once ahead of the planned charge start for an up to date soc
+func (lp *LoadPoint) planWakeUpVehicle() {
+	start := lp.socTimer.Plan().Start()
+	if start.IsZero() || start.Equal(lp.wakeUpPlanned) || !lp.connected() || lp.vehicle == nil {
+		return
+	}
+
+	if now := lp.clock.Now(); now.Before(start.Add(-wakeUpLead)) || !now.Before(start) {
+		return
+	}
+
+	lp.wakeUpPlanned = start
+
+	vs, err := vehicleWakeUps.allow(lp.vehicle)
+	if err != nil {
+		if !errors.Is(err, api.ErrNotAvailable) {
+			lp.log.DEBUG.Printf("wake-up vehicle: %v", err)
+		}
+		return
+	}
+
+	lp.log.DEBUG.Println("wake-up vehicle: planned charge start")
+
+	if err := vs.WakeUp(); err != nil {
+		lp.log.ERROR.Printf("wake-up vehicle: %v", err)
+		return
+	}
+
+	lp.refreshVehicleSoC()
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wakeUpVehicle struct {
+	*mock.MockVehicle
+	wakeups int
+}
+
+func (v *wakeUpVehicle) WakeUp() error {
+	v.wakeups++
+	return nil
+}
+
+func TestWakeUpLimiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+	l := newWakeUpLimiter(clck)
+
+	_, err := l.allow(mock.NewMockVehicle(ctrl))
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+
+	v := &wakeUpVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+
+	vs, err := l.allow(v)
+	require.NoError(t, err)
+	require.NoError(t, vs.WakeUp())
+
+	clck.Add(time.Minute)
+	_, err = l.allow(v)
+	assert.ErrorIs(t, err, api.ErrMustRetry)
+
+	clck.Add(wakeUpInterval)
+	_, err = l.allow(v)
+	assert.NoError(t, err)
+}
@@ -59,6 +59,14 @@ func (cr *ChargeRater) StartCharge(continued bool) {
 	}
 }
 
+// SetChargedEnergy continues a restored session with the previously charged energy in kWh
+func (cr *ChargeRater) SetChargedEnergy(energy float64) {
+	cr.Lock()
+	defer cr.Unlock()
+
+	cr.chargedEnergy = energy
+}
+
 // StopCharge records meter stop energy. If meter does not supply TotalEnergy,
 // stop time is recorded and accumulating energy though SetChargePower stopped.
 func (cr *ChargeRater) StopCharge() {
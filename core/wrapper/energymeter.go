@@ -0,0 +1,95 @@
+package wrapper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// EnergyMeter derives power from the deltas of a cumulative energy counter
+// for devices not reporting power. Power is only calculated when the counter
+// advances, in between it is limited by the last increment to decay towards zero.
+type EnergyMeter struct {
+	sync.Mutex
+	clck         clock.Clock
+	totalEnergyG func() (float64, error)
+	decay        float64   // smoothing factor of the derived power, 1 disables smoothing
+	energy       float64   // last counter value in kWh
+	updated      time.Time // time of last counter increment
+	increment    float64   // last counter increment in kWh
+	power        *float64  // smoothed power in W
+}
+
+// energyMeterDecay is the default smoothing factor of the derived power
+const energyMeterDecay = 0.5
+
+// NewEnergyMeter creates an energy meter for the given energy getter, 0 decay uses the default smoothing
+func NewEnergyMeter(totalEnergyG func() (float64, error), decay float64) *EnergyMeter {
+	if decay <= 0 || decay > 1 {
+		decay = energyMeterDecay
+	}
+
+	return &EnergyMeter{
+		clck:         clock.New(),
+		totalEnergyG: totalEnergyG,
+		decay:        decay,
+	}
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *EnergyMeter) CurrentPower() (float64, error) {
+	energy, err := m.totalEnergyG()
+	if err != nil {
+		return 0, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.clck.Now()
+
+	switch {
+	// first reading or counter reset
+	case m.updated.IsZero() || energy < m.energy:
+		m.energy = energy
+		m.updated = now
+		m.increment = 0
+		return m.add(0), nil
+
+	// counter advanced
+	case energy > m.energy:
+		m.increment = energy - m.energy
+		power := 3.6e6 * m.increment / now.Sub(m.updated).Seconds() // kWh/s => W
+		m.energy = energy
+		m.updated = now
+		return m.add(power), nil
+	}
+
+	// counter unchanged, the next increment is still pending
+	var power float64
+	if elapsed := now.Sub(m.updated).Seconds(); elapsed > 0 && m.power != nil {
+		power = *m.power
+		if limit := 3.6e6 * m.increment / elapsed; limit < power {
+			power = limit
+		}
+	}
+
+	return m.add(power), nil
+}
+
+// add adds a value to the exponential moving average
+func (m *EnergyMeter) add(power float64) float64 {
+	if m.power == nil {
+		m.power = &power
+	} else {
+		*m.power = power*m.decay + *m.power*(1-m.decay)
+	}
+
+	return *m.power
+}
+
+// TotalEnergy implements the api.MeterEnergy interface
+func (m *EnergyMeter) TotalEnergy() (float64, error) {
+	return m.totalEnergyG()
+}
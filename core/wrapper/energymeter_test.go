@@ -0,0 +1,60 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnergyMeter(t *testing.T) {
+	var energy float64
+	m := NewEnergyMeter(func() (float64, error) { return energy, nil }, 1)
+	clck := clock.NewMock()
+	m.clck = clck
+
+	power := func() float64 {
+		t.Helper()
+		p, err := m.CurrentPower()
+		require.NoError(t, err)
+		return p
+	}
+
+	// first reading
+	energy = 10
+	assert.Equal(t, 0.0, power())
+
+	// 0.01kWh in 36s
+	clck.Add(36 * time.Second)
+	energy = 10.01
+	assert.InDelta(t, 1000, power(), 1e-6)
+
+	// pending increment keeps power
+	clck.Add(30 * time.Second)
+	assert.InDelta(t, 1000, power(), 1e-6)
+
+	// overdue increment limits power
+	clck.Add(42 * time.Second)
+	assert.InDelta(t, 500, power(), 1e-6)
+
+	// counter reset
+	energy = 0
+	assert.Equal(t, 0.0, power())
+}
+
+func TestEnergyMeterDecay(t *testing.T) {
+	var energy float64
+	m := NewEnergyMeter(func() (float64, error) { return energy, nil }, 0)
+	clck := clock.NewMock()
+	m.clck = clck
+
+	_, _ = m.CurrentPower()
+
+	clck.Add(time.Hour)
+	energy = 2
+	p, err := m.CurrentPower()
+	require.NoError(t, err)
+	assert.InDelta(t, 1000, p, 1e-6)
+}
@@ -0,0 +1,81 @@
+package forecast
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Array is a pv array of a single orientation
+type Array struct {
+	Dec int     // panel declination (0 = horizontal, 90 = vertical)
+	Az  int     // panel azimuth (-90 = east, 0 = south, 90 = west)
+	Kwp float64 // installed peak power in kW
+}
+
+// arrays returns the configured arrays, a single array may be configured at top level
+func arrays(single Array, multiple []Array) ([]Array, error) {
+	res := multiple
+	if single.Kwp != 0 {
+		res = append([]Array{single}, res...)
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("missing kwp")
+	}
+
+	for _, a := range res {
+		if a.Kwp <= 0 {
+			return nil, errors.New("missing kwp")
+		}
+	}
+
+	return res, nil
+}
+
+// sumRates adds the power forecasts of multiple arrays. Slots are split at the boundaries of all forecasts,
+// times without forecast of an array do not add power.
+func sumRates(forecasts ...api.Rates) api.Rates {
+	if len(forecasts) == 1 {
+		return forecasts[0]
+	}
+
+	var ts []time.Time
+	for _, rates := range forecasts {
+		for _, r := range rates {
+			ts = append(ts, r.Start, r.End)
+		}
+	}
+
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Before(ts[j])
+	})
+
+	var res api.Rates
+	for i := 1; i < len(ts); i++ {
+		start, end := ts[i-1], ts[i]
+		if !end.After(start) {
+			continue
+		}
+
+		var power float64
+		var covered bool
+		for _, rates := range forecasts {
+			for _, r := range rates {
+				if !r.Start.After(start) && !r.End.Before(end) {
+					power += r.Price
+					covered = true
+					break
+				}
+			}
+		}
+
+		if covered {
+			res = append(res, api.Rate{Start: start, End: end, Price: power})
+		}
+	}
+
+	return res
+}
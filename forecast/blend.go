@@ -0,0 +1,169 @@
+package forecast
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+)
+
+const (
+	blendHorizon = 3 * time.Hour    // correction by the actual pv power decays over this horizon
+	homeAverage  = 30 * time.Minute // averaging time constant of the home consumption
+	minForecast  = 100.0            // W, forecasts below are not corrected
+	maxFactor    = 2.0              // max correction of the forecast
+)
+
+// Blended estimates the pv surplus from the forecast corrected by the actual pv power and reduced by the home consumption
+type Blended struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	forecast api.Rater
+	factor   float64 // actual to forecast pv power
+	home     float64 // average home power
+	updated  time.Time
+}
+
+var _ api.PowerForecaster = (*Blended)(nil)
+
+// Estimate is the current forecast state
+type Estimate struct {
+	Power    float64 // forecast pv power in W
+	Surplus  float64 // estimated pv surplus in W
+	Today    float64 // remaining surplus energy of today in kWh
+	Tomorrow float64 // forecast pv energy of tomorrow in kWh
+}
+
+// NewBlended creates a surplus estimate of the forecast
+func NewBlended(forecast api.Rater) *Blended {
+	return &Blended{
+		clock:    clock.New(),
+		forecast: forecast,
+		factor:   1,
+	}
+}
+
+// power returns the forecast power at the given time
+func power(rates api.Rates, ts time.Time) (float64, bool) {
+	for _, r := range rates {
+		if !ts.Before(r.Start) && ts.Before(r.End) {
+			return r.Price, true
+		}
+	}
+	return 0, false
+}
+
+// Update corrects the forecast by the actual pv power and averages the home consumption
+func (b *Blended) Update(pv, home float64) error {
+	rates, err := b.forecast.Rates()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+
+	b.factor = 1
+	if fc, ok := power(rates, now); ok && fc >= minForecast {
+		b.factor = math.Min(math.Max(pv, 0)/fc, maxFactor)
+	}
+
+	if b.updated.IsZero() {
+		b.home = home
+	} else {
+		alpha := 1 - math.Exp(-float64(now.Sub(b.updated))/float64(homeAverage))
+		b.home += alpha * (home - b.home)
+	}
+	b.updated = now
+
+	return nil
+}
+
+// surplus returns the estimated surplus slots from now on, mu must be held
+func (b *Blended) surplus(rates api.Rates) api.Rates {
+	now := b.clock.Now()
+
+	res := make(api.Rates, 0, len(rates))
+	for _, r := range rates {
+		if !r.End.After(now) {
+			continue
+		}
+
+		if r.Start.Before(now) {
+			r.Start = now
+		}
+
+		weight := math.Max(0, 1-float64(r.Start.Sub(now))/float64(blendHorizon))
+		r.Price = math.Max(0, r.Price*(1+(b.factor-1)*weight)-b.home)
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// PowerForecast implements the api.PowerForecaster interface and returns the estimated surplus power after home consumption
+func (b *Blended) PowerForecast() (api.PowerSlots, error) {
+	rates, err := b.forecast.Rates()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	surplus := b.surplus(rates)
+
+	res := make(api.PowerSlots, 0, len(surplus))
+	for _, r := range surplus {
+		res = append(res, api.PowerSlot{Start: r.Start, End: r.End, Power: r.Price})
+	}
+
+	return res, nil
+}
+
+// Estimate returns the current forecast state
+func (b *Blended) Estimate() (Estimate, error) {
+	rates, err := b.forecast.Rates()
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	var res Estimate
+	res.Power, _ = power(rates, now)
+
+	surplus := b.surplus(rates)
+	res.Surplus, _ = power(surplus, now)
+	res.Today = energy(surplus, now, midnight)
+	res.Tomorrow = energy(rates, midnight, midnight.AddDate(0, 0, 1))
+
+	return res, nil
+}
+
+// energy returns the energy in kWh between from and to
+func energy(rates api.Rates, from, to time.Time) float64 {
+	var res float64
+	for _, r := range rates {
+		start, end := r.Start, r.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			res += r.Price * end.Sub(start).Hours() / 1e3
+		}
+	}
+	return res
+}
@@ -0,0 +1,81 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rater api.Rates
+
+func (r rater) Rates() (api.Rates, error) {
+	return api.Rates(r), nil
+}
+
+func TestSumRates(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	east := api.Rates{{Start: now, End: now.Add(time.Hour), Price: 1000}}
+	west := api.Rates{
+		{Start: now.Add(30 * time.Minute), End: now.Add(90 * time.Minute), Price: 2000},
+	}
+
+	assert.Equal(t, api.Rates{
+		{Start: now, End: now.Add(30 * time.Minute), Price: 1000},
+		{Start: now.Add(30 * time.Minute), End: now.Add(time.Hour), Price: 3000},
+		{Start: now.Add(time.Hour), End: now.Add(90 * time.Minute), Price: 2000},
+	}, sumRates(east, west))
+}
+
+func TestOpenMeteoRates(t *testing.T) {
+	var res openMeteoResponse
+	res.Hourly.Time = []string{"2023-06-01T12:00", "2023-06-01T13:00"}
+	res.Hourly.Irradiance = []float64{800, 400}
+
+	rates, err := res.rates(10 * 0.85)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	assert.Equal(t, time.Date(2023, 6, 1, 11, 0, 0, 0, time.UTC), rates[0].Start.UTC())
+	assert.Equal(t, 6800.0, rates[0].Price)
+	assert.Equal(t, 3400.0, rates[1].Price)
+}
+
+func TestBlended(t *testing.T) {
+	clock := clock.NewMock()
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock.Set(now)
+
+	b := NewBlended(rater{
+		{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Price: 4000},
+		{Start: now.Add(time.Hour), End: now.Add(4 * time.Hour), Price: 4000},
+	})
+	b.clock = clock
+
+	// actual pv below forecast, correction decays over horizon
+	require.NoError(t, b.Update(2000, 500))
+
+	slots, err := b.PowerForecast()
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+
+	assert.Equal(t, now, slots[0].Start)
+	assert.Equal(t, 1500.0, slots[0].Power)                         // 4000 * 0.5 - 500
+	assert.InDelta(t, 4000*(1-0.5*2.0/3)-500, slots[1].Power, 1e-6) // two thirds of the correction left
+
+	est, err := b.Estimate()
+	require.NoError(t, err)
+	assert.Equal(t, 4000.0, est.Power)
+	assert.Equal(t, 1500.0, est.Surplus)
+	assert.Equal(t, 0.0, est.Tomorrow)
+
+	// home consumption is averaged
+	clock.Add(homeAverage)
+	require.NoError(t, b.Update(4000, 1500))
+	assert.InDelta(t, 500+1000*(1-1/2.718281828), b.home, 1e-3)
+	assert.Equal(t, 1.0, b.factor)
+}
@@ -0,0 +1,24 @@
+package forecast
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// NewFromConfig creates new solar forecast from config
+func NewFromConfig(typ string, other map[string]interface{}) (t api.Rater, err error) {
+	switch strings.ToLower(typ) {
+	case "forecast.solar", "forecastsolar":
+		t, err = NewForecastSolar(other)
+	case "solcast":
+		t, err = NewSolcast(other)
+	case "open-meteo", "openmeteo":
+		t, err = NewOpenMeteo(other)
+	default:
+		return nil, errors.New("unknown forecast: " + typ)
+	}
+
+	return
+}
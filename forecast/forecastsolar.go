@@ -0,0 +1,163 @@
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const forecastSolarURI = "https://api.forecast.solar/estimate/%g/%g/%d/%d/%g"
+
+// ForecastSolar provides solar power forecasts from https://forecast.solar
+type ForecastSolar struct {
+	mux  sync.Mutex
+	log  *util.Logger
+	uris []string // per array
+	data api.Rates
+}
+
+var _ api.Rater = (*ForecastSolar)(nil)
+
+type forecastSolarResponse struct {
+	Result struct {
+		Watts map[string]float64 `json:"watts"`
+	} `json:"result"`
+	Message struct {
+		Info struct {
+			Timezone string `json:"timezone"`
+		} `json:"info"`
+	} `json:"message"`
+}
+
+// NewForecastSolar creates a Forecast.Solar forecast of one or more arrays
+func NewForecastSolar(other map[string]interface{}) (*ForecastSolar, error) {
+	var cc struct {
+		Lat, Lon float64
+		Array    `mapstructure:",squash"`
+		Arrays   []Array
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	arrays, err := arrays(cc.Array, cc.Arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ForecastSolar{
+		log: util.NewLogger("forecast"),
+	}
+
+	for _, a := range arrays {
+		t.uris = append(t.uris, fmt.Sprintf(forecastSolarURI, cc.Lat, cc.Lon, a.Dec, a.Az, a.Kwp))
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+// Run updates the forecast hourly
+func (t *ForecastSolar) Run() {
+	client := request.NewHelper(t.log)
+
+	for ; true; <-time.NewTicker(time.Hour).C {
+		data, err := t.update(client)
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		t.mux.Lock()
+		t.data = data
+		t.mux.Unlock()
+	}
+}
+
+// update reads the forecasts of all arrays
+func (t *ForecastSolar) update(client *request.Helper) (api.Rates, error) {
+	forecasts := make([]api.Rates, 0, len(t.uris))
+
+	for _, uri := range t.uris {
+		var res forecastSolarResponse
+		if err := client.GetJSON(uri, &res); err != nil {
+			return nil, err
+		}
+
+		data, err := res.rates()
+		if err != nil {
+			return nil, err
+		}
+
+		forecasts = append(forecasts, data)
+	}
+
+	return sumRates(forecasts...), nil
+}
+
+// rates converts the power forecast into time slots using the average power between timestamps
+func (r forecastSolarResponse) rates() (api.Rates, error) {
+	loc := time.Local
+	if tz := r.Message.Info.Timezone; tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, err
+		}
+	}
+
+	type point struct {
+		ts    time.Time
+		power float64
+	}
+
+	points := make([]point, 0, len(r.Result.Watts))
+	for k, v := range r.Result.Watts {
+		ts, err := time.ParseInLocation("2006-01-02 15:04:05", k, loc)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point{ts, v})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].ts.Before(points[j].ts)
+	})
+
+	var res api.Rates
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+
+		// no forecast between days
+		if prev.ts.YearDay() != cur.ts.YearDay() {
+			continue
+		}
+
+		res = append(res, api.Rate{
+			Start: prev.ts,
+			End:   cur.ts,
+			Price: (prev.power + cur.power) / 2,
+		})
+	}
+
+	return res, nil
+}
+
+// Rates implements the api.Rater interface
+func (t *ForecastSolar) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.data == nil {
+		return nil, errors.New("no forecast available")
+	}
+
+	return t.data, nil
+}
@@ -0,0 +1,150 @@
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const openMeteoURI = "https://api.open-meteo.com/v1/forecast?latitude=%g&longitude=%g&hourly=global_tilted_irradiance&tilt=%d&azimuth=%d&timezone=UTC&forecast_days=3"
+
+// OpenMeteo provides solar power forecasts from the irradiance forecast of https://open-meteo.com
+type OpenMeteo struct {
+	mux      sync.Mutex
+	log      *util.Logger
+	arrays   []Array
+	uris     []string // per array
+	pr       float64
+	interval time.Duration
+	data     api.Rates
+}
+
+var _ api.Rater = (*OpenMeteo)(nil)
+
+type openMeteoResponse struct {
+	Hourly struct {
+		Time       []string  `json:"time"`                     // UTC, end of the averaging hour
+		Irradiance []float64 `json:"global_tilted_irradiance"` // W/m²
+	} `json:"hourly"`
+}
+
+// NewOpenMeteo creates an Open-Meteo forecast of one or more arrays
+func NewOpenMeteo(other map[string]interface{}) (*OpenMeteo, error) {
+	cc := struct {
+		Lat, Lon   float64
+		Array      `mapstructure:",squash"`
+		Arrays     []Array
+		Efficiency float64 // performance ratio including inverter and temperature losses
+		Interval   time.Duration
+	}{
+		Efficiency: 0.85,
+		Interval:   time.Hour,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	arrays, err := arrays(cc.Array, cc.Arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	if cc.Efficiency <= 0 || cc.Efficiency > 1 {
+		return nil, errors.New("invalid efficiency")
+	}
+
+	t := &OpenMeteo{
+		log:      util.NewLogger("open-meteo"),
+		arrays:   arrays,
+		pr:       cc.Efficiency,
+		interval: cc.Interval,
+	}
+
+	for _, a := range arrays {
+		t.uris = append(t.uris, fmt.Sprintf(openMeteoURI, cc.Lat, cc.Lon, a.Dec, a.Az))
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+// Run updates the forecast
+func (t *OpenMeteo) Run() {
+	client := request.NewHelper(t.log)
+
+	for ; true; <-time.NewTicker(t.interval).C {
+		data, err := t.update(client)
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		t.mux.Lock()
+		t.data = data
+		t.mux.Unlock()
+	}
+}
+
+// update reads the forecasts of all arrays
+func (t *OpenMeteo) update(client *request.Helper) (api.Rates, error) {
+	forecasts := make([]api.Rates, 0, len(t.uris))
+
+	for i, uri := range t.uris {
+		var res openMeteoResponse
+		if err := client.GetJSON(uri, &res); err != nil {
+			return nil, err
+		}
+
+		data, err := res.rates(t.arrays[i].Kwp * t.pr)
+		if err != nil {
+			return nil, err
+		}
+
+		forecasts = append(forecasts, data)
+	}
+
+	return sumRates(forecasts...), nil
+}
+
+// rates converts the hourly irradiance into power slots, the array's peak power is rated at 1000W/m²
+func (r openMeteoResponse) rates(kwp float64) (api.Rates, error) {
+	if len(r.Hourly.Time) != len(r.Hourly.Irradiance) {
+		return nil, errors.New("invalid forecast")
+	}
+
+	res := make(api.Rates, 0, len(r.Hourly.Time))
+
+	for i, s := range r.Hourly.Time {
+		ts, err := time.ParseInLocation("2006-01-02T15:04", s, time.UTC)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, api.Rate{
+			Start: ts.Add(-time.Hour).Local(),
+			End:   ts.Local(),
+			Price: r.Hourly.Irradiance[i] * kwp,
+		})
+	}
+
+	return res, nil
+}
+
+// Rates implements the api.Rater interface
+func (t *OpenMeteo) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.data == nil {
+		return nil, errors.New("no forecast available")
+	}
+
+	return t.data, nil
+}
@@ -0,0 +1,162 @@
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const solcastURI = "https://api.solcast.com.au/rooftop_sites/%s/forecasts?format=json"
+
+// Solcast provides solar power forecasts from https://solcast.com
+type Solcast struct {
+	mux      sync.Mutex
+	log      *util.Logger
+	uris     []string // per rooftop site
+	token    string
+	interval time.Duration
+	data     api.Rates
+}
+
+var _ api.Rater = (*Solcast)(nil)
+
+type solcastResponse struct {
+	Forecasts []struct {
+		PvEstimate float64   `json:"pv_estimate"` // kW
+		PeriodEnd  time.Time `json:"period_end"`
+		Period     string    `json:"period"` // ISO 8601 duration like PT30M
+	} `json:"forecasts"`
+}
+
+// NewSolcast creates a Solcast forecast of one or more rooftop sites, e.g. per array
+func NewSolcast(other map[string]interface{}) (*Solcast, error) {
+	cc := struct {
+		Site     string
+		Sites    []string
+		Token    string
+		Interval time.Duration
+	}{
+		Interval: 3 * time.Hour, // hobbyist accounts are limited to 10 requests per day
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	sites := cc.Sites
+	if cc.Site != "" {
+		sites = append([]string{cc.Site}, sites...)
+	}
+
+	if len(sites) == 0 || cc.Token == "" {
+		return nil, errors.New("missing site or token")
+	}
+
+	t := &Solcast{
+		log:      util.NewLogger("solcast").Redact(cc.Token),
+		token:    cc.Token,
+		interval: cc.Interval,
+	}
+
+	for _, site := range sites {
+		t.uris = append(t.uris, fmt.Sprintf(solcastURI, site))
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+// Run updates the forecast
+func (t *Solcast) Run() {
+	client := request.NewHelper(t.log)
+
+	for ; true; <-time.NewTicker(t.interval).C {
+		data, err := t.update(client)
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		t.mux.Lock()
+		t.data = data
+		t.mux.Unlock()
+	}
+}
+
+// update reads the forecasts of all rooftop sites
+func (t *Solcast) update(client *request.Helper) (api.Rates, error) {
+	forecasts := make([]api.Rates, 0, len(t.uris))
+
+	for _, uri := range t.uris {
+		req, err := request.New(http.MethodGet, uri, nil, map[string]string{
+			"Authorization": "Bearer " + t.token,
+			"Accept":        request.JSONContent,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var res solcastResponse
+		if err := client.DoJSON(req, &res); err != nil {
+			return nil, err
+		}
+
+		data, err := res.rates()
+		if err != nil {
+			return nil, err
+		}
+
+		forecasts = append(forecasts, data)
+	}
+
+	return sumRates(forecasts...), nil
+}
+
+// rates converts the forecast periods into time slots with power in W
+func (r solcastResponse) rates() (api.Rates, error) {
+	res := make(api.Rates, 0, len(r.Forecasts))
+
+	for _, f := range r.Forecasts {
+		period, err := parsePeriod(f.Period)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, api.Rate{
+			Start: f.PeriodEnd.Add(-period).Local(),
+			End:   f.PeriodEnd.Local(),
+			Price: f.PvEstimate * 1e3,
+		})
+	}
+
+	return res, nil
+}
+
+// parsePeriod parses ISO 8601 time durations like PT30M
+func parsePeriod(s string) (time.Duration, error) {
+	if len(s) < 3 || s[:2] != "PT" {
+		return 0, fmt.Errorf("invalid period: %s", s)
+	}
+
+	return time.ParseDuration(strings.ToLower(s[2:]))
+}
+
+// Rates implements the api.Rater interface
+func (t *Solcast) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.data == nil {
+		return nil, errors.New("no forecast available")
+	}
+
+	return t.data, nil
+}
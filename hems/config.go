@@ -6,6 +6,7 @@ import (
 
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/hems/ocpp"
+	"github.com/evcc-io/evcc/hems/openadr"
 	"github.com/evcc-io/evcc/hems/semp"
 	"github.com/evcc-io/evcc/server"
 )
@@ -22,6 +23,8 @@ func NewFromConfig(typ string, other map[string]interface{}, site *core.Site, ht
 		return semp.New(other, site, httpd)
 	case "ocpp":
 		return ocpp.New(other, site)
+	case "openadr":
+		return openadr.New(other, site)
 	default:
 		return nil, errors.New("unknown hems: " + typ)
 	}
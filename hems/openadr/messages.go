@@ -0,0 +1,220 @@
+package openadr
+
+import "encoding/xml"
+
+const (
+	nsOADR  = "http://openadr.org/oadr-2.0b/2012/07"
+	nsPyld  = "http://docs.oasis-open.org/ns/energyinterop/201110/payloads"
+	nsEI    = "http://docs.oasis-open.org/ns/energyinterop/201110"
+	nsXCal  = "urn:ietf:params:xml:ns:icalendar-2.0"
+	nsStrm  = "urn:ietf:params:xml:ns:icalendar-2.0:stream"
+	version = "2.0b"
+)
+
+// services of the simple http profile
+const (
+	serviceRegisterParty = "EiRegisterParty"
+	serviceEvent         = "EiEvent"
+	serviceReport        = "EiReport"
+	servicePoll          = "OadrPoll"
+)
+
+// final event status, far, near and active events are evaluated by their intervals
+const (
+	statusCompleted = "completed"
+	statusCancelled = "cancelled"
+)
+
+// opt types of the event response
+const (
+	optIn  = "optIn"
+	optOut = "optOut"
+)
+
+//
+// outgoing messages, element names are prefixed explicitly
+//
+
+// Payload is the outgoing message envelope
+type Payload struct {
+	XMLName  xml.Name     `xml:"oadr:oadrPayload"`
+	XmlnsOA  string       `xml:"xmlns:oadr,attr"`
+	XmlnsPy  string       `xml:"xmlns:pyld,attr"`
+	XmlnsEI  string       `xml:"xmlns:ei,attr"`
+	XmlnsXC  string       `xml:"xmlns:xcal,attr"`
+	XmlnsStr string       `xml:"xmlns:strm,attr"`
+	Signed   SignedObject `xml:"oadr:oadrSignedObject"`
+}
+
+// SignedObject contains exactly one outgoing message
+type SignedObject struct {
+	CreatePartyRegistration *CreatePartyRegistration `xml:"oadr:oadrCreatePartyRegistration,omitempty"`
+	Poll                    *Poll                    `xml:"oadr:oadrPoll,omitempty"`
+	CreatedEvent            *CreatedEvent            `xml:"oadr:oadrCreatedEvent,omitempty"`
+	RegisterReport          *RegisterReport          `xml:"oadr:oadrRegisterReport,omitempty"`
+	RegisteredReport        *RegisteredReport        `xml:"oadr:oadrRegisteredReport,omitempty"`
+	Response                *Response                `xml:"oadr:oadrResponse,omitempty"`
+}
+
+// CreatePartyRegistration registers the VEN with the VTN
+type CreatePartyRegistration struct {
+	SchemaVersion string `xml:"ei:schemaVersion,attr"`
+	RequestID     string `xml:"pyld:requestID"`
+	VenID         string `xml:"ei:venID,omitempty"`
+	ProfileName   string `xml:"oadr:oadrProfileName"`
+	TransportName string `xml:"oadr:oadrTransportName"`
+	ReportOnly    bool   `xml:"oadr:oadrReportOnly"`
+	XMLSignature  bool   `xml:"oadr:oadrXmlSignature"`
+	VenName       string `xml:"oadr:oadrVenName"`
+	HttpPullModel bool   `xml:"oadr:oadrHttpPullModel"`
+}
+
+// Poll requests pending messages from the VTN
+type Poll struct {
+	SchemaVersion string `xml:"ei:schemaVersion,attr"`
+	VenID         string `xml:"ei:venID"`
+}
+
+// CreatedEvent opts in or out of the distributed events
+type CreatedEvent struct {
+	SchemaVersion string         `xml:"ei:schemaVersion,attr"`
+	Created       EiCreatedEvent `xml:"pyld:eiCreatedEvent"`
+}
+
+// EiCreatedEvent is the event response body
+type EiCreatedEvent struct {
+	Response       EiResponse      `xml:"ei:eiResponse"`
+	EventResponses []EventResponse `xml:"ei:eventResponses>ei:eventResponse"`
+	VenID          string          `xml:"ei:venID"`
+}
+
+// EiResponse is the outgoing response status
+type EiResponse struct {
+	Code        string `xml:"ei:responseCode"`
+	Description string `xml:"ei:responseDescription"`
+	RequestID   string `xml:"pyld:requestID"`
+}
+
+// EventResponse is the response to a single event
+type EventResponse struct {
+	Code               string `xml:"ei:responseCode"`
+	Description        string `xml:"ei:responseDescription"`
+	RequestID          string `xml:"pyld:requestID"`
+	EventID            string `xml:"ei:qualifiedEventID>ei:eventID"`
+	ModificationNumber int    `xml:"ei:qualifiedEventID>ei:modificationNumber"`
+	OptType            string `xml:"ei:optType"`
+}
+
+// RegisterReport announces the VEN's reports, evcc offers none
+type RegisterReport struct {
+	SchemaVersion string `xml:"ei:schemaVersion,attr"`
+	RequestID     string `xml:"pyld:requestID"`
+	VenID         string `xml:"ei:venID"`
+}
+
+// RegisteredReport acknowledges the VTN's reports
+type RegisteredReport struct {
+	SchemaVersion string     `xml:"ei:schemaVersion,attr"`
+	Response      EiResponse `xml:"ei:eiResponse"`
+	VenID         string     `xml:"ei:venID"`
+}
+
+// Response acknowledges a VTN request
+type Response struct {
+	SchemaVersion string     `xml:"ei:schemaVersion,attr"`
+	Response      EiResponse `xml:"ei:eiResponse"`
+	VenID         string     `xml:"ei:venID"`
+}
+
+// newPayload wraps the message into the envelope
+func newPayload(msg SignedObject) Payload {
+	return Payload{
+		XmlnsOA:  nsOADR,
+		XmlnsPy:  nsPyld,
+		XmlnsEI:  nsEI,
+		XmlnsXC:  nsXCal,
+		XmlnsStr: nsStrm,
+		Signed:   msg,
+	}
+}
+
+//
+// incoming messages, element names are matched regardless of namespace prefix
+//
+
+// IncomingPayload is the incoming message envelope
+type IncomingPayload struct {
+	XMLName xml.Name       `xml:"oadrPayload"`
+	Signed  IncomingSigned `xml:"oadrSignedObject"`
+}
+
+// IncomingSigned contains exactly one incoming message
+type IncomingSigned struct {
+	CreatedPartyRegistration *CreatedPartyRegistration `xml:"oadrCreatedPartyRegistration"`
+	DistributeEvent          *DistributeEvent          `xml:"oadrDistributeEvent"`
+	Response                 *IncomingResponse         `xml:"oadrResponse"`
+	RegisterReport           *IncomingRequest          `xml:"oadrRegisterReport"`
+	RegisteredReport         *IncomingResponse         `xml:"oadrRegisteredReport"`
+	CreateReport             *IncomingRequest          `xml:"oadrCreateReport"`
+	RequestReregistration    *IncomingRequest          `xml:"oadrRequestReregistration"`
+	CancelPartyRegistration  *IncomingRequest          `xml:"oadrCancelPartyRegistration"`
+}
+
+// IncomingStatus is the incoming response status
+type IncomingStatus struct {
+	Code        string `xml:"responseCode"`
+	Description string `xml:"responseDescription"`
+	RequestID   string `xml:"requestID"`
+}
+
+// IncomingResponse is a response without payload
+type IncomingResponse struct {
+	Response IncomingStatus `xml:"eiResponse"`
+}
+
+// IncomingRequest is a VTN request answered by acknowledgement
+type IncomingRequest struct {
+	RequestID string `xml:"requestID"`
+}
+
+// CreatedPartyRegistration is the registration response
+type CreatedPartyRegistration struct {
+	Response       IncomingStatus `xml:"eiResponse"`
+	RegistrationID string         `xml:"registrationID"`
+	VenID          string         `xml:"venID"`
+	VtnID          string         `xml:"vtnID"`
+	PollFreq       string         `xml:"oadrRequestedOadrPollFreq>duration"`
+}
+
+// DistributeEvent contains all events of the VEN
+type DistributeEvent struct {
+	RequestID string  `xml:"requestID"`
+	VtnID     string  `xml:"vtnID"`
+	Events    []Event `xml:"oadrEvent"`
+}
+
+// Event is a single demand response event
+type Event struct {
+	EventID            string   `xml:"eiEvent>eventDescriptor>eventID"`
+	ModificationNumber int      `xml:"eiEvent>eventDescriptor>modificationNumber"`
+	Status             string   `xml:"eiEvent>eventDescriptor>eventStatus"`
+	Start              string   `xml:"eiEvent>eiActivePeriod>properties>dtstart>date-time"`
+	Duration           string   `xml:"eiEvent>eiActivePeriod>properties>duration>duration"`
+	Signals            []Signal `xml:"eiEvent>eiEventSignals>eiEventSignal"`
+	ResponseRequired   string   `xml:"oadrResponseRequired"`
+}
+
+// Signal is an event signal with its intervals
+type Signal struct {
+	SignalName   string     `xml:"signalName"`
+	SignalType   string     `xml:"signalType"`
+	SignalID     string     `xml:"signalID"`
+	CurrentValue *float64   `xml:"currentValue>payloadFloat>value"`
+	Intervals    []Interval `xml:"intervals>interval"`
+}
+
+// Interval is a signal interval, consecutive from the event start
+type Interval struct {
+	Duration string  `xml:"duration>duration"`
+	Value    float64 `xml:"signalPayload>payloadFloat>value"`
+}
@@ -0,0 +1,477 @@
+package openadr
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/transport"
+	"github.com/google/uuid"
+)
+
+// signalSimple is the mandatory signal of the 2.0b profile, levels 0 (normal) to 3 (special)
+const signalSimple = "SIMPLE"
+
+// OpenADR is an OpenADR 2.0b VEN (virtual end node) using the simple http pull model
+type OpenADR struct {
+	log      *util.Logger
+	clock    clock.Clock
+	site     site.API
+	client   *request.Helper
+	uri      string
+	venName  string
+	venID    string
+	maxPower float64
+	interval time.Duration
+
+	events   []Event
+	optOut   map[string]bool   // events opted out by user override
+	requests map[string]string // distribute request id by event id
+}
+
+// New creates the OpenADR VEN
+func New(conf map[string]interface{}, site site.API) (*OpenADR, error) {
+	cc := struct {
+		URI      string        // VTN url including the profile path, e.g. https://vtn.example.com/OpenADR2/Simple/2.0b
+		VenName  string        // VEN name registered with the utility
+		VenID    string        // optional, assigned by the VTN during registration
+		Cert     string        // client certificate file
+		Key      string        // client key file
+		CA       string        // optional VTN ca certificate file
+		Insecure bool          // skip VTN certificate verification
+		MaxPower float64       // total charge power in W while load shedding is requested
+		Interval time.Duration // poll interval, overrides the VTN's requested poll frequency
+	}{
+		VenName: "evcc",
+	}
+
+	if err := util.DecodeOther(conf, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	log := util.NewLogger("openadr")
+
+	tr := transport.Default()
+	tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: cc.Insecure}
+
+	if cc.Cert != "" || cc.Key != "" {
+		cert, err := tls.LoadX509KeyPair(cc.Cert, cc.Key)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		tr.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cc.CA != "" {
+		b, err := os.ReadFile(cc.CA)
+		if err != nil {
+			return nil, fmt.Errorf("ca certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errors.New("ca certificate: no certificates found")
+		}
+		tr.TLSClientConfig.RootCAs = pool
+	}
+
+	client := request.NewHelper(log)
+	client.Transport = request.NewTripper(log, tr)
+
+	v := &OpenADR{
+		log:      log,
+		clock:    clock.New(),
+		site:     site,
+		client:   client,
+		uri:      strings.TrimSuffix(cc.URI, "/"),
+		venName:  cc.VenName,
+		venID:    cc.VenID,
+		maxPower: cc.MaxPower,
+		interval: cc.Interval,
+		optOut:   make(map[string]bool),
+		requests: make(map[string]string),
+	}
+
+	return v, nil
+}
+
+// Run executes the VEN's registration and poll loop
+func (v *OpenADR) Run() {
+	var registered bool
+
+	for {
+		if !registered {
+			if err := v.register(); err != nil {
+				v.log.ERROR.Printf("registration: %v", err)
+			} else {
+				registered = true
+			}
+		}
+
+		if registered {
+			var err error
+			if registered, err = v.poll(); err != nil {
+				v.log.ERROR.Printf("poll: %v", err)
+			}
+		}
+
+		if err := v.respondOverride(); err != nil {
+			v.log.ERROR.Printf("opt out: %v", err)
+		}
+
+		v.site.SetDemandResponse(v.current())
+
+		interval := v.interval
+		if interval == 0 {
+			interval = 10 * time.Second
+		}
+
+		v.clock.Sleep(interval)
+	}
+}
+
+// send posts the message to the VTN service and decodes the response
+func (v *OpenADR) send(service string, msg SignedObject) (*IncomingSigned, error) {
+	b, err := xml.Marshal(newPayload(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := request.New(http.MethodPost, v.uri+"/"+service, bytes.NewReader(append([]byte(xml.Header), b...)), map[string]string{
+		"Content-Type": "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := v.client.DoBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res IncomingPayload
+	if len(bytes.TrimSpace(body)) > 0 {
+		err = xml.Unmarshal(body, &res)
+	}
+
+	return &res.Signed, err
+}
+
+// status returns an error for failed responses
+func status(s IncomingStatus) error {
+	if s.Code != "" && !strings.HasPrefix(s.Code, "2") {
+		return fmt.Errorf("%s %s", s.Code, s.Description)
+	}
+	return nil
+}
+
+// register obtains the VEN id and the poll frequency
+func (v *OpenADR) register() error {
+	res, err := v.send(serviceRegisterParty, SignedObject{
+		CreatePartyRegistration: &CreatePartyRegistration{
+			SchemaVersion: version,
+			RequestID:     uuid.NewString(),
+			VenID:         v.venID,
+			ProfileName:   version,
+			TransportName: "simpleHttp",
+			VenName:       v.venName,
+			HttpPullModel: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	reg := res.CreatedPartyRegistration
+	if reg == nil {
+		return errors.New("invalid response")
+	}
+
+	if err := status(reg.Response); err != nil {
+		return err
+	}
+
+	if reg.VenID == "" {
+		return errors.New("missing ven id")
+	}
+
+	v.venID = reg.VenID
+	v.log.INFO.Printf("registered as ven %s at vtn %s", v.venID, reg.VtnID)
+
+	if v.interval == 0 && reg.PollFreq != "" {
+		if d, err := parseDuration(reg.PollFreq); err == nil && d > 0 {
+			v.interval = d
+		}
+	}
+
+	// evcc does not offer reports
+	_, err = v.send(serviceReport, SignedObject{
+		RegisterReport: &RegisterReport{
+			SchemaVersion: version,
+			RequestID:     uuid.NewString(),
+			VenID:         v.venID,
+		},
+	})
+
+	return err
+}
+
+// poll handles the VTN's pending messages and returns false if registration is required
+func (v *OpenADR) poll() (bool, error) {
+	res, err := v.send(servicePoll, SignedObject{
+		Poll: &Poll{SchemaVersion: version, VenID: v.venID},
+	})
+	if err != nil {
+		return true, err
+	}
+
+	switch {
+	case res.DistributeEvent != nil:
+		return true, v.distribute(res.DistributeEvent)
+
+	case res.RegisterReport != nil:
+		_, err := v.send(serviceReport, SignedObject{
+			RegisteredReport: &RegisteredReport{
+				SchemaVersion: version,
+				Response:      EiResponse{Code: "200", Description: "OK", RequestID: res.RegisterReport.RequestID},
+				VenID:         v.venID,
+			},
+		})
+		return true, err
+
+	case res.CreateReport != nil:
+		_, err := v.send(serviceReport, SignedObject{
+			Response: &Response{
+				SchemaVersion: version,
+				Response:      EiResponse{Code: "200", Description: "OK", RequestID: res.CreateReport.RequestID},
+				VenID:         v.venID,
+			},
+		})
+		return true, err
+
+	case res.RequestReregistration != nil, res.CancelPartyRegistration != nil:
+		v.log.INFO.Println("registration requested by vtn")
+		return false, nil
+
+	case res.Response != nil:
+		return true, status(res.Response.Response)
+	}
+
+	return true, nil
+}
+
+// distribute replaces the events and opts in unless overridden
+func (v *OpenADR) distribute(msg *DistributeEvent) error {
+	v.events = msg.Events
+
+	// events not distributed anymore are implicitly cancelled
+	requests := make(map[string]string)
+	var responses []EventResponse
+
+	for _, ev := range msg.Events {
+		requests[ev.EventID] = msg.RequestID
+
+		if ev.ResponseRequired == "never" {
+			continue
+		}
+
+		opt := optIn
+		if v.optOut[ev.EventID] {
+			opt = optOut
+		}
+
+		responses = append(responses, v.eventResponse(ev, msg.RequestID, opt))
+	}
+
+	v.requests = requests
+
+	for id := range v.optOut {
+		if _, ok := requests[id]; !ok {
+			delete(v.optOut, id)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+
+	return v.created(responses)
+}
+
+func (v *OpenADR) eventResponse(ev Event, requestID, opt string) EventResponse {
+	return EventResponse{
+		Code:               "200",
+		Description:        "OK",
+		RequestID:          requestID,
+		EventID:            ev.EventID,
+		ModificationNumber: ev.ModificationNumber,
+		OptType:            opt,
+	}
+}
+
+// created sends the event responses
+func (v *OpenADR) created(responses []EventResponse) error {
+	res, err := v.send(serviceEvent, SignedObject{
+		CreatedEvent: &CreatedEvent{
+			SchemaVersion: version,
+			Created: EiCreatedEvent{
+				Response:       EiResponse{Code: "200", Description: "OK"},
+				EventResponses: responses,
+				VenID:          v.venID,
+			},
+		},
+	})
+
+	if err == nil && res.Response != nil {
+		err = status(res.Response.Response)
+	}
+
+	return err
+}
+
+// respondOverride opts out of the current event once the user overrides it for emergency charging
+func (v *OpenADR) respondOverride() error {
+	if !v.site.GetDemandResponseOverride() {
+		return nil
+	}
+
+	ev, ok := v.active()
+	if !ok || v.optOut[ev.EventID] {
+		return nil
+	}
+
+	v.optOut[ev.EventID] = true
+	v.log.WARN.Printf("opting out of event %s", ev.EventID)
+
+	if ev.ResponseRequired == "never" {
+		return nil
+	}
+
+	return v.created([]EventResponse{v.eventResponse(ev, v.requests[ev.EventID], optOut)})
+}
+
+// active returns the current or next pending event
+func (v *OpenADR) active() (Event, bool) {
+	c := v.current()
+	if c == nil {
+		return Event{}, false
+	}
+
+	for _, ev := range v.events {
+		if ev.EventID == c.ID {
+			return ev, true
+		}
+	}
+
+	return Event{}, false
+}
+
+// current returns the current or next load-shed interval of all events
+func (v *OpenADR) current() *state.DemandResponseEvent {
+	now := v.clock.Now()
+
+	var res *state.DemandResponseEvent
+	for _, ev := range v.events {
+		if ev.Status == statusCompleted || ev.Status == statusCancelled {
+			continue
+		}
+
+		dr, err := v.shed(ev, now)
+		if err != nil {
+			v.log.ERROR.Printf("event %s: %v", ev.EventID, err)
+			continue
+		}
+
+		if dr != nil && (res == nil || dr.Start.Before(res.Start)) {
+			res = dr
+		}
+	}
+
+	return res
+}
+
+// shed returns the current or next interval of the event requesting load shedding
+func (v *OpenADR) shed(ev Event, now time.Time) (*state.DemandResponseEvent, error) {
+	start, err := time.Parse(time.RFC3339, ev.Start)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range ev.Signals {
+		if !strings.EqualFold(s.SignalName, signalSimple) {
+			v.log.DEBUG.Printf("event %s: ignoring signal %s", ev.EventID, s.SignalName)
+			continue
+		}
+
+		ts := start
+		for _, i := range s.Intervals {
+			d, err := parseDuration(i.Duration)
+			if err != nil {
+				return nil, err
+			}
+
+			end := ts.Add(d)
+			if i.Value > 0 && end.After(now) {
+				return &state.DemandResponseEvent{
+					ID:     ev.EventID,
+					Start:  ts,
+					End:    end,
+					Limit:  v.maxPower,
+					Reason: fmt.Sprintf("utility load shed level %.0f", i.Value),
+				}, nil
+			}
+
+			ts = end
+		}
+	}
+
+	return nil, nil
+}
+
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseDuration parses xcal durations like PT1H30M or P1D
+func parseDuration(s string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+
+	var res time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] == "" {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(m[i+2], 64)
+		if err != nil {
+			return 0, err
+		}
+
+		res += time.Duration(f * float64(unit))
+	}
+
+	if m[1] == "-" {
+		res = -res
+	}
+
+	return res, nil
+}
@@ -0,0 +1,175 @@
+package openadr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSite struct {
+	site.API
+	override bool
+	event    *state.DemandResponseEvent
+}
+
+func (s *testSite) GetDemandResponseOverride() bool {
+	return s.override
+}
+
+func (s *testSite) SetDemandResponse(ev *state.DemandResponseEvent) {
+	s.event = ev
+}
+
+const payload = `<?xml version="1.0" encoding="UTF-8"?>
+<oadr:oadrPayload xmlns:oadr="http://openadr.org/oadr-2.0b/2012/07" xmlns:ei="http://docs.oasis-open.org/ns/energyinterop/201110" xmlns:pyld="http://docs.oasis-open.org/ns/energyinterop/201110/payloads" xmlns:xcal="urn:ietf:params:xml:ns:icalendar-2.0" xmlns:strm="urn:ietf:params:xml:ns:icalendar-2.0:stream">
+<oadr:oadrSignedObject>%s</oadr:oadrSignedObject>
+</oadr:oadrPayload>`
+
+const registered = `<oadr:oadrCreatedPartyRegistration ei:schemaVersion="2.0b">
+	<ei:eiResponse><ei:responseCode>200</ei:responseCode><ei:responseDescription>OK</ei:responseDescription><pyld:requestID>r1</pyld:requestID></ei:eiResponse>
+	<ei:registrationID>reg1</ei:registrationID>
+	<ei:venID>ven1</ei:venID>
+	<ei:vtnID>vtn1</ei:vtnID>
+	<oadr:oadrRequestedOadrPollFreq><xcal:duration>PT30S</xcal:duration></oadr:oadrRequestedOadrPollFreq>
+</oadr:oadrCreatedPartyRegistration>`
+
+const distribute = `<oadr:oadrDistributeEvent ei:schemaVersion="2.0b">
+	<pyld:requestID>dist1</pyld:requestID>
+	<ei:vtnID>vtn1</ei:vtnID>
+	<oadr:oadrEvent>
+		<ei:eiEvent>
+			<ei:eventDescriptor>
+				<ei:eventID>ev1</ei:eventID>
+				<ei:modificationNumber>2</ei:modificationNumber>
+				<ei:eventStatus>far</ei:eventStatus>
+			</ei:eventDescriptor>
+			<ei:eiActivePeriod>
+				<xcal:properties>
+					<xcal:dtstart><xcal:date-time>2022-11-18T16:00:00Z</xcal:date-time></xcal:dtstart>
+					<xcal:duration><xcal:duration>PT2H</xcal:duration></xcal:duration>
+				</xcal:properties>
+			</ei:eiActivePeriod>
+			<ei:eiEventSignals>
+				<ei:eiEventSignal>
+					<strm:intervals>
+						<ei:interval>
+							<xcal:duration><xcal:duration>PT1H</xcal:duration></xcal:duration>
+							<ei:signalPayload><ei:payloadFloat><ei:value>0</ei:value></ei:payloadFloat></ei:signalPayload>
+						</ei:interval>
+						<ei:interval>
+							<xcal:duration><xcal:duration>PT1H</xcal:duration></xcal:duration>
+							<ei:signalPayload><ei:payloadFloat><ei:value>2</ei:value></ei:payloadFloat></ei:signalPayload>
+						</ei:interval>
+					</strm:intervals>
+					<ei:signalName>SIMPLE</ei:signalName>
+					<ei:signalType>level</ei:signalType>
+					<ei:signalID>s1</ei:signalID>
+					<ei:currentValue><ei:payloadFloat><ei:value>0</ei:value></ei:payloadFloat></ei:currentValue>
+				</ei:eiEventSignal>
+			</ei:eiEventSignals>
+		</ei:eiEvent>
+		<oadr:oadrResponseRequired>always</oadr:oadrResponseRequired>
+	</oadr:oadrEvent>
+</oadr:oadrDistributeEvent>`
+
+const response = `<oadr:oadrResponse ei:schemaVersion="2.0b"><ei:eiResponse><ei:responseCode>200</ei:responseCode><ei:responseDescription>OK</ei:responseDescription><pyld:requestID></pyld:requestID></ei:eiResponse></oadr:oadrResponse>`
+
+func TestOpenADR(t *testing.T) {
+	var mu sync.Mutex
+	var created []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var res string
+		switch {
+		case strings.HasSuffix(r.URL.Path, serviceRegisterParty):
+			res = registered
+		case strings.HasSuffix(r.URL.Path, servicePoll):
+			res = distribute
+		case strings.HasSuffix(r.URL.Path, serviceEvent):
+			created = append(created, string(b))
+			res = response
+		default:
+			res = response
+		}
+
+		_, _ = w.Write([]byte(strings.Replace(payload, "%s", res, 1)))
+	}))
+	defer srv.Close()
+
+	ts := &testSite{}
+	v, err := New(map[string]interface{}{"uri": srv.URL + "/OpenADR2/Simple/2.0b", "maxPower": 2000}, ts)
+	require.NoError(t, err)
+
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 11, 18, 16, 30, 0, 0, time.UTC))
+	v.clock = clck
+
+	require.NoError(t, v.register())
+	assert.Equal(t, "ven1", v.venID)
+	assert.Equal(t, 30*time.Second, v.interval)
+
+	ok, err := v.poll()
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.Len(t, created, 1)
+	assert.Contains(t, created[0], "<ei:optType>optIn</ei:optType>")
+	assert.Contains(t, created[0], "<pyld:requestID>dist1</pyld:requestID>")
+	assert.Contains(t, created[0], "<ei:modificationNumber>2</ei:modificationNumber>")
+
+	// first interval is level 0, load shedding starts with the second interval
+	start := time.Date(2022, 11, 18, 17, 0, 0, 0, time.UTC)
+	assert.Equal(t, &state.DemandResponseEvent{
+		ID:     "ev1",
+		Start:  start,
+		End:    start.Add(time.Hour),
+		Limit:  2000,
+		Reason: "utility load shed level 2",
+	}, v.current())
+
+	// user override opts out once
+	ts.override = true
+	require.NoError(t, v.respondOverride())
+	require.NoError(t, v.respondOverride())
+	require.Len(t, created, 2)
+	assert.Contains(t, created[1], "<ei:optType>optOut</ei:optType>")
+
+	// event ends
+	clck.Add(2 * time.Hour)
+	assert.Nil(t, v.current())
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, d := range map[string]time.Duration{
+		"PT1H":    time.Hour,
+		"PT1H30M": 90 * time.Minute,
+		"PT10S":   10 * time.Second,
+		"P1D":     24 * time.Hour,
+		"P1DT2H":  26 * time.Hour,
+		"PT0S":    0,
+		"-PT5M":   -5 * time.Minute,
+		"PT0.5S":  500 * time.Millisecond,
+	} {
+		res, err := parseDuration(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, d, res, s)
+	}
+
+	_, err := parseDuration("1h")
+	assert.Error(t, err)
+}
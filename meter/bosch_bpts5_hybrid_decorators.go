@@ -7,11 +7,16 @@ import (
 )
 
 func decorateBoschBpts5Hybrid(base api.Meter, battery func() (float64, error)) api.Meter {
-	switch {
-	case battery == nil:
+	var caps int
+	if battery != nil {
+		caps |= 1
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case battery != nil:
+	case 1: // api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -7,11 +7,19 @@ import (
 )
 
 func decorateDsmr(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error)) api.Meter {
-	switch {
-	case meterCurrent == nil && meterEnergy == nil:
+	var caps int
+	if meterEnergy != nil {
+		caps |= 1
+	}
+	if meterCurrent != nil {
+		caps |= 2
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case meterCurrent == nil && meterEnergy != nil:
+	case 1: // api.MeterEnergy
 		return &struct {
 			api.Meter
 			api.MeterEnergy
@@ -22,7 +30,7 @@ func decorateDsmr(base api.Meter, meterEnergy func() (float64, error), meterCurr
 			},
 		}
 
-	case meterCurrent != nil && meterEnergy == nil:
+	case 2: // api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -33,7 +41,7 @@ func decorateDsmr(base api.Meter, meterEnergy func() (float64, error), meterCurr
 			},
 		}
 
-	case meterCurrent != nil && meterEnergy != nil:
+	case 3: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
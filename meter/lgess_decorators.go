@@ -7,11 +7,19 @@ import (
 )
 
 func decorateLgEss(base *LgEss, meterEnergy func() (float64, error), battery func() (float64, error)) api.Meter {
-	switch {
-	case battery == nil && meterEnergy == nil:
+	var caps int
+	if meterEnergy != nil {
+		caps |= 1
+	}
+	if battery != nil {
+		caps |= 2
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case battery == nil && meterEnergy != nil:
+	case 1: // api.MeterEnergy
 		return &struct {
 			*LgEss
 			api.MeterEnergy
@@ -22,7 +30,7 @@ func decorateLgEss(base *LgEss, meterEnergy func() (float64, error), battery fun
 			},
 		}
 
-	case battery != nil && meterEnergy == nil:
+	case 2: // api.Battery
 		return &struct {
 			*LgEss
 			api.Battery
@@ -33,7 +41,7 @@ func decorateLgEss(base *LgEss, meterEnergy func() (float64, error), battery fun
 			},
 		}
 
-	case battery != nil && meterEnergy != nil:
+	case 3: // api.MeterEnergy, api.Battery
 		return &struct {
 			*LgEss
 			api.Battery
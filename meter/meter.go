@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/wrapper"
 	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/util"
 )
@@ -13,27 +14,28 @@ func init() {
 	registry.Add(api.Custom, NewConfigurableFromConfig)
 }
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateMeter -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateMeter -b api.Meter -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.MeterCurrent,Currents,func() (float64, float64, float64, error)" -t "api.Battery,SoC,func() (float64, error)" -t "api.BatteryController,SetBatteryMode,func(api.BatteryMode) error"
 
 // NewConfigurableFromConfig creates api.Meter from config
 func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error) {
 	var cc struct {
-		Power    provider.Config
-		Energy   *provider.Config  // optional
-		SoC      *provider.Config  // optional
-		Currents []provider.Config // optional
+		Power       *provider.Config  // optional if energy is configured
+		Energy      *provider.Config  // optional
+		Decay       float64           // smoothing of the power derived from energy
+		SoC         *provider.Config  // optional
+		Currents    []provider.Config // optional
+		BatteryMode *provider.Config  // optional
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
 		return nil, err
 	}
 
-	power, err := provider.NewFloatGetterFromConfig(cc.Power)
-	if err != nil {
-		return nil, fmt.Errorf("power: %w", err)
+	if cc.Power == nil && cc.Energy == nil {
+		return nil, errors.New("missing power or energy")
 	}
 
-	m, _ := NewConfigurable(power)
+	var err error
 
 	// decorate Meter with MeterEnergy
 	var totalEnergyG func() (float64, error)
@@ -44,6 +46,19 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error)
 		}
 	}
 
+	// derive power from energy if not available
+	var power func() (float64, error)
+	if cc.Power != nil {
+		power, err = provider.NewFloatGetterFromConfig(*cc.Power)
+		if err != nil {
+			return nil, fmt.Errorf("power: %w", err)
+		}
+	} else {
+		power = wrapper.NewEnergyMeter(totalEnergyG, cc.Decay).CurrentPower
+	}
+
+	m, _ := NewConfigurable(power)
+
 	// decorate Meter with MeterCurrent
 	var currentsG func() (float64, float64, float64, error)
 	if len(cc.Currents) > 0 {
@@ -73,7 +88,23 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Meter, error)
 		}
 	}
 
-	res := m.Decorate(totalEnergyG, currentsG, batterySoCG)
+	// decorate Meter with BatteryController
+	var batteryModeS func(api.BatteryMode) error
+	if cc.BatteryMode != nil {
+		holdS, err := provider.NewBoolSetterFromConfig("hold", *cc.BatteryMode)
+		if err != nil {
+			return nil, fmt.Errorf("batteryMode: %w", err)
+		}
+
+		batteryModeS = func(mode api.BatteryMode) error {
+			if mode == api.BatteryCharge {
+				return api.ErrNotAvailable
+			}
+			return holdS(mode == api.BatteryHold)
+		}
+	}
+
+	res := m.Decorate(totalEnergyG, currentsG, batterySoCG, batteryModeS)
 
 	return res, nil
 }
@@ -113,8 +144,9 @@ func (m *Meter) Decorate(
 	totalEnergy func() (float64, error),
 	currents func() (float64, float64, float64, error),
 	batterySoC func() (float64, error),
+	batteryMode func(api.BatteryMode) error,
 ) api.Meter {
-	return decorateMeter(m, totalEnergy, currents, batterySoC)
+	return decorateMeter(m, totalEnergy, currents, batterySoC, batteryMode)
 }
 
 // CurrentPower implements the api.Meter interface
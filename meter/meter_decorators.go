@@ -6,12 +6,26 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error)) api.Meter {
-	switch {
-	case battery == nil && meterCurrent == nil && meterEnergy == nil:
+func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error), batteryController func(mode api.BatteryMode) error) api.Meter {
+	var caps int
+	if meterEnergy != nil {
+		caps |= 1
+	}
+	if meterCurrent != nil {
+		caps |= 2
+	}
+	if battery != nil {
+		caps |= 4
+	}
+	if batteryController != nil {
+		caps |= 8
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case battery == nil && meterCurrent == nil && meterEnergy != nil:
+	case 1: // api.MeterEnergy
 		return &struct {
 			api.Meter
 			api.MeterEnergy
@@ -22,7 +36,7 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy == nil:
+	case 2: // api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -33,7 +47,7 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy != nil:
+	case 3: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -48,7 +62,7 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy == nil:
+	case 4: // api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -59,7 +73,7 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy != nil:
+	case 5: // api.MeterEnergy, api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -74,7 +88,7 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy == nil:
+	case 6: // api.MeterCurrent, api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -89,7 +103,7 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy != nil:
+	case 7: // api.MeterEnergy, api.MeterCurrent, api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -107,6 +121,142 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), meterCur
 				meterEnergy: meterEnergy,
 			},
 		}
+
+	case 8: // api.BatteryController
+		return &struct {
+			api.Meter
+			api.BatteryController
+		}{
+			Meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+		}
+
+	case 9: // api.MeterEnergy, api.BatteryController
+		return &struct {
+			api.Meter
+			api.BatteryController
+			api.MeterEnergy
+		}{
+			Meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 10: // api.MeterCurrent, api.BatteryController
+		return &struct {
+			api.Meter
+			api.BatteryController
+			api.MeterCurrent
+		}{
+			Meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 11: // api.MeterEnergy, api.MeterCurrent, api.BatteryController
+		return &struct {
+			api.Meter
+			api.BatteryController
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 12: // api.Battery, api.BatteryController
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+		}
+
+	case 13: // api.MeterEnergy, api.Battery, api.BatteryController
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case 14: // api.MeterCurrent, api.Battery, api.BatteryController
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+			api.MeterCurrent
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+		}
+
+	case 15: // api.MeterEnergy, api.MeterCurrent, api.Battery, api.BatteryController
+		return &struct {
+			api.Meter
+			api.Battery
+			api.BatteryController
+			api.MeterCurrent
+			api.MeterEnergy
+		}{
+			Meter: base,
+			Battery: &decorateMeterBatteryImpl{
+				battery: battery,
+			},
+			BatteryController: &decorateMeterBatteryControllerImpl{
+				batteryController: batteryController,
+			},
+			MeterCurrent: &decorateMeterMeterCurrentImpl{
+				meterCurrent: meterCurrent,
+			},
+			MeterEnergy: &decorateMeterMeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
 	}
 
 	return nil
@@ -120,6 +270,14 @@ func (impl *decorateMeterBatteryImpl) SoC() (float64, error) {
 	return impl.battery()
 }
 
+type decorateMeterBatteryControllerImpl struct {
+	batteryController func(mode api.BatteryMode) error
+}
+
+func (impl *decorateMeterBatteryControllerImpl) SetBatteryMode(mode api.BatteryMode) error {
+	return impl.batteryController(mode)
+}
+
 type decorateMeterMeterCurrentImpl struct {
 	meterCurrent func() (float64, float64, float64, error)
 }
@@ -0,0 +1,144 @@
+package meter
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.Add("filter", NewFilterFromConfig)
+}
+
+// NewFilterFromConfig creates api.Meter from config
+func NewFilterFromConfig(other map[string]interface{}) (api.Meter, error) {
+	cc := struct {
+		Sign    string  // invert or positive
+		Spike   float64 // W deviation from last value rejected as spike
+		Spikes  int     // consecutive spikes rejected before accepting the new level
+		MaxRate float64 // W/s max rate of change
+		Meter   struct {
+			Type  string
+			Other map[string]interface{} `mapstructure:",remain"`
+		}
+	}{
+		Spikes: 1,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	switch cc.Sign {
+	case "", "invert", "positive":
+	default:
+		return nil, fmt.Errorf("invalid sign: %s", cc.Sign)
+	}
+
+	m, err := NewFromConfig(cc.Meter.Type, cc.Meter.Other)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Filter{
+		clock:         clock.New(),
+		sign:          cc.Sign,
+		spike:         cc.Spike,
+		spikes:        cc.Spikes,
+		maxRate:       cc.MaxRate,
+		currentPowerG: m.CurrentPower,
+	}
+
+	meter, _ := NewConfigurable(f.CurrentPower)
+
+	// decorate energy reading
+	var totalEnergy func() (float64, error)
+	if m, ok := m.(api.MeterEnergy); ok {
+		totalEnergy = m.TotalEnergy
+	}
+
+	// decorate battery reading
+	var batterySoC func() (float64, error)
+	if m, ok := m.(api.Battery); ok {
+		batterySoC = m.SoC
+	}
+
+	// decorate currents reading
+	var currents func() (float64, float64, float64, error)
+	if m, ok := m.(api.MeterCurrent); ok {
+		currents = m.Currents
+	}
+
+	// decorate battery control
+	var batteryMode func(api.BatteryMode) error
+	if m, ok := m.(api.BatteryController); ok {
+		batteryMode = m.SetBatteryMode
+	}
+
+	res := meter.Decorate(totalEnergy, currents, batterySoC, batteryMode)
+
+	return res, nil
+}
+
+// Filter applies plausibility checks to meter power readings
+type Filter struct {
+	clock         clock.Clock
+	sign          string
+	spike         float64
+	spikes        int
+	maxRate       float64
+	rejected      int
+	value         *float64
+	updated       time.Time
+	currentPowerG func() (float64, error)
+}
+
+func (m *Filter) CurrentPower() (float64, error) {
+	power, err := m.currentPowerG()
+	if err != nil {
+		return power, err
+	}
+
+	return m.add(power), nil
+}
+
+// add filters the reading and returns the plausible power value
+func (m *Filter) add(value float64) float64 {
+	switch m.sign {
+	case "invert":
+		value = -value
+	case "positive":
+		value = math.Abs(value)
+	}
+
+	if m.value == nil {
+		m.value = &value
+		m.updated = m.clock.Now()
+		return value
+	}
+
+	last := *m.value
+
+	// reject single spikes, accept the new level if it persists
+	if m.spike > 0 && math.Abs(value-last) > m.spike && m.rejected < m.spikes {
+		m.rejected++
+		return last
+	}
+	m.rejected = 0
+
+	// limit rate of change
+	if m.maxRate > 0 {
+		if max := m.maxRate * m.clock.Since(m.updated).Seconds(); math.Abs(value-last) > max {
+			value = last + math.Copysign(max, value-last)
+		}
+	}
+
+	*m.value = value
+	m.updated = m.clock.Now()
+
+	return value
+}
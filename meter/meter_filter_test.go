@@ -0,0 +1,37 @@
+package meter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	clck := clock.NewMock()
+
+	tc := []struct {
+		name   string
+		filter Filter
+		values []float64
+		res    []float64
+	}{
+		{"invert", Filter{sign: "invert"}, []float64{100, -200}, []float64{-100, 200}},
+		{"positive", Filter{sign: "positive"}, []float64{100, -200}, []float64{100, 200}},
+		{"spike", Filter{spike: 1000, spikes: 1}, []float64{100, 5000, 200, 5000, 5000, 5100}, []float64{100, 100, 200, 200, 5000, 5100}},
+		{"rate", Filter{maxRate: 100}, []float64{0, 5000, 5000, -1000}, []float64{0, 1000, 2000, 1000}},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			f := tc.filter
+			f.clock = clck
+
+			for i, v := range tc.values {
+				clck.Add(10 * time.Second)
+				assert.Equal(t, tc.res[i], f.add(v), "value %d", i)
+			}
+		})
+	}
+}
@@ -7,11 +7,22 @@ import (
 )
 
 func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCurrent func() (float64, float64, float64, error), battery func() (float64, error)) api.Meter {
-	switch {
-	case battery == nil && meterCurrent == nil && meterEnergy == nil:
+	var caps int
+	if meterEnergy != nil {
+		caps |= 1
+	}
+	if meterCurrent != nil {
+		caps |= 2
+	}
+	if battery != nil {
+		caps |= 4
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case battery == nil && meterCurrent == nil && meterEnergy != nil:
+	case 1: // api.MeterEnergy
 		return &struct {
 			api.Meter
 			api.MeterEnergy
@@ -22,7 +33,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy == nil:
+	case 2: // api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -33,7 +44,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery == nil && meterCurrent != nil && meterEnergy != nil:
+	case 3: // api.MeterEnergy, api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
@@ -48,7 +59,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy == nil:
+	case 4: // api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -59,7 +70,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent == nil && meterEnergy != nil:
+	case 5: // api.MeterEnergy, api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -74,7 +85,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy == nil:
+	case 6: // api.MeterCurrent, api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
@@ -89,7 +100,7 @@ func decorateModbus(base api.Meter, meterEnergy func() (float64, error), meterCu
 			},
 		}
 
-	case battery != nil && meterCurrent != nil && meterEnergy != nil:
+	case 7: // api.MeterEnergy, api.MeterCurrent, api.Battery
 		return &struct {
 			api.Meter
 			api.Battery
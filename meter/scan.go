@@ -0,0 +1,220 @@
+package meter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/modbus"
+	gridx "github.com/grid-x/modbus"
+	"github.com/volkszaehler/mbmd/meters"
+	"github.com/volkszaehler/mbmd/meters/rs485"
+	"github.com/volkszaehler/mbmd/meters/sunspec"
+)
+
+// e3dcMagic is the E3/DC "Simple-Mode" identifier at register 40000
+const e3dcMagic = 0xE3DC
+
+// scanVoltages are the reference phase voltages for validating rs485 probe results
+var scanVoltages = []float64{110, 230}
+
+// ScanResult is a meter detected on the modbus bus
+type ScanResult struct {
+	ID          uint8
+	Model       string // rs485 model, sunspec or e3dc
+	Description string
+}
+
+// validVoltage returns true if the probed voltage is within 10% of a reference voltage
+func validVoltage(u float64) bool {
+	for _, ref := range scanVoltages {
+		if u >= 0.9*ref && u <= 1.1*ref {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIDs parses comma separated modbus ids and ranges like 1-10,126
+func ParseIDs(s string) ([]uint8, error) {
+	var res []uint8
+
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			to = from
+		}
+
+		lo, err := strconv.ParseUint(strings.TrimSpace(from), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id: %s", part)
+		}
+
+		hi, err := strconv.ParseUint(strings.TrimSpace(to), 10, 8)
+		if err != nil || hi < lo || lo == 0 || hi > 247 {
+			return nil, fmt.Errorf("invalid id: %s", part)
+		}
+
+		for id := lo; id <= hi; id++ {
+			res = append(res, uint8(id))
+		}
+	}
+
+	return res, nil
+}
+
+// scanConnection creates the physical bus connection for scanning
+func scanConnection(s modbus.Settings) (meters.Connection, error) {
+	switch {
+	case s.URI != "" && s.Device != "":
+		return nil, errors.New("can only have either uri or device")
+
+	case s.URI != "":
+		uri := util.DefaultPort(s.URI, 502)
+		if modbus.ProtocolFromRTU(s.RTU) == modbus.Rtu {
+			return meters.NewRTUOverTCP(uri), nil
+		}
+		return meters.NewTCP(uri), nil
+
+	case s.Device != "":
+		if s.Baudrate == 0 || s.Comset == "" {
+			return nil, errors.New("need baudrate and comset")
+		}
+		return meters.NewRTU(s.Device, s.Baudrate, s.Comset), nil
+
+	default:
+		return nil, errors.New("need either uri or device")
+	}
+}
+
+// scanE3dc detects E3/DC devices by their simple mode identifier
+func scanE3dc(client gridx.Client) bool {
+	b, err := client.ReadHoldingRegisters(40000, 1)
+	return err == nil && len(b) == 2 && binary.BigEndian.Uint16(b) == e3dcMagic
+}
+
+// scanSunspec detects SunSpec devices and returns the common model description
+func scanSunspec(client gridx.Client) (string, bool) {
+	dev := sunspec.NewDevice("SUNS")
+
+	if err := dev.Initialize(client); err != nil && !errors.Is(err, meters.ErrPartiallyOpened) {
+		return "", false
+	}
+
+	desc := dev.Descriptor()
+	return strings.TrimSpace(desc.Manufacturer + " " + desc.Model), true
+}
+
+// scanRS485 probes all known rs485 meter models for a plausible phase voltage
+func scanRS485(client gridx.Client) (string, string, bool) {
+	types := make([]string, 0, len(rs485.Producers))
+	for typ := range rs485.Producers {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		dev, err := rs485.NewDevice(typ)
+		if err != nil {
+			continue
+		}
+
+		if mr, err := dev.Probe(client); err == nil && validVoltage(mr.Value) {
+			return strings.ToLower(typ), dev.Producer().Description(), true
+		}
+	}
+
+	return "", "", false
+}
+
+// ScanModbus probes the modbus ids for SunSpec, E3/DC and rs485 meters
+func ScanModbus(log *util.Logger, s modbus.Settings, ids []uint8, timeout time.Duration) ([]ScanResult, error) {
+	conn, err := scanConnection(s)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.Logger(log.TRACE)
+	if timeout > 0 {
+		conn.Timeout(timeout)
+	}
+
+	client := conn.ModbusClient()
+	tcp := s.URI != "" && modbus.ProtocolFromRTU(s.RTU) == modbus.Tcp
+
+	var res []ScanResult
+
+	for _, id := range ids {
+		// give the bus some time to recover before querying the next device
+		time.Sleep(40 * time.Millisecond)
+		conn.Slave(id)
+
+		log.DEBUG.Printf("scanning id %d", id)
+
+		if tcp {
+			if scanE3dc(client) {
+				res = append(res, ScanResult{ID: id, Model: "e3dc", Description: "E3/DC"})
+				continue
+			}
+
+			if desc, ok := scanSunspec(client); ok {
+				res = append(res, ScanResult{ID: id, Model: "sunspec", Description: desc})
+				continue
+			}
+		}
+
+		// rs485 meters, also via tcp gateways
+		if model, desc, ok := scanRS485(client); ok {
+			res = append(res, ScanResult{ID: id, Model: model, Description: desc})
+		}
+	}
+
+	return res, nil
+}
+
+// Config returns the evcc.yaml meter configuration for the scan result
+func (r ScanResult) Config(name string, s modbus.Settings) map[string]interface{} {
+	if r.Model == "e3dc" {
+		host, port, err := net.SplitHostPort(util.DefaultPort(s.URI, 502))
+		if err != nil {
+			host, port = s.URI, "502"
+		}
+
+		return map[string]interface{}{
+			"name":     name,
+			"type":     "template",
+			"template": "e3dc",
+			"usage":    "grid",
+			"host":     host,
+			"port":     port,
+		}
+	}
+
+	res := map[string]interface{}{
+		"name":  name,
+		"type":  "modbus",
+		"model": r.Model,
+		"id":    r.ID,
+		"power": "Power",
+	}
+
+	if s.URI != "" {
+		res["uri"] = util.DefaultPort(s.URI, 502)
+		if s.RTU != nil && *s.RTU {
+			res["rtu"] = true
+		}
+	} else {
+		res["device"] = s.Device
+		res["baudrate"] = s.Baudrate
+		res["comset"] = s.Comset
+	}
+
+	return res
+}
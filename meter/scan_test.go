@@ -0,0 +1,48 @@
+package meter
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	ids, err := ParseIDs("1-3, 126")
+	require.NoError(t, err)
+	assert.Equal(t, []uint8{1, 2, 3, 126}, ids)
+
+	for _, s := range []string{"", "0", "3-1", "1-248", "foo"} {
+		_, err := ParseIDs(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestScanConfig(t *testing.T) {
+	assert.True(t, validVoltage(231.5))
+	assert.False(t, validVoltage(0))
+
+	tcp := modbus.Settings{URI: "192.168.0.10"}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":  "meter1",
+		"type":  "modbus",
+		"model": "sdm",
+		"id":    uint8(2),
+		"power": "Power",
+		"uri":   "192.168.0.10:502",
+	}, ScanResult{ID: 2, Model: "sdm"}.Config("meter1", tcp))
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "meter1",
+		"type":     "template",
+		"template": "e3dc",
+		"usage":    "grid",
+		"host":     "192.168.0.10",
+		"port":     "502",
+	}, ScanResult{ID: 1, Model: "e3dc"}.Config("meter1", tcp))
+
+	rtu := modbus.Settings{Device: "/dev/ttyUSB0", Baudrate: 9600, Comset: "8N1"}
+	assert.Equal(t, "/dev/ttyUSB0", ScanResult{ID: 1, Model: "janitza"}.Config("meter1", rtu)["device"])
+}
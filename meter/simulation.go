@@ -0,0 +1,94 @@
+package meter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/simulation"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.Add("simulation", NewSimulationFromConfig)
+}
+
+// simulationMeter is a grid or pv meter of an in-memory simulation
+type simulationMeter struct {
+	powerG func() float64
+}
+
+// CurrentPower implements the api.Meter interface
+func (m *simulationMeter) CurrentPower() (float64, error) {
+	return m.powerG(), nil
+}
+
+// simulationBattery is a battery meter of an in-memory simulation
+type simulationBattery struct {
+	*simulationMeter
+	socG func() float64
+}
+
+var _ api.Battery = (*simulationBattery)(nil)
+
+// SoC implements the api.Battery interface
+func (m *simulationBattery) SoC() (float64, error) {
+	return m.socG(), nil
+}
+
+// NewSimulationFromConfig creates a meter of an in-memory simulation.
+// The grid meter's power is the home consumption, the pv meter's power the peak production and the battery meter's power the max charge power.
+func NewSimulationFromConfig(other map[string]interface{}) (api.Meter, error) {
+	cc := struct {
+		Simulation string // simulation name, devices of the same simulation share their energy flows
+		Usage      string // grid, pv or battery
+		Power      float64
+		Profile    string  // pv profile
+		Capacity   float64 // battery capacity in kWh
+		SoC        float64 // battery initial soc
+		MinSoC     float64 // battery min soc
+	}{
+		Capacity: 10,
+		SoC:      50,
+		MinSoC:   10,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	site := simulation.Instance(cc.Simulation)
+
+	switch strings.ToLower(cc.Usage) {
+	case "grid":
+		site.SetHome(cc.Power)
+		return &simulationMeter{site.GridPower}, nil
+
+	case "pv":
+		profile, err := simulation.ParseProfile(cc.Profile)
+		if err != nil {
+			return nil, err
+		}
+
+		site.SetPV(cc.Power, profile)
+		return &simulationMeter{site.PVPower}, nil
+
+	case "battery":
+		if cc.Power == 0 {
+			return nil, errors.New("missing power")
+		}
+
+		site.SetBattery(simulation.Battery{
+			Capacity: cc.Capacity,
+			SoC:      cc.SoC,
+			Power:    cc.Power,
+			MinSoC:   cc.MinSoC,
+		})
+
+		return &simulationBattery{&simulationMeter{site.BatteryPower}, site.BatterySoC}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid usage: %s", cc.Usage)
+	}
+}
@@ -70,7 +70,21 @@ func NewSMA(uri, password, iface string, serial uint32, scale float64) (api.Mete
 		}
 
 	default:
-		return nil, errors.New("missing uri or serial")
+		// zero-config energy meter or home manager
+		meters := discoverer.EnergyMeters()
+
+		switch len(meters) {
+		case 0:
+			return nil, errors.New("missing uri or serial, no energy meter discovered")
+		case 1:
+			sm.device = meters[0]
+		default:
+			serials := make([]uint32, 0, len(meters))
+			for _, m := range meters {
+				serials = append(serials, m.SerialNumber())
+			}
+			return nil, fmt.Errorf("missing serial, multiple energy meters discovered: %v", serials)
+		}
 	}
 
 	// call UpdateValues first to check if we get an error
@@ -19,13 +19,14 @@ var acceptable = []string{
 	"connect: connection refused",
 	"connect: network is unreachable",
 	"i/o timeout",
-	"'sma': missing uri or serial", // SMA
-	"'fritzdect': missing ain",     // FritzDect
+	"'sma': missing uri or serial",                      // SMA
+	"missing uri or serial, no energy meter discovered", // SMA
+	"'fritzdect': missing ain",                          // FritzDect
 	"[1ESY1161052714 1ESY1161229249 1EMH0008842285 1ESY1161978584 1EMH0004864048 1ESY1161979033 7ELS8135823805]", // Discovergy
-	"can only have either uri or device",                                          // modbus
-	"(Client.Timeout exceeded while awaiting headers)",                            // http
-	"unexpected status: 401",                                                      // Discovergy
-	"unexpected status: 503",                                                      // Discovergy
+	"can only have either uri or device",               // modbus
+	"(Client.Timeout exceeded while awaiting headers)", // http
+	"unexpected status: 401",                           // Discovergy
+	"unexpected status: 503",                           // Discovergy
 	"login failed: Put \"https://192.0.2.2/v1/login\": context deadline exceeded", // LG ESS
 }
 
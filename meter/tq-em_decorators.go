@@ -7,11 +7,16 @@ import (
 )
 
 func decorateTqEm(base api.Meter, meterCurrent func() (float64, float64, float64, error)) api.Meter {
-	switch {
-	case meterCurrent == nil:
+	var caps int
+	if meterCurrent != nil {
+		caps |= 1
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case meterCurrent != nil:
+	case 1: // api.MeterCurrent
 		return &struct {
 			api.Meter
 			api.MeterCurrent
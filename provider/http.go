@@ -1,17 +1,29 @@
 package provider
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"math"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/evcc-io/evcc/provider/pipeline"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/jq"
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/util/transport"
+	"github.com/itchyny/gojq"
 	"github.com/jpfielding/go-http-digest/pkg/digest"
 )
 
@@ -25,10 +37,37 @@ type HTTP struct {
 	cache       time.Duration
 	updated     time.Time
 	pipeline    *pipeline.Pipeline
+	signature   *signature
+	pagination  *pagination
 	val         []byte // Cached http response value
 	err         error  // Cached http response error
 }
 
+// Sign is the request signing config
+type Sign struct {
+	Algorithm, Key, Header, Encoding string
+}
+
+// Paginate is the pagination config
+type Paginate struct {
+	Next  string // jq query returning the next page uri, null or empty on last page
+	Limit int    // maximum number of pages
+}
+
+// signature signs requests using a hmac of the body, or the request uri for requests without body
+type signature struct {
+	hash     func() hash.Hash
+	key      []byte
+	header   string
+	encoding string
+}
+
+// pagination follows next page uris and combines all pages into a json array
+type pagination struct {
+	next  *gojq.Query
+	limit int
+}
+
 func init() {
 	registry.Add("http", NewHTTPProviderFromConfig)
 }
@@ -48,6 +87,8 @@ func NewHTTPProviderFromConfig(other map[string]interface{}) (IntProvider, error
 		Scale             float64
 		Insecure          bool
 		Auth              Auth
+		Sign              Sign
+		Paginate          Paginate
 		Timeout           time.Duration
 		Cache             time.Duration
 	}{
@@ -78,6 +119,14 @@ func NewHTTPProviderFromConfig(other map[string]interface{}) (IntProvider, error
 		_, err = http.WithAuth(cc.Auth.Type, cc.Auth.User, cc.Auth.Password)
 	}
 
+	if err == nil && cc.Sign.Key != "" {
+		_, err = http.WithSignature(cc.Sign.Algorithm, cc.Sign.Key, cc.Sign.Header, cc.Sign.Encoding)
+	}
+
+	if err == nil && cc.Paginate.Next != "" {
+		_, err = http.WithPagination(cc.Paginate.Next, cc.Paginate.Limit)
+	}
+
 	if err == nil {
 		var pipe *pipeline.Pipeline
 		pipe, err = pipeline.New(cc.Settings)
@@ -89,9 +138,12 @@ func NewHTTPProviderFromConfig(other map[string]interface{}) (IntProvider, error
 
 // NewHTTP create HTTP provider
 func NewHTTP(log *util.Logger, method, uri string, insecure bool, scale float64, cache time.Duration) *HTTP {
-	url := util.DefaultScheme(uri, "http")
-	if url != uri {
-		log.WARN.Printf("missing scheme for %s, assuming http", uri)
+	// keep uri templates unescaped if scheme is present
+	url := uri
+	if !strings.Contains(uri, "://") {
+		if url = util.DefaultScheme(uri, "http"); url != uri {
+			log.WARN.Printf("missing scheme for %s, assuming http", uri)
+		}
 	}
 
 	p := &HTTP{
@@ -145,21 +197,140 @@ func (p *HTTP) WithAuth(typ, user, password string) (*HTTP, error) {
 	return p, nil
 }
 
-// request executes the configured request or returns the cached value
-func (p *HTTP) request(body ...string) ([]byte, error) {
-	if time.Since(p.updated) >= p.cache {
-		var b io.Reader
-		if len(body) == 1 {
-			b = strings.NewReader(body[0])
+// WithSignature adds hmac request signing
+func (p *HTTP) WithSignature(algorithm, key, header, encoding string) (*HTTP, error) {
+	s := &signature{
+		key:      []byte(key),
+		header:   header,
+		encoding: strings.ToLower(encoding),
+	}
+
+	switch strings.ToLower(algorithm) {
+	case "", "sha256":
+		s.hash = sha256.New
+	case "sha1":
+		s.hash = sha1.New
+	case "sha512":
+		s.hash = sha512.New
+	default:
+		return nil, fmt.Errorf("unknown signing algorithm '%s'", algorithm)
+	}
+
+	switch s.encoding {
+	case "":
+		s.encoding = "hex"
+	case "hex", "base64":
+	default:
+		return nil, fmt.Errorf("unknown signature encoding '%s'", encoding)
+	}
+
+	if s.header == "" {
+		s.header = "X-Signature"
+	}
+
+	p.signature = s
+
+	return p, nil
+}
+
+// WithPagination adds following next page uris
+func (p *HTTP) WithPagination(next string, limit int) (*HTTP, error) {
+	op, err := gojq.Parse(next)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq query '%s': %w", next, err)
+	}
+
+	p.pagination = &pagination{
+		next:  op,
+		limit: limit,
+	}
+
+	return p, nil
+}
+
+// sign returns the encoded hmac of the payload
+func (s *signature) sign(payload string) string {
+	mac := hmac.New(s.hash, s.key)
+	_, _ = mac.Write([]byte(payload))
+
+	if s.encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// do executes a single request
+func (p *HTTP) do(uri string, headers map[string]string, body string) ([]byte, error) {
+	var b io.Reader
+	if body != "" {
+		b = strings.NewReader(body)
+	}
+
+	// empty method becomes GET
+	req, err := request.New(strings.ToUpper(p.method), uri, b, headers)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	if p.signature != nil {
+		payload := body
+		if payload == "" {
+			payload = req.URL.RequestURI()
 		}
 
-		// empty method becomes GET
-		req, err := request.New(strings.ToUpper(p.method), p.url, b, p.headers)
+		req.Header.Set(p.signature.header, p.signature.sign(payload))
+	}
+
+	return p.DoBody(req)
+}
+
+// paginate executes the request following next page uris and returns all pages as json array
+func (p *HTTP) paginate(uri string, headers map[string]string, body string) ([]byte, error) {
+	var pages []json.RawMessage
+
+	for uri != "" && (p.pagination.limit == 0 || len(pages) < p.pagination.limit) {
+		b, err := p.do(uri, headers, body)
 		if err != nil {
-			return []byte{}, err
+			return nil, err
+		}
+
+		if !json.Valid(b) {
+			return nil, errors.New("paginate: invalid json")
+		}
+
+		pages = append(pages, b)
+
+		next, err := jq.Query(p.pagination.next, b)
+		if err != nil || next == nil || next == "" {
+			break
+		}
+
+		u, err := url.Parse(uri)
+		if err == nil {
+			var ref *url.URL
+			if ref, err = url.Parse(fmt.Sprintf("%v", next)); err == nil {
+				uri = u.ResolveReference(ref).String()
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("paginate: %w", err)
+		}
+	}
+
+	return json.Marshal(pages)
+}
+
+// request executes the configured request or returns the cached value
+func (p *HTTP) request(uri string, headers map[string]string, body string) ([]byte, error) {
+	if time.Since(p.updated) >= p.cache {
+		if p.pagination != nil {
+			p.val, p.err = p.paginate(uri, headers, body)
+		} else {
+			p.val, p.err = p.do(uri, headers, body)
 		}
 
-		p.val, p.err = p.DoBody(req)
 		p.updated = time.Now()
 	}
 
@@ -198,7 +369,7 @@ func (p *HTTP) IntGetter() func() (int64, error) {
 // StringGetter sends string request
 func (p *HTTP) StringGetter() func() (string, error) {
 	return func() (string, error) {
-		b, err := p.request(p.body)
+		b, err := p.request(p.url, p.headers, p.body)
 
 		if err == nil && p.pipeline != nil {
 			b, err = p.pipeline.Process(b)
@@ -218,13 +389,30 @@ func (p *HTTP) BoolGetter() func() (bool, error) {
 	}
 }
 
+// set sends the value using uri, header and body templates
 func (p *HTTP) set(param string, val interface{}) error {
 	body, err := setFormattedValue(p.body, param, val)
+	if err != nil {
+		return err
+	}
 
-	if err == nil {
-		_, err = p.request(body)
+	uri, err := util.ReplaceFormatted(p.url, map[string]interface{}{param: val})
+	if err != nil {
+		return err
 	}
 
+	headers := make(map[string]string, len(p.headers))
+	for k, v := range p.headers {
+		if headers[k], err = util.ReplaceFormatted(v, map[string]interface{}{param: val}); err != nil {
+			return err
+		}
+	}
+
+	_, err = p.do(uri, headers, body)
+
+	// invalidate cached value
+	p.updated = time.Time{}
+
 	return err
 }
 
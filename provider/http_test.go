@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPPagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprint(w, `{"items":[1,2],"next":"?page=2"}`)
+		case "2":
+			fmt.Fprint(w, `{"items":[3],"next":null}`)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := NewHTTPProviderFromConfig(map[string]interface{}{
+		"uri": srv.URL,
+		"paginate": map[string]interface{}{
+			"next": ".next",
+		},
+		"jq": "[.[].items[]] | add",
+	})
+	require.NoError(t, err)
+
+	res, err := p.(*HTTP).IntGetter()()
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), res)
+}
+
+func TestHTTPSetter(t *testing.T) {
+	var uri, header, body, signature string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		uri, header, body, signature = r.URL.RequestURI(), r.Header.Get("X-Current"), string(b), r.Header.Get("X-Signature")
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(util.NewLogger("foo"), "POST", srv.URL+"/set/{{.current}}", false, 1, 0).
+		WithHeaders(map[string]string{"X-Current": "{{.current}}"}).
+		WithBody(`{"current":{{.current}}}`)
+
+	_, err := p.WithSignature("sha256", "secret", "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, p.IntSetter("current")(16))
+
+	assert.Equal(t, "/set/16", uri)
+	assert.Equal(t, "16", header)
+	assert.Equal(t, `{"current":16}`, body)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signature)
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+)
+
+// replayProvider replays a column of a recorded or scripted profile over time
+type replayProvider struct {
+	log    *util.Logger
+	clock  clock.Clock
+	start  time.Time
+	loop   bool
+	times  []time.Duration
+	values []string
+}
+
+// replay profiles sharing the same file share the same start time to keep columns in sync
+var (
+	replayMu    sync.Mutex
+	replayStart = make(map[string]time.Time)
+)
+
+func init() {
+	registry.Add("replay", NewReplayFromConfig)
+}
+
+// NewReplayFromConfig creates replay provider.
+// Profiles are CSV with a header row, the first column being the time offset from start (e.g. 0s, 90s, 5m).
+func NewReplayFromConfig(other map[string]interface{}) (IntProvider, error) {
+	cc := struct {
+		File   string
+		Data   string
+		Column string
+		Loop   *bool
+	}{}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if (cc.File == "") == (cc.Data == "") {
+		return nil, errors.New("need either file or data")
+	}
+
+	var r io.Reader = strings.NewReader(cc.Data)
+	key := cc.Data
+
+	if cc.File != "" {
+		f, err := os.Open(cc.File)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		r = f
+		key = cc.File
+	}
+
+	p := &replayProvider{
+		log:   util.NewLogger("replay"),
+		clock: clock.New(),
+		loop:  cc.Loop == nil || *cc.Loop,
+	}
+
+	if err := p.parse(r, cc.Column); err != nil {
+		return nil, err
+	}
+
+	replayMu.Lock()
+	if _, ok := replayStart[key]; !ok {
+		replayStart[key] = p.clock.Now()
+	}
+	p.start = replayStart[key]
+	replayMu.Unlock()
+
+	return p, nil
+}
+
+// parse reads the time offsets and the selected column from the profile
+func (p *replayProvider) parse(r io.Reader, column string) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if len(records) < 2 {
+		return errors.New("missing profile data")
+	}
+
+	idx := -1
+	for i, name := range records[0] {
+		if i > 0 && strings.EqualFold(name, column) {
+			idx = i
+		}
+	}
+
+	// default to first value column
+	if column == "" && len(records[0]) > 1 {
+		idx = 1
+	}
+
+	if idx < 0 {
+		return fmt.Errorf("invalid column: %s", column)
+	}
+
+	for i, record := range records[1:] {
+		if len(record) <= idx {
+			return fmt.Errorf("row %d: missing column %s", i+1, column)
+		}
+
+		ts, err := parseReplayTime(record[0])
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+
+		if len(p.times) > 0 && ts < p.times[len(p.times)-1] {
+			return fmt.Errorf("row %d: time not ascending", i+1)
+		}
+
+		p.times = append(p.times, ts)
+		p.values = append(p.values, record[idx])
+	}
+
+	return nil
+}
+
+// parseReplayTime parses durations or plain seconds
+func parseReplayTime(s string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(f * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// value returns the profile value at the current time
+func (p *replayProvider) value() string {
+	elapsed := p.clock.Since(p.start)
+
+	// repeat after the last row, which lasts as long as the step before it
+	if n := len(p.times); p.loop && n > 1 {
+		if period := 2*p.times[n-1] - p.times[n-2]; period > 0 {
+			elapsed %= period
+		}
+	}
+
+	res := p.values[0]
+	for i, ts := range p.times {
+		if ts > elapsed {
+			break
+		}
+		res = p.values[i]
+	}
+
+	return res
+}
+
+// FloatGetter returns the current profile value as float
+func (p *replayProvider) FloatGetter() func() (float64, error) {
+	return func() (float64, error) {
+		return strconv.ParseFloat(p.value(), 64)
+	}
+}
+
+// IntGetter returns the current profile value as int
+func (p *replayProvider) IntGetter() func() (int64, error) {
+	return func() (int64, error) {
+		f, err := strconv.ParseFloat(p.value(), 64)
+		return int64(f), err
+	}
+}
+
+// StringGetter returns the current profile value
+func (p *replayProvider) StringGetter() func() (string, error) {
+	return func() (string, error) {
+		return p.value(), nil
+	}
+}
+
+// BoolGetter returns the current profile value as bool
+func (p *replayProvider) BoolGetter() func() (bool, error) {
+	return func() (bool, error) {
+		return util.Truish(p.value()), nil
+	}
+}
+
+// IntSetter accepts and logs values since replayed profiles cannot be controlled
+func (p *replayProvider) IntSetter(param string) func(int64) error {
+	return func(val int64) error {
+		p.log.DEBUG.Printf("%s: %d", param, val)
+		return nil
+	}
+}
+
+// BoolSetter accepts and logs values since replayed profiles cannot be controlled
+func (p *replayProvider) BoolSetter(param string) func(bool) error {
+	return func(val bool) error {
+		p.log.DEBUG.Printf("%s: %v", param, val)
+		return nil
+	}
+}
+
+// StringSetter accepts and logs values since replayed profiles cannot be controlled
+func (p *replayProvider) StringSetter(param string) func(string) error {
+	return func(val string) error {
+		p.log.DEBUG.Printf("%s: %s", param, val)
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cloud passing while car plugs in
+const replayProfile = `time,power,status
+0s,5000,A
+1m,1000,B
+3m,5000,C
+`
+
+func TestReplay(t *testing.T) {
+	clck := clock.NewMock()
+
+	p := &replayProvider{clock: clck, start: clck.Now(), loop: true}
+	require.NoError(t, p.parse(strings.NewReader(replayProfile), "status"))
+
+	status := p.StringGetter()
+
+	tc := []struct {
+		dt  time.Duration
+		res string
+	}{
+		{0, "A"},
+		{30 * time.Second, "A"},
+		{30 * time.Second, "B"},
+		{2 * time.Minute, "C"},
+		{90 * time.Second, "C"}, // last row lasts for the previous step
+		{time.Minute, "A"},      // loop
+		{30 * time.Second, "B"},
+	}
+
+	for _, tc := range tc {
+		clck.Add(tc.dt)
+		res, err := status()
+		require.NoError(t, err)
+		assert.Equal(t, tc.res, res, clck.Now())
+	}
+
+	p = &replayProvider{clock: clck, start: clck.Now()}
+	require.NoError(t, p.parse(strings.NewReader(replayProfile), ""))
+
+	clck.Add(5 * time.Minute)
+	power, err := p.FloatGetter()()
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, power, "last value without loop")
+
+	assert.Error(t, p.parse(strings.NewReader(replayProfile), "foo"))
+}
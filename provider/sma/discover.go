@@ -3,6 +3,7 @@ package sma
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -131,6 +132,30 @@ func (d *Discoverer) DeviceBySerial(serial uint32, password string) *Device {
 	return d.get(serial, password)
 }
 
+// EnergyMeters returns the energy meters and home managers found by the discovery
+func (d *Discoverer) EnergyMeters() []*Device {
+	// wait for discovery to finish
+	for atomic.LoadUint32(&d.done) == 0 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+
+	var res []*Device
+	for _, device := range d.devices {
+		if device.IsEnergyMeter() {
+			res = append(res, device)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].SerialNumber() < res[j].SerialNumber()
+	})
+
+	return res
+}
+
 // DeviceByIP with the given serial number
 func (d *Discoverer) DeviceByIP(ip, password string) (*Device, error) {
 	d.mux.Lock()
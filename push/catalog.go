@@ -0,0 +1,61 @@
+package push
+
+import (
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/util/locale"
+)
+
+// Definition is a catalog event with its default message templates
+type Definition struct {
+	Event       event.Event
+	Description string
+	Title, Msg  string // default templates, localized by the push.<event>.title and push.<event>.msg message ids
+}
+
+// Catalog lists the events available for push messages
+var Catalog = []Definition{
+	{event.ChargeStarted{}, "charge started", "Charge started", `Started charging in "${mode}" mode`},
+	{event.ChargeStopped{}, "charge stopped", "Charge finished", "Finished charging ${chargedEnergy:%.1fk}kWh in ${chargeDuration}."},
+	{event.VehicleConnected{}, "vehicle connected", "Car connected", "Car connected at ${pvPower:%.1fk}kW PV"},
+	{event.VehicleDisconnected{}, "vehicle disconnected", "Car disconnected", "Car disconnected after ${connectedDuration}"},
+	{event.VehicleSoC{}, "vehicle soc progress", "SoC updated", "Battery charged to ${vehicleSoC:%.0f}%"},
+	{event.VehicleUnidentified{}, "guest session, vehicle could not be identified", "Unknown vehicle", "Unknown vehicle, guest connected?"},
+	{event.VehicleUnauthorized{}, "identifier not authorized", "Charging denied", "Unauthorized id ${vehicleIdentity}, charger disabled"},
+	{event.PlanActivated{}, "target charging activated", "Target charging", "Charging ${vehicleTitle} until ${targetTime}"},
+	{event.TargetReached{}, "target soc or energy reached", "Target reached", "${vehicleTitle} charged to ${vehicleSoC:%.0f}%"},
+	{event.BudgetExceeded{}, "monthly budget exceeded", "Budget exceeded", "Monthly budget used with ${budgetEnergy:%.0f}kWh, charging with pv only"},
+	{event.VehicleIdle{}, "vehicle finished charging", "Charging finished", "${vehicleTitle} finished charging, please move your car"},
+	{event.DeviceError{}, "charger or meter error", "Charger fault", "Charging paused: ${faultDescription}"},
+	{event.MeterValidation{}, "implausible meter measurements at startup", "Check meter configuration", `{{ join "; " .validation }}`},
+	{event.GridSignal{}, "grid operator load reduction changed", "Grid signal", `{{ if .event.Active }}Load reduction to {{ printf "%.0f" .event.Limit }}W{{ else }}Load reduction released{{ end }}`},
+}
+
+// definition returns the catalog definition of the event
+func definition(name string) (Definition, bool) {
+	for _, def := range Catalog {
+		if def.Event.Name() == name {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// localize translates the message id to the language, falling back to the default message
+func localize(lang, id, fallback string) string {
+	if localizer := locale.NewLocalizer(lang); localizer != nil {
+		if msg := locale.LocalizeWith(localizer, &locale.Config{MessageID: id}); msg != id {
+			return msg
+		}
+	}
+	return fallback
+}
+
+// catalogTemplate returns the localized default template of the catalog event
+func catalogTemplate(def Definition, lang string) (EventTemplate, error) {
+	name := def.Event.Name()
+
+	return newEventTemplate(EventTemplateConfig{
+		Title: localize(lang, "push."+name+".title", def.Title),
+		Msg:   localize(lang, "push."+name+".msg", def.Msg),
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/util"
 )
 
@@ -12,6 +13,35 @@ type Sender interface {
 	Send(title, msg string)
 }
 
+// LanguageSender is implemented by senders with recipients of different languages
+type LanguageSender interface {
+	Languages() []string // recipient languages, empty for default
+	SendLanguage(lang, title, msg string)
+}
+
+type languageSender struct {
+	Sender
+	lang string
+}
+
+// WithLanguage decorates the sender for recipients of the given language
+func WithLanguage(sender Sender, lang string) Sender {
+	return &languageSender{Sender: sender, lang: lang}
+}
+
+func (s *languageSender) Languages() []string {
+	return []string{s.lang}
+}
+
+func (s *languageSender) SendLanguage(_, title, msg string) {
+	s.Send(title, msg)
+}
+
+// Controller is implemented by messengers accepting commands for the loadpoints
+type Controller interface {
+	LoadpointControl([]loadpoint.API)
+}
+
 var log = util.NewLogger("push")
 
 // NewMessengerFromConfig creates a new messenger
@@ -32,6 +62,11 @@ func NewMessengerFromConfig(typ string, other map[string]interface{}) (res Sende
 		if err = util.DecodeOther(other, &cc); err == nil {
 			res, err = NewShoutrrrMessenger(cc.URI)
 		}
+	case "webhook":
+		var cc webhookConfig
+		if err = util.DecodeOther(other, &cc); err == nil {
+			res, err = NewWebhookMessenger(cc.URI, cc.Method, cc.Headers)
+		}
 	case "script":
 		var cc scriptConfig
 		if err = util.DecodeOther(other, &cc); err == nil {
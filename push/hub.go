@@ -1,23 +1,30 @@
 package push
 
 import (
+	"fmt"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
 )
 
 // Event is a notification event
 type Event struct {
 	LoadPoint *int // optional loadpoint id
 	Event     string
+	Payload   event.Event // typed event, available to templates as .event
 }
 
-// EventTemplateConfig is the push message configuration for an event
+// EventTemplateConfig is the push message configuration for an event.
+// Without title and message the localized catalog templates are used.
 type EventTemplateConfig struct {
-	Title, Msg string
+	Title, Msg   string
+	Translations map[string]EventTemplateConfig // templates by recipient language
+	Channels     []string                       // names of the services receiving the event, empty for all
 }
 
 // EventTemplate is the push message template for an event
@@ -27,47 +34,110 @@ type EventTemplate struct {
 
 // Hub subscribes to event notifications and sends them to client devices
 type Hub struct {
-	definitions map[string]EventTemplate
-	sender      []Sender
+	definitions map[string]map[string]EventTemplate // event templates by language, empty for default
+	catalog     map[string]Definition               // enabled events using the catalog templates
+	routes      map[string][]string                 // channels by event
+	sender      []channel
 	cache       *util.Cache
 }
 
+// channel is a named sender
+type channel struct {
+	name string
+	Sender
+}
+
+func newEventTemplate(v EventTemplateConfig) (EventTemplate, error) {
+	var def EventTemplate
+	var err error
+
+	def.Title, err = template.New("out").Funcs(template.FuncMap(sprig.FuncMap())).Parse(v.Title)
+	if err == nil {
+		def.Msg, err = template.New("out").Funcs(template.FuncMap(sprig.FuncMap())).Parse(v.Msg)
+	}
+
+	return def, err
+}
+
 // NewHub creates push hub with definitions and receiver
 func NewHub(cc map[string]EventTemplateConfig, cache *util.Cache) (*Hub, error) {
-	definitions := make(map[string]EventTemplate)
+	definitions := make(map[string]map[string]EventTemplate)
+	catalog := make(map[string]Definition)
+	routes := make(map[string][]string)
 
 	// instantiate all event templates
 	for k, v := range cc {
-		var def EventTemplate
-		var err error
+		if len(v.Channels) > 0 {
+			routes[k] = v.Channels
+		}
+
+		cd, known := definition(k)
+		if !known {
+			log.WARN.Printf("unknown event: %s", k)
+		}
+
+		if v.Title == "" && v.Msg == "" {
+			if !known {
+				return nil, fmt.Errorf("%s: missing title and message", k)
+			}
 
-		def.Title, err = template.New("out").Funcs(template.FuncMap(sprig.FuncMap())).Parse(v.Title)
-		if err == nil {
-			def.Msg, err = template.New("out").Funcs(template.FuncMap(sprig.FuncMap())).Parse(v.Msg)
+			catalog[k] = cd
+			continue
 		}
 
+		def, err := newEventTemplate(v)
 		if err != nil {
 			return nil, err
 		}
 
-		definitions[k] = def
+		definitions[k] = map[string]EventTemplate{"": def}
+
+		for lang, t := range v.Translations {
+			if definitions[k][strings.ToLower(lang)], err = newEventTemplate(t); err != nil {
+				return nil, fmt.Errorf("%s (%s): %w", k, lang, err)
+			}
+		}
 	}
 
 	h := &Hub{
 		definitions: definitions,
+		catalog:     catalog,
+		routes:      routes,
 		cache:       cache,
 	}
 
 	return h, nil
 }
 
-// Add adds a sender to the list of senders
+// WithCache returns a hub sharing definitions and senders that reads event attributes from the given cache
+func (h *Hub) WithCache(cache *util.Cache) *Hub {
+	return &Hub{
+		definitions: h.definitions,
+		catalog:     h.catalog,
+		routes:      h.routes,
+		sender:      h.sender,
+		cache:       cache,
+	}
+}
+
+// Add adds a sender to the list of senders. Unnamed senders only receive events without channel routing.
 func (h *Hub) Add(sender Sender) {
-	h.sender = append(h.sender, sender)
+	h.AddChannel("", sender)
 }
 
-// apply applies the event template to the content to produce the actual message
-func (h *Hub) apply(ev Event, tmpl *template.Template) (string, error) {
+// AddChannel adds a named sender to the list of senders
+func (h *Hub) AddChannel(name string, sender Sender) {
+	h.sender = append(h.sender, channel{name: name, Sender: sender})
+}
+
+// routed determines if the event is sent to the channel
+func (h *Hub) routed(ev string, ch channel) bool {
+	channels, ok := h.routes[ev]
+	return !ok || slices.Contains(channels, ch.name)
+}
+
+// attributes returns the cached values of site and event loadpoint
+func (h *Hub) attributes(ev Event) map[string]interface{} {
 	attr := make(map[string]interface{})
 
 	// let cache catch up, refs reverted https://github.com/evcc-io/evcc/pull/445
@@ -80,6 +150,31 @@ func (h *Hub) apply(ev Event, tmpl *template.Template) (string, error) {
 		}
 	}
 
+	if ev.Payload != nil {
+		attr["event"] = ev.Payload
+	}
+
+	return attr
+}
+
+// localizedTemplate returns the event template for the language, falling back to base language and default template
+func localizedTemplate(definitions map[string]EventTemplate, lang string) EventTemplate {
+	lang = strings.ToLower(lang)
+	if def, ok := definitions[lang]; ok {
+		return def
+	}
+
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if def, ok := definitions[base]; ok {
+			return def
+		}
+	}
+
+	return definitions[""]
+}
+
+// apply applies the event template to the content to produce the actual message
+func (h *Hub) apply(attr map[string]interface{}, tmpl *template.Template) (string, error) {
 	// apply data attributes to template using sprig functions
 	applied := new(strings.Builder)
 	if err := tmpl.Execute(applied, attr); err != nil {
@@ -96,28 +191,57 @@ func (h *Hub) Run(events <-chan Event) {
 			continue
 		}
 
-		definition, ok := h.definitions[ev.Event]
-		if !ok {
-			continue
-		}
-
-		title, err := h.apply(ev, definition.Title)
-		if err != nil {
-			log.ERROR.Printf("invalid title template for %s: %v", ev.Event, err)
+		definitions, configured := h.definitions[ev.Event]
+		cd, enabled := h.catalog[ev.Event]
+		if !configured && !enabled {
 			continue
 		}
 
-		msg, err := h.apply(ev, definition.Msg)
-		if err != nil {
-			log.ERROR.Printf("invalid message template for %s: %v", ev.Event, err)
-			continue
-		}
+		attr := h.attributes(ev)
 
 		for _, sender := range h.sender {
-			if strings.TrimSpace(msg) != "" {
-				go sender.Send(title, msg)
-			} else {
-				log.DEBUG.Printf("did not send empty message template for %s: %v", ev.Event, err)
+			if !h.routed(ev.Event, sender) {
+				continue
+			}
+
+			langs := []string{""}
+			ls, localized := sender.Sender.(LanguageSender)
+			if localized {
+				langs = ls.Languages()
+			}
+
+			for _, lang := range langs {
+				definition := localizedTemplate(definitions, lang)
+				if !configured {
+					var err error
+					if definition, err = catalogTemplate(cd, lang); err != nil {
+						log.ERROR.Printf("invalid catalog template for %s: %v", ev.Event, err)
+						continue
+					}
+				}
+
+				title, err := h.apply(attr, definition.Title)
+				if err != nil {
+					log.ERROR.Printf("invalid title template for %s: %v", ev.Event, err)
+					continue
+				}
+
+				msg, err := h.apply(attr, definition.Msg)
+				if err != nil {
+					log.ERROR.Printf("invalid message template for %s: %v", ev.Event, err)
+					continue
+				}
+
+				if strings.TrimSpace(msg) == "" {
+					log.DEBUG.Printf("did not send empty message template for %s", ev.Event)
+					continue
+				}
+
+				if localized {
+					go ls.SendLanguage(lang, title, msg)
+				} else {
+					go sender.Send(title, msg)
+				}
 			}
 		}
 	}
@@ -0,0 +1,106 @@
+package push
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	lang, title string
+}
+
+type testSender struct {
+	lang string
+	ch   chan message
+}
+
+func (s *testSender) Send(title, _ string) {
+	s.ch <- message{s.lang, title}
+}
+
+func TestHubTranslations(t *testing.T) {
+	hub, err := NewHub(map[string]EventTemplateConfig{
+		"start": {
+			Title: "Charge started", Msg: "msg",
+			Translations: map[string]EventTemplateConfig{
+				"de": {Title: "Laden gestartet", Msg: "msg"},
+			},
+		},
+	}, util.NewCache())
+	require.NoError(t, err)
+
+	ch := make(chan message, 3)
+	hub.Add(&testSender{"", ch})
+	hub.Add(WithLanguage(&testSender{"de-CH", ch}, "de-CH"))
+	hub.Add(WithLanguage(&testSender{"fr", ch}, "fr"))
+
+	events := make(chan Event, 1)
+	go hub.Run(events)
+	events <- Event{Event: "start"}
+
+	res := make(map[string]string)
+	for i := 0; i < 3; i++ {
+		select {
+		case m := <-ch:
+			res[m.lang] = m.title
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"":      "Charge started",
+		"de-CH": "Laden gestartet",
+		"fr":    "Charge started",
+	}, res)
+}
+
+func TestHubCatalogRouting(t *testing.T) {
+	hub, err := NewHub(map[string]EventTemplateConfig{
+		"gridsignal": {Channels: []string{"telegram"}},
+	}, util.NewCache())
+	require.NoError(t, err)
+
+	ch := make(chan message, 2)
+	hub.AddChannel("telegram", &testSender{"telegram", ch})
+	hub.AddChannel("pushover", &testSender{"pushover", ch})
+
+	events := make(chan Event, 1)
+	go hub.Run(events)
+	events <- Event{Event: "gridsignal", Payload: event.GridSignal{Active: true, Limit: 4200}}
+
+	select {
+	case m := <-ch:
+		assert.Equal(t, message{"telegram", "Grid signal"}, m)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	select {
+	case m := <-ch:
+		t.Fatalf("unexpected message: %v", m)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestCatalogTemplate(t *testing.T) {
+	def, ok := definition("gridsignal")
+	require.True(t, ok)
+
+	tmpl, err := catalogTemplate(def, "")
+	require.NoError(t, err)
+
+	msg, err := new(Hub).apply(map[string]interface{}{
+		"event": event.GridSignal{Active: true, Limit: 4200},
+	}, tmpl.Msg)
+	require.NoError(t, err)
+	assert.Equal(t, "Load reduction to 4200W", msg)
+
+	_, err = NewHub(map[string]EventTemplateConfig{"unknown": {}}, util.NewCache())
+	assert.Error(t, err)
+}
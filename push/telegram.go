@@ -4,16 +4,20 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/evcc-io/evcc/core/loadpoint"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // Telegram implements the Telegram messenger
 type Telegram struct {
 	sync.Mutex
-	bot   *tgbotapi.BotAPI
-	chats map[int64]struct{}
+	bot        *tgbotapi.BotAPI
+	chats      map[int64]struct{}
+	loadpoints []loadpoint.API
 }
 
+var _ Controller = (*Telegram)(nil)
+
 type telegramConfig struct {
 	Token string
 	Chats []int64
@@ -46,18 +50,44 @@ func NewTelegramMessenger(token string, chats []int64) (*Telegram, error) {
 	return m, nil
 }
 
-// trackChats captures ids of all chats that bot participates in
+// LoadpointControl implements the Controller interface
+func (m *Telegram) LoadpointControl(loadpoints []loadpoint.API) {
+	m.Lock()
+	defer m.Unlock()
+	m.loadpoints = loadpoints
+}
+
+// trackChats captures ids of all chats that bot participates in and answers commands of configured chats
 func (m *Telegram) trackChats() {
 	conf := tgbotapi.NewUpdate(0)
 	conf.Timeout = 1000
 
 	for update := range m.bot.GetUpdatesChan(conf) {
+		if update.Message == nil {
+			continue
+		}
+
 		m.Lock()
-		if _, ok := m.chats[update.Message.Chat.ID]; !ok {
+		_, ok := m.chats[update.Message.Chat.ID]
+		loadpoints := m.loadpoints
+		m.Unlock()
+
+		if !ok {
 			log.INFO.Printf("telegram: new chat id: %d", update.Message.Chat.ID)
-			// m.chats[update.Message.Chat.ID] = struct{}{}
+			continue
+		}
+
+		if !update.Message.IsCommand() || len(loadpoints) == 0 {
+			continue
+		}
+
+		reply := telegramCommand(loadpoints, update.Message.Command(), update.Message.CommandArguments())
+
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, reply)
+		msg.ReplyToMessageID = update.Message.MessageID
+		if _, err := m.bot.Send(msg); err != nil {
+			log.ERROR.Print(err)
 		}
-		m.Unlock()
 	}
 }
 
@@ -0,0 +1,61 @@
+package push
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+const telegramHelp = `/status [loadpoint] - show charge mode, status and soc
+/soc [loadpoint] - show vehicle soc
+/mode [loadpoint] off|now|minpv|pv - set charge mode
+/stop [loadpoint] - stop charging`
+
+// telegramCommand executes a bot command and returns the reply.
+// Loadpoints are addressed by 1-based id and default to the first loadpoint.
+func telegramCommand(loadpoints []loadpoint.API, cmd, args string) string {
+	fields := strings.Fields(args)
+
+	lp := loadpoints[0]
+	if len(fields) > 0 {
+		if id, err := strconv.Atoi(fields[0]); err == nil {
+			if id < 1 || id > len(loadpoints) {
+				return fmt.Sprintf("invalid loadpoint: %d", id)
+			}
+
+			lp = loadpoints[id-1]
+			fields = fields[1:]
+		}
+	}
+
+	switch strings.ToLower(cmd) {
+	case "status":
+		return fmt.Sprintf("%s: mode %s, status %s, soc %.0f%%", lp.Name(), lp.GetMode(), lp.GetStatus(), lp.GetVehicleSoC())
+
+	case "soc":
+		return fmt.Sprintf("%s: soc %.0f%%", lp.Name(), lp.GetVehicleSoC())
+
+	case "mode":
+		if len(fields) != 1 {
+			return fmt.Sprintf("%s: mode %s", lp.Name(), lp.GetMode())
+		}
+
+		mode, err := api.ChargeModeString(fields[0])
+		if err != nil || mode == api.ModeEmpty {
+			return fmt.Sprintf("invalid mode: %s", fields[0])
+		}
+
+		lp.SetMode(mode)
+		return fmt.Sprintf("%s: mode %s", lp.Name(), lp.GetMode())
+
+	case "stop":
+		lp.SetMode(api.ModeOff)
+		return fmt.Sprintf("%s: charging stopped", lp.Name())
+
+	default:
+		return telegramHelp
+	}
+}
@@ -0,0 +1,48 @@
+package push
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+type commandLoadpoint struct {
+	loadpoint.API
+	mode api.ChargeMode
+}
+
+func (lp *commandLoadpoint) Name() string {
+	return "garage"
+}
+
+func (lp *commandLoadpoint) GetMode() api.ChargeMode {
+	return lp.mode
+}
+
+func (lp *commandLoadpoint) SetMode(mode api.ChargeMode) {
+	lp.mode = mode
+}
+
+func (lp *commandLoadpoint) GetVehicleSoC() float64 {
+	return 42
+}
+
+func TestTelegramCommand(t *testing.T) {
+	lp := &commandLoadpoint{mode: api.ModePV}
+	lps := []loadpoint.API{lp}
+
+	assert.Equal(t, "garage: soc 42%", telegramCommand(lps, "soc", ""))
+	assert.Equal(t, "garage: mode now", telegramCommand(lps, "mode", "1 now"))
+	assert.Equal(t, api.ModeNow, lp.mode)
+
+	assert.Equal(t, "invalid mode: foo", telegramCommand(lps, "mode", "foo"))
+	assert.Equal(t, "invalid loadpoint: 2", telegramCommand(lps, "stop", "2"))
+	assert.Equal(t, api.ModeNow, lp.mode)
+
+	assert.Equal(t, "garage: charging stopped", telegramCommand(lps, "stop", ""))
+	assert.Equal(t, api.ModeOff, lp.mode)
+
+	assert.Equal(t, telegramHelp, telegramCommand(lps, "help", ""))
+}
@@ -0,0 +1,71 @@
+package push
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// Webhook implements the webhook messenger sending title and message as json
+type Webhook struct {
+	*request.Helper
+	uri     string
+	method  string
+	headers map[string]string
+}
+
+type webhookConfig struct {
+	URI     string
+	Method  string
+	Headers map[string]string
+}
+
+// NewWebhookMessenger creates new webhook messenger
+func NewWebhookMessenger(uri, method string, headers map[string]string) (*Webhook, error) {
+	if uri == "" {
+		return nil, errors.New("webhook: missing uri")
+	}
+
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	if _, ok := headers["Content-Type"]; !ok {
+		headers["Content-Type"] = request.JSONContent
+	}
+
+	m := &Webhook{
+		Helper:  request.NewHelper(util.NewLogger("webhook")),
+		uri:     uri,
+		method:  strings.ToUpper(method),
+		headers: headers,
+	}
+
+	return m, nil
+}
+
+// Send sends the message
+func (m *Webhook) Send(title, msg string) {
+	data := struct {
+		Title string `json:"title"`
+		Msg   string `json:"msg"`
+	}{
+		Title: title,
+		Msg:   msg,
+	}
+
+	req, err := request.New(m.method, m.uri, request.MarshalJSON(data), m.headers)
+	if err == nil {
+		_, err = m.DoBody(req)
+	}
+
+	if err != nil {
+		log.ERROR.Printf("webhook: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evcc-io/evcc/util/locale"
+)
+
+// Role is the access level granted to an authenticated client
+type Role string
+
+const (
+	RoleRead    Role = "read"    // read state only
+	RoleControl Role = "control" // read state and change settings
+
+	// RoleSmartHome is granted to linked smart home accounts, limited to the smart home api
+	RoleSmartHome Role = "smarthome"
+)
+
+var (
+	ErrUnauthorized = locale.NewError("errors.unauthorized", "unauthorized")
+	ErrForbidden    = locale.NewError("errors.forbidden", "forbidden")
+)
+
+// AuthConfig is the api token and basic auth user configuration
+type AuthConfig struct {
+	Tokens []TokenConfig
+	Users  []UserConfig
+}
+
+// TokenConfig is an api token granting the given role
+type TokenConfig struct {
+	Token string
+	Role  Role
+}
+
+// UserConfig is a basic auth user granting the given role
+type UserConfig struct {
+	User, Password string
+	Role           Role
+	Language       string // api messages, overrides Accept-Language header
+}
+
+// Auth authenticates api and websocket requests
+type Auth struct {
+	tokens  []TokenConfig
+	users   []UserConfig
+	sources []func(string) (Role, bool)
+}
+
+// roleKey is the request context key of the authenticated role
+type roleKey struct{}
+
+// requestRole returns the authenticated role of the request, control if authentication is disabled
+func requestRole(r *http.Request) Role {
+	if role, ok := r.Context().Value(roleKey{}).(Role); ok {
+		return role
+	}
+	return RoleControl
+}
+
+func validateRole(role Role) (Role, error) {
+	switch role {
+	case "":
+		return RoleRead, nil
+	case RoleRead, RoleControl:
+		return role, nil
+	default:
+		return "", fmt.Errorf("invalid role: %s", role)
+	}
+}
+
+// NewAuth creates request authentication from configuration. Without configured tokens or users, all requests are allowed.
+func NewAuth(conf AuthConfig) (*Auth, error) {
+	a := new(Auth)
+
+	for i, t := range conf.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("token %d: missing token", i+1)
+		}
+
+		var err error
+		if t.Role, err = validateRole(t.Role); err != nil {
+			return nil, fmt.Errorf("token %d: %w", i+1, err)
+		}
+
+		a.tokens = append(a.tokens, t)
+	}
+
+	for i, u := range conf.Users {
+		if u.User == "" || u.Password == "" {
+			return nil, fmt.Errorf("user %d: missing user or password", i+1)
+		}
+
+		var err error
+		if u.Role, err = validateRole(u.Role); err != nil {
+			return nil, fmt.Errorf("user %s: %w", u.User, err)
+		}
+
+		a.users = append(a.users, u)
+	}
+
+	return a, nil
+}
+
+// Enabled returns true if authentication is configured
+func (a *Auth) Enabled() bool {
+	return a != nil && (len(a.tokens) > 0 || len(a.users) > 0)
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// AddTokenSource adds a hook validating tokens issued outside the configuration, e.g. by account linking
+func (a *Auth) AddTokenSource(fn func(token string) (Role, bool)) {
+	a.sources = append(a.sources, fn)
+}
+
+// tokenRole returns the role granted by the api token
+func (a *Auth) tokenRole(token string) (Role, bool) {
+	for _, t := range a.tokens {
+		if equal(t.Token, token) {
+			return t.Role, true
+		}
+	}
+
+	for _, fn := range a.sources {
+		if role, ok := fn(token); ok {
+			return role, true
+		}
+	}
+
+	return "", false
+}
+
+// user returns the basic auth user of the request
+func (a *Auth) user(r *http.Request) (UserConfig, bool) {
+	if user, password, ok := r.BasicAuth(); ok {
+		for _, u := range a.users {
+			if equal(u.User, user) && equal(u.Password, password) {
+				return u, true
+			}
+		}
+	}
+
+	return UserConfig{}, false
+}
+
+// role returns the role of the request's credentials
+func (a *Auth) role(r *http.Request) (Role, bool) {
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	if token != "" {
+		return a.tokenRole(token)
+	}
+
+	if u, ok := a.user(r); ok {
+		return u.Role, true
+	}
+
+	return "", false
+}
+
+// protected returns true if the request requires authentication
+func protected(r *http.Request) bool {
+	// oauth clients authenticate with their client secret, the public dashboard and cluster members with their token
+	if r.Method == http.MethodOptions || r.URL.Path == smartHomeTokenPath || strings.HasPrefix(r.URL.Path, dashboardPath+"/") ||
+		r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, clusterPath+"/") {
+		return false
+	}
+
+	return r.URL.Path == "/ws" || strings.HasPrefix(r.URL.Path, "/ws/") ||
+		r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/")
+}
+
+// allowed returns true if the role grants the request
+func (role Role) allowed(r *http.Request) bool {
+	if role == RoleSmartHome {
+		return strings.HasPrefix(r.URL.Path, smartHomePath+"/")
+	}
+	if r.URL.Path == configPath {
+		return role == RoleControl
+	}
+	return role == RoleControl || r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// Handler is the middleware enforcing authentication for api and websocket requests
+func (a *Auth) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() || !protected(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		role, ok := a.role(r)
+		if !ok {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			if len(a.users) > 0 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="evcc"`)
+			}
+			jsonError(w, r, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		if !role.allowed(r) {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			jsonError(w, r, http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		if u, ok := a.user(r); ok && u.Language != "" {
+			r = r.WithContext(locale.WithLanguage(r.Context(), u.Language))
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), roleKey{}, role))
+
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth(t *testing.T) {
+	auth, err := NewAuth(AuthConfig{
+		Tokens: []TokenConfig{{Token: "reader"}, {Token: "controller", Role: RoleControl}},
+		Users:  []UserConfig{{User: "admin", Password: "secret", Role: RoleControl}},
+	})
+	require.NoError(t, err)
+
+	h := auth.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tc := []struct {
+		method, path   string
+		token          string
+		user, password string
+		status         int
+	}{
+		{http.MethodGet, "/", "", "", "", http.StatusNoContent},
+		{http.MethodGet, "/assets/index.js", "", "", "", http.StatusNoContent},
+		{http.MethodGet, "/api/state", "", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/ws", "", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/ws/sites/1", "", "", "", http.StatusUnauthorized},
+		{http.MethodOptions, "/api/loadpoints/0/mode/pv", "", "", "", http.StatusNoContent},
+		{http.MethodGet, "/api/state", "reader", "", "", http.StatusNoContent},
+		{http.MethodGet, "/ws?token=reader", "", "", "", http.StatusNoContent},
+		{http.MethodGet, "/ws/sites/1?token=reader", "", "", "", http.StatusNoContent},
+		{http.MethodPost, "/api/loadpoints/0/mode/pv", "reader", "", "", http.StatusForbidden},
+		{http.MethodGet, "/api/config", "reader", "", "", http.StatusForbidden},
+		{http.MethodGet, "/api/config", "controller", "", "", http.StatusNoContent},
+		{http.MethodPost, "/api/loadpoints/0/mode/pv", "controller", "", "", http.StatusNoContent},
+		{http.MethodPost, "/api/loadpoints/0/mode/pv", "foo", "", "", http.StatusUnauthorized},
+		{http.MethodPost, "/api/loadpoints/0/mode/pv", "", "admin", "secret", http.StatusNoContent},
+		{http.MethodPost, "/api/loadpoints/0/mode/pv", "", "admin", "foo", http.StatusUnauthorized},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		if tc.user != "" {
+			req.SetBasicAuth(tc.user, tc.password)
+		}
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc.method+" "+tc.path)
+	}
+}
+
+func TestAuthConfig(t *testing.T) {
+	auth, err := NewAuth(AuthConfig{})
+	require.NoError(t, err)
+	assert.False(t, auth.Enabled())
+
+	for _, conf := range []AuthConfig{
+		{Tokens: []TokenConfig{{Role: RoleRead}}},
+		{Tokens: []TokenConfig{{Token: "foo", Role: "admin"}}},
+		{Users: []UserConfig{{User: "foo"}}},
+	} {
+		_, err := NewAuth(conf)
+		assert.Error(t, err)
+	}
+}
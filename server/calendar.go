@@ -0,0 +1,109 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+)
+
+// icsTime is the iCalendar UTC date-time format
+const icsTime = "20060102T150405Z"
+
+// calendar builds iCalendar (RFC 5545) documents
+type calendar struct {
+	now   time.Time
+	lines []string
+}
+
+func (c *calendar) add(format string, args ...any) {
+	c.lines = append(c.lines, fmt.Sprintf(format, args...))
+}
+
+// event adds an event with escaped text values
+func (c *calendar) event(uid string, start, end time.Time, summary, description string) {
+	escape := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+	c.add("BEGIN:VEVENT")
+	c.add("UID:%s@evcc", uid)
+	c.add("DTSTAMP:%s", c.now.UTC().Format(icsTime))
+	c.add("DTSTART:%s", start.UTC().Format(icsTime))
+	c.add("DTEND:%s", end.UTC().Format(icsTime))
+	c.add("SUMMARY:%s", escape.Replace(summary))
+	if description != "" {
+		c.add("DESCRIPTION:%s", escape.Replace(description))
+	}
+	c.add("END:VEVENT")
+}
+
+// String returns the document with CRLF line endings
+func (c *calendar) String() string {
+	return strings.Join(c.lines, "\r\n") + "\r\n"
+}
+
+// mergeSlots joins consecutive charging slots
+func mergeSlots(slots api.Rates) api.Rates {
+	var res api.Rates
+
+	for _, slot := range slots {
+		if n := len(res); n > 0 && !res[n-1].End.Before(slot.Start) {
+			if slot.End.After(res[n-1].End) {
+				res[n-1].End = slot.End
+			}
+			continue
+		}
+
+		res = append(res, api.Rate{Start: slot.Start, End: slot.End})
+	}
+
+	return res
+}
+
+// planCalendar returns the planned charging slots, target times and expected completion of the loadpoints
+func planCalendar(lps []loadpoint.API, now time.Time) string {
+	c := &calendar{now: now}
+
+	c.add("BEGIN:VCALENDAR")
+	c.add("VERSION:2.0")
+	c.add("PRODID:-//evcc//charging plan//EN")
+	c.add("X-WR-CALNAME:evcc")
+
+	for id, lp := range lps {
+		uid := fmt.Sprintf("lp%d", id+1)
+
+		if target, soc := lp.GetTargetCharge(); !target.IsZero() && target.After(now) {
+			for _, slot := range mergeSlots(lp.GetPlan().Slots) {
+				c.event(fmt.Sprintf("%s-slot-%d", uid, slot.Start.Unix()), slot.Start, slot.End,
+					fmt.Sprintf("%s: planned charging", lp.Name()), "")
+			}
+
+			c.event(fmt.Sprintf("%s-target-%d", uid, target.Unix()), target, target,
+				fmt.Sprintf("%s: target %d%%", lp.Name(), soc), "")
+		}
+
+		if lp.GetStatus() == api.StatusC {
+			if remaining := lp.GetRemainingDuration(); remaining > 0 {
+				finish := now.Add(remaining).Truncate(time.Minute)
+				c.event(uid+"-finish", finish, finish,
+					fmt.Sprintf("%s: charging complete", lp.Name()),
+					fmt.Sprintf("expected completion, %.1f kWh remaining", lp.GetRemainingEnergy()/1e3))
+			}
+		}
+	}
+
+	c.add("END:VCALENDAR")
+
+	return c.String()
+}
+
+// calendarHandler publishes the charging plans as iCalendar feed
+func calendarHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar; charset=UTF-8")
+		fmt.Fprint(w, planCalendar(site.LoadPoints(), time.Now()))
+	}
+}
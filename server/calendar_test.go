@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/stretchr/testify/assert"
+)
+
+type calendarLoadpoint struct {
+	loadpoint.API
+	target time.Time
+	slots  api.Rates
+}
+
+func (lp *calendarLoadpoint) Name() string                      { return "Garage" }
+func (lp *calendarLoadpoint) GetTargetCharge() (time.Time, int) { return lp.target, 80 }
+func (lp *calendarLoadpoint) GetPlan() planner.Plan             { return planner.Plan{Slots: lp.slots} }
+func (lp *calendarLoadpoint) GetStatus() api.ChargeStatus       { return api.StatusC }
+func (lp *calendarLoadpoint) GetRemainingDuration() time.Duration {
+	return 90 * time.Minute
+}
+func (lp *calendarLoadpoint) GetRemainingEnergy() float64 { return 12000 }
+
+func TestPlanCalendar(t *testing.T) {
+	now := time.Date(2023, 1, 1, 20, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return now.Add(time.Duration(h) * time.Hour) }
+
+	lp := &calendarLoadpoint{
+		target: hour(10),
+		slots: api.Rates{
+			{Start: hour(2), End: hour(3)},
+			{Start: hour(3), End: hour(4)},
+			{Start: hour(6), End: hour(7)},
+		},
+	}
+
+	res := planCalendar([]loadpoint.API{lp}, now)
+
+	assert.Contains(t, res, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n")
+	assert.Contains(t, res, "UID:lp1-slot-1672610400@evcc\r\nDTSTAMP:20230101T200000Z\r\nDTSTART:20230101T220000Z\r\nDTEND:20230102T000000Z\r\nSUMMARY:Garage: planned charging\r\n", "consecutive slots merged")
+	assert.Contains(t, res, "DTSTART:20230102T020000Z\r\nDTEND:20230102T030000Z\r\n")
+	assert.Contains(t, res, "SUMMARY:Garage: target 80%\r\n")
+	assert.Contains(t, res, "DTSTART:20230101T213000Z\r\nDTEND:20230101T213000Z\r\nSUMMARY:Garage: charging complete\r\nDESCRIPTION:expected completion\\, 12.0 kWh remaining\r\n")
+	assert.True(t, len(res) > 2 && res[len(res)-2:] == "\r\n")
+
+	// no target
+	lp.target = time.Time{}
+	assert.NotContains(t, planCalendar([]loadpoint.API{lp}, now), "planned charging")
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/gorilla/mux"
+)
+
+// clusterPath is the api path of the leader, followers authenticate with the cluster token
+const clusterPath = "/api/cluster"
+
+// clusterSource is the remote budget source of cluster allocations
+const clusterSource = "cluster"
+
+// ClusterConfig is the load sharing configuration of multiple instances
+type ClusterConfig struct {
+	Role     string        // leader or follower
+	Token    string        // shared secret of leader and followers
+	Budget   float64       // leader: charge power shared by all loadpoints of the cluster in W
+	Leader   string        // follower: leader uri
+	Name     string        // follower: member name, defaults to hostname
+	Interval time.Duration // allocation and reporting interval
+	Timeout  time.Duration // leader: member expiry, follower: apply failsafe budget without leader
+	Failsafe float64       // follower: budget per loadpoint without leader in W, default no charging
+}
+
+// clusterDemand is the consumption and requested power range of a loadpoint
+type clusterDemand struct {
+	Power    float64 `json:"power"`
+	MinPower float64 `json:"minPower"` // 0 if not requesting power
+	MaxPower float64 `json:"maxPower"`
+}
+
+// clusterReport is sent by the followers
+type clusterReport struct {
+	Loadpoints []clusterDemand `json:"loadpoints"`
+}
+
+// clusterResponse is the leader's allocation per follower loadpoint
+type clusterResponse struct {
+	Budgets []float64 `json:"budgets"`
+}
+
+// clusterMember is a follower known to the leader
+type clusterMember struct {
+	Name       string          `json:"name"`
+	Loadpoints []clusterDemand `json:"loadpoints"`
+	Budgets    []float64       `json:"budgets"`
+	Seen       time.Time       `json:"seen"`
+}
+
+// loadpointDemand returns the loadpoint's demand, disconnected or disabled loadpoints do not request power
+func loadpointDemand(lp loadpoint.API) clusterDemand {
+	res := clusterDemand{Power: lp.GetChargePower()}
+
+	if lp.GetStatus() != api.StatusA && lp.GetMode() != api.ModeOff {
+		res.MinPower = lp.GetMinPower()
+		res.MaxPower = lp.GetMaxPower()
+	}
+
+	return res
+}
+
+// allocate shares the budget. In order, each demand receives its min power while the budget suffices,
+// the remaining budget is shared equally up to the max power.
+func allocate(budget float64, demands []clusterDemand) []float64 {
+	res := make([]float64, len(demands))
+
+	var active []int
+	for i, d := range demands {
+		if d.MaxPower <= 0 || d.MinPower > budget {
+			continue
+		}
+
+		res[i] = d.MinPower
+		budget -= d.MinPower
+		active = append(active, i)
+	}
+
+	for len(active) > 0 && budget > 1e-3 {
+		share := budget / float64(len(active))
+
+		var next []int
+		for _, i := range active {
+			add := math.Min(share, demands[i].MaxPower-res[i])
+			res[i] += add
+			budget -= add
+
+			if res[i] < demands[i].MaxPower {
+				next = append(next, i)
+			}
+		}
+
+		active = next
+	}
+
+	return res
+}
+
+// ClusterLeader allocates the charge power budget to its own and the followers' loadpoints
+type ClusterLeader struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	site    site.API
+	token   string
+	budget  float64
+	timeout time.Duration
+	members map[string]*clusterMember
+}
+
+// NewClusterLeader creates the cluster leader
+func NewClusterLeader(conf ClusterConfig, site site.API) (*ClusterLeader, error) {
+	if conf.Token == "" || conf.Budget <= 0 {
+		return nil, errors.New("missing token or budget")
+	}
+
+	timeout := conf.Timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+
+	return &ClusterLeader{
+		log:     util.NewLogger("cluster").Redact(conf.Token),
+		site:    site,
+		token:   conf.Token,
+		budget:  conf.Budget,
+		timeout: timeout,
+		members: make(map[string]*clusterMember),
+	}, nil
+}
+
+// Run allocates the budget in the given interval
+func (c *ClusterLeader) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		c.allocate()
+	}
+}
+
+// allocate distributes the budget, local loadpoints first, followed by the members ordered by name
+func (c *ClusterLeader) allocate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	loadpoints := c.site.LoadPoints()
+
+	demands := make([]clusterDemand, 0, len(loadpoints))
+	for _, lp := range loadpoints {
+		demands = append(demands, loadpointDemand(lp))
+	}
+
+	names := make([]string, 0, len(c.members))
+	for name, m := range c.members {
+		if time.Since(m.Seen) > c.timeout {
+			c.log.WARN.Printf("member %s: timeout, releasing budget", name)
+			delete(c.members, name)
+			continue
+		}
+
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		demands = append(demands, c.members[name].Loadpoints...)
+	}
+
+	budgets := allocate(c.budget, demands)
+
+	for i, lp := range loadpoints {
+		lp.SetRemoteBudget(clusterSource, budgets[i])
+	}
+
+	budgets = budgets[len(loadpoints):]
+	for _, name := range names {
+		m := c.members[name]
+		m.Budgets, budgets = budgets[:len(m.Loadpoints)], budgets[len(m.Loadpoints):]
+		c.log.DEBUG.Printf("member %s: budgets %.0fW", name, m.Budgets)
+	}
+}
+
+// report stores the follower's demand and returns its current allocation
+func (c *ClusterLeader) report(name string, rep clusterReport) clusterResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.members[name]
+	if !ok {
+		c.log.INFO.Printf("member %s: joined", name)
+		m = &clusterMember{Name: name}
+		c.members[name] = m
+	}
+
+	m.Loadpoints = rep.Loadpoints
+	m.Seen = time.Now()
+
+	// loadpoints changed, wait for next allocation
+	if len(m.Budgets) != len(m.Loadpoints) {
+		m.Budgets = make([]float64, len(m.Loadpoints))
+	}
+
+	return clusterResponse{Budgets: m.Budgets}
+}
+
+// Members returns the members ordered by name
+func (c *ClusterLeader) Members() []clusterMember {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make([]clusterMember, 0, len(c.members))
+	for _, m := range c.members {
+		res = append(res, *m)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
+
+// clusterStatusHandler returns the members of the cluster
+func clusterStatusHandler(c *ClusterLeader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, c.Members())
+	}
+}
+
+// clusterReportHandler receives the follower's report and returns its allocation
+func clusterReportHandler(c *ClusterLeader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+			jsonError(w, r, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		var rep clusterReport
+		if err := json.NewDecoder(r.Body).Decode(&rep); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, c.report(mux.Vars(r)["name"], rep))
+	}
+}
+
+// ClusterFollower reports its loadpoints to the leader and applies the allocated budgets
+type ClusterFollower struct {
+	*request.Helper
+	log      *util.Logger
+	site     site.API
+	uri      string
+	token    string
+	timeout  time.Duration
+	failsafe float64
+	updated  time.Time
+	lost     bool
+}
+
+// NewClusterFollower creates a cluster follower
+func NewClusterFollower(conf ClusterConfig, site site.API) (*ClusterFollower, error) {
+	if conf.Leader == "" || conf.Token == "" {
+		return nil, errors.New("missing leader or token")
+	}
+
+	if conf.Failsafe < 0 {
+		return nil, fmt.Errorf("invalid failsafe budget: %.0fW", conf.Failsafe)
+	}
+
+	name := conf.Name
+	if name == "" {
+		var err error
+		if name, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := conf.Timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+
+	log := util.NewLogger("cluster").Redact(conf.Token)
+
+	return &ClusterFollower{
+		Helper:   request.NewHelper(log),
+		log:      log,
+		site:     site,
+		uri:      fmt.Sprintf("%s%s/%s", strings.TrimSuffix(conf.Leader, "/"), clusterPath, name),
+		token:    conf.Token,
+		timeout:  timeout,
+		failsafe: conf.Failsafe,
+	}, nil
+}
+
+// Run reports to the leader in the given interval. The loadpoints are limited to the failsafe budget until the first allocation.
+func (c *ClusterFollower) Run(interval time.Duration) {
+	c.applyFailsafe()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		if err := c.update(); err != nil {
+			c.log.ERROR.Println(err)
+			c.watchdog()
+		}
+	}
+}
+
+// update sends the report and applies the budgets
+func (c *ClusterFollower) update() error {
+	loadpoints := c.site.LoadPoints()
+
+	var rep clusterReport
+	for _, lp := range loadpoints {
+		rep.Loadpoints = append(rep.Loadpoints, loadpointDemand(lp))
+	}
+
+	req, err := request.New(http.MethodPost, c.uri, request.MarshalJSON(rep), map[string]string{
+		"Authorization": "Bearer " + c.token,
+		"Content-Type":  request.JSONContent,
+		"Accept":        request.JSONContent,
+	})
+	if err != nil {
+		return err
+	}
+
+	var res struct {
+		Result clusterResponse
+	}
+	if err := c.DoJSON(req, &res); err != nil {
+		return err
+	}
+
+	if len(res.Result.Budgets) != len(loadpoints) {
+		return errors.New("invalid allocation")
+	}
+
+	for i, lp := range loadpoints {
+		lp.SetRemoteBudget(clusterSource, res.Result.Budgets[i])
+	}
+
+	c.updated = time.Now()
+	c.lost = false
+
+	return nil
+}
+
+// watchdog applies the failsafe budget while the leader has been unavailable for the timeout.
+// The loadpoints are never released to unlimited power since the leader protects the shared grid connection.
+// Refreshing the budget in each interval keeps it from expiring by the loadpoint's remote timeout.
+func (c *ClusterFollower) watchdog() {
+	if time.Since(c.updated) < c.timeout {
+		return
+	}
+
+	if !c.lost {
+		c.log.WARN.Printf("no leader for %v, applying failsafe budget %.0fW", c.timeout, c.failsafe)
+		c.lost = true
+	}
+
+	c.applyFailsafe()
+}
+
+// applyFailsafe limits all loadpoints to the failsafe budget
+func (c *ClusterFollower) applyFailsafe() {
+	for _, lp := range c.site.LoadPoints() {
+		lp.SetRemoteBudget(clusterSource, c.failsafe)
+	}
+}
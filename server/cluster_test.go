@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterAllocate(t *testing.T) {
+	tc := []struct {
+		budget  float64
+		demands []clusterDemand
+		res     []float64
+	}{
+		// idle loadpoints
+		{11000, []clusterDemand{{}, {}}, []float64{0, 0}},
+		// shared equally
+		{11000, []clusterDemand{{MinPower: 1400, MaxPower: 11000}, {MinPower: 1400, MaxPower: 11000}}, []float64{5500, 5500}},
+		// remainder of saturated loadpoint
+		{11000, []clusterDemand{{MinPower: 1400, MaxPower: 3700}, {MinPower: 1400, MaxPower: 11000}}, []float64{3700, 7300}},
+		// min power in order
+		{2000, []clusterDemand{{MinPower: 1400, MaxPower: 11000}, {MinPower: 1400, MaxPower: 11000}}, []float64{2000, 0}},
+		// more than sufficient
+		{22000, []clusterDemand{{MinPower: 1400, MaxPower: 3700}, {}, {MinPower: 4100, MaxPower: 11000}}, []float64{3700, 0, 11000}},
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc)
+		assert.Equal(t, tc.res, allocate(tc.budget, tc.demands))
+	}
+}
+
+func TestClusterFollowerFailsafe(t *testing.T) {
+	lp := newFakeLoadpoint()
+	site := &fakeSite{loadpoints: []loadpoint.API{lp}}
+
+	c, err := NewClusterFollower(ClusterConfig{Leader: "http://leader", Token: "secret", Name: "garage", Timeout: time.Minute, Failsafe: 1400}, site)
+	require.NoError(t, err)
+
+	// limited before first allocation
+	lp.budget = -1
+	c.applyFailsafe()
+	assert.Equal(t, 1400.0, lp.budget)
+
+	// allocation within timeout is kept
+	lp.budget = 7000
+	c.updated = time.Now()
+	c.watchdog()
+	assert.Equal(t, 7000.0, lp.budget)
+
+	// never unlimited after leader is lost
+	c.updated = time.Now().Add(-2 * time.Minute)
+	c.watchdog()
+	assert.Equal(t, 1400.0, lp.budget)
+
+	// failsafe refreshed while leader is lost
+	lp.budget = -1
+	c.watchdog()
+	assert.Equal(t, 1400.0, lp.budget)
+
+	_, err = NewClusterFollower(ClusterConfig{Leader: "http://leader", Token: "secret", Name: "garage", Failsafe: -1}, site)
+	assert.Error(t, err)
+}
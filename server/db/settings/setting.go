@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -20,11 +21,15 @@ type setting struct {
 }
 
 var (
+	mu       sync.RWMutex
 	settings []setting
 	dirty    int32
 )
 
 func Init() error {
+	mu.Lock()
+	defer mu.Unlock()
+
 	err := db.Instance.AutoMigrate(new(setting))
 	if err == nil {
 		err = db.Instance.Find(&settings).Error
@@ -33,6 +38,9 @@ func Init() error {
 }
 
 func Persist() error {
+	mu.RLock()
+	defer mu.RUnlock()
+
 	dirty := atomic.CompareAndSwapInt32(&dirty, 1, 0)
 	if !dirty || len(settings) == 0 {
 		// avoid "empty slice found"
@@ -42,6 +50,9 @@ func Persist() error {
 }
 
 func SetString(key string, val string) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	idx := slices.IndexFunc(settings, func(s setting) bool {
 		return s.Key == key
 	})
@@ -80,6 +91,9 @@ func SetBool(key string, val bool) {
 }
 
 func String(key string) (string, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
 	idx := slices.IndexFunc(settings, func(s setting) bool {
 		return s.Key == key
 	})
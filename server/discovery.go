@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/charger/keba"
+	"github.com/evcc-io/evcc/util"
+	"github.com/koron/go-ssdp"
+	"github.com/libp2p/zeroconf/v2"
+)
+
+// DiscoveryConfig is the lan device discovery configuration
+type DiscoveryConfig struct {
+	Disable  bool
+	Interval time.Duration
+}
+
+// DiscoveredDevice is a known device type found on the lan
+type DiscoveredDevice struct {
+	Type   string    `json:"type"` // go-e, easee, keba, shelly or sma
+	Name   string    `json:"name"`
+	Host   string    `json:"host"`
+	Port   int       `json:"port,omitempty"`
+	Source string    `json:"source"` // mdns, ssdp or udp
+	Seen   time.Time `json:"seen"`
+}
+
+// mdnsServices are the browsed mDNS service types
+var mdnsServices = []string{"_http._tcp", "_shelly._tcp"}
+
+// mdnsPrefixes maps lower case mDNS instance name prefixes to device types
+var mdnsPrefixes = map[string]string{
+	"go-echarger": "go-e",
+	"easee":       "easee",
+	"shelly":      "shelly",
+}
+
+// discoveryTimeout is the duration each scan waits for responses
+const discoveryTimeout = 5 * time.Second
+
+// Discovery finds known devices on the lan for the setup assistant
+type Discovery struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	devices map[string]DiscoveredDevice
+}
+
+// NewDiscovery creates the lan device discovery
+func NewDiscovery() *Discovery {
+	return &Discovery{
+		log:     util.NewLogger("discovery"),
+		devices: make(map[string]DiscoveredDevice),
+	}
+}
+
+// Run scans the lan in the given interval
+func (d *Discovery) Run(interval time.Duration) {
+	if interval == 0 {
+		interval = 10 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	for ; true; <-ticker.C {
+		d.scan()
+	}
+}
+
+// Devices returns the discovered devices ordered by type and host
+func (d *Discovery) Devices() []DiscoveredDevice {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	res := make([]DiscoveredDevice, 0, len(d.devices))
+	for _, dev := range d.devices {
+		res = append(res, dev)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Type != res[j].Type {
+			return res[i].Type < res[j].Type
+		}
+		return res[i].Host < res[j].Host
+	})
+
+	return res
+}
+
+func (d *Discovery) add(dev DiscoveredDevice) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := dev.Type + "/" + dev.Host
+	if _, ok := d.devices[key]; !ok {
+		d.log.DEBUG.Printf("found %s %s at %s (%s)", dev.Type, dev.Name, dev.Host, dev.Source)
+	}
+
+	dev.Seen = time.Now()
+	d.devices[key] = dev
+}
+
+func (d *Discovery) scan() {
+	var wg sync.WaitGroup
+
+	for _, scan := range []func() error{d.scanMDNS, d.scanSSDP, d.scanKeba} {
+		wg.Add(1)
+		go func(scan func() error) {
+			defer wg.Done()
+			if err := scan(); err != nil {
+				d.log.DEBUG.Println(err)
+			}
+		}(scan)
+	}
+
+	wg.Wait()
+}
+
+// mdnsType returns the device type of the mDNS instance name
+func mdnsType(service, instance string) string {
+	if service == "_shelly._tcp" {
+		return "shelly"
+	}
+
+	instance = strings.ToLower(instance)
+	for prefix, typ := range mdnsPrefixes {
+		if strings.HasPrefix(instance, prefix) {
+			return typ
+		}
+	}
+
+	return ""
+}
+
+func (d *Discovery) scanMDNS() error {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errC := make(chan error, len(mdnsServices))
+
+	for _, service := range mdnsServices {
+		entries := make(chan *zeroconf.ServiceEntry)
+
+		wg.Add(1)
+		go func(service string) {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case entry, ok := <-entries:
+					if !ok {
+						return
+					}
+
+					typ := mdnsType(service, entry.Instance)
+					if typ == "" || len(entry.AddrIPv4) == 0 {
+						continue
+					}
+
+					d.add(DiscoveredDevice{
+						Type:   typ,
+						Name:   entry.Instance,
+						Host:   entry.AddrIPv4[0].String(),
+						Port:   entry.Port,
+						Source: "mdns",
+					})
+				}
+			}
+		}(service)
+
+		go func(service string) {
+			if err := zeroconf.Browse(ctx, service, "local.", entries); err != nil {
+				errC <- fmt.Errorf("mdns %s: %w", service, err)
+			}
+		}(service)
+	}
+
+	wg.Wait()
+
+	select {
+	case err := <-errC:
+		return err
+	default:
+		return nil
+	}
+}
+
+// ssdpType returns the device type of the ssdp response
+func ssdpType(srv ssdp.Service) string {
+	if strings.Contains(strings.ToUpper(srv.Server+srv.USN), "SMA") {
+		return "sma"
+	}
+	return ""
+}
+
+func (d *Discovery) scanSSDP() error {
+	list, err := ssdp.Search(ssdp.All, int(discoveryTimeout.Seconds()), "")
+	if err != nil {
+		return fmt.Errorf("ssdp: %w", err)
+	}
+
+	for _, srv := range list {
+		typ := ssdpType(srv)
+		if typ == "" {
+			continue
+		}
+
+		u, err := url.Parse(srv.Location)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+
+		d.add(DiscoveredDevice{
+			Type:   typ,
+			Name:   srv.Server,
+			Host:   u.Hostname(),
+			Source: "ssdp",
+		})
+	}
+
+	return nil
+}
+
+// scanKeba broadcasts the KEBA info command, chargers reply to the same port
+func (d *Discovery) scanKeba() error {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: keba.Port})
+	if err != nil {
+		return fmt.Errorf("keba: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteToUDP([]byte("i"), &net.UDPAddr{IP: net.IPv4bcast, Port: keba.Port}); err != nil {
+		return fmt.Errorf("keba: %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(discoveryTimeout))
+
+	b := make([]byte, 1024)
+	for {
+		n, addr, err := conn.ReadFromUDP(b)
+		if err != nil {
+			// deadline reached
+			return nil
+		}
+
+		if msg := string(b[:n]); strings.Contains(msg, "Firmware") {
+			d.add(DiscoveredDevice{
+				Type:   "keba",
+				Name:   strings.TrimSpace(msg),
+				Host:   addr.IP.String(),
+				Port:   keba.Port,
+				Source: "udp",
+			})
+		}
+	}
+}
+
+// discoveryHandler returns the discovered devices
+func discoveryHandler(d *Discovery) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, d.Devices())
+	}
+}
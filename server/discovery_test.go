@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/koron/go-ssdp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoveryTypes(t *testing.T) {
+	assert.Equal(t, "go-e", mdnsType("_http._tcp", "go-eCharger-012345"))
+	assert.Equal(t, "easee", mdnsType("_http._tcp", "Easee-EH123456"))
+	assert.Equal(t, "shelly", mdnsType("_shelly._tcp", "plug-s-abcdef"))
+	assert.Equal(t, "", mdnsType("_http._tcp", "printer"))
+
+	assert.Equal(t, "sma", ssdpType(ssdp.Service{Server: "SMA Sunny WebBox"}))
+	assert.Equal(t, "", ssdpType(ssdp.Service{Server: "Linux UPnP/1.0"}))
+}
+
+func TestDiscoveryDevices(t *testing.T) {
+	d := NewDiscovery()
+	d.add(DiscoveredDevice{Type: "shelly", Host: "192.0.2.2"})
+	d.add(DiscoveredDevice{Type: "keba", Host: "192.0.2.3"})
+	d.add(DiscoveredDevice{Type: "keba", Host: "192.0.2.3"})
+
+	res := d.Devices()
+	assert.Len(t, res, 2)
+	assert.Equal(t, "keba", res[0].Type)
+	assert.False(t, res[0].Seen.IsZero())
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/machine"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const (
+	// FleetSignatureHeader carries the base64 encoded ed25519 signature of the response, see fleetSigned
+	FleetSignatureHeader = "X-Evcc-Signature"
+	// FleetTimestampHeader carries the signing time of the response in unix seconds
+	FleetTimestampHeader = "X-Evcc-Timestamp"
+	// FleetNonceHeader carries the random nonce of the status request the response must be signed for
+	FleetNonceHeader = "X-Evcc-Nonce"
+)
+
+// fleetSignatureMaxAge is the max age of signed responses
+const fleetSignatureMaxAge = 5 * time.Minute
+
+// FleetConfig is the remote management configuration
+type FleetConfig struct {
+	URI       string        // central management endpoint
+	ID        string        // instance id, defaults to protected machine id
+	Token     string        // shared secret for authentication of status reports
+	PublicKey string        // base64 encoded ed25519 public key of the endpoint verifying command signatures
+	Interval  time.Duration // status reporting interval
+}
+
+// Fleet reports the instance status to a central management endpoint and applies the returned commands.
+// Polling allows managing instances behind NAT without inbound connections.
+type Fleet struct {
+	*request.Helper
+	log     *util.Logger
+	site    site.API
+	cache   *util.Cache
+	uri     string
+	id      string
+	token   string
+	key     ed25519.PublicKey
+	results []fleetResult
+}
+
+// fleetStatus is the status report sent to the management endpoint
+type fleetStatus struct {
+	ID      string                 `json:"id"`
+	Version string                 `json:"version"`
+	State   map[string]interface{} `json:"state"`
+	Results []fleetResult          `json:"results,omitempty"`
+}
+
+// fleetCommand is a setting update requested by the management endpoint
+type fleetCommand struct {
+	ID        string `json:"id"`
+	Loadpoint int    `json:"loadpoint"` // 1-based loadpoint, 0 for site
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+// fleetResult is the outcome of a command, reported with the next status
+type fleetResult struct {
+	ID    string      `json:"id"`
+	Value interface{} `json:"value,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+type fleetResponse struct {
+	Commands []fleetCommand `json:"commands"`
+}
+
+// NewFleet creates a fleet management agent
+func NewFleet(conf FleetConfig, site site.API, cache *util.Cache) (*Fleet, error) {
+	if conf.URI == "" || conf.Token == "" || conf.PublicKey == "" {
+		return nil, errors.New("missing uri, token or public key")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(conf.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key")
+	}
+
+	id := conf.ID
+	if id == "" {
+		var err error
+		if id, err = machine.ProtectedID("evcc-fleet"); err != nil {
+			return nil, err
+		}
+	}
+
+	log := util.NewLogger("fleet").Redact(conf.Token)
+
+	return &Fleet{
+		Helper: request.NewHelper(log),
+		log:    log,
+		site:   site,
+		cache:  cache,
+		uri:    strings.TrimSuffix(conf.URI, "/"),
+		id:     id,
+		token:  conf.Token,
+		key:    key,
+	}, nil
+}
+
+// Run reports status and executes commands in the given interval
+func (f *Fleet) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		if err := f.update(); err != nil {
+			f.log.ERROR.Println(err)
+		}
+	}
+}
+
+// fleetSigned returns the signed message of the response, binding the body to the instance and status request
+func fleetSigned(id, nonce, timestamp string, body []byte) []byte {
+	return append([]byte(id+"\n"+nonce+"\n"+timestamp+"\n"), body...)
+}
+
+// verify checks the response signature for the nonce of the status request and rejects stale responses
+func (f *Fleet) verify(resp *http.Response, nonce string, body []byte) error {
+	timestamp := resp.Header.Get(FleetTimestampHeader)
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing response timestamp")
+	}
+
+	if age := time.Since(time.Unix(ts, 0)); age > fleetSignatureMaxAge || age < -fleetSignatureMaxAge {
+		return fmt.Errorf("stale response: %v", age.Round(time.Second))
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(resp.Header.Get(FleetSignatureHeader))
+	if err != nil || !ed25519.Verify(f.key, fleetSigned(f.id, nonce, timestamp, body), sig) {
+		return errors.New("invalid response signature")
+	}
+
+	return nil
+}
+
+// update sends the status and applies the commands of the signed response
+func (f *Fleet) update() error {
+	state := f.cache.State()
+	for _, k := range ignoreState {
+		delete(state, k)
+	}
+
+	status := fleetStatus{
+		ID:      f.id,
+		Version: FormattedVersion(),
+		State:   state,
+		Results: f.results,
+	}
+
+	// responses are only accepted once since the nonce changes with each request
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(b)
+
+	uri := fmt.Sprintf("%s/instances/%s/status", f.uri, f.id)
+	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(status), map[string]string{
+		"Authorization":  "Bearer " + f.token,
+		"Content-Type":   request.JSONContent,
+		"Accept":         request.JSONContent,
+		FleetNonceHeader: nonce,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.Do(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := request.ReadBody(resp)
+	if err != nil {
+		return err
+	}
+
+	// results have been delivered
+	f.results = nil
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if err := f.verify(resp, nonce, body); err != nil {
+		return fmt.Errorf("%w, ignoring commands", err)
+	}
+
+	var res fleetResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return err
+	}
+
+	for _, cmd := range res.Commands {
+		f.results = append(f.results, f.apply(cmd))
+	}
+
+	return nil
+}
+
+// apply executes a single command
+func (f *Fleet) apply(cmd fleetCommand) fleetResult {
+	res := fleetResult{ID: cmd.ID}
+
+	val, err := applySetting(f.site, "fleet", cmd.Loadpoint, cmd.Key, cmd.Value)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	f.log.INFO.Printf("command %s: set %s=%s for loadpoint %d", cmd.ID, cmd.Key, cmd.Value, cmd.Loadpoint)
+	res.Value = val
+
+	return res
+}
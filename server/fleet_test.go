@@ -0,0 +1,98 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFleetCommands(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	var (
+		signed    = true
+		nonce     string // replaces the request nonce if set
+		timestamp = time.Now
+		statuses  []fleetStatus
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/instances/foo/status", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var status fleetStatus
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+		statuses = append(statuses, status)
+
+		body := []byte(`{"commands":[{"id":"1","key":"bufferSoC","value":"80"},{"id":"2","loadpoint":1,"key":"mode","value":"pv"}]}`)
+		if signed {
+			ts := strconv.FormatInt(timestamp().Unix(), 10)
+
+			n := r.Header.Get(FleetNonceHeader)
+			if nonce != "" {
+				n = nonce
+			}
+
+			w.Header().Set(FleetTimestampHeader, ts)
+			w.Header().Set(FleetSignatureHeader, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, fleetSigned("foo", n, ts, body))))
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	site := &grpcSite{}
+	cache := util.NewCache()
+
+	_, err = NewFleet(FleetConfig{URI: srv.URL, ID: "foo", Token: "secret"}, site, cache)
+	assert.Error(t, err, "missing public key")
+
+	fleet, err := NewFleet(FleetConfig{URI: srv.URL + "/", ID: "foo", Token: "secret", PublicKey: base64.StdEncoding.EncodeToString(pub)}, site, cache)
+	require.NoError(t, err)
+
+	require.NoError(t, fleet.update())
+	assert.Equal(t, 80.0, site.bufferSoC)
+	assert.Empty(t, statuses[0].Results)
+
+	// results are reported with next status
+	signed = false
+	site.bufferSoC = 0
+
+	assert.Error(t, fleet.update())
+	assert.Equal(t, 0.0, site.bufferSoC, "unsigned commands must not be applied")
+
+	require.Len(t, statuses[1].Results, 2)
+	assert.Equal(t, fleetResult{ID: "1", Value: 80.0}, statuses[1].Results[0])
+	assert.Equal(t, fleetResult{ID: "2", Error: "invalid loadpoint: 1"}, statuses[1].Results[1])
+
+	// replayed response signed for a previous request
+	signed = true
+	nonce = "replayed"
+
+	assert.Error(t, fleet.update())
+	assert.Equal(t, 0.0, site.bufferSoC, "replayed commands must not be applied")
+
+	// stale response
+	nonce = ""
+	timestamp = func() time.Time { return time.Now().Add(-time.Hour) }
+
+	assert.Error(t, fleet.update())
+	assert.Equal(t, 0.0, site.bufferSoC, "stale commands must not be applied")
+
+	// the token does not allow forging commands
+	timestamp = time.Now
+	_, priv, err = ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	assert.Error(t, fleet.update())
+	assert.Equal(t, 0.0, site.bufferSoC, "commands signed with a foreign key must not be applied")
+}
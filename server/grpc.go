@@ -0,0 +1,355 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/proto/pb"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcReadMethods are the methods granted to the read role, all other methods require the control role
+var grpcReadMethods = map[string]bool{
+	"/evcc.v1.Evcc/State": true,
+}
+
+// grpcModes maps the charge modes to the api enum
+var grpcModes = map[api.ChargeMode]pb.ChargeMode{
+	api.ModeOff:   pb.ChargeMode_CHARGE_MODE_OFF,
+	api.ModeNow:   pb.ChargeMode_CHARGE_MODE_NOW,
+	api.ModeMinPV: pb.ChargeMode_CHARGE_MODE_MINPV,
+	api.ModePV:    pb.ChargeMode_CHARGE_MODE_PV,
+}
+
+// GRPC is the gRPC server exposing site and loadpoint state and setters
+type GRPC struct {
+	pb.UnimplementedEvccServer
+	site    site.API
+	cache   *util.Cache
+	auth    *Auth
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+// NewGRPC creates the gRPC server. Requests are authenticated by api token if auth is enabled.
+func NewGRPC(site site.API, cache *util.Cache, auth *Auth) *GRPC {
+	return &GRPC{
+		site:    site,
+		cache:   cache,
+		auth:    auth,
+		clients: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Server returns the grpc server with the Evcc service registered behind the authorization interceptors
+func (s *GRPC) Server() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	)
+	pb.RegisterEvccServer(srv, s)
+
+	return srv
+}
+
+// ListenAndServe serves the gRPC api on the given address
+func (s *GRPC) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Server().Serve(l)
+}
+
+// Run notifies the state streams of updated values. Notifications are coalesced, slow streams skip intermediate states.
+func (s *GRPC) Run(in <-chan util.Param) {
+	for range in {
+		s.mu.Lock()
+		for c := range s.clients {
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// subscribe registers a state stream
+func (s *GRPC) subscribe() chan struct{} {
+	c := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	return c
+}
+
+// unsubscribe removes the state stream
+func (s *GRPC) unsubscribe(c chan struct{}) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+}
+
+// authorize checks the token of the request metadata grants the method
+func (s *GRPC) authorize(ctx context.Context, method string) error {
+	if !s.auth.Enabled() {
+		return nil
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if auth := md.Get("authorization"); len(auth) > 0 {
+			token = strings.TrimPrefix(auth[0], "Bearer ")
+		}
+	}
+
+	role, ok := s.auth.tokenRole(token)
+	if !ok || role != RoleRead && role != RoleControl {
+		return status.Error(codes.Unauthenticated, ErrUnauthorized.Error())
+	}
+
+	if !grpcReadMethods[method] && role != RoleControl {
+		return status.Error(codes.PermissionDenied, ErrForbidden.Error())
+	}
+
+	return nil
+}
+
+func (s *GRPC) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *GRPC) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// stateReply converts the cached state into the typed state reply, loadpoints are numbered from 1
+func stateReply(cache *util.Cache) (*pb.StateReply, error) {
+	st, err := state.Decode(cache.State())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &pb.StateReply{
+		Site: &pb.SiteState{
+			Title:          st.SiteTitle,
+			GridPower:      st.GridPower,
+			PvPower:        st.PvPower,
+			HomePower:      st.HomePower,
+			BatteryPower:   st.BatteryPower,
+			BatterySoc:     st.BatterySoC,
+			BufferSoc:      st.BufferSoC,
+			BufferStartSoc: st.BufferStartSoC,
+			PrioritySoc:    st.PrioritySoC,
+			ResidualPower:  st.ResidualPower,
+		},
+	}
+
+	for i, lp := range st.Loadpoints {
+		res.Loadpoints = append(res.Loadpoints, &pb.LoadpointState{
+			Loadpoint:     int32(i + 1),
+			Title:         lp.Title,
+			Mode:          grpcModes[lp.Mode],
+			Enabled:       lp.Enabled,
+			Connected:     lp.Connected,
+			Charging:      lp.Charging,
+			ChargePower:   lp.ChargePower,
+			ChargedEnergy: lp.ChargedEnergy,
+			PhasesActive:  int32(lp.PhasesActive),
+			MinCurrent:    lp.MinCurrent,
+			MaxCurrent:    lp.MaxCurrent,
+			MinSoc:        int32(lp.MinSoC),
+			TargetSoc:     int32(lp.TargetSoC),
+			TargetEnergy:  lp.TargetEnergy,
+			VehicleTitle:  lp.VehicleTitle,
+			VehicleSoc:    lp.VehicleSoC,
+		})
+	}
+
+	return res, nil
+}
+
+// State implements the pb.EvccServer interface. It sends the current state followed by the state after updates.
+func (s *GRPC) State(req *pb.StateRequest, stream pb.Evcc_StateServer) error {
+	send := func() error {
+		res, err := stateReply(s.cache)
+		if err == nil {
+			err = stream.Send(res)
+		}
+		return err
+	}
+
+	// subscribe before the snapshot to not miss updates
+	c := s.subscribe()
+	defer s.unsubscribe(c)
+
+	if err := send(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-c:
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// apply executes the setter and returns the applied value
+func apply(setters map[string]setter, key, value string) (interface{}, error) {
+	fn, ok := setters[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "invalid key: %s", key)
+	}
+
+	val, err := fn(value)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return val, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// SetSite implements the pb.EvccServer interface
+func (s *GRPC) SetSite(ctx context.Context, req *pb.SetSiteRequest) (*pb.SetSiteReply, error) {
+	var (
+		key     string
+		value   float64
+		setting func(float64) *pb.SiteSetting
+	)
+
+	switch v := req.GetSetting().GetSetting().(type) {
+	case *pb.SiteSetting_BufferSoc:
+		key, value = "bufferSoC", v.BufferSoc
+		setting = func(f float64) *pb.SiteSetting {
+			return &pb.SiteSetting{Setting: &pb.SiteSetting_BufferSoc{BufferSoc: f}}
+		}
+	case *pb.SiteSetting_BufferStartSoc:
+		key, value = "bufferStartSoC", v.BufferStartSoc
+		setting = func(f float64) *pb.SiteSetting {
+			return &pb.SiteSetting{Setting: &pb.SiteSetting_BufferStartSoc{BufferStartSoc: f}}
+		}
+	case *pb.SiteSetting_PrioritySoc:
+		key, value = "prioritySoC", v.PrioritySoc
+		setting = func(f float64) *pb.SiteSetting {
+			return &pb.SiteSetting{Setting: &pb.SiteSetting_PrioritySoc{PrioritySoc: f}}
+		}
+	case *pb.SiteSetting_ResidualPower:
+		key, value = "residualPower", v.ResidualPower
+		setting = func(f float64) *pb.SiteSetting {
+			return &pb.SiteSetting{Setting: &pb.SiteSetting_ResidualPower{ResidualPower: f}}
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "missing setting")
+	}
+
+	val, err := apply(siteSetters(s.site), key, formatFloat(value))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SetSiteReply{Applied: setting(val.(float64))}, nil
+}
+
+// SetLoadpoint implements the pb.EvccServer interface
+func (s *GRPC) SetLoadpoint(ctx context.Context, req *pb.SetLoadpointRequest) (*pb.SetLoadpointReply, error) {
+	lps := s.site.LoadPoints()
+	if req.Loadpoint < 1 || int(req.Loadpoint) > len(lps) {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("invalid loadpoint: %d", req.Loadpoint))
+	}
+
+	setters := loadpointSetters("grpc", s.site, lps[req.Loadpoint-1])
+	res := new(pb.LoadpointSetting)
+
+	switch v := req.GetSetting().GetSetting().(type) {
+	case *pb.LoadpointSetting_Mode:
+		var mode api.ChargeMode
+		for m, pm := range grpcModes {
+			if pm == v.Mode {
+				mode = m
+			}
+		}
+
+		val, err := apply(setters, "mode", string(mode))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_Mode{Mode: grpcModes[val.(api.ChargeMode)]}
+
+	case *pb.LoadpointSetting_MinSoc:
+		val, err := apply(setters, "minSoC", strconv.Itoa(int(v.MinSoc)))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_MinSoc{MinSoc: int32(val.(int))}
+
+	case *pb.LoadpointSetting_TargetSoc:
+		val, err := apply(setters, "targetSoC", strconv.Itoa(int(v.TargetSoc)))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_TargetSoc{TargetSoc: int32(val.(int))}
+
+	case *pb.LoadpointSetting_TargetEnergy:
+		val, err := apply(setters, "targetEnergy", formatFloat(v.TargetEnergy))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_TargetEnergy{TargetEnergy: val.(float64)}
+
+	case *pb.LoadpointSetting_MinCurrent:
+		val, err := apply(setters, "minCurrent", formatFloat(v.MinCurrent))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_MinCurrent{MinCurrent: val.(float64)}
+
+	case *pb.LoadpointSetting_MaxCurrent:
+		val, err := apply(setters, "maxCurrent", formatFloat(v.MaxCurrent))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_MaxCurrent{MaxCurrent: val.(float64)}
+
+	case *pb.LoadpointSetting_Phases:
+		val, err := apply(setters, "phases", strconv.Itoa(int(v.Phases)))
+		if err != nil {
+			return nil, err
+		}
+		res.Setting = &pb.LoadpointSetting_Phases{Phases: int32(val.(int))}
+
+	default:
+		return nil, status.Error(codes.InvalidArgument, "missing setting")
+	}
+
+	return &pb.SetLoadpointReply{Applied: res}, nil
+}
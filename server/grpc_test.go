@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/evcc-io/evcc/api/proto/pb"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type grpcSite struct {
+	site.API
+	bufferSoC float64
+}
+
+func (s *grpcSite) LoadPoints() []loadpoint.API {
+	return nil
+}
+
+func (s *grpcSite) GetBufferSoC() float64 {
+	return s.bufferSoC
+}
+
+func (s *grpcSite) SetBufferSoC(soc float64) error {
+	s.bufferSoC = soc
+	return nil
+}
+
+func grpcClient(t *testing.T, s *GRPC) pb.EvccClient {
+	l := bufconn.Listen(1 << 16)
+
+	srv := s.Server()
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewEvccClient(conn)
+}
+
+func TestGRPCSetSite(t *testing.T) {
+	auth, err := NewAuth(AuthConfig{Tokens: []TokenConfig{{Token: "read"}, {Token: "control", Role: RoleControl}}})
+	require.NoError(t, err)
+
+	site := new(grpcSite)
+	client := grpcClient(t, NewGRPC(site, util.NewCache(), auth))
+	ctx := context.Background()
+	req := &pb.SetSiteRequest{Setting: &pb.SiteSetting{Setting: &pb.SiteSetting_BufferSoc{BufferSoc: 80}}}
+
+	_, err = client.SetSite(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.SetSite(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer read"), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer control")
+
+	_, err = client.SetSite(ctx, &pb.SetSiteRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	res, err := client.SetSite(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, res.Applied.GetBufferSoc())
+	assert.Equal(t, 80.0, site.bufferSoC)
+
+	_, err = client.SetLoadpoint(ctx, &pb.SetLoadpointRequest{
+		Loadpoint: 1,
+		Setting:   &pb.LoadpointSetting{Setting: &pb.LoadpointSetting_Mode{Mode: pb.ChargeMode_CHARGE_MODE_PV}},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCState(t *testing.T) {
+	lp := 0
+	cache := util.NewCache()
+	cache.Add("gridPower", util.Param{Key: "gridPower", Val: 1000.0})
+	cache.Add("0.mode", util.Param{LoadPoint: &lp, Key: "mode", Val: "pv"})
+
+	s := NewGRPC(new(grpcSite), cache, nil)
+	client := grpcClient(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.State(ctx, &pb.StateRequest{})
+	require.NoError(t, err)
+
+	// snapshot
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, res.Site.GridPower)
+	require.Len(t, res.Loadpoints, 1)
+	assert.Equal(t, int32(1), res.Loadpoints[0].Loadpoint)
+	assert.Equal(t, pb.ChargeMode_CHARGE_MODE_PV, res.Loadpoints[0].Mode)
+
+	// updates
+	cache.Add("0.mode", util.Param{LoadPoint: &lp, Key: "mode", Val: "now"})
+
+	in := make(chan util.Param)
+	go s.Run(in)
+	in <- util.Param{LoadPoint: &lp, Key: "mode", Val: "now"}
+
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.ChargeMode_CHARGE_MODE_NOW, res.Loadpoints[0].Mode)
+}
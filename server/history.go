@@ -0,0 +1,388 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// timelinePoints is the max number of periods returned by the timeline
+const timelinePoints = 360
+
+// HistoryConfig is the power history configuration
+type HistoryConfig struct {
+	Resolution time.Duration // storage resolution in whole seconds, defaults to 10s
+	Retention  time.Duration // defaults to 24h
+	Persist    bool          // store completed periods in the database
+}
+
+// site keys recorded by the history
+var historyKeys = []string{state.GridPower, state.PvPower, state.BatteryPower, state.HomePower}
+
+// bucket accumulates the values of a single period
+type bucket struct {
+	sum   float64
+	count int
+}
+
+// seriesKey identifies a site or loadpoint series
+type seriesKey struct {
+	loadpoint int // 1-based, 0 for site
+	key       string
+}
+
+// historyRecord is a persisted history period
+type historyRecord struct {
+	Slot      int64  `gorm:"primaryKey;autoIncrement:false"`
+	Loadpoint int    `gorm:"primaryKey;autoIncrement:false"`
+	Key       string `gorm:"primaryKey"`
+	Sum       float64
+	Count     int
+}
+
+// TableName implements the gorm.Tabler interface
+func (historyRecord) TableName() string {
+	return "history"
+}
+
+// History keeps recent power values in memory for the UI chart
+type History struct {
+	mu         sync.RWMutex
+	log        *util.Logger
+	db         *gorm.DB
+	resolution time.Duration
+	retention  time.Duration
+	loadpoints int
+	series     map[seriesKey]map[int64]*bucket
+	current    map[seriesKey]int64 // slot currently accumulated
+}
+
+// NewHistory creates the power history
+func NewHistory(cc HistoryConfig) *History {
+	if cc.Resolution < time.Second {
+		cc.Resolution = 10 * time.Second
+	}
+	if cc.Retention <= 0 {
+		cc.Retention = 24 * time.Hour
+	}
+
+	return &History{
+		log:        util.NewLogger("history"),
+		resolution: cc.Resolution,
+		retention:  cc.Retention,
+		series:     make(map[seriesKey]map[int64]*bucket),
+		current:    make(map[seriesKey]int64),
+	}
+}
+
+// Persist stores completed periods in the database and restores the retained periods
+func (h *History) Persist(db *gorm.DB) error {
+	if err := db.AutoMigrate(new(historyRecord)); err != nil {
+		return err
+	}
+
+	var records []historyRecord
+	if err := db.Where("slot >= ?", time.Now().Add(-h.retention).Unix()).Find(&records).Error; err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, r := range records {
+		sk := seriesKey{loadpoint: r.Loadpoint, key: r.Key}
+		if sk.loadpoint > h.loadpoints {
+			h.loadpoints = sk.loadpoint
+		}
+
+		if h.series[sk] == nil {
+			h.series[sk] = make(map[int64]*bucket)
+		}
+		h.series[sk][r.Slot] = &bucket{sum: r.Sum, count: r.Count}
+	}
+
+	h.db = db
+
+	return nil
+}
+
+// Run records the published power values and publishes completed periods to out, if not nil
+func (h *History) Run(in <-chan util.Param, out chan<- util.Param) {
+	for p := range in {
+		val, ok := p.Val.(float64)
+		if !ok {
+			continue
+		}
+
+		var sk seriesKey
+		switch {
+		case p.LoadPoint == nil:
+			if !slices.Contains(historyKeys, p.Key) {
+				continue
+			}
+			sk = seriesKey{key: p.Key}
+		case p.Key == state.ChargePower:
+			sk = seriesKey{loadpoint: *p.LoadPoint + 1, key: p.Key}
+		default:
+			continue
+		}
+
+		if period, ok := h.add(time.Now(), sk, val); ok && out != nil {
+			// publish asynchronously as out feeds the input
+			go func() { out <- util.Param{Key: "timeline", Val: period} }()
+		}
+	}
+}
+
+// add accumulates the value and removes expired buckets.
+// It returns the completed period when the grid power period has rolled over.
+func (h *History) add(ts time.Time, sk seriesKey, val float64) (timelinePeriod, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var (
+		period    timelinePeriod
+		completed bool
+	)
+
+	if sk.loadpoint > h.loadpoints {
+		h.loadpoints = sk.loadpoint
+	}
+
+	buckets, ok := h.series[sk]
+	if !ok {
+		buckets = make(map[int64]*bucket)
+		h.series[sk] = buckets
+	}
+
+	slot := ts.Truncate(h.resolution).Unix()
+
+	b, ok := buckets[slot]
+	if !ok {
+		b = new(bucket)
+		buckets[slot] = b
+
+		expired := ts.Add(-h.retention).Unix()
+		for s := range buckets {
+			if s < expired {
+				delete(buckets, s)
+			}
+		}
+
+		if prev, ok := h.current[sk]; ok {
+			h.persist(sk, prev, expired)
+
+			if completed = sk == (seriesKey{key: state.GridPower}); completed {
+				period = h.period(prev)
+			}
+		}
+
+		h.current[sk] = slot
+	}
+
+	b.sum += val
+	b.count++
+
+	return period, completed
+}
+
+// persist stores the completed period of the series and removes expired periods (no mutex)
+func (h *History) persist(sk seriesKey, slot, expired int64) {
+	if h.db == nil {
+		return
+	}
+
+	b, ok := h.series[sk][slot]
+	if !ok {
+		return
+	}
+
+	err := h.db.Create(&historyRecord{Slot: slot, Loadpoint: sk.loadpoint, Key: sk.key, Sum: b.sum, Count: b.count}).Error
+	if err == nil && sk == (seriesKey{key: state.GridPower}) {
+		err = h.db.Where("slot < ?", expired).Delete(new(historyRecord)).Error
+	}
+
+	if err != nil {
+		h.log.ERROR.Println(err)
+	}
+}
+
+// timelinePeriod is the average power of a single period
+type timelinePeriod struct {
+	Time       time.Time  `json:"time"`
+	Grid       *float64   `json:"grid"`
+	Pv         *float64   `json:"pv"`
+	Battery    *float64   `json:"battery"`
+	Home       *float64   `json:"home"`
+	Loadpoints []*float64 `json:"loadpoints"`
+}
+
+// period returns the average power of the given slot (no mutex)
+func (h *History) period(slot int64) timelinePeriod {
+	avg := func(sk seriesKey) *float64 {
+		if b, ok := h.series[sk][slot]; ok && b.count > 0 {
+			res := b.sum / float64(b.count)
+			return &res
+		}
+		return nil
+	}
+
+	res := timelinePeriod{
+		Time:    time.Unix(slot, 0),
+		Grid:    avg(seriesKey{key: state.GridPower}),
+		Pv:      avg(seriesKey{key: state.PvPower}),
+		Battery: avg(seriesKey{key: state.BatteryPower}),
+		Home:    avg(seriesKey{key: state.HomePower}),
+	}
+
+	for lp := 1; lp <= h.loadpoints; lp++ {
+		res.Loadpoints = append(res.Loadpoints, avg(seriesKey{loadpoint: lp, key: state.ChargePower}))
+	}
+
+	return res
+}
+
+// historyResult is the power history with one value per period, null where no values were recorded
+type historyResult struct {
+	Resolution int64        `json:"resolution"` // seconds
+	Time       []time.Time  `json:"time"`
+	Grid       []*float64   `json:"grid"`
+	Pv         []*float64   `json:"pv"`
+	Battery    []*float64   `json:"battery"`
+	Home       []*float64   `json:"home"`
+	Loadpoints [][]*float64 `json:"loadpoints"` // charge power
+}
+
+// query returns the averaged history of the given duration until now at the given resolution
+func (h *History) query(now time.Time, resolution, duration time.Duration) (historyResult, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if resolution == 0 {
+		resolution = h.resolution
+	}
+	if resolution < h.resolution || resolution%h.resolution != 0 {
+		return historyResult{}, fmt.Errorf("resolution must be a multiple of %v", h.resolution)
+	}
+
+	if duration == 0 || duration > h.retention {
+		duration = h.retention
+	}
+	if duration < resolution {
+		return historyResult{}, errors.New("duration shorter than resolution")
+	}
+
+	end := now.Truncate(resolution)
+	start := end.Add(-duration + resolution)
+
+	var periods []time.Time
+	for ts := start; !ts.After(end); ts = ts.Add(resolution) {
+		periods = append(periods, ts)
+	}
+
+	values := func(sk seriesKey) []*float64 {
+		sums := make([]bucket, len(periods))
+
+		for slot, b := range h.series[sk] {
+			if idx := int((slot - start.Unix()) / int64(resolution/time.Second)); slot >= start.Unix() && idx < len(periods) {
+				sums[idx].sum += b.sum
+				sums[idx].count += b.count
+			}
+		}
+
+		res := make([]*float64, len(periods))
+		for i, b := range sums {
+			if b.count > 0 {
+				avg := b.sum / float64(b.count)
+				res[i] = &avg
+			}
+		}
+
+		return res
+	}
+
+	res := historyResult{
+		Resolution: int64(resolution / time.Second),
+		Time:       periods,
+		Grid:       values(seriesKey{key: state.GridPower}),
+		Pv:         values(seriesKey{key: state.PvPower}),
+		Battery:    values(seriesKey{key: state.BatteryPower}),
+		Home:       values(seriesKey{key: state.HomePower}),
+	}
+
+	for lp := 1; lp <= h.loadpoints; lp++ {
+		res.Loadpoints = append(res.Loadpoints, values(seriesKey{loadpoint: lp, key: state.ChargePower}))
+	}
+
+	return res, nil
+}
+
+// historyHandler returns the recent power history, e.g. ?resolution=5m&duration=6h
+func historyHandler(h *History) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var resolution, duration time.Duration
+
+		for param, val := range map[string]*time.Duration{
+			"resolution": &resolution,
+			"duration":   &duration,
+		} {
+			if s := r.URL.Query().Get(param); s != "" {
+				d, err := time.ParseDuration(s)
+				if err != nil {
+					jsonError(w, r, http.StatusBadRequest, fmt.Errorf("%s: %w", param, err))
+					return
+				}
+				*val = d
+			}
+		}
+
+		res, err := h.query(time.Now(), resolution, duration)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// timelineHandler returns the power history of the period downsampled to at most timelinePoints, e.g. ?period=24h
+func timelineHandler(h *History) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		period := 24 * time.Hour
+
+		if s := r.URL.Query().Get("period"); s != "" {
+			var err error
+			if period, err = time.ParseDuration(s); err != nil || period <= 0 {
+				jsonError(w, r, http.StatusBadRequest, fmt.Errorf("invalid period: %s", s))
+				return
+			}
+		}
+
+		if period > h.retention {
+			period = h.retention
+		}
+
+		// smallest multiple of the storage resolution not exceeding the max number of points
+		steps := (period/h.resolution + timelinePoints - 1) / timelinePoints
+		resolution := h.resolution * steps
+		if resolution == 0 {
+			resolution = h.resolution
+		}
+
+		res, err := h.query(time.Now(), resolution, period)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
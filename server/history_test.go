@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	h := NewHistory(HistoryConfig{Resolution: time.Minute, Retention: time.Hour})
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	grid := seriesKey{key: state.GridPower}
+	lp := seriesKey{loadpoint: 2, key: state.ChargePower}
+
+	h.add(now.Add(-2*time.Hour), grid, 9999) // expired below
+	h.add(now.Add(-10*time.Minute), grid, 1000)
+	h.add(now.Add(-9*time.Minute), grid, 2000)
+	h.add(now.Add(-1*time.Minute), grid, 3000)
+	h.add(now.Add(-1*time.Minute), lp, 11000)
+	h.add(now, grid, 4000)
+
+	assert.Len(t, h.series[grid], 4, "expired bucket removed")
+
+	res, err := h.query(now, 5*time.Minute, 15*time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(300), res.Resolution)
+	require.Len(t, res.Time, 3)
+	assert.Equal(t, now.Add(-10*time.Minute), res.Time[0])
+
+	require.Len(t, res.Grid, 3)
+	assert.Equal(t, 1500.0, *res.Grid[0])
+	assert.Equal(t, 3000.0, *res.Grid[1])
+	assert.Equal(t, 4000.0, *res.Grid[2])
+	assert.Nil(t, res.Pv[0], "no values")
+
+	require.Len(t, res.Loadpoints, 2)
+	assert.Nil(t, res.Loadpoints[0][1])
+	assert.Equal(t, 11000.0, *res.Loadpoints[1][1])
+
+	_, err = h.query(now, 90*time.Second, 0)
+	assert.Error(t, err, "resolution not a multiple")
+}
+
+func TestHistoryPersist(t *testing.T) {
+	gdb, err := db.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(10 * time.Second)
+	grid := seriesKey{key: state.GridPower}
+	lp := seriesKey{loadpoint: 1, key: state.ChargePower}
+
+	h := NewHistory(HistoryConfig{})
+	require.NoError(t, h.Persist(gdb))
+
+	h.add(now.Add(-20*time.Second), lp, 11000)
+	h.add(now.Add(-20*time.Second), grid, 1000)
+	h.add(now.Add(-15*time.Second), grid, 2000)
+
+	_, completed := h.add(now.Add(-10*time.Second), lp, 7000)
+	assert.False(t, completed, "only grid power completes the period")
+
+	period, completed := h.add(now.Add(-10*time.Second), grid, 3000)
+	require.True(t, completed)
+	assert.Equal(t, now.Add(-20*time.Second), period.Time)
+	assert.Equal(t, 1500.0, *period.Grid)
+	assert.Nil(t, period.Pv)
+	require.Len(t, period.Loadpoints, 1)
+	assert.Equal(t, 11000.0, *period.Loadpoints[0])
+
+	// restore completed periods only
+	h = NewHistory(HistoryConfig{})
+	require.NoError(t, h.Persist(gdb))
+
+	assert.Len(t, h.series[grid], 1)
+	assert.Equal(t, bucket{sum: 3000, count: 2}, *h.series[grid][now.Add(-20*time.Second).Unix()])
+	assert.Equal(t, 1, h.loadpoints)
+}
+
+func TestTimeline(t *testing.T) {
+	h := NewHistory(HistoryConfig{})
+	h.add(time.Now(), seriesKey{key: state.GridPower}, 1000)
+
+	for period, points := range map[string]int{
+		"":    360, // 24h at 4m
+		"1h":  360, // 1h at 10s
+		"6h":  360, // 6h at 1m
+		"90m": 270, // 90m at 20s
+	} {
+		w := httptest.NewRecorder()
+		timelineHandler(h)(w, httptest.NewRequest("GET", "/api/timeline?period="+period, nil))
+		require.Equal(t, 200, w.Code, period)
+
+		var res struct {
+			Result historyResult
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Len(t, res.Result.Time, points, period)
+		assert.Equal(t, 1000.0, *res.Result.Grid[points-1], period)
+	}
+
+	w := httptest.NewRecorder()
+	timelineHandler(h)(w, httptest.NewRequest("GET", "/api/timeline?period=foo", nil))
+	assert.Equal(t, 400, w.Code)
+}
@@ -91,16 +91,80 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 		handlers.AllowedHeaders([]string{"Content-Type"}),
 	))
 
-	// site api
+	// global api
 	routes := map[string]route{
-		"health":        {[]string{"GET"}, "/health", healthHandler(site)},
-		"state":         {[]string{"GET"}, "/state", stateHandler(cache)},
-		"buffersoc":     {[]string{"POST", "OPTIONS"}, "/buffersoc/{value:[0-9.]+}", floatHandler(site.SetBufferSoC, site.GetBufferSoC)},
-		"prioritysoc":   {[]string{"POST", "OPTIONS"}, "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoC, site.GetPrioritySoC)},
-		"residualpower": {[]string{"POST", "OPTIONS"}, "/residualpower/{value:[-0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
+		"logs":          {[]string{"GET"}, "/logs", logsHandler},
+		"loglevels":     {[]string{"GET"}, "/loglevel", logLevelsHandler},
+		"loglevel":      {[]string{"PUT", "OPTIONS"}, "/loglevel/{area:[a-zA-Z0-9_-]+}", logLevelHandler},
+		"spec":          {[]string{"GET"}, "/spec", specHandler(router)},
+		"docs":          {[]string{"GET"}, "/docs", swaggerHandler()},
 		"sessions":      {[]string{"GET"}, "/sessions", sessionHandler},
+		"sessions2":     {[]string{"POST", "OPTIONS"}, "/sessions/import", sessionImportHandler},
+		"sessions3":     {[]string{"GET"}, "/sessions/trace/{trace:[0-9a-f]+}", sessionTraceHandler},
+		"users":         {[]string{"GET"}, "/users", usersHandler},
+		"users2":        {[]string{"POST", "OPTIONS"}, "/users", userSaveHandler},
+		"user":          {[]string{"PUT", "OPTIONS"}, "/users/{id:[0-9]+}", userSaveHandler},
+		"user2":         {[]string{"DELETE"}, "/users/{id:[0-9]+}", userDeleteHandler},
+		"user3":         {[]string{"GET"}, "/users/{id:[0-9]+}/sessions", userSessionsHandler},
+		"vehiclepush":   {[]string{"POST", "OPTIONS"}, "/vehicle/{id:[0-9a-zA-Z_.-]+}/push", vehiclePushHandler},
+		"experimental":  {[]string{"GET"}, "/settings/experimental", experimentalHandler},
+		"experimental2": {[]string{"POST", "OPTIONS"}, "/settings/experimental/{flag:[a-z0-9]+}/{value:[a-z]+}", experimentalHandler},
 		"telemetry":     {[]string{"GET"}, "/settings/telemetry", boolGetHandler(telemetry.Enabled)},
 		"telemetry2":    {[]string{"POST", "OPTIONS"}, "/settings/telemetry/{value:[a-z]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
+		"language":      {[]string{"GET"}, "/settings/language", languageHandler},
+		"language2":     {[]string{"PUT", "OPTIONS"}, "/settings/language", languageHandler},
+		"templates":     {[]string{"GET"}, "/config/templates/{class:[a-z]+}", templatesHandler},
+		"template":      {[]string{"GET"}, "/config/templates/{class:[a-z]+}/{name:[0-9a-zA-Z_.-]+}", templateHandler},
+		"template2":     {[]string{"POST", "OPTIONS"}, "/config/templates/{class:[a-z]+}/{name:[0-9a-zA-Z_.-]+}/validate", templateValidateHandler},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+
+	registerSiteRoutes(api, site, cache)
+}
+
+// RegisterSitesHandlers connects the http handlers of the site with given id below /api/sites/{id}
+func (s *HTTPd) RegisterSitesHandlers(id int, site site.API, cache *util.Cache) {
+	router := s.Server.Handler.(*mux.Router)
+
+	api := router.PathPrefix(fmt.Sprintf("/api/sites/%d", id)).Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	registerSiteRoutes(api, site, cache)
+}
+
+// RegisterSocketHub serves the websocket of an additional site, e.g. /ws/sites/1
+func (s *HTTPd) RegisterSocketHub(path string, hub *SocketHub) {
+	s.Server.Handler.(*mux.Router).HandleFunc(path, socketHandler(hub))
+}
+
+// registerSiteRoutes connects the site and loadpoint handlers to the api router
+func registerSiteRoutes(api *mux.Router, site site.API, cache *util.Cache) {
+	// site api
+	routes := map[string]route{
+		"health":         {[]string{"GET"}, "/health", healthHandler(site)},
+		"state":          {[]string{"GET"}, "/state", stateHandler(cache)},
+		"snapshot":       {[]string{"GET"}, "/snapshot", snapshotHandler(cache)},
+		"batterymode":    {[]string{"GET"}, "/batterymode", batteryModeHandler(site)},
+		"batterymode2":   {[]string{"POST", "OPTIONS"}, "/batterymode/{mode:[a-z]+}/{expiry:[0-9TZ:.-]+}", batteryModeHandler(site)},
+		"batterymode3":   {[]string{"DELETE", "OPTIONS"}, "/batterymode", batteryModeHandler(site)},
+		"calendar":       {[]string{"GET"}, "/calendar.ics", calendarHandler(site)},
+		"buffersoc":      {[]string{"POST", "OPTIONS"}, "/buffersoc/{value:[0-9.]+}", floatHandler(site.SetBufferSoC, site.GetBufferSoC)},
+		"bufferstartsoc": {[]string{"POST", "OPTIONS"}, "/bufferstartsoc/{value:[0-9.]+}", floatHandler(site.SetBufferStartSoC, site.GetBufferStartSoC)},
+		"prioritysoc":    {[]string{"POST", "OPTIONS"}, "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoC, site.GetPrioritySoC)},
+		"residualpower":  {[]string{"POST", "OPTIONS"}, "/residualpower/{value:[-0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
+		"demandresponse": {[]string{"POST", "OPTIONS"}, "/demandresponse/override/{value:[a-z]+}", boolHandler(site.SetDemandResponseOverride, site.GetDemandResponseOverride)},
+		"climatise":      {[]string{"POST", "OPTIONS"}, "/vehicles/{name}/climatise", vehicleClimateHandler(site)},
+		"climatise2":     {[]string{"DELETE", "OPTIONS"}, "/vehicles/{name}/climatise", vehicleClimateHandler(site)},
+		"wakeup":         {[]string{"POST", "OPTIONS"}, "/vehicles/{name}/wakeup", vehicleWakeUpHandler(site)},
+		"tariff":         {[]string{"GET"}, "/tariff/{tariff:grid|feedin|co2}", tariffHandler(site)},
+		"statistics":     {[]string{"GET"}, "/statistics", statisticsHandler(site)},
 	}
 
 	for _, r := range routes {
@@ -112,26 +176,65 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 		loadpoint := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", id)).Subrouter()
 
 		routes := map[string]route{
+			"settings":      {[]string{"PATCH", "OPTIONS"}, "", settingsHandler(lp)},
 			"mode":          {[]string{"POST", "OPTIONS"}, "/mode/{value:[a-z]+}", chargeModeHandler(lp)},
 			"targetenergy":  {[]string{"POST", "OPTIONS"}, "/targetenergy/{value:[0-9.]+}", floatHandler(pass(lp.SetTargetEnergy), lp.GetTargetEnergy)},
 			"targetsoc":     {[]string{"POST", "OPTIONS"}, "/targetsoc/{value:[0-9]+}", intHandler(pass(lp.SetTargetSoC), lp.GetTargetSoC)},
 			"minsoc":        {[]string{"POST", "OPTIONS"}, "/minsoc/{value:[0-9]+}", intHandler(pass(lp.SetMinSoC), lp.GetMinSoC)},
+			"vehiclesoc":    {[]string{"POST", "OPTIONS"}, "/vehiclesoc/{value:[0-9.]+}", floatHandler(lp.SetVehicleSoC, lp.GetVehicleSoC)},
 			"mincurrent":    {[]string{"POST", "OPTIONS"}, "/mincurrent/{value:[0-9.]+}", floatHandler(pass(lp.SetMinCurrent), lp.GetMinCurrent)},
 			"maxcurrent":    {[]string{"POST", "OPTIONS"}, "/maxcurrent/{value:[0-9.]+}", floatHandler(pass(lp.SetMaxCurrent), lp.GetMaxCurrent)},
 			"phases":        {[]string{"POST", "OPTIONS"}, "/phases/{value:[0-9]+}", phasesHandler(lp)},
 			"targetcharge":  {[]string{"POST", "OPTIONS"}, "/targetcharge/{soc:[0-9]+}/{time:[0-9TZ:.-]+}", targetChargeHandler(lp)},
 			"targetcharge2": {[]string{"DELETE", "OPTIONS"}, "/targetcharge", targetChargeRemoveHandler(lp)},
+			"plan":          {[]string{"GET"}, "/plan", planHandler(lp)},
+			"schedules":     {[]string{"GET"}, "/schedules", schedulesHandler(lp)},
+			"schedules2":    {[]string{"PUT", "OPTIONS"}, "/schedules", schedulesUpdateHandler(lp)},
+			"commands":      {[]string{"GET"}, "/charger/commands", chargerCommandsHandler(lp)},
+			"command":       {[]string{"POST", "OPTIONS"}, "/charger/command/{command:[a-z]+}", chargerCommandHandler(lp)},
+			"diagnostics":   {[]string{"GET"}, "/diagnostics", chargerDiagnosticsHandler(lp)},
+			"selftest":      {[]string{"GET"}, "/charger/selftest", chargerSelfTestHandler(lp)},
+			"selftest2":     {[]string{"POST", "OPTIONS"}, "/charger/selftest", chargerSelfTestStartHandler(lp)},
 			"vehicle":       {[]string{"POST", "OPTIONS"}, "/vehicle/{vehicle:[0-9]+}", vehicleHandler(site, lp)},
 			"vehicle2":      {[]string{"DELETE", "OPTIONS"}, "/vehicle", vehicleRemoveHandler(lp)},
 			"vehicleDetect": {[]string{"PATCH", "OPTIONS"}, "/vehicle", vehicleDetectHandler(lp)},
-			"remotedemand":  {[]string{"POST", "OPTIONS"}, "/remotedemand/{demand:[a-z]+}/{source::[0-9a-zA-Z_-]+}", remoteDemandHandler(lp)},
+			"guest":         {[]string{"POST", "OPTIONS"}, "/guest", guestSessionStartHandler(lp)},
+			"guest2":        {[]string{"GET"}, "/guest", guestSessionHandler(lp)},
+			"guest3":        {[]string{"DELETE", "OPTIONS"}, "/guest", guestSessionStopHandler(lp)},
+			"session":       {[]string{"POST", "OPTIONS"}, "/session", guestSessionStartHandler(lp)},
+			"session2":      {[]string{"GET"}, "/session", guestSessionHandler(lp)},
+			"session3":      {[]string{"DELETE", "OPTIONS"}, "/session", guestSessionStopHandler(lp)},
+			"remotedemand":  {[]string{"POST", "OPTIONS"}, "/remotedemand/{demand:[a-z]+}/{source:[0-9a-zA-Z_-]+}", remoteDemandHandler(lp)},
+			"remotebudget":  {[]string{"POST", "OPTIONS"}, "/remotebudget/{power:[0-9.]+}/{source:[0-9a-zA-Z_-]+}", remoteBudgetHandler(lp)},
+			"remotebudget2": {[]string{"DELETE", "OPTIONS"}, "/remotebudget/{source:[0-9a-zA-Z_-]+}", remoteBudgetRemoveHandler(lp)},
+			"heartbeat":     {[]string{"POST", "OPTIONS"}, "/heartbeat/{source:[0-9a-zA-Z_-]+}", remoteHeartbeatHandler(lp)},
 		}
 
 		for _, r := range routes {
 			loadpoint.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
 		}
 	}
+}
+
+// RegisterDeviceHandlers connects the http handlers to the configured devices
+func (s *HTTPd) RegisterDeviceHandlers(dp DeviceProvider) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	routes := map[string]route{
+		"capabilities": {[]string{"GET"}, "/config/devices/{name:[0-9a-zA-Z_.-]+}/capabilities", deviceCapabilitiesHandler(dp)},
+	}
 
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
 }
 
 // RegisterShutdownHandler connects the http handlers to the site
@@ -158,3 +261,167 @@ func (s *HTTPd) RegisterShutdownHandler(callback func()) {
 		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
 	}
 }
+
+// RegisterHistoryHandler connects the power history
+func (s *HTTPd) RegisterHistoryHandler(history *History) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	routes := map[string]route{
+		"history":  {[]string{"GET"}, "/history", historyHandler(history)},
+		"timeline": {[]string{"GET"}, "/timeline", timelineHandler(history)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterDiscoveryHandler connects the lan device discovery
+func (s *HTTPd) RegisterDiscoveryHandler(discovery *Discovery) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	api.Methods("GET").Path("/discovery").Handler(discoveryHandler(discovery))
+}
+
+// RegisterReloadHandler connects the config reload handler
+func (s *HTTPd) RegisterReloadHandler(callback func() error) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	routes := map[string]route{
+		"reload": {[]string{"POST", "OPTIONS"}, "/config/reload", func(w http.ResponseWriter, r *http.Request) {
+			if err := callback(); err != nil {
+				jsonError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterConfigHandler connects the configuration export and import to the api
+func (s *HTTPd) RegisterConfigHandler(export func() map[string]interface{}, restore func([]byte) (bool, error)) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	routes := map[string]route{
+		"config":  {[]string{"GET"}, "/config", configExportHandler(export)},
+		"config2": {[]string{"POST", "OPTIONS"}, "/config", configImportHandler(restore)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterSmartHomeHandlers connects the smart home bridge api
+func (s *HTTPd) RegisterSmartHomeHandlers(sh *SmartHome, site site.API) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix(smartHomePath).Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+	))
+
+	routes := map[string]route{
+		"authorize": {[]string{"GET"}, "/oauth/authorize", smartHomeAuthorizeHandler(sh)},
+		"token":     {[]string{"POST", "OPTIONS"}, "/oauth/token", smartHomeTokenHandler(sh)},
+		"devices":   {[]string{"GET"}, "/devices", smartHomeDevicesHandler(sh, site)},
+		"command":   {[]string{"POST", "OPTIONS"}, "/devices/{id:lp[0-9]+}", smartHomeCommandHandler(sh, site)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterDashboardHandler connects the read-only public dashboard api
+func (s *HTTPd) RegisterDashboardHandler(conf DashboardConfig, cache *util.Cache) {
+	router := s.Server.Handler.(*mux.Router)
+
+	api := router.PathPrefix(dashboardPath).Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS())
+
+	api.Methods("GET").Path("/{token}").Handler(dashboardHandler(conf, cache))
+}
+
+// RegisterClusterHandler connects the load sharing api of the cluster leader
+func (s *HTTPd) RegisterClusterHandler(leader *ClusterLeader) {
+	router := s.Server.Handler.(*mux.Router)
+
+	api := router.PathPrefix(clusterPath).Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+
+	routes := map[string]route{
+		"status": {[]string{"GET"}, "", clusterStatusHandler(leader)},
+		"report": {[]string{"POST"}, "/{name:[a-zA-Z0-9_.-]+}", clusterReportHandler(leader)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterMobileHandlers connects the mobile app api
+func (s *HTTPd) RegisterMobileHandlers(m *Mobile, site site.API, cache *util.Cache) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/mobile").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", IdempotencyKeyHeader}),
+	))
+
+	routes := map[string]route{
+		"state":   {[]string{"GET"}, "/state", mobileStateHandler(cache)},
+		"device":  {[]string{"POST", "OPTIONS"}, "/devices", mobileDeviceHandler(m)},
+		"device2": {[]string{"DELETE", "OPTIONS"}, "/devices/{id:[0-9a-f]+}", mobileDeviceRemoveHandler(m)},
+		"command": {[]string{"POST", "OPTIONS"}, "/command", mobileCommandHandler(m, site)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/gorilla/mux"
+)
+
+// templatesHandler returns the schemas of all templates of a device class
+func templatesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	lang := requestLanguage(r)
+
+	res := make([]templates.Schema, 0)
+	for _, tmpl := range templates.ByClass(templates.Class(vars["class"])) {
+		res = append(res, tmpl.Schema(lang))
+	}
+
+	jsonResult(w, res)
+}
+
+// templateHandler returns the schema of a single template
+func templateHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	tmpl, err := templates.ByName(templates.Class(vars["class"]), vars["name"])
+	if err != nil {
+		jsonError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	jsonResult(w, tmpl.Schema(requestLanguage(r)))
+}
+
+// templateValidateHandler validates the posted param values against the template and returns them including defaults
+func templateValidateHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	tmpl, err := templates.ByName(templates.Class(vars["class"]), vars["name"])
+	if err != nil {
+		jsonError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	var values map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := tmpl.ValidateValues(values); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	res := tmpl.Defaults(templates.TemplateRenderModeInstance)
+	for k, v := range values {
+		res[k] = v
+	}
+
+	jsonResult(w, res)
+}
+
+// DeviceProvider provides configured devices by name
+type DeviceProvider interface {
+	Device(name string) (interface{}, error)
+}
+
+// capabilities are the optional interfaces reported for configured devices
+var capabilities = []struct {
+	name string
+	is   func(interface{}) bool
+}{
+	{"meter", func(d interface{}) bool { _, ok := d.(api.Meter); return ok }},
+	{"meterEnergy", func(d interface{}) bool { _, ok := d.(api.MeterEnergy); return ok }},
+	{"meterCurrent", func(d interface{}) bool { _, ok := d.(api.MeterCurrent); return ok }},
+	{"meterVoltage", func(d interface{}) bool { _, ok := d.(api.MeterVoltage); return ok }},
+	{"battery", func(d interface{}) bool { _, ok := d.(api.Battery); return ok }},
+	{"charger", func(d interface{}) bool { _, ok := d.(api.Charger); return ok }},
+	{"milliAmps", func(d interface{}) bool { _, ok := d.(api.ChargerEx); return ok }},
+	{"phaseSwitcher", func(d interface{}) bool { _, ok := d.(api.PhaseSwitcher); return ok }},
+	{"chargeTimer", func(d interface{}) bool { _, ok := d.(api.ChargeTimer); return ok }},
+	{"chargeRater", func(d interface{}) bool { _, ok := d.(api.ChargeRater); return ok }},
+	{"identifier", func(d interface{}) bool { _, ok := d.(api.Identifier); return ok }},
+	{"authorizer", func(d interface{}) bool { _, ok := d.(api.Authorizer); return ok }},
+	{"vehicle", func(d interface{}) bool { _, ok := d.(api.Vehicle); return ok }},
+	{"chargeState", func(d interface{}) bool { _, ok := d.(api.ChargeState); return ok }},
+	{"finishTimer", func(d interface{}) bool { _, ok := d.(api.VehicleFinishTimer); return ok }},
+	{"range", func(d interface{}) bool { _, ok := d.(api.VehicleRange); return ok }},
+	{"climater", func(d interface{}) bool { _, ok := d.(api.VehicleClimater); return ok }},
+	{"odometer", func(d interface{}) bool { _, ok := d.(api.VehicleOdometer); return ok }},
+	{"position", func(d interface{}) bool { _, ok := d.(api.VehiclePosition); return ok }},
+	{"socLimiter", func(d interface{}) bool { _, ok := d.(api.SocLimiter); return ok }},
+	{"socLimitController", func(d interface{}) bool { _, ok := d.(api.SocLimitController); return ok }},
+	{"chargeController", func(d interface{}) bool { _, ok := d.(api.VehicleChargeController); return ok }},
+	{"resurrector", func(d interface{}) bool { _, ok := d.(api.Resurrector); return ok }},
+	{"faultReporter", func(d interface{}) bool { _, ok := d.(api.FaultReporter); return ok }},
+	{"diagnosis", func(d interface{}) bool { _, ok := d.(api.Diagnosis); return ok }},
+}
+
+// deviceCapabilities returns the capabilities and features implemented by the device
+func deviceCapabilities(device interface{}) []string {
+	res := make([]string, 0)
+
+	for _, c := range capabilities {
+		if c.is(device) {
+			res = append(res, c.name)
+		}
+	}
+
+	if fd, ok := device.(api.FeatureDescriber); ok {
+		for _, f := range fd.Features() {
+			res = append(res, f.String())
+		}
+	}
+
+	return res
+}
+
+// deviceCapabilitiesHandler returns the optional interfaces implemented by a configured device
+func deviceCapabilitiesHandler(dp DeviceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		device, err := dp.Device(vars["name"])
+		if err != nil {
+			jsonError(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		jsonResult(w, deviceCapabilities(device))
+	}
+}
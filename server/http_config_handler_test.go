@@ -0,0 +1,19 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type capabilityCharger struct {
+	api.Charger
+	api.PhaseSwitcher
+	api.Identifier
+}
+
+func TestDeviceCapabilities(t *testing.T) {
+	assert.Equal(t, []string{"charger", "phaseSwitcher", "identifier", "chargeState"}, deviceCapabilities(&capabilityCharger{}))
+	assert.Equal(t, []string{}, deviceCapabilities(struct{}{}))
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/mux"
+)
+
+// dashboardPath is the read-only public dashboard, authenticated by the dashboard token instead of api auth
+const dashboardPath = "/api/dashboard"
+
+// DashboardConfig is the read-only public dashboard configuration
+type DashboardConfig struct {
+	Token string // shared with the dashboard url, grants no control
+}
+
+var (
+	// energy flow without site title
+	dashboardSiteKeys = []string{
+		state.Currency, state.PvPower, state.GridPower, state.HomePower, state.BatteryPower, state.BatterySoC,
+	}
+	// charging state without loadpoint and vehicle titles, sessions or users
+	dashboardLoadpointKeys = []string{
+		state.Mode, state.Connected, state.Charging, state.ChargePower, state.ChargedEnergy, state.VehicleSoC,
+	}
+)
+
+// dashboardHandler returns the anonymized energy flow and charging state
+func dashboardHandler(conf DashboardConfig, cache *util.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !equal(mux.Vars(r)["token"], conf.Token) {
+			jsonError(w, r, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		values := cache.State()
+
+		lps, _ := values["loadpoints"].([]map[string]interface{})
+		loadpoints := make([]map[string]interface{}, 0, len(lps))
+		for _, lp := range lps {
+			loadpoints = append(loadpoints, pick(lp, dashboardLoadpointKeys))
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		jsonResult(w, map[string]interface{}{
+			"site":       pick(values, dashboardSiteKeys),
+			"loadpoints": loadpoints,
+		})
+	}
+}
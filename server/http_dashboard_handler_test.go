@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardHandler(t *testing.T) {
+	lp := 0
+	cache := util.NewCache()
+	cache.Add("siteTitle", util.Param{Key: "siteTitle", Val: "Home"})
+	cache.Add("pvPower", util.Param{Key: "pvPower", Val: 5000.0})
+	cache.Add("0.title", util.Param{LoadPoint: &lp, Key: "title", Val: "Garage"})
+	cache.Add("0.chargePower", util.Param{LoadPoint: &lp, Key: "chargePower", Val: 3700.0})
+
+	auth, err := NewAuth(AuthConfig{Tokens: []TokenConfig{{Token: "secret", Role: RoleControl}}})
+	require.NoError(t, err)
+
+	httpd := NewHTTPd(":0", NewSocketHub())
+	httpd.Router().Use(auth.Handler)
+	httpd.RegisterDashboardHandler(DashboardConfig{Token: "public"}, cache)
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		httpd.Handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	// dashboard token does not require api auth
+	w := request(http.MethodGet, "/api/dashboard/public")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Result struct {
+			Site       map[string]interface{}
+			Loadpoints []map[string]interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+	assert.Equal(t, map[string]interface{}{"pvPower": 5000.0}, res.Result.Site)
+	require.Len(t, res.Result.Loadpoints, 1)
+	assert.Equal(t, map[string]interface{}{"chargePower": 3700.0}, res.Result.Loadpoints[0])
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/api/dashboard/foo").Code)
+
+	// no control capability
+	assert.Equal(t, http.StatusMethodNotAllowed, request(http.MethodPost, "/api/dashboard/public").Code)
+
+	// other api requests remain protected
+	assert.True(t, protected(httptest.NewRequest(http.MethodGet, "/api/state", nil)))
+	assert.True(t, protected(httptest.NewRequest(http.MethodGet, "/api/dashboards", nil)))
+}
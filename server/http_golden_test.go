@@ -0,0 +1,209 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goldenCase is a request against the harness, its response body is compared with testdata/golden/<name>.json
+type goldenCase struct {
+	name        string
+	method      string
+	path        string
+	body        string
+	status      int
+	contentType string // defaults to json
+	noBody      bool   // body depends on runtime state and is not compared
+}
+
+// goldenCases are executed in order against a single harness, add a case for each new route
+var goldenCases = []goldenCase{
+	// global
+	{name: "logs", method: "GET", path: "/api/logs", status: http.StatusOK, noBody: true},
+	{name: "loglevels", method: "GET", path: "/api/loglevel", status: http.StatusOK, noBody: true},
+	{name: "loglevel-invalid", method: "PUT", path: "/api/loglevel/foo", body: `{"level":"bogus"}`, status: http.StatusBadRequest},
+	{name: "spec", method: "GET", path: "/api/spec", status: http.StatusOK, noBody: true},
+	{name: "docs", method: "GET", path: "/api/docs", status: http.StatusOK, contentType: "text/html", noBody: true},
+	{name: "sessions-offline", method: "GET", path: "/api/sessions", status: http.StatusBadRequest},
+	{name: "sessions-import-offline", method: "POST", path: "/api/sessions/import", status: http.StatusBadRequest},
+	{name: "sessions-trace-offline", method: "GET", path: "/api/sessions/trace/abc", status: http.StatusBadRequest},
+	{name: "users-offline", method: "GET", path: "/api/users", status: http.StatusBadRequest},
+	{name: "user-create-offline", method: "POST", path: "/api/users", body: `{"name":"anna"}`, status: http.StatusBadRequest},
+	{name: "user-update-offline", method: "PUT", path: "/api/users/1", body: `{"name":"anna"}`, status: http.StatusBadRequest},
+	{name: "user-delete-offline", method: "DELETE", path: "/api/users/1", status: http.StatusBadRequest},
+	{name: "user-sessions-offline", method: "GET", path: "/api/users/1/sessions", status: http.StatusBadRequest},
+	{name: "vehiclepush-unknown-post", method: "POST", path: "/api/vehicle/foo/push", body: `{"soc":50}`, status: http.StatusNotFound},
+	{name: "experimental", method: "GET", path: "/api/settings/experimental", status: http.StatusOK, noBody: true},
+	{name: "experimental-invalid", method: "POST", path: "/api/settings/experimental/foo/true", status: http.StatusBadRequest},
+	{name: "telemetry", method: "GET", path: "/api/settings/telemetry", status: http.StatusOK},
+	{name: "telemetry-unsponsored", method: "POST", path: "/api/settings/telemetry/true", status: http.StatusNotAcceptable},
+	{name: "language", method: "GET", path: "/api/settings/language", status: http.StatusOK, noBody: true},
+	{name: "language-set", method: "PUT", path: "/api/settings/language", body: `{"language":"de-CH"}`, status: http.StatusOK},
+	{name: "language-invalid", method: "PUT", path: "/api/settings/language", body: `{"language":"xx"}`, status: http.StatusBadRequest},
+	{name: "language-reset", method: "PUT", path: "/api/settings/language", body: `{"language":""}`, status: http.StatusOK, noBody: true},
+	{name: "templates", method: "GET", path: "/api/config/templates/charger", status: http.StatusOK, noBody: true},
+	{name: "template", method: "GET", path: "/api/config/templates/charger/keba", status: http.StatusOK, noBody: true},
+	{name: "template-validate-invalid", method: "POST", path: "/api/config/templates/charger/keba/validate", body: `{}`, status: http.StatusBadRequest},
+	{name: "capabilities-unknown", method: "GET", path: "/api/config/devices/foo/capabilities", status: http.StatusNotFound},
+	{name: "shutdown", method: "POST", path: "/api/shutdown", status: http.StatusNoContent},
+	{name: "history", method: "GET", path: "/api/history", status: http.StatusOK, noBody: true},
+	{name: "timeline", method: "GET", path: "/api/timeline", status: http.StatusOK, noBody: true},
+	{name: "discovery", method: "GET", path: "/api/discovery", status: http.StatusOK},
+	{name: "reload-failed", method: "POST", path: "/api/config/reload", status: http.StatusBadRequest},
+	{name: "config", method: "GET", path: "/api/config", status: http.StatusOK},
+	{name: "config-import", method: "POST", path: "/api/config", body: `{"site":{"title":"home"}}`, status: http.StatusOK},
+	{name: "config-import-invalid", method: "POST", path: "/api/config", body: `{}`, status: http.StatusBadRequest},
+	{name: "dashboard-unauthorized", method: "GET", path: "/api/dashboard/foo", status: http.StatusUnauthorized},
+	{name: "cluster", method: "GET", path: "/api/cluster", status: http.StatusOK},
+	{name: "cluster-report-unauthorized", method: "POST", path: "/api/cluster/garage", body: `{"loadpoints":[]}`, status: http.StatusUnauthorized},
+	{name: "mobile-state", method: "GET", path: "/api/mobile/state", status: http.StatusOK},
+	{name: "mobile-device-invalid", method: "POST", path: "/api/mobile/devices", body: `{`, status: http.StatusBadRequest},
+	{name: "mobile-device-remove-unknown", method: "DELETE", path: "/api/mobile/devices/abc", status: http.StatusNotFound},
+	{name: "mobile-command-invalid", method: "POST", path: "/api/mobile/command", body: `{`, status: http.StatusBadRequest},
+	{name: "smarthome-authorize-invalid", method: "GET", path: "/api/smarthome/oauth/authorize", status: http.StatusBadRequest},
+	{name: "smarthome-token-unauthorized", method: "POST", path: "/api/smarthome/oauth/token", status: http.StatusUnauthorized},
+	{name: "smarthome-devices", method: "GET", path: "/api/smarthome/devices", status: http.StatusOK}, // access token is checked by the auth middleware
+	{name: "smarthome-command-unknown", method: "POST", path: "/api/smarthome/devices/lp0", body: `{}`, status: http.StatusBadRequest},
+
+	// site
+	{name: "health", method: "GET", path: "/api/health", status: http.StatusOK},
+	{name: "state", method: "GET", path: "/api/state", status: http.StatusOK},
+	{name: "state-jq", method: "GET", path: "/api/state?jq=.loadpoints%7Clength", status: http.StatusOK},
+	{name: "state-jq-invalid", method: "GET", path: "/api/state?jq=.loadpoints%5B", status: http.StatusBadRequest},
+	{name: "snapshot", method: "GET", path: "/api/snapshot", status: http.StatusOK, noBody: true},
+	{name: "batterymode", method: "GET", path: "/api/batterymode", status: http.StatusOK},
+	{name: "batterymode-set", method: "POST", path: "/api/batterymode/hold/2023-01-02T04:00:00Z", status: http.StatusOK},
+	{name: "batterymode-invalid", method: "POST", path: "/api/batterymode/foo/2023-01-02T04:00:00Z", status: http.StatusBadRequest},
+	{name: "batterymode-remove", method: "DELETE", path: "/api/batterymode", status: http.StatusOK},
+	{name: "calendar", method: "GET", path: "/api/calendar.ics", status: http.StatusOK, contentType: "text/calendar", noBody: true},
+	{name: "buffersoc", method: "POST", path: "/api/buffersoc/50", status: http.StatusOK},
+	{name: "bufferstartsoc", method: "POST", path: "/api/bufferstartsoc/60", status: http.StatusOK},
+	{name: "prioritysoc", method: "POST", path: "/api/prioritysoc/40", status: http.StatusOK},
+	{name: "residualpower", method: "POST", path: "/api/residualpower/-100", status: http.StatusOK},
+	{name: "demandresponse", method: "POST", path: "/api/demandresponse/override/true", status: http.StatusOK},
+	{name: "demandresponse-invalid", method: "POST", path: "/api/demandresponse/override/foo", status: http.StatusBadRequest},
+	{name: "climatise-unknown", method: "POST", path: "/api/vehicles/foo/climatise", status: http.StatusNotFound},
+	{name: "climatise-remove-unknown", method: "DELETE", path: "/api/vehicles/foo/climatise", status: http.StatusNotFound},
+	{name: "wakeup-unknown", method: "POST", path: "/api/vehicles/foo/wakeup", status: http.StatusNotFound},
+	{name: "tariff", method: "GET", path: "/api/tariff/grid", status: http.StatusOK},
+	{name: "tariff-unavailable", method: "GET", path: "/api/tariff/feedin", status: http.StatusNotFound},
+	{name: "statistics", method: "GET", path: "/api/statistics", status: http.StatusOK},
+
+	// loadpoint
+	{name: "lp-settings", method: "PATCH", path: "/api/loadpoints/0", body: `{"mode":"now","targetSoC":90}`, status: http.StatusOK},
+	{name: "lp-settings-invalid", method: "PATCH", path: "/api/loadpoints/0", body: `{"mode":"foo"}`, status: http.StatusBadRequest},
+	{name: "lp-mode", method: "POST", path: "/api/loadpoints/0/mode/minpv", status: http.StatusOK},
+	{name: "lp-mode-invalid", method: "POST", path: "/api/loadpoints/0/mode/foo", status: http.StatusBadRequest},
+	{name: "lp-targetenergy", method: "POST", path: "/api/loadpoints/0/targetenergy/10", status: http.StatusOK},
+	{name: "lp-targetsoc", method: "POST", path: "/api/loadpoints/0/targetsoc/80", status: http.StatusOK},
+	{name: "lp-minsoc", method: "POST", path: "/api/loadpoints/0/minsoc/20", status: http.StatusOK},
+	{name: "lp-vehiclesoc", method: "POST", path: "/api/loadpoints/0/vehiclesoc/50", status: http.StatusOK},
+	{name: "lp-mincurrent", method: "POST", path: "/api/loadpoints/0/mincurrent/8", status: http.StatusOK},
+	{name: "lp-maxcurrent", method: "POST", path: "/api/loadpoints/0/maxcurrent/32", status: http.StatusOK},
+	{name: "lp-phases", method: "POST", path: "/api/loadpoints/0/phases/1", status: http.StatusOK},
+	{name: "lp-phases-invalid", method: "POST", path: "/api/loadpoints/0/phases/2", status: http.StatusBadRequest},
+	{name: "lp-targetcharge", method: "POST", path: "/api/loadpoints/0/targetcharge/80/2023-01-02T06:00:00Z", status: http.StatusOK},
+	{name: "lp-targetcharge-remove", method: "DELETE", path: "/api/loadpoints/0/targetcharge", status: http.StatusOK},
+	{name: "lp-plan", method: "GET", path: "/api/loadpoints/0/plan", status: http.StatusOK},
+	{name: "lp-schedules", method: "GET", path: "/api/loadpoints/0/schedules", status: http.StatusOK},
+	{name: "lp-schedules-update", method: "PUT", path: "/api/loadpoints/0/schedules", body: `[{"days":["mon"],"time":"07:00","soc":80}]`, status: http.StatusOK},
+	{name: "lp-schedules-invalid", method: "PUT", path: "/api/loadpoints/0/schedules", body: `{`, status: http.StatusBadRequest},
+	{name: "lp-commands", method: "GET", path: "/api/loadpoints/0/charger/commands", status: http.StatusOK},
+	{name: "lp-command", method: "POST", path: "/api/loadpoints/0/charger/command/reboot", status: http.StatusOK},
+	{name: "lp-command-unsupported", method: "POST", path: "/api/loadpoints/0/charger/command/wakeup", status: http.StatusBadRequest},
+	{name: "lp-diagnostics", method: "GET", path: "/api/loadpoints/0/diagnostics", status: http.StatusOK},
+	{name: "lp-selftest", method: "GET", path: "/api/loadpoints/0/charger/selftest", status: http.StatusOK},
+	{name: "lp-selftest-start", method: "POST", path: "/api/loadpoints/0/charger/selftest", status: http.StatusBadRequest},
+	{name: "lp-vehicle-invalid", method: "POST", path: "/api/loadpoints/0/vehicle/0", status: http.StatusBadRequest},
+	{name: "lp-vehicle-remove", method: "DELETE", path: "/api/loadpoints/0/vehicle", status: http.StatusOK},
+	{name: "lp-vehicle-detect", method: "PATCH", path: "/api/loadpoints/0/vehicle", status: http.StatusOK},
+	{name: "lp-guest-none", method: "GET", path: "/api/loadpoints/0/guest", status: http.StatusNotFound},
+	{name: "lp-guest-start", method: "POST", path: "/api/loadpoints/0/guest", body: `{"energy":10,"price":0.4}`, status: http.StatusOK},
+	{name: "lp-guest", method: "GET", path: "/api/loadpoints/0/guest", status: http.StatusOK},
+	{name: "lp-guest-stop", method: "DELETE", path: "/api/loadpoints/0/guest", status: http.StatusOK},
+	{name: "lp-session-start", method: "POST", path: "/api/loadpoints/0/session", body: `{"maxCost":5}`, status: http.StatusOK},
+	{name: "lp-session", method: "GET", path: "/api/loadpoints/0/session", status: http.StatusOK},
+	{name: "lp-session-stop", method: "DELETE", path: "/api/loadpoints/0/session", status: http.StatusOK},
+	{name: "lp-remotedemand", method: "POST", path: "/api/loadpoints/0/remotedemand/hard/hems", status: http.StatusOK},
+	{name: "lp-remotedemand-enable", method: "POST", path: "/api/loadpoints/0/remotedemand/enable/hems", status: http.StatusOK},
+	{name: "lp-remotebudget", method: "POST", path: "/api/loadpoints/0/remotebudget/2000/hems", status: http.StatusOK},
+	{name: "lp-remotebudget-remove", method: "DELETE", path: "/api/loadpoints/0/remotebudget/hems", status: http.StatusOK},
+	{name: "lp-heartbeat", method: "POST", path: "/api/loadpoints/0/heartbeat/hems", status: http.StatusOK},
+}
+
+func TestGolden(t *testing.T) {
+	h := newHarness(t)
+
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h.t = t
+			res := h.do(tc.method, tc.path, tc.body)
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, res.StatusCode, string(body))
+
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			assert.True(t, strings.HasPrefix(res.Header.Get("Content-Type"), contentType), res.Header.Get("Content-Type"))
+
+			if !tc.noBody {
+				h.golden(tc.name, body)
+			}
+		})
+	}
+}
+
+// TestGoldenCoverage ensures that each registered route is covered by a golden case
+func TestGoldenCoverage(t *testing.T) {
+	h := newHarness(t)
+
+	covered := make(map[string]bool)
+	for _, tc := range goldenCases {
+		var match mux.RouteMatch
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		require.True(t, h.router.Match(req, &match), tc.name)
+
+		tmpl, err := match.Route.GetPathTemplate()
+		require.NoError(t, err)
+		covered[tc.method+" "+tmpl] = true
+	}
+
+	err := h.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		for _, method := range methods {
+			if method != http.MethodOptions {
+				assert.True(t, covered[method+" "+tmpl], "missing golden case: %s %s", method, tmpl)
+			}
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestVehiclePushMethod(t *testing.T) {
+	h := newHarness(t)
+
+	res := h.do("GET", "/api/vehicle/foo/push?soc=50", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+}
@@ -5,25 +5,42 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/db"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/selftest"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
 	dbserver "github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/experimental"
+	"github.com/evcc-io/evcc/util/jq"
 	"github.com/evcc-io/evcc/util/locale"
+	"github.com/evcc-io/evcc/vehicle"
 	"github.com/gorilla/mux"
-	"golang.org/x/text/language"
+	"github.com/itchyny/gojq"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
 )
 
 var ignoreState = []string{"releaseNotes"} // excessive size
 
+var (
+	errDatabaseOffline      = locale.NewError("errors.databaseOffline", "database offline")
+	errTariffNotAvailable   = locale.NewError("errors.tariffNotAvailable", "tariff not available")
+	errNoGuestSession       = locale.NewError("errors.noGuestSession", "no guest session")
+	errStreamingUnsupported = errors.New("streaming not supported")
+)
+
 func indexHandler() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
@@ -42,8 +59,9 @@ func indexHandler() http.HandlerFunc {
 		}
 
 		if err := t.Execute(w, map[string]interface{}{
-			"Version": Version,
-			"Commit":  Commit,
+			"Version":  Version,
+			"Commit":   Commit,
+			"Language": locale.UserLanguage(),
 		}); err != nil {
 			log.ERROR.Println("httpd: failed to render main page:", err.Error())
 		}
@@ -68,9 +86,17 @@ func jsonResult(w http.ResponseWriter, res interface{}) {
 	jsonWrite(w, map[string]interface{}{"result": res})
 }
 
-func jsonError(w http.ResponseWriter, status int, err error) {
+// jsonError writes the error, translatable errors are localized to the request language
+func jsonError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	msg := err.Error()
+
+	var lerr *locale.Error
+	if errors.As(err, &lerr) {
+		msg = lerr.Localize(locale.NewLocalizer(requestLanguage(r)))
+	}
+
 	w.WriteHeader(status)
-	jsonWrite(w, map[string]interface{}{"error": err.Error()})
+	jsonWrite(w, map[string]interface{}{"error": msg})
 }
 
 func csvResult(ctx context.Context, w http.ResponseWriter, res any) {
@@ -116,7 +142,7 @@ func floatHandler(set func(float64) error, get func() float64) http.HandlerFunc
 		}
 
 		if err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
@@ -135,7 +161,7 @@ func intHandler(set func(int) error, get func() int) http.HandlerFunc {
 		}
 
 		if err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
@@ -150,13 +176,13 @@ func boolHandler(set func(bool) error, get func() bool) http.HandlerFunc {
 
 		val, err := strconv.ParseBool(vars["value"])
 		if err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
 		err = set(val)
 		if err != nil {
-			jsonError(w, http.StatusNotAcceptable, err)
+			jsonError(w, r, http.StatusNotAcceptable, err)
 			return
 		}
 
@@ -171,38 +197,164 @@ func boolGetHandler(get func() bool) http.HandlerFunc {
 	}
 }
 
-// stateHandler returns current charge mode
+// experimentalHandler returns and toggles the experimental subsystems
+func experimentalHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if r.Method == http.MethodPost {
+		flag, err := experimental.FlagString(vars["flag"])
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		val, err := strconv.ParseBool(vars["value"])
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		experimental.Enable(flag, val)
+	}
+
+	jsonResult(w, experimental.All())
+}
+
+// stateHandler returns the typed state tree, optionally filtered by a jq query like ?jq=.loadpoints[0].chargePower
 func stateHandler(cache *util.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res := cache.State()
+		tree, seq := cache.Tree()
 		for _, k := range ignoreState {
-			delete(res, k)
+			delete(tree, k)
+		}
+
+		res, err := state.Decode(tree)
+		if err != nil {
+			jsonError(w, r, http.StatusInternalServerError, err)
+			return
 		}
+
+		w.Header().Set("X-State-Seq", strconv.FormatUint(seq, 10))
+
+		q := r.URL.Query().Get("jq")
+		if q == "" {
+			jsonResult(w, res)
+			return
+		}
+
+		query, err := gojq.Parse(q)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		b, err := json.Marshal(res)
+		if err != nil {
+			jsonError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		val, err := jq.Query(query, b)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, val)
+	}
+}
+
+// snapshotHandler returns the current state and recent timeline for offline replay
+func snapshotHandler(cache *util.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := cache.State()
+		for _, k := range ignoreState {
+			delete(state, k)
+		}
+
+		var timeline []util.TimedParam
+		for _, p := range cache.Timeline() {
+			if !slices.Contains(ignoreState, p.Key) {
+				timeline = append(timeline, p)
+			}
+		}
+
+		jsonResult(w, util.Snapshot{
+			Created:  time.Now(),
+			Version:  FormattedVersion(),
+			State:    state,
+			Timeline: timeline,
+		})
+	}
+}
+
+// tariffHandler returns the tariff's current price and upcoming rates
+func tariffHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		t := site.GetTariff(vars["tariff"])
+		if t == nil {
+			jsonError(w, r, http.StatusNotFound, errTariffNotAvailable)
+			return
+		}
+
+		res := struct {
+			Price float64   `json:"price"`
+			Rates api.Rates `json:"rates,omitempty"`
+		}{}
+
+		var err error
+		if res.Price, err = t.CurrentPrice(); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if rater, ok := t.(api.Rater); ok {
+			if res.Rates, err = rater.Rates(); err != nil {
+				jsonError(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
 		jsonResult(w, res)
 	}
 }
 
-// sessionHandler returns the list of charging sessions
+// statisticsHandler returns the charged energy, cost and co2 statistics
+func statisticsHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, site.GetStatistics())
+	}
+}
+
+// sessionHandler returns the list of charging sessions, optionally filtered by loadpoint, vehicle, year and month
 func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	if dbserver.Instance == nil {
-		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
 		return
 	}
 
+	q := r.URL.Query()
+	txn := dbserver.Instance.Where("charged_kwh>=0.05")
+
+	// optional filters for expense reporting
+	if loadpoint := q.Get("loadpoint"); loadpoint != "" {
+		txn = txn.Where("loadpoint = ?", loadpoint)
+	}
+	if vehicle := q.Get("vehicle"); vehicle != "" {
+		txn = txn.Where("vehicle = ?", vehicle)
+	}
+	txn = sessionPeriod(txn, q)
+
 	var res db.Sessions
-	if txn := dbserver.Instance.Where("charged_kwh>=0.05").Order("created desc").Find(&res); txn.Error != nil {
-		jsonError(w, http.StatusInternalServerError, txn.Error)
+	if txn := txn.Order("created desc").Find(&res); txn.Error != nil {
+		jsonError(w, r, http.StatusInternalServerError, txn.Error)
 		return
 	}
 
 	if r.URL.Query().Get("format") == "csv" {
-		// get request language
-		lang := r.Header.Get("Accept-Language")
-		if tags, _, err := language.ParseAcceptLanguage(lang); err == nil && len(tags) > 0 {
-			lang = tags[0].String()
-		}
-
-		ctx := context.WithValue(context.Background(), locale.Locale, lang)
+		ctx := locale.WithLanguage(context.Background(), requestLanguage(r))
 		csvResult(ctx, w, &res)
 		return
 	}
@@ -210,6 +362,127 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	jsonResult(w, res)
 }
 
+// sessionPeriod filters the sessions by the optional year and month query parameters
+func sessionPeriod(txn *gorm.DB, q url.Values) *gorm.DB {
+	year, err := strconv.Atoi(q.Get("year"))
+	if err != nil {
+		return txn
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(1, 0, 0)
+
+	if month, err := strconv.Atoi(q.Get("month")); err == nil && month >= 1 && month <= 12 {
+		from = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		to = from.AddDate(0, 1, 0)
+	}
+
+	return txn.Where("created >= ? AND created < ?", from, to)
+}
+
+// sessionTraceHandler returns the decision log of a session
+func sessionTraceHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
+		return
+	}
+
+	var res db.Decisions
+	if txn := dbserver.Instance.Where("trace_id = ?", mux.Vars(r)["trace"]).Order("id").Find(&res); txn.Error != nil {
+		jsonError(w, r, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	if len(res) == 0 {
+		jsonError(w, r, http.StatusNotFound, errors.New("trace not found"))
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// sessionImportHandler imports charging sessions from csv request body or multipart file upload
+func sessionImportHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
+		return
+	}
+
+	body := io.Reader(r.Body)
+	if file, _, err := r.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+	}
+
+	q := r.URL.Query()
+	sessions, err := db.ReadCsv(body, db.ImportOptions{
+		Format:    q.Get("format"),
+		Loadpoint: q.Get("loadpoint"),
+		Vehicle:   q.Get("vehicle"),
+	})
+	if err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	imported, skipped, err := db.Import(sessions)
+	if err != nil {
+		jsonError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonResult(w, map[string]int{"imported": imported, "skipped": skipped})
+}
+
+// vehiclePushHandler receives soc updates for push vehicles from query, form or json body.
+// Torque requests are acknowledged with the plain text response expected by the app.
+func vehiclePushHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	v, err := vehicle.Pushed(vars["id"])
+	if err != nil {
+		jsonError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	values := make(map[string]string)
+
+	if r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var res map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		for k, v := range res {
+			values[k] = fmt.Sprintf("%v", v)
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		for k := range r.Form {
+			values[k] = r.Form.Get(k)
+		}
+	}
+
+	if err := v.Update(values); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// torque
+	if _, ok := values["eml"]; ok {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("OK!"))
+		return
+	}
+
+	jsonResult(w, true)
+}
+
 // chargeModeHandler updates charge mode
 func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -217,7 +490,7 @@ func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 
 		mode, err := api.ChargeModeString(vars["value"])
 		if err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
@@ -238,7 +511,7 @@ func phasesHandler(lp loadpoint.API) http.HandlerFunc {
 		}
 
 		if err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
@@ -254,7 +527,7 @@ func remoteDemandHandler(lp loadpoint.API) http.HandlerFunc {
 		source := vars["source"]
 		demand, err := loadpoint.RemoteDemandString(vars["demand"])
 		if err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
@@ -272,6 +545,54 @@ func remoteDemandHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// remoteBudgetHandler sets external power budget
+func remoteBudgetHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		source := vars["source"]
+		power, err := strconv.ParseFloat(vars["power"], 64)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		lp.SetRemoteBudget(source, power)
+
+		res := struct {
+			Power  float64 `json:"power"`
+			Source string  `json:"source"`
+		}{
+			Power:  lp.GetRemoteBudget(),
+			Source: source,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// remoteBudgetRemoveHandler removes the external power budget of the source, budgets of other sources remain
+func remoteBudgetRemoveHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		lp.SetRemoteBudget(vars["source"], -1)
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
+// remoteHeartbeatHandler signals external controller is alive
+func remoteHeartbeatHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		lp.RemoteHeartbeat(vars["source"])
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
 // targetChargeHandler updates target soc
 func targetChargeHandler(loadpoint targetCharger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -289,7 +610,7 @@ func targetChargeHandler(loadpoint targetCharger) http.HandlerFunc {
 		timeV, err := time.Parse(time.RFC3339, timeS)
 
 		if !ok || err != nil {
-			jsonError(w, http.StatusBadRequest, err)
+			jsonError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
@@ -316,6 +637,245 @@ func targetChargeRemoveHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// planHandler returns the target charging plan
+func planHandler(loadpoint loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, loadpoint.GetPlan())
+	}
+}
+
+// schedulesHandler returns the recurring weekly target charges
+func schedulesHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := lp.GetSchedules()
+		if res == nil {
+			res = []loadpoint.Schedule{}
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// schedulesUpdateHandler replaces the recurring weekly target charges
+func schedulesUpdateHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var res []loadpoint.Schedule
+		if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := lp.SetSchedules(res); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		schedulesHandler(lp)(w, r)
+	}
+}
+
+// chargerCommandsHandler returns the charger's maintenance commands
+func chargerCommandsHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := lp.GetChargerCommands()
+		if res == nil {
+			res = []api.Command{}
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// chargerCommandHandler executes a charger maintenance command
+func chargerCommandHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		cmd := api.Command(vars["command"])
+		if err := lp.ChargerCommand(cmd); err != nil {
+			status := http.StatusBadRequest
+			if !errors.Is(err, api.ErrNotAvailable) {
+				status = http.StatusInternalServerError
+			}
+
+			jsonError(w, r, status, err)
+			return
+		}
+
+		jsonResult(w, cmd)
+	}
+}
+
+// chargerDiagnosticsHandler returns the charger's diagnostic data
+func chargerDiagnosticsHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := lp.GetChargerDiagnostics()
+		if err != nil {
+			status := http.StatusBadRequest
+			if !errors.Is(err, api.ErrNotAvailable) {
+				status = http.StatusInternalServerError
+			}
+
+			jsonError(w, r, status, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// chargerSelfTestHandler returns the last charger self-test report
+func chargerSelfTestHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, running := lp.GetChargerSelfTest()
+
+		res := struct {
+			Running bool             `json:"running"`
+			Passed  bool             `json:"passed"`
+			Report  *selftest.Report `json:"report"`
+		}{
+			Running: running,
+			Report:  report,
+		}
+
+		if report != nil {
+			res.Passed = report.Passed()
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// chargerSelfTestStartHandler starts the charger self-test
+func chargerSelfTestStartHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := lp.StartChargerSelfTest(); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		chargerSelfTestHandler(lp)(w, r)
+	}
+}
+
+// batteryMode is the resolved and external battery mode
+type batteryMode struct {
+	Mode     api.BatteryMode `json:"mode"`
+	External api.BatteryMode `json:"external,omitempty"`
+	Expiry   time.Time       `json:"expiry,omitempty"`
+}
+
+// batteryModeHandler returns (GET), sets (POST) or removes (DELETE) the external battery mode
+func batteryModeHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			mode   api.BatteryMode
+			expiry time.Time
+			err    error
+		)
+
+		if r.Method == http.MethodPost {
+			vars := mux.Vars(r)
+
+			if mode, err = api.BatteryModeString(vars["mode"]); err != nil {
+				jsonError(w, r, http.StatusBadRequest, err)
+				return
+			}
+
+			if expiry, err = time.Parse(time.RFC3339, vars["expiry"]); err != nil {
+				jsonError(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		if r.Method != http.MethodGet {
+			if err := site.SetExternalBatteryMode(mode, expiry); err != nil {
+				jsonError(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		res := batteryMode{Mode: site.GetBatteryMode()}
+		res.External, res.Expiry = site.GetExternalBatteryMode()
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleClimateHandler starts (POST) or stops (DELETE) vehicle climatisation
+func vehicleClimateHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		vehicles := site.GetVehicles()
+		idx := slices.IndexFunc(vehicles, func(v api.Vehicle) bool {
+			return strings.EqualFold(v.Title(), name)
+		})
+		if idx < 0 {
+			jsonError(w, r, http.StatusNotFound, fmt.Errorf("vehicle not found: %s", name))
+			return
+		}
+
+		cc, ok := vehicles[idx].(api.VehicleClimateController)
+		if !ok {
+			jsonError(w, r, http.StatusBadRequest, api.ErrNotAvailable)
+			return
+		}
+
+		start := r.Method != http.MethodDelete
+
+		fun := cc.StopClimate
+		if start {
+			fun = cc.StartClimate
+		}
+
+		if err := fun(); err != nil {
+			jsonError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := struct {
+			Climatise bool `json:"climatise"`
+		}{
+			Climatise: start,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleWakeUpHandler wakes up the vehicle and refreshes its soc asynchronously
+func vehicleWakeUpHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		vehicles := site.GetVehicles()
+		idx := slices.IndexFunc(vehicles, func(v api.Vehicle) bool {
+			return strings.EqualFold(v.Title(), name)
+		})
+		if idx < 0 {
+			jsonError(w, r, http.StatusNotFound, fmt.Errorf("vehicle not found: %s", name))
+			return
+		}
+
+		if err := site.WakeUpVehicle(vehicles[idx]); err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, api.ErrNotAvailable):
+				status = http.StatusBadRequest
+			case errors.Is(err, api.ErrMustRetry):
+				status = http.StatusTooManyRequests
+			}
+
+			jsonError(w, r, status, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		jsonResult(w, true)
+	}
+}
+
 // vehicleHandler sets active vehicle
 func vehicleHandler(site site.API, loadpoint loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -326,7 +886,7 @@ func vehicleHandler(site site.API, loadpoint loadpoint.API) http.HandlerFunc {
 
 		vehicles := site.GetVehicles()
 		if !ok || val >= len(vehicles) || err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			jsonError(w, r, http.StatusBadRequest, errors.New("invalid vehicle"))
 			return
 		}
 
@@ -360,6 +920,238 @@ func vehicleDetectHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// guestSessionStartHandler starts a guest session
+func guestSessionStartHandler(loadpoint loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Energy  float64 `json:"energy"`
+			Price   float64 `json:"price"`
+			MaxCost float64 `json:"maxCost"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := loadpoint.StartGuestSession(req.Energy, req.Price, req.MaxCost); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, loadpoint.GetGuestSession())
+	}
+}
+
+// guestSessionHandler returns the current or last guest session
+func guestSessionHandler(loadpoint loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := loadpoint.GetGuestSession()
+		if res == nil {
+			jsonError(w, r, http.StatusNotFound, errNoGuestSession)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// guestSessionStopHandler stops the guest session and returns its summary
+func guestSessionStopHandler(loadpoint loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := loadpoint.StopGuestSession()
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// loadpointSettings defines the loadpoint settings updated by settingsHandler
+type loadpointSettings interface {
+	GetMode() api.ChargeMode
+	SetMode(api.ChargeMode)
+	GetTargetEnergy() float64
+	SetTargetEnergy(float64)
+	GetTargetSoC() int
+	SetTargetSoC(int)
+	GetMinSoC() int
+	SetMinSoC(int)
+	GetPhases() int
+	SetPhases(int) error
+	GetMinCurrent() float64
+	SetMinCurrent(float64)
+	GetMaxCurrent() float64
+	SetMaxCurrent(float64)
+}
+
+// settings returns the loadpoint's current settings
+func settings(lp loadpointSettings) map[string]interface{} {
+	return map[string]interface{}{
+		"mode":         lp.GetMode(),
+		"targetEnergy": lp.GetTargetEnergy(),
+		"targetSoC":    lp.GetTargetSoC(),
+		"minSoC":       lp.GetMinSoC(),
+		"phases":       lp.GetPhases(),
+		"minCurrent":   lp.GetMinCurrent(),
+		"maxCurrent":   lp.GetMaxCurrent(),
+	}
+}
+
+// settingsHandler updates multiple loadpoint settings at once. All settings are validated
+// before any is applied, validation errors are returned per field.
+func settingsHandler(lp loadpointSettings) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			mode                   *api.ChargeMode
+			targetEnergy           *float64
+			targetSoC, minSoC      *int
+			phases                 *int
+			minCurrent, maxCurrent *float64
+		)
+
+		errs := make(map[string]string)
+
+		decode := func(key string, raw json.RawMessage, val interface{}) bool {
+			if err := json.Unmarshal(raw, val); err != nil {
+				errs[key] = "invalid value"
+				return false
+			}
+			return true
+		}
+
+		soc := func(key string, raw json.RawMessage) *int {
+			var val int
+			if !decode(key, raw, &val) {
+				return nil
+			}
+			if val < 0 || val > 100 {
+				errs[key] = "must be between 0 and 100"
+				return nil
+			}
+			return &val
+		}
+
+		current := func(key string, raw json.RawMessage) *float64 {
+			var val float64
+			if !decode(key, raw, &val) {
+				return nil
+			}
+			if val <= 0 {
+				errs[key] = "must be positive"
+				return nil
+			}
+			return &val
+		}
+
+		for key, raw := range req {
+			switch key {
+			case "mode":
+				var s string
+				if decode(key, raw, &s) {
+					if m, err := api.ChargeModeString(s); err != nil {
+						errs[key] = err.Error()
+					} else {
+						mode = &m
+					}
+				}
+
+			case "targetEnergy":
+				var val float64
+				if decode(key, raw, &val) {
+					if val < 0 {
+						errs[key] = "must not be negative"
+					} else {
+						targetEnergy = &val
+					}
+				}
+
+			case "targetSoC":
+				targetSoC = soc(key, raw)
+
+			case "minSoC":
+				minSoC = soc(key, raw)
+
+			case "phases":
+				var val int
+				if decode(key, raw, &val) {
+					phases = &val
+				}
+
+			case "minCurrent":
+				minCurrent = current(key, raw)
+
+			case "maxCurrent":
+				maxCurrent = current(key, raw)
+
+			default:
+				errs[key] = "unknown setting"
+			}
+		}
+
+		// validate resulting current range
+		if minCurrent != nil || maxCurrent != nil {
+			min, max := lp.GetMinCurrent(), lp.GetMaxCurrent()
+			if minCurrent != nil {
+				min = *minCurrent
+			}
+			if maxCurrent != nil {
+				max = *maxCurrent
+			}
+
+			if _, ok := errs["minCurrent"]; !ok && min > max {
+				key := "minCurrent"
+				if minCurrent == nil {
+					key = "maxCurrent"
+				}
+				errs[key] = "min current exceeds max current"
+			}
+		}
+
+		// phases are validated by the loadpoint and applied first
+		if len(errs) == 0 && phases != nil {
+			if err := lp.SetPhases(*phases); err != nil {
+				errs["phases"] = err.Error()
+			}
+		}
+
+		if len(errs) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			jsonWrite(w, map[string]interface{}{"error": "invalid settings", "errors": errs})
+			return
+		}
+
+		if mode != nil {
+			lp.SetMode(*mode)
+		}
+		if targetEnergy != nil {
+			lp.SetTargetEnergy(*targetEnergy)
+		}
+		if targetSoC != nil {
+			lp.SetTargetSoC(*targetSoC)
+		}
+		if minSoC != nil {
+			lp.SetMinSoC(*minSoC)
+		}
+		if minCurrent != nil {
+			lp.SetMinCurrent(*minCurrent)
+		}
+		if maxCurrent != nil {
+			lp.SetMaxCurrent(*maxCurrent)
+		}
+
+		jsonResult(w, settings(lp))
+	}
+}
+
 // socketHandler attaches websocket handler to uri
 func socketHandler(hub *SocketHub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -372,3 +1164,38 @@ type targetCharger interface {
 	// SetTargetCharge sets the charge targetSoC
 	SetTargetCharge(time.Time, int)
 }
+
+// configPath is the api path of the configuration export and import, restricted to the control role
+const configPath = "/api/config"
+
+// configExportHandler returns the applied configuration with secrets redacted
+func configExportHandler(export func() map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, export())
+	}
+}
+
+// configImportHandler applies and persists yaml or json configuration
+func configImportHandler(restore func([]byte) (bool, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		restart, err := restore(b)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct {
+			RestartRequired bool `json:"restartRequired"`
+		}{
+			RestartRequired: restart,
+		}
+
+		jsonResult(w, res)
+	}
+}
@@ -1,13 +1,21 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+	dbserver "github.com/evcc-io/evcc/server/db"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockLoadpoint struct {
@@ -71,3 +79,128 @@ func TestTargetChargeHandler(t *testing.T) {
 		}
 	}
 }
+
+type mockSettings struct {
+	mode                   api.ChargeMode
+	targetEnergy           float64
+	targetSoC, minSoC      int
+	phases                 int
+	minCurrent, maxCurrent float64
+}
+
+func (lp *mockSettings) GetMode() api.ChargeMode   { return lp.mode }
+func (lp *mockSettings) SetMode(v api.ChargeMode)  { lp.mode = v }
+func (lp *mockSettings) GetTargetEnergy() float64  { return lp.targetEnergy }
+func (lp *mockSettings) SetTargetEnergy(v float64) { lp.targetEnergy = v }
+func (lp *mockSettings) GetTargetSoC() int         { return lp.targetSoC }
+func (lp *mockSettings) SetTargetSoC(v int)        { lp.targetSoC = v }
+func (lp *mockSettings) GetMinSoC() int            { return lp.minSoC }
+func (lp *mockSettings) SetMinSoC(v int)           { lp.minSoC = v }
+func (lp *mockSettings) GetPhases() int            { return lp.phases }
+func (lp *mockSettings) GetMinCurrent() float64    { return lp.minCurrent }
+func (lp *mockSettings) SetMinCurrent(v float64)   { lp.minCurrent = v }
+func (lp *mockSettings) GetMaxCurrent() float64    { return lp.maxCurrent }
+func (lp *mockSettings) SetMaxCurrent(v float64)   { lp.maxCurrent = v }
+
+func (lp *mockSettings) SetPhases(v int) error {
+	if v != 1 && v != 3 {
+		return fmt.Errorf("invalid number of phases: %d", v)
+	}
+	lp.phases = v
+	return nil
+}
+
+func TestSettingsHandler(t *testing.T) {
+	tc := []struct {
+		body       string
+		statusCode int
+		errors     []string
+		res        mockSettings
+	}{
+		{`{"mode":"pv","targetSoC":80,"minCurrent":8,"phases":1}`, http.StatusOK, nil, mockSettings{api.ModePV, 0, 80, 0, 1, 8, 16}},
+		{`{"maxCurrent":32,"minSoC":20}`, http.StatusOK, nil, mockSettings{api.ModeOff, 0, 0, 20, 3, 6, 32}},
+		{`{"mode":"foo","targetSoC":120,"minCurrent":"8"}`, http.StatusBadRequest, []string{"mode", "targetSoC", "minCurrent"}, mockSettings{}},
+		{`{"mode":"now","maxCurrent":4}`, http.StatusBadRequest, []string{"maxCurrent"}, mockSettings{}},
+		{`{"mode":"now","phases":2}`, http.StatusBadRequest, []string{"phases"}, mockSettings{}},
+		{`{"foo":1}`, http.StatusBadRequest, []string{"foo"}, mockSettings{}},
+		{`[]`, http.StatusBadRequest, nil, mockSettings{}},
+	}
+
+	for _, tc := range tc {
+		initial := mockSettings{mode: api.ModeOff, phases: 3, minCurrent: 6, maxCurrent: 16}
+		lp := initial
+
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tc.body))
+		rr := httptest.NewRecorder()
+		settingsHandler(&lp).ServeHTTP(rr, req)
+
+		require.Equal(t, tc.statusCode, rr.Code, tc.body)
+
+		if tc.statusCode != http.StatusOK {
+			// nothing applied
+			assert.Equal(t, initial, lp, tc.body)
+
+			var res struct {
+				Errors map[string]string
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+			for _, key := range tc.errors {
+				assert.Contains(t, res.Errors, key, tc.body)
+			}
+			assert.Len(t, res.Errors, len(tc.errors), tc.body)
+
+			continue
+		}
+
+		assert.Equal(t, tc.res, lp, tc.body)
+	}
+}
+
+func TestSessionHandlerFilter(t *testing.T) {
+	var err error
+	dbserver.Instance, err = dbserver.New("sqlite", filepath.Join(t.TempDir(), "evcc.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { dbserver.Instance = nil })
+
+	require.NoError(t, dbserver.Instance.AutoMigrate(new(db.Session)))
+
+	for _, s := range []db.Session{
+		{Loadpoint: "garage", Vehicle: "blue", Created: time.Date(2022, 9, 15, 12, 0, 0, 0, time.Local), ChargedEnergy: 1},
+		{Loadpoint: "garage", Vehicle: "red", Created: time.Date(2022, 10, 15, 12, 0, 0, 0, time.Local), ChargedEnergy: 2},
+		{Loadpoint: "carport", Vehicle: "red", Created: time.Date(2021, 10, 15, 12, 0, 0, 0, time.Local), ChargedEnergy: 4},
+		{Loadpoint: "carport", Vehicle: "red", Created: time.Date(2022, 10, 16, 12, 0, 0, 0, time.Local), ChargedEnergy: 0.01},
+	} {
+		s := s
+		require.NoError(t, dbserver.Instance.Create(&s).Error)
+	}
+
+	tc := []struct {
+		query  string
+		energy float64
+	}{
+		{"", 7},
+		{"loadpoint=garage", 3},
+		{"vehicle=red", 6},
+		{"year=2022", 3},
+		{"year=2022&month=10", 2},
+		{"year=2022&vehicle=red&loadpoint=carport", 0},
+	}
+
+	for _, tc := range tc {
+		w := httptest.NewRecorder()
+		sessionHandler(w, httptest.NewRequest(http.MethodGet, "/sessions?"+tc.query, nil))
+		require.Equal(t, http.StatusOK, w.Code, tc.query)
+
+		var res struct {
+			Result db.Sessions
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), tc.query)
+
+		var energy float64
+		for _, s := range res.Result {
+			energy += s.ChargedEnergy
+		}
+		assert.Equal(t, tc.energy, energy, tc.query)
+	}
+}
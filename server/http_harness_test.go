@@ -0,0 +1,306 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/event"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/selftest"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden responses: go test ./server -run TestGolden -update
+var update = flag.Bool("update", false, "update golden files")
+
+// harnessTime is the fixed time used by the fake site and loadpoint
+var harnessTime = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var errHarness = errors.New("harness error")
+
+// fakeSite is a site.API with fixed values, setters store the value
+type fakeSite struct {
+	loadpoints    []loadpoint.API
+	vehicles      []api.Vehicle
+	bufferSoC     float64
+	bufferStart   float64
+	prioritySoC   float64
+	residualPower float64
+	override      bool
+	batteryMode   api.BatteryMode
+	expiry        time.Time
+}
+
+var _ site.API = (*fakeSite)(nil)
+
+func (s *fakeSite) Healthy() bool                   { return true }
+func (s *fakeSite) LoadPoints() []loadpoint.API     { return s.loadpoints }
+func (s *fakeSite) Events() *event.Bus              { return nil }
+func (s *fakeSite) GetBufferSoC() float64           { return s.bufferSoC }
+func (s *fakeSite) GetBatteryMode() api.BatteryMode { return api.BatteryNormal }
+func (s *fakeSite) GetBufferStartSoC() float64      { return s.bufferStart }
+func (s *fakeSite) GetPrioritySoC() float64         { return s.prioritySoC }
+func (s *fakeSite) GetResidualPower() float64       { return s.residualPower }
+func (s *fakeSite) GetVehicles() []api.Vehicle      { return s.vehicles }
+func (s *fakeSite) WakeUpVehicle(api.Vehicle) error { return api.ErrMustRetry }
+
+func (s *fakeSite) SetBufferSoC(v float64) error      { s.bufferSoC = v; return nil }
+func (s *fakeSite) SetBufferStartSoC(v float64) error { s.bufferStart = v; return nil }
+func (s *fakeSite) SetPrioritySoC(v float64) error    { s.prioritySoC = v; return nil }
+func (s *fakeSite) SetResidualPower(v float64) error  { s.residualPower = v; return nil }
+
+func (s *fakeSite) GetExternalBatteryMode() (api.BatteryMode, time.Time) {
+	return s.batteryMode, s.expiry
+}
+
+func (s *fakeSite) SetExternalBatteryMode(mode api.BatteryMode, expiry time.Time) error {
+	s.batteryMode, s.expiry = mode, expiry
+	return nil
+}
+
+func (s *fakeSite) SetDemandResponse(*state.DemandResponseEvent) {}
+func (s *fakeSite) GetDemandResponseOverride() bool              { return s.override }
+func (s *fakeSite) SetDemandResponseOverride(v bool) error       { s.override = v; return nil }
+
+func (s *fakeSite) GetStatistics() state.ChargeStatistics {
+	return state.ChargeStatistics{Since: harnessTime, SolarCharged: 30, GridCharged: 10, Cost: 3, ReferenceCost: 16, Saved: 13}
+}
+
+func (s *fakeSite) GetTariff(name string) api.Tariff {
+	if name == "grid" {
+		return fakeTariff(0.3)
+	}
+	return nil
+}
+
+// fakeTariff is a fixed price tariff
+type fakeTariff float64
+
+func (t fakeTariff) IsCheap() (bool, error)         { return false, nil }
+func (t fakeTariff) CurrentPrice() (float64, error) { return float64(t), nil }
+
+// fakeLoadpoint is a loadpoint.API with fixed values, setters store the value
+type fakeLoadpoint struct {
+	mode         api.ChargeMode
+	targetEnergy float64
+	targetSoC    int
+	minSoC       int
+	phases       int
+	minCurrent   float64
+	maxCurrent   float64
+	vehicleSoC   float64
+	schedules    []loadpoint.Schedule
+	guest        *loadpoint.GuestSession
+	budget       float64
+}
+
+var _ loadpoint.API = (*fakeLoadpoint)(nil)
+
+func newFakeLoadpoint() *fakeLoadpoint {
+	return &fakeLoadpoint{
+		mode:       api.ModePV,
+		targetSoC:  80,
+		phases:     3,
+		minCurrent: 6,
+		maxCurrent: 16,
+	}
+}
+
+func (lp *fakeLoadpoint) Name() string                                 { return "garage" }
+func (lp *fakeLoadpoint) GetStatus() api.ChargeStatus                  { return api.StatusB }
+func (lp *fakeLoadpoint) GetVehicleSoC() float64                       { return lp.vehicleSoC }
+func (lp *fakeLoadpoint) SetVehicleSoC(v float64) error                { lp.vehicleSoC = v; return nil }
+func (lp *fakeLoadpoint) GetMode() api.ChargeMode                      { return lp.mode }
+func (lp *fakeLoadpoint) SetMode(v api.ChargeMode)                     { lp.mode = v }
+func (lp *fakeLoadpoint) GetTargetEnergy() float64                     { return lp.targetEnergy }
+func (lp *fakeLoadpoint) SetTargetEnergy(v float64)                    { lp.targetEnergy = v }
+func (lp *fakeLoadpoint) GetTargetSoC() int                            { return lp.targetSoC }
+func (lp *fakeLoadpoint) SetTargetSoC(v int)                           { lp.targetSoC = v }
+func (lp *fakeLoadpoint) GetMinSoC() int                               { return lp.minSoC }
+func (lp *fakeLoadpoint) SetMinSoC(v int)                              { lp.minSoC = v }
+func (lp *fakeLoadpoint) GetPhases() int                               { return lp.phases }
+func (lp *fakeLoadpoint) SetTargetCharge(time.Time, int)               {}
+func (lp *fakeLoadpoint) GetTargetCharge() (time.Time, int)            { return time.Time{}, 0 }
+func (lp *fakeLoadpoint) GetPlan() planner.Plan                        { return planner.Plan{} }
+func (lp *fakeLoadpoint) GetSchedules() []loadpoint.Schedule           { return lp.schedules }
+func (lp *fakeLoadpoint) RemoteControl(string, loadpoint.RemoteDemand) {}
+func (lp *fakeLoadpoint) SetRemoteBudget(_ string, v float64)          { lp.budget = v }
+func (lp *fakeLoadpoint) GetRemoteBudget() float64                     { return -1 }
+func (lp *fakeLoadpoint) RemoteHeartbeat(string)                       {}
+func (lp *fakeLoadpoint) HasChargeMeter() bool                         { return true }
+func (lp *fakeLoadpoint) GetChargePower() float64                      { return 0 }
+func (lp *fakeLoadpoint) GetMinCurrent() float64                       { return lp.minCurrent }
+func (lp *fakeLoadpoint) SetMinCurrent(v float64)                      { lp.minCurrent = v }
+func (lp *fakeLoadpoint) GetMaxCurrent() float64                       { return lp.maxCurrent }
+func (lp *fakeLoadpoint) SetMaxCurrent(v float64)                      { lp.maxCurrent = v }
+func (lp *fakeLoadpoint) GetMinPower() float64                         { return 1380 }
+func (lp *fakeLoadpoint) GetMaxPower() float64                         { return 11040 }
+func (lp *fakeLoadpoint) GetRemainingDuration() time.Duration          { return -1 }
+func (lp *fakeLoadpoint) GetRemainingEnergy() float64                  { return 0 }
+func (lp *fakeLoadpoint) GetChargerCommands() []api.Command            { return []api.Command{api.CommandReboot} }
+func (lp *fakeLoadpoint) SetVehicle(api.Vehicle)                       {}
+func (lp *fakeLoadpoint) StartVehicleDetection()                       {}
+
+func (lp *fakeLoadpoint) SetPhases(v int) error {
+	if v != 1 && v != 3 {
+		return errHarness
+	}
+	lp.phases = v
+	return nil
+}
+
+func (lp *fakeLoadpoint) SetSchedules(v []loadpoint.Schedule) error {
+	lp.schedules = v
+	return nil
+}
+
+func (lp *fakeLoadpoint) ChargerCommand(cmd api.Command) error {
+	if cmd != api.CommandReboot {
+		return api.ErrNotAvailable
+	}
+	return nil
+}
+
+func (lp *fakeLoadpoint) StartChargerSelfTest() error { return errHarness }
+
+func (lp *fakeLoadpoint) GetChargerSelfTest() (*selftest.Report, bool) { return nil, false }
+
+func (lp *fakeLoadpoint) GetChargerDiagnostics() (api.Diagnostics, error) {
+	return api.Diagnostics{"firmware": "1.2.3", "error": 0}, nil
+}
+
+func (lp *fakeLoadpoint) StartGuestSession(energy, price, maxCost float64) error {
+	lp.guest = &loadpoint.GuestSession{Created: harnessTime, Energy: energy, Price: price, MaxCost: maxCost, Mode: lp.mode}
+	return nil
+}
+
+func (lp *fakeLoadpoint) GetGuestSession() *loadpoint.GuestSession { return lp.guest }
+
+func (lp *fakeLoadpoint) StopGuestSession() (*loadpoint.GuestSession, error) {
+	if lp.guest == nil {
+		return nil, errHarness
+	}
+	lp.guest.Finished = harnessTime.Add(time.Hour)
+	return lp.guest, nil
+}
+
+// fakeDevices is a DeviceProvider without devices
+type fakeDevices struct{}
+
+func (fakeDevices) Device(name string) (interface{}, error) {
+	return nil, errors.New("device not found")
+}
+
+// harness serves all apis of a fake site with a single loadpoint
+type harness struct {
+	t      *testing.T
+	router *mux.Router
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	// localized errors are compared in the default language
+	require.NoError(t, locale.Init())
+
+	site := &fakeSite{
+		loadpoints: []loadpoint.API{newFakeLoadpoint()},
+	}
+
+	cache := util.NewCache()
+
+	httpd := NewHTTPd("", nil)
+	httpd.RegisterSiteHandlers(site, cache)
+	httpd.RegisterDeviceHandlers(fakeDevices{})
+	httpd.RegisterShutdownHandler(func() {})
+	httpd.RegisterHistoryHandler(NewHistory(HistoryConfig{}))
+	httpd.RegisterDiscoveryHandler(NewDiscovery())
+	httpd.RegisterReloadHandler(func() error { return errHarness })
+	httpd.RegisterConfigHandler(
+		func() map[string]interface{} {
+			return map[string]interface{}{"site": map[string]interface{}{"title": "home"}}
+		},
+		func(b []byte) (bool, error) {
+			if !strings.Contains(string(b), "site") {
+				return false, errHarness
+			}
+			return true, nil
+		},
+	)
+	httpd.RegisterDashboardHandler(DashboardConfig{Token: "public"}, cache)
+
+	leader, err := NewClusterLeader(ClusterConfig{Role: "leader", Token: "secret", Budget: 11000}, site)
+	require.NoError(t, err)
+	httpd.RegisterClusterHandler(leader)
+
+	mobile, err := NewMobile(MobileConfig{Relay: "http://localhost"})
+	require.NoError(t, err)
+	httpd.RegisterMobileHandlers(mobile, site, cache)
+
+	sh, err := NewSmartHome(SmartHomeConfig{ClientID: "client", ClientSecret: "secret", RedirectURIs: []string{"https://example.com/link"}})
+	require.NoError(t, err)
+	httpd.RegisterSmartHomeHandlers(sh, site)
+
+	return &harness{t: t, router: httpd.Router()}
+}
+
+// do serves the request and returns its response
+func (h *harness) do(method, path, body string) *http.Response {
+	h.t.Helper()
+
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+
+	req := httptest.NewRequest(method, path, rd)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	h.router.ServeHTTP(w, req)
+
+	return w.Result()
+}
+
+// golden compares the response body with testdata/golden/<name>.json, json is compared indented
+func (h *harness) golden(name string, body []byte) {
+	h.t.Helper()
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err == nil {
+		body = buf.Bytes()
+	}
+	body = append(bytes.TrimSpace(body), '\n')
+
+	file := filepath.Join("testdata", "golden", name+".json")
+
+	if *update {
+		require.NoError(h.t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(h.t, os.WriteFile(file, body, 0o644))
+		return
+	}
+
+	expected, err := os.ReadFile(file)
+	require.NoError(h.t, err, "missing golden file, run with -update")
+	assert.Equal(h.t, string(expected), string(body), name)
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	dbsettings "github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/locale"
+	"golang.org/x/text/language"
+)
+
+// languageSetting is the settings key of the user selected language
+const languageSetting = "language"
+
+// requestLanguage returns the base language of the request.
+// The lang query parameter takes precedence over the user's configured language and the Accept-Language header.
+func requestLanguage(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+
+	if lang, ok := r.Context().Value(locale.Locale).(string); ok && lang != "" {
+		return lang
+	}
+
+	header := r.Header.Get("Accept-Language")
+	if lang := locale.Match(header); lang != "" {
+		return lang
+	}
+
+	if tags, _, err := language.ParseAcceptLanguage(header); err == nil && len(tags) > 0 {
+		base, _ := tags[0].Base()
+		return base.String()
+	}
+
+	return "en"
+}
+
+// RestoreLanguage applies the persisted user selected language
+func RestoreLanguage() error {
+	lang, err := dbsettings.String(languageSetting)
+	if errors.Is(err, dbsettings.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return locale.SetLanguage(lang)
+}
+
+// languageSettings is the system language as exposed to the ui
+type languageSettings struct {
+	Language  string   `json:"language"` // user selected language, empty if detected
+	System    string   `json:"system"`
+	Fallbacks []string `json:"fallbacks"`
+	Available []string `json:"available"`
+}
+
+func currentLanguage() languageSettings {
+	system := locale.Language()
+
+	return languageSettings{
+		Language:  locale.UserLanguage(),
+		System:    system,
+		Fallbacks: locale.Fallbacks(system),
+		Available: locale.Languages(),
+	}
+}
+
+// languageHandler returns and updates the user selected language, an empty language restores detection
+func languageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		var req struct {
+			Language string `json:"language"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := locale.SetLanguage(req.Language); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		dbsettings.SetString(languageSetting, locale.UserLanguage())
+	}
+
+	jsonResult(w, currentLanguage())
+}
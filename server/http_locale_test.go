@@ -0,0 +1,36 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLanguage(t *testing.T) {
+	require.NoError(t, locale.Init())
+
+	req := func(uri, header, user string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, uri, nil)
+		r.Header.Set("Accept-Language", header)
+		if user != "" {
+			r = r.WithContext(locale.WithLanguage(r.Context(), user))
+		}
+		return r
+	}
+
+	assert.Equal(t, "de", requestLanguage(req("/api/state", "de-DE,de;q=0.9,en;q=0.8", "")))
+	assert.Equal(t, "nl", requestLanguage(req("/api/state", "de-DE", "nl")))
+	assert.Equal(t, "it", requestLanguage(req("/api/state?lang=it", "de-DE", "nl")))
+
+	w := httptest.NewRecorder()
+	jsonError(w, req("/api/state", "de", ""), http.StatusUnauthorized, ErrUnauthorized)
+
+	var res struct{ Error string }
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	assert.Equal(t, "Nicht angemeldet", res.Error)
+}
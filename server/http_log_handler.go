@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/mux"
+)
+
+// logLevelsHandler returns the log levels of all areas
+func logLevelsHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResult(w, util.LogLevels())
+}
+
+// logLevelHandler changes the log level of an area at runtime
+func logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Level string `json:"level"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	area := mux.Vars(r)["area"]
+	if err := util.SetLogLevel(area, req.Level); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonResult(w, map[string]string{strings.ToLower(area): strings.ToLower(req.Level)})
+}
+
+// logFilter matches log entries by area and level
+type logFilter struct {
+	areas  []string
+	levels []string
+}
+
+func (f logFilter) match(e util.LogEntry) bool {
+	return (len(f.areas) == 0 || containsFold(f.areas, e.Area)) && (len(f.levels) == 0 || containsFold(f.levels, e.Level))
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// logsHandler returns the recent log entries, e.g. ?area=lp-1&level=warn&level=error&since=42.
+// With ?follow=true new entries are streamed as newline-delimited json until the connection closes,
+// clients resume with the last received sequence number after the server's write timeout.
+func logsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var since uint64
+	if s := q.Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	filter := logFilter{areas: q["area"], levels: q["level"]}
+
+	if follow, _ := strconv.ParseBool(q.Get("follow")); !follow {
+		res := []util.LogEntry{}
+		for _, e := range util.LogEntries(since) {
+			if filter.match(e) {
+				res = append(res, e)
+			}
+		}
+
+		jsonResult(w, res)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		jsonError(w, r, http.StatusInternalServerError, errStreamingUnsupported)
+		return
+	}
+
+	// subscribe before reading the buffer to avoid gaps
+	entries, cancel := util.SubscribeLogs()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	send := func(e util.LogEntry) bool {
+		if e.Seq <= since || !filter.match(e) {
+			return true
+		}
+		since = e.Seq
+		return enc.Encode(e) == nil
+	}
+
+	for _, e := range util.LogEntries(since) {
+		if !send(e) {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case e := <-entries:
+			if !send(e) {
+				return
+			}
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
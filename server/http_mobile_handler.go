@@ -0,0 +1,166 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/mux"
+)
+
+// IdempotencyKeyHeader identifies repeated mobile commands, e.g. retries after connection loss
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+var (
+	mobileSiteKeys = []string{
+		state.SiteTitle, state.Currency, state.PvPower, state.GridPower, state.HomePower, state.BatteryPower, state.BatterySoC,
+	}
+	mobileLoadpointKeys = []string{
+		state.Title, state.Mode, state.Connected, state.Charging, state.Enabled, state.ChargePower, state.ChargedEnergy, state.ChargeRemainingDuration,
+		state.VehicleTitle, state.VehicleSoC, state.VehicleRange, state.MinSoC, state.TargetSoC, state.PhasesActive, state.Fault, state.Authorized,
+	}
+)
+
+// pick returns the given keys of the state
+func pick(values map[string]interface{}, keys []string) map[string]interface{} {
+	res := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// mobileStateHandler returns a compact state for bandwidth constrained clients
+func mobileStateHandler(cache *util.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		values := cache.State()
+
+		lps, _ := values["loadpoints"].([]map[string]interface{})
+		loadpoints := make([]map[string]interface{}, 0, len(lps))
+		for _, lp := range lps {
+			loadpoints = append(loadpoints, pick(lp, mobileLoadpointKeys))
+		}
+
+		jsonResult(w, map[string]interface{}{
+			"site":       pick(values, mobileSiteKeys),
+			"loadpoints": loadpoints,
+		})
+	}
+}
+
+// mobileDeviceHandler registers a device for push notifications
+func mobileDeviceHandler(m *Mobile) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var dev MobileDevice
+		if err := json.NewDecoder(r.Body).Decode(&dev); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// notifications in the app's language
+		if dev.Lang == "" {
+			dev.Lang = requestLanguage(r)
+		}
+
+		res, err := m.Register(dev)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// mobileDeviceRemoveHandler removes a device registration
+func mobileDeviceRemoveHandler(m *Mobile) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		if err := m.Unregister(vars["id"]); err != nil {
+			jsonError(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// mobileCommandHandler applies a setting to the site or a 1-based loadpoint.
+// Requests with idempotency key are executed once, repeated requests wait for and return the original result.
+// Reusing the key for a different payload is rejected.
+func mobileCommandHandler(m *Mobile, site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			writeMobileResult(w, r, executeMobileCommand(site, b))
+			return
+		}
+
+		sum := sha256.Sum256(b)
+		hash := hex.EncodeToString(sum[:])
+
+		cmd, reserved := m.reserve(key, hash)
+		if cmd.hash != hash {
+			jsonError(w, r, http.StatusUnprocessableEntity, errors.New("idempotency key used for different request"))
+			return
+		}
+
+		if reserved {
+			m.complete(cmd, executeMobileCommand(site, b))
+		}
+
+		select {
+		case <-cmd.done:
+			writeMobileResult(w, r, cmd.result)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+// executeMobileCommand decodes and applies the command
+func executeMobileCommand(site site.API, b []byte) mobileResult {
+	var req struct {
+		Loadpoint int    `json:"loadpoint"`
+		Key       string `json:"key"`
+		Value     string `json:"value"`
+	}
+
+	if err := json.Unmarshal(b, &req); err != nil {
+		return mobileResult{status: http.StatusBadRequest, err: err.Error()}
+	}
+
+	if req.Key == "" {
+		return mobileResult{status: http.StatusBadRequest, err: "missing key"}
+	}
+
+	val, err := applySetting(site, "mobile", req.Loadpoint, req.Key, req.Value)
+	if err != nil {
+		return mobileResult{status: http.StatusBadRequest, err: err.Error()}
+	}
+
+	return mobileResult{status: http.StatusOK, value: val}
+}
+
+func writeMobileResult(w http.ResponseWriter, r *http.Request, res mobileResult) {
+	if res.err != "" {
+		jsonError(w, r, res.status, errors.New(res.err))
+		return
+	}
+
+	jsonResult(w, res.value)
+}
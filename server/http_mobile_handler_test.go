@@ -0,0 +1,134 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMobileCommandIdempotency(t *testing.T) {
+	m, err := NewMobile(MobileConfig{Relay: "http://localhost"})
+	require.NoError(t, err)
+
+	site := &grpcSite{}
+	handler := mobileCommandHandler(m, site)
+
+	command := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/mobile/command", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		return w
+	}
+
+	w := command("1", `{"key":"bufferSoC","value":"80"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":80}`, w.Body.String())
+
+	// repeated request is not applied again
+	site.bufferSoC = 50
+	w = command("1", `{"key":"bufferSoC","value":"80"}`)
+	assert.JSONEq(t, `{"result":80}`, w.Body.String())
+	assert.Equal(t, 50.0, site.bufferSoC)
+
+	// errors are reproduced as well
+	w = command("2", `{"key":"foo","value":"1"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = command("2", `{"key":"foo","value":"1"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// key is bound to the payload
+	w = command("2", `{"key":"bufferSoC","value":"1"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 50.0, site.bufferSoC)
+
+	// requests without key are always applied
+	command("", `{"key":"bufferSoC","value":"70"}`)
+	assert.Equal(t, 70.0, site.bufferSoC)
+}
+
+func TestMobileRegister(t *testing.T) {
+	m, err := NewMobile(MobileConfig{Relay: "http://localhost"})
+	require.NoError(t, err)
+
+	_, err = m.Register(MobileDevice{Platform: "foo", Token: "bar"})
+	assert.Error(t, err)
+
+	dev, err := m.Register(MobileDevice{Platform: "fcm", Token: "bar"})
+	require.NoError(t, err)
+
+	// re-registration updates device
+	dev2, err := m.Register(MobileDevice{Platform: "fcm", Token: "bar", Name: "phone"})
+	require.NoError(t, err)
+	assert.Equal(t, dev.ID, dev2.ID)
+	assert.Len(t, m.devices, 1)
+
+	assert.NoError(t, m.Unregister(dev.ID))
+	assert.Error(t, m.Unregister(dev.ID))
+}
+
+type blockingSite struct {
+	grpcSite
+	calls   int32
+	release chan struct{}
+}
+
+func (s *blockingSite) SetBufferSoC(soc float64) error {
+	atomic.AddInt32(&s.calls, 1)
+	<-s.release
+	return s.grpcSite.SetBufferSoC(soc)
+}
+
+func TestMobileCommandInFlight(t *testing.T) {
+	m, err := NewMobile(MobileConfig{Relay: "http://localhost"})
+	require.NoError(t, err)
+
+	site := &blockingSite{release: make(chan struct{})}
+	handler := mobileCommandHandler(m, site)
+
+	command := func() <-chan *httptest.ResponseRecorder {
+		res := make(chan *httptest.ResponseRecorder, 1)
+
+		go func() {
+			req := httptest.NewRequest(http.MethodPost, "/api/mobile/command", strings.NewReader(`{"key":"bufferSoC","value":"80"}`))
+			req.Header.Set(IdempotencyKeyHeader, "1")
+
+			w := httptest.NewRecorder()
+			handler(w, req)
+			res <- w
+		}()
+
+		return res
+	}
+
+	first := command()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&site.calls) == 1 }, time.Second, time.Millisecond)
+
+	// duplicate waits for the command in flight
+	second := command()
+	select {
+	case <-second:
+		t.Fatal("duplicate not waiting for command in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(site.release)
+
+	for _, res := range []<-chan *httptest.ResponseRecorder{first, second} {
+		w := <-res
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"result":80}`, w.Body.String())
+	}
+
+	assert.Equal(t, int32(1), site.calls)
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// smartHomePath is the smart home api, the only api accessible to linked accounts
+	smartHomePath = "/api/smarthome"
+
+	// smartHomeTokenPath is the oauth token endpoint, authenticated by client credentials instead of api auth
+	smartHomeTokenPath = smartHomePath + "/oauth/token"
+)
+
+// smartHomeAuthorizeHandler links the account of a user with control role and redirects to the skill with authorization code
+func smartHomeAuthorizeHandler(sh *SmartHome) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestRole(r) != RoleControl {
+			jsonError(w, r, http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		q := r.URL.Query()
+
+		if q.Get("response_type") != "code" {
+			jsonError(w, r, http.StatusBadRequest, errors.New("unsupported response type"))
+			return
+		}
+
+		redirectURI := q.Get("redirect_uri")
+
+		code, err := sh.Authorize(q.Get("client_id"), redirectURI)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u, err := url.Parse(redirectURI)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		res := u.Query()
+		res.Set("code", code)
+		res.Set("state", q.Get("state"))
+		u.RawQuery = res.Encode()
+
+		http.Redirect(w, r, u.String(), http.StatusFound)
+	}
+}
+
+// smartHomeTokenHandler exchanges authorization codes and refresh tokens for access tokens
+func smartHomeTokenHandler(sh *SmartHome) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+
+		grantType := r.PostForm.Get("grant_type")
+
+		value := r.PostForm.Get("code")
+		if grantType == "refresh_token" {
+			value = r.PostForm.Get("refresh_token")
+		}
+
+		res, err := sh.Exchange(clientID, clientSecret, grantType, value)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrUnauthorized) {
+				status = http.StatusUnauthorized
+			}
+
+			jsonError(w, r, status, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		jsonWrite(w, res)
+	}
+}
+
+// smartHomeDevicesHandler returns the loadpoints as smart home devices
+func smartHomeDevicesHandler(sh *SmartHome, site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, sh.Devices(site.LoadPoints()))
+	}
+}
+
+// smartHomeCommandHandler switches a device on or off or sets its mode
+func smartHomeCommandHandler(sh *SmartHome, site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			On   *bool  `json:"on"`
+			Mode string `json:"mode"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := sh.Command(site.LoadPoints(), mux.Vars(r)["id"], req.On, req.Mode)
+		if err != nil {
+			jsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/evcc-io/evcc/core/db"
+	dbserver "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/gorilla/mux"
+)
+
+// userID returns the user id of the request path or 0 if not set
+func userID(r *http.Request) uint {
+	id, _ := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	return uint(id)
+}
+
+// userError writes the user api error
+func userError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, db.ErrUserNotFound) {
+		jsonError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	jsonError(w, r, http.StatusInternalServerError, err)
+}
+
+// usersHandler returns the users
+func usersHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
+		return
+	}
+
+	res, err := db.Users()
+	if err != nil {
+		jsonError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if res == nil {
+		res = []db.User{}
+	}
+
+	jsonResult(w, res)
+}
+
+// userSaveHandler creates or, given the path id, updates the user
+func userSaveHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
+		return
+	}
+
+	var u db.User
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	u.ID = 0
+	if id := userID(r); id > 0 {
+		if _, err := db.GetUser(id); err != nil {
+			userError(w, r, err)
+			return
+		}
+
+		u.ID = id
+	}
+
+	if err := db.SaveUser(&u); err != nil {
+		jsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonResult(w, u)
+}
+
+// userDeleteHandler deletes the user
+func userDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
+		return
+	}
+
+	if err := db.DeleteUser(userID(r)); err != nil {
+		userError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userSessionsHandler returns the user's energy and cost report, optionally filtered by year and month
+func userSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	if dbserver.Instance == nil {
+		jsonError(w, r, http.StatusBadRequest, errDatabaseOffline)
+		return
+	}
+
+	u, err := db.GetUser(userID(r))
+	if err != nil {
+		userError(w, r, err)
+		return
+	}
+
+	q := r.URL.Query()
+	txn := sessionPeriod(db.UserSessions(u).Where("charged_kwh>=0.05"), q)
+
+	var res db.Sessions
+	if txn := txn.Order("created desc").Find(&res); txn.Error != nil {
+		jsonError(w, r, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	if q.Get("format") == "csv" {
+		ctx := locale.WithLanguage(context.Background(), requestLanguage(r))
+		csvResult(ctx, w, &res)
+		return
+	}
+
+	jsonResult(w, db.NewUserReport(u, res))
+}
@@ -2,55 +2,90 @@ package server
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/util"
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	influxlog "github.com/influxdata/influxdb-client-go/v2/log"
 )
 
 // InfluxConfig is the influx db configuration
 type InfluxConfig struct {
-	URL      string
-	Database string
-	Token    string
-	Org      string
-	User     string
-	Password string
-	Interval time.Duration
+	URL           string
+	Database      string
+	Bucket        string // InfluxDB 2.x bucket, replaces database
+	Token         string
+	Org           string
+	User          string
+	Password      string
+	Interval      time.Duration
+	BatchSize     uint                     // points per write
+	FlushInterval time.Duration            // max delay before writing incomplete batches
+	Measurements  map[string]InfluxMapping // schema per published key
+}
+
+// InfluxMapping defines measurement, field and tags a published key is written to
+type InfluxMapping struct {
+	Measurement string            // defaults to key
+	Field       string            // defaults to value
+	Tags        map[string]string // static tags added to the point
 }
 
 // Influx is a influx publisher
 type Influx struct {
 	sync.Mutex
-	log      *util.Logger
-	client   influxdb2.Client
-	org      string
-	database string
+	log          *util.Logger
+	client       influxdb2.Client
+	org          string
+	bucket       string
+	measurements map[string]InfluxMapping
 }
 
 // NewInfluxClient creates new publisher for influx
-func NewInfluxClient(url, token, org, user, password, database string) *Influx {
+func NewInfluxClient(conf InfluxConfig) *Influx {
 	log := util.NewLogger("influx")
 
 	// InfluxDB v1 compatibility
-	if token == "" && user != "" {
-		token = fmt.Sprintf("%s:%s", user, password)
+	token := conf.Token
+	if token == "" && conf.User != "" {
+		token = fmt.Sprintf("%s:%s", conf.User, conf.Password)
+	}
+
+	bucket := conf.Bucket
+	if bucket == "" {
+		bucket = conf.Database
 	}
 
 	options := influxdb2.DefaultOptions().SetPrecision(time.Second)
-	client := influxdb2.NewClientWithOptions(url, token, options)
+	if conf.BatchSize > 0 {
+		options.SetBatchSize(conf.BatchSize)
+	}
+	if conf.FlushInterval > 0 {
+		options.SetFlushInterval(uint(conf.FlushInterval.Milliseconds()))
+	}
+
+	client := influxdb2.NewClientWithOptions(conf.URL, token, options)
 
 	// handle error logging in writer
 	influxlog.Log = nil
 
+	// config keys are case-insensitive
+	measurements := make(map[string]InfluxMapping, len(conf.Measurements))
+	for key, m := range conf.Measurements {
+		measurements[strings.ToLower(key)] = m
+	}
+
 	return &Influx{
-		log:      log,
-		client:   client,
-		org:      org,
-		database: database,
+		log:          log,
+		client:       client,
+		org:          conf.Org,
+		bucket:       bucket,
+		measurements: measurements,
 	}
 }
 
@@ -74,7 +109,7 @@ func (m *Influx) supportedType(p util.Param) bool {
 
 // Run Influx publisher
 func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
-	writer := m.client.WriteAPI(m.org, m.database)
+	writer := m.client.WriteAPI(m.org, m.bucket)
 
 	// log errors
 	go func() {
@@ -91,7 +126,7 @@ func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
 	for param := range in {
 		// vehicle name
 		if param.LoadPoint != nil {
-			if name, ok := param.Val.(string); ok && param.Key == "vehicleTitle" {
+			if name, ok := param.Val.(string); ok && param.Key == state.VehicleTitle {
 				vehicles[*param.LoadPoint] = name
 				continue
 			}
@@ -107,33 +142,52 @@ func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
 			tags["vehicle"] = vehicles[*param.LoadPoint]
 		}
 
-		fields := map[string]interface{}{}
+		// write asynchronously
+		m.log.TRACE.Printf("write %s=%v (%v)", param.Key, param.Val, tags)
+		writer.WritePoint(m.point(param, tags, time.Now()))
+	}
 
-		// array to slice
-		val := param.Val
-		if v, ok := val.([3]float64); ok {
-			val = v[:]
-		}
+	m.client.Close()
+}
 
-		// add slice as phase values
-		if phases, ok := val.([]float64); ok {
-			var total float64
-			for i, v := range phases {
-				total += v
-				fields[fmt.Sprintf("l%d", i+1)] = v
-			}
+// point converts the param to a point according to the configured measurement mapping
+func (m *Influx) point(param util.Param, tags map[string]string, ts time.Time) *write.Point {
+	measurement := param.Key
+	field := "value"
 
-			// add total as "value"
-			val = total
+	if mapping, ok := m.measurements[strings.ToLower(param.Key)]; ok {
+		if mapping.Measurement != "" {
+			measurement = mapping.Measurement
+		}
+		if mapping.Field != "" {
+			field = mapping.Field
+		}
+		for k, v := range mapping.Tags {
+			tags[k] = v
 		}
+	}
 
-		fields["value"] = val
+	fields := map[string]interface{}{}
 
-		// write asynchronously
-		m.log.TRACE.Printf("write %s=%v (%v)", param.Key, param.Val, tags)
-		p := influxdb2.NewPoint(param.Key, tags, fields, time.Now())
-		writer.WritePoint(p)
+	// array to slice
+	val := param.Val
+	if v, ok := val.([3]float64); ok {
+		val = v[:]
 	}
 
-	m.client.Close()
+	// add slice as phase values
+	if phases, ok := val.([]float64); ok {
+		var total float64
+		for i, v := range phases {
+			total += v
+			fields[fmt.Sprintf("l%d", i+1)] = v
+		}
+
+		// add total as value
+		val = total
+	}
+
+	fields[field] = val
+
+	return influxdb2.NewPoint(measurement, tags, fields, ts)
 }
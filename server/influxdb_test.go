@@ -0,0 +1,38 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfluxPoint(t *testing.T) {
+	m := NewInfluxClient(InfluxConfig{
+		URL: "http://localhost:8086",
+		Measurements: map[string]InfluxMapping{
+			// keys are lowercased by config decoding
+			"chargepower": {Measurement: "power", Field: "charge", Tags: map[string]string{"source": "wallbox"}},
+		},
+	})
+	defer m.client.Close()
+
+	ts := time.Unix(1672531200, 0)
+
+	tc := []struct {
+		param util.Param
+		tags  map[string]string
+		res   string
+	}{
+		{util.Param{Key: "pvPower", Val: 1000.0}, map[string]string{"site": "home"}, "pvPower,site=home value=1000 1672531200"},
+		{util.Param{Key: "chargePower", Val: 4200.0}, map[string]string{"loadpoint": "garage"}, "power,loadpoint=garage,source=wallbox charge=4200 1672531200"},
+		{util.Param{Key: "gridCurrents", Val: []float64{1, 2, 3}}, map[string]string{"site": "home"}, "gridCurrents,site=home l1=1,l2=2,l3=3,value=6 1672531200"},
+	}
+
+	for _, tc := range tc {
+		p := m.point(tc.param, tc.tags, ts)
+		assert.Equal(t, tc.res+"\n", write.PointToLineProtocol(p, time.Second), tc.param.Key)
+	}
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	dbsettings "github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	mobileDevicesSetting = "mobile.devices"
+	mobileIdempotencyTTL = 24 * time.Hour // retention of command results for repeated requests
+)
+
+// MobileConfig is the mobile app api configuration
+type MobileConfig struct {
+	Relay string // push relay forwarding notifications to FCM/APNs
+	Token string // push relay authorization
+}
+
+// MobileDevice is a mobile app registered for push notifications
+type MobileDevice struct {
+	ID       string    `json:"id"`
+	Platform string    `json:"platform"` // fcm or apns
+	Token    string    `json:"token"`
+	Name     string    `json:"name,omitempty"`
+	Lang     string    `json:"lang,omitempty"` // notification language
+	Created  time.Time `json:"created"`
+}
+
+// mobileResult is the response of a command
+type mobileResult struct {
+	status int
+	value  interface{}
+	err    string
+}
+
+// mobileCommand is an idempotent command reserved by its key. The result is available once done is closed.
+type mobileCommand struct {
+	created time.Time
+	hash    string // request payload
+	done    chan struct{}
+	result  mobileResult
+}
+
+// Mobile manages mobile app push registrations and delivers notifications through the relay
+type Mobile struct {
+	mu sync.Mutex
+	*request.Helper
+	log     *util.Logger
+	relay   string
+	token   string
+	devices []MobileDevice
+	results map[string]*mobileCommand
+}
+
+// NewMobile creates the mobile app api backend
+func NewMobile(conf MobileConfig) (*Mobile, error) {
+	if conf.Relay == "" {
+		return nil, errors.New("missing relay")
+	}
+
+	log := util.NewLogger("mobile").Redact(conf.Token)
+
+	m := &Mobile{
+		Helper:  request.NewHelper(log),
+		log:     log,
+		relay:   conf.Relay,
+		token:   conf.Token,
+		results: make(map[string]*mobileCommand),
+	}
+
+	if err := dbsettings.Json(mobileDevicesSetting, &m.devices); err != nil && !errors.Is(err, dbsettings.ErrNotFound) {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// mobileDeviceID derives a stable id from the push token so repeated registrations update the device
+func mobileDeviceID(platform, token string) string {
+	hash := sha256.Sum256([]byte(platform + ":" + token))
+	return hex.EncodeToString(hash[:8])
+}
+
+// persist stores the registered devices (no mutex)
+func (m *Mobile) persist() {
+	if err := dbsettings.SetJson(mobileDevicesSetting, m.devices); err != nil {
+		m.log.ERROR.Println(err)
+	}
+}
+
+// Register adds or updates a device
+func (m *Mobile) Register(dev MobileDevice) (MobileDevice, error) {
+	if dev.Platform != "fcm" && dev.Platform != "apns" {
+		return dev, errors.New("invalid platform, must be fcm or apns")
+	}
+
+	if dev.Token == "" {
+		return dev, errors.New("missing token")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev.ID = mobileDeviceID(dev.Platform, dev.Token)
+	dev.Created = time.Now()
+
+	if idx := slices.IndexFunc(m.devices, func(d MobileDevice) bool { return d.ID == dev.ID }); idx >= 0 {
+		m.devices[idx] = dev
+	} else {
+		m.devices = append(m.devices, dev)
+	}
+
+	m.persist()
+	m.log.DEBUG.Printf("registered device %s (%s)", dev.ID, dev.Platform)
+
+	return dev, nil
+}
+
+// Unregister removes a device
+func (m *Mobile) Unregister(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := slices.IndexFunc(m.devices, func(d MobileDevice) bool { return d.ID == id })
+	if idx < 0 {
+		return errors.New("device not found")
+	}
+
+	m.devices = slices.Delete(m.devices, idx, idx+1)
+	m.persist()
+
+	return nil
+}
+
+// reserve returns the command for the idempotency key and removes expired results.
+// Unknown keys are reserved for the payload hash, the caller must execute and complete the command.
+func (m *Mobile) reserve(key, hash string) (*mobileCommand, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, cmd := range m.results {
+		select {
+		case <-cmd.done:
+			if time.Since(cmd.created) > mobileIdempotencyTTL {
+				delete(m.results, k)
+			}
+		default:
+		}
+	}
+
+	if cmd, ok := m.results[key]; ok {
+		return cmd, false
+	}
+
+	cmd := &mobileCommand{created: time.Now(), hash: hash, done: make(chan struct{})}
+	m.results[key] = cmd
+
+	return cmd, true
+}
+
+// complete stores the result of the reserved command and releases waiting requests
+func (m *Mobile) complete(cmd *mobileCommand, res mobileResult) {
+	cmd.result = res
+	close(cmd.done)
+}
+
+// Languages implements the push.LanguageSender interface
+func (m *Mobile) Languages() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var res []string
+	for _, d := range m.devices {
+		if !slices.Contains(res, d.Lang) {
+			res = append(res, d.Lang)
+		}
+	}
+
+	return res
+}
+
+// SendLanguage implements the push.LanguageSender interface
+func (m *Mobile) SendLanguage(lang, title, msg string) {
+	m.mu.Lock()
+	var devices []MobileDevice
+	for _, d := range m.devices {
+		if d.Lang == lang {
+			devices = append(devices, d)
+		}
+	}
+	m.mu.Unlock()
+
+	m.send(devices, title, msg)
+}
+
+// Send implements the push.Sender interface
+func (m *Mobile) Send(title, msg string) {
+	m.mu.Lock()
+	devices := slices.Clone(m.devices)
+	m.mu.Unlock()
+
+	m.send(devices, title, msg)
+}
+
+// send delivers the message to the devices through the relay
+func (m *Mobile) send(devices []MobileDevice, title, msg string) {
+	if len(devices) == 0 {
+		return
+	}
+
+	type target struct {
+		Platform string `json:"platform"`
+		Token    string `json:"token"`
+	}
+
+	data := struct {
+		Devices []target `json:"devices"`
+		Title   string   `json:"title"`
+		Message string   `json:"message"`
+	}{
+		Title:   title,
+		Message: msg,
+	}
+
+	for _, d := range devices {
+		data.Devices = append(data.Devices, target{Platform: d.Platform, Token: d.Token})
+	}
+
+	go func() {
+		req, err := request.New(http.MethodPost, m.relay, request.MarshalJSON(data), map[string]string{
+			"Authorization": "Bearer " + m.token,
+			"Content-Type":  request.JSONContent,
+		})
+
+		if err == nil {
+			_, err = m.DoBody(req)
+		}
+
+		if err != nil {
+			m.log.ERROR.Println(err)
+		}
+	}()
+}
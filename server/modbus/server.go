@@ -0,0 +1,263 @@
+package modbus
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// Register layout
+//
+// All values are signed 32 bit integers (big endian, high word first) occupying two registers.
+// Site values start at address 0, values of loadpoint n (1-based) start at address 100*n.
+//
+// Input registers (read only):
+//
+//	site       +0 grid power (W), +2 pv power (W), +4 battery power (W), +6 home power (W), +8 battery soc (%)
+//	loadpoint  +0 charge power (W), +2 charged energy (Wh), +4 vehicle soc (%), +6 status (0=A, 1=B, 2=C), +8 mode
+//
+// Holding registers (read/write):
+//
+//	site       +0 residual power (W)
+//	loadpoint  +0 power budget (W, -1 removes the budget), +2 mode, +4 target soc (%)
+//
+// Modes are 0=off, 1=now, 2=minpv, 3=pv.
+const (
+	loadpointOffset = 100
+
+	// input registers
+	regGridPower     = 0
+	regPvPower       = 2
+	regBatteryPower  = 4
+	regHomePower     = 6
+	regBatterySoC    = 8
+	regChargePower   = 0
+	regChargedEnergy = 2
+	regVehicleSoC    = 4
+	regStatus        = 6
+	regMode          = 8
+
+	// holding registers
+	regResidualPower = 0
+	regBudget        = 0
+	regSetMode       = 2
+	regTargetSoC     = 4
+)
+
+// budgetSource is the remote control source of power budgets written via modbus
+const budgetSource = "modbus"
+
+var modes = []api.ChargeMode{api.ModeOff, api.ModeNow, api.ModeMinPV, api.ModePV}
+
+type server struct {
+	log      *util.Logger
+	writable bool
+	mbserver.RequestHandler
+	site  site.API
+	cache *util.Cache
+}
+
+// StartServer starts a modbus tcp server exposing site and loadpoint values. Setpoints are only accepted if writable,
+// modbus has no authentication.
+func StartServer(port int, writable bool, site site.API, cache *util.Cache) error {
+	h := &server{
+		log:            util.NewLogger("modbus"),
+		writable:       writable,
+		RequestHandler: new(mbserver.DummyHandler), // supplies HandleCoils and HandleDiscreteInputs
+		site:           site,
+		cache:          cache,
+	}
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	h.log.DEBUG.Printf("modbus server listening at :%d", port)
+
+	if writable {
+		h.log.WARN.Println("modbus server accepts setpoints without authentication")
+	}
+
+	srv, err := mbserver.New(h)
+
+	if err == nil {
+		err = srv.Start(l)
+	}
+
+	return err
+}
+
+// values are the register values by address
+type values map[uint16]int32
+
+func (v values) set(base uint16, offset int, val float64) {
+	v[base+uint16(offset)] = int32(val)
+}
+
+// words returns the requested register range
+func (v values) words(addr, qty uint16) ([]uint16, error) {
+	res := make([]uint16, 0, qty)
+
+	for a := addr; a < addr+qty; a++ {
+		if val, ok := v[a]; ok {
+			res = append(res, uint16(uint32(val)>>16))
+		} else if val, ok := v[a-1]; ok && a > 0 {
+			res = append(res, uint16(uint32(val)))
+		} else {
+			return nil, mbserver.ErrIllegalDataAddress
+		}
+	}
+
+	return res, nil
+}
+
+func modeValue(mode api.ChargeMode) float64 {
+	return float64(slices.Index(modes, mode))
+}
+
+// statusValue returns the charge status as 0=A (disconnected), 1=B (connected) or 2=C (charging)
+func statusValue(lp state.Loadpoint) float64 {
+	switch {
+	case lp.Charging:
+		return 2
+	case lp.Connected:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// inputs returns the input register values from the published state
+func (h *server) inputs() (values, error) {
+	s, err := state.Decode(h.cache.State())
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(values)
+	res.set(0, regGridPower, s.GridPower)
+	res.set(0, regPvPower, s.PvPower)
+	res.set(0, regBatteryPower, s.BatteryPower)
+	res.set(0, regHomePower, s.HomePower)
+	res.set(0, regBatterySoC, s.BatterySoC)
+
+	for i, lp := range s.Loadpoints {
+		base := uint16(loadpointOffset * (i + 1))
+
+		res.set(base, regChargePower, lp.ChargePower)
+		res.set(base, regChargedEnergy, lp.ChargedEnergy)
+		res.set(base, regVehicleSoC, lp.VehicleSoC)
+		res.set(base, regStatus, statusValue(lp))
+		res.set(base, regMode, modeValue(lp.Mode))
+	}
+
+	return res, nil
+}
+
+// holdings returns the holding register values
+func (h *server) holdings() values {
+	res := make(values)
+	res.set(0, regResidualPower, h.site.GetResidualPower())
+
+	for i, lp := range h.site.LoadPoints() {
+		base := uint16(loadpointOffset * (i + 1))
+
+		res.set(base, regBudget, lp.GetRemoteBudget())
+		res.set(base, regSetMode, modeValue(lp.GetMode()))
+		res.set(base, regTargetSoC, float64(lp.GetTargetSoC()))
+	}
+
+	return res
+}
+
+// write applies the setpoint written to the given address
+func (h *server) write(addr uint16, val int32) error {
+	if addr == regResidualPower {
+		return h.site.SetResidualPower(float64(val))
+	}
+
+	lps := h.site.LoadPoints()
+
+	idx := int(addr/loadpointOffset) - 1
+	if idx < 0 || idx >= len(lps) {
+		return mbserver.ErrIllegalDataAddress
+	}
+
+	lp := lps[idx]
+
+	switch addr % loadpointOffset {
+	case regBudget:
+		lp.SetRemoteBudget(budgetSource, float64(val))
+		lp.RemoteHeartbeat(budgetSource)
+
+	case regSetMode:
+		if val < 0 || int(val) >= len(modes) {
+			return mbserver.ErrIllegalDataValue
+		}
+		lp.SetMode(modes[val])
+
+	case regTargetSoC:
+		if val < 0 || val > 100 {
+			return mbserver.ErrIllegalDataValue
+		}
+		lp.SetTargetSoC(int(val))
+
+	default:
+		return mbserver.ErrIllegalDataAddress
+	}
+
+	return nil
+}
+
+func (h *server) HandleInputRegisters(req *mbserver.InputRegistersRequest) ([]uint16, error) {
+	h.log.TRACE.Printf("read input: id %d addr %d qty %d", req.UnitId, req.Addr, req.Quantity)
+
+	v, err := h.inputs()
+	if err != nil {
+		h.log.ERROR.Printf("read input: %v", err)
+		return nil, mbserver.ErrServerDeviceFailure
+	}
+
+	return v.words(req.Addr, req.Quantity)
+}
+
+func (h *server) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) ([]uint16, error) {
+	if !req.IsWrite {
+		h.log.TRACE.Printf("read holding: id %d addr %d qty %d", req.UnitId, req.Addr, req.Quantity)
+		return h.holdings().words(req.Addr, req.Quantity)
+	}
+
+	if !h.writable {
+		return nil, mbserver.ErrIllegalFunction
+	}
+
+	h.log.TRACE.Printf("write holding: id %d addr %d qty %d val %0x", req.UnitId, req.Addr, req.Quantity, asBytes(req.Args))
+
+	// values must be written completely
+	current := h.holdings()
+	if _, ok := current[req.Addr]; !ok || len(req.Args)%2 != 0 {
+		return nil, mbserver.ErrIllegalDataAddress
+	}
+
+	for i := 0; i < len(req.Args); i += 2 {
+		addr := req.Addr + uint16(i)
+		if _, ok := current[addr]; !ok {
+			return nil, mbserver.ErrIllegalDataAddress
+		}
+
+		val := int32(uint32(req.Args[i])<<16 | uint32(req.Args[i+1]))
+		if err := h.write(addr, val); err != nil {
+			h.log.ERROR.Printf("write holding: addr %d: %v", addr, err)
+			return nil, err
+		}
+	}
+
+	return req.Args, nil
+}
@@ -0,0 +1,81 @@
+package modbus
+
+import (
+	"testing"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSite struct {
+	site.API
+	residualPower float64
+}
+
+func (s *testSite) GetResidualPower() float64 {
+	return s.residualPower
+}
+
+func (s *testSite) SetResidualPower(power float64) error {
+	s.residualPower = power
+	return nil
+}
+
+func (s *testSite) LoadPoints() []loadpoint.API {
+	return nil
+}
+
+func TestServerInputs(t *testing.T) {
+	cache := util.NewCache()
+
+	lp := 0
+	for _, p := range []util.Param{
+		{Key: state.GridPower, Val: -1500.0},
+		{Key: state.PvPower, Val: 5000.0},
+		{LoadPoint: &lp, Key: state.ChargePower, Val: 3500.0},
+		{LoadPoint: &lp, Key: state.Charging, Val: true},
+		{LoadPoint: &lp, Key: state.Mode, Val: api.ModePV},
+	} {
+		cache.Add(p.UniqueID(), p)
+	}
+
+	h := &server{log: util.NewLogger("foo"), cache: cache}
+
+	res, err := h.HandleInputRegisters(&mbserver.InputRegistersRequest{Addr: regGridPower, Quantity: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0xffff, 0xfa24, 0, 5000}, res)
+
+	res, err = h.HandleInputRegisters(&mbserver.InputRegistersRequest{Addr: loadpointOffset + regStatus, Quantity: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0, 2, 0, 3}, res)
+
+	_, err = h.HandleInputRegisters(&mbserver.InputRegistersRequest{Addr: 50, Quantity: 2})
+	assert.Equal(t, mbserver.ErrIllegalDataAddress, err)
+}
+
+func TestServerHoldings(t *testing.T) {
+	site := new(testSite)
+	h := &server{log: util.NewLogger("foo"), site: site, writable: true}
+
+	_, err := h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{IsWrite: true, Addr: regResidualPower, Quantity: 2, Args: []uint16{0xffff, 0xff9c}})
+	require.NoError(t, err)
+	assert.Equal(t, -100.0, site.residualPower)
+
+	res, err := h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{Addr: regResidualPower, Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0xffff, 0xff9c}, res)
+
+	// partial value
+	_, err = h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{IsWrite: true, Addr: regResidualPower, Quantity: 1, Args: []uint16{1}})
+	assert.Equal(t, mbserver.ErrIllegalDataAddress, err)
+
+	h.writable = false
+	_, err = h.HandleHoldingRegisters(&mbserver.HoldingRegistersRequest{IsWrite: true, Addr: regResidualPower, Quantity: 2, Args: []uint16{0, 1}})
+	assert.Equal(t, mbserver.ErrIllegalFunction, err)
+}
@@ -1,14 +1,15 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/evcc-io/evcc/api"
-	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/event"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/util"
 )
@@ -82,51 +83,48 @@ func (m *MQTT) publish(topic string, retained bool, payload interface{}) {
 		}
 	}
 
+	if pvs, ok := payload.([]state.PvString); ok {
+		payload = len(pvs)
+
+		// publish pv meters
+		for i, pv := range pvs {
+			m.publishSingleValue(fmt.Sprintf("%s/%d/title", topic, i+1), retained, pv.Title)
+			m.publishSingleValue(fmt.Sprintf("%s/%d/power", topic, i+1), retained, pv.Power)
+		}
+	}
+
 	m.publishSingleValue(topic, retained, payload)
 }
 
-func (m *MQTT) listenSetters(topic string, site site.API, lp loadpoint.API) {
-	m.Handler.ListenSetter(topic+"/mode/set", func(payload string) {
-		lp.SetMode(api.ChargeMode(payload))
-	})
-	m.Handler.ListenSetter(topic+"/minSoC/set", func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			lp.SetMinSoC(soc)
-		}
-	})
-	m.Handler.ListenSetter(topic+"/targetSoC/set", func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			lp.SetTargetSoC(soc)
-		}
-	})
-	m.Handler.ListenSetter(topic+"/minCurrent/set", func(payload string) {
-		if current, err := strconv.ParseFloat(payload, 64); err == nil {
-			lp.SetMinCurrent(current)
-		}
-	})
-	m.Handler.ListenSetter(topic+"/maxCurrent/set", func(payload string) {
-		if current, err := strconv.ParseFloat(payload, 64); err == nil {
-			lp.SetMaxCurrent(current)
-		}
-	})
-	m.Handler.ListenSetter(topic+"/phases/set", func(payload string) {
-		if phases, err := strconv.Atoi(payload); err == nil {
-			_ = lp.SetPhases(phases)
-		}
-	})
-	m.Handler.ListenSetter(topic+"/vehicle/set", func(payload string) {
-		if vehicle, err := strconv.Atoi(payload); err == nil {
-			if vehicle >= 0 {
-				if vehicles := site.GetVehicles(); vehicle < len(vehicles) {
-					lp.SetVehicle(vehicles[vehicle])
-				}
-			} else {
-				lp.SetVehicle(nil)
-			}
+// listenSetter subscribes the setter to <topic>/set and acknowledges the applied value or error on <topic>/ack
+func (m *MQTT) listenSetter(topic string, fn setter) {
+	m.Handler.ListenSetter(topic+"/set", func(payload string) {
+		val, err := fn(payload)
+		if err != nil {
+			log.ERROR.Printf("mqtt: %s: %v", topic, err)
+			val = "error: " + err.Error()
 		}
+
+		m.publish(topic+"/ack", false, val)
 	})
 }
 
+// publishEvent publishes the event as json to <topic>/events/<name>
+func (m *MQTT) publishEvent(e event.Envelope) {
+	topic := fmt.Sprintf("%s/site", m.root)
+	if e.LoadPoint != nil {
+		topic = fmt.Sprintf("%s/loadpoints/%d", m.root, *e.LoadPoint+1)
+	}
+
+	b, err := json.Marshal(e.Event)
+	if err != nil {
+		log.ERROR.Printf("mqtt: %s: %v", e.Event.Name(), err)
+		return
+	}
+
+	m.publishSingleValue(fmt.Sprintf("%s/events/%s", topic, e.Event.Name()), false, string(b))
+}
+
 // Run starts the MQTT publisher for the MQTT API
 func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 	// alive
@@ -134,23 +132,9 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 	m.publish(topic, true, "online")
 
 	// site setters
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/prioritySoC/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetPrioritySoC(float64(soc))
-		}
-	})
-
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/bufferSoC/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetBufferSoC(float64(soc))
-		}
-	})
-
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/residualPower/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetResidualPower(float64(soc))
-		}
-	})
+	for key, fn := range siteSetters(site) {
+		m.listenSetter(fmt.Sprintf("%s/site/%s", m.root, key), fn)
+	}
 
 	// number of loadpoints
 	topic = fmt.Sprintf("%s/loadpoints", m.root)
@@ -159,7 +143,14 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 	// loadpoint setters
 	for id, lp := range site.LoadPoints() {
 		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
-		m.listenSetters(topic, site, lp)
+		for key, fn := range loadpointSetters("mqtt", site, lp) {
+			m.listenSetter(fmt.Sprintf("%s/%s", topic, key), fn)
+		}
+	}
+
+	// events
+	if bus := site.Events(); bus != nil {
+		bus.Subscribe(m.publishEvent)
 	}
 
 	// TODO remove deprecated topics
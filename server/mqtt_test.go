@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTargetCharge(t *testing.T) {
+	tc, err := parseTargetCharge(`{"soc":80,"time":"2022-10-01T07:00:00Z"}`)
+	require.NoError(t, err)
+	assert.Equal(t, targetCharge{SoC: 80, Time: time.Date(2022, 10, 1, 7, 0, 0, 0, time.UTC)}, tc)
+
+	// remove target
+	tc, err = parseTargetCharge("")
+	require.NoError(t, err)
+	assert.True(t, tc.Time.IsZero())
+
+	for _, payload := range []string{"80", `{"soc":0,"time":"2022-10-01T07:00:00Z"}`, `{"soc":101,"time":"2022-10-01T07:00:00Z"}`} {
+		_, err := parseTargetCharge(payload)
+		assert.Error(t, err, payload)
+	}
+}
+
+func TestSetterAck(t *testing.T) {
+	var val int
+	fn := intSetter(func(v int) { val = v }, func() int { return val })
+
+	res, err := fn("42")
+	require.NoError(t, err)
+	assert.Equal(t, 42, res)
+
+	// invalid payload acknowledges unchanged value
+	res, err = fn("foo")
+	assert.Error(t, err)
+	assert.Equal(t, 42, res)
+}
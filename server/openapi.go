@@ -0,0 +1,178 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// openAPI is the OpenAPI 3 document generated from the registered api routes
+type openAPI struct {
+	OpenAPI string                                  `json:"openapi"`
+	Info    openAPIInfo                             `json:"info"`
+	Paths   map[string]map[string]*openAPIOperation `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string        `json:"name"`
+	In       string        `json:"in"`
+	Required bool          `json:"required"`
+	Schema   openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type openAPIResponse struct {
+	Description string `json:"description"`
+}
+
+var (
+	// pathVarRE matches mux path variables with optional pattern, e.g. {value:[0-9]+}
+	pathVarRE = regexp.MustCompile(`\{([^:}]+)(?::([^}]+))?\}`)
+
+	// loadpointRE matches the per-loadpoint route prefix
+	loadpointRE = regexp.MustCompile(`^/api/loadpoints/[0-9]+`)
+)
+
+// openAPISpec generates the OpenAPI document from the router's api routes.
+// Per-loadpoint routes are collapsed into a single path with id parameter.
+func openAPISpec(router *mux.Router) (openAPI, error) {
+	res := openAPI{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "evcc", Version: Version},
+		Paths:   make(map[string]map[string]*openAPIOperation),
+	}
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(tmpl, "/api/") {
+			return nil
+		}
+
+		// subrouters and prefixes have no methods
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		var params []openAPIParameter
+
+		if loadpointRE.MatchString(tmpl) {
+			params = append(params, openAPIParameter{
+				Name:     "id",
+				In:       "path",
+				Required: true,
+				Schema:   openAPISchema{Type: "integer"},
+			})
+		}
+
+		for _, m := range pathVarRE.FindAllStringSubmatch(tmpl, -1) {
+			params = append(params, openAPIParameter{
+				Name:     m[1],
+				In:       "path",
+				Required: true,
+				Schema:   openAPISchema{Type: "string", Pattern: m[2]},
+			})
+		}
+
+		path := pathVarRE.ReplaceAllString(tmpl, "{$1}")
+		path = loadpointRE.ReplaceAllString(path, "/api/loadpoints/{id}")
+
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+
+			if res.Paths[path] == nil {
+				res.Paths[path] = make(map[string]*openAPIOperation)
+			}
+
+			method = strings.ToLower(method)
+			if _, ok := res.Paths[path][method]; ok {
+				continue
+			}
+
+			res.Paths[path][method] = &openAPIOperation{
+				OperationID: operationID(method, path),
+				Tags:        []string{strings.Split(strings.TrimPrefix(path, "/api/"), "/")[0]},
+				Parameters:  params,
+				Responses: map[string]openAPIResponse{
+					"200": {Description: "OK"},
+					"400": {Description: "Bad request"},
+				},
+			}
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// operationID derives a unique operation id from method and path, e.g. postLoadpointsIdModeValue
+func operationID(method, path string) string {
+	res := method
+
+	for _, s := range strings.FieldsFunc(strings.TrimPrefix(path, "/api"), func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.'
+	}) {
+		res += strings.ToUpper(s[:1]) + s[1:]
+	}
+
+	return res
+}
+
+// specHandler serves the OpenAPI specification of the api routes
+func specHandler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := openAPISpec(router)
+		if err != nil {
+			jsonError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		jsonWrite(w, res)
+	}
+}
+
+// swaggerTemplate renders the Swagger UI for the api specification
+const swaggerTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>evcc API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>SwaggerUIBundle({ url: "%s", dom_id: "#swagger-ui" });</script>
+</body>
+</html>
+`
+
+// swaggerHandler serves the Swagger UI
+func swaggerHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		fmt.Fprintf(w, swaggerTemplate, "spec")
+	}
+}
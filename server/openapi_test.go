@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	api.Methods("GET").Path("/state").HandlerFunc(http.NotFound)
+
+	for _, id := range []string{"0", "1"} {
+		lp := api.PathPrefix("/loadpoints/" + id).Subrouter()
+		lp.Methods("POST", "OPTIONS").Path("/mode/{value:[a-z]+}").HandlerFunc(http.NotFound)
+	}
+
+	router.Methods("GET").Path("/ws").HandlerFunc(http.NotFound)
+
+	res, err := openAPISpec(router)
+	require.NoError(t, err)
+
+	assert.Len(t, res.Paths, 2)
+	assert.Contains(t, res.Paths["/api/state"], "get")
+
+	mode := res.Paths["/api/loadpoints/{id}/mode/{value}"]
+	require.Len(t, mode, 1, "options and duplicate loadpoints removed")
+
+	op := mode["post"]
+	assert.Equal(t, "postLoadpointsIdModeValue", op.OperationID)
+	assert.Equal(t, []string{"loadpoints"}, op.Tags)
+	assert.Equal(t, []openAPIParameter{
+		{Name: "id", In: "path", Required: true, Schema: openAPISchema{Type: "integer"}},
+		{Name: "value", In: "path", Required: true, Schema: openAPISchema{Type: "string", Pattern: "[a-z]+"}},
+	}, op.Parameters)
+}
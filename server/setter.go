@@ -0,0 +1,200 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+)
+
+// setter applies the payload and returns the applied value
+type setter func(payload string) (interface{}, error)
+
+func intSetter(set func(int), get func() int) setter {
+	return func(payload string) (interface{}, error) {
+		val, err := strconv.Atoi(payload)
+		if err == nil {
+			set(val)
+		}
+		return get(), err
+	}
+}
+
+func floatSetter(set func(float64), get func() float64) setter {
+	return func(payload string) (interface{}, error) {
+		val, err := strconv.ParseFloat(payload, 64)
+		if err == nil {
+			set(val)
+		}
+		return get(), err
+	}
+}
+
+// targetCharge is the target charge payload
+type targetCharge struct {
+	SoC  int       `json:"soc"`
+	Time time.Time `json:"time"`
+}
+
+// parseTargetCharge parses json target charge payload, empty payload removes the target
+func parseTargetCharge(payload string) (targetCharge, error) {
+	var res targetCharge
+	if payload == "" {
+		return res, nil
+	}
+
+	err := json.Unmarshal([]byte(payload), &res)
+	if err == nil && !res.Time.IsZero() && (res.SoC <= 0 || res.SoC > 100) {
+		err = fmt.Errorf("invalid soc: %d", res.SoC)
+	}
+
+	return res, err
+}
+
+// externalBatteryMode is the external battery mode payload
+type externalBatteryMode struct {
+	Mode   api.BatteryMode `json:"mode"`
+	Expiry time.Time       `json:"expiry"`
+}
+
+// parseBatteryMode parses json battery mode payload, empty payload removes the external mode
+func parseBatteryMode(payload string) (externalBatteryMode, error) {
+	var res externalBatteryMode
+	if payload == "" {
+		return res, nil
+	}
+
+	err := json.Unmarshal([]byte(payload), &res)
+	if err == nil && res.Mode != "" {
+		res.Mode, err = api.BatteryModeString(string(res.Mode))
+	}
+
+	return res, err
+}
+
+// loadpointSetters are the loadpoint setters by key, remote control requests are attributed to source
+func loadpointSetters(source string, site site.API, lp loadpoint.API) map[string]setter {
+	return map[string]setter{
+		"mode": func(payload string) (interface{}, error) {
+			mode, err := api.ChargeModeString(payload)
+			if err == nil {
+				lp.SetMode(mode)
+			}
+			return lp.GetMode(), err
+		},
+		"minSoC":       intSetter(lp.SetMinSoC, lp.GetMinSoC),
+		"targetSoC":    intSetter(lp.SetTargetSoC, lp.GetTargetSoC),
+		"targetEnergy": floatSetter(lp.SetTargetEnergy, lp.GetTargetEnergy),
+		"minCurrent":   floatSetter(lp.SetMinCurrent, lp.GetMinCurrent),
+		"maxCurrent":   floatSetter(lp.SetMaxCurrent, lp.GetMaxCurrent),
+		"phases": func(payload string) (interface{}, error) {
+			phases, err := strconv.Atoi(payload)
+			if err == nil {
+				err = lp.SetPhases(phases)
+			}
+			return lp.GetPhases(), err
+		},
+		"targetCharge": func(payload string) (interface{}, error) {
+			tc, err := parseTargetCharge(payload)
+			if err != nil {
+				return nil, err
+			}
+
+			lp.SetTargetCharge(tc.Time, tc.SoC)
+
+			b, err := json.Marshal(tc)
+			return string(b), err
+		},
+		"remoteDemand": func(payload string) (interface{}, error) {
+			demand, err := loadpoint.RemoteDemandString(payload)
+			if err == nil {
+				lp.RemoteControl(source, demand)
+			}
+			return string(demand), err
+		},
+		"remoteBudget": floatSetter(func(power float64) {
+			lp.SetRemoteBudget(source, power)
+		}, lp.GetRemoteBudget),
+		"heartbeat": func(payload string) (interface{}, error) {
+			lp.RemoteHeartbeat(source)
+			return true, nil
+		},
+		"vehicle": func(payload string) (interface{}, error) {
+			vehicle, err := strconv.Atoi(payload)
+			if err != nil {
+				return nil, err
+			}
+
+			if vehicle < 0 {
+				lp.SetVehicle(nil)
+				return vehicle, nil
+			}
+
+			vehicles := site.GetVehicles()
+			if vehicle >= len(vehicles) {
+				return nil, fmt.Errorf("invalid vehicle: %d", vehicle)
+			}
+
+			lp.SetVehicle(vehicles[vehicle])
+			return vehicle, nil
+		},
+	}
+}
+
+// siteSetters are the site setters by key
+func siteSetters(site site.API) map[string]setter {
+	floatErrSetter := func(set func(float64) error, get func() float64) setter {
+		return func(payload string) (interface{}, error) {
+			val, err := strconv.ParseFloat(payload, 64)
+			if err == nil {
+				err = set(val)
+			}
+			return get(), err
+		}
+	}
+
+	return map[string]setter{
+		"prioritySoC":    floatErrSetter(site.SetPrioritySoC, site.GetPrioritySoC),
+		"bufferSoC":      floatErrSetter(site.SetBufferSoC, site.GetBufferSoC),
+		"bufferStartSoC": floatErrSetter(site.SetBufferStartSoC, site.GetBufferStartSoC),
+		"residualPower":  floatErrSetter(site.SetResidualPower, site.GetResidualPower),
+		"batteryMode": func(payload string) (interface{}, error) {
+			bm, err := parseBatteryMode(payload)
+			if err == nil {
+				err = site.SetExternalBatteryMode(bm.Mode, bm.Expiry)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			bm.Mode, bm.Expiry = site.GetExternalBatteryMode()
+
+			b, err := json.Marshal(bm)
+			return string(b), err
+		},
+	}
+}
+
+// applySetting applies a setting to the site or the 1-based loadpoint, 0 addressing the site
+func applySetting(site site.API, source string, loadpoint int, key, value string) (interface{}, error) {
+	setters := siteSetters(site)
+	if loadpoint != 0 {
+		lps := site.LoadPoints()
+		if loadpoint < 0 || loadpoint > len(lps) {
+			return nil, fmt.Errorf("invalid loadpoint: %d", loadpoint)
+		}
+
+		setters = loadpointSetters(source, site, lps[loadpoint-1])
+	}
+
+	set, ok := setters[key]
+	if !ok {
+		return nil, fmt.Errorf("invalid key: %s", key)
+	}
+
+	return set(value)
+}
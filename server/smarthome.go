@@ -0,0 +1,266 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	dbsettings "github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	smartHomeTokensSetting = "smarthome.tokens"
+	smartHomeCodeTTL       = 5 * time.Minute
+	smartHomeTokenTTL      = time.Hour
+)
+
+// SmartHomeConfig is the smart home bridge configuration. The bridge acts as OAuth authorization server for account linking.
+type SmartHomeConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURIs []string       // allowed redirect uris of the skill's account linking
+	OnMode       api.ChargeMode // charge mode applied when switching on, defaults to pv
+}
+
+// SmartHomeDevice is a loadpoint exposed as smart home device
+type SmartHomeDevice struct {
+	ID    string         `json:"id"`
+	Name  string         `json:"name"`
+	Type  string         `json:"type"`
+	On    bool           `json:"on"`
+	Mode  api.ChargeMode `json:"mode"`
+	Modes []string       `json:"modes"`
+	SoC   *float64       `json:"soc,omitempty"` // percentage sensor, nil without vehicle soc
+}
+
+// SmartHomeToken is the OAuth token response
+type SmartHomeToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// SmartHome is the smart home bridge for voice assistant skills
+type SmartHome struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	conf    SmartHomeConfig
+	codes   map[string]time.Time // authorization codes by expiry
+	access  map[string]time.Time // access tokens by expiry
+	refresh []string             // persisted refresh token hashes
+}
+
+// NewSmartHome creates the smart home bridge
+func NewSmartHome(conf SmartHomeConfig) (*SmartHome, error) {
+	if conf.ClientID == "" || conf.ClientSecret == "" {
+		return nil, errors.New("missing client id or secret")
+	}
+
+	if len(conf.RedirectURIs) == 0 {
+		return nil, errors.New("missing redirect uris")
+	}
+
+	if conf.OnMode == "" {
+		conf.OnMode = api.ModePV
+	}
+
+	if _, err := api.ChargeModeString(string(conf.OnMode)); err != nil || conf.OnMode == api.ModeOff {
+		return nil, fmt.Errorf("invalid on mode: %s", conf.OnMode)
+	}
+
+	sh := &SmartHome{
+		log:    util.NewLogger("smarthome").Redact(conf.ClientSecret),
+		conf:   conf,
+		codes:  make(map[string]time.Time),
+		access: make(map[string]time.Time),
+	}
+
+	if err := dbsettings.Json(smartHomeTokensSetting, &sh.refresh); err != nil && !errors.Is(err, dbsettings.ErrNotFound) {
+		return nil, err
+	}
+
+	return sh, nil
+}
+
+func randomToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func tokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// expire removes expired codes and access tokens (no mutex)
+func (sh *SmartHome) expire() {
+	for _, m := range []map[string]time.Time{sh.codes, sh.access} {
+		for k, exp := range m {
+			if time.Now().After(exp) {
+				delete(m, k)
+			}
+		}
+	}
+}
+
+// Authorize validates the account linking request and returns a short-lived authorization code
+func (sh *SmartHome) Authorize(clientID, redirectURI string) (string, error) {
+	if !equal(clientID, sh.conf.ClientID) {
+		return "", errors.New("invalid client")
+	}
+
+	if !slices.Contains(sh.conf.RedirectURIs, redirectURI) {
+		return "", errors.New("invalid redirect uri")
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.expire()
+
+	code := randomToken()
+	sh.codes[tokenHash(code)] = time.Now().Add(smartHomeCodeTTL)
+
+	return code, nil
+}
+
+// Exchange issues an access token for an authorization code or refresh token
+func (sh *SmartHome) Exchange(clientID, clientSecret, grantType, value string) (SmartHomeToken, error) {
+	var res SmartHomeToken
+
+	if !equal(clientID, sh.conf.ClientID) || !equal(clientSecret, sh.conf.ClientSecret) {
+		return res, ErrUnauthorized
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.expire()
+	hash := tokenHash(value)
+
+	switch grantType {
+	case "authorization_code":
+		if _, ok := sh.codes[hash]; !ok {
+			return res, errors.New("invalid code")
+		}
+		delete(sh.codes, hash)
+
+		res.RefreshToken = randomToken()
+		sh.refresh = append(sh.refresh, tokenHash(res.RefreshToken))
+		sh.persist()
+
+		sh.log.DEBUG.Println("account linked")
+
+	case "refresh_token":
+		if !slices.Contains(sh.refresh, hash) {
+			return res, errors.New("invalid refresh token")
+		}
+
+	default:
+		return res, fmt.Errorf("unsupported grant type: %s", grantType)
+	}
+
+	res.AccessToken = randomToken()
+	res.TokenType = "Bearer"
+	res.ExpiresIn = int(smartHomeTokenTTL / time.Second)
+	sh.access[tokenHash(res.AccessToken)] = time.Now().Add(smartHomeTokenTTL)
+
+	return res, nil
+}
+
+// persist stores the refresh token hashes (no mutex)
+func (sh *SmartHome) persist() {
+	if err := dbsettings.SetJson(smartHomeTokensSetting, sh.refresh); err != nil {
+		sh.log.ERROR.Println(err)
+	}
+}
+
+// TokenRole implements the api token hook, linked accounts may control the loadpoints through the smart home api
+func (sh *SmartHome) TokenRole(token string) (Role, bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	exp, ok := sh.access[tokenHash(token)]
+	if !ok || time.Now().After(exp) {
+		return "", false
+	}
+
+	return RoleSmartHome, true
+}
+
+// smartHomeModes are the charge modes in display order
+var smartHomeModes = []string{string(api.ModeOff), string(api.ModeNow), string(api.ModeMinPV), string(api.ModePV)}
+
+// device returns the loadpoint as smart home device
+func (sh *SmartHome) device(id int, lp loadpoint.API) SmartHomeDevice {
+	mode := lp.GetMode()
+
+	res := SmartHomeDevice{
+		ID:    fmt.Sprintf("lp%d", id+1),
+		Name:  lp.Name(),
+		Type:  "EV_CHARGER",
+		On:    mode != api.ModeOff,
+		Mode:  mode,
+		Modes: smartHomeModes,
+	}
+
+	if lp.GetStatus() != api.StatusA {
+		if soc := lp.GetVehicleSoC(); soc > 0 {
+			res.SoC = &soc
+		}
+	}
+
+	return res
+}
+
+// Devices returns the loadpoints as smart home devices
+func (sh *SmartHome) Devices(lps []loadpoint.API) []SmartHomeDevice {
+	res := make([]SmartHomeDevice, 0, len(lps))
+	for id, lp := range lps {
+		res = append(res, sh.device(id, lp))
+	}
+	return res
+}
+
+// Command switches the device on or off or sets its mode
+func (sh *SmartHome) Command(lps []loadpoint.API, id string, on *bool, mode string) (SmartHomeDevice, error) {
+	var n int
+	if _, err := fmt.Sscanf(id, "lp%d", &n); err != nil || n < 1 || n > len(lps) {
+		return SmartHomeDevice{}, fmt.Errorf("device not found: %s", id)
+	}
+
+	idx := n - 1
+	lp := lps[idx]
+
+	switch {
+	case mode != "":
+		m, err := api.ChargeModeString(mode)
+		if err != nil {
+			return SmartHomeDevice{}, err
+		}
+		lp.SetMode(m)
+
+	case on != nil && *on:
+		lp.SetMode(sh.conf.OnMode)
+
+	case on != nil:
+		lp.SetMode(api.ModeOff)
+
+	default:
+		return SmartHomeDevice{}, errors.New("missing on or mode")
+	}
+
+	return sh.device(idx, lp), nil
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmartHomeAccountLinking(t *testing.T) {
+	sh, err := NewSmartHome(SmartHomeConfig{
+		ClientID:     "skill",
+		ClientSecret: "secret",
+		RedirectURIs: []string{"https://skill.example.com/link"},
+	})
+	require.NoError(t, err)
+
+	auth, err := NewAuth(AuthConfig{Tokens: []TokenConfig{{Token: "reader"}}})
+	require.NoError(t, err)
+	auth.AddTokenSource(sh.TokenRole)
+
+	authorize := auth.Handler(smartHomeAuthorizeHandler(sh))
+	authorizeURL := "/api/smarthome/oauth/authorize?response_type=code&client_id=skill&state=xyz&redirect_uri=" + url.QueryEscape("https://skill.example.com/link")
+
+	// read role may not link accounts
+	w := httptest.NewRecorder()
+	authorize.ServeHTTP(w, httptest.NewRequest(http.MethodGet, authorizeURL+"&token=reader", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// without authentication all requests are allowed
+	w = httptest.NewRecorder()
+	smartHomeAuthorizeHandler(sh)(w, httptest.NewRequest(http.MethodGet, authorizeURL, nil))
+	require.Equal(t, http.StatusFound, w.Code)
+
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "skill.example.com", loc.Host)
+	assert.Equal(t, "xyz", loc.Query().Get("state"))
+
+	token := func(form url.Values) (*httptest.ResponseRecorder, SmartHomeToken) {
+		req := httptest.NewRequest(http.MethodPost, smartHomeTokenPath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		w := httptest.NewRecorder()
+		auth.Handler(smartHomeTokenHandler(sh)).ServeHTTP(w, req)
+
+		var res SmartHomeToken
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+
+		return w, res
+	}
+
+	w, _ = token(url.Values{"grant_type": {"authorization_code"}, "code": {loc.Query().Get("code")}, "client_id": {"skill"}, "client_secret": {"wrong"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w, res := token(url.Values{"grant_type": {"authorization_code"}, "code": {loc.Query().Get("code")}, "client_id": {"skill"}, "client_secret": {"secret"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, res.RefreshToken)
+
+	role, ok := sh.TokenRole(res.AccessToken)
+	assert.True(t, ok)
+	assert.Equal(t, RoleSmartHome, role)
+
+	// codes are single use
+	w, _ = token(url.Values{"grant_type": {"authorization_code"}, "code": {loc.Query().Get("code")}, "client_id": {"skill"}, "client_secret": {"secret"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, refreshed := token(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {res.RefreshToken}, "client_id": {"skill"}, "client_secret": {"secret"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, res.AccessToken, refreshed.AccessToken)
+
+	// linked accounts may control the smart home api only
+	for path, status := range map[string]int{
+		"/api/smarthome/devices/lp1": http.StatusNoContent,
+		"/api/loadpoints/1/mode/now": http.StatusForbidden,
+	} {
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+refreshed.AccessToken)
+		auth.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})).ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code, path)
+	}
+}
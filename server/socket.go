@@ -10,6 +10,7 @@ import (
 
 	"github.com/evcc-io/evcc/util"
 	"github.com/gorilla/websocket"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -32,6 +33,115 @@ type SocketClient struct {
 
 	// Buffered channel of outbound messages.
 	send chan []byte
+
+	// Subscribed topics unless subscribed to all topics. Guarded by the hub.
+	all    bool
+	topics []string
+
+	// JSON Patch protocol and number of loadpoints in the client's state. Guarded by the hub.
+	patch      bool
+	loadpoints int
+
+	// Sequence number of the client's state. Guarded by the hub.
+	seq uint64
+}
+
+// socketRequest is a subscription request sent by the client
+type socketRequest struct {
+	Subscribe   []string `json:"subscribe"`
+	Unsubscribe []string `json:"unsubscribe"`
+}
+
+// subscription is a client's subscription request forwarded to the hub
+type subscription struct {
+	client *SocketClient
+	socketRequest
+}
+
+// topicMatch returns true if the topic matches the param key.
+// Topics use / or . as separator, + matches a single level and topics match all keys below them.
+func topicMatch(topic, key string) bool {
+	ts := strings.FieldsFunc(topic, func(r rune) bool { return r == '/' || r == '.' })
+	ks := strings.Split(key, ".")
+
+	if len(ts) == 0 || len(ts) > len(ks) {
+		return false
+	}
+
+	for i, t := range ts {
+		if t != "+" && t != "#" && !strings.EqualFold(t, ks[i]) {
+			return false
+		}
+		if t == "#" {
+			return true
+		}
+	}
+
+	return true
+}
+
+// subscribed returns true if the client is subscribed to the param key
+func (c *SocketClient) subscribed(key string) bool {
+	if c.all {
+		return true
+	}
+
+	for _, topic := range c.topics {
+		if topicMatch(topic, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// update adds and removes subscribed topics and returns the added topics.
+// Subscribing narrows a client subscribed to all topics to the requested topics.
+func (c *SocketClient) update(req socketRequest) []string {
+	if len(req.Subscribe) > 0 {
+		c.all = false
+	}
+
+	for _, topic := range req.Unsubscribe {
+		for i, t := range c.topics {
+			if t == topic {
+				c.topics = append(c.topics[:i], c.topics[i+1:]...)
+				break
+			}
+		}
+	}
+
+	var added []string
+	for _, topic := range req.Subscribe {
+		if !slices.Contains(c.topics, topic) {
+			c.topics = append(c.topics, topic)
+			added = append(added, topic)
+		}
+	}
+
+	return added
+}
+
+// readPump pumps subscription requests from the websocket connection to the hub.
+func (c *SocketClient) readPump() {
+	defer func() {
+		c.hub.unregister <- c
+	}()
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req socketRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			log.DEBUG.Printf("websocket: invalid request: %v", err)
+			continue
+		}
+
+		c.hub.subscribe <- subscription{client: c, socketRequest: req}
+	}
 }
 
 // writePump pumps messages from the hub to the websocket connection.
@@ -59,10 +169,21 @@ func ServeWebsocket(hub *SocketHub, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	client := &SocketClient{hub: hub, conn: conn, send: make(chan []byte, 256)}
+
+	// deltas as JSON Patch like ?protocol=patch
+	client.patch = r.URL.Query().Get("protocol") == patchProtocol
+
+	// initial subscription like ?topics=gridPower,loadpoints/1
+	for _, topics := range r.URL.Query()["topics"] {
+		client.topics = append(client.topics, strings.Split(topics, ",")...)
+	}
+	client.all = len(client.topics) == 0
+
 	client.hub.register <- client
 
 	// run writing to client in goroutine
 	go client.writePump()
+	go client.readPump()
 }
 
 // SocketHub maintains the set of active clients and broadcasts messages to the
@@ -76,6 +197,9 @@ type SocketHub struct {
 
 	// Unregister requests from clients.
 	unregister chan *SocketClient
+
+	// Subscription requests from clients.
+	subscribe chan subscription
 }
 
 // NewSocketHub creates a web socket hub that distributes meter status and
@@ -84,6 +208,7 @@ func NewSocketHub() *SocketHub {
 	return &SocketHub{
 		register:   make(chan *SocketClient),
 		unregister: make(chan *SocketClient),
+		subscribe:  make(chan subscription),
 		clients:    make(map[*SocketClient]bool),
 	}
 }
@@ -101,7 +226,7 @@ func encode(v interface{}) (string, error) {
 		// must be before stringer to convert to seconds instead of string
 		s = fmt.Sprintf("%d", int64(val.Seconds()))
 	case float64:
-		if math.IsNaN(val) {
+		if math.IsNaN(val) || math.IsInf(val, 0) {
 			s = "null"
 		} else {
 			s = fmt.Sprintf("%.5g", val)
@@ -114,6 +239,14 @@ func encode(v interface{}) (string, error) {
 	return s, nil
 }
 
+// paramKey returns the param key including the loadpoint scope
+func paramKey(p util.Param) string {
+	if p.LoadPoint != nil {
+		return fmt.Sprintf("loadpoints.%d.%s", *p.LoadPoint, p.Key)
+	}
+	return p.Key
+}
+
 func kv(p util.Param) string {
 	val, err := encode(p.Val)
 	if err != nil {
@@ -122,22 +255,72 @@ func kv(p util.Param) string {
 
 	var msg strings.Builder
 	msg.WriteString("\"")
-	if p.LoadPoint != nil {
-		msg.WriteString(fmt.Sprintf("loadpoints.%d.", *p.LoadPoint))
-	}
-	msg.WriteString(p.Key)
+	msg.WriteString(paramKey(p))
 	msg.WriteString("\":")
 	msg.WriteString(val)
 
 	return msg.String()
 }
 
-func (h *SocketHub) welcome(client *SocketClient, params []util.Param) {
+// welcome sends the state to the new client. Updates older than the state are not sent to the client.
+func (h *SocketHub) welcome(client *SocketClient, params []util.Param, seq uint64) {
 	h.clients[client] = true
+	client.seq = seq
+
+	if client.patch {
+		h.sendPatch(client, client.patchState(subscribedParams(client, params)))
+		return
+	}
+
+	h.snapshot(client, params)
+}
+
+// subscribedParams returns the params the client is subscribed to
+func subscribedParams(client *SocketClient, params []util.Param) []util.Param {
+	var res []util.Param
+	for _, p := range params {
+		if client.subscribed(paramKey(p)) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// send sends the message to the client or removes the client if it is not receiving
+func (h *SocketHub) send(client *SocketClient, msg []byte) {
+	select {
+	case client.send <- msg:
+	default:
+		h.remove(client)
+	}
+}
+
+// sendPatch sends the operations with the client's sequence number. Operations failing to encode are skipped.
+func (h *SocketHub) sendPatch(client *SocketClient, ops []patchOp) {
+	b, err := encodePatch(client.seq, ops)
+	if err != nil {
+		log.ERROR.Printf("websocket: patch: %v", err)
+		return
+	}
+
+	h.send(client, b)
+}
+
+// snapshot sends the subscribed params to the client
+func (h *SocketHub) snapshot(client *SocketClient, params []util.Param) {
+	if client.patch {
+		if params = subscribedParams(client, params); len(params) > 0 {
+			h.sendPatch(client, client.patchOps(params))
+		}
+		return
+	}
 
 	var msg strings.Builder
 	msg.WriteString("{")
 	for _, p := range params {
+		if !client.subscribed(paramKey(p)) {
+			continue
+		}
 		if msg.Len() > 1 {
 			msg.WriteString(",")
 		}
@@ -145,37 +328,71 @@ func (h *SocketHub) welcome(client *SocketClient, params []util.Param) {
 	}
 	msg.WriteString("}")
 
-	select {
-	case client.send <- []byte(msg.String()):
-	default:
+	h.send(client, []byte(msg.String()))
+}
+
+// remove closes and removes the client
+func (h *SocketHub) remove(client *SocketClient) {
+	if _, ok := h.clients[client]; ok {
 		close(client.send)
+		delete(h.clients, client)
 	}
 }
 
-func (h *SocketHub) broadcast(p util.Param) {
+// broadcast sends the update to the subscribed clients unless it is already part of their state
+func (h *SocketHub) broadcast(u util.Update) {
 	if len(h.clients) > 0 {
-		msg := "{" + kv(p) + "}"
+		key := paramKey(u.Param)
+		msg := "{" + kv(u.Param) + "}"
 
 		for client := range h.clients {
-			select {
-			case client.send <- []byte(msg):
-			default:
-				h.unregister <- client
+			if !client.subscribed(key) || u.Seq < client.seq {
+				continue
+			}
+			client.seq = u.Seq
+
+			if client.patch {
+				h.sendPatch(client, client.patchOps([]util.Param{u.Param}))
+				continue
+			}
+
+			h.send(client, []byte(msg))
+		}
+	}
+}
+
+// filterParams returns the params matching any of the topics
+func filterParams(params []util.Param, topics []string) []util.Param {
+	var res []util.Param
+	for _, p := range params {
+		key := paramKey(p)
+		for _, topic := range topics {
+			if topicMatch(topic, key) {
+				res = append(res, p)
+				break
 			}
 		}
 	}
+	return res
 }
 
-// Run starts data and status distribution
-func (h *SocketHub) Run(in <-chan util.Param, cache *util.Cache) {
+// Run starts data and status distribution. The updates must be received from the cache to share its sequence numbers.
+func (h *SocketHub) Run(in <-chan util.Update, cache *util.Cache) {
 	for {
 		select {
 		case client := <-h.register:
-			h.welcome(client, cache.All())
+			params, seq := cache.Current()
+			h.welcome(client, params, seq)
 		case client := <-h.unregister:
-			if _, ok := h.clients[client]; ok {
-				close(client.send)
-				delete(h.clients, client)
+			h.remove(client)
+		case sub := <-h.subscribe:
+			if _, ok := h.clients[sub.client]; !ok {
+				continue
+			}
+			wasAll := sub.client.all
+			// send current values of newly subscribed topics
+			if added := sub.client.update(sub.socketRequest); len(added) > 0 && !wasAll {
+				h.snapshot(sub.client, filterParams(cache.All(), added))
 			}
 		case msg, ok := <-in:
 			if !ok {
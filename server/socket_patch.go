@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// patchProtocol is the websocket protocol sending RFC 6902 JSON Patch deltas instead of key/value messages
+const patchProtocol = "patch"
+
+// patchOp is a JSON Patch operation
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// patchMessage is a sequence of operations. The sequence number is incremented with each state change
+// of the hub, snapshots carry the sequence number of the last change included.
+type patchMessage struct {
+	Seq   uint64    `json:"seq"`
+	Patch []patchOp `json:"patch"`
+}
+
+// pointerEscaper escapes JSON Pointer reference tokens
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// patchPath returns the JSON Pointer of the param in the state tree
+func patchPath(p util.Param) string {
+	if p.LoadPoint != nil {
+		return fmt.Sprintf("/loadpoints/%d/%s", *p.LoadPoint, pointerEscaper.Replace(p.Key))
+	}
+	return "/" + pointerEscaper.Replace(p.Key)
+}
+
+// patchValue encodes the param value like the key/value protocol
+func patchValue(v interface{}) json.RawMessage {
+	s, err := encode(v)
+	if err != nil || s == "" {
+		s = "null"
+	}
+	return json.RawMessage(s)
+}
+
+// patchMember encodes the param as object member
+func patchMember(p util.Param) string {
+	key, _ := json.Marshal(p.Key)
+	return string(key) + ":" + string(patchValue(p.Val))
+}
+
+// patchOps returns the operations adding the params to the client's state.
+// Missing loadpoints are appended as empty objects first.
+func (c *SocketClient) patchOps(params []util.Param) []patchOp {
+	var res []patchOp
+
+	for _, p := range params {
+		if p.LoadPoint != nil {
+			for ; c.loadpoints <= *p.LoadPoint; c.loadpoints++ {
+				res = append(res, patchOp{Op: "add", Path: "/loadpoints/-", Value: json.RawMessage("{}")})
+			}
+		}
+
+		// add replaces existing object members
+		res = append(res, patchOp{Op: "add", Path: patchPath(p), Value: patchValue(p.Val)})
+	}
+
+	return res
+}
+
+// patchState returns the operation replacing the client's state with the params
+func (c *SocketClient) patchState(params []util.Param) []patchOp {
+	lps := make(map[int][]util.Param)
+	var site []util.Param
+
+	for _, p := range params {
+		if p.LoadPoint == nil {
+			site = append(site, p)
+		} else {
+			lps[*p.LoadPoint] = append(lps[*p.LoadPoint], p)
+		}
+	}
+
+	c.loadpoints = 0
+	for id := range lps {
+		if id >= c.loadpoints {
+			c.loadpoints = id + 1
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("{")
+
+	for _, p := range site {
+		b.WriteString(patchMember(p))
+		b.WriteString(",")
+	}
+
+	b.WriteString(`"loadpoints":[`)
+	for id := 0; id < c.loadpoints; id++ {
+		if id > 0 {
+			b.WriteString(",")
+		}
+
+		b.WriteString("{")
+		for i, p := range lps[id] {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(patchMember(p))
+		}
+		b.WriteString("}")
+	}
+	b.WriteString("]}")
+
+	return []patchOp{{Op: "replace", Path: "", Value: json.RawMessage(b.String())}}
+}
+
+// encodePatch encodes the patch message
+func encodePatch(seq uint64, ops []patchOp) ([]byte, error) {
+	return json.Marshal(patchMessage{Seq: seq, Patch: ops})
+}
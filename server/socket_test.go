@@ -1,10 +1,13 @@
 package server
 
 import (
+	"encoding/json"
 	"math"
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/evcc-io/evcc/util"
 )
 
 func TestEncode(t *testing.T) {
@@ -13,6 +16,7 @@ func TestEncode(t *testing.T) {
 	}{
 		{int64(1), "1"},
 		{math.NaN(), "null"},
+		{math.Inf(1), "null"},
 		{float64(1.23456), "1.2346"},
 		{"1.2345", "\"1.2345\""},
 		{time.Hour, "3600"},
@@ -33,3 +37,124 @@ func TestEncode(t *testing.T) {
 		}
 	}
 }
+
+func TestTopicMatch(t *testing.T) {
+	tc := []struct {
+		topic, key string
+		match      bool
+	}{
+		{"gridPower", "gridPower", true},
+		{"gridpower", "gridPower", true},
+		{"gridPower", "pvPower", false},
+		{"loadpoints/1/chargePower", "loadpoints.1.chargePower", true},
+		{"loadpoints/1/chargePower", "loadpoints.0.chargePower", false},
+		{"loadpoints/1", "loadpoints.1.chargePower", true},
+		{"loadpoints/+/chargePower", "loadpoints.0.chargePower", true},
+		{"loadpoints/+/chargePower", "loadpoints.0.mode", false},
+		{"loadpoints/#", "loadpoints.0.mode", true},
+		{"loadpoints.1.mode", "loadpoints.1.mode", true},
+		{"loadpoints/1/chargePower/foo", "loadpoints.1.chargePower", false},
+		{"", "gridPower", false},
+	}
+
+	for _, tc := range tc {
+		if res := topicMatch(tc.topic, tc.key); res != tc.match {
+			t.Errorf("%s %s: expected %v, got %v", tc.topic, tc.key, tc.match, res)
+		}
+	}
+}
+
+func TestSubscriptionUpdate(t *testing.T) {
+	c := &SocketClient{all: true}
+
+	if !c.subscribed("gridPower") {
+		t.Error("expected unsubscribed client to receive all keys")
+	}
+
+	if added := c.update(socketRequest{Subscribe: []string{"loadpoints/0", "gridPower", "gridPower"}}); len(added) != 2 {
+		t.Errorf("expected 2 added topics, got %v", added)
+	}
+
+	if c.subscribed("pvPower") || !c.subscribed("loadpoints.0.mode") {
+		t.Error("invalid subscription")
+	}
+
+	c.update(socketRequest{Unsubscribe: []string{"loadpoints/0"}})
+	if c.subscribed("loadpoints.0.mode") {
+		t.Error("expected unsubscribed topic")
+	}
+
+	c.update(socketRequest{Unsubscribe: []string{"gridPower"}})
+	if c.subscribed("gridPower") || c.subscribed("pvPower") {
+		t.Error("expected client without topics to receive no keys")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	lp := 1
+	c := &SocketClient{patch: true}
+
+	state := c.patchState([]util.Param{{Key: "gridPower", Val: 1.5}})
+	if b, _ := encodePatch(1, state); string(b) != `{"seq":1,"patch":[{"op":"replace","path":"","value":{"gridPower":1.5,"loadpoints":[]}}]}` {
+		t.Errorf("invalid state: %s", b)
+	}
+
+	ops := c.patchOps([]util.Param{{LoadPoint: &lp, Key: "mode", Val: "pv"}, {Key: "a/b", Val: time.Minute}})
+	if b, _ := encodePatch(2, ops); string(b) != `{"seq":2,"patch":[`+
+		`{"op":"add","path":"/loadpoints/-","value":{}},{"op":"add","path":"/loadpoints/-","value":{}},`+
+		`{"op":"add","path":"/loadpoints/1/mode","value":"pv"},{"op":"add","path":"/a~1b","value":60}]}` {
+		t.Errorf("invalid patch: %s", b)
+	}
+
+	if ops := c.patchOps([]util.Param{{LoadPoint: &lp, Key: "mode", Val: "now"}}); len(ops) != 1 {
+		t.Errorf("expected single op, got %v", ops)
+	}
+
+	if _, err := encodePatch(3, []patchOp{{Op: "add", Path: "/a", Value: json.RawMessage("+Inf")}}); err == nil {
+		t.Error("expected invalid value error")
+	}
+}
+
+func TestPatchSeq(t *testing.T) {
+	cache := util.NewCache("error")
+	updates := cache.Attach()
+	in := make(chan util.Param)
+	go cache.Run(in)
+
+	h := NewSocketHub()
+	c := &SocketClient{hub: h, send: make(chan []byte, 8), all: true, patch: true}
+
+	// the client's state includes both changes
+	go func() {
+		in <- util.Param{Key: "gridPower", Val: 1.0}
+		in <- util.Param{Key: "gridPower", Val: 2.0}
+	}()
+	stale := <-updates
+	<-updates
+
+	params, seq := cache.Current()
+	h.welcome(c, params, seq)
+	if b := <-c.send; string(b) != `{"seq":2,"patch":[{"op":"replace","path":"","value":{"gridPower":2,"loadpoints":[]}}]}` {
+		t.Errorf("invalid state: %s", b)
+	}
+
+	// older than the client's state
+	h.broadcast(stale)
+
+	go func() { in <- util.Param{Key: "gridPower", Val: 3.0} }()
+	h.broadcast(<-updates)
+	if b := <-c.send; string(b) != `{"seq":3,"patch":[{"op":"add","path":"/gridPower","value":3}]}` {
+		t.Errorf("invalid patch: %s", b)
+	}
+
+	// volatile params are forwarded with the current sequence number
+	go func() { in <- util.Param{Key: "error", Val: "foo"} }()
+	h.broadcast(<-updates)
+	if b := <-c.send; string(b) != `{"seq":3,"patch":[{"op":"add","path":"/error","value":"foo"}]}` {
+		t.Errorf("invalid patch: %s", b)
+	}
+
+	if len(cache.All()) != 1 {
+		t.Errorf("expected volatile param not to be cached, got %v", cache.All())
+	}
+}
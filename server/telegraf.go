@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/state"
+	"github.com/evcc-io/evcc/util"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	protocol "github.com/influxdata/line-protocol"
+	"golang.org/x/exp/slices"
+)
+
+// telegrafKeys are the metrics sent by default
+var telegrafKeys = []string{
+	state.GridPower, state.PvPower, state.BatteryPower, state.BatterySoC, state.HomePower,
+	state.ChargePower, state.ChargedEnergy, state.ChargeCurrents, state.VehicleSoC,
+}
+
+// TelegrafConfig is the telegraf socket output configuration
+type TelegrafConfig struct {
+	URL    string   // udp://host:port, unix:///path or unixgram:///path
+	Format string   // line protocol (line) or json, defaults to line
+	Keys   []string // published keys to send, defaults to key metrics
+}
+
+// Telegraf sends metrics to a telegraf socket listener
+type Telegraf struct {
+	log     *util.Logger
+	network string
+	address string
+	format  string
+	keys    []string
+	conn    net.Conn
+}
+
+// NewTelegraf creates the telegraf socket output
+func NewTelegraf(conf TelegrafConfig) (*Telegraf, error) {
+	u, err := url.Parse(conf.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Telegraf{
+		log:     util.NewLogger("telegraf"),
+		network: u.Scheme,
+		format:  conf.Format,
+		keys:    conf.Keys,
+	}
+
+	switch u.Scheme {
+	case "udp":
+		m.address = u.Host
+	case "unix", "unixgram":
+		m.address = u.Path
+	default:
+		return nil, fmt.Errorf("invalid scheme: %s not in [udp, unix, unixgram]", u.Scheme)
+	}
+
+	switch m.format {
+	case "":
+		m.format = "line"
+	case "line", "json":
+	default:
+		return nil, fmt.Errorf("invalid format: %s not in [line, json]", m.format)
+	}
+
+	if len(m.keys) == 0 {
+		m.keys = telegrafKeys
+	}
+
+	// unix stream sockets may not exist yet, connect lazily
+	if m.network != "unix" {
+		if m.conn, err = net.Dial(m.network, m.address); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// fields converts the value to fields, adding phase values and their total as value
+func (m *Telegraf) fields(val any) (map[string]any, bool) {
+	switch v := val.(type) {
+	case int, int64, float64:
+		return map[string]any{"value": v}, true
+
+	case [3]float64:
+		return m.fields(v[:])
+
+	case []float64:
+		if len(v) != 3 {
+			return nil, false
+		}
+
+		var total float64
+		res := make(map[string]any, len(v)+1)
+		for i, f := range v {
+			total += f
+			res[fmt.Sprintf("l%d", i+1)] = f
+		}
+		res["value"] = total
+
+		return res, true
+
+	default:
+		return nil, false
+	}
+}
+
+// encode returns the metric in the configured format
+func (m *Telegraf) encode(key string, tags map[string]string, fields map[string]any, ts time.Time) ([]byte, error) {
+	if m.format == "line" {
+		var buf bytes.Buffer
+		enc := protocol.NewEncoder(&buf)
+		enc.SetPrecision(time.Second)
+
+		_, err := enc.Encode(write.NewPoint(key, tags, fields, ts))
+		return buf.Bytes(), err
+	}
+
+	// json_name_key = "name", json_time_key = "time", json_time_format = "unix"
+	res := map[string]any{
+		"name": key,
+		"time": ts.Unix(),
+	}
+	for k, v := range tags {
+		res[k] = v
+	}
+	for k, v := range fields {
+		res[k] = v
+	}
+
+	b, err := json.Marshal(res)
+	return append(b, '\n'), err
+}
+
+// send writes the metric, reconnecting after errors
+func (m *Telegraf) send(b []byte) error {
+	if m.conn == nil {
+		conn, err := net.Dial(m.network, m.address)
+		if err != nil {
+			return err
+		}
+		m.conn = conn
+	}
+
+	if _, err := m.conn.Write(b); err != nil {
+		m.conn.Close()
+		m.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// Run Telegraf publisher
+func (m *Telegraf) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
+	// track active vehicle per loadpoint
+	vehicles := make(map[int]string)
+
+	for param := range in {
+		// vehicle name
+		if param.LoadPoint != nil {
+			if name, ok := param.Val.(string); ok && param.Key == state.VehicleTitle {
+				vehicles[*param.LoadPoint] = name
+				continue
+			}
+		}
+
+		if !slices.Contains(m.keys, param.Key) {
+			continue
+		}
+
+		fields, ok := m.fields(param.Val)
+		if !ok {
+			continue
+		}
+
+		tags := map[string]string{}
+		if param.LoadPoint != nil {
+			tags["loadpoint"] = loadPoints[*param.LoadPoint].Name()
+			if vehicle := vehicles[*param.LoadPoint]; vehicle != "" {
+				tags["vehicle"] = vehicle
+			}
+		}
+
+		b, err := m.encode(param.Key, tags, fields, time.Now())
+		if err == nil {
+			err = m.send(b)
+		}
+
+		if err != nil {
+			// log async as we're part of the logging loop
+			go m.log.ERROR.Println(err)
+		}
+	}
+
+	if m.conn != nil {
+		m.conn.Close()
+	}
+}
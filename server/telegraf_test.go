@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type telegrafLoadpoint struct {
+	loadpoint.API
+}
+
+func (lp *telegrafLoadpoint) Name() string {
+	return "garage"
+}
+
+func TestTelegraf(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	read := func() string {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		b := make([]byte, 1024)
+		n, _, err := conn.ReadFrom(b)
+		require.NoError(t, err)
+		return string(b[:n])
+	}
+
+	for format, res := range map[string][]string{
+		"line": {
+			"gridPower value=1000 ",
+			"chargeCurrents,loadpoint=garage,vehicle=ID.3 l1=6,l2=6,l3=6,value=18 ",
+		},
+		"json": {
+			`{"name":"gridPower","time":`,
+			`{"l1":6,"l2":6,"l3":6,"loadpoint":"garage","name":"chargeCurrents","time":`,
+		},
+	} {
+		m, err := NewTelegraf(TelegrafConfig{URL: "udp://" + conn.LocalAddr().String(), Format: format})
+		require.NoError(t, err)
+
+		in := make(chan util.Param)
+		go m.Run([]loadpoint.API{new(telegrafLoadpoint)}, in)
+
+		lp := 0
+		in <- util.Param{Key: "gridPower", Val: 1000.0}
+		in <- util.Param{Key: "pvEnergy", Val: 1.0} // not a key metric
+		in <- util.Param{LoadPoint: &lp, Key: "vehicleTitle", Val: "ID.3"}
+		in <- util.Param{LoadPoint: &lp, Key: "chargeCurrents", Val: []float64{6, 6, 6}}
+		close(in)
+
+		for _, r := range res {
+			assert.Contains(t, read(), r, format)
+		}
+	}
+
+	_, err = NewTelegraf(TelegrafConfig{URL: "tcp://localhost:8094"})
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"sync/atomic"
 
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/gokrazy/updater"
 )
 
@@ -86,23 +87,23 @@ func (u *watch) executeAsync(target *updater.Target, rootFS io.ReadCloser, size
 
 	go func() {
 		for v := range cw.C {
-			u.Send("uploadProgress", 100*v/size)
+			u.Send(state.UploadProgress, 100*v/size)
 		}
-		u.Send("uploadProgress", 100)
+		u.Send(state.UploadProgress, 100)
 	}()
 
-	u.Send("uploadMessage", "uploading")
+	u.Send(state.UploadMessage, "uploading")
 	if err := target.StreamTo("root", io.TeeReader(rootFS, cw)); err != nil {
 		return fmt.Errorf("updating root file system: %w", err)
 	}
 	close(cw.C) // upload finished
 
-	u.Send("uploadMessage", "switching to non-active partition")
+	u.Send(state.UploadMessage, "switching to non-active partition")
 	if err := target.Switch(); err != nil {
 		return fmt.Errorf("switching to non-active partition: %w", err)
 	}
 
-	u.Send("uploadMessage", "rebooting")
+	u.Send(state.UploadMessage, "rebooting")
 	if err := target.Reboot(); err != nil {
 		return fmt.Errorf("reboot: %w", err)
 	}
@@ -3,6 +3,7 @@
 package updater
 
 import (
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/util"
 	"github.com/google/go-github/v32/github"
@@ -20,6 +21,6 @@ func Run(log *util.Logger, httpd webServer, outChan chan<- util.Param) {
 	go u.watchReleases(server.Version, c) // endless
 
 	for rel := range c {
-		u.Send("availableVersion", *rel.TagName)
+		u.Send(state.AvailableVersion, *rel.TagName)
 	}
 }
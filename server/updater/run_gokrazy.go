@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/util"
 	"github.com/google/go-github/v32/github"
@@ -27,11 +28,11 @@ func Run(log *util.Logger, httpd webServer, outChan chan<- util.Param) {
 	go u.watchReleases(server.Version, c) // endless
 
 	// signal update support
-	u.Send("hasUpdater", true)
+	u.Send(state.HasUpdater, true)
 
 	for rel := range c {
 		latest = rel
-		u.Send("availableVersion", *latest.TagName)
+		u.Send(state.AvailableVersion, *latest.TagName)
 	}
 }
 
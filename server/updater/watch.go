@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/evcc-io/evcc/core/state"
 	"github.com/evcc-io/evcc/util"
 	"github.com/google/go-github/v32/github"
 	"github.com/gorilla/mux"
@@ -76,7 +77,7 @@ func (u *watch) findReleaseUpdate(installed string) (*github.RepositoryRelease,
 func (u *watch) fetchReleaseNotes(installed string) {
 	if notes, err := u.repo.ReleaseNotes(installed); err == nil {
 		u.outChan <- util.Param{
-			Key: "releaseNotes",
+			Key: state.ReleaseNotes,
 			Val: notes,
 		}
 	} else {
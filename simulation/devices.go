@@ -0,0 +1,175 @@
+package simulation
+
+import (
+	"math"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Vehicle is a simulated vehicle battery with reduced charge power above 80% soc
+type Vehicle struct {
+	site     *Site
+	capacity float64 // kWh
+	soc      float64 // %
+	power    float64 // W, max charge power
+}
+
+// NewVehicle creates a simulated vehicle battery
+func NewVehicle(capacity, soc, power float64) *Vehicle {
+	return &Vehicle{
+		capacity: capacity,
+		soc:      soc,
+		power:    power,
+	}
+}
+
+// acceptance returns the max charge power at the current soc (no mutex)
+func (v *Vehicle) acceptance() float64 {
+	switch {
+	case v.soc >= 100:
+		return 0
+	case v.soc > 80:
+		return v.power * (100 - v.soc) / 20
+	default:
+		return v.power
+	}
+}
+
+// SoC implements the api.Battery interface
+func (v *Vehicle) SoC() (float64, error) {
+	if v.site == nil {
+		return v.soc, nil
+	}
+
+	v.site.mu.Lock()
+	defer v.site.mu.Unlock()
+
+	v.site.advance()
+	return v.soc, nil
+}
+
+// Charger is a simulated charger with integrated meter
+type Charger struct {
+	site    *Site
+	enabled bool
+	current float64
+	phases  int
+	vehicle *Vehicle
+}
+
+var (
+	_ api.Charger   = (*Charger)(nil)
+	_ api.ChargerEx = (*Charger)(nil)
+	_ api.Meter     = (*Charger)(nil)
+)
+
+// Plug connects the vehicle to the charger
+func (c *Charger) Plug(v *Vehicle) {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+	v.site = c.site
+	c.vehicle = v
+}
+
+// Unplug disconnects the vehicle
+func (c *Charger) Unplug() {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+	c.vehicle = nil
+}
+
+// SetPhases sets the phases used for charging
+func (c *Charger) SetPhases(phases int) {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+	c.phases = phases
+}
+
+// power returns the charge power limited by the vehicle (no mutex)
+func (c *Charger) power() float64 {
+	if c.vehicle == nil || !c.enabled {
+		return 0
+	}
+
+	return math.Min(c.current*float64(c.phases)*Voltage, c.vehicle.acceptance())
+}
+
+// Status implements the api.Charger interface
+func (c *Charger) Status() (api.ChargeStatus, error) {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+
+	switch {
+	case c.vehicle == nil:
+		return api.StatusA, nil
+	case c.power() > 0:
+		return api.StatusC, nil
+	default:
+		return api.StatusB, nil
+	}
+}
+
+// Enabled implements the api.Charger interface
+func (c *Charger) Enabled() (bool, error) {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	return c.enabled, nil
+}
+
+// Enable implements the api.Charger interface
+func (c *Charger) Enable(enable bool) error {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+	c.enabled = enable
+
+	return nil
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *Charger) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (c *Charger) MaxCurrentMillis(current float64) error {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+	c.current = current
+
+	return nil
+}
+
+// CurrentPower implements the api.Meter interface
+func (c *Charger) CurrentPower() (float64, error) {
+	c.site.mu.Lock()
+	defer c.site.mu.Unlock()
+
+	c.site.advance()
+	return c.power(), nil
+}
+
+// PhaseCharger is a simulated charger with phase switching
+type PhaseCharger struct {
+	*Charger
+}
+
+var _ api.PhaseSwitcher = (*PhaseCharger)(nil)
+
+// Phases1p3p implements the api.PhaseSwitcher interface
+func (c *PhaseCharger) Phases1p3p(phases int) error {
+	c.SetPhases(phases)
+	return nil
+}
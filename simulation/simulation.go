@@ -0,0 +1,252 @@
+// Package simulation provides an in-memory installation with pv, home consumption, home battery,
+// chargers and vehicles. Devices of the same simulation share their energy flows, e.g. the grid
+// meter reflects pv production, battery and charging.
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Voltage is the simulated supply voltage
+const Voltage = 230
+
+// Profile is the pv generation profile over the day
+type Profile string
+
+const (
+	ProfileSunny    Profile = "sunny"    // clear sky from 6:00 to 20:00
+	ProfileCloudy   Profile = "cloudy"   // sunny with fluctuating clouds
+	ProfileConstant Profile = "constant" // peak power all day
+)
+
+// ParseProfile parses a pv profile name
+func ParseProfile(s string) (Profile, error) {
+	switch p := Profile(strings.ToLower(s)); p {
+	case ProfileSunny, ProfileCloudy, ProfileConstant:
+		return p, nil
+	case "":
+		return ProfileSunny, nil
+	default:
+		return "", fmt.Errorf("invalid profile: %s", s)
+	}
+}
+
+// factor returns the pv production relative to peak power
+func (p Profile) factor(t time.Time) float64 {
+	if p == ProfileConstant {
+		return 1
+	}
+
+	hour := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	if hour < 6 || hour > 20 {
+		return 0
+	}
+
+	res := math.Sin((hour - 6) / 14 * math.Pi)
+
+	if p == ProfileCloudy {
+		// deterministic clouds passing every couple of minutes
+		minute := hour * 60
+		res *= 0.6 + 0.4*math.Sin(minute/7)*math.Sin(minute/3)
+	}
+
+	return math.Max(0, res)
+}
+
+// Battery is a simulated home battery
+type Battery struct {
+	Capacity float64 // kWh
+	SoC      float64 // %
+	Power    float64 // W, max charge and discharge power
+	MinSoC   float64 // %, discharging stops below
+}
+
+// Site is a simulated installation
+type Site struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	updated  time.Time
+	pvPeak   float64
+	profile  Profile
+	home     float64
+	battery  *Battery
+	chargers map[string]*Charger
+}
+
+var (
+	mu       sync.Mutex
+	registry = make(map[string]*Site)
+)
+
+// Instance returns the shared simulation of the given name
+func Instance(name string) *Site {
+	mu.Lock()
+	defer mu.Unlock()
+
+	s, ok := registry[name]
+	if !ok {
+		s = NewSite(clock.New())
+		registry[name] = s
+	}
+
+	return s
+}
+
+// NewSite creates a simulation advancing with the given clock
+func NewSite(clock clock.Clock) *Site {
+	return &Site{
+		clock:    clock,
+		updated:  clock.Now(),
+		profile:  ProfileSunny,
+		chargers: make(map[string]*Charger),
+	}
+}
+
+// SetPV configures the pv peak power in W and profile
+func (s *Site) SetPV(peak float64, profile Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	s.pvPeak = peak
+	s.profile = profile
+}
+
+// SetHome configures the household consumption in W excluding charging
+func (s *Site) SetHome(power float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	s.home = power
+}
+
+// SetBattery configures the home battery
+func (s *Site) SetBattery(b Battery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	s.battery = &b
+}
+
+// Charger returns the charger of the given id, creating it with 3 phases if required
+func (s *Site) Charger(id string) *Charger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.chargers[id]
+	if !ok {
+		c = &Charger{site: s, phases: 3}
+		s.chargers[id] = c
+	}
+
+	return c
+}
+
+// advance integrates the energy flows since the last update (no mutex)
+func (s *Site) advance() {
+	now := s.clock.Now()
+	hours := now.Sub(s.updated).Hours()
+	s.updated = now
+
+	if hours <= 0 {
+		return
+	}
+
+	for _, c := range s.chargers {
+		if v := c.vehicle; v != nil && v.capacity > 0 {
+			v.soc = math.Min(100, v.soc+c.power()*hours/1e3/v.capacity*100)
+		}
+	}
+
+	if b := s.battery; b != nil && b.Capacity > 0 {
+		b.SoC = math.Max(0, math.Min(100, b.SoC-s.batteryPower()*hours/1e3/b.Capacity*100))
+	}
+}
+
+// pvPower returns the pv production (no mutex)
+func (s *Site) pvPower() float64 {
+	return s.pvPeak * s.profile.factor(s.clock.Now())
+}
+
+// chargePower returns the total charge power (no mutex)
+func (s *Site) chargePower() float64 {
+	var res float64
+	for _, c := range s.chargers {
+		res += c.power()
+	}
+	return res
+}
+
+// batteryPower returns the battery power, charging the surplus and discharging the deficit (no mutex)
+func (s *Site) batteryPower() float64 {
+	b := s.battery
+	if b == nil {
+		return 0
+	}
+
+	surplus := s.pvPower() - s.home - s.chargePower()
+
+	switch {
+	case surplus > 0 && b.SoC < 100:
+		return -math.Min(surplus, b.Power)
+	case surplus < 0 && b.SoC > b.MinSoC:
+		return math.Min(-surplus, b.Power)
+	default:
+		return 0
+	}
+}
+
+// PVPower returns the pv production in W
+func (s *Site) PVPower() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	return s.pvPower()
+}
+
+// HomePower returns the household consumption in W
+func (s *Site) HomePower() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.home
+}
+
+// BatteryPower returns the battery power in W, positive when discharging
+func (s *Site) BatteryPower() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	return s.batteryPower()
+}
+
+// BatterySoC returns the battery soc in %
+func (s *Site) BatterySoC() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	if s.battery == nil {
+		return 0
+	}
+	return s.battery.SoC
+}
+
+// GridPower returns the grid power in W, negative when exporting
+func (s *Site) GridPower() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	return s.home + s.chargePower() - s.pvPower() - s.batteryPower()
+}
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	noon := time.Date(2022, 6, 1, 13, 0, 0, 0, time.UTC)
+	night := time.Date(2022, 6, 1, 22, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 1.0, ProfileSunny.factor(noon))
+	assert.Equal(t, 0.0, ProfileSunny.factor(night))
+	assert.Equal(t, 1.0, ProfileConstant.factor(night))
+	assert.LessOrEqual(t, ProfileCloudy.factor(noon), 1.0)
+
+	_, err := ParseProfile("foo")
+	assert.Error(t, err)
+}
+
+func TestSite(t *testing.T) {
+	clck := clock.NewMock()
+	s := NewSite(clck)
+	s.SetPV(5000, ProfileConstant)
+	s.SetHome(1000)
+
+	c := s.Charger("foo")
+	v := NewVehicle(10, 50, 11000)
+
+	status, _ := c.Status()
+	assert.Equal(t, api.StatusA, status)
+	assert.Equal(t, -4000.0, s.GridPower())
+
+	c.Plug(v)
+	_ = c.MaxCurrent(10)
+	_ = c.Enable(true)
+
+	status, _ = c.Status()
+	assert.Equal(t, api.StatusC, status)
+	assert.Equal(t, 2900.0, s.GridPower())
+
+	// 6.9kW for 30 minutes
+	clck.Add(30 * time.Minute)
+	soc, _ := v.SoC()
+	assert.InDelta(t, 84.5, soc, 0.1)
+
+	// charge power reduced above 80%
+	_ = c.MaxCurrent(16)
+	power, _ := c.CurrentPower()
+	assert.InDelta(t, 11000*(100-soc)/20, power, 1)
+
+	// battery absorbs surplus
+	_ = c.Enable(false)
+	s.SetBattery(Battery{Capacity: 10, SoC: 50, Power: 3000})
+	assert.Equal(t, -3000.0, s.BatteryPower())
+	assert.Equal(t, -1000.0, s.GridPower())
+
+	clck.Add(time.Hour)
+	assert.Equal(t, 80.0, s.BatterySoC())
+}
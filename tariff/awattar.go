@@ -21,7 +21,10 @@ type Awattar struct {
 	data  []awattar.PriceInfo
 }
 
-var _ api.Tariff = (*Awattar)(nil)
+var (
+	_ api.Tariff = (*Awattar)(nil)
+	_ api.Rater  = (*Awattar)(nil)
+)
 
 func NewAwattar(other map[string]interface{}) (*Awattar, error) {
 	cc := struct {
@@ -81,3 +84,20 @@ func (t *Awattar) IsCheap() (bool, error) {
 	price, err := t.CurrentPrice()
 	return price <= t.cheap, err
 }
+
+// Rates implements the api.Rater interface
+func (t *Awattar) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	res := make(api.Rates, 0, len(t.data))
+	for _, pi := range t.data {
+		res = append(res, api.Rate{
+			Start: pi.StartTimestamp,
+			End:   pi.EndTimestamp,
+			Price: pi.Marketprice / 1000, // convert EUR/MWh to EUR/KWh
+		})
+	}
+
+	return res, nil
+}
@@ -16,6 +16,10 @@ func NewFromConfig(typ string, other map[string]interface{}) (t api.Tariff, err
 		t, err = NewAwattar(other)
 	case "tibber":
 		t, err = NewTibber(other)
+	case "entsoe":
+		t, err = NewEntsoe(other)
+	case "electricitymaps":
+		t, err = NewElectricityMaps(other)
 	default:
 		return nil, errors.New("unknown tariff: " + typ)
 	}
@@ -0,0 +1,195 @@
+package tariff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/text/currency"
+)
+
+// ecbURI provides the daily ECB euro foreign exchange reference rates
+const ecbURI = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// converted converts the prices of a tariff reporting in a different currency
+type converted struct {
+	api.Tariff
+	rate func() (float64, error)
+}
+
+// convertedRater is the converted tariff of a provider with dynamic rates
+type convertedRater struct {
+	*converted
+	rater api.Rater
+}
+
+// NewConvertedFromConfig creates the tariff from config. Prices of providers reporting
+// in a currency other than the target currency are converted using the configured
+// exchange rate or the ECB reference rates.
+func NewConvertedFromConfig(typ string, other map[string]interface{}, target currency.Unit) (api.Tariff, error) {
+	var cc struct {
+		Currency     string
+		ExchangeRate float64
+		Other        map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	t, err := NewFromConfig(typ, cc.Other)
+	if err != nil || cc.Currency == "" {
+		return t, err
+	}
+
+	from, err := currency.ParseISO(cc.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == target {
+		return t, nil
+	}
+
+	rate := func() (float64, error) {
+		return cc.ExchangeRate, nil
+	}
+
+	if cc.ExchangeRate <= 0 {
+		rate = ecbRate(from, target)
+	}
+
+	return NewConverted(t, rate), nil
+}
+
+// NewConverted multiplies the tariff's prices by the exchange rate
+func NewConverted(t api.Tariff, rate func() (float64, error)) api.Tariff {
+	res := &converted{
+		Tariff: t,
+		rate:   rate,
+	}
+
+	if r, ok := t.(api.Rater); ok {
+		return &convertedRater{
+			converted: res,
+			rater:     r,
+		}
+	}
+
+	return res
+}
+
+// CurrentPrice implements the api.Tariff interface
+func (t *converted) CurrentPrice() (float64, error) {
+	price, err := t.Tariff.CurrentPrice()
+	if err != nil {
+		return 0, err
+	}
+
+	rate, err := t.rate()
+	return price * rate, err
+}
+
+// Rates implements the api.Rater interface
+func (t *convertedRater) Rates() (api.Rates, error) {
+	rates, err := t.rater.Rates()
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := t.rate()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(api.Rates, 0, len(rates))
+	for _, r := range rates {
+		r.Price *= rate
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// ecbRates are the cached ECB reference rates, shared by all converted tariffs
+var ecbRates = struct {
+	sync.Mutex
+	log     *util.Logger
+	rates   map[string]float64
+	updated time.Time
+}{
+	log: util.NewLogger("ecb"),
+}
+
+// ecbRate returns the exchange rate provider based on the ECB reference rates, updated daily
+func ecbRate(from, to currency.Unit) func() (float64, error) {
+	return func() (float64, error) {
+		ecbRates.Lock()
+		defer ecbRates.Unlock()
+
+		if time.Since(ecbRates.updated) > 24*time.Hour {
+			b, err := request.NewHelper(ecbRates.log).GetBody(ecbURI)
+			if err == nil {
+				var rates map[string]float64
+				if rates, err = decodeECB(b); err == nil {
+					ecbRates.rates = rates
+					ecbRates.updated = time.Now()
+				}
+			}
+
+			// continue using outdated rates
+			if err != nil && ecbRates.rates == nil {
+				return 0, err
+			}
+		}
+
+		return exchangeRate(ecbRates.rates, from, to)
+	}
+}
+
+// exchangeRate converts using euro based rates
+func exchangeRate(rates map[string]float64, from, to currency.Unit) (float64, error) {
+	rate := func(c currency.Unit) (float64, error) {
+		if c == currency.EUR {
+			return 1, nil
+		}
+		if r, ok := rates[c.String()]; ok && r > 0 {
+			return r, nil
+		}
+		return 0, fmt.Errorf("no exchange rate for %s", c)
+	}
+
+	f, err := rate(from)
+	if err != nil {
+		return 0, err
+	}
+
+	t, err := rate(to)
+	return t / f, err
+}
+
+// decodeECB decodes the ECB reference rates document
+func decodeECB(b []byte) (map[string]float64, error) {
+	var doc struct {
+		Cube []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube>Cube>Cube"`
+	}
+
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]float64, len(doc.Cube))
+	for _, c := range doc.Cube {
+		res[strings.ToUpper(c.Currency)] = c.Rate
+	}
+
+	return res, nil
+}
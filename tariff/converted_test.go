@@ -0,0 +1,88 @@
+package tariff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/currency"
+)
+
+type rater struct {
+	Fixed
+	rates api.Rates
+}
+
+func (t *rater) Rates() (api.Rates, error) {
+	return t.rates, nil
+}
+
+func TestConverted(t *testing.T) {
+	rate := func() (float64, error) { return 11.5, nil }
+
+	tf := NewConverted(&Fixed{Price: 0.2}, rate)
+	_, ok := tf.(api.Rater)
+	assert.False(t, ok)
+
+	price, err := tf.CurrentPrice()
+	require.NoError(t, err)
+	assert.InDelta(t, 2.3, price, 1e-9)
+
+	now := time.Now()
+	tr := NewConverted(&rater{rates: api.Rates{{Start: now, End: now.Add(time.Hour), Price: 0.1}}}, rate)
+	r, ok := tr.(api.Rater)
+	require.True(t, ok)
+
+	rates, err := r.Rates()
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.InDelta(t, 1.15, rates[0].Price, 1e-9)
+}
+
+func TestConvertedFromConfig(t *testing.T) {
+	// same currency is not converted
+	tf, err := NewConvertedFromConfig("fixed", map[string]interface{}{"price": 0.2, "currency": "EUR"}, currency.EUR)
+	require.NoError(t, err)
+	assert.IsType(t, new(Fixed), tf)
+
+	tf, err = NewConvertedFromConfig("fixed", map[string]interface{}{"price": 0.2, "currency": "EUR", "exchangerate": 10}, currency.NOK)
+	require.NoError(t, err)
+
+	price, err := tf.CurrentPrice()
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, price, 1e-9)
+
+	_, err = NewConvertedFromConfig("fixed", map[string]interface{}{"price": 0.2, "currency": "foo"}, currency.EUR)
+	assert.Error(t, err)
+}
+
+func TestECB(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2022-11-18">
+			<Cube currency="USD" rate="1.0365"/>
+			<Cube currency="NOK" rate="10.4560"/>
+			<Cube currency="SEK" rate="10.9195"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+	rates, err := decodeECB([]byte(doc))
+	require.NoError(t, err)
+	assert.Equal(t, 10.456, rates["NOK"])
+
+	res, err := exchangeRate(rates, currency.EUR, currency.NOK)
+	require.NoError(t, err)
+	assert.Equal(t, 10.456, res)
+
+	res, err = exchangeRate(rates, currency.SEK, currency.NOK)
+	require.NoError(t, err)
+	assert.InDelta(t, 10.456/10.9195, res, 1e-9)
+
+	_, err = exchangeRate(rates, currency.EUR, currency.CHF)
+	assert.Error(t, err)
+}
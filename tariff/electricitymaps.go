@@ -0,0 +1,115 @@
+package tariff
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// ElectricityMaps provides the live grid carbon intensity in gCO2eq/kWh
+type ElectricityMaps struct {
+	mux     sync.Mutex
+	log     *util.Logger
+	uri     string
+	token   string
+	zone    string
+	cheap   float64
+	data    float64
+	updated time.Time
+}
+
+var _ api.Tariff = (*ElectricityMaps)(nil)
+
+type electricityMapsIntensity struct {
+	Zone            string
+	CarbonIntensity float64
+	Datetime        time.Time
+	Error           string
+}
+
+func NewElectricityMaps(other map[string]interface{}) (*ElectricityMaps, error) {
+	cc := struct {
+		Uri   string
+		Token string
+		Zone  string
+		Cheap float64
+	}{
+		Uri: "https://api.electricitymap.org/v3",
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Zone == "" {
+		return nil, errors.New("missing zone")
+	}
+
+	t := &ElectricityMaps{
+		log:   util.NewLogger("electricitymaps").Redact(cc.Token),
+		uri:   strings.TrimSuffix(cc.Uri, "/"),
+		token: cc.Token,
+		zone:  cc.Zone,
+		cheap: cc.Cheap,
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+func (t *ElectricityMaps) Run() {
+	client := request.NewHelper(t.log)
+	uri := fmt.Sprintf("%s/carbon-intensity/latest?zone=%s", t.uri, url.QueryEscape(t.zone))
+
+	for ; true; <-time.NewTicker(15 * time.Minute).C {
+		req, err := request.New(http.MethodGet, uri, nil, map[string]string{
+			"auth-token": t.token,
+		})
+
+		var res electricityMapsIntensity
+		if err == nil {
+			err = client.DoJSON(req, &res)
+		}
+
+		if err == nil && res.Error != "" {
+			err = errors.New(res.Error)
+		}
+
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		t.mux.Lock()
+		t.data = res.CarbonIntensity
+		t.updated = time.Now()
+		t.mux.Unlock()
+	}
+}
+
+// CurrentPrice implements the api.Tariff interface and returns the carbon intensity in gCO2eq/kWh
+func (t *ElectricityMaps) CurrentPrice() (float64, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if time.Since(t.updated) > 2*time.Hour {
+		return 0, errors.New("outdated carbon intensity")
+	}
+
+	return t.data, nil
+}
+
+// IsCheap returns true if the carbon intensity is below the configured limit
+func (t *ElectricityMaps) IsCheap() (bool, error) {
+	intensity, err := t.CurrentPrice()
+	return intensity <= t.cheap, err
+}
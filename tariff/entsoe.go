@@ -0,0 +1,129 @@
+package tariff
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/tariff/entsoe"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+type Entsoe struct {
+	mux    sync.Mutex
+	log    *util.Logger
+	token  string
+	domain string
+	cheap  float64
+	data   []entsoe.Price
+}
+
+var (
+	_ api.Tariff = (*Entsoe)(nil)
+	_ api.Rater  = (*Entsoe)(nil)
+)
+
+func NewEntsoe(other map[string]interface{}) (*Entsoe, error) {
+	var cc struct {
+		SecurityToken string
+		Domain        string
+		Cheap         float64
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.SecurityToken == "" || cc.Domain == "" {
+		return nil, errors.New("missing securitytoken or domain")
+	}
+
+	t := &Entsoe{
+		log:    util.NewLogger("entsoe").Redact(cc.SecurityToken),
+		token:  cc.SecurityToken,
+		domain: cc.Domain,
+		cheap:  cc.Cheap,
+	}
+
+	go t.Run()
+
+	return t, nil
+}
+
+func (t *Entsoe) Run() {
+	client := request.NewHelper(t.log)
+
+	for ; true; <-time.NewTicker(time.Hour).C {
+		// day-ahead prices are published for the next day around noon
+		start := time.Now().UTC().Truncate(time.Hour)
+
+		params := url.Values{
+			"securityToken": {t.token},
+			"documentType":  {entsoe.DayAheadPricesDocument},
+			"in_Domain":     {t.domain},
+			"out_Domain":    {t.domain},
+			"periodStart":   {start.Format(entsoe.TimeFormat)},
+			"periodEnd":     {start.AddDate(0, 0, 2).Format(entsoe.TimeFormat)},
+		}
+
+		resp, err := client.Get(entsoe.URI + "?" + params.Encode())
+		if err != nil {
+			t.log.ERROR.Println(err)
+			continue
+		}
+
+		// error details are returned as acknowledgement document
+		b, err := request.ReadBody(resp)
+		if len(b) > 0 {
+			var data []entsoe.Price
+			if data, err = entsoe.Decode(b); err == nil {
+				t.mux.Lock()
+				t.data = data
+				t.mux.Unlock()
+			}
+		}
+
+		if err != nil {
+			t.log.ERROR.Println(err)
+		}
+	}
+}
+
+func (t *Entsoe) CurrentPrice() (float64, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	now := time.Now()
+	for _, p := range t.data {
+		if !now.Before(p.Start) && now.Before(p.End) {
+			return p.Price / 1e3, nil // convert EUR/MWh to EUR/KWh
+		}
+	}
+
+	return 0, errors.New("unable to find current entsoe price")
+}
+
+func (t *Entsoe) IsCheap() (bool, error) {
+	price, err := t.CurrentPrice()
+	return price <= t.cheap, err
+}
+
+// Rates implements the api.Rater interface
+func (t *Entsoe) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	res := make(api.Rates, 0, len(t.data))
+	for _, p := range t.data {
+		res = append(res, api.Rate{
+			Start: p.Start,
+			End:   p.End,
+			Price: p.Price / 1e3, // convert EUR/MWh to EUR/KWh
+		})
+	}
+
+	return res, nil
+}
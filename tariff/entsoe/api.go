@@ -0,0 +1,114 @@
+package entsoe
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// URI is the ENTSO-E transparency platform api
+const URI = "https://web-api.tp.entsoe.eu/api"
+
+// TimeFormat is the api's period query format
+const TimeFormat = "200601021504"
+
+// DayAheadPricesDocument is the document type of day-ahead prices
+const DayAheadPricesDocument = "A44"
+
+// PublicationMarketDocument is the day-ahead prices response
+type PublicationMarketDocument struct {
+	XMLName    xml.Name     `xml:"Publication_MarketDocument"`
+	TimeSeries []TimeSeries `xml:"TimeSeries"`
+}
+
+// AcknowledgementMarketDocument is the error response
+type AcknowledgementMarketDocument struct {
+	XMLName xml.Name `xml:"Acknowledgement_MarketDocument"`
+	Reason  struct {
+		Code string `xml:"code"`
+		Text string `xml:"text"`
+	} `xml:"Reason"`
+}
+
+// TimeSeries is a series of prices
+type TimeSeries struct {
+	Currency    string   `xml:"currency_Unit.name"`
+	MeasureUnit string   `xml:"price_Measure_Unit.name"`
+	Period      []Period `xml:"Period"`
+}
+
+// Period is a time interval of regular price points
+type Period struct {
+	TimeInterval struct {
+		Start string `xml:"start"`
+		End   string `xml:"end"`
+	} `xml:"timeInterval"`
+	Resolution string  `xml:"resolution"`
+	Point      []Point `xml:"Point"`
+}
+
+// Point is the price at a position within the period
+type Point struct {
+	Position int     `xml:"position"`
+	Price    float64 `xml:"price.amount"`
+}
+
+// Price is the price for a time slot in currency per MWh
+type Price struct {
+	Start, End time.Time
+	Price      float64
+}
+
+// resolution parses ISO 8601 durations like PT15M or PT60M
+func resolution(s string) (time.Duration, error) {
+	if !strings.HasPrefix(s, "PT") {
+		return 0, fmt.Errorf("invalid resolution: %s", s)
+	}
+	return time.ParseDuration(strings.ToLower(strings.TrimPrefix(s, "PT")))
+}
+
+// Decode parses the api response into prices
+func Decode(b []byte) ([]Price, error) {
+	var ack AcknowledgementMarketDocument
+	if err := xml.Unmarshal(b, &ack); err == nil {
+		return nil, errors.New(ack.Reason.Text)
+	}
+
+	var doc PublicationMarketDocument
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	var res []Price
+
+	for _, ts := range doc.TimeSeries {
+		if ts.MeasureUnit != "" && ts.MeasureUnit != "MWH" {
+			return nil, fmt.Errorf("invalid unit: %s", ts.MeasureUnit)
+		}
+
+		for _, p := range ts.Period {
+			start, err := time.Parse("2006-01-02T15:04Z", p.TimeInterval.Start)
+			if err != nil {
+				return nil, err
+			}
+
+			d, err := resolution(p.Resolution)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, pt := range p.Point {
+				ts := start.Add(time.Duration(pt.Position-1) * d)
+				res = append(res, Price{
+					Start: ts,
+					End:   ts.Add(d),
+					Price: pt.Price,
+				})
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,44 @@
+package entsoe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<Publication_MarketDocument xmlns="urn:iec62325.351:tc57wg16:451-3:publicationdocument:7:0">
+	<TimeSeries>
+		<currency_Unit.name>EUR</currency_Unit.name>
+		<price_Measure_Unit.name>MWH</price_Measure_Unit.name>
+		<Period>
+			<timeInterval>
+				<start>2022-10-13T22:00Z</start>
+				<end>2022-10-14T00:00Z</end>
+			</timeInterval>
+			<resolution>PT60M</resolution>
+			<Point><position>1</position><price.amount>250.50</price.amount></Point>
+			<Point><position>2</position><price.amount>199.00</price.amount></Point>
+		</Period>
+	</TimeSeries>
+</Publication_MarketDocument>`
+
+	res, err := Decode([]byte(doc))
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	start := time.Date(2022, 10, 13, 22, 0, 0, 0, time.UTC)
+	assert.Equal(t, Price{Start: start, End: start.Add(time.Hour), Price: 250.5}, res[0])
+	assert.Equal(t, start.Add(time.Hour), res[1].Start)
+	assert.Equal(t, 199.0, res[1].Price)
+
+	ack := `<Acknowledgement_MarketDocument xmlns="urn:iec62325.351:tc57wg16:451-1:acknowledgementdocument:7:0">
+	<Reason><code>999</code><text>No matching data found</text></Reason>
+</Acknowledgement_MarketDocument>`
+
+	_, err = Decode([]byte(ack))
+	assert.EqualError(t, err, "No matching data found")
+}
@@ -9,6 +9,7 @@ type Tariffs struct {
 	Currency currency.Unit
 	Grid     api.Tariff
 	FeedIn   api.Tariff
+	Co2      api.Tariff // grid carbon intensity in gCO2eq/kWh
 }
 
 var _ api.Tariff = (*Fixed)(nil)
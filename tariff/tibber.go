@@ -22,7 +22,10 @@ type Tibber struct {
 	data   []tibber.PriceInfo
 }
 
-var _ api.Tariff = (*Tibber)(nil)
+var (
+	_ api.Tariff = (*Tibber)(nil)
+	_ api.Rater  = (*Tibber)(nil)
+)
 
 func NewTibber(other map[string]interface{}) (*Tibber, error) {
 	var cc struct {
@@ -82,7 +85,8 @@ func (t *Tibber) Run() {
 		}
 
 		t.mux.Lock()
-		t.data = res.Viewer.Home.CurrentSubscription.PriceInfo.Today
+		pi := res.Viewer.Home.CurrentSubscription.PriceInfo
+		t.data = append(pi.Today, pi.Tomorrow...)
 		t.mux.Unlock()
 	}
 }
@@ -105,3 +109,20 @@ func (t *Tibber) IsCheap() (bool, error) {
 	price, err := t.CurrentPrice()
 	return price <= t.cheap, err
 }
+
+// Rates implements the api.Rater interface
+func (t *Tibber) Rates() (api.Rates, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	res := make(api.Rates, 0, len(t.data))
+	for _, pi := range t.data {
+		res = append(res, api.Rate{
+			Start: pi.StartsAt,
+			End:   pi.StartsAt.Add(time.Hour),
+			Price: pi.Total,
+		})
+	}
+
+	return res, nil
+}
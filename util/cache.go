@@ -3,45 +3,151 @@ package util
 import (
 	"fmt"
 	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
 )
 
+// TimelineSize is the number of recent values kept in the cache's timeline
+const TimelineSize = 20000
+
+// TimedParam is a param with its publishing time
+type TimedParam struct {
+	Time      time.Time   `json:"time"`
+	LoadPoint *int        `json:"loadpoint,omitempty"`
+	Key       string      `json:"key"`
+	Val       interface{} `json:"val"`
+}
+
+// Snapshot is the current state and recent timeline used for reproducing control decisions
+type Snapshot struct {
+	Created  time.Time              `json:"created"`
+	Version  string                 `json:"version"`
+	State    map[string]interface{} `json:"state"`
+	Timeline []TimedParam           `json:"timeline"`
+}
+
+// Update is a param forwarded by the cache with the sequence number of the state it has been applied to
+type Update struct {
+	Param
+	Seq uint64
+}
+
 // Cache is a data store
 type Cache struct {
 	sync.Mutex
-	val map[string]Param
+	val      map[string]Param
+	timeline []TimedParam
+	next     int    // ring buffer position
+	seq      uint64 // incremented on each change
+	volatile []string
+	recv     []chan<- Update
 }
 
-// NewCache creates cache
-func NewCache() *Cache {
+// NewCache creates cache. Params with volatile keys like log messages are forwarded but not cached.
+func NewCache(volatile ...string) *Cache {
 	return &Cache{
-		val: make(map[string]Param),
+		val:      make(map[string]Param),
+		timeline: make([]TimedParam, 0, TimelineSize),
+		volatile: volatile,
 	}
 }
 
-// Run adds input channel's values to cache
+// Attach creates a receiver of the params and their sequence number. Must be attached before running the cache.
+func (c *Cache) Attach() <-chan Update {
+	out := make(chan Update)
+	c.recv = append(c.recv, out)
+	return out
+}
+
+// Run adds input channel's values to cache and forwards them to the receivers
 func (c *Cache) Run(in <-chan Param) {
 	log := NewLogger("cache")
 
 	for p := range in {
-		key := p.Key
-		if p.LoadPoint != nil {
-			key = fmt.Sprintf("lp-%d/%s", *p.LoadPoint+1, key)
+		seq := c.Seq()
+
+		if !slices.Contains(c.volatile, p.Key) {
+			key := p.Key
+			if p.LoadPoint != nil {
+				key = fmt.Sprintf("lp-%d/%s", *p.LoadPoint+1, key)
+			}
+			log.TRACE.Printf("%s: %v", key, p.Val)
+			seq = c.add(p.UniqueID(), p)
+			c.record(time.Now(), p)
+		}
+
+		for _, recv := range c.recv {
+			recv <- Update{Param: p, Seq: seq}
 		}
-		log.TRACE.Printf("%s: %v", key, p.Val)
-		c.Add(p.UniqueID(), p)
 	}
 }
 
+// record adds the param to the timeline, replacing the oldest entry when full
+func (c *Cache) record(ts time.Time, p Param) {
+	c.Lock()
+	defer c.Unlock()
+
+	tp := TimedParam{Time: ts, LoadPoint: p.LoadPoint, Key: p.Key, Val: p.Val}
+
+	if len(c.timeline) < cap(c.timeline) {
+		c.timeline = append(c.timeline, tp)
+		return
+	}
+
+	c.timeline[c.next] = tp
+	c.next = (c.next + 1) % len(c.timeline)
+}
+
+// Timeline provides a chronological copy of the recent values
+func (c *Cache) Timeline() []TimedParam {
+	c.Lock()
+	defer c.Unlock()
+
+	res := make([]TimedParam, 0, len(c.timeline))
+	res = append(res, c.timeline[c.next:]...)
+	res = append(res, c.timeline[:c.next]...)
+
+	return res
+}
+
 // State provides a structured copy of the cached values
 // Loadpoints are aggregated as loadpoints array
 func (c *Cache) State() map[string]interface{} {
+	res, _ := c.Tree()
+	return res
+}
+
+// Tree provides a structured copy of the cached values and the sequence number of the last change
+func (c *Cache) Tree() (map[string]interface{}, uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	return StateTree(c.val), c.seq
+}
+
+// Seq returns the sequence number of the last change
+func (c *Cache) Seq() uint64 {
 	c.Lock()
 	defer c.Unlock()
 
+	return c.seq
+}
+
+// Current provides a copy of the cached values and the sequence number of the last change
+func (c *Cache) Current() ([]Param, uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.all(), c.seq
+}
+
+// StateTree aggregates the params as state tree with loadpoints as loadpoints array
+func StateTree(params map[string]Param) map[string]interface{} {
 	res := map[string]interface{}{}
 	lps := make(map[int]map[string]interface{})
 
-	for _, param := range c.val {
+	for _, param := range params {
 		if param.LoadPoint == nil {
 			res[param.Key] = param.Val
 		} else {
@@ -54,10 +160,21 @@ func (c *Cache) State() map[string]interface{} {
 		}
 	}
 
-	// convert map to array
-	loadpoints := make([]map[string]interface{}, len(lps))
-	for id, lp := range lps {
-		loadpoints[id] = lp
+	// convert map to array, loadpoints without values are empty
+	var count int
+	for id := range lps {
+		if id >= count {
+			count = id + 1
+		}
+	}
+
+	loadpoints := make([]map[string]interface{}, count)
+	for id := range loadpoints {
+		if lp, ok := lps[id]; ok {
+			loadpoints[id] = lp
+		} else {
+			loadpoints[id] = make(map[string]interface{})
+		}
 	}
 	res["loadpoints"] = loadpoints
 
@@ -69,6 +186,10 @@ func (c *Cache) All() []Param {
 	c.Lock()
 	defer c.Unlock()
 
+	return c.all()
+}
+
+func (c *Cache) all() []Param {
 	copy := make([]Param, 0, len(c.val))
 	for _, val := range c.val {
 		copy = append(copy, val)
@@ -79,10 +200,18 @@ func (c *Cache) All() []Param {
 
 // Add entry to cache
 func (c *Cache) Add(key string, param Param) {
+	c.add(key, param)
+}
+
+// add adds the entry and returns the sequence number of the change
+func (c *Cache) add(key string, param Param) uint64 {
 	c.Lock()
 	defer c.Unlock()
 
 	c.val[key] = param
+	c.seq++
+
+	return c.seq
 }
 
 // Get entry from cache
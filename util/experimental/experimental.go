@@ -0,0 +1,113 @@
+package experimental
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// Flag identifies an experimental subsystem
+type Flag string
+
+// Experimental subsystems, disabled by default
+const (
+	Planner Flag = "planner"
+	V2G     Flag = "v2g"
+	EEBus   Flag = "eebus"
+)
+
+// Flags are the known experimental subsystems
+var Flags = []Flag{Planner, V2G, EEBus}
+
+const settingPrefix = "experimental."
+
+var (
+	mu       sync.Mutex
+	defaults = make(map[Flag]bool)
+	out      chan<- util.Param
+)
+
+// FlagString parses the flag name
+func FlagString(s string) (Flag, error) {
+	f := Flag(strings.ToLower(s))
+	if !slices.Contains(Flags, f) {
+		return "", fmt.Errorf("invalid experimental flag: %s", s)
+	}
+	return f, nil
+}
+
+// Configure sets the flags enabled by configuration, runtime settings take precedence
+func Configure(conf map[string]bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for k, v := range conf {
+		f, err := FlagString(k)
+		if err != nil {
+			return err
+		}
+		defaults[f] = v
+	}
+
+	return nil
+}
+
+// enabled returns the flag's state (no mutex)
+func enabled(f Flag) bool {
+	if v, err := settings.Bool(settingPrefix + string(f)); err == nil {
+		return v
+	}
+	return defaults[f]
+}
+
+// Enabled returns if the experimental subsystem is enabled
+func Enabled(f Flag) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled(f)
+}
+
+// Enable toggles the experimental subsystem at runtime and persists the setting
+func Enable(f Flag, enable bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	settings.SetBool(settingPrefix+string(f), enable)
+	publish()
+}
+
+// All returns the state of all flags
+func All() map[Flag]bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return all()
+}
+
+// all returns the state of all flags (no mutex)
+func all() map[Flag]bool {
+	res := make(map[Flag]bool, len(Flags))
+	for _, f := range Flags {
+		res[f] = enabled(f)
+	}
+	return res
+}
+
+// publish sends the flags to the state cache (no mutex)
+func publish() {
+	if out != nil {
+		out <- util.Param{Key: "experimental", Val: all()}
+	}
+}
+
+// Publish sends the flags to the channel whenever they change
+func Publish(ch chan<- util.Param) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	out = ch
+	publish()
+}
@@ -0,0 +1,29 @@
+package experimental
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimental(t *testing.T) {
+	assert.Error(t, Configure(map[string]bool{"foo": true}))
+	require.NoError(t, Configure(map[string]bool{"planner": true}))
+
+	assert.True(t, Enabled(Planner), "enabled by config")
+	assert.False(t, Enabled(V2G), "disabled by default")
+
+	ch := make(chan util.Param, 1)
+	Publish(ch)
+	assert.Equal(t, map[Flag]bool{Planner: true, V2G: false, EEBus: false}, (<-ch).Val)
+
+	// runtime setting takes precedence
+	Enable(Planner, false)
+	assert.False(t, Enabled(Planner))
+	assert.Equal(t, false, (<-ch).Val.(map[Flag]bool)[Planner])
+
+	_, err := FlagString("V2G")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,158 @@
+// Package holiday provides public holiday calendars by region
+package holiday
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rule returns the holiday's month and day for the given year
+type rule func(year int) (time.Month, int)
+
+// fixed is a holiday on the same date each year
+func fixed(month time.Month, day int) rule {
+	return func(int) (time.Month, int) {
+		return month, day
+	}
+}
+
+// easter is a holiday relative to easter sunday
+func easter(offset int) rule {
+	return func(year int) (time.Month, int) {
+		t := easterSunday(year).AddDate(0, 0, offset)
+		return t.Month(), t.Day()
+	}
+}
+
+// repentance is the german Buß- und Bettag, the wednesday before november 23rd
+func repentance(year int) (time.Month, int) {
+	t := time.Date(year, time.November, 22, 0, 0, 0, 0, time.UTC)
+	for t.Weekday() != time.Wednesday {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t.Month(), t.Day()
+}
+
+// kingsDay is the dutch Koningsdag, moved to saturday if on sunday
+func kingsDay(year int) (time.Month, int) {
+	if time.Date(year, time.April, 27, 0, 0, 0, 0, time.UTC).Weekday() == time.Sunday {
+		return time.April, 26
+	}
+	return time.April, 27
+}
+
+// easterSunday calculates the gregorian easter date
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := (19*a + b - b/4 - (b-(b+8)/25+1)/3 + 15) % 30
+	e := (32 + 2*(b%4) + 2*(c/4) - d - c%4) % 7
+	f := d + e - 7*((a+11*d+22*e)/451) + 114
+
+	return time.Date(year, time.Month(f/31), f%31+1, 0, 0, 0, 0, time.UTC)
+}
+
+var (
+	newYear       = fixed(time.January, 1)
+	epiphany      = fixed(time.January, 6)
+	goodFriday    = easter(-2)
+	easterMonday  = easter(1)
+	labourDay     = fixed(time.May, 1)
+	ascension     = easter(39)
+	whitMonday    = easter(50)
+	corpusChristi = easter(60)
+	assumption    = fixed(time.August, 15)
+	allSaints     = fixed(time.November, 1)
+	christmas     = fixed(time.December, 25)
+	boxingDay     = fixed(time.December, 26)
+)
+
+// regions contains the holiday rules per country and region
+var regions = map[string][]rule{
+	"de":    {newYear, goodFriday, easterMonday, labourDay, ascension, whitMonday, fixed(time.October, 3), christmas, boxingDay},
+	"de-bw": {epiphany, corpusChristi, allSaints},
+	"de-by": {epiphany, corpusChristi, allSaints},
+	"de-be": {fixed(time.March, 8)},
+	"de-bb": {easter(0), easter(49), fixed(time.October, 31)},
+	"de-hb": {fixed(time.October, 31)},
+	"de-hh": {fixed(time.October, 31)},
+	"de-he": {corpusChristi},
+	"de-mv": {fixed(time.March, 8), fixed(time.October, 31)},
+	"de-ni": {fixed(time.October, 31)},
+	"de-nw": {corpusChristi, allSaints},
+	"de-rp": {corpusChristi, allSaints},
+	"de-sl": {corpusChristi, assumption, allSaints},
+	"de-sn": {fixed(time.October, 31), repentance},
+	"de-st": {epiphany, fixed(time.October, 31)},
+	"de-sh": {fixed(time.October, 31)},
+	"de-th": {fixed(time.September, 20), fixed(time.October, 31)},
+	"at":    {newYear, epiphany, easterMonday, labourDay, ascension, whitMonday, corpusChristi, assumption, fixed(time.October, 26), allSaints, fixed(time.December, 8), christmas, boxingDay},
+	"ch":    {newYear, goodFriday, easterMonday, ascension, whitMonday, fixed(time.August, 1), christmas, boxingDay},
+	"fr":    {newYear, easterMonday, labourDay, fixed(time.May, 8), ascension, whitMonday, fixed(time.July, 14), assumption, allSaints, fixed(time.November, 11), christmas},
+	"nl":    {newYear, easterMonday, kingsDay, ascension, whitMonday, christmas, boxingDay},
+}
+
+// Calendar is the public holiday calendar of a region
+type Calendar struct {
+	rules []rule
+	dates []time.Time
+}
+
+// New creates a holiday calendar for a country like "de" or a region like "de-by".
+// Additional dates in 2006-01-02 format are treated as holidays, too.
+func New(region string, dates ...string) (*Calendar, error) {
+	c := new(Calendar)
+
+	if region = strings.ToLower(region); region != "" {
+		country, _, _ := strings.Cut(region, "-")
+
+		rules, ok := regions[country]
+		if !ok {
+			return nil, fmt.Errorf("invalid holiday region: %s", region)
+		}
+		c.rules = append(c.rules, rules...)
+
+		if region != country {
+			rules, ok := regions[region]
+			if !ok {
+				return nil, fmt.Errorf("invalid holiday region: %s", region)
+			}
+			c.rules = append(c.rules, rules...)
+		}
+	}
+
+	for _, d := range dates {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday date: %w", err)
+		}
+		c.dates = append(c.dates, t)
+	}
+
+	return c, nil
+}
+
+// IsHoliday returns true if the date of t is a public holiday
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+
+	year, month, day := t.Date()
+
+	for _, r := range c.rules {
+		if m, d := r(year); m == month && d == day {
+			return true
+		}
+	}
+
+	for _, d := range c.dates {
+		if y, m, dd := d.Date(); y == year && m == month && dd == day {
+			return true
+		}
+	}
+
+	return false
+}
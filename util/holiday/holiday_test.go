@@ -0,0 +1,60 @@
+package holiday
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEasterSunday(t *testing.T) {
+	for year, exp := range map[int]string{
+		2022: "2022-04-17",
+		2023: "2023-04-09",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+	} {
+		assert.Equal(t, exp, easterSunday(year).Format("2006-01-02"), year)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	c, err := New("de-by", "2022-12-24")
+	require.NoError(t, err)
+
+	date := func(s string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		return t
+	}
+
+	tc := []struct {
+		date string
+		res  bool
+	}{
+		{"2022-01-06 07:00", true},  // epiphany (by)
+		{"2022-04-15 07:00", true},  // good friday
+		{"2022-04-18 07:00", true},  // easter monday
+		{"2022-06-16 07:00", true},  // corpus christi (by)
+		{"2022-10-03 07:00", true},  // german unity day
+		{"2022-10-31 07:00", false}, // reformation day (not by)
+		{"2022-12-24 07:00", true},  // custom date
+		{"2022-06-15 07:00", false},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.res, c.IsHoliday(date(tc.date)), tc.date)
+	}
+
+	_, err = New("xx")
+	assert.Error(t, err)
+
+	_, err = New("de-xx")
+	assert.Error(t, err)
+}
+
+func TestRepentance(t *testing.T) {
+	m, d := repentance(2022)
+	assert.Equal(t, time.November, m)
+	assert.Equal(t, 16, d)
+}
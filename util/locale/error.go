@@ -0,0 +1,31 @@
+package locale
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+// Error is an error with a translatable message
+type Error struct {
+	ID  string // message id
+	Msg string // default message
+}
+
+// NewError creates a translatable error
+func NewError(id, msg string) *Error {
+	return &Error{ID: id, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// Localize translates the error message, falling back to the default message
+func (e *Error) Localize(localizer *i18n.Localizer) string {
+	if localizer == nil {
+		return e.Msg
+	}
+
+	msg, err := lookup(localizer, &Config{MessageID: e.ID})
+	if err != nil {
+		return e.Msg
+	}
+	return msg
+}
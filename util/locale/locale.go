@@ -1,53 +1,232 @@
 package locale
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/cloudfoundry/jibber_jabber"
 	assets "github.com/evcc-io/evcc/assets/i18n"
 	"github.com/evcc-io/evcc/util/locale/internal"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/exp/slices"
 	"golang.org/x/text/language"
 )
 
 type Config = i18n.LocalizeConfig
 
+var Locale internal.ContextKey
+
 var (
-	Locale internal.ContextKey
+	mu sync.RWMutex
+
+	// bundle is replaced when message files are loaded, localizers keep the bundle they were created with
+	bundle    *i18n.Bundle
+	files     map[string][]byte // loaded message files by language
+	available []language.Tag    // languages with message files
 
-	Bundle    *i18n.Bundle
-	Language  string
-	Localizer *i18n.Localizer
+	detected  string // os language
+	user      string // user selected language
+	localizer *i18n.Localizer
 )
 
+// Init lists the available message files and detects the system language.
+// Message files are loaded on first use of their language.
 func Init() error {
-	Bundle = i18n.NewBundle(language.English)
-	Bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
-
 	dir, err := assets.LocaleFS.ReadDir(".")
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("loading locales failed: %w", err)
 	}
 
+	mu.Lock()
+	defer mu.Unlock()
+
+	available = nil
 	for _, d := range dir {
-		if _, err := Bundle.LoadMessageFileFS(assets.LocaleFS, d.Name()); err != nil {
-			return fmt.Errorf("loading locales failed: %w", err)
+		name := strings.TrimSuffix(d.Name(), ".toml")
+		if tag, err := language.Parse(name); err == nil && name != d.Name() {
+			available = append(available, tag)
 		}
 	}
 
-	Language, err = jibber_jabber.DetectLanguage()
+	files = make(map[string][]byte)
+	bundle = newBundle()
+
+	detected, err = jibber_jabber.DetectLanguage()
 	if err != nil {
-		Language = language.German.String()
+		detected = language.German.String()
+	}
+
+	return update()
+}
+
+func newBundle() *i18n.Bundle {
+	b := i18n.NewBundle(language.English)
+	b.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+	return b
+}
+
+// update refreshes the system localizer, mu must be held
+func update() error {
+	if err := load(Fallbacks(system())...); err != nil {
+		return err
+	}
+
+	localizer = i18n.NewLocalizer(bundle, Fallbacks(system())...)
+
+	return nil
+}
+
+// load adds the missing message files of the languages to a new bundle, mu must be held
+func load(langs ...string) error {
+	var added bool
+
+	for _, lang := range langs {
+		if _, ok := files[lang]; ok {
+			continue
+		}
+
+		b, err := assets.LocaleFS.ReadFile(lang + ".toml")
+		if err != nil {
+			// no message file, remember to avoid further lookups
+			files[lang] = nil
+			continue
+		}
+
+		files[lang] = b
+		added = true
+	}
+
+	if !added {
+		return nil
+	}
+
+	res := newBundle()
+	for lang, b := range files {
+		if b == nil {
+			continue
+		}
+
+		if _, err := res.ParseMessageFileBytes(b, lang+".toml"); err != nil {
+			return fmt.Errorf("loading locale %s failed: %w", lang, err)
+		}
 	}
 
-	Localizer = i18n.NewLocalizer(Bundle, Language)
+	bundle = res
 
 	return nil
 }
 
+// system returns the user selected or detected language, mu must be held
+func system() string {
+	if user != "" {
+		return user
+	}
+	return detected
+}
+
+// Fallbacks returns the lookup chain of the languages, e.g. de-CH, de, en
+func Fallbacks(langs ...string) []string {
+	var res []string
+
+	add := func(lang string) {
+		if lang != "" && !slices.Contains(res, lang) {
+			res = append(res, lang)
+		}
+	}
+
+	for _, lang := range langs {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			continue
+		}
+
+		add(tag.String())
+
+		if base, conf := tag.Base(); conf != language.No {
+			add(base.String())
+		}
+	}
+
+	add(language.English.String())
+
+	return res
+}
+
+// Languages returns the languages with message files
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	res := make([]string, 0, len(available))
+	for _, tag := range available {
+		res = append(res, tag.String())
+	}
+
+	return res
+}
+
+// Language returns the system language, i.e. the user selected or the detected language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return system()
+}
+
+// UserLanguage returns the user selected language or an empty string if the language is detected
+func UserLanguage() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return user
+}
+
+// SetLanguage overrides the detected system language, an empty language restores detection
+func SetLanguage(lang string) error {
+	if lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("invalid language: %s", lang)
+		}
+
+		if Match(tag.String()) == "" {
+			return fmt.Errorf("unsupported language: %s", lang)
+		}
+
+		lang = tag.String()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	user = lang
+
+	return update()
+}
+
+// Localizer returns the localizer of the system language or nil if not initialized
+func Localizer() *i18n.Localizer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return localizer
+}
+
+// lookup localizes the message, messages missing in the chosen language fall back to english
+func lookup(localizer *i18n.Localizer, lc *Config) (string, error) {
+	msg, err := localizer.Localize(lc)
+
+	var nf *i18n.MessageNotFoundErr
+	if errors.As(err, &nf) && msg != "" {
+		err = nil
+	}
+
+	return msg, err
+}
+
 func Localize(lc *Config) string {
-	msg, _, err := Localizer.LocalizeWithTag(lc)
+	msg, err := lookup(Localizer(), lc)
 	if err != nil {
 		msg = lc.MessageID
 	}
@@ -59,3 +238,77 @@ func LocalizeID(id string) string {
 		MessageID: id,
 	})
 }
+
+// Match returns the best supported language for the given preferences, e.g. Accept-Language header values.
+// It returns an empty string if none of the preferences is supported.
+func Match(prefs ...string) string {
+	mu.RLock()
+	supported := available
+	mu.RUnlock()
+
+	if len(supported) == 0 {
+		return ""
+	}
+
+	var tags []language.Tag
+	for _, pref := range prefs {
+		if t, _, err := language.ParseAcceptLanguage(pref); err == nil {
+			tags = append(tags, t...)
+		}
+	}
+
+	if len(tags) == 0 {
+		return ""
+	}
+
+	_, idx, conf := language.NewMatcher(supported).Match(tags...)
+	if conf == language.No {
+		return ""
+	}
+
+	base, _ := supported[idx].Base()
+	return base.String()
+}
+
+// NewLocalizer creates a localizer for the language falling back to the system language
+func NewLocalizer(lang string) *i18n.Localizer {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if lang == "" || bundle == nil {
+		return localizer
+	}
+
+	langs := Fallbacks(lang, system())
+	if err := load(langs...); err != nil {
+		return localizer
+	}
+
+	return i18n.NewLocalizer(bundle, langs...)
+}
+
+// WithLanguage returns a context carrying the language
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, Locale, lang)
+}
+
+// LanguageFromContext returns the context's language or the system language
+func LanguageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(Locale).(string); ok && lang != "" {
+		return lang
+	}
+	return Language()
+}
+
+// LocalizeWith localizes using the given localizer, falling back to the message id
+func LocalizeWith(localizer *i18n.Localizer, lc *Config) string {
+	if localizer == nil {
+		return lc.MessageID
+	}
+
+	msg, err := lookup(localizer, lc)
+	if err != nil {
+		msg = lc.MessageID
+	}
+	return msg
+}
@@ -0,0 +1,35 @@
+package locale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbacks(t *testing.T) {
+	assert.Equal(t, []string{"de-CH", "de", "en"}, Fallbacks("de-CH"))
+	assert.Equal(t, []string{"nl", "de", "en"}, Fallbacks("nl", "de"))
+	assert.Equal(t, []string{"en"}, Fallbacks("en", "invalid language"))
+}
+
+func TestLazyLoading(t *testing.T) {
+	require.NoError(t, Init())
+	require.NoError(t, SetLanguage("en"))
+	defer func() { _ = SetLanguage("") }()
+
+	mu.RLock()
+	_, loaded := files["it"]
+	mu.RUnlock()
+	assert.False(t, loaded, "it loaded before use")
+
+	lc := &Config{MessageID: "errors.unauthorized"}
+	assert.Equal(t, "Nicht angemeldet", LocalizeWith(NewLocalizer("de-CH"), lc))
+	assert.Equal(t, "Unauthorized", LocalizeWith(NewLocalizer("nl"), lc))
+
+	require.NoError(t, SetLanguage("de-CH"))
+	assert.Equal(t, "de-CH", Language())
+	assert.Equal(t, "Nicht angemeldet", Localize(lc))
+
+	assert.Error(t, SetLanguage("xx"))
+}
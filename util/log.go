@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"regexp"
@@ -100,24 +101,70 @@ func LogLevel(defaultLevel string, areaLevels map[string]string) {
 	})
 }
 
-// LogLevelToThreshold converts log level string to a jww Threshold
-func LogLevelToThreshold(level string) jww.Threshold {
+// SetLogLevel changes the log level of the area at runtime
+func SetLogLevel(area, level string) error {
+	threshold, ok := logLevelToThreshold(level)
+	if !ok {
+		return fmt.Errorf("invalid log level: %s", level)
+	}
+
+	loggersMux.Lock()
+	defer loggersMux.Unlock()
+
+	area = strings.ToLower(area)
+	levels[area] = threshold
+
+	if logger, ok := loggers[area]; ok {
+		logger.SetStdoutThreshold(threshold)
+
+		// thresholds recreate the level loggers
+		if uiChan != nil {
+			captureLoggers(logger)
+		}
+	}
+
+	return nil
+}
+
+// LogLevels returns the log level of all areas
+func LogLevels() map[string]string {
+	loggersMux.Lock()
+	defer loggersMux.Unlock()
+
+	res := make(map[string]string, len(loggers))
+	for area := range loggers {
+		res[area] = strings.ToLower(LogLevelForArea(area).String())
+	}
+
+	return res
+}
+
+func logLevelToThreshold(level string) (jww.Threshold, bool) {
 	switch strings.ToUpper(level) {
 	case "FATAL":
-		return jww.LevelFatal
+		return jww.LevelFatal, true
 	case "ERROR":
-		return jww.LevelError
+		return jww.LevelError, true
 	case "WARN":
-		return jww.LevelWarn
+		return jww.LevelWarn, true
 	case "INFO":
-		return jww.LevelInfo
+		return jww.LevelInfo, true
 	case "DEBUG":
-		return jww.LevelDebug
+		return jww.LevelDebug, true
 	case "TRACE":
-		return jww.LevelTrace
+		return jww.LevelTrace, true
 	default:
+		return 0, false
+	}
+}
+
+// LogLevelToThreshold converts log level string to a jww Threshold
+func LogLevelToThreshold(level string) jww.Threshold {
+	threshold, ok := logLevelToThreshold(level)
+	if !ok {
 		panic("invalid log level " + level)
 	}
+	return threshold
 }
 
 var uiChan chan<- Param
@@ -144,12 +191,17 @@ func CaptureLogs(c chan<- Param) {
 	uiChan = c
 
 	for _, l := range loggers {
-		captureLogger("warn", l.Notepad.WARN)
-		captureLogger("error", l.Notepad.ERROR)
-		captureLogger("error", l.Notepad.FATAL)
+		captureLoggers(l)
 	}
 }
 
+// captureLoggers appends uiWriter to the logger's relevant log levels
+func captureLoggers(l *Logger) {
+	captureLogger("warn", l.Notepad.WARN)
+	captureLogger("error", l.Notepad.ERROR)
+	captureLogger("error", l.Notepad.FATAL)
+}
+
 func captureLogger(level string, l *log.Logger) {
 	re, err := regexp.Compile(`^\[[a-zA-Z0-9-]+\s*\] \w+ .{19} `)
 	if err != nil {
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogBufferSize is the number of retained log entries
+const LogBufferSize = 1000
+
+// LogEntry is a structured log line
+type LogEntry struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	Area    string    `json:"area"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// logLineRe splits the log line into area, level, timestamp and message
+var logLineRe = regexp.MustCompile(`^\[([a-zA-Z0-9-]+)\s*\] (\w+) (.{19}) (.*)$`)
+
+type logBuffer struct {
+	mu      sync.Mutex
+	seq     uint64
+	entries []LogEntry
+	subs    map[chan LogEntry]struct{}
+}
+
+var logs = &logBuffer{
+	subs: make(map[chan LogEntry]struct{}),
+}
+
+// write adds the redacted console output to the buffer
+func (b *logBuffer) write(p []byte) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		match := logLineRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		ts, err := time.ParseInLocation("2006/01/02 15:04:05", match[3], time.Local)
+		if err != nil {
+			ts = time.Now()
+		}
+
+		b.add(LogEntry{
+			Time:    ts,
+			Area:    match[1],
+			Level:   strings.ToLower(match[2]),
+			Message: match[4],
+		})
+	}
+}
+
+func (b *logBuffer) add(e LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e.Seq = b.seq
+
+	b.entries = append(b.entries, e)
+	if len(b.entries) > LogBufferSize {
+		b.entries = b.entries[len(b.entries)-LogBufferSize:]
+	}
+
+	for ch := range b.subs {
+		// drop entries for slow subscribers
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// LogEntries returns the retained log entries after the given sequence number
+func LogEntries(since uint64) []LogEntry {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+
+	res := make([]LogEntry, 0, len(logs.entries))
+	for _, e := range logs.entries {
+		if e.Seq > since {
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
+
+// SubscribeLogs returns a channel receiving new log entries and a function to cancel the subscription
+func SubscribeLogs() (<-chan LogEntry, func()) {
+	ch := make(chan LogEntry, 100)
+
+	logs.mu.Lock()
+	logs.subs[ch] = struct{}{}
+	logs.mu.Unlock()
+
+	return ch, func() {
+		logs.mu.Lock()
+		delete(logs.subs, ch)
+		logs.mu.Unlock()
+	}
+}
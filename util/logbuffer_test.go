@@ -0,0 +1,38 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogBuffer(t *testing.T) {
+	b := &logBuffer{subs: make(map[chan LogEntry]struct{})}
+
+	b.write([]byte("[lp-1  ] WARN 2023/01/02 15:04:05 charger: timeout\n[site  ] INFO 2023/01/02 15:04:06 started\ninvalid\n"))
+
+	require.Len(t, b.entries, 2)
+	assert.Equal(t, LogEntry{Seq: 1, Time: b.entries[0].Time, Area: "lp-1", Level: "warn", Message: "charger: timeout"}, b.entries[0])
+	assert.Equal(t, "site", b.entries[1].Area)
+	assert.Equal(t, 6, b.entries[1].Time.Second())
+
+	for i := 0; i < LogBufferSize; i++ {
+		b.add(LogEntry{})
+	}
+	assert.Len(t, b.entries, LogBufferSize)
+	assert.Equal(t, uint64(LogBufferSize+2), b.entries[LogBufferSize-1].Seq)
+}
+
+func TestSetLogLevel(t *testing.T) {
+	assert.Error(t, SetLogLevel("foo", "verbose"))
+
+	require.NoError(t, SetLogLevel("Foo", "debug"))
+	assert.Equal(t, LogLevelToThreshold("debug"), LogLevelForArea("foo"))
+
+	// applies to existing loggers
+	log := NewLogger("foo")
+	require.NoError(t, SetLogLevel("foo", "error"))
+	assert.Equal(t, LogLevelToThreshold("error"), log.GetStdoutThreshold())
+	assert.Equal(t, "error", LogLevels()["foo"])
+}
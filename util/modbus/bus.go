@@ -0,0 +1,151 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/grid-x/modbus"
+	"github.com/volkszaehler/mbmd/meters"
+)
+
+// serialDelay is the default inter-frame delay of serial buses and rtu gateways
+const serialDelay = 20 * time.Millisecond
+
+// BusConfig configures the request scheduling of a shared physical connection
+type BusConfig struct {
+	URI, Device string
+	Delay       time.Duration // minimum gap between end of a response and start of the next request
+	Retries     *int          // retries after transport errors including writes, default none
+	Timeout     time.Duration // response timeout
+}
+
+func (c BusConfig) key() string {
+	if c.Device != "" {
+		return c.Device
+	}
+	return util.DefaultPort(c.URI, 502)
+}
+
+// bus serializes all requests of the devices sharing a physical connection, e.g. an RS485 gateway
+type bus struct {
+	mu      sync.Mutex
+	conn    meters.Connection
+	delay   time.Duration
+	retries int
+	timeout time.Duration // configured timeout, takes precedence over device defaults
+	last    time.Time
+	sleep   func(time.Duration)
+}
+
+var (
+	buses   = make(map[string]*bus)
+	configs = make(map[string]BusConfig)
+	mu      sync.Mutex
+)
+
+// ConfigureBus sets the request scheduling of the bus identified by uri or device.
+// It must be called before the first connection to the bus is created.
+func ConfigureBus(conf BusConfig) error {
+	if (conf.URI == "") == (conf.Device == "") {
+		return errors.New("invalid modbus bus configuration: need either uri or device")
+	}
+
+	if conf.Retries != nil && *conf.Retries < 0 {
+		return fmt.Errorf("invalid modbus bus configuration: retries: %d", *conf.Retries)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := conf.key()
+	if _, ok := buses[key]; ok {
+		return fmt.Errorf("modbus bus already in use: %s", key)
+	}
+
+	configs[key] = conf
+
+	return nil
+}
+
+// registeredBus returns the bus for given key, creating it from the connection if not registered yet
+func registeredBus(key string, proto Protocol, serial bool, newConn func() meters.Connection) *bus {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if b, ok := buses[key]; ok {
+		return b
+	}
+
+	b := &bus{
+		conn:  newConn(),
+		sleep: time.Sleep,
+	}
+
+	if serial || proto != Tcp {
+		b.delay = serialDelay
+	}
+
+	if conf, ok := configs[key]; ok {
+		if conf.Delay > 0 {
+			b.delay = conf.Delay
+		}
+		if conf.Retries != nil {
+			b.retries = *conf.Retries
+		}
+		if conf.Timeout > 0 {
+			b.timeout = conf.Timeout
+			b.conn.Timeout(conf.Timeout)
+		}
+	}
+
+	buses[key] = b
+
+	return b
+}
+
+// retryable determines if the request may be repeated. Modbus exceptions are valid responses of the device.
+func retryable(err error) bool {
+	var mbErr *modbus.Error
+	return !errors.As(err, &mbErr)
+}
+
+// execute runs the request exclusively on the bus, keeping the inter-frame gap and retrying transport errors
+func (b *bus) execute(slaveID uint8, delay time.Duration, fun func(modbus.Client) ([]byte, error)) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if delay < b.delay {
+		delay = b.delay
+	}
+
+	var (
+		res []byte
+		err error
+	)
+
+	for attempt := 0; attempt <= b.retries; attempt++ {
+		if d := delay - time.Since(b.last); d > 0 {
+			b.sleep(d)
+		}
+
+		b.conn.Slave(slaveID)
+		res, err = fun(b.conn.ModbusClient())
+		b.last = time.Now()
+
+		if err == nil {
+			break
+		}
+
+		// reconnect to discard partial frames
+		b.conn.Close()
+
+		if !retryable(err) {
+			break
+		}
+	}
+
+	return res, err
+}
@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grid-x/modbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/volkszaehler/mbmd/meters"
+)
+
+type fakeConn struct {
+	meters.Connection
+	slaves []uint8
+	closed int
+}
+
+func (c *fakeConn) Slave(id uint8) {
+	c.slaves = append(c.slaves, id)
+}
+
+func (c *fakeConn) ModbusClient() modbus.Client {
+	return nil
+}
+
+func (c *fakeConn) Close() {
+	c.closed++
+}
+
+func TestBusShared(t *testing.T) {
+	retries := 0
+	assert.NoError(t, ConfigureBus(BusConfig{URI: "gateway.test", Retries: &retries}))
+
+	conn := new(fakeConn)
+	newConn := func() meters.Connection { return conn }
+
+	b1 := registeredBus("gateway.test:502", Rtu, false, newConn)
+	b2 := registeredBus("gateway.test:502", Rtu, false, func() meters.Connection { return new(fakeConn) })
+
+	assert.Same(t, b1, b2)
+	assert.Equal(t, serialDelay, b1.delay)
+	assert.Equal(t, 0, b1.retries)
+
+	assert.Error(t, ConfigureBus(BusConfig{URI: "gateway.test:502"}), "bus already in use")
+	assert.Equal(t, 0, registeredBus("tcp.test:502", Tcp, false, newConn).retries)
+}
+
+func TestBusExecute(t *testing.T) {
+	conn := new(fakeConn)
+
+	var sleeps []time.Duration
+	b := &bus{
+		conn:    conn,
+		delay:   time.Hour,
+		retries: 1,
+		sleep:   func(d time.Duration) { sleeps = append(sleeps, d) },
+	}
+
+	// transport errors are retried and reconnected
+	var calls int
+	_, err := b.execute(1, 0, func(modbus.Client) ([]byte, error) {
+		calls++
+		return nil, errors.New("timeout")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, conn.closed)
+	assert.Equal(t, []uint8{1, 1}, conn.slaves)
+
+	// first request is sent immediately, retry waits for the inter-frame gap
+	assert.Len(t, sleeps, 1)
+
+	// modbus exceptions are not retried
+	calls = 0
+	_, err = b.execute(2, 0, func(modbus.Client) ([]byte, error) {
+		calls++
+		return nil, &modbus.Error{FunctionCode: modbus.FuncCodeReadHoldingRegisters, ExceptionCode: modbus.ExceptionCodeIllegalDataAddress}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// success
+	res, err := b.execute(3, 0, func(modbus.Client) ([]byte, error) {
+		return []byte{1}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, res)
+}
@@ -6,7 +6,6 @@ import (
 	"math"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/util"
@@ -53,26 +52,16 @@ func (s *Settings) String() string {
 	return s.Device
 }
 
-// Connection decorates a meters.Connection with transparent slave id and error handling
+// Connection decorates a meters.Connection with transparent slave id and error handling.
+// Requests of all connections sharing a bus are serialized.
 type Connection struct {
 	slaveID uint8
-	mu      sync.Mutex
-	conn    meters.Connection
+	bus     *bus
 	delay   time.Duration
 }
 
-func (mb *Connection) prepare(slaveID uint8) {
-	mb.conn.Slave(slaveID)
-	if mb.delay > 0 {
-		time.Sleep(mb.delay)
-	}
-}
-
-func (mb *Connection) handle(res []byte, err error) ([]byte, error) {
-	if err != nil {
-		mb.conn.Close()
-	}
-	return res, err
+func (mb *Connection) execute(slaveID uint8, fun func(modbus.Client) ([]byte, error)) ([]byte, error) {
+	return mb.bus.execute(slaveID, mb.delay, fun)
 }
 
 // Delay sets delay so use between subsequent modbus operations
@@ -82,105 +71,96 @@ func (mb *Connection) Delay(delay time.Duration) {
 
 // ConnectDelay sets the initial delay after connecting before starting communication
 func (mb *Connection) ConnectDelay(delay time.Duration) {
-	mb.conn.ConnectDelay(delay)
+	mb.bus.conn.ConnectDelay(delay)
 }
 
 // Logger sets logger implementation
 func (mb *Connection) Logger(logger meters.Logger) {
-	mb.conn.Logger(logger)
+	mb.bus.conn.Logger(logger)
 }
 
 // Timeout sets the connection timeout (not idle timeout)
 func (mb *Connection) Timeout(timeout time.Duration) {
-	mb.conn.Timeout(timeout)
+	if mb.bus.timeout == 0 {
+		mb.bus.conn.Timeout(timeout)
+	}
 }
 
 // ReadCoils wraps the underlying implementation
 func (mb *Connection) ReadCoilsWithSlave(slaveID uint8, address, quantity uint16) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadCoils(address, quantity))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.ReadCoils(address, quantity)
+	})
 }
 
 // WriteSingleCoil wraps the underlying implementation
 func (mb *Connection) WriteSingleCoilWithSlave(slaveID uint8, address, value uint16) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteSingleCoil(address, value))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.WriteSingleCoil(address, value)
+	})
 }
 
 // ReadInputRegisters wraps the underlying implementation
 func (mb *Connection) ReadInputRegistersWithSlave(slaveID uint8, address, quantity uint16) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadInputRegisters(address, quantity))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.ReadInputRegisters(address, quantity)
+	})
 }
 
 // ReadHoldingRegisters wraps the underlying implementation
 func (mb *Connection) ReadHoldingRegistersWithSlave(slaveID uint8, address, quantity uint16) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadHoldingRegisters(address, quantity))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.ReadHoldingRegisters(address, quantity)
+	})
 }
 
 // WriteSingleRegister wraps the underlying implementation
 func (mb *Connection) WriteSingleRegisterWithSlave(slaveID uint8, address, value uint16) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteSingleRegister(address, value))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.WriteSingleRegister(address, value)
+	})
 }
 
 // WriteMultipleRegisters wraps the underlying implementation
 func (mb *Connection) WriteMultipleRegistersWithSlave(slaveID uint8, address, quantity uint16, value []byte) ([]byte, error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteMultipleRegisters(address, quantity, value))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.WriteMultipleRegisters(address, quantity, value)
+	})
 }
 
 // ReadDiscreteInputs wraps the underlying implementation
 func (mb *Connection) ReadDiscreteInputsWithSlave(slaveID uint8, address, quantity uint16) (results []byte, err error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadDiscreteInputs(address, quantity))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.ReadDiscreteInputs(address, quantity)
+	})
 }
 
 // WriteMultipleCoils wraps the underlying implementation
 func (mb *Connection) WriteMultipleCoilsWithSlave(slaveID uint8, address, quantity uint16, value []byte) (results []byte, err error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().WriteMultipleCoils(address, quantity, value))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.WriteMultipleCoils(address, quantity, value)
+	})
 }
 
 // ReadWriteMultipleRegisters wraps the underlying implementation
 func (mb *Connection) ReadWriteMultipleRegistersWithSlave(slaveID uint8, readAddress, readQuantity, writeAddress, writeQuantity uint16, value []byte) (results []byte, err error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadWriteMultipleRegisters(readAddress, readQuantity, writeAddress, writeQuantity, value))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.ReadWriteMultipleRegisters(readAddress, readQuantity, writeAddress, writeQuantity, value)
+	})
 }
 
 // MaskWriteRegister wraps the underlying implementation
 func (mb *Connection) MaskWriteRegisterWithSlave(slaveID uint8, address, andMask, orMask uint16) (results []byte, err error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().MaskWriteRegister(address, andMask, orMask))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.MaskWriteRegister(address, andMask, orMask)
+	})
 }
 
 // ReadFIFOQueue wraps the underlying implementation
 func (mb *Connection) ReadFIFOQueueWithSlave(slaveID uint8, address uint16) (results []byte, err error) {
-	mb.mu.Lock()
-	defer mb.mu.Unlock()
-	mb.prepare(slaveID)
-	return mb.handle(mb.conn.ModbusClient().ReadFIFOQueue(address))
+	return mb.execute(slaveID, func(client modbus.Client) ([]byte, error) {
+		return client.ReadFIFOQueue(address)
+	})
 }
 
 func (mb *Connection) ReadCoils(address, quantity uint16) ([]byte, error) {
@@ -227,24 +207,6 @@ func (mb *Connection) ReadFIFOQueue(address uint16) (results []byte, err error)
 	return mb.ReadFIFOQueueWithSlave(mb.slaveID, address)
 }
 
-var (
-	connections = make(map[string]meters.Connection)
-	mu          sync.Mutex
-)
-
-func registeredConnection(key string, newConn meters.Connection) meters.Connection {
-	mu.Lock()
-	defer mu.Unlock()
-
-	if conn, ok := connections[key]; ok {
-		return conn
-	}
-
-	connections[key] = newConn
-
-	return newConn
-}
-
 // ProtocolFromRTU identifies the wire format from the RTU setting
 func ProtocolFromRTU(rtu *bool) Protocol {
 	if rtu != nil && *rtu {
@@ -255,7 +217,7 @@ func ProtocolFromRTU(rtu *bool) Protocol {
 
 // NewConnection creates physical modbus device from config
 func NewConnection(uri, device, comset string, baudrate int, proto Protocol, slaveID uint8) (*Connection, error) {
-	var conn meters.Connection
+	var b *bus
 
 	if device != "" && uri != "" {
 		return nil, errors.New("invalid modbus configuration: can only have either uri or device")
@@ -275,9 +237,13 @@ func NewConnection(uri, device, comset string, baudrate int, proto Protocol, sla
 		}
 
 		if proto == Ascii {
-			conn = registeredConnection(device, meters.NewASCII(device, baudrate, comset))
+			b = registeredBus(device, proto, true, func() meters.Connection {
+				return meters.NewASCII(device, baudrate, comset)
+			})
 		} else {
-			conn = registeredConnection(device, meters.NewRTU(device, baudrate, comset))
+			b = registeredBus(device, proto, true, func() meters.Connection {
+				return meters.NewRTU(device, baudrate, comset)
+			})
 		}
 	}
 
@@ -286,21 +252,27 @@ func NewConnection(uri, device, comset string, baudrate int, proto Protocol, sla
 
 		switch proto {
 		case Rtu:
-			conn = registeredConnection(uri, meters.NewRTUOverTCP(uri))
+			b = registeredBus(uri, proto, false, func() meters.Connection {
+				return meters.NewRTUOverTCP(uri)
+			})
 		case Ascii:
-			conn = registeredConnection(uri, meters.NewASCIIOverTCP(uri))
+			b = registeredBus(uri, proto, false, func() meters.Connection {
+				return meters.NewASCIIOverTCP(uri)
+			})
 		default:
-			conn = registeredConnection(uri, meters.NewTCP(uri))
+			b = registeredBus(uri, proto, false, func() meters.Connection {
+				return meters.NewTCP(uri)
+			})
 		}
 	}
 
-	if conn == nil {
+	if b == nil {
 		return nil, errors.New("invalid modbus configuration: need either uri or device")
 	}
 
 	slaveConn := &Connection{
 		slaveID: slaveID,
-		conn:    conn,
+		bus:     b,
 	}
 
 	return slaveConn, nil
@@ -40,6 +40,7 @@ func (l *Redactor) Write(p []byte) (n int, err error) {
 		p = bytes.ReplaceAll(p, []byte(s), []byte(RedactReplacement))
 	}
 	l.mu.Unlock()
+	logs.write(p)
 	return os.Stdout.Write(p)
 }
 
@@ -2,9 +2,11 @@ package templates
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"testing"
 
+	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/jinzhu/copier"
 	"gopkg.in/yaml.v3"
@@ -32,6 +34,10 @@ func RenderInstance(class Class, other map[string]interface{}) (Instance, error)
 		return *new(Instance), err
 	}
 
+	if err := tmpl.ValidateValues(cc.Other); err != nil {
+		return *new(Instance), fmt.Errorf("%s: %w", cc.Template, err)
+	}
+
 	b, _, err := tmpl.RenderResult(TemplateRenderModeInstance, other)
 	if err != nil {
 		return *new(Instance), err
@@ -67,6 +73,7 @@ func RenderTest(t *testing.T, tmpl Template, values map[string]interface{}, cb f
 					panic(err)
 				}
 				usageValues[ParamUsage] = u
+				requiredTestValues(tmpl, usageValues)
 
 				b, _, err := tmpl.RenderResult(TemplateRenderModeInstance, usageValues)
 				if err != nil {
@@ -86,3 +93,33 @@ func RenderTest(t *testing.T, tmpl Template, values map[string]interface{}, cb f
 		}
 	})
 }
+
+// requiredTestValues supplies placeholder values for required params the test values don't cover
+func requiredTestValues(tmpl Template, values map[string]interface{}) {
+	for _, p := range tmpl.Params {
+		if !p.Required || p.Deprecated || p.Name == ParamModbus || p.ValueType == ParamValueTypeStringList ||
+			tmpl.lookup(values, p.Name) != "" || !tmpl.DependenciesMet(p, values) {
+			continue
+		}
+
+		value := p.Example
+
+		switch {
+		case len(p.ValidValues) > 0:
+			value = p.ValidValues[0]
+		case value != "" && p.ValidateValue(value) == nil:
+		case p.ValueType == ParamValueTypeNumber, p.ValueType == ParamValueTypeFloat:
+			value = "1"
+		case p.ValueType == ParamValueTypeBool:
+			value = "false"
+		case p.ValueType == ParamValueTypeDuration:
+			value = "1s"
+		case p.ValueType == ParamValueTypeChargeModes:
+			value = string(api.ModePV)
+		default:
+			value = "test"
+		}
+
+		values[p.Name] = value
+	}
+}
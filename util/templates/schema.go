@@ -0,0 +1,181 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/slices"
+)
+
+// ErrMissingValue indicates that a required param has no value
+var ErrMissingValue = errors.New("missing required value")
+
+// Schema is the language specific description of a template's params used for rendering forms
+type Schema struct {
+	Template string        `json:"template"`
+	Title    string        `json:"title"`
+	Group    string        `json:"group,omitempty"`
+	Params   []SchemaParam `json:"params"`
+}
+
+// SchemaParam describes a single template param
+type SchemaParam struct {
+	Name         string       `json:"name"`
+	Description  string       `json:"description,omitempty"`
+	Help         string       `json:"help,omitempty"`
+	Type         string       `json:"type"`
+	Enum         []string     `json:"enum,omitempty"`
+	Default      string       `json:"default,omitempty"`
+	Example      string       `json:"example,omitempty"`
+	Required     bool         `json:"required,omitempty"`
+	Mask         bool         `json:"mask,omitempty"`
+	Advanced     bool         `json:"advanced,omitempty"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+// Schema returns the param schema of the template
+func (t *Template) Schema(lang string) Schema {
+	t.Lang = lang
+
+	res := Schema{
+		Template: t.Template,
+		Title:    strings.Join(t.Titles(lang), "/"),
+		Params:   make([]SchemaParam, 0, len(t.Params)),
+	}
+
+	if t.Group != "" {
+		res.Group = t.GroupTitle()
+	}
+
+	for _, p := range t.Params {
+		if p.Hidden || p.Deprecated {
+			continue
+		}
+
+		enum := p.ValidValues
+		if len(p.Choice) > 0 {
+			enum = p.Choice
+		}
+
+		res.Params = append(res.Params, SchemaParam{
+			Name:         p.Name,
+			Description:  p.Description.String(lang),
+			Help:         p.Help.String(lang),
+			Type:         p.ValueType,
+			Enum:         enum,
+			Default:      p.Default,
+			Example:      p.Example,
+			Required:     p.Required,
+			Mask:         p.Mask,
+			Advanced:     p.Advanced,
+			Dependencies: p.Dependencies,
+		})
+	}
+
+	return res
+}
+
+// lookup returns the value of the named param, falling back to the param's default
+func (t *Template) lookup(values map[string]interface{}, name string) string {
+	for k, v := range values {
+		if strings.EqualFold(k, name) && v != nil {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+
+	if _, p := t.ParamByName(name); p.ValueType != ParamValueTypeStringList {
+		return p.Default
+	}
+
+	return ""
+}
+
+// DependenciesMet checks if all dependencies of the param are met by the given values
+func (t *Template) DependenciesMet(p Param, values map[string]interface{}) bool {
+	for _, d := range p.Dependencies {
+		value := t.lookup(values, d.Name)
+
+		switch d.Check {
+		case DependencyCheckEmpty:
+			if value != "" {
+				return false
+			}
+		case DependencyCheckNotEmpty:
+			if value == "" {
+				return false
+			}
+		case DependencyCheckEqual:
+			if value != d.Value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ValidateValue checks if value is valid for the param's type and valid values
+func (p *Param) ValidateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if enum := p.ValidValues; len(enum) > 0 && !slices.Contains(enum, value) {
+		return fmt.Errorf("%s: invalid value %s, expected one of %s", p.Name, value, strings.Join(enum, ", "))
+	}
+
+	var err error
+
+	switch p.ValueType {
+	case ParamValueTypeNumber:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case ParamValueTypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case ParamValueTypeBool:
+		_, err = strconv.ParseBool(value)
+	case ParamValueTypeDuration:
+		_, err = time.ParseDuration(value)
+	case ParamValueTypeChargeModes:
+		_, err = api.ChargeModeString(value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: invalid %s value %s", p.Name, p.ValueType, value)
+	}
+
+	return nil
+}
+
+// ValidateValues validates the given values against the template's params before instantiating a device.
+// Required params without value are only reported if their dependencies are met.
+func (t *Template) ValidateValues(values map[string]interface{}) error {
+	for _, p := range t.Params {
+		if p.Deprecated || !t.DependenciesMet(p, values) {
+			continue
+		}
+
+		if p.ValueType == ParamValueTypeStringList {
+			continue
+		}
+
+		value := t.lookup(values, p.Name)
+
+		if p.Name == ParamUsage && value != "" && len(p.Choice) > 0 && !slices.Contains(p.Choice, value) {
+			return fmt.Errorf("%s: invalid value %s, expected one of %s", p.Name, value, strings.Join(p.Choice, ", "))
+		}
+
+		if p.Required && value == "" && p.Name != ParamModbus {
+			return fmt.Errorf("%s: %w", p.Name, ErrMissingValue)
+		}
+
+		if err := p.ValidateValue(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package templates
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValues(t *testing.T) {
+	tmpl := Template{
+		TemplateDefinition: TemplateDefinition{
+			Template: "test",
+			Params: []Param{
+				{Name: "cloud", ValueType: ParamValueTypeBool},
+				{Name: "host", Required: true, Dependencies: []Dependency{{Name: "cloud", Check: DependencyCheckEqual, Value: "false"}}},
+				{Name: "token", Required: true, Dependencies: []Dependency{{Name: "cloud", Check: DependencyCheckEqual, Value: "true"}}},
+				{Name: "port", ValueType: ParamValueTypeNumber, Default: "80"},
+				{Name: "mode", ValidValues: []string{"a", "b"}},
+			},
+		},
+	}
+
+	tc := []struct {
+		values map[string]interface{}
+		err    error
+	}{
+		{map[string]interface{}{"cloud": false, "host": "foo"}, nil},
+		{map[string]interface{}{"cloud": true, "token": "foo"}, nil},
+		{map[string]interface{}{"cloud": false}, ErrMissingValue},
+		{map[string]interface{}{"Cloud": "true", "host": "foo"}, ErrMissingValue},
+		{map[string]interface{}{"cloud": "maybe"}, errors.New("cloud: invalid bool value maybe")},
+		{map[string]interface{}{"cloud": true, "token": "foo", "port": "http"}, errors.New("port: invalid number value http")},
+		{map[string]interface{}{"cloud": true, "token": "foo", "mode": "c"}, errors.New("mode: invalid value c, expected one of a, b")},
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc.values)
+
+		err := tmpl.ValidateValues(tc.values)
+		switch {
+		case tc.err == nil:
+			assert.NoError(t, err)
+		case errors.Is(tc.err, ErrMissingValue):
+			assert.ErrorIs(t, err, ErrMissingValue)
+		default:
+			assert.EqualError(t, err, tc.err.Error())
+		}
+	}
+}
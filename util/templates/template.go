@@ -78,6 +78,16 @@ func (t *Template) Validate() error {
 		if p.ValueType != "" && !slices.Contains(ValidParamValueTypes, p.ValueType) {
 			return fmt.Errorf("invalid value type '%s' in template %s", p.ValueType, t.Template)
 		}
+
+		for _, d := range p.Dependencies {
+			if !slices.Contains(ValidDependencies, d.Check) {
+				return fmt.Errorf("invalid dependency check '%s' for param %s in template %s", d.Check, p.Name, t.Template)
+			}
+
+			if i, _ := t.ParamByName(d.Name); i == -1 {
+				return fmt.Errorf("invalid dependency '%s' for param %s in template %s", d.Name, p.Name, t.Template)
+			}
+		}
 	}
 
 	return nil
@@ -159,6 +159,13 @@ type LinkedTemplate struct {
 	ExcludeTemplate string // only consider this if no device of the named linked template was added
 }
 
+// Dependency makes a param depend on the value of another param, e.g. to be required only for a specific choice
+type Dependency struct {
+	Name  string // name of the referenced param
+	Check string // check to perform: "empty", "notempty" or "equal"
+	Value string // value to compare with for check "equal"
+}
+
 // Param is a proxy template parameter
 // Params can be defined:
 // 1. in the template: uses entries in 4. for default properties and values, can be overwritten here
@@ -191,6 +198,7 @@ type Param struct {
 	Choice        []string     // defines a set of choices, e.g. "grid", "pv", "battery", "charge" for "usage"
 	AllInOne      bool         // defines if the defined usages can all be present in a single device
 	Requirements  Requirements // requirements for this param to be usable, only supported via ValueType "bool"
+	Dependencies  []Dependency // conditions on other params, the param is only used if all are met
 
 	Baudrate int    // device specific default for modbus RS485 baudrate
 	Comset   string // device specific default for modbus RS485 comset
@@ -251,6 +259,10 @@ func (p *Param) OverwriteProperties(withParam Param) {
 	if reflect.DeepEqual(p.Requirements, Requirements{}) {
 		p.Requirements = withParam.Requirements
 	}
+
+	if p.Dependencies == nil && withParam.Dependencies != nil {
+		p.Dependencies = withParam.Dependencies
+	}
 }
 
 // Product contains naming information about a product a template supports
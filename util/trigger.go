@@ -0,0 +1,39 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// PushInterval is the min interval between update requests of a single push-based device
+var PushInterval = time.Second
+
+var (
+	triggerMu sync.Mutex
+	triggers  []chan struct{}
+)
+
+// UpdateTrigger registers a channel signaling new measurements of push-based devices.
+// Each control loop registers its own channel, pending requests are coalesced.
+func UpdateTrigger() <-chan struct{} {
+	triggerMu.Lock()
+	defer triggerMu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	triggers = append(triggers, ch)
+
+	return ch
+}
+
+// TriggerUpdate requests an immediate recalculation of all registered control loops
+func TriggerUpdate() {
+	triggerMu.Lock()
+	defer triggerMu.Unlock()
+
+	for _, ch := range triggers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
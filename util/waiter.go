@@ -12,6 +12,7 @@ type Waiter struct {
 	mu      sync.Mutex
 	log     func()
 	updated time.Time
+	trigger time.Time // last update request
 	timeout time.Duration
 	initial chan bool
 }
@@ -25,13 +26,19 @@ func NewWaiter(timeout time.Duration, logInitialWait func()) *Waiter {
 	}
 }
 
-// Update is called when client has received data. Update resets the timeout counter.
+// Update is called when client has received data. Update resets the timeout counter
+// and requests an update of the control loop, rate limited by PushInterval.
 func (p *Waiter) Update() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	p.updated = time.Now()
 
+	if p.updated.Sub(p.trigger) >= PushInterval {
+		p.trigger = p.updated
+		TriggerUpdate()
+	}
+
 	select {
 	case <-p.initial:
 	default:
@@ -62,3 +62,40 @@ func TestWaiterUpdateNotReceived(t *testing.T) {
 		t.Errorf("expected >%v, got %v", 2*testTimeout, elapsed)
 	}
 }
+
+func TestWaiterTriggerUpdate(t *testing.T) {
+	defer func(d time.Duration) { PushInterval = d }(PushInterval)
+	PushInterval = testTimeout
+
+	trigger, other := UpdateTrigger(), UpdateTrigger()
+
+	triggered := func(ch <-chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+
+	w := NewWaiter(0, func() {})
+	w.Update()
+
+	if !triggered(trigger) || !triggered(other) {
+		t.Error("expected update request for all control loops")
+	}
+
+	// rate limited
+	w.Update()
+
+	if triggered(trigger) {
+		t.Error("expected no update request within push interval")
+	}
+
+	time.Sleep(testTimeout)
+	w.Update()
+
+	if !triggered(trigger) {
+		t.Error("expected update request after push interval")
+	}
+}
@@ -12,6 +12,9 @@ type embed struct {
 	Identifiers_ []string         `mapstructure:"identifiers"`
 	Features_    []api.Feature    `mapstructure:"features"`
 	OnIdentify   api.ActionConfig `mapstructure:"onIdentify"`
+	SocSync_     api.SocSync      `mapstructure:"socSync"`
+	Poll_        api.PollConfig   `mapstructure:"poll"`
+	Stale_       api.StaleConfig  `mapstructure:"stale"`
 }
 
 // Title implements the api.Vehicle interface
@@ -39,6 +42,27 @@ func (v *embed) OnIdentified() api.ActionConfig {
 	return v.OnIdentify
 }
 
+var _ api.SocSyncer = (*embed)(nil)
+
+// SocSync implements the api.SocSyncer interface
+func (v *embed) SocSync() api.SocSync {
+	return v.SocSync_
+}
+
+var _ api.Poller = (*embed)(nil)
+
+// Poll implements the api.Poller interface
+func (v *embed) Poll() api.PollConfig {
+	return v.Poll_
+}
+
+var _ api.StaleHandler = (*embed)(nil)
+
+// Stale implements the api.StaleHandler interface
+func (v *embed) Stale() api.StaleConfig {
+	return v.Stale_
+}
+
 var _ api.FeatureDescriber = (*embed)(nil)
 
 // Features implements the api.Describer interface
@@ -0,0 +1,150 @@
+package vehicle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Push is an api.Vehicle implementation receiving soc updates via the push api, e.g. from EVNotify or Torque
+type Push struct {
+	*embed
+	log      *util.Logger
+	mu       sync.Mutex
+	key      string
+	timeout  time.Duration
+	soc      float64
+	rng      int64
+	hasRange bool
+	updated  time.Time
+}
+
+var (
+	pushMu      sync.Mutex
+	pushTargets = make(map[string]*Push)
+)
+
+func init() {
+	registry.Add("push", NewPushFromConfig)
+}
+
+// NewPushFromConfig creates a new vehicle
+func NewPushFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed   `mapstructure:",squash"`
+		ID      string        // push api id
+		Key     string        // soc parameter, e.g. Torque pid
+		Timeout time.Duration // maximum age of pushed soc
+	}{
+		Key:     "soc",
+		Timeout: time.Hour,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.ID == "" {
+		return nil, errors.New("missing id")
+	}
+
+	v := &Push{
+		embed:   &cc.embed,
+		log:     util.NewLogger("push"),
+		key:     cc.Key,
+		timeout: cc.Timeout,
+	}
+
+	pushMu.Lock()
+	defer pushMu.Unlock()
+
+	if _, exists := pushTargets[cc.ID]; exists {
+		return nil, fmt.Errorf("duplicate id: %s", cc.ID)
+	}
+	pushTargets[cc.ID] = v
+
+	return v, nil
+}
+
+// Pushed returns the push vehicle with the given id
+func Pushed(id string) (*Push, error) {
+	pushMu.Lock()
+	defer pushMu.Unlock()
+
+	v, ok := pushTargets[id]
+	if !ok {
+		return nil, fmt.Errorf("push vehicle not found: %s", id)
+	}
+
+	return v, nil
+}
+
+// Update applies pushed values. The soc is read from the configured key, range is optional.
+func (v *Push) Update(values map[string]string) error {
+	val, ok := values[v.key]
+	if !ok {
+		return fmt.Errorf("missing %s", v.key)
+	}
+
+	soc, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil || soc < 0 || soc > 100 {
+		return fmt.Errorf("invalid %s: %s", v.key, val)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.soc = soc
+	v.updated = time.Now()
+
+	if val, ok := values["range"]; ok {
+		if rng, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+			v.rng, v.hasRange = int64(rng), true
+		}
+	}
+
+	v.log.DEBUG.Printf("%s: soc %.0f%%", v.Title(), soc)
+
+	return nil
+}
+
+// current returns an error if no recent values have been pushed
+func (v *Push) current() error {
+	if v.updated.IsZero() {
+		return api.ErrMustRetry
+	}
+
+	if v.timeout > 0 && time.Since(v.updated) > v.timeout {
+		return fmt.Errorf("soc outdated: %v", time.Since(v.updated).Truncate(time.Second))
+	}
+
+	return nil
+}
+
+// SoC implements the api.Vehicle interface
+func (v *Push) SoC() (float64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.soc, v.current()
+}
+
+var _ api.VehicleRange = (*Push)(nil)
+
+// Range implements the api.VehicleRange interface
+func (v *Push) Range() (int64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.hasRange {
+		return 0, api.ErrNotAvailable
+	}
+
+	return v.rng, v.current()
+}
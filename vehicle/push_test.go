@@ -0,0 +1,41 @@
+package vehicle
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPush(t *testing.T) {
+	_, err := NewPushFromConfig(map[string]interface{}{"title": "Kona"})
+	assert.Error(t, err, "missing id")
+
+	v, err := NewPushFromConfig(map[string]interface{}{"id": "kona", "key": "k22b0"})
+	require.NoError(t, err)
+
+	_, err = NewPushFromConfig(map[string]interface{}{"id": "kona"})
+	assert.Error(t, err, "duplicate id")
+
+	_, err = v.SoC()
+	assert.ErrorIs(t, err, api.ErrMustRetry)
+
+	p, err := Pushed("kona")
+	require.NoError(t, err)
+
+	assert.Error(t, p.Update(map[string]string{"soc": "50"}))
+	assert.Error(t, p.Update(map[string]string{"k22b0": "150"}))
+	require.NoError(t, p.Update(map[string]string{"k22b0": "55.5", "range": "201.4"}))
+
+	soc, err := v.SoC()
+	require.NoError(t, err)
+	assert.Equal(t, 55.5, soc)
+
+	rng, err := v.(api.VehicleRange).Range()
+	require.NoError(t, err)
+	assert.Equal(t, int64(201), rng)
+
+	_, err = Pushed("soul")
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package vehicle
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/simulation"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Simulation is an api.Vehicle implementation of an in-memory simulation
+type Simulation struct {
+	*embed
+	*simulation.Vehicle
+}
+
+func init() {
+	registry.Add("simulation", NewSimulationFromConfig)
+}
+
+// NewSimulationFromConfig creates a simulated vehicle, optionally plugged into a simulated charger
+func NewSimulationFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed      `mapstructure:",squash"`
+		Simulation string  // simulation name, devices of the same simulation share their energy flows
+		Charger    string  // id of the simulated charger the vehicle is connected to
+		SoC        float64 // initial soc
+		Power      float64 // max charge power in W, reduced above 80% soc
+	}{
+		SoC:   30,
+		Power: 11000,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Capacity_ == 0 {
+		return nil, errors.New("missing capacity")
+	}
+
+	v := &Simulation{
+		embed:   &cc.embed,
+		Vehicle: simulation.NewVehicle(cc.Capacity_, cc.SoC, cc.Power),
+	}
+
+	if cc.Charger != "" {
+		simulation.Instance(cc.Simulation).Charger(cc.Charger).Plug(v.Vehicle)
+	}
+
+	return v, nil
+}
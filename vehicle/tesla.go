@@ -198,6 +198,21 @@ func (v *Tesla) TargetSoC() (float64, error) {
 	return 0, err
 }
 
+var _ api.SocLimitController = (*Tesla)(nil)
+
+// SetTargetSoC implements the api.SocLimitController interface
+func (v *Tesla) SetTargetSoC(soc int) error {
+	return v.vehicle.SetChargeLimit(soc)
+}
+
+var _ api.Resurrector = (*Tesla)(nil)
+
+// WakeUp implements the api.Resurrector interface
+func (v *Tesla) WakeUp() error {
+	_, err := v.vehicle.Wakeup()
+	return err
+}
+
 var _ api.VehicleChargeController = (*Tesla)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface
@@ -238,3 +253,22 @@ func (v *Tesla) StopCharge() error {
 
 	return err
 }
+
+var _ api.VehicleClimateController = (*Tesla)(nil)
+
+// StartClimate implements the api.VehicleClimateController interface
+func (v *Tesla) StartClimate() error {
+	return v.vehicle.StartAirConditioning()
+}
+
+// StopClimate implements the api.VehicleClimateController interface
+func (v *Tesla) StopClimate() error {
+	err := v.vehicle.StopAirConditioning()
+
+	// ignore sleeping vehicle
+	if err != nil && err.Error() == "408 Request Timeout" {
+		err = nil
+	}
+
+	return err
+}
@@ -0,0 +1,174 @@
+package vehicle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/provider/mqtt"
+	"github.com/evcc-io/evcc/util"
+)
+
+// TeslaMate is an api.Vehicle implementation reading vehicle data from a local TeslaMate instance via mqtt
+type TeslaMate struct {
+	*embed
+	geofence       string
+	socG           func() (float64, error)
+	limitSocG      func() (float64, error)
+	pluggedInG     func() (bool, error)
+	chargingStateG func() (string, error)
+	geofenceG      func() (string, error)
+	rangeG         func() (float64, error)
+	odometerG      func() (float64, error)
+	timeToFullG    func() (float64, error)
+	energyAddedG   func() (float64, error)
+	latitudeG      func() (float64, error)
+	longitudeG     func() (float64, error)
+}
+
+func init() {
+	registry.Add("teslamate", NewTeslaMateFromConfig)
+}
+
+// NewTeslaMateFromConfig creates a new vehicle
+func NewTeslaMateFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed       `mapstructure:",squash"`
+		mqtt.Config `mapstructure:",squash"`
+		Topic       string
+		CarID       int
+		Geofence    string // report vehicle connected only inside this geofence
+		Timeout     time.Duration
+	}{
+		Topic: "teslamate",
+		CarID: 1,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	log := util.NewLogger("teslamate").Redact(cc.User, cc.Password)
+
+	client, err := mqtt.RegisteredClientOrDefault(log, cc.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := func(name string) *provider.Mqtt {
+		return provider.NewMqtt(log, client, fmt.Sprintf("%s/cars/%d/%s", strings.TrimSuffix(cc.Topic, "/"), cc.CarID, name), cc.Timeout)
+	}
+
+	v := &TeslaMate{
+		embed:          &cc.embed,
+		geofence:       cc.Geofence,
+		socG:           topic("usable_battery_level").FloatGetter(),
+		limitSocG:      topic("charge_limit_soc").FloatGetter(),
+		pluggedInG:     topic("plugged_in").BoolGetter(),
+		chargingStateG: topic("charging_state").StringGetter(),
+		rangeG:         topic("est_battery_range_km").FloatGetter(),
+		odometerG:      topic("odometer").FloatGetter(),
+		timeToFullG:    topic("time_to_full_charge").FloatGetter(),
+		energyAddedG:   topic("charge_energy_added").FloatGetter(),
+		latitudeG:      topic("latitude").FloatGetter(),
+		longitudeG:     topic("longitude").FloatGetter(),
+	}
+
+	if cc.Geofence != "" {
+		v.geofenceG = topic("geofence").StringGetter()
+	}
+
+	return v, nil
+}
+
+// SoC implements the api.Vehicle interface
+func (v *TeslaMate) SoC() (float64, error) {
+	return v.socG()
+}
+
+var _ api.ChargeState = (*TeslaMate)(nil)
+
+// Status implements the api.ChargeState interface
+func (v *TeslaMate) Status() (api.ChargeStatus, error) {
+	status := api.StatusA // disconnected
+
+	pluggedIn, err := v.pluggedInG()
+	if err != nil || !pluggedIn {
+		return status, err
+	}
+
+	// vehicle is plugged in elsewhere
+	if v.geofenceG != nil {
+		geofence, err := v.geofenceG()
+		if err != nil || !strings.EqualFold(geofence, v.geofence) {
+			return status, err
+		}
+	}
+
+	status = api.StatusB
+
+	state, err := v.chargingStateG()
+	if err == nil && state == "Charging" {
+		status = api.StatusC
+	}
+
+	return status, err
+}
+
+var _ api.ChargeRater = (*TeslaMate)(nil)
+
+// ChargedEnergy implements the api.ChargeRater interface
+func (v *TeslaMate) ChargedEnergy() (float64, error) {
+	return v.energyAddedG()
+}
+
+var _ api.VehicleRange = (*TeslaMate)(nil)
+
+// Range implements the api.VehicleRange interface
+func (v *TeslaMate) Range() (int64, error) {
+	res, err := v.rangeG()
+	return int64(res), err
+}
+
+var _ api.VehicleOdometer = (*TeslaMate)(nil)
+
+// Odometer implements the api.VehicleOdometer interface
+func (v *TeslaMate) Odometer() (float64, error) {
+	return v.odometerG()
+}
+
+var _ api.VehicleFinishTimer = (*TeslaMate)(nil)
+
+// FinishTime implements the api.VehicleFinishTimer interface
+func (v *TeslaMate) FinishTime() (time.Time, error) {
+	res, err := v.timeToFullG()
+	if err == nil {
+		// hours to full charge
+		return time.Now().Add(time.Duration(res * float64(time.Hour))), nil
+	}
+
+	return time.Time{}, err
+}
+
+var _ api.VehiclePosition = (*TeslaMate)(nil)
+
+// Position implements the api.VehiclePosition interface
+func (v *TeslaMate) Position() (float64, float64, error) {
+	lat, err := v.latitudeG()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lon, err := v.longitudeG()
+
+	return lat, lon, err
+}
+
+var _ api.SocLimiter = (*TeslaMate)(nil)
+
+// TargetSoC implements the api.SocLimiter interface
+func (v *TeslaMate) TargetSoC() (float64, error) {
+	return v.limitSocG()
+}
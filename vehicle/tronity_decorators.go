@@ -7,11 +7,22 @@ import (
 )
 
 func decorateTronity(base *Tronity, chargeState func() (api.ChargeStatus, error), vehicleOdometer func() (float64, error), vehicleStartCharge func() error, vehicleStopCharge func() error) api.Vehicle {
-	switch {
-	case chargeState == nil && vehicleOdometer == nil && vehicleStartCharge == nil && vehicleStopCharge == nil:
+	var caps int
+	if chargeState != nil {
+		caps |= 1
+	}
+	if vehicleOdometer != nil {
+		caps |= 2
+	}
+	if vehicleStartCharge != nil && vehicleStopCharge != nil {
+		caps |= 4
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case chargeState != nil && vehicleOdometer == nil && vehicleStartCharge == nil && vehicleStopCharge == nil:
+	case 1: // api.ChargeState
 		return &struct {
 			*Tronity
 			api.ChargeState
@@ -22,7 +33,7 @@ func decorateTronity(base *Tronity, chargeState func() (api.ChargeStatus, error)
 			},
 		}
 
-	case chargeState == nil && vehicleOdometer != nil && vehicleStartCharge == nil && vehicleStopCharge == nil:
+	case 2: // api.VehicleOdometer
 		return &struct {
 			*Tronity
 			api.VehicleOdometer
@@ -33,7 +44,7 @@ func decorateTronity(base *Tronity, chargeState func() (api.ChargeStatus, error)
 			},
 		}
 
-	case chargeState != nil && vehicleOdometer != nil && vehicleStartCharge == nil && vehicleStopCharge == nil:
+	case 3: // api.ChargeState, api.VehicleOdometer
 		return &struct {
 			*Tronity
 			api.ChargeState
@@ -48,18 +59,7 @@ func decorateTronity(base *Tronity, chargeState func() (api.ChargeStatus, error)
 			},
 		}
 
-	case chargeState == nil && vehicleOdometer == nil && vehicleStartCharge != nil && vehicleStopCharge != nil:
-		return &struct {
-			*Tronity
-			api.VehicleChargeController
-		}{
-			Tronity: base,
-			VehicleChargeController: &decorateTronityVehicleChargeControllerImpl{
-				vehicleStartCharge: vehicleStartCharge,
-			},
-		}
-
-	case chargeState == nil && vehicleOdometer == nil && vehicleStartCharge != nil && vehicleStopCharge != nil:
+	case 4: // api.VehicleChargeController
 		return &struct {
 			*Tronity
 			api.VehicleChargeController
@@ -71,7 +71,7 @@ func decorateTronity(base *Tronity, chargeState func() (api.ChargeStatus, error)
 			},
 		}
 
-	case chargeState != nil && vehicleOdometer == nil && vehicleStartCharge != nil && vehicleStopCharge != nil:
+	case 5: // api.ChargeState, api.VehicleChargeController
 		return &struct {
 			*Tronity
 			api.ChargeState
@@ -87,40 +87,40 @@ func decorateTronity(base *Tronity, chargeState func() (api.ChargeStatus, error)
 			},
 		}
 
-	case chargeState == nil && vehicleOdometer != nil && vehicleStartCharge != nil && vehicleStopCharge != nil:
+	case 6: // api.VehicleOdometer, api.VehicleChargeController
 		return &struct {
 			*Tronity
-			api.VehicleOdometer
 			api.VehicleChargeController
+			api.VehicleOdometer
 		}{
 			Tronity: base,
-			VehicleOdometer: &decorateTronityVehicleOdometerImpl{
-				vehicleOdometer: vehicleOdometer,
-			},
 			VehicleChargeController: &decorateTronityVehicleChargeControllerImpl{
 				vehicleStartCharge: vehicleStartCharge,
 				vehicleStopCharge:  vehicleStopCharge,
 			},
+			VehicleOdometer: &decorateTronityVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
 		}
 
-	case chargeState != nil && vehicleOdometer != nil && vehicleStartCharge != nil && vehicleStopCharge != nil:
+	case 7: // api.ChargeState, api.VehicleOdometer, api.VehicleChargeController
 		return &struct {
 			*Tronity
 			api.ChargeState
-			api.VehicleOdometer
 			api.VehicleChargeController
+			api.VehicleOdometer
 		}{
 			Tronity: base,
 			ChargeState: &decorateTronityChargeStateImpl{
 				chargeState: chargeState,
 			},
-			VehicleOdometer: &decorateTronityVehicleOdometerImpl{
-				vehicleOdometer: vehicleOdometer,
-			},
 			VehicleChargeController: &decorateTronityVehicleChargeControllerImpl{
 				vehicleStartCharge: vehicleStartCharge,
 				vehicleStopCharge:  vehicleStopCharge,
 			},
+			VehicleOdometer: &decorateTronityVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
 		}
 	}
 
@@ -135,14 +135,6 @@ func (impl *decorateTronityChargeStateImpl) Status() (api.ChargeStatus, error) {
 	return impl.chargeState()
 }
 
-type decorateTronityVehicleOdometerImpl struct {
-	vehicleOdometer func() (float64, error)
-}
-
-func (impl *decorateTronityVehicleOdometerImpl) Odometer() (float64, error) {
-	return impl.vehicleOdometer()
-}
-
 type decorateTronityVehicleChargeControllerImpl struct {
 	vehicleStartCharge func() error
 	vehicleStopCharge  func() error
@@ -155,3 +147,11 @@ func (impl *decorateTronityVehicleChargeControllerImpl) StartCharge() error {
 func (impl *decorateTronityVehicleChargeControllerImpl) StopCharge() error {
 	return impl.vehicleStopCharge()
 }
+
+type decorateTronityVehicleOdometerImpl struct {
+	vehicleOdometer func() (float64, error)
+}
+
+func (impl *decorateTronityVehicleOdometerImpl) Odometer() (float64, error) {
+	return impl.vehicleOdometer()
+}
@@ -8,13 +8,15 @@ import (
 	"github.com/evcc-io/evcc/util"
 )
 
-//go:generate go run ../cmd/tools/decorate.go -f decorateVehicle -b api.Vehicle -t "api.ChargeState,Status,func() (api.ChargeStatus, error)" -t "api.VehicleRange,Range,func() (int64, error)" -t "api.VehicleOdometer,Odometer,func() (float64, error)"
+//go:generate go run ../cmd/tools/decorate.go -f decorateVehicle -b api.Vehicle -t "api.ChargeState,Status,func() (api.ChargeStatus, error)" -t "api.VehicleRange,Range,func() (int64, error)" -t "api.VehicleOdometer,Odometer,func() (float64, error)" -t "api.VehiclePosition,Position,func() (float64, float64, error)"
 
 // Vehicle is an api.Vehicle implementation with configurable getters and setters.
 type Vehicle struct {
 	*embed
 	socG    func() (float64, error)
 	statusG func() (string, error)
+	latG    func() (float64, error)
+	lonG    func() (float64, error)
 }
 
 func init() {
@@ -29,6 +31,10 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Vehicle, error
 		Status   *provider.Config
 		Range    *provider.Config
 		Odometer *provider.Config
+		Position *struct {
+			Latitude  provider.Config
+			Longitude provider.Config
+		}
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -75,7 +81,19 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Vehicle, error
 		odo = odoG
 	}
 
-	res := decorateVehicle(v, status, rng, odo)
+	// decorate vehicle with position
+	var position func() (float64, float64, error)
+	if cc.Position != nil {
+		if v.latG, err = provider.NewFloatGetterFromConfig(cc.Position.Latitude); err != nil {
+			return nil, fmt.Errorf("latitude: %w", err)
+		}
+		if v.lonG, err = provider.NewFloatGetterFromConfig(cc.Position.Longitude); err != nil {
+			return nil, fmt.Errorf("longitude: %w", err)
+		}
+		position = v.position
+	}
+
+	res := decorateVehicle(v, status, rng, odo, position)
 
 	return res, nil
 }
@@ -96,3 +114,14 @@ func (v *Vehicle) status() (api.ChargeStatus, error) {
 
 	return status, err
 }
+
+// position implements the api.VehiclePosition interface
+func (v *Vehicle) position() (float64, float64, error) {
+	lat, err := v.latG()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lon, err := v.lonG()
+	return lat, lon, err
+}
@@ -6,12 +6,26 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, error), vehicleRange func() (int64, error), vehicleOdometer func() (float64, error)) api.Vehicle {
-	switch {
-	case chargeState == nil && vehicleOdometer == nil && vehicleRange == nil:
+func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, error), vehicleRange func() (int64, error), vehicleOdometer func() (float64, error), vehiclePosition func() (float64, float64, error)) api.Vehicle {
+	var caps int
+	if chargeState != nil {
+		caps |= 1
+	}
+	if vehicleRange != nil {
+		caps |= 2
+	}
+	if vehicleOdometer != nil {
+		caps |= 4
+	}
+	if vehiclePosition != nil {
+		caps |= 8
+	}
+
+	switch caps {
+	case 0:
 		return base
 
-	case chargeState != nil && vehicleOdometer == nil && vehicleRange == nil:
+	case 1: // api.ChargeState
 		return &struct {
 			api.Vehicle
 			api.ChargeState
@@ -22,7 +36,7 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 			},
 		}
 
-	case chargeState == nil && vehicleOdometer == nil && vehicleRange != nil:
+	case 2: // api.VehicleRange
 		return &struct {
 			api.Vehicle
 			api.VehicleRange
@@ -33,7 +47,7 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 			},
 		}
 
-	case chargeState != nil && vehicleOdometer == nil && vehicleRange != nil:
+	case 3: // api.ChargeState, api.VehicleRange
 		return &struct {
 			api.Vehicle
 			api.ChargeState
@@ -48,7 +62,7 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 			},
 		}
 
-	case chargeState == nil && vehicleOdometer != nil && vehicleRange == nil:
+	case 4: // api.VehicleOdometer
 		return &struct {
 			api.Vehicle
 			api.VehicleOdometer
@@ -59,7 +73,7 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 			},
 		}
 
-	case chargeState != nil && vehicleOdometer != nil && vehicleRange == nil:
+	case 5: // api.ChargeState, api.VehicleOdometer
 		return &struct {
 			api.Vehicle
 			api.ChargeState
@@ -74,7 +88,7 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 			},
 		}
 
-	case chargeState == nil && vehicleOdometer != nil && vehicleRange != nil:
+	case 6: // api.VehicleRange, api.VehicleOdometer
 		return &struct {
 			api.Vehicle
 			api.VehicleOdometer
@@ -89,7 +103,7 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 			},
 		}
 
-	case chargeState != nil && vehicleOdometer != nil && vehicleRange != nil:
+	case 7: // api.ChargeState, api.VehicleRange, api.VehicleOdometer
 		return &struct {
 			api.Vehicle
 			api.ChargeState
@@ -107,6 +121,142 @@ func decorateVehicle(base api.Vehicle, chargeState func() (api.ChargeStatus, err
 				vehicleRange: vehicleRange,
 			},
 		}
+
+	case 8: // api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.VehiclePosition
+		}{
+			Vehicle: base,
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+		}
+
+	case 9: // api.ChargeState, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.ChargeState
+			api.VehiclePosition
+		}{
+			Vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+		}
+
+	case 10: // api.VehicleRange, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.VehiclePosition
+			api.VehicleRange
+		}{
+			Vehicle: base,
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case 11: // api.ChargeState, api.VehicleRange, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.ChargeState
+			api.VehiclePosition
+			api.VehicleRange
+		}{
+			Vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case 12: // api.VehicleOdometer, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.VehicleOdometer
+			api.VehiclePosition
+		}{
+			Vehicle: base,
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+		}
+
+	case 13: // api.ChargeState, api.VehicleOdometer, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.ChargeState
+			api.VehicleOdometer
+			api.VehiclePosition
+		}{
+			Vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+		}
+
+	case 14: // api.VehicleRange, api.VehicleOdometer, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.VehicleOdometer
+			api.VehiclePosition
+			api.VehicleRange
+		}{
+			Vehicle: base,
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
+
+	case 15: // api.ChargeState, api.VehicleRange, api.VehicleOdometer, api.VehiclePosition
+		return &struct {
+			api.Vehicle
+			api.ChargeState
+			api.VehicleOdometer
+			api.VehiclePosition
+			api.VehicleRange
+		}{
+			Vehicle: base,
+			ChargeState: &decorateVehicleChargeStateImpl{
+				chargeState: chargeState,
+			},
+			VehicleOdometer: &decorateVehicleVehicleOdometerImpl{
+				vehicleOdometer: vehicleOdometer,
+			},
+			VehiclePosition: &decorateVehicleVehiclePositionImpl{
+				vehiclePosition: vehiclePosition,
+			},
+			VehicleRange: &decorateVehicleVehicleRangeImpl{
+				vehicleRange: vehicleRange,
+			},
+		}
 	}
 
 	return nil
@@ -128,6 +278,14 @@ func (impl *decorateVehicleVehicleOdometerImpl) Odometer() (float64, error) {
 	return impl.vehicleOdometer()
 }
 
+type decorateVehicleVehiclePositionImpl struct {
+	vehiclePosition func() (float64, float64, error)
+}
+
+func (impl *decorateVehicleVehiclePositionImpl) Position() (float64, float64, error) {
+	return impl.vehiclePosition()
+}
+
 type decorateVehicleVehicleRangeImpl struct {
 	vehicleRange func() (int64, error)
 }
@@ -0,0 +1,35 @@
+package vehicle
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurablePosition(t *testing.T) {
+	script := func(val string) map[string]interface{} {
+		return map[string]interface{}{"source": "script", "cmd": "echo " + val}
+	}
+
+	v, err := NewConfigurableFromConfig(map[string]interface{}{
+		"title": "foo",
+		"soc":   script("50"),
+		"position": map[string]interface{}{
+			"latitude":  script("52.5"),
+			"longitude": script("13.4"),
+		},
+	})
+	require.NoError(t, err)
+
+	_, ok := v.(api.VehicleRange)
+	assert.False(t, ok, "range not configured")
+
+	vp, ok := v.(api.VehiclePosition)
+	require.True(t, ok)
+
+	lat, lon, err := vp.Position()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{52.5, 13.4}, []float64{lat, lon})
+}
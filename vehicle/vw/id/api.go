@@ -100,6 +100,18 @@ func (v *API) Action(vin, action, value string) error {
 	return err
 }
 
+// WakeUp triggers a vehicle wake-up for refreshing its status
+func (v *API) WakeUp(vin string) error {
+	uri := fmt.Sprintf("%s/vehicles/%s/vehiclewakeup", BaseURL, vin)
+
+	req, err := request.New(http.MethodPost, uri, nil, request.AcceptJSON)
+	if err == nil {
+		_, err = v.DoBody(req)
+	}
+
+	return err
+}
+
 // Any implements any api response
 func (v *API) Any(uri, vin string) (interface{}, error) {
 	if strings.Contains(uri, "%s") {
@@ -12,6 +12,7 @@ import (
 type Provider struct {
 	statusG func() (Status, error)
 	action  func(action, value string) error
+	wakeup  func() error
 }
 
 // NewProvider creates a new vehicle
@@ -23,6 +24,9 @@ func NewProvider(api *API, vin string, cache time.Duration) *Provider {
 		action: func(action, value string) error {
 			return api.Action(vin, action, value)
 		},
+		wakeup: func() error {
+			return api.WakeUp(vin)
+		},
 	}
 	return impl
 }
@@ -170,3 +174,10 @@ func (v *Provider) StartCharge() error {
 func (v *Provider) StopCharge() error {
 	return v.action(ActionCharge, ActionChargeStop)
 }
+
+var _ api.Resurrector = (*Provider)(nil)
+
+// WakeUp implements the api.Resurrector interface
+func (v *Provider) WakeUp() error {
+	return v.wakeup()
+}